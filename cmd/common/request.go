@@ -35,6 +35,7 @@ type PodRequest struct {
 	Password      string `json:"password,omitempty"`
 	Reference     string `json:"reference,omitempty"`
 	SharedPodName string `json:"sharedPodName,omitempty"`
+	FeedType      string `json:"feedType,omitempty"`
 }
 
 // PodShareRequest
@@ -58,6 +59,8 @@ type FileSystemRequest struct {
 	FilePath      string `json:"filePath,omitempty"`
 	FileName      string `json:"fileName,omitempty"`
 	Destination   string `json:"destUser,omitempty"`
+	// Xattrs asks for the extended attributes in a listing or a stat
+	Xattrs bool `json:"xattrs,omitempty"`
 }
 
 // RenameRequest
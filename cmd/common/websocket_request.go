@@ -57,6 +57,22 @@ var (
 	DirLs Event = "/dir/ls"
 	//DirStat
 	DirStat Event = "/dir/stat"
+	//DirWatch
+	DirWatch Event = "/dir/watch"
+	//DirXattr
+	DirXattr Event = "/dir/xattr"
+	//DirXattrSet
+	DirXattrSet Event = "/dir/xattr/set"
+	//DirXattrRemove
+	DirXattrRemove Event = "/dir/xattr/remove"
+	//DirSymlink
+	DirSymlink Event = "/dir/symlink"
+	//DirSymlinkStat
+	DirSymlinkStat Event = "/dir/symlink/stat"
+	//DirSymlinkRemove
+	DirSymlinkRemove Event = "/dir/symlink/remove"
+	//DirCopy
+	DirCopy Event = "/dir/copy"
 	//FileDownload
 	FileDownload Event = "/file/download"
 	//FileDownloadStream
@@ -77,6 +93,14 @@ var (
 	FileDelete Event = "/file/delete"
 	//FileStat
 	FileStat Event = "/file/stat"
+	//FileXattr
+	FileXattr Event = "/file/xattr"
+	//FileXattrSet
+	FileXattrSet Event = "/file/xattr/set"
+	//FileXattrRemove
+	FileXattrRemove Event = "/file/xattr/remove"
+	//FileCopy
+	FileCopy Event = "/file/copy"
 	//KVCreate
 	KVCreate Event = "/kv/new"
 	//KVList
@@ -103,6 +127,8 @@ var (
 	KVSeek Event = "/kv/seek"
 	//KVSeekNext
 	KVSeekNext Event = "/kv/seek/next"
+	//KVWatch
+	KVWatch Event = "/kv/watch"
 	//DocCreate
 	DocCreate Event = "/doc/new"
 	//DocList
@@ -127,6 +153,10 @@ var (
 	DocLoadJsonStream Event = "/doc/loadjson/stream"
 	//DocIndexJson
 	DocIndexJson Event = "/doc/indexjson"
+	//DocWatch
+	DocWatch Event = "/doc/watch"
+	//Unwatch stops the watch that was started by the request with the same id
+	Unwatch Event = "/unwatch"
 )
 
 // WebsocketRequest
@@ -146,6 +176,10 @@ type FileRequest struct {
 	ContentLength string `json:"contentLength,omitempty"`
 	Compression   string `json:"compression,omitempty"`
 	Overwrite     bool   `json:"overwrite,omitempty"`
+	// Resumable streams the file into a new upload session, which can be resumed with
+	// UploadId over another connection
+	Resumable bool   `json:"resumable,omitempty"`
+	UploadId  string `json:"uploadId,omitempty"`
 }
 
 // FileDownloadRequest
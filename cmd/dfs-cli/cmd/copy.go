@@ -0,0 +1,53 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/api"
+)
+
+func copyFileOrDir(podName, podPath, dstPodName, dstPath string) {
+	copyReq := api.CopyRequest{
+		PodName:            podName,
+		DestinationPodName: dstPodName,
+		DestinationPath:    dstPath,
+	}
+	urlPath := apiFileCopy
+	if isDirectoryPresent(podName, podPath) {
+		urlPath = apiDirCopy
+		copyReq.DirPath = podPath
+	} else {
+		copyReq.FilePath = podPath
+	}
+	jsonData, err := json.Marshal(copyReq)
+	if err != nil {
+		fmt.Println("cp: error marshalling request")
+		return
+	}
+	data, err := fdfsAPI.postReq(http.MethodPost, urlPath, jsonData)
+	if err != nil {
+		fmt.Println("cp: ", err)
+		return
+	}
+	message := strings.ReplaceAll(string(data), "\n", "")
+	fmt.Println(message)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package cmd
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -69,6 +70,10 @@ func listFileAndDirectories(podName, dirNameWithpath string) {
 		fmt.Println("empty directory")
 	}
 	for _, entry := range resp.Directories {
+		if entry.ContentType == dir.MineTypeSymlink {
+			fmt.Println("<Link>: ", entry.Name, "->", entry.Target)
+			continue
+		}
 		fmt.Println("<Dir>: ", entry.Name)
 	}
 	for _, entry := range resp.Files {
@@ -77,11 +82,11 @@ func listFileAndDirectories(podName, dirNameWithpath string) {
 }
 
 func statFileOrDirectory(podName, statElement string) {
-	args := fmt.Sprintf("podName=%s&dirPath=%s", podName, statElement)
+	args := fmt.Sprintf("podName=%s&dirPath=%s&xattrs=true", podName, statElement)
 	data, err := fdfsAPI.getReq(apiDirStat, args)
 	if err != nil {
 		if strings.Contains(err.Error(), "directory not found") {
-			args := fmt.Sprintf("podName=%s&filePath=%s", podName, statElement)
+			args := fmt.Sprintf("podName=%s&filePath=%s&xattrs=true", podName, statElement)
 			data, err := fdfsAPI.getReq(apiFileStat, args)
 			if err != nil {
 				fmt.Println("stat failed: ", err)
@@ -119,13 +124,23 @@ func statFileOrDirectory(podName, statElement string) {
 			fmt.Println("Block Size	  : ", resp.BlockSize)
 			fmt.Println("Compression  	  : ", compression)
 			fmt.Println("Content Type 	  : ", resp.ContentType)
+			if resp.Checksum != "" {
+				fmt.Println("SHA-256		  : ", resp.Checksum)
+			}
+			if resp.BlockTreeChecksum != "" {
+				fmt.Println("Block Tree Sum	  : ", resp.BlockTreeChecksum)
+			}
 			fmt.Println("Cr. Time	  : ", time.Unix(crTime, 0).String())
 			fmt.Println("Mo. Time	  : ", time.Unix(accTime, 0).String())
 			fmt.Println("Ac. Time	  : ", time.Unix(modTime, 0).String())
 			for _, b := range resp.Blocks {
 				blkStr := fmt.Sprintf("0x%s, %s bytes, %s bytes", b.Reference, b.Size, b.CompressedSize)
+				if b.Compression != "" {
+					blkStr += ", " + b.Compression
+				}
 				fmt.Println(blkStr)
 			}
+			printXattrs(resp.Xattrs)
 		} else {
 			fmt.Println("stat: ", err)
 			return
@@ -160,7 +175,84 @@ func statFileOrDirectory(podName, statElement string) {
 		fmt.Println("Ac. Time	 : ", time.Unix(modTime, 0).String())
 		fmt.Println("No of Dir.	 : ", resp.NoOfDirectories)
 		fmt.Println("No of Files      : ", resp.NoOfFiles)
+		printXattrs(resp.Xattrs)
+	}
+}
+
+func historyOfFileOrDirectory(podName, element string) {
+	if isDirectoryPresent(podName, element) {
+		args := fmt.Sprintf("podName=%s&dirPath=%s", podName, element)
+		data, err := fdfsAPI.getReq(apiDirHistory, args)
+		if err != nil {
+			fmt.Println("history: ", err)
+			return
+		}
+		var resp api.DirHistoryResponse
+		err = json.Unmarshal(data, &resp)
+		if err != nil {
+			fmt.Println("dir history: ", err)
+			return
+		}
+		for _, r := range resp.Revisions {
+			switch {
+			case r.Deleted:
+				fmt.Println(time.Unix(r.Timestamp, 0).String(), " deleted")
+			case r.Inode != nil:
+				fmt.Println(time.Unix(r.Timestamp, 0).String(), " ", len(r.Inode.FileOrDirNames), " entries")
+			}
+		}
+		return
+	}
+	args := fmt.Sprintf("podName=%s&filePath=%s", podName, element)
+	data, err := fdfsAPI.getReq(apiFileHistory, args)
+	if err != nil {
+		fmt.Println("history: ", err)
+		return
+	}
+	var resp api.FileHistoryResponse
+	err = json.Unmarshal(data, &resp)
+	if err != nil {
+		fmt.Println("file history: ", err)
+		return
+	}
+	for _, r := range resp.Revisions {
+		switch {
+		case r.Deleted:
+			fmt.Println(time.Unix(r.Timestamp, 0).String(), " deleted")
+		case r.Meta != nil:
+			fmt.Println(time.Unix(r.Timestamp, 0).String(), " ", r.Meta.Size, " bytes, inode 0x"+hex.EncodeToString(r.Meta.InodeAddress))
+		}
+	}
+}
+
+func verifyFile(podName, podFileName string) {
+	args := fmt.Sprintf("podName=%s&filePath=%s", podName, podFileName)
+	data, err := fdfsAPI.getReq(apiFileVerify, args)
+	if err != nil {
+		fmt.Println("verify: ", err)
+		return
+	}
+	var resp file.Verification
+	err = json.Unmarshal(data, &resp)
+	if err != nil {
+		fmt.Println("verify: ", err)
+		return
+	}
+	if resp.Checksum != "" {
+		fmt.Println("SHA-256		  : ", resp.Checksum)
+	}
+	if resp.BlockTreeChecksum != "" {
+		fmt.Println("Block Tree Sum	  : ", resp.BlockTreeChecksum)
+	}
+	fmt.Println("Blocks		  : ", resp.Blocks)
+	if resp.Unverified > 0 {
+		fmt.Println("Unverified	  : ", resp.Unverified, " blocks without checksum")
+	}
+	if !resp.Valid {
+		fmt.Println("Corrupt blocks	  : ", resp.Corrupt)
+		return
 	}
+	fmt.Println("file is valid")
 }
 
 func mkdir(podName, dirNameWithpath string) {
@@ -28,9 +28,10 @@ import (
 	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
 )
 
-func podNew(podName string) {
+func podNew(podName, feedType string) {
 	newPod := common.PodRequest{
-		PodName: podName,
+		PodName:  podName,
+		FeedType: feedType,
 	}
 	jsonData, err := json.Marshal(newPod)
 	if err != nil {
@@ -149,6 +150,42 @@ func syncPod(podName string) {
 	fmt.Println(message)
 }
 
+func gcPod(podName string, dryRun bool) {
+	gcReq := api.PodGCRequest{
+		PodName: podName,
+		DryRun:  dryRun,
+	}
+	jsonData, err := json.Marshal(gcReq)
+	if err != nil {
+		fmt.Println("gc pod: error marshalling request")
+		return
+	}
+	data, err := fdfsAPI.postReq(http.MethodPost, apiPodGC, jsonData)
+	if err != nil {
+		fmt.Println("could not gc pod: ", err)
+		return
+	}
+	var report pod.GCReport
+	err = json.Unmarshal(data, &report)
+	if err != nil {
+		fmt.Println("gc pod: ", err)
+		return
+	}
+	fmt.Println("Recorded   : ", report.Recorded)
+	fmt.Println("Reachable  : ", report.Reachable)
+	fmt.Println("Exported   : ", report.Exported)
+	fmt.Println("Recent     : ", report.Recent)
+	fmt.Println("Garbage    : ", len(report.Garbage))
+	if report.DryRun {
+		for _, ref := range report.Garbage {
+			fmt.Println("  ", ref)
+		}
+		return
+	}
+	fmt.Println("Unpinned   : ", report.Unpinned)
+	fmt.Println("Failed     : ", report.Failed)
+}
+
 func sharePod(podName string) {
 	sharePodReq := common.PodRequest{
 		PodName: podName,
@@ -207,6 +244,7 @@ func podStat(podName string) {
 
 	fmt.Println("pod Name         : ", resp.PodName)
 	fmt.Println("pod Address      : ", resp.PodAddress)
+	fmt.Println("feed Type        : ", resp.FeedType)
 }
 
 func receive(sharingRef string) {
@@ -0,0 +1,63 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/api"
+)
+
+func postageBatches() {
+	data, err := fdfsAPI.getReq(apiPostage, "")
+	if err != nil {
+		fmt.Println("postage: ", err)
+		return
+	}
+	var resp api.PostageResponse
+	err = json.Unmarshal(data, &resp)
+	if err != nil {
+		fmt.Println("postage: ", err)
+		return
+	}
+	for _, b := range resp.Batches {
+		state := ""
+		switch {
+		case b.Active:
+			state = "(active)"
+		case b.Exhausted:
+			state = "(exhausted)"
+		}
+		fmt.Println("batch        : ", b.BatchID, state)
+		if b.Node != "" {
+			fmt.Println("node         : ", b.Node)
+		}
+		if b.Label != "" {
+			fmt.Println("label        : ", b.Label)
+		}
+		fmt.Printf("utilization  :  %.1f%%\n", b.Utilization*100)
+		if b.TTL >= 0 {
+			fmt.Println("ttl          : ", time.Duration(b.TTL)*time.Second)
+		}
+		fmt.Println("usable       : ", b.Usable)
+		if b.Warning != "" {
+			fmt.Println("warning      : ", b.Warning)
+		}
+	}
+}
@@ -21,9 +21,11 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/c-bata/go-prompt"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/file"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
 	"github.com/google/shlex"
 	"golang.org/x/term"
@@ -53,53 +55,68 @@ var (
 )
 
 const (
-	apiUserIsLoggedin  = APIVersion + "/user/isloggedin"
-	apiUserLogout      = APIVersion + "/user/logout"
-	apiUserStat        = APIVersion + "/user/stat"
-	apiPodNew          = APIVersion + "/pod/new"
-	apiPodOpen         = APIVersion + "/pod/open"
-	apiPodClose        = APIVersion + "/pod/close"
-	apiPodSync         = APIVersion + "/pod/sync"
-	apiPodDelete       = APIVersion + "/pod/delete"
-	apiPodLs           = APIVersion + "/pod/ls"
-	apiPodStat         = APIVersion + "/pod/stat"
-	apiPodShare        = APIVersion + "/pod/share"
-	apiPodReceive      = APIVersion + "/pod/receive"
-	apiPodReceiveInfo  = APIVersion + "/pod/receiveinfo"
-	apiDirIsPresent    = APIVersion + "/dir/present"
-	apiDirMkdir        = APIVersion + "/dir/mkdir"
-	apiDirRmdir        = APIVersion + "/dir/rmdir"
-	apiDirLs           = APIVersion + "/dir/ls"
-	apiDirStat         = APIVersion + "/dir/stat"
-	apiFileDownload    = APIVersion + "/file/download"
-	apiFileUpload      = APIVersion + "/file/upload"
-	apiFileShare       = APIVersion + "/file/share"
-	apiFileReceive     = APIVersion + "/file/receive"
-	apiFileReceiveInfo = APIVersion + "/file/receiveinfo"
-	apiFileDelete      = APIVersion + "/file/delete"
-	apiFileStat        = APIVersion + "/file/stat"
-	apiKVCreate        = APIVersion + "/kv/new"
-	apiKVList          = APIVersion + "/kv/ls"
-	apiKVOpen          = APIVersion + "/kv/open"
-	apiKVDelete        = APIVersion + "/kv/delete"
-	apiKVCount         = APIVersion + "/kv/count"
-	apiKVEntryPut      = APIVersion + "/kv/entry/put"
-	apiKVEntryGet      = APIVersion + "/kv/entry/get"
-	apiKVEntryDelete   = APIVersion + "/kv/entry/del"
-	apiKVLoadCSV       = APIVersion + "/kv/loadcsv"
-	apiKVSeek          = APIVersion + "/kv/seek"
-	apiKVSeekNext      = APIVersion + "/kv/seek/next"
-	apiDocCreate       = APIVersion + "/doc/new"
-	apiDocList         = APIVersion + "/doc/ls"
-	apiDocOpen         = APIVersion + "/doc/open"
-	apiDocCount        = APIVersion + "/doc/count"
-	apiDocDelete       = APIVersion + "/doc/delete"
-	apiDocFind         = APIVersion + "/doc/find"
-	apiDocEntryPut     = APIVersion + "/doc/entry/put"
-	apiDocEntryGet     = APIVersion + "/doc/entry/get"
-	apiDocEntryDel     = APIVersion + "/doc/entry/del"
-	apiDocLoadJson     = APIVersion + "/doc/loadjson"
-	apiDocIndexJson    = APIVersion + "/doc/indexjson"
+	apiUserIsLoggedin      = APIVersion + "/user/isloggedin"
+	apiUserLogout          = APIVersion + "/user/logout"
+	apiUserStat            = APIVersion + "/user/stat"
+	apiPostage             = APIVersion + "/postage"
+	apiPodNew              = APIVersion + "/pod/new"
+	apiPodOpen             = APIVersion + "/pod/open"
+	apiPodClose            = APIVersion + "/pod/close"
+	apiPodSync             = APIVersion + "/pod/sync"
+	apiPodGC               = APIVersion + "/pod/gc"
+	apiPodVersioning       = APIVersion + "/pod/versioning"
+	apiPodDelete           = APIVersion + "/pod/delete"
+	apiPodLs               = APIVersion + "/pod/ls"
+	apiPodStat             = APIVersion + "/pod/stat"
+	apiPodShare            = APIVersion + "/pod/share"
+	apiPodReceive          = APIVersion + "/pod/receive"
+	apiPodReceiveInfo      = APIVersion + "/pod/receiveinfo"
+	apiDirIsPresent        = APIVersion + "/dir/present"
+	apiDirMkdir            = APIVersion + "/dir/mkdir"
+	apiDirRmdir            = APIVersion + "/dir/rmdir"
+	apiDirLs               = APIVersion + "/dir/ls"
+	apiDirStat             = APIVersion + "/dir/stat"
+	apiDirHistory          = APIVersion + "/dir/history"
+	apiDirXattr            = APIVersion + "/dir/xattr"
+	apiDirSymlink          = APIVersion + "/dir/symlink"
+	apiDirCopy             = APIVersion + "/dir/copy"
+	apiFileDownload        = APIVersion + "/file/download"
+	apiFileUpload          = APIVersion + "/file/upload"
+	apiFileShare           = APIVersion + "/file/share"
+	apiFileReceive         = APIVersion + "/file/receive"
+	apiFileReceiveInfo     = APIVersion + "/file/receiveinfo"
+	apiFileDelete          = APIVersion + "/file/delete"
+	apiFileStat            = APIVersion + "/file/stat"
+	apiFileHistory         = APIVersion + "/file/history"
+	apiFileVerify          = APIVersion + "/file/verify"
+	apiFileXattr           = APIVersion + "/file/xattr"
+	apiFileCopy            = APIVersion + "/file/copy"
+	apiFileVersions        = APIVersion + "/file/versions"
+	apiFileVersionDownload = APIVersion + "/file/version/download"
+	apiFileVersionDiff     = APIVersion + "/file/version/diff"
+	apiFileVersionRestore  = APIVersion + "/file/version/restore"
+	apiKVCreate            = APIVersion + "/kv/new"
+	apiKVList              = APIVersion + "/kv/ls"
+	apiKVOpen              = APIVersion + "/kv/open"
+	apiKVDelete            = APIVersion + "/kv/delete"
+	apiKVCount             = APIVersion + "/kv/count"
+	apiKVEntryPut          = APIVersion + "/kv/entry/put"
+	apiKVEntryGet          = APIVersion + "/kv/entry/get"
+	apiKVEntryDelete       = APIVersion + "/kv/entry/del"
+	apiKVLoadCSV           = APIVersion + "/kv/loadcsv"
+	apiKVSeek              = APIVersion + "/kv/seek"
+	apiKVSeekNext          = APIVersion + "/kv/seek/next"
+	apiDocCreate           = APIVersion + "/doc/new"
+	apiDocList             = APIVersion + "/doc/ls"
+	apiDocOpen             = APIVersion + "/doc/open"
+	apiDocCount            = APIVersion + "/doc/count"
+	apiDocDelete           = APIVersion + "/doc/delete"
+	apiDocFind             = APIVersion + "/doc/find"
+	apiDocEntryPut         = APIVersion + "/doc/entry/put"
+	apiDocEntryGet         = APIVersion + "/doc/entry/get"
+	apiDocEntryDel         = APIVersion + "/doc/entry/del"
+	apiDocLoadJson         = APIVersion + "/doc/loadjson"
+	apiDocIndexJson        = APIVersion + "/doc/indexjson"
 
 	apiUserSignupV2  = APIVersionV2 + "/user/signup"
 	apiUserLoginV2   = APIVersionV2 + "/user/login"
@@ -160,6 +177,8 @@ var podSuggestions = []prompt.Suggest{
 	{Text: "ls", Description: "list all the existing pods of a user"},
 	{Text: "stat", Description: "show the metadata of a pod of a user"},
 	{Text: "sync", Description: "sync the pod from swarm"},
+	{Text: "gc", Description: "unpin the content of the pod that is no longer referenced"},
+	{Text: "versioning", Description: "show or set if the previous versions of the files are kept"},
 }
 
 var kvSuggestions = []prompt.Suggest{
@@ -204,6 +223,8 @@ var suggestions = []prompt.Suggest{
 	{Text: "pod ls", Description: "list all the existing pods of a user"},
 	{Text: "pod stat", Description: "show the metadata of a pod of a user"},
 	{Text: "pod sync", Description: "sync the pod from swarm"},
+	{Text: "pod gc", Description: "unpin the content of the pod that is no longer referenced"},
+	{Text: "pod versioning", Description: "show or set if the previous versions of the files are kept"},
 	{Text: "kv new", Description: "create new key value store"},
 	{Text: "kv delete", Description: "delete the  key value store"},
 	{Text: "kv ls", Description: "lists all the key value stores"},
@@ -235,8 +256,20 @@ var suggestions = []prompt.Suggest{
 	{Text: "ls", Description: "list all the file and directories in the current path"},
 	{Text: "mkdir", Description: "make a new directory"},
 	{Text: "rmdir", Description: "remove a existing directory"},
+	{Text: "ln", Description: "make a symbolic link to a file or directory"},
+	{Text: "unlink", Description: "remove a symbolic link"},
+	{Text: "cp", Description: "copy a file or directory, also to another open pod"},
 	{Text: "pwd", Description: "show the current working directory"},
 	{Text: "rm", Description: "remove a file"},
+	{Text: "history", Description: "show the revisions of a file or directory"},
+	{Text: "verify", Description: "check the content of a file against its checksums"},
+	{Text: "xattr", Description: "list the extended attributes of a file or directory"},
+	{Text: "setxattr", Description: "set an extended attribute of a file or directory"},
+	{Text: "rmxattr", Description: "remove an extended attribute of a file or directory"},
+	{Text: "versions", Description: "list the previous versions of a file"},
+	{Text: "restore", Description: "make a previous version of a file the current one"},
+	{Text: "diff", Description: "show the byte ranges that differ between two versions of a file"},
+	{Text: "postage", Description: "show the postage batches used for the uploads"},
 }
 
 func completer(in prompt.Document) []prompt.Suggest {
@@ -374,7 +407,11 @@ func executor(in string) {
 				return
 			}
 			podName := blocks[2]
-			podNew(podName)
+			feedType := ""
+			if len(blocks) > 3 {
+				feedType = blocks[3]
+			}
+			podNew(podName, feedType)
 			currentPrompt = getCurrentPrompt()
 		case "del":
 			if len(blocks) < 3 {
@@ -419,6 +456,49 @@ func executor(in string) {
 			}
 			syncPod(currentPod)
 			currentPrompt = getCurrentPrompt()
+		case "gc":
+			if !isPodOpened() {
+				return
+			}
+			dryRun := len(blocks) > 2 && blocks[2] == "dry-run"
+			gcPod(currentPod, dryRun)
+			currentPrompt = getCurrentPrompt()
+		case "versioning":
+			if !isPodOpened() {
+				return
+			}
+			if len(blocks) < 3 {
+				podVersioning(currentPod)
+				currentPrompt = getCurrentPrompt()
+				return
+			}
+			var policy file.VersionPolicy
+			switch blocks[2] {
+			case "on":
+				policy.Enabled = true
+			case "off":
+			default:
+				fmt.Println("invalid command. versioning should be \"on\" or \"off\"")
+				return
+			}
+			if len(blocks) > 3 {
+				keepVersions, err := strconv.Atoi(blocks[3])
+				if err != nil || keepVersions < 0 {
+					fmt.Println("invalid \"keep-versions\" argument")
+					return
+				}
+				policy.KeepVersions = keepVersions
+			}
+			if len(blocks) > 4 {
+				keepDays, err := strconv.Atoi(blocks[4])
+				if err != nil || keepDays < 0 {
+					fmt.Println("invalid \"keep-days\" argument")
+					return
+				}
+				policy.KeepDays = keepDays
+			}
+			setPodVersioning(currentPod, policy)
+			currentPrompt = getCurrentPrompt()
 		case "ls":
 			listPod()
 			currentPrompt = getCurrentPrompt()
@@ -752,6 +832,40 @@ func executor(in string) {
 		}
 		listFileAndDirectories(currentPod, currentDirectory)
 		currentPrompt = getCurrentPrompt()
+	case "ln":
+		if !isPodOpened() {
+			return
+		}
+		if len(blocks) < 3 {
+			fmt.Println("invalid command. Missing one or more arguments")
+			return
+		}
+		symlink(currentPod, blocks[1], podPathOf(blocks[2]))
+		currentPrompt = getCurrentPrompt()
+	case "unlink":
+		if !isPodOpened() {
+			return
+		}
+		if len(blocks) < 2 {
+			fmt.Println("invalid command. Missing one or more arguments")
+			return
+		}
+		unlink(currentPod, podPathOf(blocks[1]))
+		currentPrompt = getCurrentPrompt()
+	case "cp":
+		if !isPodOpened() {
+			return
+		}
+		if len(blocks) < 3 {
+			fmt.Println("invalid command. Missing one or more arguments")
+			return
+		}
+		dstPodName := ""
+		if len(blocks) > 3 {
+			dstPodName = blocks[3]
+		}
+		copyFileOrDir(currentPod, podPathOf(blocks[1]), dstPodName, podPathOf(blocks[2]))
+		currentPrompt = getCurrentPrompt()
 	case "mkdir":
 		if !isPodOpened() {
 			return
@@ -813,9 +927,9 @@ func executor(in string) {
 		blockSize := blocks[3]
 		compression := ""
 		if len(blocks) >= 5 {
-			compression = blocks[4]
-			if compression != "snappy" && compression != "gzip" {
-				fmt.Println("invalid value for \"compression\", should either be \"snappy\" or \"gzip\"")
+			compression = strings.ToLower(blocks[4])
+			if file.CheckCompression(compression) != nil {
+				fmt.Println("invalid value for \"compression\", should be one of \"snappy\", \"gzip\", \"zstd\", \"zstd:<level>\" or \"lz4\"")
 				return
 			}
 		}
@@ -851,6 +965,11 @@ func executor(in string) {
 			}
 		}
 
+		if len(blocks) > 3 {
+			downloadFileVersion(currentPod, loalFile, podFile, blocks[3])
+			currentPrompt = getCurrentPrompt()
+			return
+		}
 		downloadFile(currentPod, loalFile, podFile)
 		currentPrompt = getCurrentPrompt()
 	case "stat":
@@ -874,6 +993,110 @@ func executor(in string) {
 		}
 		statFileOrDirectory(currentPod, statElement)
 		currentPrompt = getCurrentPrompt()
+	case "history":
+		if !isPodOpened() {
+			return
+		}
+		if len(blocks) < 2 {
+			fmt.Println("invalid command. Missing one or more arguments")
+			return
+		}
+		element := blocks[1]
+		if element == "" {
+			return
+		}
+		if !strings.HasPrefix(element, utils.PathSeparator) {
+			if currentDirectory == utils.PathSeparator {
+				element = currentDirectory + element
+			} else {
+				element = currentDirectory + utils.PathSeparator + element
+			}
+		}
+		historyOfFileOrDirectory(currentPod, element)
+		currentPrompt = getCurrentPrompt()
+	case "verify":
+		if !isPodOpened() {
+			return
+		}
+		if len(blocks) < 2 {
+			fmt.Println("invalid command. Missing one or more arguments")
+			return
+		}
+		verifyFile(currentPod, podPathOf(blocks[1]))
+		currentPrompt = getCurrentPrompt()
+	case "xattr":
+		if !isPodOpened() {
+			return
+		}
+		if len(blocks) < 2 {
+			fmt.Println("invalid command. Missing one or more arguments")
+			return
+		}
+		name := ""
+		if len(blocks) > 2 {
+			name = blocks[2]
+		}
+		listXattrs(currentPod, podPathOf(blocks[1]), name)
+		currentPrompt = getCurrentPrompt()
+	case "setxattr":
+		if !isPodOpened() {
+			return
+		}
+		if len(blocks) < 4 {
+			fmt.Println("invalid command. Missing one or more arguments")
+			return
+		}
+		changeXattr(currentPod, podPathOf(blocks[1]), blocks[2], strings.Join(blocks[3:], " "), false)
+		currentPrompt = getCurrentPrompt()
+	case "rmxattr":
+		if !isPodOpened() {
+			return
+		}
+		if len(blocks) < 3 {
+			fmt.Println("invalid command. Missing one or more arguments")
+			return
+		}
+		changeXattr(currentPod, podPathOf(blocks[1]), blocks[2], "", true)
+		currentPrompt = getCurrentPrompt()
+	case "versions":
+		if !isPodOpened() {
+			return
+		}
+		if len(blocks) < 2 {
+			fmt.Println("invalid command. Missing one or more arguments")
+			return
+		}
+		listFileVersions(currentPod, podPathOf(blocks[1]))
+		currentPrompt = getCurrentPrompt()
+	case "restore":
+		if !isPodOpened() {
+			return
+		}
+		if len(blocks) < 3 {
+			fmt.Println("invalid command. Missing one or more arguments")
+			return
+		}
+		version, err := strconv.Atoi(blocks[2])
+		if err != nil {
+			fmt.Println("invalid \"version\" argument")
+			return
+		}
+		restoreFileVersion(currentPod, podPathOf(blocks[1]), version)
+		currentPrompt = getCurrentPrompt()
+	case "diff":
+		if !isPodOpened() {
+			return
+		}
+		if len(blocks) < 3 {
+			fmt.Println("invalid command. Missing one or more arguments")
+			return
+		}
+		to := "0"
+		if len(blocks) > 3 {
+			to = blocks[3]
+		}
+		diffFileVersions(currentPod, podPathOf(blocks[1]), blocks[2], to)
+		currentPrompt = getCurrentPrompt()
 	case "pwd":
 		if !isPodOpened() {
 			return
@@ -929,6 +1152,8 @@ func executor(in string) {
 		podDir := blocks[2]
 		fileReceive(currentPod, sharingRefString, podDir)
 		currentPrompt = getCurrentPrompt()
+	case "postage":
+		postageBatches()
 	case "receiveinfo":
 		if len(blocks) < 2 {
 			fmt.Println("invalid command. Missing one or more arguments")
@@ -952,11 +1177,13 @@ func help() {
 	fmt.Println(" - user <present> (user-name) - returns true if the user is present, false otherwise")
 	fmt.Println(" - user <stat> - shows information about a user")
 
-	fmt.Println(" - pod <new> (pod-name) - create a new pod for the logged-in user and opens the pod")
+	fmt.Println(" - pod <new> (pod-name) [epoch|sequence] - create a new pod for the logged-in user and opens the pod, the pod keeps its files in feeds of the given type")
 	fmt.Println(" - pod <del> (pod-name) - deletes a already created pod of the user")
 	fmt.Println(" - pod <open> (pod-name) - open a already created pod")
 	fmt.Println(" - pod <stat> (pod-name) - display meta information about a pod")
 	fmt.Println(" - pod <sync> (pod-name) - sync the contents of a logged-in pod from Swarm")
+	fmt.Println(" - pod <gc> [dry-run] - unpin the content of the opened pod that is no longer referenced")
+	fmt.Println(" - pod <versioning> [on|off] [keep-versions] [keep-days] - show or set if the previous versions of the files of the opened pod are kept")
 	fmt.Println(" - pod <close>  - close a opened pod")
 	fmt.Println(" - pod <ls> - lists all the pods created for this account")
 
@@ -986,16 +1213,28 @@ func help() {
 
 	fmt.Println(" - cd <directory name>")
 	fmt.Println(" - ls ")
-	fmt.Println(" - download <destination dir in local fs> <relative path of source file in pod>")
-	fmt.Println(" - upload <source file in local fs> <destination directory in pod> <block size (ex: 1Mb, 64Mb)>, <compression (snappy/gzip)>")
+	fmt.Println(" - download <destination dir in local fs> <relative path of source file in pod> [version]")
+	fmt.Println(" - upload <source file in local fs> <destination directory in pod> <block size (ex: 1Mb, 64Mb)>, <compression (snappy/gzip/zstd/zstd:<level>/lz4)>")
 	fmt.Println(" - share <file name> -  shares a file with another user")
 	fmt.Println(" - receive <sharing reference> <pod dir> - receives a file from another user")
 	fmt.Println(" - receiveinfo <sharing reference> - shows the received file info before accepting the receive")
 	fmt.Println(" - mkdir <directory name>")
 	fmt.Println(" - rmdir <directory name>")
+	fmt.Println(" - ln <target> <link name> - makes a symbolic link, a relative target is relative to the directory of the link")
+	fmt.Println(" - unlink <link name> - removes a symbolic link, not its target")
+	fmt.Println(" - cp <source> <destination> [destination pod] - copies a file or directory without uploading its content again, the destination pod has to be open")
 	fmt.Println(" - rm <file name>")
 	fmt.Println(" - pwd - show present working directory")
 	fmt.Println(" - stat <file name or directory name> - shows the information about a file or directory")
+	fmt.Println(" - history <file name or directory name> - shows the revisions of a file or directory")
+	fmt.Println(" - verify <file name> - checks the content of a file against its checksums")
+	fmt.Println(" - xattr <file name or directory name> [name] - lists the extended attributes of a file or directory, or shows one of them")
+	fmt.Println(" - setxattr <file name or directory name> <name> <value> - sets an extended attribute of a file or directory")
+	fmt.Println(" - rmxattr <file name or directory name> <name> - removes an extended attribute of a file or directory")
+	fmt.Println(" - versions <file name> - lists the previous versions of a file")
+	fmt.Println(" - restore <file name> <version> - makes a previous version of a file the current one")
+	fmt.Println(" - diff <file name> <from version> [to version] - shows the byte ranges that differ between two versions, 0 is the current one")
+	fmt.Println(" - postage - shows the utilization and ttl of the postage batches used for the uploads")
 	fmt.Println(" - help - display this help")
 	fmt.Println(" - exit - exits from the prompt")
 
@@ -1014,6 +1253,17 @@ func getCurrentPrompt() string {
 	return currPrompt
 }
 
+// podPathOf resolves a file name against the current directory
+func podPathOf(element string) string {
+	if strings.HasPrefix(element, utils.PathSeparator) {
+		return element
+	}
+	if currentDirectory == utils.PathSeparator {
+		return currentDirectory + element
+	}
+	return currentDirectory + utils.PathSeparator + element
+}
+
 func isPodOpened() bool {
 	if currentPod == "" {
 		fmt.Println("open the pod to do the operation")
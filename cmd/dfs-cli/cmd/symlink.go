@@ -0,0 +1,65 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/api"
+)
+
+func symlink(podName, target, linkWithPath string) {
+	symlinkReq := api.SymlinkRequest{
+		PodName:  podName,
+		LinkPath: linkWithPath,
+		Target:   target,
+	}
+	jsonData, err := json.Marshal(symlinkReq)
+	if err != nil {
+		fmt.Println("ln: error marshalling request")
+		return
+	}
+	data, err := fdfsAPI.postReq(http.MethodPost, apiDirSymlink, jsonData)
+	if err != nil {
+		fmt.Println("ln: ", err)
+		return
+	}
+	message := strings.ReplaceAll(string(data), "\n", "")
+	fmt.Println(message)
+}
+
+func unlink(podName, linkWithPath string) {
+	symlinkReq := api.SymlinkRequest{
+		PodName:  podName,
+		LinkPath: linkWithPath,
+	}
+	jsonData, err := json.Marshal(symlinkReq)
+	if err != nil {
+		fmt.Println("unlink: error marshalling request")
+		return
+	}
+	data, err := fdfsAPI.postReq(http.MethodDelete, apiDirSymlink, jsonData)
+	if err != nil {
+		fmt.Println("unlink: ", err)
+		return
+	}
+	message := strings.ReplaceAll(string(data), "\n", "")
+	fmt.Println(message)
+}
@@ -0,0 +1,149 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/api"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/file"
+)
+
+func listFileVersions(podName, podFileName string) {
+	args := fmt.Sprintf("podName=%s&filePath=%s", podName, url.QueryEscape(podFileName))
+	data, err := fdfsAPI.getReq(apiFileVersions, args)
+	if err != nil {
+		fmt.Println("versions: ", err)
+		return
+	}
+	var resp api.FileVersionsResponse
+	err = json.Unmarshal(data, &resp)
+	if err != nil {
+		fmt.Println("versions: ", err)
+		return
+	}
+	if len(resp.Versions) == 0 {
+		fmt.Println("no previous versions")
+		return
+	}
+	for _, v := range resp.Versions {
+		fmt.Println(v.Version, " ", time.Unix(v.ReplacedTime, 0).String(), " ", v.Size, " bytes, inode 0x"+hex.EncodeToString(v.InodeAddress))
+	}
+}
+
+func downloadFileVersion(podName, localFileName, podFileName, version string) {
+	args := fmt.Sprintf("podName=%s&filePath=%s&version=%s", podName, url.QueryEscape(podFileName), version)
+	data, err := fdfsAPI.getReq(apiFileVersionDownload, args)
+	if err != nil {
+		fmt.Println("download failed: ", err)
+		return
+	}
+	err = os.WriteFile(localFileName, data, 0600)
+	if err != nil {
+		fmt.Println("download failed: ", err)
+		return
+	}
+	fmt.Println("Downloaded ", len(data), " bytes")
+}
+
+func restoreFileVersion(podName, podFileName string, version int) {
+	restoreReq := api.FileVersionRequest{
+		PodName:  podName,
+		FilePath: podFileName,
+		Version:  version,
+	}
+	jsonData, err := json.Marshal(restoreReq)
+	if err != nil {
+		fmt.Println("restore: error marshalling request")
+		return
+	}
+	data, err := fdfsAPI.postReq(http.MethodPost, apiFileVersionRestore, jsonData)
+	if err != nil {
+		fmt.Println("restore: ", err)
+		return
+	}
+	message := strings.ReplaceAll(string(data), "\n", "")
+	fmt.Println(message)
+}
+
+func diffFileVersions(podName, podFileName, from, to string) {
+	args := fmt.Sprintf("podName=%s&filePath=%s&from=%s&to=%s", podName, url.QueryEscape(podFileName), from, to)
+	data, err := fdfsAPI.getReq(apiFileVersionDiff, args)
+	if err != nil {
+		fmt.Println("diff: ", err)
+		return
+	}
+	var diff file.VersionDiff
+	err = json.Unmarshal(data, &diff)
+	if err != nil {
+		fmt.Println("diff: ", err)
+		return
+	}
+	fmt.Println("From       : ", diff.From, " (", diff.FromSize, " bytes)")
+	fmt.Println("To         : ", diff.To, " (", diff.ToSize, " bytes)")
+	if len(diff.Changed) == 0 {
+		fmt.Println("no changes")
+		return
+	}
+	for _, r := range diff.Changed {
+		fmt.Println("  ", r.Offset, " - ", r.Offset+r.Length, " (", r.Length, " bytes)")
+	}
+}
+
+func podVersioning(podName string) {
+	args := fmt.Sprintf("podName=%s", podName)
+	data, err := fdfsAPI.getReq(apiPodVersioning, args)
+	if err != nil {
+		fmt.Println("pod versioning: ", err)
+		return
+	}
+	var policy file.VersionPolicy
+	err = json.Unmarshal(data, &policy)
+	if err != nil {
+		fmt.Println("pod versioning: ", err)
+		return
+	}
+	fmt.Println("Enabled       : ", policy.Enabled)
+	fmt.Println("Keep versions : ", policy.KeepVersions)
+	fmt.Println("Keep days     : ", policy.KeepDays)
+}
+
+func setPodVersioning(podName string, policy file.VersionPolicy) {
+	versioningReq := api.PodVersioningRequest{
+		PodName:       podName,
+		VersionPolicy: policy,
+	}
+	jsonData, err := json.Marshal(versioningReq)
+	if err != nil {
+		fmt.Println("pod versioning: error marshalling request")
+		return
+	}
+	data, err := fdfsAPI.postReq(http.MethodPost, apiPodVersioning, jsonData)
+	if err != nil {
+		fmt.Println("pod versioning: ", err)
+		return
+	}
+	message := strings.ReplaceAll(string(data), "\n", "")
+	fmt.Println(message)
+}
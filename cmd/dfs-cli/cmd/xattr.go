@@ -0,0 +1,103 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/api"
+)
+
+func listXattrs(podName, podPath, name string) {
+	urlPath, pathArg := apiFileXattr, "filePath"
+	if isDirectoryPresent(podName, podPath) {
+		urlPath, pathArg = apiDirXattr, "dirPath"
+	}
+	args := fmt.Sprintf("podName=%s&%s=%s&name=%s", podName, pathArg, url.QueryEscape(podPath), url.QueryEscape(name))
+	data, err := fdfsAPI.getReq(urlPath, args)
+	if err != nil {
+		fmt.Println("xattr: ", err)
+		return
+	}
+	if name != "" {
+		var resp api.XattrResponse
+		err = json.Unmarshal(data, &resp)
+		if err != nil {
+			fmt.Println("xattr: ", err)
+			return
+		}
+		fmt.Println(resp.Value)
+		return
+	}
+	var resp api.XattrsResponse
+	err = json.Unmarshal(data, &resp)
+	if err != nil {
+		fmt.Println("xattr: ", err)
+		return
+	}
+	if len(resp.Xattrs) == 0 {
+		fmt.Println("no extended attributes")
+		return
+	}
+	printXattrs(resp.Xattrs)
+}
+
+func printXattrs(xattrs map[string]string) {
+	names := make([]string, 0, len(xattrs))
+	for name := range xattrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Println(name, "=", xattrs[name])
+	}
+}
+
+func changeXattr(podName, podPath, name, value string, remove bool) {
+	op, method := "setxattr", http.MethodPost
+	if remove {
+		op, method = "rmxattr", http.MethodDelete
+	}
+	xattrReq := api.XattrRequest{
+		PodName: podName,
+		Name:    name,
+		Value:   value,
+	}
+	urlPath := apiFileXattr
+	if isDirectoryPresent(podName, podPath) {
+		urlPath, xattrReq.DirPath = apiDirXattr, podPath
+	} else {
+		xattrReq.FilePath = podPath
+	}
+	jsonData, err := json.Marshal(xattrReq)
+	if err != nil {
+		fmt.Println(op + ": error marshalling request")
+		return
+	}
+	data, err := fdfsAPI.postReq(method, urlPath, jsonData)
+	if err != nil {
+		fmt.Println(op+": ", err)
+		return
+	}
+	message := strings.ReplaceAll(string(data), "\n", "")
+	fmt.Println(message)
+}
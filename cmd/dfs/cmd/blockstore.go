@@ -0,0 +1,228 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/bee"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/cache"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/journal"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/local"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/pool"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/s3"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	"github.com/mitchellh/go-homedir"
+)
+
+const (
+	blockstoreBee   = "bee"
+	blockstoreLocal = "local"
+	blockstoreS3    = "s3"
+)
+
+var (
+	blockstoreType   string
+	beeApis          []string
+	beeProbeInterval time.Duration
+	beeRetry         = bee.DefaultRetryOptions()
+	beeDebugApi      string
+	postageBatchIds  []string
+	beePostage       = bee.DefaultPostageOptions()
+	localStoreDir    string
+	s3Options        s3.Options
+	cacheDir         string
+	cacheSize        string
+	cachePolicy      string
+	cacheFeedTTL     time.Duration
+	journalDir       string
+	journalReplay    time.Duration
+)
+
+// newBlockstoreClient creates the blockstore client configured for the server
+func newBlockstoreClient(logger logging.Logger) (blockstore.Client, error) {
+	client, err := newBackendClient(logger)
+	if err != nil {
+		return nil, err
+	}
+	// the journal is in front of the backend, so that the cache also keeps journaled blobs
+	if journalDir != "" {
+		client, err = journal.NewClient(client, journal.Options{
+			Dir:            journalDir,
+			ReplayInterval: journalReplay,
+		}, logger)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cacheDir == "" {
+		return client, nil
+	}
+	size, err := humanize.ParseBytes(cacheSize)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cacheSize %s: %w", cacheSize, err)
+	}
+	return cache.NewClient(client, cache.Options{
+		Dir:     cacheDir,
+		MaxSize: int64(size),
+		Policy:  cachePolicy,
+		FeedTTL: cacheFeedTTL,
+	}, logger)
+}
+
+func newBackendClient(logger logging.Logger) (blockstore.Client, error) {
+	switch blockstoreType {
+	case blockstoreBee:
+		return newBeeClient(logger)
+	case blockstoreLocal:
+		if localStoreDir == "" {
+			return nil, fmt.Errorf("localStoreDir is required for local blockstore")
+		}
+		return local.NewClient(localStoreDir, logger)
+	case blockstoreS3:
+		opts := s3Options
+		if opts.AccessKey == "" {
+			opts.AccessKey = os.Getenv("AWS_ACCESS_KEY_ID")
+		}
+		if opts.SecretKey == "" {
+			opts.SecretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		}
+		return s3.NewClient(opts, logger)
+	default:
+		return nil, fmt.Errorf("unknown blockstore %s", blockstoreType)
+	}
+}
+
+// beeEndpoint is a bee node of the pool with the postage batches bought by its own wallet,
+// as postage batches can only be used on the node that owns them
+type beeEndpoint struct {
+	api      string
+	debugApi string
+	batchIds []string
+}
+
+// parseBeeEndpoint parses an entry of beeApis, "url|debugUrl|batch1;batch2". The debug url
+// and the batches are optional, a node without batches stamps with the zero batch of a
+// gateway.
+func parseBeeEndpoint(entry string) (beeEndpoint, error) {
+	parts := strings.Split(strings.TrimSpace(entry), "|")
+	if len(parts) > 3 || parts[0] == "" {
+		return beeEndpoint{}, fmt.Errorf("beeApis entry %q should be url|debugUrl|batch1;batch2", entry)
+	}
+	e := beeEndpoint{api: parts[0]}
+	if len(parts) > 1 {
+		e.debugApi = parts[1]
+	}
+	if len(parts) > 2 {
+		for _, id := range strings.Split(parts[2], ";") {
+			id = strings.TrimSpace(id)
+			if id == "" {
+				continue
+			}
+			if !isBatchId(id) {
+				return beeEndpoint{}, fmt.Errorf("beeApis entry %q has an invalid postage batch %s", entry, id)
+			}
+			e.batchIds = append(e.batchIds, id)
+		}
+	}
+	return e, nil
+}
+
+// beeEndpoints returns beeApi with its postage batches followed by the other bee nodes
+func beeEndpoints() ([]beeEndpoint, error) {
+	endpoints := []beeEndpoint{{
+		api:      beeApi,
+		debugApi: beeDebugApi,
+		batchIds: append([]string{postageBlockId}, postageBatchIds...),
+	}}
+	seen := map[string]bool{beeApi: true}
+	for _, entry := range beeApis {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		e, err := parseBeeEndpoint(entry)
+		if err != nil {
+			return nil, err
+		}
+		if seen[e.api] {
+			continue
+		}
+		seen[e.api] = true
+		if len(e.batchIds) == 0 {
+			if postageBlockId != zeroBatchId && postageBlockId != "0" {
+				return nil, fmt.Errorf("bee endpoint %s needs the postage batches of its own node", e.api)
+			}
+			e.batchIds = []string{postageBlockId}
+		}
+		endpoints = append(endpoints, e)
+	}
+	return endpoints, nil
+}
+
+// newBeeClient creates a single bee client, or a pool when more endpoints are given
+func newBeeClient(logger logging.Logger) (blockstore.Client, error) {
+	endpoints, err := beeEndpoints()
+	if err != nil {
+		return nil, err
+	}
+	if len(endpoints) == 1 {
+		return newBeeNodeClient(endpoints[0], logger), nil
+	}
+	nodes := make([]pool.Node, len(endpoints))
+	for i, e := range endpoints {
+		nodes[i] = pool.Node{
+			Name:   e.api,
+			Client: newBeeNodeClient(e, logger),
+		}
+	}
+	return pool.NewClient(nodes, pool.Options{ProbeInterval: beeProbeInterval}, logger)
+}
+
+// newBeeNodeClient creates a bee client that monitors the postage batches of its node and
+// switches to the next one when the active batch is full
+func newBeeNodeClient(e beeEndpoint, logger logging.Logger) *bee.Client {
+	c := bee.NewBeeClientWithRetry(e.api, e.batchIds[0], beeRetry, logger)
+	var batchIds []string
+	for _, id := range e.batchIds {
+		// the zero batch is stamped by a gateway, there is nothing to monitor
+		if id != zeroBatchId && id != "0" {
+			batchIds = append(batchIds, id)
+		}
+	}
+	if len(batchIds) == 0 {
+		return c
+	}
+	opts := beePostage
+	opts.BatchIDs = batchIds
+	opts.DebugApiUrl = e.debugApi
+	c.SetPostage(opts)
+	return c
+}
+
+func defaultLocalStoreDir() string {
+	home, err := homedir.Dir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".fairOS", "dfs", "blockstore")
+}
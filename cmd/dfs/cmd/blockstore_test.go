@@ -0,0 +1,136 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/bee"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	"github.com/sirupsen/logrus"
+)
+
+func TestBeeEndpoints(t *testing.T) {
+	batchA := strings.Repeat("a", 64)
+	batchB := strings.Repeat("b", 64)
+	defer func(api, block string, apis, batches []string, postage bee.PostageOptions) {
+		beeApi, postageBlockId, beeApis, postageBatchIds, beePostage = api, block, apis, batches, postage
+	}(beeApi, postageBlockId, beeApis, postageBatchIds, beePostage)
+	beePostage.RefreshInterval = 0
+	postageBatchIds = nil
+
+	// newNode serves a bee node that records the postage batches of the uploads it gets
+	newNode := func(t *testing.T) (*httptest.Server, func() []string) {
+		var (
+			mu   sync.Mutex
+			used []string
+		)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/":
+				_, _ = w.Write([]byte("Ethereum Swarm Bee\n"))
+			case r.Method == http.MethodPost && r.URL.Path == "/bytes":
+				_, _ = io.ReadAll(r.Body)
+				mu.Lock()
+				used = append(used, r.Header.Get("Swarm-Postage-Batch-Id"))
+				mu.Unlock()
+				_, _ = w.Write([]byte(`{"reference":"` + strings.Repeat("0", 63) + `1"}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		t.Cleanup(srv.Close)
+		return srv, func() []string {
+			mu.Lock()
+			defer mu.Unlock()
+			return append([]string{}, used...)
+		}
+	}
+
+	t.Run("batches-of-each-node", func(t *testing.T) {
+		node1, used1 := newNode(t)
+		node2, used2 := newNode(t)
+		beeApi, postageBlockId = node1.URL, batchA
+		beeApis = []string{node2.URL + "|" + node2.URL + "|" + batchB}
+
+		client, err := newBeeClient(logging.New(io.Discard, logrus.ErrorLevel))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 4; i++ {
+			_, err = client.UploadBlob(context.Background(), []byte{byte(i)}, 0, false, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		for node, used := range map[string][]string{batchA: used1(), batchB: used2()} {
+			if len(used) == 0 {
+				t.Fatalf("expected uploads stamped with %s", node)
+			}
+			for _, id := range used {
+				if id != node {
+					t.Fatalf("node of batch %s got an upload stamped with %s", node, id)
+				}
+			}
+		}
+	})
+
+	t.Run("node-without-batches", func(t *testing.T) {
+		beeApi, postageBlockId = "http://localhost:1633", batchA
+		beeApis = []string{"http://localhost:1733"}
+		_, err := beeEndpoints()
+		if err == nil {
+			t.Fatal("a node without batches should need its own")
+		}
+
+		// the zero batch is stamped by a gateway, so every node can use it
+		postageBlockId = zeroBatchId
+		endpoints, err := beeEndpoints()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(endpoints) != 2 || endpoints[1].batchIds[0] != zeroBatchId {
+			t.Fatalf("unexpected endpoints %+v", endpoints)
+		}
+	})
+
+	t.Run("invalid-entries", func(t *testing.T) {
+		postageBlockId = batchA
+		for _, entry := range []string{"http://localhost:1733|a|b|c", "|http://localhost:1735", "http://localhost:1733||zz"} {
+			beeApis = []string{entry}
+			_, err := beeEndpoints()
+			if err == nil {
+				t.Fatalf("%q should be invalid", entry)
+			}
+		}
+
+		beeApis = []string{"http://localhost:1733|http://localhost:1735|" + batchB + ";" + batchA}
+		endpoints, err := beeEndpoints()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if endpoints[1].debugApi != "http://localhost:1735" || len(endpoints[1].batchIds) != 2 || endpoints[1].batchIds[0] != batchB {
+			t.Fatalf("unexpected endpoint %+v", endpoints[1])
+		}
+	})
+}
@@ -1,6 +1,11 @@
 package cmd
 
 import (
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/bee"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/cache"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/journal"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/pool"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/s3"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
 )
@@ -11,7 +16,18 @@ var (
 	optionDFSPprofPort          = "dfs.ports.pprof-port"
 	optionVerbosity             = "verbosity"
 	optionBeeApi                = "bee.bee-api-endpoint"
+	optionBeeApis               = "bee.bee-api-endpoints"
+	optionBeeProbeInterval      = "bee.probe-interval"
 	optionBeePostageBatchId     = "bee.postage-batch-id"
+	optionBeePostageBatchIds    = "bee.postage-batch-ids"
+	optionBeeDebugApi           = "bee.bee-debug-api-endpoint"
+	optionPostageWarnUsage      = "bee.postage-warn-utilization"
+	optionPostageMaxUsage       = "bee.postage-max-utilization"
+	optionPostageWarnTTL        = "bee.postage-warn-ttl"
+	optionBeeMaxRetries         = "bee.max-retries"
+	optionBeeRetryBackoff       = "bee.retry-backoff"
+	optionBeeBreakerThreshold   = "bee.breaker-threshold"
+	optionBeeBreakerCooldown    = "bee.breaker-cooldown"
 	optionCookieDomain          = "cookie-domain"
 	optionNetwork               = "network"
 	optionRPC                   = "rpc"
@@ -19,13 +35,41 @@ var (
 	optionPublicResolverAddress = "ens.public-resolver-address"
 	optionFDSRegistrarAddress   = "ens.fds-registrar-address"
 	optionENSRegistryAddress    = "ens.ens-registry-address"
+	optionBlockstore            = "blockstore.type"
+	optionLocalStoreDir         = "blockstore.local-dir"
+	optionS3Endpoint            = "blockstore.s3.endpoint"
+	optionS3Bucket              = "blockstore.s3.bucket"
+	optionS3Region              = "blockstore.s3.region"
+	optionS3AccessKey           = "blockstore.s3.access-key"
+	optionS3SecretKey           = "blockstore.s3.secret-key"
+	optionS3Prefix              = "blockstore.s3.prefix"
+	optionCacheDir              = "blockstore.cache.dir"
+	optionCacheSize             = "blockstore.cache.size"
+	optionCachePolicy           = "blockstore.cache.policy"
+	optionCacheFeedTTL          = "blockstore.cache.feed-ttl"
+	optionJournalDir            = "blockstore.journal.dir"
+	optionJournalReplay         = "blockstore.journal.replay-interval"
 
-	defaultCORSAllowedOrigins = []string{}
-	defaultDFSHttpPort        = ":9090"
-	defaultDFSPprofPort       = ":9091"
-	defaultVerbosity          = "trace"
-	defaultBeeApi             = "http://localhost:1633"
-	defaultCookieDomain       = "api.fairos.io"
+	defaultCORSAllowedOrigins  = []string{}
+	defaultDFSHttpPort         = ":9090"
+	defaultDFSPprofPort        = ":9091"
+	defaultVerbosity           = "trace"
+	defaultBeeApi              = "http://localhost:1633"
+	defaultCookieDomain        = "api.fairos.io"
+	defaultBlockstore          = blockstoreBee
+	defaultBeeMaxRetries       = bee.DefaultRetryOptions().MaxRetries
+	defaultBeeRetryBackoff     = bee.DefaultRetryOptions().InitialBackoff
+	defaultBeeBreakerThreshold = bee.DefaultRetryOptions().BreakerThreshold
+	defaultBeeBreakerCooldown  = bee.DefaultRetryOptions().BreakerCooldown
+	defaultBeeProbeInterval    = pool.DefaultProbeInterval
+	defaultPostageWarnUsage    = bee.DefaultPostageWarnUtilization
+	defaultPostageMaxUsage     = bee.DefaultPostageMaxUtilization
+	defaultPostageWarnTTL      = bee.DefaultPostageWarnTTL
+	defaultCacheSize           = "1GB"
+	defaultCachePolicy         = cache.PolicyLRU
+	defaultCacheFeedTTL        = cache.DefaultFeedTTL
+	defaultJournalReplay       = journal.DefaultReplayInterval
+	defaultS3Region            = s3.DefaultRegion
 )
 
 var configCmd = &cobra.Command{
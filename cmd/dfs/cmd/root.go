@@ -161,8 +161,33 @@ func writeConfig() {
 	c.Set(optionDFSPprofPort, defaultDFSPprofPort)
 	c.Set(optionVerbosity, defaultVerbosity)
 	c.Set(optionBeeApi, defaultBeeApi)
+	c.Set(optionBeeApis, []string{})
+	c.Set(optionBeeProbeInterval, defaultBeeProbeInterval)
 	c.Set(optionBeePostageBatchId, "")
+	c.Set(optionBeePostageBatchIds, []string{})
+	c.Set(optionBeeDebugApi, "")
+	c.Set(optionPostageWarnUsage, defaultPostageWarnUsage)
+	c.Set(optionPostageMaxUsage, defaultPostageMaxUsage)
+	c.Set(optionPostageWarnTTL, defaultPostageWarnTTL)
+	c.Set(optionBeeMaxRetries, defaultBeeMaxRetries)
+	c.Set(optionBeeRetryBackoff, defaultBeeRetryBackoff)
+	c.Set(optionBeeBreakerThreshold, defaultBeeBreakerThreshold)
+	c.Set(optionBeeBreakerCooldown, defaultBeeBreakerCooldown)
 	c.Set(optionCookieDomain, defaultCookieDomain)
+	c.Set(optionBlockstore, defaultBlockstore)
+	c.Set(optionLocalStoreDir, defaultLocalStoreDir())
+	c.Set(optionS3Endpoint, "")
+	c.Set(optionS3Bucket, "")
+	c.Set(optionS3Region, defaultS3Region)
+	c.Set(optionS3AccessKey, "")
+	c.Set(optionS3SecretKey, "")
+	c.Set(optionS3Prefix, "")
+	c.Set(optionCacheDir, "")
+	c.Set(optionCacheSize, defaultCacheSize)
+	c.Set(optionCachePolicy, defaultCachePolicy)
+	c.Set(optionCacheFeedTTL, defaultCacheFeedTTL)
+	c.Set(optionJournalDir, "")
+	c.Set(optionJournalReplay, defaultJournalReplay)
 
 	if err := c.WriteConfigAs(cfgFile); err != nil {
 		fmt.Println("failed to write config file")
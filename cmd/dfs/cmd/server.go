@@ -87,6 +87,81 @@ can consume it.`,
 		if err := config.BindPFlag(optionRPC, cmd.Flags().Lookup("rpc")); err != nil {
 			return err
 		}
+		if err := config.BindPFlag(optionBlockstore, cmd.Flags().Lookup("blockstore")); err != nil {
+			return err
+		}
+		if err := config.BindPFlag(optionLocalStoreDir, cmd.Flags().Lookup("localStoreDir")); err != nil {
+			return err
+		}
+		if err := config.BindPFlag(optionS3Endpoint, cmd.Flags().Lookup("s3Endpoint")); err != nil {
+			return err
+		}
+		if err := config.BindPFlag(optionS3Bucket, cmd.Flags().Lookup("s3Bucket")); err != nil {
+			return err
+		}
+		if err := config.BindPFlag(optionS3Region, cmd.Flags().Lookup("s3Region")); err != nil {
+			return err
+		}
+		if err := config.BindPFlag(optionS3AccessKey, cmd.Flags().Lookup("s3AccessKey")); err != nil {
+			return err
+		}
+		if err := config.BindPFlag(optionS3SecretKey, cmd.Flags().Lookup("s3SecretKey")); err != nil {
+			return err
+		}
+		if err := config.BindPFlag(optionS3Prefix, cmd.Flags().Lookup("s3Prefix")); err != nil {
+			return err
+		}
+		if err := config.BindPFlag(optionBeeApis, cmd.Flags().Lookup("beeApis")); err != nil {
+			return err
+		}
+		if err := config.BindPFlag(optionBeeProbeInterval, cmd.Flags().Lookup("beeProbeInterval")); err != nil {
+			return err
+		}
+		if err := config.BindPFlag(optionBeePostageBatchIds, cmd.Flags().Lookup("postageBatchIds")); err != nil {
+			return err
+		}
+		if err := config.BindPFlag(optionBeeDebugApi, cmd.Flags().Lookup("beeDebugApi")); err != nil {
+			return err
+		}
+		if err := config.BindPFlag(optionPostageWarnUsage, cmd.Flags().Lookup("postageWarnUtilization")); err != nil {
+			return err
+		}
+		if err := config.BindPFlag(optionPostageMaxUsage, cmd.Flags().Lookup("postageMaxUtilization")); err != nil {
+			return err
+		}
+		if err := config.BindPFlag(optionPostageWarnTTL, cmd.Flags().Lookup("postageWarnTTL")); err != nil {
+			return err
+		}
+		if err := config.BindPFlag(optionBeeMaxRetries, cmd.Flags().Lookup("beeMaxRetries")); err != nil {
+			return err
+		}
+		if err := config.BindPFlag(optionBeeRetryBackoff, cmd.Flags().Lookup("beeRetryBackoff")); err != nil {
+			return err
+		}
+		if err := config.BindPFlag(optionBeeBreakerThreshold, cmd.Flags().Lookup("beeBreakerThreshold")); err != nil {
+			return err
+		}
+		if err := config.BindPFlag(optionBeeBreakerCooldown, cmd.Flags().Lookup("beeBreakerCooldown")); err != nil {
+			return err
+		}
+		if err := config.BindPFlag(optionCacheDir, cmd.Flags().Lookup("cacheDir")); err != nil {
+			return err
+		}
+		if err := config.BindPFlag(optionCacheSize, cmd.Flags().Lookup("cacheSize")); err != nil {
+			return err
+		}
+		if err := config.BindPFlag(optionCachePolicy, cmd.Flags().Lookup("cachePolicy")); err != nil {
+			return err
+		}
+		if err := config.BindPFlag(optionCacheFeedTTL, cmd.Flags().Lookup("cacheFeedTTL")); err != nil {
+			return err
+		}
+		if err := config.BindPFlag(optionJournalDir, cmd.Flags().Lookup("journalDir")); err != nil {
+			return err
+		}
+		if err := config.BindPFlag(optionJournalReplay, cmd.Flags().Lookup("journalReplayInterval")); err != nil {
+			return err
+		}
 		return config.BindPFlag(optionBeePostageBatchId, cmd.Flags().Lookup("postageBlockId"))
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -101,20 +176,41 @@ can consume it.`,
 		postageBlockId = config.GetString(optionBeePostageBatchId)
 		corsOrigins = config.GetStringSlice(optionCORSAllowedOrigins)
 		verbosity = config.GetString(optionVerbosity)
+		blockstoreType = strings.ToLower(config.GetString(optionBlockstore))
+		localStoreDir = config.GetString(optionLocalStoreDir)
+		s3Options.Endpoint = config.GetString(optionS3Endpoint)
+		s3Options.Bucket = config.GetString(optionS3Bucket)
+		s3Options.Region = config.GetString(optionS3Region)
+		s3Options.AccessKey = config.GetString(optionS3AccessKey)
+		s3Options.SecretKey = config.GetString(optionS3SecretKey)
+		s3Options.Prefix = config.GetString(optionS3Prefix)
+		beeApis = config.GetStringSlice(optionBeeApis)
+		beeProbeInterval = config.GetDuration(optionBeeProbeInterval)
+		postageBatchIds = config.GetStringSlice(optionBeePostageBatchIds)
+		beeDebugApi = config.GetString(optionBeeDebugApi)
+		beePostage.WarnUtilization = config.GetFloat64(optionPostageWarnUsage)
+		beePostage.MaxUtilization = config.GetFloat64(optionPostageMaxUsage)
+		beePostage.WarnTTL = config.GetDuration(optionPostageWarnTTL)
+		beeRetry.MaxRetries = config.GetInt(optionBeeMaxRetries)
+		beeRetry.InitialBackoff = config.GetDuration(optionBeeRetryBackoff)
+		beeRetry.BreakerThreshold = config.GetInt(optionBeeBreakerThreshold)
+		beeRetry.BreakerCooldown = config.GetDuration(optionBeeBreakerCooldown)
+		cacheDir = config.GetString(optionCacheDir)
+		cacheSize = config.GetString(optionCacheSize)
+		cachePolicy = strings.ToLower(config.GetString(optionCachePolicy))
+		cacheFeedTTL = config.GetDuration(optionCacheFeedTTL)
+		journalDir = config.GetString(optionJournalDir)
+		journalReplay = config.GetDuration(optionJournalReplay)
+
+		if blockstoreType != blockstoreBee && blockstoreType != blockstoreLocal && blockstoreType != blockstoreS3 {
+			fmt.Println("\nunknown blockstore")
+			return fmt.Errorf("unknown blockstore")
+		}
 
-		if postageBlockId == "" {
-			_ = cmd.Help()
-			fmt.Println("\npostageBlockId is required to run server")
-			return fmt.Errorf("postageBlockId is required to run server")
-		} else if postageBlockId != zeroBatchId && postageBlockId != "0" {
-			if len(postageBlockId) != 64 {
-				fmt.Println("\npostageBlockId is invalid")
-				return fmt.Errorf("postageBlockId is invalid")
-			}
-			_, err := hex.DecodeString(postageBlockId)
-			if err != nil {
-				fmt.Println("\npostageBlockId is invalid")
-				return fmt.Errorf("postageBlockId is invalid")
+		// postage batches are only needed to store data in bee
+		if blockstoreType == blockstoreBee {
+			if err := validatePostageBlockId(cmd); err != nil {
+				return err
 			}
 		}
 		ensConfig := &contracts.Config{}
@@ -199,11 +295,52 @@ can consume it.`,
 		logger.Info("cookieDomain   : ", cookieDomain)
 		logger.Info("postageBlockId : ", postageBlockId)
 		logger.Info("corsOrigins    : ", corsOrigins)
+		logger.Info("blockstore     : ", blockstoreType)
+		if blockstoreType == blockstoreBee {
+			if len(beeApis) > 0 {
+				logger.Info("beeApis        : ", beeApis)
+				logger.Info("beeProbe       : ", beeProbeInterval)
+			}
+			if len(postageBatchIds) > 0 {
+				logger.Info("postageBatches : ", postageBatchIds)
+			}
+			if beeDebugApi != "" {
+				logger.Info("beeDebugApi    : ", beeDebugApi)
+			}
+			logger.Info("postageWarn    : ", beePostage.WarnUtilization, " utilization, ", beePostage.WarnTTL, " ttl")
+			logger.Info("beeMaxRetries  : ", beeRetry.MaxRetries)
+			logger.Info("beeBreaker     : ", beeRetry.BreakerThreshold, " failures, ", beeRetry.BreakerCooldown, " cooldown")
+		}
+		if blockstoreType == blockstoreLocal {
+			logger.Info("localStoreDir  : ", localStoreDir)
+		}
+		if blockstoreType == blockstoreS3 {
+			logger.Info("s3Endpoint     : ", s3Options.Endpoint)
+			logger.Info("s3Bucket       : ", s3Options.Bucket)
+			if s3Options.Prefix != "" {
+				logger.Info("s3Prefix       : ", s3Options.Prefix)
+			}
+		}
+		if cacheDir != "" {
+			logger.Info("cacheDir       : ", cacheDir)
+			logger.Info("cacheSize      : ", cacheSize)
+			logger.Info("cachePolicy    : ", cachePolicy)
+			logger.Info("cacheFeedTTL   : ", cacheFeedTTL)
+		}
+		if journalDir != "" {
+			logger.Info("journalDir     : ", journalDir)
+			logger.Info("journalReplay  : ", journalReplay)
+		}
 
 		ctx, cancel := context.WithCancel(cmd.Context())
 		defer cancel()
+		client, err := newBlockstoreClient(logger)
+		if err != nil {
+			logger.Error(err.Error())
+			return err
+		}
 		// datadir will be removed in some future version. it is kept for migration purpose only
-		hdlr, err := api.New(ctx, beeApi, cookieDomain, postageBlockId, corsOrigins, ensConfig, logger)
+		hdlr, err := api.New(ctx, client, cookieDomain, corsOrigins, ensConfig, logger)
 		if err != nil {
 			logger.Error(err.Error())
 			return err
@@ -245,9 +382,72 @@ func init() {
 	serverCmd.Flags().StringSlice("cors-origins", defaultCORSAllowedOrigins, "allow CORS headers for the given origins")
 	serverCmd.Flags().String("network", "", "network to use for authentication (mainnet/testnet/play)")
 	serverCmd.Flags().String("rpc", "", "rpc endpoint for ens network. xDai for mainnet | Goerli for testnet | local fdp-play rpc endpoint for play")
+	serverCmd.Flags().String("blockstore", defaultBlockstore, "blockstore to keep the data in (bee/local/s3)")
+	serverCmd.Flags().String("localStoreDir", defaultLocalStoreDir(), "directory to keep the data in when using the local blockstore")
+	serverCmd.Flags().String("s3Endpoint", "", "url of the S3 compatible service when using the s3 blockstore")
+	serverCmd.Flags().String("s3Bucket", "", "bucket to keep the data in when using the s3 blockstore")
+	serverCmd.Flags().String("s3Region", defaultS3Region, "region of the s3 bucket")
+	serverCmd.Flags().String("s3AccessKey", "", "access key of the s3 bucket, AWS_ACCESS_KEY_ID if empty")
+	serverCmd.Flags().String("s3SecretKey", "", "secret key of the s3 bucket, AWS_SECRET_ACCESS_KEY if empty")
+	serverCmd.Flags().String("s3Prefix", "", "prefix of the object keys in the s3 bucket")
+	serverCmd.Flags().StringSlice("beeApis", []string{}, "more bee nodes to balance the requests over together with beeApi, as url|debugUrl|batch1;batch2 with the postage batches of each node")
+	serverCmd.Flags().Duration("beeProbeInterval", defaultBeeProbeInterval, "how often a bee node that is down is checked again when using beeApis")
+	serverCmd.Flags().StringSlice("postageBatchIds", []string{}, "more postage batches to switch to when the active one is full")
+	serverCmd.Flags().String("beeDebugApi", "", "bee debug api endpoint that reports the postage batches of beeApi, beeApi if empty")
+	serverCmd.Flags().Float64("postageWarnUtilization", defaultPostageWarnUsage, "postage batch utilization, from 0 to 1, that logs a warning")
+	serverCmd.Flags().Float64("postageMaxUtilization", defaultPostageMaxUsage, "postage batch utilization, from 0 to 1, that switches to the next batch")
+	serverCmd.Flags().Duration("postageWarnTTL", defaultPostageWarnTTL, "log a warning when a postage batch expires sooner")
+	serverCmd.Flags().Int("beeMaxRetries", defaultBeeMaxRetries, "number of retries of a failed bee request")
+	serverCmd.Flags().Duration("beeRetryBackoff", defaultBeeRetryBackoff, "wait before the first retry of a failed bee request, doubled on every retry")
+	serverCmd.Flags().Int("beeBreakerThreshold", defaultBeeBreakerThreshold, "consecutive bee failures after which requests fail fast, 0 to disable")
+	serverCmd.Flags().Duration("beeBreakerCooldown", defaultBeeBreakerCooldown, "how long requests fail fast before bee is tried again")
+	// the cache encrypts the blobs it keeps with keys derived from their references, the
+	// plaintext of encrypted file blocks and inodes is not written to the disk
+	serverCmd.Flags().String("cacheDir", "", "directory for the on-disk read cache of the blockstore, cache is disabled if empty")
+	serverCmd.Flags().String("cacheSize", defaultCacheSize, "maximum size of the on-disk read cache")
+	serverCmd.Flags().String("cachePolicy", defaultCachePolicy, "eviction policy of the on-disk read cache (lru/lfu/fifo)")
+	serverCmd.Flags().Duration("cacheFeedTTL", defaultCacheFeedTTL, "how long feed updates are served from the on-disk read cache")
+	serverCmd.Flags().String("journalDir", "", "directory of the write-ahead journal that keeps writes while the blockstore is unreachable, journal is disabled if empty")
+	serverCmd.Flags().Duration("journalReplayInterval", defaultJournalReplay, "how often the blockstore is checked to replay the journaled writes")
 	rootCmd.AddCommand(serverCmd)
 }
 
+func validatePostageBlockId(cmd *cobra.Command) error {
+	if postageBlockId == "" {
+		_ = cmd.Help()
+		fmt.Println("\npostageBlockId is required to run server")
+		return fmt.Errorf("postageBlockId is required to run server")
+	} else if postageBlockId != zeroBatchId && postageBlockId != "0" {
+		if !isBatchId(postageBlockId) {
+			fmt.Println("\npostageBlockId is invalid")
+			return fmt.Errorf("postageBlockId is invalid")
+		}
+	}
+	for _, id := range postageBatchIds {
+		if !isBatchId(id) {
+			fmt.Println("\npostageBatchIds is invalid")
+			return fmt.Errorf("postageBatchIds is invalid")
+		}
+	}
+	if _, err := beeEndpoints(); err != nil {
+		fmt.Println("\n" + err.Error())
+		return err
+	}
+	if beePostage.MaxUtilization <= 0 || beePostage.MaxUtilization > 1 || beePostage.WarnUtilization < 0 {
+		fmt.Println("\npostage utilization should be between 0 and 1")
+		return fmt.Errorf("postage utilization should be between 0 and 1")
+	}
+	return nil
+}
+
+func isBatchId(id string) bool {
+	if len(id) != 64 {
+		return false
+	}
+	_, err := hex.DecodeString(id)
+	return err == nil
+}
+
 func startHttpService(logger logging.Logger) *http.Server {
 	router := mux.NewRouter()
 
@@ -266,6 +466,7 @@ func startHttpService(logger logging.Logger) *http.Server {
 			return
 		}
 	})
+	router.HandleFunc("/health", handler.HealthHandler).Methods("GET")
 	if swag {
 		router.PathPrefix("/swagger/").Handler(httpSwagger.Handler(
 			httpSwagger.URL("./swagger/doc.json"),
@@ -313,6 +514,7 @@ func startHttpService(logger logging.Logger) *http.Server {
 	baseRouter.HandleFunc("/user/login", handler.UserLoginHandler).Methods("POST")
 	baseRouter.HandleFunc("/user/present", handler.UserPresentHandler).Methods("GET")
 	baseRouter.HandleFunc("/user/isloggedin", handler.IsUserLoggedInHandler).Methods("GET")
+	baseRouter.HandleFunc("/postage", handler.PostageHandler).Methods("GET")
 
 	// user account related handlers which require login middleware
 	userRouter := baseRouter.PathPrefix("/user/").Subrouter()
@@ -331,6 +533,9 @@ func startHttpService(logger logging.Logger) *http.Server {
 	podRouter.HandleFunc("/open-async", handler.PodOpenAsyncHandler).Methods("POST")
 	podRouter.HandleFunc("/close", handler.PodCloseHandler).Methods("POST")
 	podRouter.HandleFunc("/sync", handler.PodSyncHandler).Methods("POST")
+	podRouter.HandleFunc("/gc", handler.PodGCHandler).Methods("POST")
+	podRouter.HandleFunc("/versioning", handler.PodVersioningHandler).Methods("POST")
+	podRouter.HandleFunc("/versioning", handler.PodVersioningGetHandler).Methods("GET")
 	podRouter.HandleFunc("/sync-async", handler.PodSyncAsyncHandler).Methods("POST")
 	podRouter.HandleFunc("/share", handler.PodShareHandler).Methods("POST")
 	podRouter.HandleFunc("/delete", handler.PodDeleteHandler).Methods("DELETE")
@@ -348,7 +553,15 @@ func startHttpService(logger logging.Logger) *http.Server {
 	dirRouter.HandleFunc("/rmdir", handler.DirectoryRmdirHandler).Methods("DELETE")
 	dirRouter.HandleFunc("/ls", handler.DirectoryLsHandler).Methods("GET")
 	dirRouter.HandleFunc("/stat", handler.DirectoryStatHandler).Methods("GET")
+	dirRouter.HandleFunc("/history", handler.DirectoryHistoryHandler).Methods("GET")
 	dirRouter.HandleFunc("/chmod", handler.DirectoryModeHandler).Methods("POST")
+	dirRouter.HandleFunc("/xattr", handler.DirectoryXattrHandler).Methods("GET")
+	dirRouter.HandleFunc("/xattr", handler.DirectoryXattrSetHandler).Methods("POST")
+	dirRouter.HandleFunc("/xattr", handler.DirectoryXattrRemoveHandler).Methods("DELETE")
+	dirRouter.HandleFunc("/symlink", handler.DirectorySymlinkHandler).Methods("POST")
+	dirRouter.HandleFunc("/symlink", handler.DirectorySymlinkStatHandler).Methods("GET")
+	dirRouter.HandleFunc("/symlink", handler.DirectorySymlinkRemoveHandler).Methods("DELETE")
+	dirRouter.HandleFunc("/copy", handler.DirectoryCopyHandler).Methods("POST")
 	dirRouter.HandleFunc("/present", handler.DirectoryPresentHandler).Methods("GET")
 	dirRouter.HandleFunc("/rename", handler.DirectoryRenameHandler).Methods("POST")
 
@@ -360,12 +573,27 @@ func startHttpService(logger logging.Logger) *http.Server {
 	fileRouter.HandleFunc("/download", handler.FileDownloadHandlerPost).Methods("POST")
 	fileRouter.HandleFunc("/update", handler.FileUpdateHandler).Methods("POST")
 	fileRouter.HandleFunc("/upload", handler.FileUploadHandler).Methods("POST")
+	fileRouter.HandleFunc("/upload/session", handler.FileUploadSessionHandler).Methods("POST")
+	fileRouter.HandleFunc("/upload/session", handler.FileUploadSessionStatusHandler).Methods("GET")
+	fileRouter.HandleFunc("/upload/session", handler.FileUploadSessionAbortHandler).Methods("DELETE")
+	fileRouter.HandleFunc("/upload/part", handler.FileUploadPartHandler).Methods("PUT")
+	fileRouter.HandleFunc("/upload/finish", handler.FileUploadSessionFinishHandler).Methods("POST")
 	fileRouter.HandleFunc("/share", handler.FileShareHandler).Methods("POST")
 	fileRouter.HandleFunc("/receive", handler.FileReceiveHandler).Methods("GET")
 	fileRouter.HandleFunc("/receiveinfo", handler.FileReceiveInfoHandler).Methods("GET")
 	fileRouter.HandleFunc("/delete", handler.FileDeleteHandler).Methods("DELETE")
 	fileRouter.HandleFunc("/stat", handler.FileStatHandler).Methods("GET")
+	fileRouter.HandleFunc("/history", handler.FileHistoryHandler).Methods("GET")
+	fileRouter.HandleFunc("/verify", handler.FileVerifyHandler).Methods("GET")
+	fileRouter.HandleFunc("/versions", handler.FileVersionsHandler).Methods("GET")
+	fileRouter.HandleFunc("/version/download", handler.FileVersionDownloadHandler).Methods("GET")
+	fileRouter.HandleFunc("/version/diff", handler.FileVersionDiffHandler).Methods("GET")
+	fileRouter.HandleFunc("/version/restore", handler.FileVersionRestoreHandler).Methods("POST")
 	fileRouter.HandleFunc("/chmod", handler.FileModeHandler).Methods("POST")
+	fileRouter.HandleFunc("/xattr", handler.FileXattrHandler).Methods("GET")
+	fileRouter.HandleFunc("/xattr", handler.FileXattrSetHandler).Methods("POST")
+	fileRouter.HandleFunc("/xattr", handler.FileXattrRemoveHandler).Methods("DELETE")
+	fileRouter.HandleFunc("/copy", handler.FileCopyHandler).Methods("POST")
 	fileRouter.HandleFunc("/rename", handler.FileRenameHandler).Methods("POST")
 
 	kvRouter := baseRouter.PathPrefix("/kv/").Subrouter()
@@ -409,8 +637,9 @@ func startHttpService(logger logging.Logger) *http.Server {
 	c := cors.New(cors.Options{
 		AllowedOrigins:   origins,
 		AllowCredentials: true,
-		AllowedHeaders:   []string{"Origin", "Accept", "Authorization", "Content-Type", "X-Requested-With", "Access-Control-Request-Headers", "Access-Control-Request-Method"},
-		AllowedMethods:   []string{"GET", "POST", "DELETE"},
+		AllowedHeaders:   []string{"Origin", "Accept", "Authorization", "Content-Type", "X-Requested-With", "Access-Control-Request-Headers", "Access-Control-Request-Method", "Range", "If-Range", "If-None-Match"},
+		ExposedHeaders:   []string{"Accept-Ranges", "Content-Range", "ETag", "X-Fairos-Checksum", "X-Fairos-Block-Tree-Checksum"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE"},
 		MaxAge:           3600,
 	})
 
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -14,6 +16,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -21,7 +24,9 @@ import (
 	"github.com/fairdatasociety/fairOS-dfs/pkg/api"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/bee/mock"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/dfs"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/dir"
 	mock2 "github.com/fairdatasociety/fairOS-dfs/pkg/ensm/eth/mock"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/file"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/user"
 	"github.com/gorilla/websocket"
@@ -1071,6 +1076,826 @@ func TestApis(t *testing.T) {
 		}
 	})
 
+	t.Run("signup-login-pod-file-download-range", func(t *testing.T) {
+		c := http.Client{Timeout: time.Duration(1) * time.Minute}
+		userRequest := &common.UserSignupRequest{
+			UserName: randStringRunes(16),
+			Password: randStringRunes(12),
+		}
+		userBytes, err := json.Marshal(userRequest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signupResp, err := c.Post(fmt.Sprintf("%s%s", basev2, string(common.UserSignup)), "application/json", bytes.NewBuffer(userBytes))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = signupResp.Body.Close()
+		if signupResp.StatusCode != http.StatusCreated {
+			t.Fatal("Signup failed", signupResp.StatusCode)
+		}
+		loginResp, err := c.Post(fmt.Sprintf("%s%s", basev2, string(common.UserLogin)), "application/json", bytes.NewBuffer(userBytes))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = loginResp.Body.Close()
+		if loginResp.StatusCode != http.StatusOK {
+			t.Fatal("user should be able to login")
+		}
+		cookie := loginResp.Header["Set-Cookie"]
+
+		podRequest := &common.PodRequest{
+			PodName:  randStringRunes(16),
+			Password: userRequest.Password,
+		}
+		podBytes, err := json.Marshal(podRequest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		podNewHttpReq, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s%s", basev1, string(common.PodNew)), bytes.NewBuffer(podBytes))
+		if err != nil {
+			t.Fatal(err)
+		}
+		podNewHttpReq.Header.Set("Cookie", cookie[0])
+		podNewHttpReq.Header.Add("Content-Type", "application/json")
+		podNewResp, err := c.Do(podNewHttpReq)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = podNewResp.Body.Close()
+		if podNewResp.StatusCode != 201 {
+			t.Fatal("pod creation failed")
+		}
+
+		// the ranges span the 4kb blocks of the file
+		content := make([]byte, 10000)
+		_, err = rand.Read(content)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body := new(bytes.Buffer)
+		writer := multipart.NewWriter(body)
+		for k, v := range map[string]string{
+			"podName":       podRequest.PodName,
+			"contentLength": strconv.Itoa(len(content)),
+			"dirPath":       "/",
+			"blockSize":     "4kb",
+		} {
+			err = writer.WriteField(k, v)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		part, err := writer.CreateFormFile("files", "file1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = part.Write(content)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = writer.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		uploadReq, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s%s", basev1, string(common.FileUpload)), body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		uploadReq.Header.Set("Cookie", cookie[0])
+		uploadReq.Header.Add("Content-Type", writer.FormDataContentType())
+		uploadResp, err := c.Do(uploadReq)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = uploadResp.Body.Close()
+		if uploadResp.StatusCode != 200 {
+			t.Fatal("upload failed")
+		}
+
+		download := func(headers map[string]string) (*http.Response, []byte) {
+			t.Helper()
+			req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s%s?podName=%s&filePath=/file1", basev1, string(common.FileDownload), podRequest.PodName), http.NoBody)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Cookie", cookie[0])
+			for k, v := range headers {
+				req.Header.Set(k, v)
+			}
+			resp, err := c.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			data, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return resp, data
+		}
+
+		resp, data := download(nil)
+		etag := resp.Header.Get("ETag")
+		if resp.StatusCode != http.StatusOK || !bytes.Equal(data, content) || etag == "" {
+			t.Fatalf("full download failed with %d, etag %q", resp.StatusCode, etag)
+		}
+		if resp.Header.Get("Accept-Ranges") != "bytes" {
+			t.Fatal("ranges should be accepted")
+		}
+
+		resp, data = download(map[string]string{"Range": "bytes=4000-8299"})
+		if resp.StatusCode != http.StatusPartialContent || !bytes.Equal(data, content[4000:8300]) {
+			t.Fatalf("range download failed with %d", resp.StatusCode)
+		}
+		if resp.Header.Get("Content-Range") != "bytes 4000-8299/10000" {
+			t.Fatalf("unexpected content range %q", resp.Header.Get("Content-Range"))
+		}
+
+		resp, data = download(map[string]string{"Range": "bytes=-100"})
+		if resp.StatusCode != http.StatusPartialContent || !bytes.Equal(data, content[9900:]) {
+			t.Fatalf("suffix range download failed with %d", resp.StatusCode)
+		}
+
+		resp, _ = download(map[string]string{"Range": "bytes=0-9,5000-5009"})
+		if resp.StatusCode != http.StatusPartialContent || !strings.HasPrefix(resp.Header.Get("Content-Type"), "multipart/byteranges") {
+			t.Fatalf("multi range download failed with %d", resp.StatusCode)
+		}
+
+		resp, _ = download(map[string]string{"Range": "bytes=20000-"})
+		if resp.StatusCode != http.StatusRequestedRangeNotSatisfiable {
+			t.Fatalf("expected unsatisfiable range, got %d", resp.StatusCode)
+		}
+
+		resp, data = download(map[string]string{"If-None-Match": etag})
+		if resp.StatusCode != http.StatusNotModified || len(data) != 0 {
+			t.Fatalf("expected not modified, got %d", resp.StatusCode)
+		}
+
+		// a stale If-Range gets the whole file
+		resp, data = download(map[string]string{"Range": "bytes=0-9", "If-Range": "\"stale\""})
+		if resp.StatusCode != http.StatusOK || !bytes.Equal(data, content) {
+			t.Fatalf("stale if-range should download the file, got %d", resp.StatusCode)
+		}
+		resp, data = download(map[string]string{"Range": "bytes=0-9", "If-Range": etag})
+		if resp.StatusCode != http.StatusPartialContent || !bytes.Equal(data, content[:10]) {
+			t.Fatalf("matching if-range should download the range, got %d", resp.StatusCode)
+		}
+
+		verifyReq, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/file/verify?podName=%s&filePath=/file1", basev1, podRequest.PodName), http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+		verifyReq.Header.Set("Cookie", cookie[0])
+		verifyResp, err := c.Do(verifyReq)
+		if err != nil {
+			t.Fatal(err)
+		}
+		verifyBody, err := io.ReadAll(verifyResp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = verifyResp.Body.Close()
+		if verifyResp.StatusCode != http.StatusOK {
+			t.Fatalf("verify failed with %d", verifyResp.StatusCode)
+		}
+		verification := &file.Verification{}
+		err = json.Unmarshal(verifyBody, verification)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !verification.Valid || verification.BlockTreeChecksum == "" || verification.BlockTreeChecksum != resp.Header.Get("X-Fairos-Block-Tree-Checksum") {
+			t.Fatalf("unexpected verification %+v", verification)
+		}
+
+		// the checksum header is the one sha256sum prints for the downloaded content
+		resp, data = download(nil)
+		sum := sha256.Sum256(data)
+		if resp.StatusCode != http.StatusOK || !bytes.Equal(data, content) || resp.Header.Get("X-Fairos-Checksum") != hex.EncodeToString(sum[:]) {
+			t.Fatalf("unexpected checksum header %q", resp.Header.Get("X-Fairos-Checksum"))
+		}
+		if verification.Checksum != hex.EncodeToString(sum[:]) {
+			t.Fatalf("unexpected verification %+v", verification)
+		}
+	})
+
+	t.Run("signup-login-pod-file-xattr", func(t *testing.T) {
+		c := http.Client{Timeout: time.Duration(1) * time.Minute}
+		userRequest := &common.UserSignupRequest{
+			UserName: randStringRunes(16),
+			Password: randStringRunes(12),
+		}
+		userBytes, err := json.Marshal(userRequest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signupResp, err := c.Post(fmt.Sprintf("%s%s", basev2, string(common.UserSignup)), "application/json", bytes.NewBuffer(userBytes))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = signupResp.Body.Close()
+		if signupResp.StatusCode != http.StatusCreated {
+			t.Fatal("Signup failed", signupResp.StatusCode)
+		}
+		loginResp, err := c.Post(fmt.Sprintf("%s%s", basev2, string(common.UserLogin)), "application/json", bytes.NewBuffer(userBytes))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = loginResp.Body.Close()
+		if loginResp.StatusCode != http.StatusOK {
+			t.Fatal("user should be able to login")
+		}
+		cookie := loginResp.Header["Set-Cookie"]
+
+		podRequest := &common.PodRequest{
+			PodName:  randStringRunes(16),
+			Password: userRequest.Password,
+		}
+		podBytes, err := json.Marshal(podRequest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		podNewHttpReq, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s%s", basev1, string(common.PodNew)), bytes.NewBuffer(podBytes))
+		if err != nil {
+			t.Fatal(err)
+		}
+		podNewHttpReq.Header.Set("Cookie", cookie[0])
+		podNewHttpReq.Header.Add("Content-Type", "application/json")
+		podNewResp, err := c.Do(podNewHttpReq)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = podNewResp.Body.Close()
+		if podNewResp.StatusCode != 201 {
+			t.Fatal("pod creation failed")
+		}
+
+		body := new(bytes.Buffer)
+		writer := multipart.NewWriter(body)
+		for k, v := range map[string]string{
+			"podName":       podRequest.PodName,
+			"contentLength": "5",
+			"dirPath":       "/",
+			"blockSize":     "1kb",
+		} {
+			err = writer.WriteField(k, v)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		part, err := writer.CreateFormFile("files", "file1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = part.Write([]byte("hello"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = writer.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		uploadReq, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s%s", basev1, string(common.FileUpload)), body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		uploadReq.Header.Set("Cookie", cookie[0])
+		uploadReq.Header.Add("Content-Type", writer.FormDataContentType())
+		uploadResp, err := c.Do(uploadReq)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = uploadResp.Body.Close()
+		if uploadResp.StatusCode != 200 {
+			t.Fatal("upload failed")
+		}
+
+		do := func(method, urlPath string, req interface{}) (int, []byte) {
+			t.Helper()
+			var reqBody io.Reader = http.NoBody
+			if req != nil {
+				data, err := json.Marshal(req)
+				if err != nil {
+					t.Fatal(err)
+				}
+				reqBody = bytes.NewBuffer(data)
+			}
+			httpReq, err := http.NewRequest(method, basev1+urlPath, reqBody)
+			if err != nil {
+				t.Fatal(err)
+			}
+			httpReq.Header.Set("Cookie", cookie[0])
+			httpReq.Header.Add("Content-Type", "application/json")
+			resp, err := c.Do(httpReq)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			data, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return resp.StatusCode, data
+		}
+
+		xattrReq := &api.XattrRequest{
+			PodName:  podRequest.PodName,
+			FilePath: "/file1",
+			Name:     "user.tag",
+			Value:    "red",
+		}
+		code, _ := do(http.MethodPost, string(common.FileXattr), xattrReq)
+		if code != http.StatusOK {
+			t.Fatalf("file xattr set failed with %d", code)
+		}
+		code, _ = do(http.MethodPost, string(common.DirXattr), &api.XattrRequest{PodName: podRequest.PodName, DirPath: "/", Name: "user.tag", Value: "blue"})
+		if code != http.StatusOK {
+			t.Fatalf("dir xattr set failed with %d", code)
+		}
+
+		code, data := do(http.MethodGet, fmt.Sprintf("%s?podName=%s&filePath=/file1&name=user.tag", common.FileXattr, podRequest.PodName), nil)
+		xattr := &api.XattrResponse{}
+		err = json.Unmarshal(data, xattr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code != http.StatusOK || xattr.Value != "red" {
+			t.Fatalf("file xattr get failed with %d: %s", code, data)
+		}
+
+		// the extended attributes are only listed when asked for
+		stat := &file.Stats{}
+		code, data = do(http.MethodGet, fmt.Sprintf("%s?podName=%s&filePath=/file1", common.FileStat, podRequest.PodName), nil)
+		err = json.Unmarshal(data, stat)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code != http.StatusOK || stat.Xattrs != nil {
+			t.Fatalf("unexpected stat %d: %s", code, data)
+		}
+		code, data = do(http.MethodGet, fmt.Sprintf("%s?podName=%s&filePath=/file1&xattrs=true", common.FileStat, podRequest.PodName), nil)
+		err = json.Unmarshal(data, stat)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code != http.StatusOK || stat.Xattrs["user.tag"] != "red" {
+			t.Fatalf("unexpected stat %d: %s", code, data)
+		}
+		ls := &api.ListFileResponse{}
+		code, data = do(http.MethodGet, fmt.Sprintf("%s?podName=%s&dirPath=/&xattrs=true", common.DirLs, podRequest.PodName), nil)
+		err = json.Unmarshal(data, ls)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code != http.StatusOK || len(ls.Files) != 1 || ls.Files[0].Xattrs["user.tag"] != "red" {
+			t.Fatalf("unexpected listing %d: %s", code, data)
+		}
+		xattrs := &api.XattrsResponse{}
+		code, data = do(http.MethodGet, fmt.Sprintf("%s?podName=%s&dirPath=/", common.DirXattr, podRequest.PodName), nil)
+		err = json.Unmarshal(data, xattrs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code != http.StatusOK || len(xattrs.Xattrs) != 1 || xattrs.Xattrs["user.tag"] != "blue" {
+			t.Fatalf("dir xattr list failed with %d: %s", code, data)
+		}
+
+		code, _ = do(http.MethodDelete, string(common.FileXattr), xattrReq)
+		if code != http.StatusOK {
+			t.Fatalf("file xattr remove failed with %d", code)
+		}
+		code, _ = do(http.MethodGet, fmt.Sprintf("%s?podName=%s&filePath=/file1&name=user.tag", common.FileXattr, podRequest.PodName), nil)
+		if code != http.StatusNotFound {
+			t.Fatalf("expected removed xattr to be not found, got %d", code)
+		}
+		code, _ = do(http.MethodDelete, string(common.FileXattr), xattrReq)
+		if code != http.StatusNotFound {
+			t.Fatalf("expected removing a missing xattr to be not found, got %d", code)
+		}
+	})
+
+	t.Run("signup-login-pod-dir-symlink", func(t *testing.T) {
+		c := http.Client{Timeout: time.Duration(1) * time.Minute}
+		userRequest := &common.UserSignupRequest{
+			UserName: randStringRunes(16),
+			Password: randStringRunes(12),
+		}
+		userBytes, err := json.Marshal(userRequest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signupResp, err := c.Post(fmt.Sprintf("%s%s", basev2, string(common.UserSignup)), "application/json", bytes.NewBuffer(userBytes))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = signupResp.Body.Close()
+		if signupResp.StatusCode != http.StatusCreated {
+			t.Fatal("Signup failed", signupResp.StatusCode)
+		}
+		loginResp, err := c.Post(fmt.Sprintf("%s%s", basev2, string(common.UserLogin)), "application/json", bytes.NewBuffer(userBytes))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = loginResp.Body.Close()
+		if loginResp.StatusCode != http.StatusOK {
+			t.Fatal("user should be able to login")
+		}
+		cookie := loginResp.Header["Set-Cookie"]
+
+		podRequest := &common.PodRequest{
+			PodName:  randStringRunes(16),
+			Password: userRequest.Password,
+		}
+		podBytes, err := json.Marshal(podRequest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		podNewHttpReq, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s%s", basev1, string(common.PodNew)), bytes.NewBuffer(podBytes))
+		if err != nil {
+			t.Fatal(err)
+		}
+		podNewHttpReq.Header.Set("Cookie", cookie[0])
+		podNewHttpReq.Header.Add("Content-Type", "application/json")
+		podNewResp, err := c.Do(podNewHttpReq)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = podNewResp.Body.Close()
+		if podNewResp.StatusCode != 201 {
+			t.Fatal("pod creation failed")
+		}
+
+		mkDirBytes, err := json.Marshal(&common.FileSystemRequest{PodName: podRequest.PodName, DirectoryPath: "/v2"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		mkDirHttpReq, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s%s", basev1, string(common.DirMkdir)), bytes.NewBuffer(mkDirBytes))
+		if err != nil {
+			t.Fatal(err)
+		}
+		mkDirHttpReq.Header.Set("Cookie", cookie[0])
+		mkDirHttpReq.Header.Add("Content-Type", "application/json")
+		mkDirResp, err := c.Do(mkDirHttpReq)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = mkDirResp.Body.Close()
+		if mkDirResp.StatusCode != 201 {
+			t.Fatal("mkdir failed")
+		}
+
+		body := new(bytes.Buffer)
+		writer := multipart.NewWriter(body)
+		for k, v := range map[string]string{
+			"podName":       podRequest.PodName,
+			"contentLength": "5",
+			"dirPath":       "/v2",
+			"blockSize":     "1kb",
+		} {
+			err = writer.WriteField(k, v)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		part, err := writer.CreateFormFile("files", "file1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = part.Write([]byte("hello"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = writer.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		uploadReq, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s%s", basev1, string(common.FileUpload)), body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		uploadReq.Header.Set("Cookie", cookie[0])
+		uploadReq.Header.Add("Content-Type", writer.FormDataContentType())
+		uploadResp, err := c.Do(uploadReq)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = uploadResp.Body.Close()
+		if uploadResp.StatusCode != 200 {
+			t.Fatal("upload failed")
+		}
+
+		do := func(method, urlPath string, req interface{}) (int, []byte) {
+			t.Helper()
+			var reqBody io.Reader = http.NoBody
+			if req != nil {
+				data, err := json.Marshal(req)
+				if err != nil {
+					t.Fatal(err)
+				}
+				reqBody = bytes.NewBuffer(data)
+			}
+			httpReq, err := http.NewRequest(method, basev1+urlPath, reqBody)
+			if err != nil {
+				t.Fatal(err)
+			}
+			httpReq.Header.Set("Cookie", cookie[0])
+			httpReq.Header.Add("Content-Type", "application/json")
+			resp, err := c.Do(httpReq)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			data, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return resp.StatusCode, data
+		}
+
+		code, _ := do(http.MethodPost, string(common.DirSymlink), &api.SymlinkRequest{PodName: podRequest.PodName, LinkPath: "/latest", Target: "v2"})
+		if code != http.StatusCreated {
+			t.Fatalf("symlink failed with %d", code)
+		}
+		code, _ = do(http.MethodPost, string(common.DirSymlink), &api.SymlinkRequest{PodName: podRequest.PodName, LinkPath: "/latest", Target: "v1"})
+		if code != http.StatusBadRequest {
+			t.Fatalf("expected an existing link to be a bad request, got %d", code)
+		}
+
+		// the paths through the link are the paths of the target
+		ls := &api.ListFileResponse{}
+		code, data := do(http.MethodGet, fmt.Sprintf("%s?podName=%s&dirPath=/latest", common.DirLs, podRequest.PodName), nil)
+		err = json.Unmarshal(data, ls)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code != http.StatusOK || len(ls.Files) != 1 || ls.Files[0].Name != "file1" {
+			t.Fatalf("unexpected listing %d: %s", code, data)
+		}
+		stat := &file.Stats{}
+		code, data = do(http.MethodGet, fmt.Sprintf("%s?podName=%s&filePath=/latest/file1", common.FileStat, podRequest.PodName), nil)
+		err = json.Unmarshal(data, stat)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code != http.StatusOK || stat.FilePath != "/v2" || stat.FileSize != "5" {
+			t.Fatalf("unexpected stat %d: %s", code, data)
+		}
+		code, data = do(http.MethodGet, fmt.Sprintf("%s?podName=%s&filePath=/latest/file1", common.FileDownload, podRequest.PodName), nil)
+		if code != http.StatusOK || string(data) != "hello" {
+			t.Fatalf("unexpected download %d: %s", code, data)
+		}
+		present := &api.DirPresentResponse{}
+		code, data = do(http.MethodGet, fmt.Sprintf("%s?podName=%s&dirPath=/latest", common.DirIsPresent, podRequest.PodName), nil)
+		err = json.Unmarshal(data, present)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code != http.StatusOK || !present.Present {
+			t.Fatalf("unexpected present %d: %s", code, data)
+		}
+
+		// the link itself is listed and can be looked at
+		code, data = do(http.MethodGet, fmt.Sprintf("%s?podName=%s&dirPath=/", common.DirLs, podRequest.PodName), nil)
+		err = json.Unmarshal(data, ls)
+		if err != nil {
+			t.Fatal(err)
+		}
+		links := 0
+		for _, entry := range ls.Directories {
+			if entry.ContentType == dir.MineTypeSymlink && entry.Name == "latest" && entry.Target == "v2" {
+				links++
+			}
+		}
+		if code != http.StatusOK || links != 1 {
+			t.Fatalf("unexpected listing %d: %s", code, data)
+		}
+		linkStat := &dir.LinkStats{}
+		code, data = do(http.MethodGet, fmt.Sprintf("%s?podName=%s&linkPath=/latest", common.DirSymlink, podRequest.PodName), nil)
+		err = json.Unmarshal(data, linkStat)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code != http.StatusOK || linkStat.Target != "v2" || linkStat.LinkName != "latest" {
+			t.Fatalf("unexpected link stat %d: %s", code, data)
+		}
+
+		code, _ = do(http.MethodPost, string(common.DirSymlink), &api.SymlinkRequest{PodName: podRequest.PodName, LinkPath: "/loop1", Target: "loop2"})
+		if code != http.StatusCreated {
+			t.Fatalf("symlink failed with %d", code)
+		}
+		code, _ = do(http.MethodPost, string(common.DirSymlink), &api.SymlinkRequest{PodName: podRequest.PodName, LinkPath: "/loop2", Target: "loop1"})
+		if code != http.StatusCreated {
+			t.Fatalf("symlink failed with %d", code)
+		}
+		code, data = do(http.MethodGet, fmt.Sprintf("%s?podName=%s&filePath=/loop1/file1", common.FileStat, podRequest.PodName), nil)
+		if code == http.StatusOK || !strings.Contains(string(data), dir.ErrTooManyLinks.Error()) {
+			t.Fatalf("expected a link loop, got %d: %s", code, data)
+		}
+
+		code, _ = do(http.MethodDelete, string(common.DirSymlink), &api.SymlinkRequest{PodName: podRequest.PodName, LinkPath: "/latest"})
+		if code != http.StatusOK {
+			t.Fatalf("symlink remove failed with %d", code)
+		}
+		code, _ = do(http.MethodGet, fmt.Sprintf("%s?podName=%s&linkPath=/latest", common.DirSymlink, podRequest.PodName), nil)
+		if code != http.StatusNotFound {
+			t.Fatalf("expected removed link to be not found, got %d", code)
+		}
+	})
+
+	t.Run("signup-login-pod-copy", func(t *testing.T) {
+		c := http.Client{Timeout: time.Duration(1) * time.Minute}
+		userRequest := &common.UserSignupRequest{
+			UserName: randStringRunes(16),
+			Password: randStringRunes(12),
+		}
+		userBytes, err := json.Marshal(userRequest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signupResp, err := c.Post(fmt.Sprintf("%s%s", basev2, string(common.UserSignup)), "application/json", bytes.NewBuffer(userBytes))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = signupResp.Body.Close()
+		if signupResp.StatusCode != http.StatusCreated {
+			t.Fatal("Signup failed", signupResp.StatusCode)
+		}
+		loginResp, err := c.Post(fmt.Sprintf("%s%s", basev2, string(common.UserLogin)), "application/json", bytes.NewBuffer(userBytes))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = loginResp.Body.Close()
+		if loginResp.StatusCode != http.StatusOK {
+			t.Fatal("user should be able to login")
+		}
+		cookie := loginResp.Header["Set-Cookie"]
+
+		podRequest := &common.PodRequest{
+			PodName:  randStringRunes(16),
+			Password: userRequest.Password,
+		}
+		podBytes, err := json.Marshal(podRequest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		podNewHttpReq, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s%s", basev1, string(common.PodNew)), bytes.NewBuffer(podBytes))
+		if err != nil {
+			t.Fatal(err)
+		}
+		podNewHttpReq.Header.Set("Cookie", cookie[0])
+		podNewHttpReq.Header.Add("Content-Type", "application/json")
+		podNewResp, err := c.Do(podNewHttpReq)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = podNewResp.Body.Close()
+		if podNewResp.StatusCode != 201 {
+			t.Fatal("pod creation failed")
+		}
+
+		dstPodName := randStringRunes(16)
+		podBytes, err = json.Marshal(&common.PodRequest{PodName: dstPodName, Password: userRequest.Password})
+		if err != nil {
+			t.Fatal(err)
+		}
+		podNewHttpReq, err = http.NewRequest(http.MethodPost, fmt.Sprintf("%s%s", basev1, string(common.PodNew)), bytes.NewBuffer(podBytes))
+		if err != nil {
+			t.Fatal(err)
+		}
+		podNewHttpReq.Header.Set("Cookie", cookie[0])
+		podNewHttpReq.Header.Add("Content-Type", "application/json")
+		podNewResp, err = c.Do(podNewHttpReq)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = podNewResp.Body.Close()
+		if podNewResp.StatusCode != 201 {
+			t.Fatal("pod creation failed")
+		}
+
+		mkDirBytes, err := json.Marshal(&common.FileSystemRequest{PodName: podRequest.PodName, DirectoryPath: "/v2"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		mkDirHttpReq, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s%s", basev1, string(common.DirMkdir)), bytes.NewBuffer(mkDirBytes))
+		if err != nil {
+			t.Fatal(err)
+		}
+		mkDirHttpReq.Header.Set("Cookie", cookie[0])
+		mkDirHttpReq.Header.Add("Content-Type", "application/json")
+		mkDirResp, err := c.Do(mkDirHttpReq)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = mkDirResp.Body.Close()
+		if mkDirResp.StatusCode != 201 {
+			t.Fatal("mkdir failed")
+		}
+
+		body := new(bytes.Buffer)
+		writer := multipart.NewWriter(body)
+		for k, v := range map[string]string{
+			"podName":       podRequest.PodName,
+			"contentLength": "5",
+			"dirPath":       "/v2",
+			"blockSize":     "1kb",
+		} {
+			err = writer.WriteField(k, v)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		part, err := writer.CreateFormFile("files", "file1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = part.Write([]byte("hello"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = writer.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		uploadReq, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s%s", basev1, string(common.FileUpload)), body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		uploadReq.Header.Set("Cookie", cookie[0])
+		uploadReq.Header.Add("Content-Type", writer.FormDataContentType())
+		uploadResp, err := c.Do(uploadReq)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = uploadResp.Body.Close()
+		if uploadResp.StatusCode != 200 {
+			t.Fatal("upload failed")
+		}
+
+		do := func(method, urlPath string, req interface{}) (int, []byte) {
+			t.Helper()
+			var reqBody io.Reader = http.NoBody
+			if req != nil {
+				data, err := json.Marshal(req)
+				if err != nil {
+					t.Fatal(err)
+				}
+				reqBody = bytes.NewBuffer(data)
+			}
+			httpReq, err := http.NewRequest(method, basev1+urlPath, reqBody)
+			if err != nil {
+				t.Fatal(err)
+			}
+			httpReq.Header.Set("Cookie", cookie[0])
+			httpReq.Header.Add("Content-Type", "application/json")
+			resp, err := c.Do(httpReq)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			data, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return resp.StatusCode, data
+		}
+
+		code, _ := do(http.MethodPost, string(common.FileCopy), &api.CopyRequest{PodName: podRequest.PodName, FilePath: "/v2/file1", DestinationPath: "/file1"})
+		if code != http.StatusCreated {
+			t.Fatalf("file copy failed with %d", code)
+		}
+		code, _ = do(http.MethodPost, string(common.FileCopy), &api.CopyRequest{PodName: podRequest.PodName, FilePath: "/v2/file1", DestinationPath: "/file1"})
+		if code != http.StatusBadRequest {
+			t.Fatalf("expected a copy over an existing file to be a bad request, got %d", code)
+		}
+		code, _ = do(http.MethodPost, string(common.FileCopy), &api.CopyRequest{PodName: podRequest.PodName, FilePath: "/v2/file2", DestinationPath: "/file2"})
+		if code != http.StatusNotFound {
+			t.Fatalf("expected a missing file to be not found, got %d", code)
+		}
+		code, _ = do(http.MethodPost, string(common.DirCopy), &api.CopyRequest{PodName: podRequest.PodName, DirPath: "/v2", DestinationPath: "/v2/v3"})
+		if code != http.StatusBadRequest {
+			t.Fatalf("expected a copy into itself to be a bad request, got %d", code)
+		}
+		code, _ = do(http.MethodPost, string(common.DirCopy), &api.CopyRequest{PodName: podRequest.PodName, DirPath: "/v2", DestinationPodName: dstPodName, DestinationPath: "/v2"})
+		if code != http.StatusCreated {
+			t.Fatalf("dir copy failed with %d", code)
+		}
+
+		// the copies keep their content when the original is deleted
+		code, _ = do(http.MethodDelete, string(common.FileDelete), &api.FileDeleteRequest{PodName: podRequest.PodName, FilePath: "/v2/file1"})
+		if code != http.StatusOK {
+			t.Fatalf("file delete failed with %d", code)
+		}
+		code, data := do(http.MethodGet, fmt.Sprintf("%s?podName=%s&filePath=/file1", common.FileDownload, podRequest.PodName), nil)
+		if code != http.StatusOK || string(data) != "hello" {
+			t.Fatalf("unexpected download %d: %s", code, data)
+		}
+		code, data = do(http.MethodGet, fmt.Sprintf("%s?podName=%s&filePath=/v2/file1", common.FileDownload, dstPodName), nil)
+		if code != http.StatusOK || string(data) != "hello" {
+			t.Fatalf("unexpected download %d: %s", code, data)
+		}
+	})
+
 	t.Run("ws test", func(t *testing.T) {
 		u := url.URL{Scheme: "ws", Host: base, Path: "/ws/v1/"}
 		header := http.Header{}
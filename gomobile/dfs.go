@@ -3,6 +3,7 @@ package fairos
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -67,7 +68,7 @@ func Connect(beeEndpoint, postageBlockId, network, rpc string, logLevel int) err
 }
 
 func LoginUser(username, password string) (string, error) {
-	ui, nameHash, publicKey, err := api.LoginUserV2(username, password, "")
+	ui, nameHash, publicKey, err := api.LoginUserV2(context.Background(), username, password, "")
 	if err != nil {
 		return "", err
 	}
@@ -94,7 +95,7 @@ func LogoutUser() error {
 }
 
 func DeleteUser() error {
-	return api.DeleteUserV2(savedPassword, sessionId)
+	return api.DeleteUserV2(context.Background(), savedPassword, sessionId)
 }
 
 func StatUser() (string, error) {
@@ -107,7 +108,7 @@ func StatUser() (string, error) {
 }
 
 func NewPod(podName string) (string, error) {
-	_, err := api.CreatePod(podName, sessionId)
+	_, err := api.CreatePod(context.Background(), podName, sessionId)
 	if err != nil {
 		return "", err
 	}
@@ -115,7 +116,7 @@ func NewPod(podName string) (string, error) {
 }
 
 func PodOpen(podName string) (string, error) {
-	_, err := api.OpenPod(podName, sessionId)
+	_, err := api.OpenPod(context.Background(), podName, sessionId)
 	if err != nil {
 		return "", err
 	}
@@ -123,19 +124,19 @@ func PodOpen(podName string) (string, error) {
 }
 
 func PodClose(podName string) error {
-	return api.ClosePod(podName, sessionId)
+	return api.ClosePod(context.Background(), podName, sessionId)
 }
 
 func PodDelete(podName string) error {
-	return api.DeletePod(podName, sessionId)
+	return api.DeletePod(context.Background(), podName, sessionId)
 }
 
 func PodSync(podName string) error {
-	return api.SyncPod(podName, sessionId)
+	return api.SyncPod(context.Background(), podName, sessionId)
 }
 
 func PodList() (string, error) {
-	ownPods, sharedPods, err := api.ListPods(sessionId)
+	ownPods, sharedPods, err := api.ListPods(context.Background(), sessionId)
 	if err != nil {
 		return "", err
 	}
@@ -162,11 +163,11 @@ func PodStat(podName string) (string, error) {
 }
 
 func IsPodPresent(podName string) bool {
-	return api.IsPodExist(podName, sessionId)
+	return api.IsPodExist(context.Background(), podName, sessionId)
 }
 
 func PodShare(podName string) (string, error) {
-	reference, err := api.PodShare(podName, "", sessionId)
+	reference, err := api.PodShare(context.Background(), podName, "", sessionId)
 	if err != nil {
 		return "", err
 	}
@@ -181,7 +182,7 @@ func PodReceive(podSharingReference string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	pi, err := api.PodReceive(sessionId, "", ref)
+	pi, err := api.PodReceive(context.Background(), sessionId, "", ref)
 	if err != nil {
 		return "", err
 	}
@@ -193,7 +194,7 @@ func PodReceiveInfo(podSharingReference string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	shareInfo, err := api.PodReceiveInfo(sessionId, ref)
+	shareInfo, err := api.PodReceiveInfo(context.Background(), sessionId, ref)
 	if err != nil {
 		return "", err
 	}
@@ -202,7 +203,7 @@ func PodReceiveInfo(podSharingReference string) (string, error) {
 }
 
 func DirPresent(podName, dirPath string) (string, error) {
-	present, err := api.IsDirPresent(podName, dirPath, sessionId)
+	present, err := api.IsDirPresent(context.Background(), podName, dirPath, sessionId)
 	if err != nil {
 		return "", err
 	}
@@ -213,7 +214,7 @@ func DirPresent(podName, dirPath string) (string, error) {
 }
 
 func DirMake(podName, dirPath string) (string, error) {
-	err := api.Mkdir(podName, dirPath, sessionId)
+	err := api.Mkdir(context.Background(), podName, dirPath, sessionId)
 	if err != nil {
 		return "", err
 	}
@@ -221,7 +222,7 @@ func DirMake(podName, dirPath string) (string, error) {
 }
 
 func DirRemove(podName, dirPath string) (string, error) {
-	err := api.RmDir(podName, dirPath, sessionId)
+	err := api.RmDir(context.Background(), podName, dirPath, sessionId)
 	if err != nil {
 		return "", err
 	}
@@ -229,7 +230,7 @@ func DirRemove(podName, dirPath string) (string, error) {
 }
 
 func DirList(podName, dirPath string) (string, error) {
-	dirs, files, err := api.ListDir(podName, dirPath, sessionId)
+	dirs, files, err := api.ListDir(context.Background(), podName, dirPath, sessionId)
 	if err != nil {
 		return "", err
 	}
@@ -249,7 +250,7 @@ func DirList(podName, dirPath string) (string, error) {
 }
 
 func DirStat(podName, dirPath string) (string, error) {
-	stat, err := api.DirectoryStat(podName, dirPath, sessionId)
+	stat, err := api.DirectoryStat(context.Background(), podName, dirPath, sessionId)
 	if err != nil {
 		return "", err
 	}
@@ -258,7 +259,7 @@ func DirStat(podName, dirPath string) (string, error) {
 }
 
 func FileShare(podName, dirPath, destinationUser string) (string, error) {
-	ref, err := api.ShareFile(podName, dirPath, destinationUser, sessionId)
+	ref, err := api.ShareFile(context.Background(), podName, dirPath, destinationUser, sessionId)
 	if err != nil {
 		return "", err
 	}
@@ -273,7 +274,7 @@ func FileReceive(podName, directory, fileSharingReference string) (string, error
 	if err != nil {
 		return "", err
 	}
-	filePath, err := api.ReceiveFile(podName, sessionId, ref, directory)
+	filePath, err := api.ReceiveFile(context.Background(), podName, sessionId, ref, directory)
 	if err != nil {
 		return "", err
 	}
@@ -288,7 +289,7 @@ func FileReceiveInfo(podName, fileSharingReference string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	receiveInfo, err := api.ReceiveInfo(sessionId, ref)
+	receiveInfo, err := api.ReceiveInfo(context.Background(), sessionId, ref)
 	if err != nil {
 		return "", err
 	}
@@ -297,11 +298,11 @@ func FileReceiveInfo(podName, fileSharingReference string) (string, error) {
 }
 
 func FileDelete(podName, filePath string) error {
-	return api.DeleteFile(podName, filePath, sessionId)
+	return api.DeleteFile(context.Background(), podName, filePath, sessionId)
 }
 
 func FileStat(podName, filePath string) (string, error) {
-	stat, err := api.FileStat(podName, filePath, sessionId)
+	stat, err := api.FileStat(context.Background(), podName, filePath, sessionId)
 	if err != nil {
 		return "", err
 	}
@@ -323,16 +324,16 @@ func FileUpload(podName, filePath, dirPath, compression, blockSize string, overw
 	if err != nil {
 		return err
 	}
-	return api.UploadFile(podName, fileInfo.Name(), sessionId, fileInfo.Size(), f, dirPath, compression, uint32(bs), overwrite)
+	return api.UploadFile(context.Background(), podName, fileInfo.Name(), sessionId, fileInfo.Size(), f, dirPath, compression, uint32(bs), overwrite)
 }
 
 func BlobUpload(data []byte, podName, fileName, dirPath, compression string, size, blockSize int64, overwrite bool) error {
 	r := bytes.NewReader(data)
-	return api.UploadFile(podName, fileName, sessionId, size, r, dirPath, compression, uint32(blockSize), overwrite)
+	return api.UploadFile(context.Background(), podName, fileName, sessionId, size, r, dirPath, compression, uint32(blockSize), overwrite)
 }
 
 func FileDownload(podName, filePath string) ([]byte, error) {
-	r, _, err := api.DownloadFile(podName, filePath, sessionId)
+	r, _, err := api.DownloadFile(context.Background(), podName, filePath, sessionId)
 	if err != nil {
 		return nil, err
 	}
@@ -361,7 +362,7 @@ func KVNewStore(podName, tableName, indexType string) (string, error) {
 	default:
 		return "", fmt.Errorf("invalid indexType. only string and number are allowed")
 	}
-	err := api.KVCreate(sessionId, podName, tableName, idxType)
+	err := api.KVCreate(context.Background(), sessionId, podName, tableName, idxType)
 	if err != nil {
 		return "", err
 	}
@@ -369,7 +370,7 @@ func KVNewStore(podName, tableName, indexType string) (string, error) {
 }
 
 func KVList(podName string) (string, error) {
-	collections, err := api.KVList(sessionId, podName)
+	collections, err := api.KVList(context.Background(), sessionId, podName)
 	if err != nil {
 		return "", err
 	}
@@ -378,15 +379,15 @@ func KVList(podName string) (string, error) {
 }
 
 func KVOpen(podName, tableName string) error {
-	return api.KVOpen(sessionId, podName, tableName)
+	return api.KVOpen(context.Background(), sessionId, podName, tableName)
 }
 
 func KVDelete(podName, tableName string) error {
-	return api.KVDelete(sessionId, podName, tableName)
+	return api.KVDelete(context.Background(), sessionId, podName, tableName)
 }
 
 func KVCount(podName, tableName string) (string, error) {
-	count, err := api.KVCount(sessionId, podName, tableName)
+	count, err := api.KVCount(context.Background(), sessionId, podName, tableName)
 	if err != nil {
 		return "", err
 	}
@@ -395,11 +396,11 @@ func KVCount(podName, tableName string) (string, error) {
 }
 
 func KVEntryPut(podName, tableName, key string, value []byte) error {
-	return api.KVPut(sessionId, podName, tableName, key, value)
+	return api.KVPut(context.Background(), sessionId, podName, tableName, key, value)
 }
 
 func KVEntryGet(podName, tableName, key string) ([]byte, error) {
-	_, data, err := api.KVGet(sessionId, podName, tableName, key)
+	_, data, err := api.KVGet(context.Background(), sessionId, podName, tableName, key)
 	if err != nil {
 		return nil, err
 	}
@@ -407,11 +408,12 @@ func KVEntryGet(podName, tableName, key string) ([]byte, error) {
 }
 
 func KVEntryDelete(podName, tableName, key string) error {
-	_, err := api.KVDel(sessionId, podName, tableName, key)
+	_, err := api.KVDel(context.Background(), sessionId, podName, tableName, key)
 	return err
 }
 
 func KVLoadCSV(podName, tableName, filePath, memory string) (string, error) {
+	ctx := context.Background()
 	_, err := os.Lstat(filePath)
 	if err != nil {
 		return "", err
@@ -452,7 +454,7 @@ func KVLoadCSV(podName, tableName, filePath, memory string) (string, error) {
 				return "", err
 			}
 
-			err = batch.Put(collection.CSVHeaderKey, []byte(record), false, mem)
+			err = batch.Put(ctx, collection.CSVHeaderKey, []byte(record), false, mem)
 			if err != nil {
 				failureCount++
 				readHeader = true
@@ -464,14 +466,14 @@ func KVLoadCSV(podName, tableName, filePath, memory string) (string, error) {
 		}
 
 		key := strings.Split(record, ",")[0]
-		err = batch.Put(key, []byte(record), false, mem)
+		err = batch.Put(ctx, key, []byte(record), false, mem)
 		if err != nil {
 			failureCount++
 			continue
 		}
 		successCount++
 	}
-	_, err = batch.Write("")
+	_, err = batch.Write(ctx, "")
 	if err != nil {
 		return "", err
 	}
@@ -479,12 +481,12 @@ func KVLoadCSV(podName, tableName, filePath, memory string) (string, error) {
 }
 
 func KVSeek(podName, tableName, start, end string, limit int64) error {
-	_, err := api.KVSeek(sessionId, podName, tableName, start, end, limit)
+	_, err := api.KVSeek(context.Background(), sessionId, podName, tableName, start, end, limit)
 	return err
 }
 
 func KVSeekNext(podName, tableName string) (string, error) {
-	_, key, data, err := api.KVGetNext(sessionId, podName, tableName)
+	_, key, data, err := api.KVGetNext(context.Background(), sessionId, podName, tableName)
 	if err != nil {
 		return "", err
 	}
@@ -519,11 +521,11 @@ func DocNewStore(podName, tableName, simpleIndexes string, mutable bool) error {
 			}
 		}
 	}
-	return api.DocCreate(sessionId, podName, tableName, indexes, mutable)
+	return api.DocCreate(context.Background(), sessionId, podName, tableName, indexes, mutable)
 }
 
 func DocList(podName string) (string, error) {
-	collections, err := api.DocList(sessionId, podName)
+	collections, err := api.DocList(context.Background(), sessionId, podName)
 	if err != nil {
 		return "", err
 	}
@@ -532,11 +534,11 @@ func DocList(podName string) (string, error) {
 }
 
 func DocOpen(podName, tableName string) error {
-	return api.DocOpen(sessionId, podName, tableName)
+	return api.DocOpen(context.Background(), sessionId, podName, tableName)
 }
 
 func DocCount(podName, tableName, expression string) (string, error) {
-	count, err := api.DocCount(sessionId, podName, tableName, expression)
+	count, err := api.DocCount(context.Background(), sessionId, podName, tableName, expression)
 	if err != nil {
 		return "", err
 	}
@@ -545,11 +547,11 @@ func DocCount(podName, tableName, expression string) (string, error) {
 }
 
 func DocDelete(podName, tableName string) error {
-	return api.DocDelete(sessionId, podName, tableName)
+	return api.DocDelete(context.Background(), sessionId, podName, tableName)
 }
 
 func DocFind(podName, tableName, expression string, limit int) (string, error) {
-	count, err := api.DocFind(sessionId, podName, tableName, expression, limit)
+	count, err := api.DocFind(context.Background(), sessionId, podName, tableName, expression, limit)
 	if err != nil {
 		return "", err
 	}
@@ -558,7 +560,7 @@ func DocFind(podName, tableName, expression string, limit int) (string, error) {
 }
 
 func DocEntryPut(podName, tableName, value string) error {
-	return api.DocPut(sessionId, podName, tableName, []byte(value))
+	return api.DocPut(context.Background(), sessionId, podName, tableName, []byte(value))
 }
 
 type DocGetResponse struct {
@@ -566,7 +568,7 @@ type DocGetResponse struct {
 }
 
 func DocEntryGet(podName, tableName, id string) (string, error) {
-	data, err := api.DocGet(sessionId, podName, tableName, id)
+	data, err := api.DocGet(context.Background(), sessionId, podName, tableName, id)
 	if err != nil {
 		return "", err
 	}
@@ -578,10 +580,11 @@ func DocEntryGet(podName, tableName, id string) (string, error) {
 }
 
 func DocEntryDelete(podName, tableName, id string) error {
-	return api.DocDel(sessionId, podName, tableName, id)
+	return api.DocDel(context.Background(), sessionId, podName, tableName, id)
 }
 
 func DocLoadJson(podName, tableName, filePath string) (string, error) {
+	ctx := context.Background()
 	_, err := os.Lstat(filePath)
 	if err != nil {
 		return "", err
@@ -596,7 +599,7 @@ func DocLoadJson(podName, tableName, filePath string) (string, error) {
 	rowCount := 0
 	successCount := 0
 	failureCount := 0
-	docBatch, err := api.DocBatch(sessionId, podName, tableName)
+	docBatch, err := api.DocBatch(ctx, sessionId, podName, tableName)
 	if err != nil {
 		return "", err
 	}
@@ -615,14 +618,14 @@ func DocLoadJson(podName, tableName, filePath string) (string, error) {
 		record = strings.TrimSuffix(record, "\n")
 		record = strings.TrimSuffix(record, "\r")
 
-		err = api.DocBatchPut(sessionId, podName, []byte(record), docBatch)
+		err = api.DocBatchPut(ctx, sessionId, podName, []byte(record), docBatch)
 		if err != nil {
 			failureCount++
 			continue
 		}
 		successCount++
 	}
-	err = api.DocBatchWrite(sessionId, podName, docBatch)
+	err = api.DocBatchWrite(ctx, sessionId, podName, docBatch)
 	if err != nil {
 		return "", err
 	}
@@ -630,5 +633,5 @@ func DocLoadJson(podName, tableName, filePath string) (string, error) {
 }
 
 func DocIndexJson(podName, tableName, filePath string) error {
-	return api.DocIndexJson(sessionId, podName, tableName, filePath)
+	return api.DocIndexJson(context.Background(), sessionId, podName, tableName, filePath)
 }
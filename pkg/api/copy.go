@@ -0,0 +1,167 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/fairdatasociety/fairOS-dfs/cmd/common"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/dfs"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/dir"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/file"
+	p "github.com/fairdatasociety/fairOS-dfs/pkg/pod"
+	"resenje.org/jsonhttp"
+)
+
+// CopyRequest copies a file or a directory tree, to another pod of the user if
+// destinationPodName is given
+type CopyRequest struct {
+	PodName            string `json:"podName,omitempty"`
+	FilePath           string `json:"filePath,omitempty"`
+	DirPath            string `json:"dirPath,omitempty"`
+	DestinationPodName string `json:"destinationPodName,omitempty"`
+	DestinationPath    string `json:"destinationPath,omitempty"`
+}
+
+// FileCopyHandler godoc
+//
+//	@Summary      Copy a file
+//	@Description  FileCopyHandler is the api handler to copy a file in the pod or to another open pod. The copy refers to the blocks of the file, they are not uploaded again.
+//	@Tags         file
+//	@Accept       json
+//	@Produce      json
+//	@Param	      copy_request body CopyRequest true "pod name, file path, destination pod name and destination path"
+//	@Param	      Cookie header string true "cookie parameter"
+//	@Success      201  {object}  response
+//	@Failure      400  {object}  response
+//	@Failure      404  {object}  response
+//	@Failure      500  {object}  response
+//	@Router       /v1/file/copy [post]
+func (h *Handler) FileCopyHandler(w http.ResponseWriter, r *http.Request) {
+	req, sessionId, ok := h.copyArguments(w, r, "file copy", "filePath")
+	if !ok {
+		return
+	}
+	err := h.dfsAPI.CopyFile(r.Context(), req.PodName, req.FilePath, req.DestinationPodName, req.DestinationPath, sessionId)
+	if err != nil {
+		h.respondCopyError(w, "file copy", err)
+		return
+	}
+	jsonhttp.Created(w, &response{Message: "file copied successfully"})
+}
+
+// DirectoryCopyHandler godoc
+//
+//	@Summary      Copy a directory
+//	@Description  DirectoryCopyHandler is the api handler to copy a directory tree in the pod or to another open pod. The files of the copy refer to the blocks of the files, they are not uploaded again.
+//	@Tags         dir
+//	@Accept       json
+//	@Produce      json
+//	@Param	      copy_request body CopyRequest true "pod name, directory path, destination pod name and destination path"
+//	@Param	      Cookie header string true "cookie parameter"
+//	@Success      201  {object}  response
+//	@Failure      400  {object}  response
+//	@Failure      404  {object}  response
+//	@Failure      500  {object}  response
+//	@Router       /v1/dir/copy [post]
+func (h *Handler) DirectoryCopyHandler(w http.ResponseWriter, r *http.Request) {
+	req, sessionId, ok := h.copyArguments(w, r, "dir copy", "dirPath")
+	if !ok {
+		return
+	}
+	err := h.dfsAPI.CopyDirectory(r.Context(), req.PodName, req.DirPath, req.DestinationPodName, req.DestinationPath, sessionId)
+	if err != nil {
+		h.respondCopyError(w, "dir copy", err)
+		return
+	}
+	jsonhttp.Created(w, &response{Message: "directory copied successfully"})
+}
+
+// handleWsCopy copies a file or a directory tree for a websocket event
+func (h *Handler) handleWsCopy(ctx context.Context, event common.Event, req *CopyRequest, sessionId string) (interface{}, error) {
+	if event == common.DirCopy {
+		if req.PodName == "" || req.DirPath == "" || req.DestinationPath == "" {
+			return nil, fmt.Errorf("dir copy: \"podName\", \"dirPath\" and \"destinationPath\" arguments are needed")
+		}
+		err := h.dfsAPI.CopyDirectory(ctx, req.PodName, req.DirPath, req.DestinationPodName, req.DestinationPath, sessionId)
+		if err != nil {
+			return nil, err
+		}
+		return &response{Message: "directory copied successfully"}, nil
+	}
+	if req.PodName == "" || req.FilePath == "" || req.DestinationPath == "" {
+		return nil, fmt.Errorf("file copy: \"podName\", \"filePath\" and \"destinationPath\" arguments are needed")
+	}
+	err := h.dfsAPI.CopyFile(ctx, req.PodName, req.FilePath, req.DestinationPodName, req.DestinationPath, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	return &response{Message: "file copied successfully"}, nil
+}
+
+// copyArguments decodes the request body of a copy
+func (h *Handler) copyArguments(w http.ResponseWriter, r *http.Request, op, pathArg string) (*CopyRequest, string, bool) {
+	contentType := r.Header.Get("Content-Type")
+	if contentType != jsonContentType {
+		h.logger.Errorf("%s: invalid request body type", op)
+		jsonhttp.BadRequest(w, &response{Message: op + ": invalid request body type"})
+		return nil, "", false
+	}
+	req := &CopyRequest{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		h.logger.Errorf("%s: could not decode arguments", op)
+		jsonhttp.BadRequest(w, &response{Message: op + ": could not decode arguments"})
+		return nil, "", false
+	}
+	path := req.FilePath
+	if pathArg == "dirPath" {
+		path = req.DirPath
+	}
+	args := [][2]string{{"podName", req.PodName}, {pathArg, path}, {"destinationPath", req.DestinationPath}}
+	for _, arg := range args {
+		if arg[1] == "" {
+			h.logger.Errorf("%s: \"%s\" argument missing", op, arg[0])
+			jsonhttp.BadRequest(w, &response{Message: op + ": \"" + arg[0] + "\" argument missing"})
+			return nil, "", false
+		}
+	}
+	sessionId, ok := h.sessionArgument(w, r, op)
+	if !ok {
+		return nil, "", false
+	}
+	return req, sessionId, true
+}
+
+func (h *Handler) respondCopyError(w http.ResponseWriter, op string, err error) {
+	h.logger.Errorf("%s: %v", op, err)
+	switch {
+	case errors.Is(err, file.ErrFileNotPresent) || errors.Is(err, dir.ErrDirectoryNotPresent):
+		jsonhttp.NotFound(w, &response{Message: op + ": " + err.Error()})
+	case errors.Is(err, file.ErrFileAlreadyPresent) || errors.Is(err, dir.ErrDirectoryAlreadyPresent) ||
+		errors.Is(err, dir.ErrLinkAlreadyPresent) || errors.Is(err, dir.ErrTooManyLinks) ||
+		errors.Is(err, dir.ErrInvalidFileOrDirectoryName) || errors.Is(err, p.ErrCopyIntoItself) ||
+		err == dfs.ErrPodNotOpen || err == dfs.ErrUserNotLoggedIn || err == p.ErrPodNotOpened:
+		jsonhttp.BadRequest(w, &response{Message: op + ": " + err.Error()})
+	default:
+		jsonhttp.InternalServerError(w, &response{Message: op + ": " + err.Error()})
+	}
+}
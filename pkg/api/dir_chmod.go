@@ -31,6 +31,7 @@ type DirModeRequest struct {
 //	@Failure      500  {object}  response
 //	@Router       /v1/dir/chmod [post]
 func (h *Handler) DirectoryModeHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("dir chmod: invalid request body type")
@@ -88,7 +89,7 @@ func (h *Handler) DirectoryModeHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.dfsAPI.ChmodDir(podName, dirPath, sessionId, uint32(mode))
+	err = h.dfsAPI.ChmodDir(ctx, podName, dirPath, sessionId, uint32(mode))
 	if err != nil {
 		h.logger.Errorf("dir chmod: %v", err)
 		jsonhttp.BadRequest(w, &response{Message: err.Error()})
@@ -0,0 +1,94 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+
+	"resenje.org/jsonhttp"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/cookie"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/dfs"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/dir"
+	p "github.com/fairdatasociety/fairOS-dfs/pkg/pod"
+)
+
+// DirHistoryResponse lists the revisions of the inode of a directory
+type DirHistoryResponse struct {
+	Revisions []dir.InodeRevision `json:"revisions"`
+}
+
+// DirectoryHistoryHandler godoc
+//
+//	@Summary      Directory history
+//	@Description  DirectoryHistoryHandler is the api handler to list all the revisions of the inode of a directory, oldest first
+//	@Tags         dir
+//	@Produce      json
+//	@Param	      podName query string true "pod name"
+//	@Param	      dirPath query string true "dir path"
+//	@Param	      Cookie header string true "cookie parameter"
+//	@Success      200  {object}  DirHistoryResponse
+//	@Failure      400  {object}  response
+//	@Failure      500  {object}  response
+//	@Router       /v1/dir/history [get]
+func (h *Handler) DirectoryHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	keys, ok := r.URL.Query()["podName"]
+	if !ok || len(keys[0]) < 1 {
+		h.logger.Errorf("dir history: \"podName\" argument missing")
+		jsonhttp.BadRequest(w, &response{Message: "dir history: \"podName\" argument missing"})
+		return
+	}
+	podName := keys[0]
+
+	keys, ok = r.URL.Query()["dirPath"]
+	if !ok || len(keys[0]) < 1 {
+		h.logger.Errorf("dir history: \"dirPath\" argument missing")
+		jsonhttp.BadRequest(w, &response{Message: "dir history: \"dirPath\" argument missing"})
+		return
+	}
+	dirPath := keys[0]
+
+	// get values from cookie
+	sessionId, err := cookie.GetSessionIdFromCookie(r)
+	if err != nil {
+		h.logger.Errorf("dir history: invalid cookie: %v", err)
+		jsonhttp.BadRequest(w, &response{Message: ErrInvalidCookie.Error()})
+		return
+	}
+	if sessionId == "" {
+		h.logger.Errorf("dir history: \"cookie-id\" parameter missing in cookie")
+		jsonhttp.BadRequest(w, &response{Message: "dir history: \"cookie-id\" parameter missing in cookie"})
+		return
+	}
+
+	revisions, err := h.dfsAPI.DirectoryHistory(ctx, podName, dirPath, sessionId)
+	if err != nil {
+		if err == dfs.ErrPodNotOpen || err == dfs.ErrUserNotLoggedIn ||
+			err == p.ErrPodNotOpened {
+			h.logger.Errorf("dir history: %v", err)
+			jsonhttp.BadRequest(w, &response{Message: "dir history: " + err.Error()})
+			return
+		}
+		h.logger.Errorf("dir history: %v", err)
+		jsonhttp.InternalServerError(w, &response{Message: "dir history: " + err.Error()})
+		return
+	}
+
+	w.Header().Set("Content-Type", " application/json")
+	jsonhttp.OK(w, &DirHistoryResponse{Revisions: revisions})
+}
@@ -42,12 +42,14 @@ type ListFileResponse struct {
 //	@Produce      json
 //	@Param	      podName query string true "pod name"
 //	@Param	      dirPath query string true "dir path"
+//	@Param	      xattrs query bool false "list the extended attributes of the entries"
 //	@Param	      Cookie header string true "cookie parameter"
 //	@Success      200  {object}  ListFileResponse
 //	@Failure      400  {object}  response
 //	@Failure      500  {object}  response
 //	@Router       /v1/dir/ls [get]
 func (h *Handler) DirectoryLsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	keys, ok := r.URL.Query()["podName"]
 	if !ok || len(keys[0]) < 1 {
 		h.logger.Errorf("ls: \"podName\" argument missing")
@@ -78,7 +80,7 @@ func (h *Handler) DirectoryLsHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// list directory
-	dEntries, fEntries, err := h.dfsAPI.ListDir(podName, directory, sessionId)
+	dEntries, fEntries, err := h.dfsAPI.ListDir(ctx, podName, directory, sessionId)
 	if err != nil {
 		if err == dfs.ErrPodNotOpen || err == dfs.ErrUserNotLoggedIn ||
 			err == p.ErrPodNotOpened {
@@ -102,6 +104,9 @@ func (h *Handler) DirectoryLsHandler(w http.ResponseWriter, r *http.Request) {
 	if fEntries == nil {
 		fEntries = make([]file.Entry, 0)
 	}
+	if !withXattrs(r) {
+		withoutXattrs(dEntries, fEntries)
+	}
 	w.Header().Set("Content-Type", "application/json")
 	jsonhttp.OK(w, &ListFileResponse{
 		Directories: dEntries,
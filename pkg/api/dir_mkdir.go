@@ -47,6 +47,7 @@ type DirRequest struct {
 //	@Failure      500  {object}  response
 //	@Router       /v1/dir/mkdir [post]
 func (h *Handler) DirectoryMkdirHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("mkdir: invalid request body type")
@@ -91,7 +92,7 @@ func (h *Handler) DirectoryMkdirHandler(w http.ResponseWriter, r *http.Request)
 	}
 
 	// make directory
-	err = h.dfsAPI.Mkdir(podName, dirToCreateWithPath, sessionId)
+	err = h.dfsAPI.Mkdir(ctx, podName, dirToCreateWithPath, sessionId)
 	if err != nil {
 		if err == dfs.ErrPodNotOpen || err == dfs.ErrUserNotLoggedIn ||
 			err == p.ErrInvalidDirectory ||
@@ -43,6 +43,7 @@ type DirPresentResponse struct {
 //	@Failure      500  {object}  response
 //	@Router       /v1/dir/present [get]
 func (h *Handler) DirectoryPresentHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	keys, ok := r.URL.Query()["podName"]
 	if !ok || len(keys[0]) < 1 {
 		h.logger.Errorf("dir present: \"podName\" argument missing")
@@ -73,7 +74,7 @@ func (h *Handler) DirectoryPresentHandler(w http.ResponseWriter, r *http.Request
 	}
 
 	// check if user is present
-	present, err := h.dfsAPI.IsDirPresent(podName, dirToCheck, sessionId)
+	present, err := h.dfsAPI.IsDirPresent(ctx, podName, dirToCheck, sessionId)
 	if err != nil {
 		jsonhttp.OK(w, &DirPresentResponse{
 			Present: present,
@@ -43,6 +43,7 @@ import (
 //	@Failure      500  {object}  response
 //	@Router       /v1/dir/rename [post]
 func (h *Handler) DirectoryRenameHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("rename-dir: invalid request body type")
@@ -94,7 +95,7 @@ func (h *Handler) DirectoryRenameHandler(w http.ResponseWriter, r *http.Request)
 	}
 
 	// make directory
-	err = h.dfsAPI.RenameDir(podName, oldPath, newPath, sessionId)
+	err = h.dfsAPI.RenameDir(ctx, podName, oldPath, newPath, sessionId)
 	if err != nil {
 		if err == dfs.ErrPodNotOpen || err == dfs.ErrUserNotLoggedIn ||
 			err == p.ErrInvalidDirectory ||
@@ -41,6 +41,7 @@ import (
 //	@Failure      500  {object}  response
 //	@Router       /v1/dir/rmdir [delete]
 func (h *Handler) DirectoryRmdirHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("rmdir: invalid request body type")
@@ -85,7 +86,7 @@ func (h *Handler) DirectoryRmdirHandler(w http.ResponseWriter, r *http.Request)
 	}
 
 	// remove directory
-	err = h.dfsAPI.RmDir(podName, dir, sessionId)
+	err = h.dfsAPI.RmDir(ctx, podName, dir, sessionId)
 	if err != nil {
 		if err == dfs.ErrPodNotOpen || err == dfs.ErrUserNotLoggedIn ||
 			err == p.ErrPodNotOpened {
@@ -34,12 +34,14 @@ import (
 //	@Produce      json
 //	@Param	      podName query string true "pod name"
 //	@Param	      dirPath query string true "dir path"
+//	@Param	      xattrs query bool false "include the extended attributes"
 //	@Param	      Cookie header string true "cookie parameter"
 //	@Success      200  {object}  dir.Stats
 //	@Failure      400  {object}  response
 //	@Failure      500  {object}  response
 //	@Router       /v1/dir/stat [get]
 func (h *Handler) DirectoryStatHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	keys, ok := r.URL.Query()["podName"]
 	if !ok || len(keys[0]) < 1 {
 		h.logger.Errorf("dir: \"podName\" argument missing")
@@ -70,7 +72,7 @@ func (h *Handler) DirectoryStatHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// stat directory
-	ds, err := h.dfsAPI.DirectoryStat(podName, dir, sessionId)
+	ds, err := h.dfsAPI.DirectoryStat(ctx, podName, dir, sessionId)
 	if err != nil {
 		if err == dfs.ErrPodNotOpen || err == dfs.ErrUserNotLoggedIn ||
 			err == p.ErrPodNotOpened {
@@ -83,6 +85,9 @@ func (h *Handler) DirectoryStatHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !withXattrs(r) {
+		ds.Xattrs = nil
+	}
 	w.Header().Set("Content-Type", " application/json")
 	jsonhttp.OK(w, ds)
 }
@@ -47,6 +47,7 @@ type DocCountRequest struct {
 //	@Failure      500  {object}  response
 //	@Router       /v1/doc/count [post]
 func (h *Handler) DocCountHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("doc count: invalid request body type")
@@ -92,7 +93,7 @@ func (h *Handler) DocCountHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	count, err := h.dfsAPI.DocCount(sessionId, podName, name, expr)
+	count, err := h.dfsAPI.DocCount(ctx, sessionId, podName, name, expr)
 	if err != nil {
 		h.logger.Errorf("doc count: %v", err)
 		jsonhttp.InternalServerError(w, &response{Message: "doc count: " + err.Error()})
@@ -38,6 +38,7 @@ import (
 //	@Failure      500  {object}  response
 //	@Router       /v1/doc/delete [delete]
 func (h *Handler) DocDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("doc delete: invalid request body type")
@@ -81,7 +82,7 @@ func (h *Handler) DocDeleteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.dfsAPI.DocDelete(sessionId, podName, name)
+	err = h.dfsAPI.DocDelete(ctx, sessionId, podName, name)
 	if err != nil {
 		h.logger.Errorf("doc delete: %v", err)
 		jsonhttp.InternalServerError(w, &response{Message: "doc delete: " + err.Error()})
@@ -51,6 +51,7 @@ type DocFind struct {
 //	@Failure      500  {object}  response
 //	@Router       /v1/doc/find [get]
 func (h *Handler) DocFindHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	keys, ok := r.URL.Query()["podName"]
 	if !ok || len(keys[0]) < 1 {
 		h.logger.Errorf("doc find: \"podName\" argument missing")
@@ -122,7 +123,7 @@ func (h *Handler) DocFindHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	data, err := h.dfsAPI.DocFind(sessionId, podName, name, expr, limitInt)
+	data, err := h.dfsAPI.DocFind(ctx, sessionId, podName, name, expr, limitInt)
 	if err != nil {
 		h.logger.Errorf("doc find: %v", err)
 		jsonhttp.InternalServerError(w, &response{Message: "doc find: " + err.Error()})
@@ -46,6 +46,7 @@ type DocIndexRequest struct {
 //	@Failure      500  {object}  response
 //	@Router       /v1/doc/indexjson [post]
 func (h *Handler) DocIndexJsonHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("doc indexjson: invalid request body type")
@@ -96,7 +97,7 @@ func (h *Handler) DocIndexJsonHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.dfsAPI.DocIndexJson(sessionId, podName, tableName, podFile)
+	err = h.dfsAPI.DocIndexJson(ctx, sessionId, podName, tableName, podFile)
 	if err != nil {
 		if err == dfs.ErrPodNotOpen || err == dfs.ErrFileNotPresent {
 			h.logger.Errorf("doc indexjson: %v", err)
@@ -43,6 +43,7 @@ import (
 //	@Failure      500  {object}  response
 //	@Router       /v1/doc/loadjson [post]
 func (h *Handler) DocLoadJsonHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	podName := r.FormValue("podName")
 	if podName == "" {
 		h.logger.Errorf("doc loadjson: \"podName\" argument missing")
@@ -96,7 +97,7 @@ func (h *Handler) DocLoadJsonHandler(w http.ResponseWriter, r *http.Request) {
 	rowCount := 0
 	successCount := 0
 	failureCount := 0
-	docBatch, err := h.dfsAPI.DocBatch(sessionId, podName, name)
+	docBatch, err := h.dfsAPI.DocBatch(ctx, sessionId, podName, name)
 	if err != nil {
 		h.logger.Errorf("doc loadjson: %v", err)
 		jsonhttp.InternalServerError(w, &response{Message: "doc loadjson: " + err.Error()})
@@ -119,7 +120,7 @@ func (h *Handler) DocLoadJsonHandler(w http.ResponseWriter, r *http.Request) {
 		record = strings.TrimSuffix(record, "\n")
 		record = strings.TrimSuffix(record, "\r")
 
-		err = h.dfsAPI.DocBatchPut(sessionId, podName, []byte(record), docBatch)
+		err = h.dfsAPI.DocBatchPut(ctx, sessionId, podName, []byte(record), docBatch)
 		if err != nil {
 			failureCount++
 			continue
@@ -130,7 +131,7 @@ func (h *Handler) DocLoadJsonHandler(w http.ResponseWriter, r *http.Request) {
 			h.logger.Info("uploaded ", rowCount)
 		}
 	}
-	err = h.dfsAPI.DocBatchWrite(sessionId, podName, docBatch)
+	err = h.dfsAPI.DocBatchWrite(ctx, sessionId, podName, docBatch)
 	if err != nil {
 		h.logger.Errorf("doc loadjson: %v", err)
 		jsonhttp.InternalServerError(w, &response{Message: "doc loadjson: " + err.Error()})
@@ -49,6 +49,7 @@ type documentDB struct {
 //	@Failure      500  {object}  response
 //	@Router       /v1/doc/ls [get]
 func (h *Handler) DocListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	keys, ok := r.URL.Query()["podName"]
 	if !ok || len(keys[0]) < 1 {
 		h.logger.Errorf("doc ls: \"podName\" argument missing")
@@ -75,7 +76,7 @@ func (h *Handler) DocListHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	collections, err := h.dfsAPI.DocList(sessionId, podName)
+	collections, err := h.dfsAPI.DocList(ctx, sessionId, podName)
 	if err != nil {
 		h.logger.Errorf("doc ls: %v", err)
 		jsonhttp.InternalServerError(w, &response{Message: "doc ls: " + err.Error()})
@@ -54,6 +54,7 @@ type SimpleDocRequest struct {
 //	@Failure      500  {object}  response
 //	@Router       /v1/doc/new [post]
 func (h *Handler) DocCreateHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("doc create: invalid request body type")
@@ -129,7 +130,7 @@ func (h *Handler) DocCreateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.dfsAPI.DocCreate(sessionId, podName, name, indexes, mutable)
+	err = h.dfsAPI.DocCreate(ctx, sessionId, podName, name, indexes, mutable)
 	if err != nil {
 		h.logger.Errorf("doc create: %v", err)
 		jsonhttp.InternalServerError(w, &response{Message: "doc create: " + err.Error()})
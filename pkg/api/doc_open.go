@@ -38,6 +38,7 @@ import (
 //	@Failure      500  {object}  response
 //	@Router       /v1/doc/open [post]
 func (h *Handler) DocOpenHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("doc open: invalid request body type")
@@ -81,7 +82,7 @@ func (h *Handler) DocOpenHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.dfsAPI.DocOpen(sessionId, podName, name)
+	err = h.dfsAPI.DocOpen(ctx, sessionId, podName, name)
 	if err != nil {
 		h.logger.Errorf("doc open: %v", err)
 		jsonhttp.InternalServerError(w, &response{Message: "doc open: " + err.Error()})
@@ -62,6 +62,7 @@ type DocGet struct {
 //	@Failure      500  {object}  response
 //	@Router       /v1/doc/entry/put [post]
 func (h *Handler) DocEntryPutHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("doc put: invalid request body type")
@@ -111,7 +112,7 @@ func (h *Handler) DocEntryPutHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.dfsAPI.DocPut(sessionId, podName, name, []byte(doc))
+	err = h.dfsAPI.DocPut(ctx, sessionId, podName, name, []byte(doc))
 	if err != nil {
 		h.logger.Errorf("doc put: %v", err)
 		jsonhttp.InternalServerError(w, &response{Message: "doc put: " + err.Error()})
@@ -136,6 +137,7 @@ func (h *Handler) DocEntryPutHandler(w http.ResponseWriter, r *http.Request) {
 //	@Failure      500  {object}  response
 //	@Router       /v1/doc/entry/get [get]
 func (h *Handler) DocEntryGetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	keys, ok := r.URL.Query()["podName"]
 	if !ok || len(keys[0]) < 1 {
 		h.logger.Errorf("doc get: \"podName\" argument missing")
@@ -188,7 +190,7 @@ func (h *Handler) DocEntryGetHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	data, err := h.dfsAPI.DocGet(sessionId, podName, name, id)
+	data, err := h.dfsAPI.DocGet(ctx, sessionId, podName, name, id)
 	if err != nil {
 		h.logger.Errorf("doc get: %v", err)
 		jsonhttp.InternalServerError(w, &response{Message: "doc get: " + err.Error()})
@@ -216,6 +218,7 @@ func (h *Handler) DocEntryGetHandler(w http.ResponseWriter, r *http.Request) {
 //	@Failure      500  {object}  response
 //	@Router       /v1/doc/entry/del [delete]
 func (h *Handler) DocEntryDelHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("doc del: invalid request body type")
@@ -266,7 +269,7 @@ func (h *Handler) DocEntryDelHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.dfsAPI.DocDel(sessionId, podName, name, id)
+	err = h.dfsAPI.DocDel(ctx, sessionId, podName, name, id)
 	if err != nil {
 		h.logger.Errorf("doc del: %v", err)
 		jsonhttp.InternalServerError(w, &response{Message: "doc del: " + err.Error()})
@@ -31,6 +31,7 @@ type FileModeRequest struct {
 //	@Failure      500  {object}  response
 //	@Router       /v1/file/chmod [Post]
 func (h *Handler) FileModeHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("file chmod: invalid request body type")
@@ -88,7 +89,7 @@ func (h *Handler) FileModeHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.dfsAPI.ChmodFile(podName, filePath, sessionId, uint32(mode))
+	err = h.dfsAPI.ChmodFile(ctx, podName, filePath, sessionId, uint32(mode))
 	if err != nil {
 		h.logger.Errorf("file chmod: %v", err)
 		jsonhttp.BadRequest(w, &response{Message: err.Error()})
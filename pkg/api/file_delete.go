@@ -47,6 +47,7 @@ type FileDeleteRequest struct {
 //	@Failure      500  {object}  response
 //	@Router       /v1/file/delete [delete]
 func (h *Handler) FileDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("file delete: invalid request body type")
@@ -90,7 +91,7 @@ func (h *Handler) FileDeleteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	// delete file
-	err = h.dfsAPI.DeleteFile(podName, podFileWithPath, sessionId)
+	err = h.dfsAPI.DeleteFile(ctx, podName, podFileWithPath, sessionId)
 	if err != nil {
 		if err == dfs.ErrPodNotOpen {
 			h.logger.Errorf("file delete: %v", err)
@@ -17,9 +17,9 @@ limitations under the License.
 package api
 
 import (
-	"io"
+	"encoding/hex"
 	"net/http"
-	"strconv"
+	"time"
 
 	"github.com/fairdatasociety/fairOS-dfs/pkg/cookie"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/dfs"
@@ -30,15 +30,21 @@ import (
 // FileDownloadHandlerPost godoc
 //
 //	@Summary      Download a file
-//	@Description  FileDownloadHandlerPost is the api handler to download a file from a given pod
+//	@Description  FileDownloadHandlerPost is the api handler to download a file, or ranges of it, from a given pod
 //	@Tags         file
 //	@Accept       mpfd
 //	@Produce      */*
 //	@Param	      podName formData string true "pod name"
 //	@Param	      filePath formData string true "file path"
 //	@Param	      Cookie header string true "cookie parameter"
+//	@Param	      Range header string false "byte ranges to download"
+//	@Param	      If-Range header string false "entity tag the ranges are downloaded for"
+//	@Param	      If-None-Match header string false "entity tags of the content already downloaded"
 //	@Success      200  {array}  byte
+//	@Success      206  {array}  byte
+//	@Success      304  {string}  string
 //	@Failure      400  {object}  response
+//	@Failure      416  {string}  string
 //	@Failure      500  {object}  response
 //	@Router       /v1/file/download [post]
 func (h *Handler) FileDownloadHandlerPost(w http.ResponseWriter, r *http.Request) {
@@ -62,15 +68,21 @@ func (h *Handler) FileDownloadHandlerPost(w http.ResponseWriter, r *http.Request
 // FileDownloadHandlerGet godoc
 //
 //	@Summary      Download a file
-//	@Description  FileDownloadHandlerGet is the api handler to download a file from a given pod
+//	@Description  FileDownloadHandlerGet is the api handler to download a file, or ranges of it, from a given pod
 //	@Tags         file
 //	@Accept       json
 //	@Produce      */*
 //	@Param	      podName query string true "pod name"
 //	@Param	      filePath query string true "file path"
 //	@Param	      Cookie header string true "cookie parameter"
+//	@Param	      Range header string false "byte ranges to download"
+//	@Param	      If-Range header string false "entity tag the ranges are downloaded for"
+//	@Param	      If-None-Match header string false "entity tags of the content already downloaded"
 //	@Success      200  {array}  byte
+//	@Success      206  {array}  byte
+//	@Success      304  {string}  string
 //	@Failure      400  {object}  response
+//	@Failure      416  {string}  string
 //	@Failure      500  {object}  response
 //	@Router       /v1/file/download [get]
 func (h *Handler) FileDownloadHandlerGet(w http.ResponseWriter, r *http.Request) {
@@ -104,6 +116,7 @@ func (h *Handler) FileDownloadHandlerGet(w http.ResponseWriter, r *http.Request)
 }
 
 func (h *Handler) handleDownload(w http.ResponseWriter, r *http.Request, podName, podFileWithPath string) {
+	ctx := r.Context()
 	// get values from cookie
 	sessionId, err := cookie.GetSessionIdFromCookie(r)
 	if err != nil {
@@ -117,8 +130,8 @@ func (h *Handler) handleDownload(w http.ResponseWriter, r *http.Request, podName
 		return
 	}
 
-	// download file from bee
-	reader, size, err := h.dfsAPI.DownloadFile(podName, podFileWithPath, sessionId)
+	// open the file, the content is read in the ranges that are requested
+	reader, meta, err := h.dfsAPI.OpenFile(ctx, podName, podFileWithPath, sessionId)
 	if err != nil {
 		if err == dfs.ErrPodNotOpen {
 			h.logger.Errorf("download: %v", err)
@@ -136,13 +149,30 @@ func (h *Handler) handleDownload(w http.ResponseWriter, r *http.Request, podName
 	}
 	defer reader.Close()
 
-	sizeString := strconv.FormatUint(size, 10)
-	w.Header().Set("Content-Length", sizeString)
-
-	_, err = io.Copy(w, reader)
-	if err != nil {
-		h.logger.Errorf("download: %v", err)
-		w.Header().Set("Content-Type", " application/json")
-		jsonhttp.InternalServerError(w, "download: "+err.Error())
+	// the inode reference changes with every change of the content, so it is a strong
+	// validator for the Range, If-Range and If-None-Match headers
+	w.Header().Set("ETag", fileETag(meta))
+	if meta.Checksum != "" {
+		w.Header().Set(checksumHeader, meta.Checksum)
+	}
+	if meta.BlockTreeChecksum != "" {
+		w.Header().Set(blockTreeChecksumHeader, meta.BlockTreeChecksum)
 	}
+	if meta.ContentType != "" {
+		w.Header().Set("Content-Type", meta.ContentType)
+	}
+	http.ServeContent(w, r, meta.Name, time.Unix(meta.ModificationTime, 0), reader)
+}
+
+// checksumHeader carries the hex encoded SHA-256 of the whole content of a file, which is
+// the content that is sent when no range is requested
+const checksumHeader = "X-Fairos-Checksum"
+
+// blockTreeChecksumHeader carries the SHA-256 of the SHA-256 of the blocks of a file. It
+// is not the SHA-256 of the content that is sent.
+const blockTreeChecksumHeader = "X-Fairos-Block-Tree-Checksum"
+
+// fileETag returns the entity tag of the content of a file
+func fileETag(meta *file.MetaData) string {
+	return "\"" + hex.EncodeToString(meta.InodeAddress) + "\""
 }
@@ -42,6 +42,7 @@ import (
 //	@Failure      500  {object}  response
 //	@Router       /v1/file/rename [post]
 func (h *Handler) FileRenameHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("file rename: invalid request body type")
@@ -92,7 +93,7 @@ func (h *Handler) FileRenameHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	// rename file
-	err = h.dfsAPI.RenameFile(podName, podFileWithPath, newPodFileWithPath, sessionId)
+	err = h.dfsAPI.RenameFile(ctx, podName, podFileWithPath, newPodFileWithPath, sessionId)
 	if err != nil {
 		if err == dfs.ErrPodNotOpen {
 			h.logger.Errorf("file rename: %v", err)
@@ -57,6 +57,7 @@ type FileShareRequest struct {
 //	@Failure      500  {object}  response
 //	@Router       /v1/file/share [post]
 func (h *Handler) FileShareHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("file share: invalid request body type")
@@ -106,7 +107,7 @@ func (h *Handler) FileShareHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	sharingRef, err := h.dfsAPI.ShareFile(podName, podFileWithPath, destinationRef, sessionId)
+	sharingRef, err := h.dfsAPI.ShareFile(ctx, podName, podFileWithPath, destinationRef, sessionId)
 	if err != nil {
 		h.logger.Errorf("file share: %v", err)
 		jsonhttp.InternalServerError(w, &response{Message: "file share: " + err.Error()})
@@ -135,6 +136,7 @@ func (h *Handler) FileShareHandler(w http.ResponseWriter, r *http.Request) {
 //	@Failure      500  {object}  response
 //	@Router       /v1/file/receive [get]
 func (h *Handler) FileReceiveHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	keys, ok := r.URL.Query()["podName"]
 	if !ok || len(keys[0]) < 1 {
 		h.logger.Errorf("file receive: \"podName\" argument missing")
@@ -189,7 +191,7 @@ func (h *Handler) FileReceiveHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	filePath, err := h.dfsAPI.ReceiveFile(podName, sessionId, sharingRef, dir)
+	filePath, err := h.dfsAPI.ReceiveFile(ctx, podName, sessionId, sharingRef, dir)
 	if err != nil {
 		h.logger.Errorf("file receive: %v", err)
 		jsonhttp.InternalServerError(w, &response{Message: "file receive: " + err.Error()})
@@ -216,6 +218,7 @@ func (h *Handler) FileReceiveHandler(w http.ResponseWriter, r *http.Request) {
 //	@Failure      500  {object}  response
 //	@Router       /v1/file/receiveinfo [get]
 func (h *Handler) FileReceiveInfoHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	keys, ok := r.URL.Query()["sharingRef"]
 	if !ok || len(keys[0]) < 1 {
 		h.logger.Errorf("file receive info: \"sharingRef\" argument missing")
@@ -249,7 +252,7 @@ func (h *Handler) FileReceiveInfoHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	receiveInfo, err := h.dfsAPI.ReceiveInfo(sessionId, sharingRef)
+	receiveInfo, err := h.dfsAPI.ReceiveInfo(ctx, sessionId, sharingRef)
 	if err != nil {
 		h.logger.Errorf("file receive info: %v", err)
 		jsonhttp.InternalServerError(w, &response{Message: "file receive info: " + err.Error()})
@@ -34,12 +34,14 @@ import (
 //	@Produce      json
 //	@Param	      podName query string true "pod name"
 //	@Param	      filePath query string true "file path"
+//	@Param	      xattrs query bool false "include the extended attributes"
 //	@Param	      Cookie header string true "cookie parameter"
 //	@Success      200  {object}  file.Stats
 //	@Failure      400  {object}  response
 //	@Failure      500  {object}  response
 //	@Router       /v1/file/stat [get]
 func (h *Handler) FileStatHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	keys, ok := r.URL.Query()["podName"]
 	if !ok || len(keys[0]) < 1 {
 		h.logger.Errorf("file stat: \"podName\" argument missing")
@@ -80,7 +82,7 @@ func (h *Handler) FileStatHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// get file stat
-	stat, err := h.dfsAPI.FileStat(podName, podFileWithPath, sessionId)
+	stat, err := h.dfsAPI.FileStat(ctx, podName, podFileWithPath, sessionId)
 	if err != nil {
 		if err == dfs.ErrPodNotOpen {
 			h.logger.Errorf("file stat: %v", err)
@@ -92,6 +94,9 @@ func (h *Handler) FileStatHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !withXattrs(r) {
+		stat.Xattrs = nil
+	}
 	w.Header().Set("Content-Type", " application/json")
 	jsonhttp.OK(w, stat)
 }
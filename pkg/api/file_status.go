@@ -31,6 +31,7 @@ type StatusResponse struct {
 //	@Failure      500  {object}  response
 //	@Router       /v1/file/status [get]
 func (h *Handler) FileStatusHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	keys, ok := r.URL.Query()["podName"]
 	if !ok || len(keys[0]) < 1 {
 		h.logger.Errorf("status \"podName\" argument missing")
@@ -71,7 +72,7 @@ func (h *Handler) FileStatusHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// status of file
-	t, p, s, err := h.dfsAPI.StatusFile(podName, podFileWithPath, sessionId)
+	t, p, s, err := h.dfsAPI.StatusFile(ctx, podName, podFileWithPath, sessionId)
 	if err != nil {
 		if err == dfs.ErrPodNotOpen {
 			h.logger.Errorf("status: %v", err)
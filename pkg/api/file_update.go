@@ -42,6 +42,7 @@ import (
 //	@Failure      500  {object}  response
 //	@Router       /v1/file/update [Post]
 func (h *Handler) FileUpdateHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	// get values from cookie
 	sessionId, err := cookie.GetSessionIdFromCookie(r)
 	if err != nil {
@@ -95,7 +96,7 @@ func (h *Handler) FileUpdateHandler(w http.ResponseWriter, r *http.Request) {
 	//	jsonhttp.BadRequest(w, &response{Message: "file update: seek failed: " + err.Error()})
 	//	return
 	//}
-	_, err = h.dfsAPI.WriteAtFile(podName, fileNameWithPath, sessionId, file, uint64(offset), false)
+	_, err = h.dfsAPI.WriteAtFile(ctx, podName, fileNameWithPath, sessionId, file, uint64(offset), false)
 	if err != nil {
 		h.logger.Errorf("file update: writeAt failed: %s", err.Error())
 		jsonhttp.BadRequest(w, &response{Message: "file update: writeAt failed: " + err.Error()})
@@ -17,6 +17,7 @@ limitations under the License.
 package api
 
 import (
+	"context"
 	"mime/multipart"
 	"net/http"
 	"strconv"
@@ -24,6 +25,7 @@ import (
 	"github.com/dustin/go-humanize"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/cookie"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/dfs"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/file"
 	"resenje.org/jsonhttp"
 )
 
@@ -55,7 +57,7 @@ const (
 //	@Param	      dirPath formData string true "location"
 //	@Param	      blockSize formData string true "block size to break the file" example(4Kb, 1Mb)
 //	@Param	      files formData file true "file to upload"
-//	@Param	      fairOS-dfs-Compression header string false "cookie parameter" example(snappy, gzip)
+//	@Param	      fairOS-dfs-Compression header string false "cookie parameter" example(snappy, gzip, zstd, zstd:19, lz4)
 //	@Param	      Cookie header string true "cookie parameter"
 //	@Param	      overwrite formData string false "overwrite the file if already exists" example(true, false)
 //	@Success      200  {object}  response
@@ -63,6 +65,7 @@ const (
 //	@Failure      500  {object}  response
 //	@Router       /v1/file/upload [Post]
 func (h *Handler) FileUploadHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	podName := r.FormValue("podName")
 	if podName == "" {
 		h.logger.Errorf("file upload: \"podName\" argument missing")
@@ -85,12 +88,10 @@ func (h *Handler) FileUploadHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	compression := r.Header.Get(CompressionHeader)
-	if compression != "" {
-		if compression != "snappy" && compression != "gzip" {
-			h.logger.Errorf("file upload: invalid value for \"compression\" header")
-			jsonhttp.BadRequest(w, &response{Message: "file upload: invalid value for \"compression\" header"})
-			return
-		}
+	if file.CheckCompression(compression) != nil {
+		h.logger.Errorf("file upload: invalid value for \"compression\" header")
+		jsonhttp.BadRequest(w, &response{Message: "file upload: invalid value for \"compression\" header"})
+		return
 	}
 	var err error
 	overwrite := true
@@ -148,7 +149,7 @@ func (h *Handler) FileUploadHandler(w http.ResponseWriter, r *http.Request) {
 			responses = append(responses, UploadResponse{FileName: file.Filename, Message: err.Error()})
 			continue
 		}
-		err = h.handleFileUpload(podName, file.Filename, sessionId, file.Size, fd, podPath, compression, uint32(bs), overwrite)
+		err = h.handleFileUpload(ctx, podName, file.Filename, sessionId, file.Size, fd, podPath, compression, uint32(bs), overwrite)
 		if err != nil {
 			if err == dfs.ErrPodNotOpen {
 				h.logger.Errorf("file upload: %v", err)
@@ -168,7 +169,7 @@ func (h *Handler) FileUploadHandler(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-func (h *Handler) handleFileUpload(podName, podFileName, sessionId string, fileSize int64, f multipart.File, podPath, compression string, blockSize uint32, overwrite bool) error {
+func (h *Handler) handleFileUpload(ctx context.Context, podName, podFileName, sessionId string, fileSize int64, f multipart.File, podPath, compression string, blockSize uint32, overwrite bool) error {
 	defer f.Close()
-	return h.dfsAPI.UploadFile(podName, podFileName, sessionId, fileSize, f, podPath, compression, blockSize, overwrite)
+	return h.dfsAPI.UploadFile(ctx, podName, podFileName, sessionId, fileSize, f, podPath, compression, blockSize, overwrite)
 }
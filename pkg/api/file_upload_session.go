@@ -0,0 +1,386 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/cookie"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/dfs"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/file"
+	p "github.com/fairdatasociety/fairOS-dfs/pkg/pod"
+	"resenje.org/jsonhttp"
+)
+
+// UploadSessionRequest is used to start a resumable upload
+type UploadSessionRequest struct {
+	PodName     string `json:"podName,omitempty"`
+	DirPath     string `json:"dirPath,omitempty"`
+	FileName    string `json:"fileName,omitempty"`
+	FileSize    int64  `json:"fileSize"`
+	BlockSize   string `json:"blockSize,omitempty"`
+	Compression string `json:"compression,omitempty"`
+}
+
+// UploadSessionFinishRequest is used to finish or abort a resumable upload
+type UploadSessionFinishRequest struct {
+	PodName   string `json:"podName,omitempty"`
+	UploadId  string `json:"uploadId,omitempty"`
+	Overwrite bool   `json:"overwrite,omitempty"`
+}
+
+// UploadSessionResponse tells which blocks of a resumable upload are stored
+type UploadSessionResponse struct {
+	UploadId     string `json:"uploadId"`
+	DirPath      string `json:"dirPath"`
+	FileName     string `json:"fileName"`
+	FileSize     uint64 `json:"fileSize"`
+	BlockSize    uint32 `json:"blockSize"`
+	Compression  string `json:"compression,omitempty"`
+	StoredBlocks []int  `json:"storedBlocks"`
+	NextOffset   uint64 `json:"nextOffset"`
+	Complete     bool   `json:"complete"`
+}
+
+func newUploadSessionResponse(s *file.UploadSession) *UploadSessionResponse {
+	return &UploadSessionResponse{
+		UploadId:     s.ID,
+		DirPath:      s.Path,
+		FileName:     s.Name,
+		FileSize:     s.Size,
+		BlockSize:    s.BlockSize,
+		Compression:  s.Compression,
+		StoredBlocks: s.StoredBlocks(),
+		NextOffset:   s.NextOffset(),
+		Complete:     s.Complete(),
+	}
+}
+
+// FileUploadSessionHandler godoc
+//
+//	@Summary      Start a resumable upload
+//	@Description  FileUploadSessionHandler is the api handler to start an upload session, whose parts are sent with /v1/file/upload/part
+//	@Tags         file
+//	@Accept       json
+//	@Produce      json
+//	@Param	      upload_session_request body UploadSessionRequest true "file to upload"
+//	@Param	      Cookie header string true "cookie parameter"
+//	@Success      200  {object}  UploadSessionResponse
+//	@Failure      400  {object}  response
+//	@Failure      500  {object}  response
+//	@Router       /v1/file/upload/session [post]
+func (h *Handler) FileUploadSessionHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	contentType := r.Header.Get("Content-Type")
+	if contentType != jsonContentType {
+		h.logger.Errorf("upload session: invalid request body type")
+		jsonhttp.BadRequest(w, &response{Message: "upload session: invalid request body type"})
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	var req UploadSessionRequest
+	err := decoder.Decode(&req)
+	if err != nil {
+		h.logger.Errorf("upload session: could not decode arguments")
+		jsonhttp.BadRequest(w, &response{Message: "upload session: could not decode arguments"})
+		return
+	}
+	if req.PodName == "" {
+		h.logger.Errorf("upload session: \"podName\" argument missing")
+		jsonhttp.BadRequest(w, &response{Message: "upload session: \"podName\" argument missing"})
+		return
+	}
+	if req.DirPath == "" {
+		h.logger.Errorf("upload session: \"dirPath\" argument missing")
+		jsonhttp.BadRequest(w, &response{Message: "upload session: \"dirPath\" argument missing"})
+		return
+	}
+	if req.FileName == "" {
+		h.logger.Errorf("upload session: \"fileName\" argument missing")
+		jsonhttp.BadRequest(w, &response{Message: "upload session: \"fileName\" argument missing"})
+		return
+	}
+	if req.BlockSize == "" {
+		h.logger.Errorf("upload session: \"blockSize\" argument missing")
+		jsonhttp.BadRequest(w, &response{Message: "upload session: \"blockSize\" argument missing"})
+		return
+	}
+	if file.CheckCompression(req.Compression) != nil {
+		h.logger.Errorf("upload session: invalid value for \"compression\"")
+		jsonhttp.BadRequest(w, &response{Message: "upload session: invalid value for \"compression\""})
+		return
+	}
+	bs, err := humanize.ParseBytes(req.BlockSize)
+	if err != nil {
+		h.logger.Errorf("upload session: %v", err)
+		jsonhttp.BadRequest(w, &response{Message: "upload session: " + err.Error()})
+		return
+	}
+
+	// get values from cookie
+	sessionId, err := cookie.GetSessionIdFromCookie(r)
+	if err != nil {
+		h.logger.Errorf("upload session: invalid cookie: %v", err)
+		jsonhttp.BadRequest(w, &response{Message: ErrInvalidCookie.Error()})
+		return
+	}
+	if sessionId == "" {
+		h.logger.Errorf("upload session: \"cookie-id\" parameter missing in cookie")
+		jsonhttp.BadRequest(w, &response{Message: "upload session: \"cookie-id\" parameter missing in cookie"})
+		return
+	}
+
+	s, err := h.dfsAPI.CreateUploadSession(ctx, req.PodName, req.FileName, sessionId, req.FileSize, req.DirPath, req.Compression, uint32(bs))
+	if err != nil {
+		h.respondUploadSessionError(w, "upload session", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", " application/json")
+	jsonhttp.OK(w, newUploadSessionResponse(s))
+}
+
+// FileUploadSessionStatusHandler godoc
+//
+//	@Summary      Status of a resumable upload
+//	@Description  FileUploadSessionStatusHandler is the api handler to list the blocks of an upload session that are stored
+//	@Tags         file
+//	@Produce      json
+//	@Param	      podName query string true "pod name"
+//	@Param	      uploadId query string true "upload id"
+//	@Param	      Cookie header string true "cookie parameter"
+//	@Success      200  {object}  UploadSessionResponse
+//	@Failure      400  {object}  response
+//	@Failure      404  {object}  response
+//	@Failure      500  {object}  response
+//	@Router       /v1/file/upload/session [get]
+func (h *Handler) FileUploadSessionStatusHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	podName := r.URL.Query().Get("podName")
+	if podName == "" {
+		h.logger.Errorf("upload status: \"podName\" argument missing")
+		jsonhttp.BadRequest(w, &response{Message: "upload status: \"podName\" argument missing"})
+		return
+	}
+	uploadId := r.URL.Query().Get("uploadId")
+	if uploadId == "" {
+		h.logger.Errorf("upload status: \"uploadId\" argument missing")
+		jsonhttp.BadRequest(w, &response{Message: "upload status: \"uploadId\" argument missing"})
+		return
+	}
+
+	// get values from cookie
+	sessionId, err := cookie.GetSessionIdFromCookie(r)
+	if err != nil {
+		h.logger.Errorf("upload status: invalid cookie: %v", err)
+		jsonhttp.BadRequest(w, &response{Message: ErrInvalidCookie.Error()})
+		return
+	}
+	if sessionId == "" {
+		h.logger.Errorf("upload status: \"cookie-id\" parameter missing in cookie")
+		jsonhttp.BadRequest(w, &response{Message: "upload status: \"cookie-id\" parameter missing in cookie"})
+		return
+	}
+
+	s, err := h.dfsAPI.GetUploadSession(ctx, podName, uploadId, sessionId)
+	if err != nil {
+		h.respondUploadSessionError(w, "upload status", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", " application/json")
+	jsonhttp.OK(w, newUploadSessionResponse(s))
+}
+
+// FileUploadPartHandler godoc
+//
+//	@Summary      Upload a part of a resumable upload
+//	@Description  FileUploadPartHandler is the api handler to store the blocks of a part of an upload session. The part starts at a block boundary and holds whole blocks, except for the last block of the file.
+//	@Tags         file
+//	@Accept       octet-stream
+//	@Produce      json
+//	@Param	      podName query string true "pod name"
+//	@Param	      uploadId query string true "upload id"
+//	@Param	      offset query string true "offset of the part in the file"
+//	@Param	      Cookie header string true "cookie parameter"
+//	@Success      200  {object}  UploadSessionResponse
+//	@Failure      400  {object}  response
+//	@Failure      404  {object}  response
+//	@Failure      500  {object}  response
+//	@Router       /v1/file/upload/part [put]
+func (h *Handler) FileUploadPartHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	podName := r.URL.Query().Get("podName")
+	if podName == "" {
+		h.logger.Errorf("upload part: \"podName\" argument missing")
+		jsonhttp.BadRequest(w, &response{Message: "upload part: \"podName\" argument missing"})
+		return
+	}
+	uploadId := r.URL.Query().Get("uploadId")
+	if uploadId == "" {
+		h.logger.Errorf("upload part: \"uploadId\" argument missing")
+		jsonhttp.BadRequest(w, &response{Message: "upload part: \"uploadId\" argument missing"})
+		return
+	}
+	offset, err := strconv.ParseUint(r.URL.Query().Get("offset"), 10, 64)
+	if err != nil {
+		h.logger.Errorf("upload part: invalid \"offset\" argument")
+		jsonhttp.BadRequest(w, &response{Message: "upload part: invalid \"offset\" argument"})
+		return
+	}
+
+	// get values from cookie
+	sessionId, err := cookie.GetSessionIdFromCookie(r)
+	if err != nil {
+		h.logger.Errorf("upload part: invalid cookie: %v", err)
+		jsonhttp.BadRequest(w, &response{Message: ErrInvalidCookie.Error()})
+		return
+	}
+	if sessionId == "" {
+		h.logger.Errorf("upload part: \"cookie-id\" parameter missing in cookie")
+		jsonhttp.BadRequest(w, &response{Message: "upload part: \"cookie-id\" parameter missing in cookie"})
+		return
+	}
+
+	s, err := h.dfsAPI.UploadPart(ctx, podName, uploadId, sessionId, offset, r.Body)
+	if err != nil {
+		h.respondUploadSessionError(w, "upload part", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", " application/json")
+	jsonhttp.OK(w, newUploadSessionResponse(s))
+}
+
+// FileUploadSessionFinishHandler godoc
+//
+//	@Summary      Finish a resumable upload
+//	@Description  FileUploadSessionFinishHandler is the api handler to create the file of an upload session whose blocks are all stored
+//	@Tags         file
+//	@Accept       json
+//	@Produce      json
+//	@Param	      upload_session_request body UploadSessionFinishRequest true "upload to finish"
+//	@Param	      Cookie header string true "cookie parameter"
+//	@Success      200  {object}  UploadResponse
+//	@Failure      400  {object}  response
+//	@Failure      404  {object}  response
+//	@Failure      500  {object}  response
+//	@Router       /v1/file/upload/finish [post]
+func (h *Handler) FileUploadSessionFinishHandler(w http.ResponseWriter, r *http.Request) {
+	req, sessionId, ok := h.decodeUploadSessionFinishRequest(w, r, "upload finish")
+	if !ok {
+		return
+	}
+	meta, err := h.dfsAPI.FinishUploadSession(r.Context(), req.PodName, req.UploadId, sessionId, req.Overwrite)
+	if err != nil {
+		h.respondUploadSessionError(w, "upload finish", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", " application/json")
+	jsonhttp.OK(w, &UploadResponse{FileName: meta.Name, Message: "uploaded successfully"})
+}
+
+// FileUploadSessionAbortHandler godoc
+//
+//	@Summary      Abort a resumable upload
+//	@Description  FileUploadSessionAbortHandler is the api handler to remove an upload session
+//	@Tags         file
+//	@Accept       json
+//	@Produce      json
+//	@Param	      upload_session_request body UploadSessionFinishRequest true "upload to abort"
+//	@Param	      Cookie header string true "cookie parameter"
+//	@Success      200  {object}  response
+//	@Failure      400  {object}  response
+//	@Failure      404  {object}  response
+//	@Failure      500  {object}  response
+//	@Router       /v1/file/upload/session [delete]
+func (h *Handler) FileUploadSessionAbortHandler(w http.ResponseWriter, r *http.Request) {
+	req, sessionId, ok := h.decodeUploadSessionFinishRequest(w, r, "upload abort")
+	if !ok {
+		return
+	}
+	err := h.dfsAPI.AbortUploadSession(r.Context(), req.PodName, req.UploadId, sessionId)
+	if err != nil {
+		h.respondUploadSessionError(w, "upload abort", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", " application/json")
+	jsonhttp.OK(w, &response{Message: "upload aborted successfully"})
+}
+
+func (h *Handler) decodeUploadSessionFinishRequest(w http.ResponseWriter, r *http.Request, op string) (*UploadSessionFinishRequest, string, bool) {
+	contentType := r.Header.Get("Content-Type")
+	if contentType != jsonContentType {
+		h.logger.Errorf("%s: invalid request body type", op)
+		jsonhttp.BadRequest(w, &response{Message: op + ": invalid request body type"})
+		return nil, "", false
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	var req UploadSessionFinishRequest
+	err := decoder.Decode(&req)
+	if err != nil {
+		h.logger.Errorf("%s: could not decode arguments", op)
+		jsonhttp.BadRequest(w, &response{Message: op + ": could not decode arguments"})
+		return nil, "", false
+	}
+	if req.PodName == "" {
+		h.logger.Errorf("%s: \"podName\" argument missing", op)
+		jsonhttp.BadRequest(w, &response{Message: op + ": \"podName\" argument missing"})
+		return nil, "", false
+	}
+	if req.UploadId == "" {
+		h.logger.Errorf("%s: \"uploadId\" argument missing", op)
+		jsonhttp.BadRequest(w, &response{Message: op + ": \"uploadId\" argument missing"})
+		return nil, "", false
+	}
+
+	// get values from cookie
+	sessionId, err := cookie.GetSessionIdFromCookie(r)
+	if err != nil {
+		h.logger.Errorf("%s: invalid cookie: %v", op, err)
+		jsonhttp.BadRequest(w, &response{Message: ErrInvalidCookie.Error()})
+		return nil, "", false
+	}
+	if sessionId == "" {
+		h.logger.Errorf("%s: \"cookie-id\" parameter missing in cookie", op)
+		jsonhttp.BadRequest(w, &response{Message: op + ": \"cookie-id\" parameter missing in cookie"})
+		return nil, "", false
+	}
+	return &req, sessionId, true
+}
+
+func (h *Handler) respondUploadSessionError(w http.ResponseWriter, op string, err error) {
+	h.logger.Errorf("%s: %v", op, err)
+	switch {
+	case errors.Is(err, file.ErrUploadSessionNotFound):
+		jsonhttp.NotFound(w, &response{Message: op + ": " + err.Error()})
+	case err == dfs.ErrPodNotOpen || err == dfs.ErrUserNotLoggedIn || err == p.ErrPodNotOpened ||
+		errors.Is(err, file.ErrInvalidPart) || errors.Is(err, file.ErrUploadIncomplete) ||
+		errors.Is(err, file.ErrGzipBlSize):
+		jsonhttp.BadRequest(w, &response{Message: op + ": " + err.Error()})
+	default:
+		jsonhttp.InternalServerError(w, &response{Message: op + ": " + err.Error()})
+	}
+}
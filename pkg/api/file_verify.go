@@ -0,0 +1,52 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+
+	"resenje.org/jsonhttp"
+)
+
+// FileVerifyHandler godoc
+//
+//	@Summary      Verify a file
+//	@Description  FileVerifyHandler is the api handler to download all the blocks of a file and check them against the checksums recorded when the file was written
+//	@Tags         file
+//	@Produce      json
+//	@Param	      podName query string true "pod name"
+//	@Param	      filePath query string true "file path"
+//	@Param	      Cookie header string true "cookie parameter"
+//	@Success      200  {object}  file.Verification
+//	@Failure      400  {object}  response
+//	@Failure      404  {object}  response
+//	@Failure      500  {object}  response
+//	@Router       /v1/file/verify [get]
+func (h *Handler) FileVerifyHandler(w http.ResponseWriter, r *http.Request) {
+	podName, podFileWithPath, sessionId, ok := h.fileArguments(w, r, "verify")
+	if !ok {
+		return
+	}
+	v, err := h.dfsAPI.VerifyFile(r.Context(), podName, podFileWithPath, sessionId)
+	if err != nil {
+		h.respondVersionError(w, "verify", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", " application/json")
+	jsonhttp.OK(w, v)
+}
@@ -0,0 +1,284 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/cookie"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/dfs"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/file"
+	p "github.com/fairdatasociety/fairOS-dfs/pkg/pod"
+	"resenje.org/jsonhttp"
+)
+
+// FileVersionsResponse lists the previous versions of a file
+type FileVersionsResponse struct {
+	Versions []file.FileVersion `json:"versions"`
+}
+
+// FileVersionRequest is used to restore a version of a file
+type FileVersionRequest struct {
+	PodName  string `json:"podName,omitempty"`
+	FilePath string `json:"filePath,omitempty"`
+	Version  int    `json:"version"`
+}
+
+// FileVersionsHandler godoc
+//
+//	@Summary      Versions of a file
+//	@Description  FileVersionsHandler is the api handler to list the previous versions of a file that are kept, oldest first
+//	@Tags         file
+//	@Produce      json
+//	@Param	      podName query string true "pod name"
+//	@Param	      filePath query string true "file path"
+//	@Param	      Cookie header string true "cookie parameter"
+//	@Success      200  {object}  FileVersionsResponse
+//	@Failure      400  {object}  response
+//	@Failure      404  {object}  response
+//	@Failure      500  {object}  response
+//	@Router       /v1/file/versions [get]
+func (h *Handler) FileVersionsHandler(w http.ResponseWriter, r *http.Request) {
+	podName, podFileWithPath, sessionId, ok := h.fileArguments(w, r, "file versions")
+	if !ok {
+		return
+	}
+	versions, err := h.dfsAPI.FileVersions(r.Context(), podName, podFileWithPath, sessionId)
+	if err != nil {
+		h.respondVersionError(w, "file versions", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", " application/json")
+	jsonhttp.OK(w, &FileVersionsResponse{Versions: versions})
+}
+
+// FileVersionDownloadHandler godoc
+//
+//	@Summary      Download a version of a file
+//	@Description  FileVersionDownloadHandler is the api handler to download a previous version of a file, version 0 is the current content
+//	@Tags         file
+//	@Produce      */*
+//	@Param	      podName query string true "pod name"
+//	@Param	      filePath query string true "file path"
+//	@Param	      version query string true "version number"
+//	@Param	      Cookie header string true "cookie parameter"
+//	@Success      200  {array}  byte
+//	@Failure      400  {object}  response
+//	@Failure      404  {object}  response
+//	@Failure      500  {object}  response
+//	@Router       /v1/file/version/download [get]
+func (h *Handler) FileVersionDownloadHandler(w http.ResponseWriter, r *http.Request) {
+	podName, podFileWithPath, sessionId, ok := h.fileArguments(w, r, "version download")
+	if !ok {
+		return
+	}
+	version, err := strconv.Atoi(r.URL.Query().Get("version"))
+	if err != nil {
+		h.logger.Errorf("version download: invalid \"version\" argument")
+		jsonhttp.BadRequest(w, &response{Message: "version download: invalid \"version\" argument"})
+		return
+	}
+
+	reader, size, err := h.dfsAPI.DownloadFileVersion(r.Context(), podName, podFileWithPath, sessionId, version)
+	if err != nil {
+		h.respondVersionError(w, "version download", err)
+		return
+	}
+	defer reader.Close()
+
+	w.Header().Set("Content-Length", strconv.FormatUint(size, 10))
+	_, err = io.Copy(w, reader)
+	if err != nil {
+		h.logger.Errorf("version download: %v", err)
+		w.Header().Set("Content-Type", " application/json")
+		jsonhttp.InternalServerError(w, "version download: "+err.Error())
+	}
+}
+
+// FileVersionDiffHandler godoc
+//
+//	@Summary      Compare two versions of a file
+//	@Description  FileVersionDiffHandler is the api handler to list the byte ranges that differ between two versions of a file, version 0 is the current content
+//	@Tags         file
+//	@Produce      json
+//	@Param	      podName query string true "pod name"
+//	@Param	      filePath query string true "file path"
+//	@Param	      from query string true "version to compare from"
+//	@Param	      to query string false "version to compare to, the current content by default"
+//	@Param	      Cookie header string true "cookie parameter"
+//	@Success      200  {object}  file.VersionDiff
+//	@Failure      400  {object}  response
+//	@Failure      404  {object}  response
+//	@Failure      500  {object}  response
+//	@Router       /v1/file/version/diff [get]
+func (h *Handler) FileVersionDiffHandler(w http.ResponseWriter, r *http.Request) {
+	podName, podFileWithPath, sessionId, ok := h.fileArguments(w, r, "version diff")
+	if !ok {
+		return
+	}
+	from, err := strconv.Atoi(r.URL.Query().Get("from"))
+	if err != nil {
+		h.logger.Errorf("version diff: invalid \"from\" argument")
+		jsonhttp.BadRequest(w, &response{Message: "version diff: invalid \"from\" argument"})
+		return
+	}
+	to := 0
+	if toString := r.URL.Query().Get("to"); toString != "" {
+		to, err = strconv.Atoi(toString)
+		if err != nil {
+			h.logger.Errorf("version diff: invalid \"to\" argument")
+			jsonhttp.BadRequest(w, &response{Message: "version diff: invalid \"to\" argument"})
+			return
+		}
+	}
+
+	diff, err := h.dfsAPI.DiffFileVersions(r.Context(), podName, podFileWithPath, sessionId, from, to)
+	if err != nil {
+		h.respondVersionError(w, "version diff", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", " application/json")
+	jsonhttp.OK(w, diff)
+}
+
+// FileVersionRestoreHandler godoc
+//
+//	@Summary      Restore a version of a file
+//	@Description  FileVersionRestoreHandler is the api handler to make a previous version of a file its current content
+//	@Tags         file
+//	@Accept       json
+//	@Produce      json
+//	@Param	      file_version_request body FileVersionRequest true "file and version"
+//	@Param	      Cookie header string true "cookie parameter"
+//	@Success      200  {object}  response
+//	@Failure      400  {object}  response
+//	@Failure      404  {object}  response
+//	@Failure      500  {object}  response
+//	@Router       /v1/file/version/restore [post]
+func (h *Handler) FileVersionRestoreHandler(w http.ResponseWriter, r *http.Request) {
+	contentType := r.Header.Get("Content-Type")
+	if contentType != jsonContentType {
+		h.logger.Errorf("version restore: invalid request body type")
+		jsonhttp.BadRequest(w, &response{Message: "version restore: invalid request body type"})
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	var req FileVersionRequest
+	err := decoder.Decode(&req)
+	if err != nil {
+		h.logger.Errorf("version restore: could not decode arguments")
+		jsonhttp.BadRequest(w, &response{Message: "version restore: could not decode arguments"})
+		return
+	}
+	if req.PodName == "" {
+		h.logger.Errorf("version restore: \"podName\" argument missing")
+		jsonhttp.BadRequest(w, &response{Message: "version restore: \"podName\" argument missing"})
+		return
+	}
+	if req.FilePath == "" {
+		h.logger.Errorf("version restore: \"filePath\" argument missing")
+		jsonhttp.BadRequest(w, &response{Message: "version restore: \"filePath\" argument missing"})
+		return
+	}
+
+	// get values from cookie
+	sessionId, err := cookie.GetSessionIdFromCookie(r)
+	if err != nil {
+		h.logger.Errorf("version restore: invalid cookie: %v", err)
+		jsonhttp.BadRequest(w, &response{Message: ErrInvalidCookie.Error()})
+		return
+	}
+	if sessionId == "" {
+		h.logger.Errorf("version restore: \"cookie-id\" parameter missing in cookie")
+		jsonhttp.BadRequest(w, &response{Message: "version restore: \"cookie-id\" parameter missing in cookie"})
+		return
+	}
+
+	err = h.dfsAPI.RestoreFileVersion(r.Context(), req.PodName, req.FilePath, sessionId, req.Version)
+	if err != nil {
+		h.respondVersionError(w, "version restore", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", " application/json")
+	jsonhttp.OK(w, &response{Message: "version restored successfully"})
+}
+
+// fileArguments reads the pod name and file path query arguments and the session
+// id of a request about a file
+func (h *Handler) fileArguments(w http.ResponseWriter, r *http.Request, op string) (string, string, string, bool) {
+	return h.pathArguments(w, r, op, "filePath")
+}
+
+// pathArguments returns the pod name, the path in the argument pathArg and the session id
+// of a request
+func (h *Handler) pathArguments(w http.ResponseWriter, r *http.Request, op, pathArg string) (string, string, string, bool) {
+	podName := r.URL.Query().Get("podName")
+	if podName == "" {
+		h.logger.Errorf("%s: \"podName\" argument missing", op)
+		jsonhttp.BadRequest(w, &response{Message: op + ": \"podName\" argument missing"})
+		return "", "", "", false
+	}
+	podPath := r.URL.Query().Get(pathArg)
+	if podPath == "" {
+		h.logger.Errorf("%s: \"%s\" argument missing", op, pathArg)
+		jsonhttp.BadRequest(w, &response{Message: op + ": \"" + pathArg + "\" argument missing"})
+		return "", "", "", false
+	}
+
+	sessionId, ok := h.sessionArgument(w, r, op)
+	if !ok {
+		return "", "", "", false
+	}
+	return podName, podPath, sessionId, true
+}
+
+// sessionArgument returns the session id in the cookie of a request
+func (h *Handler) sessionArgument(w http.ResponseWriter, r *http.Request, op string) (string, bool) {
+	sessionId, err := cookie.GetSessionIdFromCookie(r)
+	if err != nil {
+		h.logger.Errorf("%s: invalid cookie: %v", op, err)
+		jsonhttp.BadRequest(w, &response{Message: ErrInvalidCookie.Error()})
+		return "", false
+	}
+	if sessionId == "" {
+		h.logger.Errorf("%s: \"cookie-id\" parameter missing in cookie", op)
+		jsonhttp.BadRequest(w, &response{Message: op + ": \"cookie-id\" parameter missing in cookie"})
+		return "", false
+	}
+	return sessionId, true
+}
+
+func (h *Handler) respondVersionError(w http.ResponseWriter, op string, err error) {
+	h.logger.Errorf("%s: %v", op, err)
+	switch {
+	case errors.Is(err, file.ErrVersionNotFound) || errors.Is(err, file.ErrFileNotPresent) ||
+		errors.Is(err, file.ErrFileNotFound):
+		jsonhttp.NotFound(w, &response{Message: op + ": " + err.Error()})
+	case err == dfs.ErrPodNotOpen || err == dfs.ErrUserNotLoggedIn || err == p.ErrPodNotOpened:
+		jsonhttp.BadRequest(w, &response{Message: op + ": " + err.Error()})
+	default:
+		jsonhttp.InternalServerError(w, &response{Message: op + ": " + err.Error()})
+	}
+}
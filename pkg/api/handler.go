@@ -19,6 +19,7 @@ package api
 import (
 	"context"
 
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/contracts"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/dfs"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
@@ -36,8 +37,8 @@ type Handler struct {
 }
 
 // New
-func New(ctx context.Context, beeApi, cookieDomain, postageBlockId string, whitelistedOrigins []string, ensConfig *contracts.Config, logger logging.Logger) (*Handler, error) {
-	api, err := dfs.NewDfsAPI(beeApi, postageBlockId, ensConfig, logger)
+func New(ctx context.Context, client blockstore.Client, cookieDomain string, whitelistedOrigins []string, ensConfig *contracts.Config, logger logging.Logger) (*Handler, error) {
+	api, err := dfs.NewDfsAPIWithClient(client, ensConfig, logger)
 
 	if err != nil {
 		return nil, err
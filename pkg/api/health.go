@@ -0,0 +1,78 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/bee"
+	"resenje.org/jsonhttp"
+)
+
+const (
+	healthOk       = "ok"
+	healthDegraded = "degraded"
+	healthDown     = "down"
+)
+
+// HealthResponse is the status of the server and its blockstore
+type HealthResponse struct {
+	Status     string            `json:"status"`
+	Blockstore blockstore.Status `json:"blockstore"`
+}
+
+// HealthHandler godoc
+//
+//	@Summary      Health
+//	@Description  Health of the server. It is "degraded" after recent failed bee requests, while journaled writes are pending or when some of the bee nodes are down and "down" when bee is unreachable or the circuit breaker is open
+//	@Tags         health
+//	@Produce      json
+//	@Success      200  {object}  HealthResponse
+//	@Failure      503  {object}  HealthResponse
+//	@Router       /health [get]
+func (h *Handler) HealthHandler(w http.ResponseWriter, _ *http.Request) {
+	status := h.dfsAPI.BlockstoreStatus()
+	resp := &HealthResponse{
+		Status:     healthOk,
+		Blockstore: status,
+	}
+	switch {
+	case !status.Connected || status.Breaker == bee.BreakerOpen:
+		resp.Status = healthDown
+		w.Header().Set("Content-Type", " application/json")
+		jsonhttp.ServiceUnavailable(w, resp)
+		return
+	case degraded(status):
+		resp.Status = healthDegraded
+	}
+	w.Header().Set("Content-Type", " application/json")
+	jsonhttp.OK(w, resp)
+}
+
+// degraded tells if the blockstore or any of its nodes is not fully healthy
+func degraded(status blockstore.Status) bool {
+	if status.Breaker == bee.BreakerHalfOpen || status.Failures > 0 || status.Pending > 0 {
+		return true
+	}
+	for _, n := range status.Nodes {
+		if !n.Connected || n.Breaker == bee.BreakerOpen || degraded(n) {
+			return true
+		}
+	}
+	return false
+}
@@ -38,6 +38,7 @@ import (
 //	@Failure      500  {object}  response
 //	@Router       /v1/kv/count [post]
 func (h *Handler) KVCountHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("kv count: invalid request body type")
@@ -81,7 +82,7 @@ func (h *Handler) KVCountHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	count, err := h.dfsAPI.KVCount(sessionId, podName, name)
+	count, err := h.dfsAPI.KVCount(ctx, sessionId, podName, name)
 	if err != nil {
 		h.logger.Errorf("kv count: %v", err)
 		jsonhttp.InternalServerError(w, &response{Message: "kv count: " + err.Error()})
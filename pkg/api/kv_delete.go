@@ -38,6 +38,7 @@ import (
 //	@Failure      500  {object}  response
 //	@Router       /v1/kv/delete [delete]
 func (h *Handler) KVDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("kv delete: invalid request body type")
@@ -81,7 +82,7 @@ func (h *Handler) KVDeleteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.dfsAPI.KVDelete(sessionId, podName, name)
+	err = h.dfsAPI.KVDelete(ctx, sessionId, podName, name)
 	if err != nil {
 		h.logger.Errorf("kv delete: %v", err)
 		jsonhttp.InternalServerError(w, &response{Message: "kv delete: " + err.Error()})
@@ -36,6 +36,7 @@ type KVExportRequest struct {
 //	@Failure      500  {object}  response
 //	@Router       /v1/kv/export [Post]
 func (h *Handler) KVExportHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("kv export: invalid request body type")
@@ -104,7 +105,7 @@ func (h *Handler) KVExportHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	itr, err := h.dfsAPI.KVSeek(sessionId, podName, name, start, end, noOfRows)
+	itr, err := h.dfsAPI.KVSeek(ctx, sessionId, podName, name, start, end, noOfRows)
 	if err != nil {
 		h.logger.Errorf("kv export: %v", err)
 		jsonhttp.InternalServerError(w, &response{Message: "kv export: " + err.Error()})
@@ -116,7 +117,7 @@ func (h *Handler) KVExportHandler(w http.ResponseWriter, r *http.Request) {
 		if itr == nil {
 			break
 		}
-		ok := itr.Next()
+		ok := itr.Next(ctx)
 		if !ok {
 			break
 		}
@@ -45,6 +45,7 @@ import (
 //	@Failure      500  {object}  response
 //	@Router       /v1/kv/loadcsv [Post]
 func (h *Handler) KVLoadCSVHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	podName := r.FormValue("podName")
 	if podName == "" {
 		h.logger.Errorf("kv loadcsv: \"podName\" argument missing")
@@ -131,7 +132,7 @@ func (h *Handler) KVLoadCSVHandler(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 
-			err = batch.Put(collection.CSVHeaderKey, []byte(record), false, memory)
+			err = batch.Put(ctx, collection.CSVHeaderKey, []byte(record), false, memory)
 			if err != nil {
 				h.logger.Errorf("kv loadcsv: error adding header %d: %v", rowCount, err)
 				failureCount++
@@ -144,7 +145,7 @@ func (h *Handler) KVLoadCSVHandler(w http.ResponseWriter, r *http.Request) {
 		}
 
 		key := strings.Split(record, ",")[0]
-		err = batch.Put(key, []byte(record), false, memory)
+		err = batch.Put(ctx, key, []byte(record), false, memory)
 		if err != nil {
 			h.logger.Errorf("kv loadcsv: error adding row %d: %v", rowCount, err)
 			failureCount++
@@ -152,7 +153,7 @@ func (h *Handler) KVLoadCSVHandler(w http.ResponseWriter, r *http.Request) {
 		}
 		successCount++
 	}
-	_, err = batch.Write("")
+	_, err = batch.Write(ctx, "")
 	if err != nil {
 		h.logger.Errorf("kv loadcsv: %v", err)
 		jsonhttp.InternalServerError(w, &response{Message: "kv loadcsv: " + err.Error()})
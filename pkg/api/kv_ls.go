@@ -49,6 +49,7 @@ type Collection struct {
 //	@Failure      500  {object}  response
 //	@Router       /v1/kv/ls [get]
 func (h *Handler) KVListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	keys, ok := r.URL.Query()["podName"]
 	if !ok || len(keys[0]) < 1 {
 		h.logger.Errorf("kv ls: \"podName\" argument missing")
@@ -70,7 +71,7 @@ func (h *Handler) KVListHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	collections, err := h.dfsAPI.KVList(sessionId, podName)
+	collections, err := h.dfsAPI.KVList(ctx, sessionId, podName)
 	if err != nil {
 		h.logger.Errorf("kv ls: %v", err)
 		jsonhttp.InternalServerError(w, &response{Message: "kv ls: " + err.Error()})
@@ -47,6 +47,7 @@ type KVTableRequest struct {
 //	@Failure      500  {object}  response
 //	@Router       /v1/kv/new [post]
 func (h *Handler) KVCreateHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("kv create: invalid request body type")
@@ -109,7 +110,7 @@ func (h *Handler) KVCreateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.dfsAPI.KVCreate(sessionId, podName, name, indexType)
+	err = h.dfsAPI.KVCreate(ctx, sessionId, podName, name, indexType)
 	if err != nil {
 		h.logger.Errorf("kv create: %v", err)
 		jsonhttp.InternalServerError(w, &response{Message: "kv create: " + err.Error()})
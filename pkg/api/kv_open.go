@@ -38,6 +38,7 @@ import (
 //	@Failure      500  {object}  response
 //	@Router       /v1/kv/open [post]
 func (h *Handler) KVOpenHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("kv open: invalid request body type")
@@ -81,7 +82,7 @@ func (h *Handler) KVOpenHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.dfsAPI.KVOpen(sessionId, podName, name)
+	err = h.dfsAPI.KVOpen(ctx, sessionId, podName, name)
 	if err != nil {
 		h.logger.Errorf("kv open: %v", err)
 		jsonhttp.InternalServerError(w, &response{Message: "kv open: " + err.Error()})
@@ -67,6 +67,7 @@ type KVResponseRaw struct {
 //	@Failure      500  {object}  response
 //	@Router       /v1/kv/entry/put [post]
 func (h *Handler) KVPutHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("kv put: invalid request body type")
@@ -124,7 +125,7 @@ func (h *Handler) KVPutHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.dfsAPI.KVPut(sessionId, podName, name, key, []byte(value))
+	err = h.dfsAPI.KVPut(ctx, sessionId, podName, name, key, []byte(value))
 	if err != nil {
 		h.logger.Errorf("kv put: %v", err)
 		jsonhttp.InternalServerError(w, &response{Message: "kv put: " + err.Error()})
@@ -149,6 +150,7 @@ func (h *Handler) KVPutHandler(w http.ResponseWriter, r *http.Request) {
 //	@Failure      500  {object}  response
 //	@Router       /v1/kv/entry/get [get]
 func (h *Handler) KVGetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	keys, ok := r.URL.Query()["podName"]
 	if !ok || len(keys[0]) < 1 {
 		h.logger.Errorf("kv get: \"podName\" argument missing")
@@ -201,7 +203,7 @@ func (h *Handler) KVGetHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	columns, data, err := h.dfsAPI.KVGet(sessionId, podName, name, key)
+	columns, data, err := h.dfsAPI.KVGet(ctx, sessionId, podName, name, key)
 	if err != nil {
 		h.logger.Errorf("kv get: %v", err)
 		if err == collection.ErrEntryNotFound {
@@ -241,6 +243,7 @@ func (h *Handler) KVGetHandler(w http.ResponseWriter, r *http.Request) {
 //	@Failure      500  {object}  response
 //	@Router       /v1/kv/entry/get-data [get]
 func (h *Handler) KVGetDataHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	keys, ok := r.URL.Query()["podName"]
 	if !ok || len(keys[0]) < 1 {
 		h.logger.Errorf("kv get: \"podName\" argument missing")
@@ -312,7 +315,7 @@ func (h *Handler) KVGetDataHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	columns, data, err := h.dfsAPI.KVGet(sessionId, podName, name, key)
+	columns, data, err := h.dfsAPI.KVGet(ctx, sessionId, podName, name, key)
 	if err != nil {
 		h.logger.Errorf("kv get: %v", err)
 		if err == collection.ErrEntryNotFound {
@@ -354,6 +357,7 @@ func (h *Handler) KVGetDataHandler(w http.ResponseWriter, r *http.Request) {
 //	@Failure      500  {object}  response
 //	@Router       /v1/kv/entry/del [delete]
 func (h *Handler) KVDelHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("kv delete: invalid request body type")
@@ -404,7 +408,7 @@ func (h *Handler) KVDelHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err = h.dfsAPI.KVDel(sessionId, podName, name, key)
+	_, err = h.dfsAPI.KVDel(ctx, sessionId, podName, name, key)
 	if err != nil {
 		h.logger.Errorf("kv del: %v", err)
 		jsonhttp.InternalServerError(w, "kv del: "+err.Error())
@@ -429,6 +433,7 @@ func (h *Handler) KVDelHandler(w http.ResponseWriter, r *http.Request) {
 //	@Failure      500  {object}  response
 //	@Router       /v1/kv/entry/present [get]
 func (h *Handler) KVPresentHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	keys, ok := r.URL.Query()["podName"]
 	if !ok || len(keys[0]) < 1 {
 		h.logger.Errorf("kv get: \"podName\" argument missing")
@@ -482,7 +487,7 @@ func (h *Handler) KVPresentHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	w.Header().Set("Content-Type", "application/json")
 
-	_, _, err = h.dfsAPI.KVGet(sessionId, podName, name, key)
+	_, _, err = h.dfsAPI.KVGet(ctx, sessionId, podName, name, key)
 	if err != nil {
 		jsonhttp.OK(w, &PresentResponse{
 			Present: false,
@@ -46,6 +46,7 @@ const (
 //	@Failure      500  {object}  response
 //	@Router       /v1/kv/seek [Post]
 func (h *Handler) KVSeekHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("kv seek: invalid request body type")
@@ -108,7 +109,7 @@ func (h *Handler) KVSeekHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err = h.dfsAPI.KVSeek(sessionId, podName, name, start, end, noOfRows)
+	_, err = h.dfsAPI.KVSeek(ctx, sessionId, podName, name, start, end, noOfRows)
 	if err != nil {
 		h.logger.Errorf("kv seek: %v", err)
 		jsonhttp.InternalServerError(w, &response{Message: "kv seek: " + err.Error()})
@@ -133,6 +134,7 @@ func (h *Handler) KVSeekHandler(w http.ResponseWriter, r *http.Request) {
 //	@Failure      500  {object}  response
 //	@Router       /v1/kv/seek/next [Post]
 func (h *Handler) KVGetNextHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	keys, ok := r.URL.Query()["podName"]
 	if !ok || len(keys[0]) < 1 {
 		h.logger.Errorf("kv get_next: \"podName\" argument missing")
@@ -174,7 +176,7 @@ func (h *Handler) KVGetNextHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	columns, key, data, err := h.dfsAPI.KVGetNext(sessionId, podName, name)
+	columns, key, data, err := h.dfsAPI.KVGetNext(ctx, sessionId, podName, name)
 	if err != nil && !errors.Is(err, collection.ErrNoNextElement) {
 		h.logger.Errorf("kv get_next: %v", err)
 		jsonhttp.InternalServerError(w, "kv get_next: "+err.Error())
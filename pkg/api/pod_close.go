@@ -45,6 +45,7 @@ type PodNameRequest struct {
 //	@Failure      500  {object}  response
 //	@Router       /v1/pod/close [post]
 func (h *Handler) PodCloseHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("pod close: invalid request body type")
@@ -80,7 +81,7 @@ func (h *Handler) PodCloseHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// close pod
-	err = h.dfsAPI.ClosePod(podName, sessionId)
+	err = h.dfsAPI.ClosePod(ctx, podName, sessionId)
 	if err != nil {
 		if err == dfs.ErrPodNotOpen || err == dfs.ErrUserNotLoggedIn ||
 			err == p.ErrPodNotOpened {
@@ -40,6 +40,7 @@ import (
 //	@Failure      500  {object}  response
 //	@Router       /v1/pod/delete [delete]
 func (h *Handler) PodDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("pod delete: invalid request body type")
@@ -77,7 +78,7 @@ func (h *Handler) PodDeleteHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// delete pod
-	err = h.dfsAPI.DeletePod(podName, sessionId)
+	err = h.dfsAPI.DeletePod(ctx, podName, sessionId)
 	if err != nil {
 		if err == dfs.ErrUserNotLoggedIn {
 			h.logger.Errorf("delete pod: %v", err)
@@ -36,6 +36,7 @@ type PodForkFromReferenceRequest struct {
 //	@Failure      500  {object}  response
 //	@Router       /v1/pod/fork [post]
 func (h *Handler) PodForkHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("pod fork: invalid request body type")
@@ -79,7 +80,7 @@ func (h *Handler) PodForkHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.dfsAPI.ForkPod(pod, forkName, sessionId)
+	err = h.dfsAPI.ForkPod(ctx, pod, forkName, sessionId)
 	if err != nil {
 		if err == dfs.ErrUserNotLoggedIn ||
 			err == p.ErrInvalidPodName {
@@ -110,6 +111,7 @@ func (h *Handler) PodForkHandler(w http.ResponseWriter, r *http.Request) {
 //	@Failure      500  {object}  response
 //	@Router       /v1/pod/fork-from-reference [post]
 func (h *Handler) PodForkFromReferenceHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("pod fork: invalid request body type")
@@ -141,7 +143,7 @@ func (h *Handler) PodForkFromReferenceHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	err = h.dfsAPI.ForkPodFromRef(forkName, podReq.Reference, sessionId)
+	err = h.dfsAPI.ForkPodFromRef(ctx, forkName, podReq.Reference, sessionId)
 	if err != nil {
 		if err == dfs.ErrUserNotLoggedIn ||
 			err == p.ErrInvalidPodName {
@@ -0,0 +1,101 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"resenje.org/jsonhttp"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/cookie"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/dfs"
+	p "github.com/fairdatasociety/fairOS-dfs/pkg/pod"
+)
+
+// PodGCRequest is the request to garbage collect a pod
+type PodGCRequest struct {
+	PodName string `json:"podName,omitempty"`
+	DryRun  bool   `json:"dryRun,omitempty"`
+}
+
+// PodGCHandler godoc
+//
+//	@Summary      Garbage collect pod
+//	@Description  PodGCHandler is the api handler to unpin the content of a pod that is no longer referenced
+//	@Tags         pod
+//	@Accept       json
+//	@Produce      json
+//	@Param	      pod_request body PodGCRequest true "pod name and dry run"
+//	@Param	      Cookie header string true "cookie parameter"
+//	@Success      200  {object}  p.GCReport
+//	@Failure      400  {object}  response
+//	@Failure      500  {object}  response
+//	@Router       /v1/pod/gc [post]
+func (h *Handler) PodGCHandler(w http.ResponseWriter, r *http.Request) {
+	contentType := r.Header.Get("Content-Type")
+	if contentType != jsonContentType {
+		h.logger.Errorf("pod gc: invalid request body type")
+		jsonhttp.BadRequest(w, &response{Message: "pod gc: invalid request body type"})
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	var podReq PodGCRequest
+	err := decoder.Decode(&podReq)
+	if err != nil {
+		h.logger.Errorf("pod gc: could not decode arguments")
+		jsonhttp.BadRequest(w, &response{Message: "pod gc: could not decode arguments"})
+		return
+	}
+	podName := podReq.PodName
+	if podName == "" {
+		h.logger.Errorf("pod gc: \"podName\" argument missing")
+		jsonhttp.BadRequest(w, &response{Message: "pod gc: \"podName\" argument missing"})
+		return
+	}
+	// get values from cookie
+	sessionId, err := cookie.GetSessionIdFromCookie(r)
+	if err != nil {
+		h.logger.Errorf("pod gc: invalid cookie: %v", err)
+		jsonhttp.BadRequest(w, &response{Message: ErrInvalidCookie.Error()})
+		return
+	}
+	if sessionId == "" {
+		h.logger.Errorf("pod gc: \"cookie-id\" parameter missing in cookie")
+		jsonhttp.BadRequest(w, &response{Message: "pod gc: \"cookie-id\" parameter missing in cookie"})
+		return
+	}
+
+	report, err := h.dfsAPI.PodGC(r.Context(), podName, sessionId, podReq.DryRun)
+	if err != nil {
+		if err == dfs.ErrPodNotOpen || err == dfs.ErrUserNotLoggedIn ||
+			err == p.ErrInvalidPodName ||
+			err == p.ErrTooLongPodName ||
+			err == p.ErrPodNotOpened ||
+			err == p.ErrGCSharedPod ||
+			err == p.ErrGCRunning {
+			h.logger.Errorf("pod gc: %v", err)
+			jsonhttp.BadRequest(w, &response{Message: "pod gc: " + err.Error()})
+			return
+		}
+		h.logger.Errorf("pod gc: %v", err)
+		jsonhttp.InternalServerError(w, &response{Message: "pod gc: " + err.Error()})
+		return
+	}
+	jsonhttp.OK(w, report)
+}
@@ -45,6 +45,7 @@ type PodListResponse struct {
 //	@Failure      500  {object}  response
 //	@Router       /v1/pod/ls [get]
 func (h *Handler) PodListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	// get values from cookie
 	sessionId, err := cookie.GetSessionIdFromCookie(r)
 	if err != nil {
@@ -59,7 +60,7 @@ func (h *Handler) PodListHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// fetch pods and list them
-	pods, sharedPods, err := h.dfsAPI.ListPods(sessionId)
+	pods, sharedPods, err := h.dfsAPI.ListPods(ctx, sessionId)
 	if err != nil {
 		if err == dfs.ErrUserNotLoggedIn ||
 			err == pod.ErrPodNotOpened {
@@ -22,10 +22,18 @@ import (
 
 	"github.com/fairdatasociety/fairOS-dfs/pkg/cookie"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/dfs"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
 	p "github.com/fairdatasociety/fairOS-dfs/pkg/pod"
 	"resenje.org/jsonhttp"
 )
 
+// PodCreateRequest is the request to create a pod
+type PodCreateRequest struct {
+	PodName string `json:"podName,omitempty"`
+	// FeedType is "epoch" or "sequence", an epoch pod is created if it is empty
+	FeedType string `json:"feedType,omitempty"`
+}
+
 // PodCreateHandler godoc
 //
 //	@Summary      Create pod
@@ -33,13 +41,14 @@ import (
 //	@Tags         pod
 //	@Accept       json
 //	@Produce      json
-//	@Param	      pod_request body PodNameRequest true "pod name and user password"
+//	@Param	      pod_request body PodCreateRequest true "pod name and feed type"
 //	@Param	      Cookie header string true "cookie parameter"
 //	@Success      201  {object}  response
 //	@Failure      400  {object}  response
 //	@Failure      500  {object}  response
 //	@Router       /v1/pod/new [post]
 func (h *Handler) PodCreateHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("pod new: invalid request body type")
@@ -48,7 +57,7 @@ func (h *Handler) PodCreateHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	decoder := json.NewDecoder(r.Body)
-	var podReq PodNameRequest
+	var podReq PodCreateRequest
 	err := decoder.Decode(&podReq)
 	if err != nil {
 		h.logger.Errorf("pod new: could not decode arguments")
@@ -63,6 +72,13 @@ func (h *Handler) PodCreateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	feedType, err := feed.ParseType(podReq.FeedType)
+	if err != nil {
+		h.logger.Errorf("pod new: %v", err)
+		jsonhttp.BadRequest(w, &response{Message: "pod new: " + err.Error()})
+		return
+	}
+
 	// get values from cookie
 	sessionId, err := cookie.GetSessionIdFromCookie(r)
 	if err != nil {
@@ -77,7 +93,7 @@ func (h *Handler) PodCreateHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// create pod
-	_, err = h.dfsAPI.CreatePod(pod, sessionId)
+	_, err = h.dfsAPI.CreatePodWithFeedType(ctx, pod, feedType, sessionId)
 	if err != nil {
 		if err == dfs.ErrUserNotLoggedIn ||
 			err == p.ErrInvalidPodName ||
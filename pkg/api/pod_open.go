@@ -45,6 +45,7 @@ type PodOpenResponse struct {
 //	@Failure      500  {object}  response
 //	@Router       /v1/pod/open [post]
 func (h *Handler) PodOpenHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("pod open: invalid request body type")
@@ -81,7 +82,7 @@ func (h *Handler) PodOpenHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// open pod
-	_, err = h.dfsAPI.OpenPod(pod, sessionId)
+	_, err = h.dfsAPI.OpenPod(ctx, pod, sessionId)
 	if err != nil {
 		if err == dfs.ErrUserNotLoggedIn ||
 			err == p.ErrInvalidPodName {
@@ -22,6 +22,7 @@ import (
 //	@Failure      500  {object}  response
 //	@Router       /v1/pod/present [get]
 func (h *Handler) PodPresentHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	keys, ok := r.URL.Query()["podName"]
 	if !ok || len(keys[0]) < 1 {
 		h.logger.Errorf("doc ls: \"podName\" argument missing")
@@ -47,7 +48,7 @@ func (h *Handler) PodPresentHandler(w http.ResponseWriter, r *http.Request) {
 		jsonhttp.BadRequest(w, &response{Message: "pod open: \"cookie-id\" parameter missing in cookie"})
 		return
 	}
-	if h.dfsAPI.IsPodExist(podName, sessionId) {
+	if h.dfsAPI.IsPodExist(ctx, podName, sessionId) {
 		jsonhttp.OK(w, &PresentResponse{
 			Present: true,
 		})
@@ -48,6 +48,7 @@ type PodSharingReference struct {
 //	@Failure      500  {object}  response
 //	@Router       /v1/pod/share [post]
 func (h *Handler) PodShareHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("pod share: invalid request body type")
@@ -90,7 +91,7 @@ func (h *Handler) PodShareHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// fetch pod stat
-	sharingRef, err := h.dfsAPI.PodShare(pod, sharedPodName, sessionId)
+	sharingRef, err := h.dfsAPI.PodShare(ctx, pod, sharedPodName, sessionId)
 	if err != nil {
 		if err == dfs.ErrUserNotLoggedIn ||
 			err == p.ErrInvalidPodName {
@@ -123,6 +124,7 @@ func (h *Handler) PodShareHandler(w http.ResponseWriter, r *http.Request) {
 //	@Failure      500  {object}  response
 //	@Router       /v1/pod/receiveinfo [get]
 func (h *Handler) PodReceiveInfoHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	keys, ok := r.URL.Query()["sharingRef"]
 	if !ok || len(keys[0]) < 1 {
 		h.logger.Errorf("pod receive info: \"sharingRef\" argument missing")
@@ -157,7 +159,7 @@ func (h *Handler) PodReceiveInfoHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	shareInfo, err := h.dfsAPI.PodReceiveInfo(sessionId, ref)
+	shareInfo, err := h.dfsAPI.PodReceiveInfo(ctx, sessionId, ref)
 	if err != nil {
 		h.logger.Errorf("pod receive info: %v", err)
 		jsonhttp.InternalServerError(w, "pod receive info: "+err.Error())
@@ -183,6 +185,7 @@ func (h *Handler) PodReceiveInfoHandler(w http.ResponseWriter, r *http.Request)
 //	@Failure      500  {object}  response
 //	@Router       /v1/pod/receive [get]
 func (h *Handler) PodReceiveHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	keys, ok := r.URL.Query()["sharingRef"]
 	if !ok || len(keys[0]) < 1 {
 		h.logger.Errorf("pod receive: \"sharingRef\" argument missing")
@@ -223,7 +226,7 @@ func (h *Handler) PodReceiveHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pi, err := h.dfsAPI.PodReceive(sessionId, sharedPodName, ref)
+	pi, err := h.dfsAPI.PodReceive(ctx, sessionId, sharedPodName, ref)
 	if err != nil {
 		h.logger.Errorf("pod receive: %v", err)
 		jsonhttp.InternalServerError(w, "pod receive: "+err.Error())
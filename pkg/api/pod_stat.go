@@ -30,6 +30,7 @@ import (
 type PodStatResponse struct {
 	PodName    string `json:"podName"`
 	PodAddress string `json:"address"`
+	FeedType   string `json:"feedType"`
 }
 
 // PodStatHandler godoc
@@ -90,5 +91,6 @@ func (h *Handler) PodStatHandler(w http.ResponseWriter, r *http.Request) {
 	jsonhttp.OK(w, &PodStatResponse{
 		PodName:    stat.PodName,
 		PodAddress: stat.PodAddress,
+		FeedType:   stat.FeedType,
 	})
 }
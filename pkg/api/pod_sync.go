@@ -41,6 +41,7 @@ import (
 //	@Failure      500  {object}  response
 //	@Router       /v1/pod/sync [post]
 func (h *Handler) PodSyncHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("pod sync: invalid request body type")
@@ -75,7 +76,7 @@ func (h *Handler) PodSyncHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	// fetch pods and list them
-	err = h.dfsAPI.SyncPod(podName, sessionId)
+	err = h.dfsAPI.SyncPod(ctx, podName, sessionId)
 	if err != nil {
 		if err == dfs.ErrPodNotOpen || err == dfs.ErrUserNotLoggedIn ||
 			err == p.ErrInvalidPodName ||
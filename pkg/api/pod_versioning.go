@@ -0,0 +1,155 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"resenje.org/jsonhttp"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/cookie"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/dfs"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/file"
+	p "github.com/fairdatasociety/fairOS-dfs/pkg/pod"
+)
+
+// PodVersioningRequest sets the version policy of a pod
+type PodVersioningRequest struct {
+	PodName string `json:"podName,omitempty"`
+	file.VersionPolicy
+}
+
+// PodVersioningHandler godoc
+//
+//	@Summary      Set file versioning of a pod
+//	@Description  PodVersioningHandler is the api handler to set if the previous versions of the files of a pod are kept, and how many or for how many days
+//	@Tags         pod
+//	@Accept       json
+//	@Produce      json
+//	@Param	      pod_request body PodVersioningRequest true "pod name and version policy"
+//	@Param	      Cookie header string true "cookie parameter"
+//	@Success      200  {object}  response
+//	@Failure      400  {object}  response
+//	@Failure      500  {object}  response
+//	@Router       /v1/pod/versioning [post]
+func (h *Handler) PodVersioningHandler(w http.ResponseWriter, r *http.Request) {
+	contentType := r.Header.Get("Content-Type")
+	if contentType != jsonContentType {
+		h.logger.Errorf("pod versioning: invalid request body type")
+		jsonhttp.BadRequest(w, &response{Message: "pod versioning: invalid request body type"})
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	var podReq PodVersioningRequest
+	err := decoder.Decode(&podReq)
+	if err != nil {
+		h.logger.Errorf("pod versioning: could not decode arguments")
+		jsonhttp.BadRequest(w, &response{Message: "pod versioning: could not decode arguments"})
+		return
+	}
+	if podReq.PodName == "" {
+		h.logger.Errorf("pod versioning: \"podName\" argument missing")
+		jsonhttp.BadRequest(w, &response{Message: "pod versioning: \"podName\" argument missing"})
+		return
+	}
+	if podReq.KeepVersions < 0 || podReq.KeepDays < 0 {
+		h.logger.Errorf("pod versioning: negative retention")
+		jsonhttp.BadRequest(w, &response{Message: "pod versioning: \"keepVersions\" and \"keepDays\" cannot be negative"})
+		return
+	}
+
+	// get values from cookie
+	sessionId, err := cookie.GetSessionIdFromCookie(r)
+	if err != nil {
+		h.logger.Errorf("pod versioning: invalid cookie: %v", err)
+		jsonhttp.BadRequest(w, &response{Message: ErrInvalidCookie.Error()})
+		return
+	}
+	if sessionId == "" {
+		h.logger.Errorf("pod versioning: \"cookie-id\" parameter missing in cookie")
+		jsonhttp.BadRequest(w, &response{Message: "pod versioning: \"cookie-id\" parameter missing in cookie"})
+		return
+	}
+
+	err = h.dfsAPI.SetVersionPolicy(r.Context(), podReq.PodName, sessionId, podReq.VersionPolicy)
+	if err != nil {
+		if err == dfs.ErrPodNotOpen || err == dfs.ErrUserNotLoggedIn ||
+			err == p.ErrPodNotOpened {
+			h.logger.Errorf("pod versioning: %v", err)
+			jsonhttp.BadRequest(w, &response{Message: "pod versioning: " + err.Error()})
+			return
+		}
+		h.logger.Errorf("pod versioning: %v", err)
+		jsonhttp.InternalServerError(w, &response{Message: "pod versioning: " + err.Error()})
+		return
+	}
+
+	w.Header().Set("Content-Type", " application/json")
+	jsonhttp.OK(w, &response{Message: "pod versioning set successfully"})
+}
+
+// PodVersioningGetHandler godoc
+//
+//	@Summary      File versioning of a pod
+//	@Description  PodVersioningGetHandler is the api handler to show the version policy of a pod
+//	@Tags         pod
+//	@Produce      json
+//	@Param	      podName query string true "pod name"
+//	@Param	      Cookie header string true "cookie parameter"
+//	@Success      200  {object}  file.VersionPolicy
+//	@Failure      400  {object}  response
+//	@Failure      500  {object}  response
+//	@Router       /v1/pod/versioning [get]
+func (h *Handler) PodVersioningGetHandler(w http.ResponseWriter, r *http.Request) {
+	podName := r.URL.Query().Get("podName")
+	if podName == "" {
+		h.logger.Errorf("pod versioning: \"podName\" argument missing")
+		jsonhttp.BadRequest(w, &response{Message: "pod versioning: \"podName\" argument missing"})
+		return
+	}
+
+	// get values from cookie
+	sessionId, err := cookie.GetSessionIdFromCookie(r)
+	if err != nil {
+		h.logger.Errorf("pod versioning: invalid cookie: %v", err)
+		jsonhttp.BadRequest(w, &response{Message: ErrInvalidCookie.Error()})
+		return
+	}
+	if sessionId == "" {
+		h.logger.Errorf("pod versioning: \"cookie-id\" parameter missing in cookie")
+		jsonhttp.BadRequest(w, &response{Message: "pod versioning: \"cookie-id\" parameter missing in cookie"})
+		return
+	}
+
+	policy, err := h.dfsAPI.GetVersionPolicy(r.Context(), podName, sessionId)
+	if err != nil {
+		if err == dfs.ErrPodNotOpen || err == dfs.ErrUserNotLoggedIn ||
+			err == p.ErrPodNotOpened {
+			h.logger.Errorf("pod versioning: %v", err)
+			jsonhttp.BadRequest(w, &response{Message: "pod versioning: " + err.Error()})
+			return
+		}
+		h.logger.Errorf("pod versioning: %v", err)
+		jsonhttp.InternalServerError(w, &response{Message: "pod versioning: " + err.Error()})
+		return
+	}
+
+	w.Header().Set("Content-Type", " application/json")
+	jsonhttp.OK(w, policy)
+}
@@ -0,0 +1,55 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore"
+	"resenje.org/jsonhttp"
+)
+
+// PostageResponse lists the postage batches used for the uploads
+type PostageResponse struct {
+	Batches []blockstore.PostageBatch `json:"batches"`
+}
+
+// PostageHandler godoc
+//
+//	@Summary      Postage batches
+//	@Description  Utilization and time to live of the postage batches that pay for the uploads. The active batch stamps new uploads and exhausted batches are not used any more
+//	@Tags         postage
+//	@Produce      json
+//	@Success      200  {object}  PostageResponse
+//	@Failure      500  {object}  response
+//	@Failure      501  {object}  response
+//	@Router       /v1/postage [get]
+func (h *Handler) PostageHandler(w http.ResponseWriter, r *http.Request) {
+	batches, err := h.dfsAPI.PostageBatches(r.Context())
+	if err != nil {
+		h.logger.Errorf("postage: %v", err)
+		if errors.Is(err, blockstore.ErrPostageUnsupported) {
+			jsonhttp.NotImplemented(w, &response{Message: "postage: " + err.Error()})
+			return
+		}
+		jsonhttp.InternalServerError(w, &response{Message: "postage: " + err.Error()})
+		return
+	}
+	w.Header().Set("Content-Type", " application/json")
+	jsonhttp.OK(w, &PostageResponse{Batches: batches})
+}
@@ -0,0 +1,193 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/fairdatasociety/fairOS-dfs/cmd/common"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/dfs"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/dir"
+	p "github.com/fairdatasociety/fairOS-dfs/pkg/pod"
+	"resenje.org/jsonhttp"
+)
+
+// SymlinkRequest creates or removes a symbolic link
+type SymlinkRequest struct {
+	PodName  string `json:"podName,omitempty"`
+	LinkPath string `json:"linkPath,omitempty"`
+	Target   string `json:"target,omitempty"`
+}
+
+// DirectorySymlinkHandler godoc
+//
+//	@Summary      Create a symbolic link
+//	@Description  DirectorySymlinkHandler is the api handler to create a symbolic link to a file or a directory. The target is a path in the pod, relative to the directory of the link if it does not start with "/", and does not need to exist.
+//	@Tags         dir
+//	@Accept       json
+//	@Produce      json
+//	@Param	      symlink_request body SymlinkRequest true "pod name, link path and target"
+//	@Param	      Cookie header string true "cookie parameter"
+//	@Success      201  {object}  response
+//	@Failure      400  {object}  response
+//	@Failure      404  {object}  response
+//	@Failure      500  {object}  response
+//	@Router       /v1/dir/symlink [post]
+func (h *Handler) DirectorySymlinkHandler(w http.ResponseWriter, r *http.Request) {
+	req, sessionId, ok := h.symlinkArguments(w, r, "symlink", true)
+	if !ok {
+		return
+	}
+	err := h.dfsAPI.Symlink(r.Context(), req.PodName, req.Target, req.LinkPath, sessionId)
+	if err != nil {
+		h.respondSymlinkError(w, "symlink", err)
+		return
+	}
+	jsonhttp.Created(w, &response{Message: "symbolic link created successfully"})
+}
+
+// DirectorySymlinkStatHandler godoc
+//
+//	@Summary      Show a symbolic link
+//	@Description  DirectorySymlinkStatHandler is the api handler to get the information of a symbolic link itself, not of its target
+//	@Tags         dir
+//	@Produce      json
+//	@Param	      podName query string true "pod name"
+//	@Param	      linkPath query string true "link path"
+//	@Param	      Cookie header string true "cookie parameter"
+//	@Success      200  {object}  dir.LinkStats
+//	@Failure      400  {object}  response
+//	@Failure      404  {object}  response
+//	@Failure      500  {object}  response
+//	@Router       /v1/dir/symlink [get]
+func (h *Handler) DirectorySymlinkStatHandler(w http.ResponseWriter, r *http.Request) {
+	podName, linkPath, sessionId, ok := h.pathArguments(w, r, "symlink stat", "linkPath")
+	if !ok {
+		return
+	}
+	stat, err := h.dfsAPI.LinkStat(r.Context(), podName, linkPath, sessionId)
+	if err != nil {
+		h.respondSymlinkError(w, "symlink stat", err)
+		return
+	}
+	w.Header().Set("Content-Type", " application/json")
+	jsonhttp.OK(w, stat)
+}
+
+// DirectorySymlinkRemoveHandler godoc
+//
+//	@Summary      Remove a symbolic link
+//	@Description  DirectorySymlinkRemoveHandler is the api handler to remove a symbolic link, its target is left as it is
+//	@Tags         dir
+//	@Accept       json
+//	@Produce      json
+//	@Param	      symlink_request body SymlinkRequest true "pod name and link path"
+//	@Param	      Cookie header string true "cookie parameter"
+//	@Success      200  {object}  response
+//	@Failure      400  {object}  response
+//	@Failure      404  {object}  response
+//	@Failure      500  {object}  response
+//	@Router       /v1/dir/symlink [delete]
+func (h *Handler) DirectorySymlinkRemoveHandler(w http.ResponseWriter, r *http.Request) {
+	req, sessionId, ok := h.symlinkArguments(w, r, "symlink remove", false)
+	if !ok {
+		return
+	}
+	err := h.dfsAPI.RemoveSymlink(r.Context(), req.PodName, req.LinkPath, sessionId)
+	if err != nil {
+		h.respondSymlinkError(w, "symlink remove", err)
+		return
+	}
+	jsonhttp.OK(w, &response{Message: "symbolic link removed successfully"})
+}
+
+// handleWsSymlink creates, shows or removes a symbolic link for a websocket event
+func (h *Handler) handleWsSymlink(ctx context.Context, event common.Event, req *SymlinkRequest, sessionId string) (interface{}, error) {
+	if req.PodName == "" || req.LinkPath == "" {
+		return nil, fmt.Errorf("symlink: \"podName\" and \"linkPath\" arguments are needed")
+	}
+	switch event {
+	case common.DirSymlinkStat:
+		return h.dfsAPI.LinkStat(ctx, req.PodName, req.LinkPath, sessionId)
+	case common.DirSymlinkRemove:
+		err := h.dfsAPI.RemoveSymlink(ctx, req.PodName, req.LinkPath, sessionId)
+		if err != nil {
+			return nil, err
+		}
+		return &response{Message: "symbolic link removed successfully"}, nil
+	}
+	if req.Target == "" {
+		return nil, fmt.Errorf("symlink: \"target\" argument missing")
+	}
+	err := h.dfsAPI.Symlink(ctx, req.PodName, req.Target, req.LinkPath, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	return &response{Message: "symbolic link created successfully"}, nil
+}
+
+// symlinkArguments decodes the request body of a symbolic link change
+func (h *Handler) symlinkArguments(w http.ResponseWriter, r *http.Request, op string, withTarget bool) (*SymlinkRequest, string, bool) {
+	contentType := r.Header.Get("Content-Type")
+	if contentType != jsonContentType {
+		h.logger.Errorf("%s: invalid request body type", op)
+		jsonhttp.BadRequest(w, &response{Message: op + ": invalid request body type"})
+		return nil, "", false
+	}
+	req := &SymlinkRequest{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		h.logger.Errorf("%s: could not decode arguments", op)
+		jsonhttp.BadRequest(w, &response{Message: op + ": could not decode arguments"})
+		return nil, "", false
+	}
+	args := [][2]string{{"podName", req.PodName}, {"linkPath", req.LinkPath}}
+	if withTarget {
+		args = append(args, [2]string{"target", req.Target})
+	}
+	for _, arg := range args {
+		if arg[1] == "" {
+			h.logger.Errorf("%s: \"%s\" argument missing", op, arg[0])
+			jsonhttp.BadRequest(w, &response{Message: op + ": \"" + arg[0] + "\" argument missing"})
+			return nil, "", false
+		}
+	}
+	sessionId, ok := h.sessionArgument(w, r, op)
+	if !ok {
+		return nil, "", false
+	}
+	return req, sessionId, true
+}
+
+func (h *Handler) respondSymlinkError(w http.ResponseWriter, op string, err error) {
+	h.logger.Errorf("%s: %v", op, err)
+	switch {
+	case errors.Is(err, dir.ErrLinkNotPresent) || errors.Is(err, dir.ErrDirectoryNotPresent):
+		jsonhttp.NotFound(w, &response{Message: op + ": " + err.Error()})
+	case errors.Is(err, dir.ErrInvalidLinkTarget) || errors.Is(err, dir.ErrLinkAlreadyPresent) ||
+		errors.Is(err, dir.ErrTooManyLinks) || errors.Is(err, dir.ErrInvalidFileOrDirectoryName) ||
+		errors.Is(err, dir.ErrTooLongDirectoryName) ||
+		err == dfs.ErrPodNotOpen || err == dfs.ErrUserNotLoggedIn || err == p.ErrPodNotOpened:
+		jsonhttp.BadRequest(w, &response{Message: op + ": " + err.Error()})
+	default:
+		jsonhttp.InternalServerError(w, &response{Message: op + ": " + err.Error()})
+	}
+}
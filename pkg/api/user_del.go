@@ -88,6 +88,7 @@ type UserDeleteRequest struct {
 //	@Failure      500  {object}  response
 //	@Router       /v2/user/delete [delete]
 func (h *Handler) UserDeleteV2Handler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("user delete: invalid request body type")
@@ -125,7 +126,7 @@ func (h *Handler) UserDeleteV2Handler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// delete user
-	err = h.dfsAPI.DeleteUserV2(password, sessionId)
+	err = h.dfsAPI.DeleteUserV2(ctx, password, sessionId)
 	if err != nil {
 		if err == u.ErrInvalidUserName ||
 			err == u.ErrInvalidPassword ||
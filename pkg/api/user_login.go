@@ -51,6 +51,7 @@ type UserLoginResponse struct {
 //	@Header	      200  {string}  Set-Cookie "fairos-dfs session"
 //	@Router       /v2/user/login [post]
 func (h *Handler) UserLoginV2Handler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("user login: invalid request body type")
@@ -81,7 +82,7 @@ func (h *Handler) UserLoginV2Handler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// login user
-	ui, nameHash, publicKey, err := h.dfsAPI.LoginUserV2(user, password, "")
+	ui, nameHash, publicKey, err := h.dfsAPI.LoginUserV2(ctx, user, password, "")
 	if err != nil {
 		if errors.Is(err, u.ErrUserNameNotFound) {
 			h.logger.Errorf("user login: %v", err)
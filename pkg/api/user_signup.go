@@ -54,6 +54,7 @@ type UserSignupResponse struct {
 //	@Failure      500  {object}  response
 //	@Router       /v2/user/signup [post]
 func (h *Handler) UserSignupV2Handler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	contentType := r.Header.Get("Content-Type")
 	if contentType != jsonContentType {
 		h.logger.Errorf("user signup: invalid request body type")
@@ -85,7 +86,7 @@ func (h *Handler) UserSignupV2Handler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// create user
-	address, createdMnemonic, nameHash, publicKey, ui, err := h.dfsAPI.CreateUserV2(user, password, mnemonic, "")
+	address, createdMnemonic, nameHash, publicKey, ui, err := h.dfsAPI.CreateUserV2(ctx, user, password, mnemonic, "")
 	if err != nil {
 		if err == u.ErrUserAlreadyPresent {
 			h.logger.Errorf("user signup: %v", err)
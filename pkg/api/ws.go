@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,12 +10,14 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/dustin/go-humanize"
 	"github.com/fairdatasociety/fairOS-dfs/cmd/common"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/collection"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/dir"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/file"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
@@ -34,6 +37,7 @@ var (
 
 // WebsocketHandler
 func (h *Handler) WebsocketHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	upgrader := websocket.Upgrader{} // use default options
 	upgrader.CheckOrigin = func(r *http.Request) bool {
 		origin := r.Header.Get("Origin")
@@ -51,16 +55,41 @@ func (h *Handler) WebsocketHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	defer conn.Close()
 
-	err = h.handleEvents(conn)
+	err = h.handleEvents(ctx, conn)
 	if err != nil {
 		h.logger.Errorf("Error during handling event:", err)
 		return
 	}
 }
 
-func (h *Handler) handleEvents(conn *websocket.Conn) error {
+func (h *Handler) handleEvents(ctx context.Context, conn *websocket.Conn) error {
 	defer conn.Close()
 
+	// the watches write to the connection too
+	var writeMu sync.Mutex
+	write := func(messageType int, data []byte) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		if err := conn.SetWriteDeadline(time.Now().Add(writeDeadline)); err != nil {
+			return err
+		}
+		return conn.WriteMessage(messageType, data)
+	}
+	watches := make(map[string]context.CancelFunc)
+	defer func() {
+		for _, cancel := range watches {
+			cancel()
+		}
+	}()
+	startWatch := func(id string, event common.Event, watcher *feed.Watcher, notice WatchEventResponse) {
+		if cancel, ok := watches[id]; ok {
+			cancel()
+		}
+		watchCtx, cancel := context.WithCancel(ctx)
+		watches[id] = cancel
+		go h.runWatch(watchCtx, watcher, id, event, notice, write)
+	}
+
 	err := conn.SetReadDeadline(time.Now().Add(readDeadline))
 	if err != nil {
 		h.logger.Debugf("ws event handler: set read deadline failed on connection : %v", err)
@@ -80,10 +109,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				h.logger.Debug("stopping server")
 				return
 			case <-ticker.C:
-				if err := conn.SetWriteDeadline(time.Now().Add(writeDeadline)); err != nil {
-					return
-				}
-				if err := conn.WriteMessage(websocket.PingMessage, []byte{}); err != nil {
+				if err := write(websocket.PingMessage, []byte{}); err != nil {
 					h.logger.Debugf("ws event handler: failed to send ping: %v", err)
 					h.logger.Error("ws event handler: failed to send ping")
 					return
@@ -131,10 +157,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 		if err != nil {
 			return
 		}
-		if err := conn.SetWriteDeadline(time.Now().Add(writeDeadline)); err != nil {
-			return
-		}
-		if err := conn.WriteMessage(websocket.TextMessage, response.Marshal()); err != nil {
+		if err := write(websocket.TextMessage, response.Marshal()); err != nil {
 			h.logger.Debugf("ws event handler: failed to write error response: %v", err)
 			h.logger.Error("ws event handler: failed to write error response")
 			return
@@ -159,10 +182,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 		if err != nil {
 			h.logger.Debugf("ws event handler: failed to read request: %v", err)
 			h.logger.Error("ws event handler: failed to read request")
-			if err := conn.SetWriteDeadline(time.Now().Add(writeDeadline)); err != nil {
-				return err
-			}
-			return conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, err.Error()))
+			return write(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, err.Error()))
 		}
 		res.Id = req.Id
 		res.Event = req.Event
@@ -183,7 +203,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				respondWithError(res, err)
 				continue
 			}
-			ui, nameHash, publicKey, err := h.dfsAPI.LoginUserV2(loginRequest.UserName, loginRequest.Password, "")
+			ui, nameHash, publicKey, err := h.dfsAPI.LoginUserV2(ctx, loginRequest.UserName, loginRequest.Password, "")
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -293,7 +313,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				respondWithError(res, err)
 				continue
 			}
-			err = h.dfsAPI.DeleteUserV2(request.Password, sessionID)
+			err = h.dfsAPI.DeleteUserV2(ctx, request.Password, sessionID)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -349,7 +369,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				respondWithError(res, err)
 				continue
 			}
-			pi, err := h.dfsAPI.PodReceive(sessionID, request.PodName, ref)
+			pi, err := h.dfsAPI.PodReceive(ctx, sessionID, request.PodName, ref)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -386,7 +406,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				respondWithError(res, err)
 				continue
 			}
-			shareInfo, err := h.dfsAPI.PodReceiveInfo(sessionID, ref)
+			shareInfo, err := h.dfsAPI.PodReceiveInfo(ctx, sessionID, ref)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -417,7 +437,12 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				continue
 			}
 
-			_, err = h.dfsAPI.CreatePod(podReq.PodName, sessionID)
+			feedType, err := feed.ParseType(podReq.FeedType)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			_, err = h.dfsAPI.CreatePodWithFeedType(ctx, podReq.PodName, feedType, sessionID)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -450,7 +475,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				continue
 			}
 
-			_, err = h.dfsAPI.OpenPod(podReq.PodName, sessionID)
+			_, err = h.dfsAPI.OpenPod(ctx, podReq.PodName, sessionID)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -483,7 +508,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				continue
 			}
 
-			err = h.dfsAPI.ClosePod(podReq.PodName, sessionID)
+			err = h.dfsAPI.ClosePod(ctx, podReq.PodName, sessionID)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -516,7 +541,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				continue
 			}
 
-			err = h.dfsAPI.SyncPod(podReq.PodName, sessionID)
+			err = h.dfsAPI.SyncPod(ctx, podReq.PodName, sessionID)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -552,7 +577,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 			if sharedPodName == "" {
 				sharedPodName = podReq.PodName
 			}
-			sharingRef, err := h.dfsAPI.PodShare(podReq.PodName, sharedPodName, sessionID)
+			sharingRef, err := h.dfsAPI.PodShare(ctx, podReq.PodName, sharedPodName, sessionID)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -585,7 +610,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				continue
 			}
 
-			err = h.dfsAPI.DeletePod(podReq.PodName, sessionID)
+			err = h.dfsAPI.DeletePod(ctx, podReq.PodName, sessionID)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -617,7 +642,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				respondWithError(res, err)
 				continue
 			}
-			pods, sharedPods, err := h.dfsAPI.ListPods(sessionID)
+			pods, sharedPods, err := h.dfsAPI.ListPods(ctx, sessionID)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -693,7 +718,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				respondWithError(res, err)
 				continue
 			}
-			err = h.dfsAPI.Mkdir(fsReq.PodName, fsReq.DirectoryPath, sessionID)
+			err = h.dfsAPI.Mkdir(ctx, fsReq.PodName, fsReq.DirectoryPath, sessionID)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -725,7 +750,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				respondWithError(res, err)
 				continue
 			}
-			err = h.dfsAPI.RmDir(fsReq.PodName, fsReq.DirectoryPath, sessionID)
+			err = h.dfsAPI.RmDir(ctx, fsReq.PodName, fsReq.DirectoryPath, sessionID)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -757,7 +782,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				respondWithError(res, err)
 				continue
 			}
-			dEntries, fEntries, err := h.dfsAPI.ListDir(fsReq.PodName, fsReq.DirectoryPath, sessionID)
+			dEntries, fEntries, err := h.dfsAPI.ListDir(ctx, fsReq.PodName, fsReq.DirectoryPath, sessionID)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -768,6 +793,9 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 			if fEntries == nil {
 				fEntries = make([]file.Entry, 0)
 			}
+			if !fsReq.Xattrs {
+				withoutXattrs(dEntries, fEntries)
+			}
 			listResponse := &ListFileResponse{
 				Directories: dEntries,
 				Files:       fEntries,
@@ -796,11 +824,14 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				respondWithError(res, err)
 				continue
 			}
-			ds, err := h.dfsAPI.DirectoryStat(fsReq.PodName, fsReq.DirectoryPath, sessionID)
+			ds, err := h.dfsAPI.DirectoryStat(ctx, fsReq.PodName, fsReq.DirectoryPath, sessionID)
 			if err != nil {
 				respondWithError(res, err)
 				continue
 			}
+			if !fsReq.Xattrs {
+				ds.Xattrs = nil
+			}
 
 			messageBytes, err := json.Marshal(ds)
 			if err != nil {
@@ -826,7 +857,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				respondWithError(res, err)
 				continue
 			}
-			present, err := h.dfsAPI.IsDirPresent(fsReq.PodName, fsReq.DirectoryPath, sessionID)
+			present, err := h.dfsAPI.IsDirPresent(ctx, fsReq.PodName, fsReq.DirectoryPath, sessionID)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -846,6 +877,39 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				continue
 			}
 			logEventDescription(string(common.DirIsPresent), to, res.StatusCode, h.logger)
+		case common.DirWatch:
+			jsonBytes, err := json.Marshal(req.Params)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			watchReq := &common.FileSystemRequest{}
+			err = json.Unmarshal(jsonBytes, watchReq)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			watcher, err := h.dfsAPI.WatchDir(watchReq.PodName, watchReq.DirectoryPath, sessionID, 0)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			startWatch(req.Id, common.DirWatch, watcher, WatchEventResponse{PodName: watchReq.PodName, DirPath: watchReq.DirectoryPath})
+			message := map[string]interface{}{}
+			message["message"] = "watch started"
+
+			messageBytes, err := json.Marshal(message)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			res.StatusCode = http.StatusOK
+			_, err = res.WriteJson(messageBytes)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			logEventDescription(string(common.DirWatch), to, res.StatusCode, h.logger)
 		//  case common.FileDownloadStream:
 		//	jsonBytes, _ := json.Marshal(req.Params)
 		//	args := make(map[string]string)
@@ -951,7 +1015,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				respondWithError(res, err)
 				continue
 			}
-			data, n, err := h.dfsAPI.DownloadFile(fsReq.PodName, fsReq.Filepath, sessionID)
+			data, n, err := h.dfsAPI.DownloadFile(ctx, fsReq.PodName, fsReq.Filepath, sessionID)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -978,7 +1042,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 			}
 
 			downloadConfirmResponse.StatusCode = http.StatusOK
-			if err := conn.WriteMessage(messageType, downloadConfirmResponse.Marshal()); err != nil {
+			if err := write(messageType, downloadConfirmResponse.Marshal()); err != nil {
 				respondWithError(res, err)
 				continue
 			}
@@ -1027,6 +1091,28 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 			compression := strings.ToLower(fsReq.Compression)
 			contentLength := fsReq.ContentLength
 
+			if streaming && (fsReq.Resumable || fsReq.UploadId != "") {
+				meta, err := h.streamUploadSession(ctx, conn, sessionID, req.Id, fsReq, write)
+				if err != nil {
+					respondWithError(res, err)
+					continue
+				}
+				messageBytes, err := json.Marshal(&UploadResponse{FileName: meta.Name, Message: "uploaded successfully"})
+				if err != nil {
+					respondWithError(res, err)
+					continue
+				}
+				res.StatusCode = http.StatusOK
+				_, err = res.WriteJson(messageBytes)
+				if err != nil {
+					respondWithError(res, err)
+					continue
+				}
+				logEventDescription(string(common.FileUploadStream), to, res.StatusCode, h.logger)
+				// the response is written after the switch
+				break
+			}
+
 			data := &bytes.Buffer{}
 			if streaming {
 				if contentLength == "" || contentLength == "0" {
@@ -1076,7 +1162,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				respondWithError(res, err)
 				continue
 			}
-			err = h.dfsAPI.UploadFile(fsReq.PodName, fileName, sessionID, int64(len(data.Bytes())), data, fsReq.DirPath, compression, uint32(bs), fsReq.Overwrite)
+			err = h.dfsAPI.UploadFile(ctx, fsReq.PodName, fileName, sessionID, int64(len(data.Bytes())), data, fsReq.DirPath, compression, uint32(bs), fsReq.Overwrite)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -1106,7 +1192,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				respondWithError(res, err)
 				continue
 			}
-			sharingRef, err := h.dfsAPI.ShareFile(fsReq.PodName, fsReq.DirectoryPath, fsReq.Destination, sessionID)
+			sharingRef, err := h.dfsAPI.ShareFile(ctx, fsReq.PodName, fsReq.DirectoryPath, fsReq.Destination, sessionID)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -1143,7 +1229,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				respondWithError(res, err)
 				continue
 			}
-			filePath, err := h.dfsAPI.ReceiveFile(fsReq.PodName, fsReq.DirectoryPath, sharingRef, sessionID)
+			filePath, err := h.dfsAPI.ReceiveFile(ctx, fsReq.PodName, fsReq.DirectoryPath, sharingRef, sessionID)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -1180,7 +1266,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				respondWithError(res, err)
 				continue
 			}
-			receiveInfo, err := h.dfsAPI.ReceiveInfo(sessionID, sharingRef)
+			receiveInfo, err := h.dfsAPI.ReceiveInfo(ctx, sessionID, sharingRef)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -1209,7 +1295,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				respondWithError(res, err)
 				continue
 			}
-			err = h.dfsAPI.DeleteFile(fsReq.PodName, fsReq.FilePath, sessionID)
+			err = h.dfsAPI.DeleteFile(ctx, fsReq.PodName, fsReq.FilePath, sessionID)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -1241,11 +1327,14 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				respondWithError(res, err)
 				continue
 			}
-			stat, err := h.dfsAPI.FileStat(fsReq.PodName, fsReq.DirectoryPath, sessionID)
+			stat, err := h.dfsAPI.FileStat(ctx, fsReq.PodName, fsReq.DirectoryPath, sessionID)
 			if err != nil {
 				respondWithError(res, err)
 				continue
 			}
+			if !fsReq.Xattrs {
+				stat.Xattrs = nil
+			}
 			messageBytes, err := json.Marshal(stat)
 			if err != nil {
 				respondWithError(res, err)
@@ -1258,6 +1347,94 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				continue
 			}
 			logEventDescription(string(common.FileStat), to, res.StatusCode, h.logger)
+		case common.FileXattr, common.FileXattrSet, common.FileXattrRemove,
+			common.DirXattr, common.DirXattrSet, common.DirXattrRemove:
+			jsonBytes, err := json.Marshal(req.Params)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			xattrReq := &XattrRequest{}
+			err = json.Unmarshal(jsonBytes, xattrReq)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			message, err := h.handleWsXattr(ctx, req.Event, xattrReq, sessionID)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			messageBytes, err := json.Marshal(message)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			res.StatusCode = http.StatusOK
+			_, err = res.WriteJson(messageBytes)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			logEventDescription(string(req.Event), to, res.StatusCode, h.logger)
+		case common.DirSymlink, common.DirSymlinkStat, common.DirSymlinkRemove:
+			jsonBytes, err := json.Marshal(req.Params)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			symlinkReq := &SymlinkRequest{}
+			err = json.Unmarshal(jsonBytes, symlinkReq)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			message, err := h.handleWsSymlink(ctx, req.Event, symlinkReq, sessionID)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			messageBytes, err := json.Marshal(message)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			res.StatusCode = http.StatusOK
+			_, err = res.WriteJson(messageBytes)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			logEventDescription(string(req.Event), to, res.StatusCode, h.logger)
+		case common.FileCopy, common.DirCopy:
+			jsonBytes, err := json.Marshal(req.Params)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			copyReq := &CopyRequest{}
+			err = json.Unmarshal(jsonBytes, copyReq)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			message, err := h.handleWsCopy(ctx, req.Event, copyReq, sessionID)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			messageBytes, err := json.Marshal(message)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			res.StatusCode = http.StatusOK
+			_, err = res.WriteJson(messageBytes)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			logEventDescription(string(req.Event), to, res.StatusCode, h.logger)
 
 		// kv related events
 		case common.KVCreate:
@@ -1288,7 +1465,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				respondWithError(res, fmt.Errorf("kv create: invalid \"indexType\" "))
 				continue
 			}
-			err = h.dfsAPI.KVCreate(sessionID, kvReq.PodName, kvReq.TableName, indexType)
+			err = h.dfsAPI.KVCreate(ctx, sessionID, kvReq.PodName, kvReq.TableName, indexType)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -1321,7 +1498,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				continue
 			}
 
-			collections, err := h.dfsAPI.KVList(sessionID, kvReq.PodName)
+			collections, err := h.dfsAPI.KVList(ctx, sessionID, kvReq.PodName)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -1361,7 +1538,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				continue
 			}
 
-			err = h.dfsAPI.KVOpen(sessionID, kvReq.PodName, kvReq.TableName)
+			err = h.dfsAPI.KVOpen(ctx, sessionID, kvReq.PodName, kvReq.TableName)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -1394,7 +1571,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				continue
 			}
 
-			count, err := h.dfsAPI.KVCount(sessionID, kvReq.PodName, kvReq.TableName)
+			count, err := h.dfsAPI.KVCount(ctx, sessionID, kvReq.PodName, kvReq.TableName)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -1424,7 +1601,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				continue
 			}
 
-			err = h.dfsAPI.KVDelete(sessionID, kvReq.PodName, kvReq.TableName)
+			err = h.dfsAPI.KVDelete(ctx, sessionID, kvReq.PodName, kvReq.TableName)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -1459,7 +1636,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 			presentResponse := &PresentResponse{
 				Present: true,
 			}
-			_, _, err = h.dfsAPI.KVGet(sessionID, kvReq.PodName, kvReq.TableName, kvReq.Key)
+			_, _, err = h.dfsAPI.KVGet(ctx, sessionID, kvReq.PodName, kvReq.TableName, kvReq.Key)
 			if err != nil {
 				presentResponse.Present = false
 			}
@@ -1487,7 +1664,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				respondWithError(res, err)
 				continue
 			}
-			err = h.dfsAPI.KVPut(sessionID, kvReq.PodName, kvReq.TableName, kvReq.Key, []byte(kvReq.Value))
+			err = h.dfsAPI.KVPut(ctx, sessionID, kvReq.PodName, kvReq.TableName, kvReq.Key, []byte(kvReq.Value))
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -1520,7 +1697,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				continue
 			}
 
-			columns, data, err := h.dfsAPI.KVGet(sessionID, kvReq.PodName, kvReq.TableName, kvReq.Key)
+			columns, data, err := h.dfsAPI.KVGet(ctx, sessionID, kvReq.PodName, kvReq.TableName, kvReq.Key)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -1557,7 +1734,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				continue
 			}
 
-			_, err = h.dfsAPI.KVDel(sessionID, kvReq.PodName, kvReq.TableName, kvReq.Key)
+			_, err = h.dfsAPI.KVDel(ctx, sessionID, kvReq.PodName, kvReq.TableName, kvReq.Key)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -1612,7 +1789,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				respondWithError(res, err)
 				continue
 			}
-			_, err = h.dfsAPI.KVSeek(sessionID, kvReq.PodName, kvReq.TableName,
+			_, err = h.dfsAPI.KVSeek(ctx, sessionID, kvReq.PodName, kvReq.TableName,
 				kvReq.StartPrefix, kvReq.EndPrefix, noOfRows)
 			if err != nil {
 				respondWithError(res, err)
@@ -1647,7 +1824,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				continue
 			}
 
-			columns, key, data, err := h.dfsAPI.KVGetNext(sessionID, kvReq.PodName, kvReq.TableName)
+			columns, key, data, err := h.dfsAPI.KVGetNext(ctx, sessionID, kvReq.PodName, kvReq.TableName)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -1674,6 +1851,39 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 			}
 
 			logEventDescription(string(common.KVSeekNext), to, res.StatusCode, h.logger)
+		case common.KVWatch:
+			jsonBytes, err := json.Marshal(req.Params)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			watchReq := &common.KVRequest{}
+			err = json.Unmarshal(jsonBytes, watchReq)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			watcher, err := h.dfsAPI.WatchKVTable(ctx, sessionID, watchReq.PodName, watchReq.TableName, 0)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			startWatch(req.Id, common.KVWatch, watcher, WatchEventResponse{PodName: watchReq.PodName, TableName: watchReq.TableName})
+			message := map[string]interface{}{}
+			message["message"] = "watch started"
+
+			messageBytes, err := json.Marshal(message)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			res.StatusCode = http.StatusOK
+			_, err = res.WriteJson(messageBytes)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			logEventDescription(string(common.KVWatch), to, res.StatusCode, h.logger)
 
 		// doc related events
 		case common.DocCreate:
@@ -1715,7 +1925,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				}
 			}
 
-			err = h.dfsAPI.DocCreate(sessionID, docReq.PodName, docReq.TableName,
+			err = h.dfsAPI.DocCreate(ctx, sessionID, docReq.PodName, docReq.TableName,
 				indexes, docReq.Mutable)
 			if err != nil {
 				respondWithError(res, err)
@@ -1748,7 +1958,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				respondWithError(res, err)
 				continue
 			}
-			collections, err := h.dfsAPI.DocList(sessionID, docReq.PodName)
+			collections, err := h.dfsAPI.DocList(ctx, sessionID, docReq.PodName)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -1790,7 +2000,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				respondWithError(res, err)
 				continue
 			}
-			err = h.dfsAPI.DocOpen(sessionID, docReq.PodName, docReq.TableName)
+			err = h.dfsAPI.DocOpen(ctx, sessionID, docReq.PodName, docReq.TableName)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -1822,7 +2032,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				respondWithError(res, err)
 				continue
 			}
-			count, err := h.dfsAPI.DocCount(sessionID, docReq.PodName, docReq.TableName, docReq.Expression)
+			count, err := h.dfsAPI.DocCount(ctx, sessionID, docReq.PodName, docReq.TableName, docReq.Expression)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -1851,7 +2061,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				respondWithError(res, err)
 				continue
 			}
-			err = h.dfsAPI.DocDelete(sessionID, docReq.PodName, docReq.TableName)
+			err = h.dfsAPI.DocDelete(ctx, sessionID, docReq.PodName, docReq.TableName)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -1894,7 +2104,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				}
 				limitInt = lmt
 			}
-			data, err := h.dfsAPI.DocFind(sessionID, docReq.PodName, docReq.TableName, docReq.Expression, limitInt)
+			data, err := h.dfsAPI.DocFind(ctx, sessionID, docReq.PodName, docReq.TableName, docReq.Expression, limitInt)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -1925,7 +2135,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				respondWithError(res, err)
 				continue
 			}
-			err = h.dfsAPI.DocPut(sessionID, docReq.PodName, docReq.TableName, []byte(docReq.Document))
+			err = h.dfsAPI.DocPut(ctx, sessionID, docReq.PodName, docReq.TableName, []byte(docReq.Document))
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -1957,7 +2167,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				respondWithError(res, err)
 				continue
 			}
-			data, err := h.dfsAPI.DocGet(sessionID, docReq.PodName, docReq.TableName, docReq.ID)
+			data, err := h.dfsAPI.DocGet(ctx, sessionID, docReq.PodName, docReq.TableName, docReq.ID)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -1989,7 +2199,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				respondWithError(res, err)
 				continue
 			}
-			err = h.dfsAPI.DocDel(sessionID, docReq.PodName, docReq.TableName, docReq.ID)
+			err = h.dfsAPI.DocDel(ctx, sessionID, docReq.PodName, docReq.TableName, docReq.ID)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -2034,7 +2244,7 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				respondWithError(res, err)
 				continue
 			}
-			err = h.dfsAPI.DocIndexJson(sessionID, docReq.PodName, docReq.TableName, docReq.FileName)
+			err = h.dfsAPI.DocIndexJson(ctx, sessionID, docReq.PodName, docReq.TableName, docReq.FileName)
 			if err != nil {
 				respondWithError(res, err)
 				continue
@@ -2054,14 +2264,67 @@ func (h *Handler) handleEvents(conn *websocket.Conn) error {
 				continue
 			}
 			logEventDescription(string(common.DocIndexJson), to, res.StatusCode, h.logger)
+		case common.DocWatch:
+			jsonBytes, err := json.Marshal(req.Params)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			watchReq := &common.DocRequest{}
+			err = json.Unmarshal(jsonBytes, watchReq)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			watcher, err := h.dfsAPI.WatchDocDB(ctx, sessionID, watchReq.PodName, watchReq.TableName, 0)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			startWatch(req.Id, common.DocWatch, watcher, WatchEventResponse{PodName: watchReq.PodName, TableName: watchReq.TableName})
+			message := map[string]interface{}{}
+			message["message"] = "watch started"
+
+			messageBytes, err := json.Marshal(message)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			res.StatusCode = http.StatusOK
+			_, err = res.WriteJson(messageBytes)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			logEventDescription(string(common.DocWatch), to, res.StatusCode, h.logger)
+		case common.Unwatch:
+			cancel, ok := watches[req.Id]
+			if !ok {
+				respondWithError(res, fmt.Errorf("no watch with id %s", req.Id))
+				continue
+			}
+			cancel()
+			delete(watches, req.Id)
+			message := map[string]interface{}{}
+			message["message"] = "watch stopped"
+
+			messageBytes, err := json.Marshal(message)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			res.StatusCode = http.StatusOK
+			_, err = res.WriteJson(messageBytes)
+			if err != nil {
+				respondWithError(res, err)
+				continue
+			}
+			logEventDescription(string(common.Unwatch), to, res.StatusCode, h.logger)
 		default:
 			respondWithError(res, fmt.Errorf("unknown event"))
 			continue
 		}
-		if err := conn.SetWriteDeadline(time.Now().Add(readDeadline)); err != nil {
-			return err
-		}
-		if err := conn.WriteMessage(messageType, res.Marshal()); err != nil {
+		if err := write(messageType, res.Marshal()); err != nil {
 			h.logger.Debugf("ws event handler: response: failed to write in connection: %v", err)
 			h.logger.Error("ws event handler: response: failed to write in connection")
 			return err
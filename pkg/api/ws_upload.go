@@ -0,0 +1,120 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fairdatasociety/fairOS-dfs/cmd/common"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/file"
+	"github.com/gorilla/websocket"
+)
+
+// streamPartBlocks is the number of blocks of a resumable stream that are stored as a part
+const streamPartBlocks = 16
+
+// streamUploadSession streams a file into an upload session. A new session is started,
+// unless the request resumes one with its upload id. The client is told the upload id
+// and the offset to stream from, and then sends the rest of the file as binary messages.
+// Every full part is stored as soon as it is received, so a stream that is cut off can be
+// resumed from the last stored part over another connection.
+func (h *Handler) streamUploadSession(ctx context.Context, conn *websocket.Conn, sessionID, id string, fsReq *common.FileRequest, write func(messageType int, data []byte) error) (*file.MetaData, error) {
+	var s *file.UploadSession
+	if fsReq.UploadId == "" {
+		contentLength, err := strconv.ParseInt(fsReq.ContentLength, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("streaming needs \"content_length\"")
+		}
+		bs, err := humanize.ParseBytes(fsReq.BlockSize)
+		if err != nil {
+			return nil, err
+		}
+		compression := strings.ToLower(fsReq.Compression)
+		s, err = h.dfsAPI.CreateUploadSession(ctx, fsReq.PodName, fsReq.FileName, sessionID, contentLength, fsReq.DirPath, compression, uint32(bs))
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		s, err = h.dfsAPI.GetUploadSession(ctx, fsReq.PodName, fsReq.UploadId, sessionID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// tell the client the upload id and where to go on from
+	confirm := common.NewWebsocketResponse()
+	confirm.Event = common.FileUploadStream
+	confirm.Id = id
+	confirm.StatusCode = http.StatusOK
+	messageBytes, err := json.Marshal(newUploadSessionResponse(s))
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	_, err = confirm.WriteJson(messageBytes)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	err = write(websocket.TextMessage, confirm.Marshal())
+	if err != nil {
+		return nil, err
+	}
+
+	offset := s.NextOffset()
+	received := offset
+	partSize := int(s.BlockSize) * streamPartBlocks
+	data := &bytes.Buffer{}
+	for received < s.Size {
+		mt, reader, err := conn.NextReader()
+		if err != nil {
+			return nil, err
+		}
+		if mt != websocket.BinaryMessage {
+			return nil, fmt.Errorf("file content should be as binary message")
+		}
+		n, err := io.Copy(data, reader)
+		if err != nil {
+			return nil, err
+		}
+		received += uint64(n)
+		if received > s.Size {
+			return nil, file.ErrInvalidPart
+		}
+		for data.Len() >= partSize || (received == s.Size && data.Len() > 0) {
+			n := partSize
+			if data.Len() < n {
+				n = data.Len()
+			}
+			part := data.Next(n)
+			_, err = h.dfsAPI.UploadPart(ctx, fsReq.PodName, s.ID, sessionID, offset, bytes.NewReader(part))
+			if err != nil {
+				return nil, err
+			}
+			offset += uint64(n)
+		}
+	}
+	h.logger.Debug("streamed full content")
+	return h.dfsAPI.FinishUploadSession(ctx, fsReq.PodName, s.ID, sessionID, fsReq.Overwrite)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/fairdatasociety/fairOS-dfs/cmd/common"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+	"github.com/gorilla/websocket"
+)
+
+// WatchEventResponse is sent over the websocket for every change of a watched directory
+// or collection, with the id of the request that started the watch
+type WatchEventResponse struct {
+	PodName   string `json:"podName"`
+	DirPath   string `json:"dirPath,omitempty"`
+	TableName string `json:"tableName,omitempty"`
+	Reference string `json:"reference,omitempty"`
+}
+
+// runWatch runs the watcher until the context is done and writes a response for every
+// event it reports
+func (h *Handler) runWatch(ctx context.Context, watcher *feed.Watcher, id string, event common.Event, notice WatchEventResponse, write func(messageType int, data []byte) error) {
+	go watcher.Run(ctx)
+	for ev := range watcher.Events() {
+		notice.Reference = utils.Encode(ev.Reference)
+		messageBytes, err := json.Marshal(notice)
+		if err != nil { // skipcq: TCV-001
+			continue
+		}
+		res := common.NewWebsocketResponse()
+		res.Id = id
+		res.Event = event
+		res.StatusCode = http.StatusOK
+		_, err = res.WriteJson(messageBytes)
+		if err != nil { // skipcq: TCV-001
+			continue
+		}
+		if err := write(websocket.TextMessage, res.Marshal()); err != nil {
+			h.logger.Debugf("ws event handler: failed to write watch event: %v", err)
+			return
+		}
+	}
+}
@@ -0,0 +1,345 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/fairdatasociety/fairOS-dfs/cmd/common"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/dfs"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/dir"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/file"
+	p "github.com/fairdatasociety/fairOS-dfs/pkg/pod"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+	"resenje.org/jsonhttp"
+)
+
+// XattrRequest sets or removes an extended attribute of a file or a directory
+type XattrRequest struct {
+	PodName  string `json:"podName,omitempty"`
+	FilePath string `json:"filePath,omitempty"`
+	DirPath  string `json:"dirPath,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Value    string `json:"value"`
+}
+
+// XattrsResponse lists the extended attributes of a file or a directory
+type XattrsResponse struct {
+	Xattrs map[string]string `json:"xattrs"`
+}
+
+// XattrResponse is an extended attribute of a file or a directory
+type XattrResponse struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// xattrTarget is the kind of entry, file or directory, the extended attributes of a
+// request are about
+type xattrTarget struct {
+	op      string
+	pathArg string
+	list    func(ctx context.Context, podName, podPath, sessionId string) (map[string]string, error)
+	set     func(ctx context.Context, podName, podPath, sessionId, name, value string) error
+	remove  func(ctx context.Context, podName, podPath, sessionId, name string) error
+}
+
+func (h *Handler) fileXattrTarget() *xattrTarget {
+	return &xattrTarget{
+		op:      "file xattr",
+		pathArg: "filePath",
+		list:    h.dfsAPI.FileXattrs,
+		set:     h.dfsAPI.SetFileXattr,
+		remove:  h.dfsAPI.RemoveFileXattr,
+	}
+}
+
+func (h *Handler) dirXattrTarget() *xattrTarget {
+	return &xattrTarget{
+		op:      "dir xattr",
+		pathArg: "dirPath",
+		list:    h.dfsAPI.DirectoryXattrs,
+		set:     h.dfsAPI.SetDirectoryXattr,
+		remove:  h.dfsAPI.RemoveDirectoryXattr,
+	}
+}
+
+// FileXattrHandler godoc
+//
+//	@Summary      Get the extended attributes of a file
+//	@Description  FileXattrHandler is the api handler to list the extended attributes of a file, or to get one of them if "name" is given
+//	@Tags         file
+//	@Produce      json
+//	@Param	      podName query string true "pod name"
+//	@Param	      filePath query string true "file path"
+//	@Param	      name query string false "name of the extended attribute"
+//	@Param	      Cookie header string true "cookie parameter"
+//	@Success      200  {object}  XattrsResponse
+//	@Failure      400  {object}  response
+//	@Failure      404  {object}  response
+//	@Failure      500  {object}  response
+//	@Router       /v1/file/xattr [get]
+func (h *Handler) FileXattrHandler(w http.ResponseWriter, r *http.Request) {
+	h.handleXattrGet(w, r, h.fileXattrTarget())
+}
+
+// FileXattrSetHandler godoc
+//
+//	@Summary      Set an extended attribute of a file
+//	@Description  FileXattrSetHandler is the api handler to set an extended attribute of a file
+//	@Tags         file
+//	@Accept       json
+//	@Produce      json
+//	@Param	      xattr_request body XattrRequest true "pod name, file path, name and value of the attribute"
+//	@Param	      Cookie header string true "cookie parameter"
+//	@Success      200  {object}  response
+//	@Failure      400  {object}  response
+//	@Failure      404  {object}  response
+//	@Failure      500  {object}  response
+//	@Router       /v1/file/xattr [post]
+func (h *Handler) FileXattrSetHandler(w http.ResponseWriter, r *http.Request) {
+	h.handleXattrChange(w, r, h.fileXattrTarget(), false)
+}
+
+// FileXattrRemoveHandler godoc
+//
+//	@Summary      Remove an extended attribute of a file
+//	@Description  FileXattrRemoveHandler is the api handler to remove an extended attribute of a file
+//	@Tags         file
+//	@Accept       json
+//	@Produce      json
+//	@Param	      xattr_request body XattrRequest true "pod name, file path and name of the attribute"
+//	@Param	      Cookie header string true "cookie parameter"
+//	@Success      200  {object}  response
+//	@Failure      400  {object}  response
+//	@Failure      404  {object}  response
+//	@Failure      500  {object}  response
+//	@Router       /v1/file/xattr [delete]
+func (h *Handler) FileXattrRemoveHandler(w http.ResponseWriter, r *http.Request) {
+	h.handleXattrChange(w, r, h.fileXattrTarget(), true)
+}
+
+// DirectoryXattrHandler godoc
+//
+//	@Summary      Get the extended attributes of a directory
+//	@Description  DirectoryXattrHandler is the api handler to list the extended attributes of a directory, or to get one of them if "name" is given
+//	@Tags         dir
+//	@Produce      json
+//	@Param	      podName query string true "pod name"
+//	@Param	      dirPath query string true "dir path"
+//	@Param	      name query string false "name of the extended attribute"
+//	@Param	      Cookie header string true "cookie parameter"
+//	@Success      200  {object}  XattrsResponse
+//	@Failure      400  {object}  response
+//	@Failure      404  {object}  response
+//	@Failure      500  {object}  response
+//	@Router       /v1/dir/xattr [get]
+func (h *Handler) DirectoryXattrHandler(w http.ResponseWriter, r *http.Request) {
+	h.handleXattrGet(w, r, h.dirXattrTarget())
+}
+
+// DirectoryXattrSetHandler godoc
+//
+//	@Summary      Set an extended attribute of a directory
+//	@Description  DirectoryXattrSetHandler is the api handler to set an extended attribute of a directory
+//	@Tags         dir
+//	@Accept       json
+//	@Produce      json
+//	@Param	      xattr_request body XattrRequest true "pod name, dir path, name and value of the attribute"
+//	@Param	      Cookie header string true "cookie parameter"
+//	@Success      200  {object}  response
+//	@Failure      400  {object}  response
+//	@Failure      404  {object}  response
+//	@Failure      500  {object}  response
+//	@Router       /v1/dir/xattr [post]
+func (h *Handler) DirectoryXattrSetHandler(w http.ResponseWriter, r *http.Request) {
+	h.handleXattrChange(w, r, h.dirXattrTarget(), false)
+}
+
+// DirectoryXattrRemoveHandler godoc
+//
+//	@Summary      Remove an extended attribute of a directory
+//	@Description  DirectoryXattrRemoveHandler is the api handler to remove an extended attribute of a directory
+//	@Tags         dir
+//	@Accept       json
+//	@Produce      json
+//	@Param	      xattr_request body XattrRequest true "pod name, dir path and name of the attribute"
+//	@Param	      Cookie header string true "cookie parameter"
+//	@Success      200  {object}  response
+//	@Failure      400  {object}  response
+//	@Failure      404  {object}  response
+//	@Failure      500  {object}  response
+//	@Router       /v1/dir/xattr [delete]
+func (h *Handler) DirectoryXattrRemoveHandler(w http.ResponseWriter, r *http.Request) {
+	h.handleXattrChange(w, r, h.dirXattrTarget(), true)
+}
+
+func (h *Handler) handleXattrGet(w http.ResponseWriter, r *http.Request, t *xattrTarget) {
+	podName, podPath, sessionId, ok := h.pathArguments(w, r, t.op, t.pathArg)
+	if !ok {
+		return
+	}
+	xattrs, err := t.list(r.Context(), podName, podPath, sessionId)
+	if err != nil {
+		h.respondXattrError(w, t.op, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", " application/json")
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		if xattrs == nil {
+			xattrs = map[string]string{}
+		}
+		jsonhttp.OK(w, &XattrsResponse{Xattrs: xattrs})
+		return
+	}
+	value, found := xattrs[name]
+	if !found {
+		h.respondXattrError(w, t.op, utils.ErrXattrNotFound)
+		return
+	}
+	jsonhttp.OK(w, &XattrResponse{Name: name, Value: value})
+}
+
+func (h *Handler) handleXattrChange(w http.ResponseWriter, r *http.Request, t *xattrTarget, remove bool) {
+	contentType := r.Header.Get("Content-Type")
+	if contentType != jsonContentType {
+		h.logger.Errorf("%s: invalid request body type", t.op)
+		jsonhttp.BadRequest(w, &response{Message: t.op + ": invalid request body type"})
+		return
+	}
+	var req XattrRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Errorf("%s: could not decode arguments", t.op)
+		jsonhttp.BadRequest(w, &response{Message: t.op + ": could not decode arguments"})
+		return
+	}
+	podPath := req.FilePath
+	if t.pathArg == "dirPath" {
+		podPath = req.DirPath
+	}
+	for _, arg := range [][2]string{{"podName", req.PodName}, {t.pathArg, podPath}, {"name", req.Name}} {
+		if arg[1] == "" {
+			h.logger.Errorf("%s: \"%s\" argument missing", t.op, arg[0])
+			jsonhttp.BadRequest(w, &response{Message: t.op + ": \"" + arg[0] + "\" argument missing"})
+			return
+		}
+	}
+	sessionId, ok := h.sessionArgument(w, r, t.op)
+	if !ok {
+		return
+	}
+
+	message := "extended attribute set successfully"
+	if remove {
+		message = "extended attribute removed successfully"
+		err = t.remove(r.Context(), req.PodName, podPath, sessionId, req.Name)
+	} else {
+		err = t.set(r.Context(), req.PodName, podPath, sessionId, req.Name, req.Value)
+	}
+	if err != nil {
+		h.respondXattrError(w, t.op, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", " application/json")
+	jsonhttp.OK(w, &response{Message: message})
+}
+
+// handleWsXattr gets, sets or removes extended attributes for a websocket event
+func (h *Handler) handleWsXattr(ctx context.Context, event common.Event, req *XattrRequest, sessionId string) (interface{}, error) {
+	t, podPath := h.fileXattrTarget(), req.FilePath
+	if event == common.DirXattr || event == common.DirXattrSet || event == common.DirXattrRemove {
+		t, podPath = h.dirXattrTarget(), req.DirPath
+	}
+	if req.PodName == "" || podPath == "" {
+		return nil, fmt.Errorf("%s: \"podName\" and \"%s\" arguments are needed", t.op, t.pathArg)
+	}
+	switch event {
+	case common.FileXattrSet, common.DirXattrSet:
+		if req.Name == "" {
+			return nil, fmt.Errorf("%s: \"name\" argument missing", t.op)
+		}
+		err := t.set(ctx, req.PodName, podPath, sessionId, req.Name, req.Value)
+		if err != nil {
+			return nil, err
+		}
+		return &response{Message: "extended attribute set successfully"}, nil
+	case common.FileXattrRemove, common.DirXattrRemove:
+		if req.Name == "" {
+			return nil, fmt.Errorf("%s: \"name\" argument missing", t.op)
+		}
+		err := t.remove(ctx, req.PodName, podPath, sessionId, req.Name)
+		if err != nil {
+			return nil, err
+		}
+		return &response{Message: "extended attribute removed successfully"}, nil
+	}
+	xattrs, err := t.list(ctx, req.PodName, podPath, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name == "" {
+		if xattrs == nil {
+			xattrs = map[string]string{}
+		}
+		return &XattrsResponse{Xattrs: xattrs}, nil
+	}
+	value, found := xattrs[req.Name]
+	if !found {
+		return nil, utils.ErrXattrNotFound
+	}
+	return &XattrResponse{Name: req.Name, Value: value}, nil
+}
+
+// withXattrs tells if the "xattrs" argument of a listing or a stat request asks for the
+// extended attributes
+func withXattrs(r *http.Request) bool {
+	with, _ := strconv.ParseBool(r.URL.Query().Get("xattrs"))
+	return with
+}
+
+// withoutXattrs removes the extended attributes from the entries of a listing
+func withoutXattrs(dEntries []dir.Entry, fEntries []file.Entry) {
+	for i := range dEntries {
+		dEntries[i].Xattrs = nil
+	}
+	for i := range fEntries {
+		fEntries[i].Xattrs = nil
+	}
+}
+
+func (h *Handler) respondXattrError(w http.ResponseWriter, op string, err error) {
+	h.logger.Errorf("%s: %v", op, err)
+	switch {
+	case errors.Is(err, utils.ErrXattrNotFound) || errors.Is(err, file.ErrFileNotPresent) ||
+		errors.Is(err, file.ErrFileNotFound) || errors.Is(err, dir.ErrDirectoryNotPresent):
+		jsonhttp.NotFound(w, &response{Message: op + ": " + err.Error()})
+	case errors.Is(err, utils.ErrInvalidXattrName) || errors.Is(err, utils.ErrXattrsTooLarge) ||
+		err == dfs.ErrPodNotOpen || err == dfs.ErrUserNotLoggedIn || err == p.ErrPodNotOpened:
+		jsonhttp.BadRequest(w, &response{Message: op + ": " + err.Error()})
+	default:
+		jsonhttp.InternalServerError(w, &response{Message: op + ": " + err.Error()})
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blockstore
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// BatchConcurrency is the number of requests sent in parallel by a batch upload that
+// falls back to single uploads
+const BatchConcurrency = 16
+
+// Parallel calls fn for the indexes 0 to n-1 with at most BatchConcurrency calls at a
+// time. It stops at the first error and cancels the context passed to the other calls.
+func Parallel(ctx context.Context, n int, fn func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	workers := make(chan struct{}, BatchConcurrency)
+	for i := 0; i < n; i++ {
+		select {
+		case workers <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+			if err := fn(ctx, i); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(i)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// UploadSOCsParallel uploads the SOCs one by one with parallel UploadSOC calls
+func UploadSOCsParallel(ctx context.Context, c Client, socs []SOC) ([][]byte, error) {
+	addresses := make([][]byte, len(socs))
+	err := Parallel(ctx, len(socs), func(ctx context.Context, i int) error {
+		s := socs[i]
+		addr, err := c.UploadSOC(ctx, s.Owner, s.ID, s.Signature, s.Data)
+		addresses[i] = addr
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return addresses, nil
+}
+
+// UploadChunksParallel uploads the chunks one by one with parallel UploadChunk calls
+func UploadChunksParallel(ctx context.Context, c Client, chs []swarm.Chunk, pin bool) error {
+	return Parallel(ctx, len(chs), func(ctx context.Context, i int) error {
+		_, err := c.UploadChunk(ctx, chs[i], pin)
+		return err
+	})
+}
+
+// UploadBlobsParallel uploads the blobs one by one with parallel UploadBlob calls
+func UploadBlobsParallel(ctx context.Context, c Client, blobs [][]byte, tag uint32, pin, encrypt bool) ([][]byte, error) {
+	addresses := make([][]byte, len(blobs))
+	err := Parallel(ctx, len(blobs), func(ctx context.Context, i int) error {
+		addr, err := c.UploadBlob(ctx, blobs[i], tag, pin, encrypt)
+		addresses[i] = addr
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return addresses, nil
+}
@@ -0,0 +1,196 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bee
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/splitter"
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	chunkStreamUrl         = "/chunks/stream"
+	streamHandshakeTimeout = 10 * time.Second
+	streamCloseTimeout     = time.Second
+)
+
+// UploadSOCs uploads the socs with parallel requests, as bee has no batch endpoint for them
+func (s *Client) UploadSOCs(ctx context.Context, socs []blockstore.SOC) ([][]byte, error) {
+	return blockstore.UploadSOCsParallel(ctx, s, socs)
+}
+
+// UploadChunks streams the chunks to bee over a single websocket. If bee, or the proxy in
+// front of it, does not support streaming, the chunks are uploaded with parallel requests.
+// Those requests are not counted in the tag.
+func (s *Client) UploadChunks(ctx context.Context, chs []swarm.Chunk, tag uint32, pin bool) error {
+	if len(chs) == 0 {
+		return nil
+	}
+	if !s.canStream() {
+		return blockstore.UploadChunksParallel(ctx, s, chs, pin)
+	}
+	to := time.Now()
+	sent, err := s.streamChunks(ctx, chs, tag, pin)
+	if err == nil {
+		fields := logrus.Fields{
+			"chunks":   len(chs),
+			"duration": time.Since(to).String(),
+		}
+		s.logger.WithFields(fields).Log(logrus.DebugLevel, "upload chunks: ")
+		return nil
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	fields := logrus.Fields{
+		"sent":  sent,
+		"total": len(chs),
+		"error": err.Error(),
+	}
+	s.logger.WithFields(fields).Log(logrus.DebugLevel, "chunk stream failed, uploading the rest in parallel: ")
+	return blockstore.UploadChunksParallel(ctx, s, chs[sent:], pin)
+}
+
+// UploadBlobs splits the blobs locally and streams all of their chunks to bee in one go.
+// Without streaming support the blobs are uploaded with parallel requests.
+func (s *Client) UploadBlobs(ctx context.Context, blobs [][]byte, tag uint32, pin, encrypt bool) ([][]byte, error) {
+	if !s.canStream() {
+		return blockstore.UploadBlobsParallel(ctx, s, blobs, tag, pin, encrypt)
+	}
+
+	addresses := make([][]byte, len(blobs))
+	seen := make(map[string]bool)
+	var chs []swarm.Chunk
+	for i, data := range blobs {
+		if !pin && s.inBlockCache(s.uploadBlockCache, string(data)) {
+			addresses[i] = s.getFromBlockCache(s.uploadBlockCache, string(data))
+			continue
+		}
+		ref, err := splitter.Split(func(ch swarm.Chunk) error {
+			if !seen[ch.Address().String()] {
+				seen[ch.Address().String()] = true
+				// the pipeline reuses its buffers
+				chs = append(chs, swarm.NewChunk(
+					swarm.NewAddress(append([]byte(nil), ch.Address().Bytes()...)),
+					append([]byte(nil), ch.Data()...)))
+			}
+			return nil
+		}, data, encrypt)
+		if err != nil { // skipcq: TCV-001
+			return nil, err
+		}
+		addresses[i] = ref.Bytes()
+	}
+
+	err := s.UploadChunks(ctx, chs, tag, pin)
+	if err != nil {
+		return nil, err
+	}
+	for i, data := range blobs {
+		if !pin && !s.inBlockCache(s.uploadBlockCache, string(data)) {
+			s.addToBlockCache(s.uploadBlockCache, string(data), addresses[i])
+		}
+	}
+	return addresses, nil
+}
+
+func (s *Client) canStream() bool {
+	return !s.isProxy && atomic.LoadInt32(&s.streamUnsupported) == 0
+}
+
+// streamChunks sends the chunks over the bee chunk stream and returns how many of them
+// bee acknowledged. Bee answers every stored chunk with an empty message, in order.
+func (s *Client) streamChunks(ctx context.Context, chs []swarm.Chunk, tag uint32, pin bool) (int, error) {
+	header := http.Header{}
+	header.Set(swarmPostageBatchId, s.postageBatchID())
+	if pin {
+		header.Set(swarmPinHeader, "true")
+	}
+	if tag > 0 {
+		header.Set(swarmTagHeader, fmt.Sprintf("%d", tag))
+	}
+
+	dialer := &websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: streamHandshakeTimeout,
+	}
+	conn, resp, err := dialer.DialContext(ctx, "ws"+strings.TrimPrefix(s.url, "http")+chunkStreamUrl, header)
+	if err != nil {
+		if resp != nil {
+			switch resp.StatusCode {
+			case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+				atomic.StoreInt32(&s.streamUnsupported, 1)
+			}
+		}
+		return 0, err
+	}
+	defer conn.Close()
+
+	// a cancelled context closes the connection, so that blocked reads and writes return
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	var acked int64
+	ackC := make(chan error, 1)
+	go func() {
+		for range chs {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				ackC <- err
+				return
+			}
+			if len(msg) != 0 { // skipcq: TCV-001
+				ackC <- fmt.Errorf("unexpected chunk stream reply: %s", msg)
+				return
+			}
+			atomic.AddInt64(&acked, 1)
+		}
+		ackC <- nil
+	}()
+
+	for _, ch := range chs {
+		err = conn.WriteMessage(websocket.BinaryMessage, ch.Data())
+		if err != nil {
+			// unblock the reader, it returns the reason if bee closed the stream
+			conn.Close()
+			break
+		}
+	}
+	err = <-ackC
+	if err == nil {
+		_ = conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+			time.Now().Add(streamCloseTimeout))
+	}
+	return int(atomic.LoadInt64(&acked)), err
+}
@@ -0,0 +1,161 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bee_test
+
+import (
+	"context"
+	"crypto/rand"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/cac"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/bee"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+)
+
+func TestBatchUpload(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.New(io.Discard, logrus.ErrorLevel)
+
+	t.Run("stream", func(t *testing.T) {
+		var (
+			mu      sync.Mutex
+			stored  = make(map[string]bool)
+			streams int32
+		)
+		upgrader := websocket.Upgrader{}
+		srv := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/chunks/stream" {
+				t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			atomic.AddInt32(&streams, 1)
+			if r.Header.Get("Swarm-Postage-Batch-Id") != "batch" || r.Header.Get("Swarm-Pin") != "true" {
+				t.Errorf("missing stream headers %v", r.Header)
+			}
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer conn.Close()
+			for {
+				_, msg, err := conn.ReadMessage()
+				if err != nil {
+					return
+				}
+				ch, err := cac.NewWithDataSpan(msg)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				mu.Lock()
+				stored[ch.Address().String()] = true
+				mu.Unlock()
+				err = conn.WriteMessage(websocket.BinaryMessage, []byte{})
+				if err != nil {
+					return
+				}
+			}
+		})
+		client := bee.NewBeeClient(srv.URL, "batch", logger)
+
+		blobs := [][]byte{make([]byte, 3*swarm.ChunkSize), []byte("small blob")}
+		_, err := rand.Read(blobs[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		refs, err := client.UploadBlobs(ctx, blobs, 0, true, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(refs) != 2 {
+			t.Fatalf("expected 2 references, got %d", len(refs))
+		}
+		// three data chunks and their root for the first blob, one chunk for the second one
+		if len(stored) != 5 {
+			t.Fatalf("expected 5 chunks, got %d", len(stored))
+		}
+		for _, ref := range refs {
+			if !stored[swarm.NewAddress(ref).String()] {
+				t.Fatalf("root chunk %x was not uploaded", ref)
+			}
+		}
+
+		ch, err := utils.NewChunkWithSpan([]byte("chunk"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = client.UploadChunks(ctx, []swarm.Chunk{ch}, 0, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !stored[ch.Address().String()] {
+			t.Fatal("chunk was not uploaded")
+		}
+		if streams != 2 {
+			t.Fatalf("expected a stream per batch, got %d", streams)
+		}
+	})
+
+	t.Run("fallback", func(t *testing.T) {
+		var streams, posts int32
+		srv := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/chunks/stream":
+				atomic.AddInt32(&streams, 1)
+				w.WriteHeader(http.StatusNotFound)
+			case r.Method == http.MethodPost && r.URL.Path == "/chunks":
+				atomic.AddInt32(&posts, 1)
+				_, _ = w.Write([]byte(`{"reference":"` + testReference + `"}`))
+			default:
+				t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		})
+		client := bee.NewBeeClient(srv.URL, "batch", logger)
+
+		chs := make([]swarm.Chunk, 20)
+		for i := range chs {
+			ch, err := utils.NewChunkWithSpan([]byte{byte(i)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			chs[i] = ch
+		}
+		for i := 0; i < 2; i++ {
+			err := client.UploadChunks(ctx, chs, 0, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		if posts != 40 {
+			t.Fatalf("expected 40 chunk uploads, got %d", posts)
+		}
+		if streams != 1 {
+			t.Fatalf("unsupported stream should only be tried once, got %d", streams)
+		}
+	})
+}
@@ -30,6 +30,7 @@ import (
 
 	"github.com/ethersphere/bee/pkg/swarm"
 	bmtlegacy "github.com/ethersphere/bmt/legacy"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/sirupsen/logrus"
@@ -48,7 +49,6 @@ const (
 	bytesUploadDownloadUrl = "/bytes"
 	tagsUrl                = "/tags"
 	pinsUrl                = "/pins/"
-	_                      = pinsUrl
 	swarmPinHeader         = "Swarm-Pin"
 	swarmEncryptHeader     = "Swarm-Encrypt"
 	swarmPostageBatchId    = "Swarm-Postage-Batch-Id"
@@ -64,9 +64,12 @@ type Client struct {
 	chunkCache         *lru.Cache
 	uploadBlockCache   *lru.Cache
 	downloadBlockCache *lru.Cache
-	postageBlockId     string
+	postage            *postage
 	logger             logging.Logger
 	isProxy            bool
+	retry              RetryOptions
+	breaker            *breaker
+	streamUnsupported  int32
 }
 
 func hashFunc() hash.Hash {
@@ -96,6 +99,11 @@ type beeError struct {
 
 // NewBeeClient creates a new client which connects to the Swarm bee node to access the Swarm network.
 func NewBeeClient(apiUrl, postageBlockId string, logger logging.Logger) *Client {
+	return NewBeeClientWithRetry(apiUrl, postageBlockId, DefaultRetryOptions(), logger)
+}
+
+// NewBeeClientWithRetry creates a new bee client which retries failed requests as configured.
+func NewBeeClientWithRetry(apiUrl, postageBlockId string, retry RetryOptions, logger logging.Logger) *Client {
 	p := bmtlegacy.NewTreePool(hashFunc, swarm.Branches, bmtlegacy.PoolSize)
 	cache, err := lru.New(chunkCacheSize)
 	if err != nil {
@@ -110,6 +118,11 @@ func NewBeeClient(apiUrl, postageBlockId string, logger logging.Logger) *Client
 		logger.Warningf("could not initialise blockCache. system will be slow")
 	}
 
+	// the batch state is only fetched on request until SetPostage enables the monitoring
+	postageOptions := DefaultPostageOptions(postageBlockId)
+	postageOptions.RefreshInterval = 0
+	postageOptions.DebugApiUrl = apiUrl
+
 	return &Client{
 		url:                apiUrl,
 		client:             createHTTPClient(),
@@ -117,8 +130,10 @@ func NewBeeClient(apiUrl, postageBlockId string, logger logging.Logger) *Client
 		chunkCache:         cache,
 		uploadBlockCache:   uploadBlockCache,
 		downloadBlockCache: downloadBlockCache,
-		postageBlockId:     postageBlockId,
+		postage:            newPostage(postageOptions, logger),
 		logger:             logger,
+		retry:              retry,
+		breaker:            newBreaker(retry.BreakerThreshold, retry.BreakerCooldown),
 	}
 }
 
@@ -150,6 +165,16 @@ func (s *Client) CheckConnection() bool {
 	return s.isProxy
 }
 
+// Status reports the connection and the circuit breaker state
+func (s *Client) Status() blockstore.Status {
+	state, failures := s.breaker.status()
+	return blockstore.Status{
+		Connected: s.CheckConnection(),
+		Breaker:   state,
+		Failures:  failures,
+	}
+}
+
 func (s *Client) checkBee(isProxy bool) (string, error) {
 	url := s.url
 	if isProxy {
@@ -173,26 +198,23 @@ func (s *Client) checkBee(isProxy bool) (string, error) {
 }
 
 // UploadSOC is used construct and send a Single Owner Chunk to the Swarm bee client.
-func (s *Client) UploadSOC(owner, id, signature string, data []byte) (address []byte, err error) {
+func (s *Client) UploadSOC(ctx context.Context, owner, id, signature string, data []byte) (address []byte, err error) {
 	to := time.Now()
 	socResStr := socResource(owner, id, signature)
 	fullUrl := fmt.Sprintf(s.url + socResStr)
 
-	req, err := http.NewRequest(http.MethodPost, fullUrl, bytes.NewBuffer(data))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fullUrl, bytes.NewBuffer(data))
 	if err != nil {
 		return nil, err
 	}
 
-	// the postage block id to store the SOC chunk
-	req.Header.Set(swarmPostageBatchId, s.postageBlockId)
-
 	//req.Header.Set(swarmDeferredUploadHeader, "false")
 
 	// TODO change this in the future when we have some alternative to pin SOC
 	// This is a temporary fix to force soc pinning
 	req.Header.Set(swarmPinHeader, "true")
 
-	response, err := s.client.Do(req)
+	response, err := s.doUpload(req)
 	if err != nil {
 		return nil, err
 	}
@@ -232,10 +254,10 @@ func (s *Client) UploadSOC(owner, id, signature string, data []byte) (address []
 }
 
 // UploadChunk uploads a chunk to Swarm network.
-func (s *Client) UploadChunk(ch swarm.Chunk, pin bool) (address []byte, err error) {
+func (s *Client) UploadChunk(ctx context.Context, ch swarm.Chunk, pin bool) (address []byte, err error) {
 	to := time.Now()
 	fullUrl := fmt.Sprintf(s.url + chunkUploadDownloadUrl)
-	req, err := http.NewRequest(http.MethodPost, fullUrl, bytes.NewBuffer(ch.Data()))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fullUrl, bytes.NewBuffer(ch.Data()))
 	if err != nil {
 		return nil, err
 	}
@@ -244,12 +266,9 @@ func (s *Client) UploadChunk(ch swarm.Chunk, pin bool) (address []byte, err erro
 		req.Header.Set(swarmPinHeader, "true")
 	}
 
-	// the postage block id to store the chunk
-	req.Header.Set(swarmPostageBatchId, s.postageBlockId)
-
 	//req.Header.Set(swarmDeferredUploadHeader, "false")
 
-	response, err := s.client.Do(req)
+	response, err := s.doUpload(req)
 	if err != nil {
 		return nil, err
 	}
@@ -299,14 +318,12 @@ func (s *Client) DownloadChunk(ctx context.Context, address []byte) (data []byte
 
 	path := chunkUploadDownloadUrl + "/" + addrString
 	fullUrl := fmt.Sprintf(s.url + path)
-	req, err := http.NewRequest(http.MethodGet, fullUrl, bytes.NewBuffer(data))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullUrl, bytes.NewBuffer(data))
 	if err != nil {
 		return nil, err
 	}
 
-	req = req.WithContext(ctx)
-
-	response, err := s.client.Do(req)
+	response, err := s.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -333,16 +350,17 @@ func (s *Client) DownloadChunk(ctx context.Context, address []byte) (data []byte
 }
 
 // UploadBlob uploads a binary blob of data to Swarm network. It also optionally pins and encrypts the data.
-func (s *Client) UploadBlob(data []byte, tag uint32, pin, encrypt bool) (address []byte, err error) {
+func (s *Client) UploadBlob(ctx context.Context, data []byte, tag uint32, pin, encrypt bool) (address []byte, err error) {
 	to := time.Now()
 
-	// return the ref if this data is already in swarm
-	if s.inBlockCache(s.uploadBlockCache, string(data)) {
+	// return the ref if this data is already in swarm. A pinned upload is always sent, as
+	// the cached reference can be unpinned when the file that uploaded it is removed.
+	if !pin && s.inBlockCache(s.uploadBlockCache, string(data)) {
 		return s.getFromBlockCache(s.uploadBlockCache, string(data)), nil
 	}
 
 	fullUrl := s.url + bytesUploadDownloadUrl
-	req, err := http.NewRequest(http.MethodPost, fullUrl, bytes.NewBuffer(data))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fullUrl, bytes.NewBuffer(data))
 	if err != nil {
 		return nil, err
 	}
@@ -359,12 +377,9 @@ func (s *Client) UploadBlob(data []byte, tag uint32, pin, encrypt bool) (address
 		req.Header.Set(swarmTagHeader, fmt.Sprintf("%d", tag))
 	}
 
-	// the postage block id to store the blob
-	req.Header.Set(swarmPostageBatchId, s.postageBlockId)
-
 	//req.Header.Set(swarmDeferredUploadHeader, "false")
 
-	response, err := s.client.Do(req)
+	response, err := s.doUpload(req)
 	if err != nil {
 		return nil, err
 	}
@@ -399,7 +414,7 @@ func (s *Client) UploadBlob(data []byte, tag uint32, pin, encrypt bool) (address
 	s.logger.WithFields(fields).Log(logrus.DebugLevel, "upload blob: ")
 
 	// add the data and ref if itis not in cache
-	if !s.inBlockCache(s.uploadBlockCache, string(data)) {
+	if !pin && !s.inBlockCache(s.uploadBlockCache, string(data)) {
 		s.addToBlockCache(s.uploadBlockCache, string(data), resp.Reference.Bytes())
 	}
 
@@ -407,7 +422,7 @@ func (s *Client) UploadBlob(data []byte, tag uint32, pin, encrypt bool) (address
 }
 
 // DownloadBlob downloads a blob of binary data from the Swarm network.
-func (s *Client) DownloadBlob(address []byte) ([]byte, int, error) {
+func (s *Client) DownloadBlob(ctx context.Context, address []byte) ([]byte, int, error) {
 	to := time.Now()
 
 	// return the data if this address is already in cache
@@ -417,13 +432,16 @@ func (s *Client) DownloadBlob(address []byte) ([]byte, int, error) {
 	}
 
 	fullUrl := s.url + bytesUploadDownloadUrl + "/" + addrString
-	req, err := http.NewRequest(http.MethodGet, fullUrl, http.NoBody)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullUrl, http.NoBody)
 	if err != nil {
 		return nil, http.StatusNotFound, err
 	}
 
-	response, err := s.client.Do(req)
+	response, err := s.do(req)
 	if err != nil {
+		if errors.Is(err, ErrCircuitOpen) {
+			return nil, http.StatusServiceUnavailable, err
+		}
 		return nil, http.StatusNotFound, err
 	}
 	defer response.Body.Close()
@@ -459,45 +477,48 @@ func (s *Client) DownloadBlob(address []byte) ([]byte, int, error) {
 }
 
 // DeleteReference unpins a reference so that it will be garbage collected by the Swarm network.
-func (s *Client) DeleteReference(address []byte) error {
-	// TODO uncomment after unpinning is fixed
-	_ = address
-	/*
-		to := time.Now()
-		addrString := swarm.NewAddress(address).String()
+// A reference that is not pinned is not an error.
+func (s *Client) DeleteReference(ctx context.Context, address []byte) error {
+	// gateway proxy does not have pinning api exposed
+	if s.isProxy {
+		return nil
+	}
+	to := time.Now()
+	addrString := swarm.NewAddress(address).String()
+	// an upload of the same data must not get the unpinned reference back
+	s.removeFromBlockCache(s.uploadBlockCache, address)
 
-		fullUrl := s.url + pinsUrl + addrString
-		req, err := http.NewRequest(http.MethodDelete, fullUrl, http.NoBody)
-		if err != nil {
-			return err
-		}
+	fullUrl := s.url + pinsUrl + addrString
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fullUrl, http.NoBody)
+	if err != nil {
+		return err
+	}
+
+	response, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
 
-		response, err := s.client.Do(req)
+	req.Close = true
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusNotFound {
+		respData, err := io.ReadAll(response.Body)
 		if err != nil {
 			return err
 		}
-		defer response.Body.Close()
-
-		req.Close = true
-		if response.StatusCode != http.StatusOK {
-			respData, err := io.ReadAll(response.Body)
-			if err != nil {
-				return err
-			}
-			return fmt.Errorf("failed to unpin reference : %s", respData)
-		}
+		return fmt.Errorf("failed to unpin reference : %s", respData)
+	}
 
-		fields := logrus.Fields{
-			"reference": addrString,
-			"duration":  time.Since(to).String(),
-		}
-		s.logger.WithFields(fields).Log(logrus.DebugLevel, "delete chunk: ")
-	*/
+	fields := logrus.Fields{
+		"reference": addrString,
+		"duration":  time.Since(to).String(),
+	}
+	s.logger.WithFields(fields).Log(logrus.DebugLevel, "delete chunk: ")
 	return nil
 }
 
 // CreateTag creates a tag for given address
-func (s *Client) CreateTag(address []byte) (uint32, error) {
+func (s *Client) CreateTag(ctx context.Context, address []byte) (uint32, error) {
 	// gateway proxy does not have tags api exposed
 	if s.isProxy {
 		return 0, nil
@@ -515,12 +536,12 @@ func (s *Client) CreateTag(address []byte) (uint32, error) {
 			return 0, err
 		}
 	}
-	req, err := http.NewRequest(http.MethodPost, fullUrl, bytes.NewBuffer(data))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fullUrl, bytes.NewBuffer(data))
 	if err != nil {
 		return 0, err
 	}
 
-	response, err := s.client.Do(req)
+	response, err := s.do(req)
 	if err != nil {
 		return 0, err
 	}
@@ -558,7 +579,7 @@ func (s *Client) CreateTag(address []byte) (uint32, error) {
 }
 
 // GetTag gets sync status of a given tag
-func (s *Client) GetTag(tag uint32) (int64, int64, int64, error) {
+func (s *Client) GetTag(ctx context.Context, tag uint32) (int64, int64, int64, error) {
 	// gateway proxy does not have tags api exposed
 	if s.isProxy {
 		return 0, 0, 0, nil
@@ -568,12 +589,12 @@ func (s *Client) GetTag(tag uint32) (int64, int64, int64, error) {
 
 	fullUrl := s.url + tagsUrl + fmt.Sprintf("/%d", tag)
 
-	req, err := http.NewRequest(http.MethodGet, fullUrl, http.NoBody)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullUrl, http.NoBody)
 	if err != nil {
 		return 0, 0, 0, err
 	}
 
-	response, err := s.client.Do(req)
+	response, err := s.do(req)
 	if err != nil {
 		return 0, 0, 0, err
 	}
@@ -655,6 +676,18 @@ func (*Client) addToBlockCache(cache *lru.Cache, key string, value []byte) {
 	}
 }
 
+// removeFromBlockCache removes the entries of the upload cache that hold the reference
+func (*Client) removeFromBlockCache(cache *lru.Cache, value []byte) {
+	if cache == nil {
+		return
+	}
+	for _, key := range cache.Keys() {
+		if v, ok := cache.Peek(key); ok && bytes.Equal(v.([]byte), value) {
+			cache.Remove(key)
+		}
+	}
+}
+
 func (*Client) inBlockCache(cache *lru.Cache, key string) bool {
 	if cache != nil {
 		return cache.Contains(key)
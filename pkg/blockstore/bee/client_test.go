@@ -0,0 +1,103 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bee_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/bee"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	"github.com/sirupsen/logrus"
+)
+
+func TestUploadCache(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.New(io.Discard, logrus.ErrorLevel)
+
+	var (
+		mu      sync.Mutex
+		uploads int
+		pinned  int
+	)
+	srv := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPost:
+			_, _ = io.ReadAll(r.Body)
+			uploads++
+			if r.Header.Get("Swarm-Pin") == "true" {
+				pinned++
+			}
+			_, _ = w.Write([]byte(`{"reference":"` + testReference + `"}`))
+		case http.MethodDelete:
+			pinned--
+		}
+	})
+	counts := func() (int, int) {
+		mu.Lock()
+		defer mu.Unlock()
+		return uploads, pinned
+	}
+	client := bee.NewBeeClient(srv.URL, batchA, logger)
+
+	t.Run("pinned-uploads-are-sent", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			_, err := client.UploadBlob(ctx, []byte("pinned"), 0, true, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		if u, p := counts(); u != 2 || p != 2 {
+			t.Fatalf("every pinned upload should pin, got %d uploads and %d pins", u, p)
+		}
+	})
+
+	t.Run("unpinned-uploads-are-cached", func(t *testing.T) {
+		before, _ := counts()
+		for i := 0; i < 2; i++ {
+			_, err := client.UploadBlob(ctx, []byte("unpinned"), 0, false, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		if u, _ := counts(); u != before+1 {
+			t.Fatalf("expected one upload, got %d", u-before)
+		}
+
+		// the reference is not returned from the cache once it is deleted
+		ref, err := client.UploadBlob(ctx, []byte("unpinned"), 0, false, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = client.DeleteReference(ctx, ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = client.UploadBlob(ctx, []byte("unpinned"), 0, false, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u, _ := counts(); u != before+2 {
+			t.Fatalf("expected the upload to be sent again, got %d uploads", u-before)
+		}
+	})
+}
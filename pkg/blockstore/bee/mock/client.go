@@ -26,6 +26,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
 
 	"github.com/ethersphere/bee/pkg/soc"
@@ -54,7 +55,7 @@ func (*BeeClient) CheckConnection() bool {
 }
 
 // UploadSOC uploads soc into swarm
-func (m *BeeClient) UploadSOC(owner, id, signature string, data []byte) (address []byte, err error) {
+func (m *BeeClient) UploadSOC(_ context.Context, owner, id, signature string, data []byte) (address []byte, err error) {
 	m.storerMu.Lock()
 	defer m.storerMu.Unlock()
 	ch, err := utils.NewChunkWithoutSpan(data)
@@ -88,8 +89,23 @@ func (m *BeeClient) UploadSOC(owner, id, signature string, data []byte) (address
 	return signedChunk.Address().Bytes(), nil
 }
 
+// UploadSOCs uploads the socs one by one
+func (m *BeeClient) UploadSOCs(ctx context.Context, socs []blockstore.SOC) ([][]byte, error) {
+	return blockstore.UploadSOCsParallel(ctx, m, socs)
+}
+
+// UploadChunks uploads the chunks one by one
+func (m *BeeClient) UploadChunks(ctx context.Context, chs []swarm.Chunk, _ uint32, pin bool) error {
+	return blockstore.UploadChunksParallel(ctx, m, chs, pin)
+}
+
+// UploadBlobs uploads the blobs one by one
+func (m *BeeClient) UploadBlobs(ctx context.Context, blobs [][]byte, tag uint32, pin, encrypt bool) ([][]byte, error) {
+	return blockstore.UploadBlobsParallel(ctx, m, blobs, tag, pin, encrypt)
+}
+
 // UploadChunk into swarm
-func (m *BeeClient) UploadChunk(ch swarm.Chunk, _ bool) (address []byte, err error) {
+func (m *BeeClient) UploadChunk(_ context.Context, ch swarm.Chunk, _ bool) (address []byte, err error) {
 	m.storerMu.Lock()
 	defer m.storerMu.Unlock()
 	m.storer[ch.Address().String()] = ch.Data()
@@ -107,7 +123,7 @@ func (m *BeeClient) DownloadChunk(_ context.Context, address []byte) (data []byt
 }
 
 // UploadBlob into swarm
-func (m *BeeClient) UploadBlob(data []byte, tag uint32, _, _ bool) (address []byte, err error) {
+func (m *BeeClient) UploadBlob(_ context.Context, data []byte, tag uint32, _, _ bool) (address []byte, err error) {
 	m.storerMu.Lock()
 	defer m.storerMu.Unlock()
 	address = make([]byte, 32)
@@ -123,7 +139,7 @@ func (m *BeeClient) UploadBlob(data []byte, tag uint32, _, _ bool) (address []by
 }
 
 // DownloadBlob from swarm
-func (m *BeeClient) DownloadBlob(address []byte) ([]byte, int, error) {
+func (m *BeeClient) DownloadBlob(_ context.Context, address []byte) ([]byte, int, error) {
 	m.storerMu.Lock()
 	defer m.storerMu.Unlock()
 	if data, ok := m.storer[swarm.NewAddress(address).String()]; ok {
@@ -133,7 +149,7 @@ func (m *BeeClient) DownloadBlob(address []byte) ([]byte, int, error) {
 }
 
 // DeleteReference unpins chunk in swarm
-func (m *BeeClient) DeleteReference(address []byte) error {
+func (m *BeeClient) DeleteReference(_ context.Context, address []byte) error {
 	m.storerMu.Lock()
 	defer m.storerMu.Unlock()
 	if _, found := m.storer[swarm.NewAddress(address).String()]; found {
@@ -144,7 +160,7 @@ func (m *BeeClient) DeleteReference(address []byte) error {
 }
 
 // CreateTag
-func (m *BeeClient) CreateTag(_ []byte) (uint32, error) {
+func (m *BeeClient) CreateTag(_ context.Context, _ []byte) (uint32, error) {
 	tag := time.Now().UnixNano()
 	m.storerMu.Lock()
 	defer m.storerMu.Unlock()
@@ -153,7 +169,7 @@ func (m *BeeClient) CreateTag(_ []byte) (uint32, error) {
 }
 
 // GetTag
-func (m *BeeClient) GetTag(tag uint32) (int64, int64, int64, error) {
+func (m *BeeClient) GetTag(_ context.Context, tag uint32) (int64, int64, int64, error) {
 	m.storerMu.Lock()
 	defer m.storerMu.Unlock()
 	return m.tagStorer[tag], m.tagStorer[tag], m.tagStorer[tag], nil
@@ -0,0 +1,343 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bee
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	stampsUrl = "/stamps/"
+
+	// DefaultPostageWarnUtilization is the batch utilization that logs a warning
+	DefaultPostageWarnUtilization = 0.8
+	// DefaultPostageMaxUtilization is the batch utilization that switches to the next batch
+	DefaultPostageMaxUtilization = 0.95
+	// DefaultPostageWarnTTL logs a warning when a batch expires sooner
+	DefaultPostageWarnTTL = 24 * time.Hour
+	// DefaultPostageRefreshInterval is how often the batch state is fetched from bee
+	DefaultPostageRefreshInterval = time.Minute
+
+	postageRequestTimeout = 10 * time.Second
+)
+
+// PostageOptions configures the postage batches that the uploads are stamped with
+type PostageOptions struct {
+	// BatchIDs are used in order, the next usable one is selected when the active one is exhausted
+	BatchIDs []string
+	// DebugApiUrl is the bee debug api that reports the batch state, the api url if empty
+	DebugApiUrl string
+	// WarnUtilization is the utilization, from 0 to 1, that logs a warning
+	WarnUtilization float64
+	// MaxUtilization is the utilization, from 0 to 1, at which the batch is not used any more
+	MaxUtilization float64
+	// WarnTTL logs a warning when a batch expires sooner
+	WarnTTL time.Duration
+	// RefreshInterval is how often the batch state is fetched from bee, 0 only fetches it on request
+	RefreshInterval time.Duration
+}
+
+// DefaultPostageOptions returns the postage options that monitor the given batches
+func DefaultPostageOptions(batchIDs ...string) PostageOptions {
+	return PostageOptions{
+		BatchIDs:        batchIDs,
+		WarnUtilization: DefaultPostageWarnUtilization,
+		MaxUtilization:  DefaultPostageMaxUtilization,
+		WarnTTL:         DefaultPostageWarnTTL,
+		RefreshInterval: DefaultPostageRefreshInterval,
+	}
+}
+
+type stampResponse struct {
+	BatchID     string `json:"batchID"`
+	Utilization uint32 `json:"utilization"`
+	Usable      bool   `json:"usable"`
+	Label       string `json:"label"`
+	Depth       uint8  `json:"depth"`
+	BucketDepth uint8  `json:"bucketDepth"`
+	BatchTTL    int64  `json:"batchTTL"`
+	Expired     bool   `json:"expired"`
+}
+
+type postageBatch struct {
+	info    blockstore.PostageBatch
+	fetched bool
+}
+
+// postage selects the batch that uploads are stamped with
+type postage struct {
+	mu         sync.Mutex
+	opts       PostageOptions
+	batches    []*postageBatch
+	active     int
+	refreshed  time.Time
+	refreshing int32
+	logger     logging.Logger
+}
+
+func newPostage(opts PostageOptions, logger logging.Logger) *postage {
+	p := &postage{
+		opts:   opts,
+		logger: logger,
+	}
+	for _, id := range opts.BatchIDs {
+		p.batches = append(p.batches, &postageBatch{
+			info: blockstore.PostageBatch{BatchID: id, TTL: -1},
+		})
+	}
+	return p
+}
+
+// SetPostage replaces the postage batches of the client and starts monitoring them
+func (s *Client) SetPostage(opts PostageOptions) {
+	if opts.DebugApiUrl == "" {
+		opts.DebugApiUrl = s.url
+	}
+	s.postage = newPostage(opts, s.logger)
+}
+
+// PostageBatches fetches the state of the configured postage batches from bee
+func (s *Client) PostageBatches(ctx context.Context) ([]blockstore.PostageBatch, error) {
+	err := s.refreshPostage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.postage.list(), nil
+}
+
+// postageBatchID returns the batch to stamp an upload with, refreshing the batch state
+// in the background when it is due
+func (s *Client) postageBatchID() string {
+	p := s.postage
+	p.mu.Lock()
+	due := p.opts.RefreshInterval > 0 && time.Since(p.refreshed) >= p.opts.RefreshInterval
+	id := ""
+	if len(p.batches) > 0 {
+		id = p.batches[p.active].info.BatchID
+	}
+	p.mu.Unlock()
+
+	if due && atomic.CompareAndSwapInt32(&p.refreshing, 0, 1) {
+		go func() {
+			defer atomic.StoreInt32(&p.refreshing, 0)
+			ctx, cancel := context.WithTimeout(context.Background(), postageRequestTimeout)
+			defer cancel()
+			err := s.refreshPostage(ctx)
+			if err != nil {
+				s.logger.Debugf("postage batch refresh: %v", err)
+			}
+		}()
+	}
+	return id
+}
+
+// doUpload sends an upload stamped with the active batch. When bee reports the batch
+// as full, the upload is sent again with the next batch.
+func (s *Client) doUpload(req *http.Request) (*http.Response, error) {
+	for {
+		id := s.postageBatchID()
+		req.Header.Set(swarmPostageBatchId, id)
+		resp, err := s.do(req)
+		if err != nil || resp.StatusCode != http.StatusPaymentRequired || req.GetBody == nil {
+			return resp, err
+		}
+		if !s.postage.exhausted(id) {
+			return resp, nil
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		req.Body, err = req.GetBody()
+		if err != nil { // skipcq: TCV-001
+			return nil, err
+		}
+	}
+}
+
+// refreshPostage fetches the state of all the batches. It only fails if none of them
+// could be fetched.
+func (s *Client) refreshPostage(ctx context.Context) error {
+	p := s.postage
+	p.mu.Lock()
+	ids := make([]string, len(p.batches))
+	for i, b := range p.batches {
+		ids[i] = b.info.BatchID
+	}
+	p.mu.Unlock()
+
+	var lastErr error
+	stamps := make(map[string]*stampResponse)
+	for _, id := range ids {
+		stamp, err := s.getStamp(ctx, id)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		stamps[id] = stamp
+	}
+	p.update(stamps)
+	if len(stamps) == 0 && lastErr != nil {
+		return lastErr
+	}
+	return nil
+}
+
+func (s *Client) getStamp(ctx context.Context, id string) (*stampResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.postage.opts.DebugApiUrl+stampsUrl+id, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var beeErr *beeError
+		err = json.Unmarshal(data, &beeErr)
+		if err != nil || beeErr.Message == "" {
+			return nil, fmt.Errorf("postage batch %s: %s", id, resp.Status)
+		}
+		return nil, fmt.Errorf("postage batch %s: %s", id, beeErr.Message)
+	}
+	stamp := &stampResponse{}
+	err = json.Unmarshal(data, stamp)
+	if err != nil {
+		return nil, err
+	}
+	return stamp, nil
+}
+
+func (p *postage) update(stamps map[string]*stampResponse) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.refreshed = time.Now()
+	for _, b := range p.batches {
+		stamp, ok := stamps[b.info.BatchID]
+		if !ok {
+			continue
+		}
+		b.fetched = true
+		b.info.Label = stamp.Label
+		b.info.Usable = stamp.Usable
+		b.info.Expired = stamp.Expired
+		b.info.TTL = stamp.BatchTTL
+		b.info.Utilization = 0
+		if stamp.Depth >= stamp.BucketDepth {
+			capacity := math.Pow(2, float64(stamp.Depth-stamp.BucketDepth))
+			b.info.Utilization = float64(stamp.Utilization) / capacity
+		}
+		b.info.Exhausted = stamp.Expired || (p.opts.MaxUtilization > 0 && b.info.Utilization >= p.opts.MaxUtilization)
+
+		warning := p.warning(b.info)
+		if warning != "" && warning != b.info.Warning {
+			fields := logrus.Fields{
+				"batchID":     b.info.BatchID,
+				"utilization": fmt.Sprintf("%.2f", b.info.Utilization),
+				"ttl":         b.info.TTL,
+			}
+			p.logger.WithFields(fields).Log(logrus.WarnLevel, "postage batch: "+warning)
+		}
+		b.info.Warning = warning
+	}
+	p.selectBatch()
+}
+
+func (p *postage) warning(b blockstore.PostageBatch) string {
+	switch {
+	case b.Expired:
+		return "batch expired"
+	case b.Exhausted:
+		return "batch is full"
+	case b.Utilization >= p.opts.WarnUtilization && p.opts.WarnUtilization > 0:
+		return fmt.Sprintf("batch is %.0f%% full", b.Utilization*100)
+	case b.TTL >= 0 && time.Duration(b.TTL)*time.Second < p.opts.WarnTTL:
+		return fmt.Sprintf("batch expires in %s", time.Duration(b.TTL)*time.Second)
+	default:
+		return ""
+	}
+}
+
+// exhausted marks the batch as full after bee refused it. It returns true if there is
+// another batch to upload with.
+func (p *postage) exhausted(id string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, b := range p.batches {
+		if b.info.BatchID == id && !b.info.Exhausted {
+			b.info.Exhausted = true
+			b.info.Warning = "batch is full"
+			p.logger.WithField("batchID", id).Log(logrus.WarnLevel, "postage batch: batch is full")
+		}
+	}
+	p.selectBatch()
+	if len(p.batches) == 0 {
+		return false
+	}
+	return p.usable(p.batches[p.active]) && p.batches[p.active].info.BatchID != id
+}
+
+// selectBatch keeps the active batch while it is usable, otherwise it switches to the next usable one
+func (p *postage) selectBatch() {
+	if len(p.batches) == 0 || p.usable(p.batches[p.active]) {
+		return
+	}
+	for i := 1; i < len(p.batches); i++ {
+		next := (p.active + i) % len(p.batches)
+		if p.usable(p.batches[next]) {
+			fields := logrus.Fields{
+				"from": p.batches[p.active].info.BatchID,
+				"to":   p.batches[next].info.BatchID,
+			}
+			p.logger.WithFields(fields).Log(logrus.WarnLevel, "switching postage batch: ")
+			p.active = next
+			return
+		}
+	}
+	p.logger.Errorf("postage batch: no usable batch left, uploads will fail")
+}
+
+func (*postage) usable(b *postageBatch) bool {
+	return !b.info.Exhausted && (!b.fetched || b.info.Usable)
+}
+
+func (p *postage) list() []blockstore.PostageBatch {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	batches := make([]blockstore.PostageBatch, len(p.batches))
+	for i, b := range p.batches {
+		batches[i] = b.info
+		batches[i].Active = i == p.active
+	}
+	return batches
+}
@@ -0,0 +1,186 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bee_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/bee"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	batchA = strings.Repeat("a", 64)
+	batchB = strings.Repeat("b", 64)
+)
+
+type testStamp struct {
+	BatchID     string `json:"batchID"`
+	Utilization uint32 `json:"utilization"`
+	Usable      bool   `json:"usable"`
+	Depth       uint8  `json:"depth"`
+	BucketDepth uint8  `json:"bucketDepth"`
+	BatchTTL    int64  `json:"batchTTL"`
+}
+
+// newPostageServer serves the stamps and accepts blob uploads with batches that are not full
+func newPostageServer(t *testing.T, stamps map[string]*testStamp, full map[string]bool) (string, *[]string) {
+	var (
+		mu   sync.Mutex
+		used []string
+	)
+	srv := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/stamps/"):
+			stamp, ok := stamps[strings.TrimPrefix(r.URL.Path, "/stamps/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"code":404,"message":"issuer does not exist"}`))
+				return
+			}
+			_ = json.NewEncoder(w).Encode(stamp)
+		case r.Method == http.MethodPost && r.URL.Path == "/bytes":
+			id := r.Header.Get("Swarm-Postage-Batch-Id")
+			mu.Lock()
+			used = append(used, id)
+			mu.Unlock()
+			_, _ = io.ReadAll(r.Body)
+			if full[id] {
+				w.WriteHeader(http.StatusPaymentRequired)
+				_, _ = w.Write([]byte(`{"code":402,"message":"batch is overissued"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"reference":"` + testReference + `"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+	return srv.URL, &used
+}
+
+func activeBatch(batches []blockstore.PostageBatch) string {
+	for _, b := range batches {
+		if b.Active {
+			return b.BatchID
+		}
+	}
+	return ""
+}
+
+func TestPostage(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.New(io.Discard, logrus.ErrorLevel)
+
+	t.Run("switch-on-payment-required", func(t *testing.T) {
+		url, used := newPostageServer(t, nil, map[string]bool{batchA: true})
+		client := bee.NewBeeClient(url, batchA, logger)
+		opts := bee.DefaultPostageOptions(batchA, batchB)
+		opts.RefreshInterval = 0
+		client.SetPostage(opts)
+
+		for i := 0; i < 2; i++ {
+			_, err := client.UploadBlob(ctx, []byte{byte(i)}, 0, false, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		if len(*used) != 3 || (*used)[0] != batchA || (*used)[1] != batchB || (*used)[2] != batchB {
+			t.Fatalf("expected the full batch to be used once, got %v", *used)
+		}
+	})
+
+	t.Run("single-batch-keeps-bee-error", func(t *testing.T) {
+		url, _ := newPostageServer(t, nil, map[string]bool{batchA: true})
+		client := bee.NewBeeClient(url, batchA, logger)
+		_, err := client.UploadBlob(ctx, []byte("full"), 0, false, false)
+		if err == nil || err.Error() != "batch is overissued" {
+			t.Fatalf("expected overissued error, got %v", err)
+		}
+	})
+
+	t.Run("no-batches", func(t *testing.T) {
+		url, _ := newPostageServer(t, nil, map[string]bool{"": true})
+		client := bee.NewBeeClient(url, "", logger)
+		opts := bee.DefaultPostageOptions()
+		opts.RefreshInterval = 0
+		client.SetPostage(opts)
+		_, err := client.UploadBlob(ctx, []byte("full"), 0, false, false)
+		if err == nil || err.Error() != "batch is overissued" {
+			t.Fatalf("expected overissued error, got %v", err)
+		}
+	})
+
+	t.Run("utilization-and-warnings", func(t *testing.T) {
+		stamps := map[string]*testStamp{
+			// 31 of 32 slots of the fullest bucket are used
+			batchA: {BatchID: batchA, Utilization: 31, Usable: true, Depth: 21, BucketDepth: 16, BatchTTL: 86400 * 30},
+			batchB: {BatchID: batchB, Utilization: 26, Usable: true, Depth: 21, BucketDepth: 16, BatchTTL: 3600},
+		}
+		url, used := newPostageServer(t, stamps, nil)
+		client := bee.NewBeeClient(url, batchA, logger)
+		opts := bee.DefaultPostageOptions(batchA, batchB)
+		opts.RefreshInterval = time.Hour
+		client.SetPostage(opts)
+
+		batches, err := client.PostageBatches(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(batches) != 2 {
+			t.Fatalf("expected 2 batches, got %d", len(batches))
+		}
+		if !batches[0].Exhausted || batches[0].Warning != "batch is full" {
+			t.Fatalf("batch over the max utilization should be exhausted, got %+v", batches[0])
+		}
+		if batches[1].Utilization != 26.0/32 || batches[1].TTL != 3600 {
+			t.Fatalf("unexpected batch state %+v", batches[1])
+		}
+		if batches[1].Warning != "batch is 81% full" {
+			t.Fatalf("expected a utilization warning, got %q", batches[1].Warning)
+		}
+		if activeBatch(batches) != batchB {
+			t.Fatalf("expected the second batch to be active, got %s", activeBatch(batches))
+		}
+
+		_, err = client.UploadBlob(ctx, []byte("data"), 0, false, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(*used) != 1 || (*used)[0] != batchB {
+			t.Fatalf("expected the upload to use the second batch, got %v", *used)
+		}
+	})
+
+	t.Run("unknown-batches", func(t *testing.T) {
+		url, _ := newPostageServer(t, map[string]*testStamp{}, nil)
+		client := bee.NewBeeClient(url, batchA, logger)
+		_, err := client.PostageBatches(ctx)
+		if err == nil {
+			t.Fatal("expected error for batches bee does not know")
+		}
+	})
+}
@@ -0,0 +1,261 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bee
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// BreakerClosed lets all the requests through
+	BreakerClosed = "closed"
+	// BreakerOpen fails all the requests without calling bee
+	BreakerOpen = "open"
+	// BreakerHalfOpen lets a single trial request through to check if bee is back
+	BreakerHalfOpen = "half-open"
+
+	defaultMaxRetries       = 3
+	defaultInitialBackoff   = 200 * time.Millisecond
+	defaultMaxBackoff       = 5 * time.Second
+	defaultBreakerThreshold = 5
+	defaultBreakerCooldown  = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned without calling bee while the circuit breaker is open
+var ErrCircuitOpen = errors.New("bee is unavailable, circuit breaker is open")
+
+// RetryOptions configures how failed requests to bee are retried
+type RetryOptions struct {
+	// MaxRetries is the number of retries after the first attempt, 0 disables retries
+	MaxRetries int
+	// InitialBackoff is the wait before the first retry, it doubles with every retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between two retries
+	MaxBackoff time.Duration
+	// BreakerThreshold is the number of consecutive failures that opens the breaker, 0 disables it
+	BreakerThreshold int
+	// BreakerCooldown is how long the breaker stays open before a trial request is let through
+	BreakerCooldown time.Duration
+}
+
+// DefaultRetryOptions returns the retry options used by NewBeeClient
+func DefaultRetryOptions() RetryOptions {
+	return RetryOptions{
+		MaxRetries:       defaultMaxRetries,
+		InitialBackoff:   defaultInitialBackoff,
+		MaxBackoff:       defaultMaxBackoff,
+		BreakerThreshold: defaultBreakerThreshold,
+		BreakerCooldown:  defaultBreakerCooldown,
+	}
+}
+
+// breaker is a consecutive failure circuit breaker
+type breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	state     string
+	failures  int
+	openedAt  time.Time
+	trial     bool
+}
+
+func newBreaker(threshold int, cooldown time.Duration) *breaker {
+	return &breaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		state:     BreakerClosed,
+	}
+}
+
+// allow returns ErrCircuitOpen if the request should not be sent to bee
+func (b *breaker) allow() error {
+	if b.threshold <= 0 {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		b.state = BreakerHalfOpen
+		b.trial = true
+		return nil
+	case BreakerHalfOpen:
+		if b.trial {
+			return ErrCircuitOpen
+		}
+		b.trial = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+func (b *breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.state = BreakerClosed
+	b.trial = false
+}
+
+func (b *breaker) failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.trial = false
+	if b.threshold > 0 && (b.state == BreakerHalfOpen || b.failures >= b.threshold) {
+		b.state = BreakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// release ends a request that tells nothing about the health of bee, like a cancelled one
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+}
+
+func (b *breaker) status() (string, int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == BreakerOpen && time.Since(b.openedAt) >= b.cooldown {
+		return BreakerHalfOpen, b.failures
+	}
+	return b.state, b.failures
+}
+
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota
+	outcomeFailure
+	outcomeNeutral
+)
+
+// classify tells if a request should be retried and how it counts for the breaker
+func classify(req *http.Request, resp *http.Response, err error) (bool, outcome) {
+	if err != nil {
+		if req.Context().Err() != nil {
+			return false, outcomeNeutral
+		}
+		return true, outcomeFailure
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true, outcomeFailure
+	case http.StatusRequestTimeout, http.StatusTooManyRequests:
+		// bee is up, but busy
+		return true, outcomeSuccess
+	default:
+		return false, outcomeSuccess
+	}
+}
+
+// do sends the request to bee, retrying transient failures with a jittered exponential
+// backoff. The response of the last attempt is returned, so callers handle the status
+// codes the same way as with a single attempt.
+func (s *Client) do(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		err := s.breaker.allow()
+		if err != nil {
+			return nil, err
+		}
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil { // skipcq: TCV-001
+				s.breaker.release()
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		resp, err := s.client.Do(req)
+		retry, result := classify(req, resp, err)
+		switch result {
+		case outcomeSuccess:
+			s.breaker.success()
+		case outcomeFailure:
+			s.breaker.failure()
+		default:
+			s.breaker.release()
+		}
+		if !retry || attempt >= s.retry.MaxRetries {
+			return resp, err
+		}
+
+		wait := s.backoff(attempt, resp)
+		fields := logrus.Fields{
+			"method":  req.Method,
+			"url":     req.URL.Path,
+			"attempt": attempt + 1,
+			"wait":    wait.String(),
+		}
+		if err != nil {
+			fields["error"] = err.Error()
+		} else {
+			fields["status"] = resp.StatusCode
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		s.logger.WithFields(fields).Log(logrus.DebugLevel, "retrying bee request: ")
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the wait before the next attempt. Half of the exponential delay is
+// randomised so that many clients failing together do not retry together.
+func (s *Client) backoff(attempt int, resp *http.Response) time.Duration {
+	d := s.retry.InitialBackoff << uint(attempt)
+	if d <= 0 || d > s.retry.MaxBackoff {
+		d = s.retry.MaxBackoff
+	}
+	if d > 1 {
+		d = d/2 + time.Duration(rand.Int63n(int64(d/2)+1)) // skipcq: GSC-G404
+	}
+	if resp != nil {
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			after := time.Duration(secs) * time.Second
+			if after > d {
+				d = after
+			}
+			if d > s.retry.MaxBackoff {
+				d = s.retry.MaxBackoff
+			}
+		}
+	}
+	return d
+}
@@ -0,0 +1,154 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bee_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/bee"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	"github.com/sirupsen/logrus"
+)
+
+const testReference = "0000000000000000000000000000000000000000000000000000000000000001"
+
+func newTestServer(t *testing.T, handler func(w http.ResponseWriter, r *http.Request)) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			_, _ = w.Write([]byte("Ethereum Swarm Bee\n"))
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRetry(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.New(io.Discard, logrus.ErrorLevel)
+	opts := bee.RetryOptions{
+		MaxRetries:       3,
+		InitialBackoff:   time.Millisecond,
+		MaxBackoff:       5 * time.Millisecond,
+		BreakerThreshold: 10,
+		BreakerCooldown:  time.Minute,
+	}
+
+	t.Run("transient-errors-are-retried", func(t *testing.T) {
+		var calls int32
+		srv := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			if string(body) != "payload" {
+				t.Errorf("body not resent on retry: %q", body)
+			}
+			if atomic.AddInt32(&calls, 1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"reference":"` + testReference + `"}`))
+		})
+		client := bee.NewBeeClientWithRetry(srv.URL, "", opts, logger)
+		_, err := client.UploadBlob(ctx, []byte("payload"), 0, false, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if calls != 3 {
+			t.Fatalf("expected 3 calls, got %d", calls)
+		}
+		status := client.Status()
+		if status.Breaker != bee.BreakerClosed || status.Failures != 0 {
+			t.Fatalf("unexpected status %+v", status)
+		}
+	})
+
+	t.Run("client-errors-are-not-retried", func(t *testing.T) {
+		var calls int32
+		srv := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":400,"message":"bad request"}`))
+		})
+		client := bee.NewBeeClientWithRetry(srv.URL, "", opts, logger)
+		_, _, err := client.DownloadBlob(ctx, []byte{1})
+		if err == nil || err.Error() != "bad request" {
+			t.Fatalf("expected bad request, got %v", err)
+		}
+		if calls != 1 {
+			t.Fatalf("expected 1 call, got %d", calls)
+		}
+	})
+
+	t.Run("circuit-breaker", func(t *testing.T) {
+		var calls int32
+		var healthy int32
+		srv := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			if atomic.LoadInt32(&healthy) == 0 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			_, _ = w.Write([]byte("chunk"))
+		})
+		client := bee.NewBeeClientWithRetry(srv.URL, "", bee.RetryOptions{
+			BreakerThreshold: 2,
+			BreakerCooldown:  50 * time.Millisecond,
+		}, logger)
+
+		for i := 0; i < 2; i++ {
+			_, err := client.DownloadChunk(ctx, []byte{1})
+			if err == nil {
+				t.Fatal("expected error")
+			}
+		}
+		_, err := client.DownloadChunk(ctx, []byte{1})
+		if !errors.Is(err, bee.ErrCircuitOpen) {
+			t.Fatalf("expected circuit open, got %v", err)
+		}
+		if calls != 2 {
+			t.Fatalf("open breaker should not call bee, got %d calls", calls)
+		}
+		if client.Status().Breaker != bee.BreakerOpen {
+			t.Fatalf("unexpected status %+v", client.Status())
+		}
+
+		atomic.StoreInt32(&healthy, 1)
+		time.Sleep(60 * time.Millisecond)
+		if client.Status().Breaker != bee.BreakerHalfOpen {
+			t.Fatalf("unexpected status %+v", client.Status())
+		}
+		data, err := client.DownloadChunk(ctx, []byte{1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != "chunk" {
+			t.Fatalf("unexpected data %s", data)
+		}
+		if client.Status().Breaker != bee.BreakerClosed {
+			t.Fatalf("unexpected status %+v", client.Status())
+		}
+	})
+}
@@ -0,0 +1,582 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/cac"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+const (
+	blobsDir  = "blobs"
+	chunksDir = "chunks"
+	dirMode   = 0700
+	fileMode  = 0600
+
+	// DefaultFeedTTL is how long a single owner chunk is served from the cache
+	DefaultFeedTTL = 10 * time.Second
+
+	socCacheSize = 4096
+)
+
+// Options configures the cache
+type Options struct {
+	// Dir is the directory where the cached content is kept
+	Dir string
+	// MaxSize is the maximum size of the cached content on disk in bytes
+	MaxSize int64
+	// Policy is the eviction policy, one of lru, lfu or fifo
+	Policy string
+	// FeedTTL is how long single owner chunks (feed updates) are cached
+	FeedTTL time.Duration
+}
+
+// Stats are the statistics of the cache
+type Stats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+	Entries   int    `json:"entries"`
+	Size      int64  `json:"size"`
+	MaxSize   int64  `json:"maxSize"`
+}
+
+type socEntry struct {
+	data    []byte
+	expires time.Time
+}
+
+// Client is a blockstore.Client that keeps downloaded content in a size bounded
+// on-disk cache in front of another blockstore.Client. Content addressed chunks and
+// blobs never change, so they stay in the cache until they are evicted. Single owner
+// chunks can be overwritten by the owner, so they are only kept for a short time.
+//
+// Blobs are cached as the backend returns them, which is the plaintext for encrypted
+// references. They are stored encrypted with a key derived from their reference, under
+// a name that does not give away the reference, so the cache directory alone does not
+// reveal them. Chunks are stored as they are, encrypted chunks stay encrypted.
+type Client struct {
+	backend blockstore.Client
+	dir     string
+	maxSize int64
+	feedTTL time.Duration
+	logger  logging.Logger
+
+	mu      sync.Mutex
+	entries map[string]*entry
+	queue   *evictionQueue
+	size    int64
+	clock   uint64
+	stats   Stats
+
+	socCache *lru.Cache
+}
+
+// NewClient creates a new caching client over the given backend. Content already present
+// in the cache directory from an earlier run is indexed and served again.
+func NewClient(backend blockstore.Client, opts Options, logger logging.Logger) (*Client, error) {
+	less, err := policyLessFunc(opts.Policy)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range []string{blobsDir, chunksDir} {
+		err := os.MkdirAll(filepath.Join(opts.Dir, d), dirMode)
+		if err != nil {
+			return nil, err
+		}
+	}
+	socCache, err := lru.New(socCacheSize)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	feedTTL := opts.FeedTTL
+	if feedTTL == 0 {
+		feedTTL = DefaultFeedTTL
+	}
+	c := &Client{
+		backend:  backend,
+		dir:      opts.Dir,
+		maxSize:  opts.MaxSize,
+		feedTTL:  feedTTL,
+		logger:   logger,
+		entries:  make(map[string]*entry),
+		queue:    &evictionQueue{less: less},
+		socCache: socCache,
+	}
+	err = c.load()
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Stats returns the hit/miss statistics of the cache
+func (c *Client) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.stats
+	s.Entries = len(c.entries)
+	s.Size = c.size
+	s.MaxSize = c.maxSize
+	return s
+}
+
+// CheckConnection checks the connection of the backend
+func (c *Client) CheckConnection() bool {
+	return c.backend.CheckConnection()
+}
+
+// Status reports the status of the backend
+func (c *Client) Status() blockstore.Status {
+	if r, ok := c.backend.(blockstore.StatusReporter); ok {
+		return r.Status()
+	}
+	return blockstore.Status{Connected: c.backend.CheckConnection()}
+}
+
+// PostageBatches reports the postage batches of the backend
+func (c *Client) PostageBatches(ctx context.Context) ([]blockstore.PostageBatch, error) {
+	if r, ok := c.backend.(blockstore.PostageReporter); ok {
+		return r.PostageBatches(ctx)
+	}
+	return nil, blockstore.ErrPostageUnsupported
+}
+
+// UploadSOC uploads a soc to the backend and drops any cached copy of it
+func (c *Client) UploadSOC(ctx context.Context, owner, id, signature string, data []byte) (address []byte, err error) {
+	address, err = c.backend.UploadSOC(ctx, owner, id, signature, data)
+	if err != nil {
+		return nil, err
+	}
+	c.socCache.Remove(swarm.NewAddress(address).String())
+	return address, nil
+}
+
+// UploadChunk uploads a chunk to the backend
+func (c *Client) UploadChunk(ctx context.Context, ch swarm.Chunk, pin bool) (address []byte, err error) {
+	return c.backend.UploadChunk(ctx, ch, pin)
+}
+
+// UploadBlob uploads a blob to the backend and keeps the data in the cache, as
+// it is very likely to be read back soon.
+func (c *Client) UploadBlob(ctx context.Context, data []byte, tag uint32, pin, encrypt bool) (address []byte, err error) {
+	address, err = c.backend.UploadBlob(ctx, data, tag, pin, encrypt)
+	if err != nil {
+		return nil, err
+	}
+	c.putBlob(address, data)
+	return address, nil
+}
+
+// UploadSOCs uploads the socs to the backend and drops their cached copies
+func (c *Client) UploadSOCs(ctx context.Context, socs []blockstore.SOC) ([][]byte, error) {
+	addresses, err := c.backend.UploadSOCs(ctx, socs)
+	if err != nil {
+		return nil, err
+	}
+	for _, address := range addresses {
+		c.socCache.Remove(swarm.NewAddress(address).String())
+	}
+	return addresses, nil
+}
+
+// UploadChunks uploads the chunks to the backend
+func (c *Client) UploadChunks(ctx context.Context, chs []swarm.Chunk, tag uint32, pin bool) error {
+	return c.backend.UploadChunks(ctx, chs, tag, pin)
+}
+
+// UploadBlobs uploads the blobs to the backend and keeps them in the cache like UploadBlob
+func (c *Client) UploadBlobs(ctx context.Context, blobs [][]byte, tag uint32, pin, encrypt bool) ([][]byte, error) {
+	addresses, err := c.backend.UploadBlobs(ctx, blobs, tag, pin, encrypt)
+	if err != nil {
+		return nil, err
+	}
+	for i, address := range addresses {
+		c.putBlob(address, blobs[i])
+	}
+	return addresses, nil
+}
+
+// DownloadChunk returns the chunk from the cache or downloads it from the backend
+func (c *Client) DownloadChunk(ctx context.Context, address []byte) (data []byte, err error) {
+	addr := swarm.NewAddress(address)
+	key := chunkKey(address)
+	if data, ok := c.get(key); ok {
+		return data, nil
+	}
+	if v, ok := c.socCache.Get(addr.String()); ok {
+		se := v.(*socEntry)
+		if time.Now().Before(se.expires) {
+			c.hit()
+			return se.data, nil
+		}
+		c.socCache.Remove(addr.String())
+	}
+	c.miss()
+
+	data, err = c.backend.DownloadChunk(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	// content addressed chunks are immutable, anything else is a soc
+	if cac.Valid(swarm.NewChunk(addr, data)) {
+		c.put(key, data)
+	} else {
+		c.socCache.Add(addr.String(), &socEntry{
+			data:    data,
+			expires: time.Now().Add(c.feedTTL),
+		})
+	}
+	return data, nil
+}
+
+// DownloadBlob returns the blob from the cache or downloads it from the backend
+func (c *Client) DownloadBlob(ctx context.Context, address []byte) ([]byte, int, error) {
+	if data, ok := c.getBlob(address); ok {
+		return data, http.StatusOK, nil
+	}
+	c.miss()
+
+	data, respCode, err := c.backend.DownloadBlob(ctx, address)
+	if err != nil {
+		return nil, respCode, err
+	}
+	if respCode == http.StatusOK {
+		c.putBlob(address, data)
+	}
+	return data, respCode, nil
+}
+
+// DeleteReference deletes the reference in the backend and removes it from the cache
+func (c *Client) DeleteReference(ctx context.Context, address []byte) error {
+	err := c.backend.DeleteReference(ctx, address)
+	if err != nil {
+		return err
+	}
+	c.socCache.Remove(swarm.NewAddress(address).String())
+	c.remove(blobKey(address))
+	c.remove(chunkKey(address))
+	return nil
+}
+
+// CreateTag creates a tag in the backend
+func (c *Client) CreateTag(ctx context.Context, address []byte) (uint32, error) {
+	return c.backend.CreateTag(ctx, address)
+}
+
+// GetTag gets a tag from the backend
+func (c *Client) GetTag(ctx context.Context, tag uint32) (int64, int64, int64, error) {
+	return c.backend.GetTag(ctx, tag)
+}
+
+func (c *Client) hit() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats.Hits++
+}
+
+func (c *Client) miss() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats.Misses++
+}
+
+func (c *Client) get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	c.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(filepath.Join(c.dir, key))
+	if err != nil {
+		// the file was evicted in between or removed from outside
+		c.remove(key)
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats.Hits++
+	if e.index >= 0 {
+		c.clock++
+		e.accessed = c.clock
+		e.hits++
+		c.queue.update(e)
+	}
+	return data, true
+}
+
+// getBlob returns a cached blob, an entry that does not decrypt is dropped
+func (c *Client) getBlob(address []byte) ([]byte, bool) {
+	key := blobKey(address)
+	sealed, ok := c.get(key)
+	if !ok {
+		return nil, false
+	}
+	data, err := openBlob(address, sealed)
+	if err != nil {
+		c.logger.Warningf("cache: could not decrypt %s: %v", key, err)
+		c.remove(key)
+		return nil, false
+	}
+	return data, true
+}
+
+func (c *Client) putBlob(address, data []byte) {
+	sealed, err := sealBlob(address, data)
+	if err != nil { // skipcq: TCV-001
+		c.logger.Warningf("cache: could not encrypt blob: %v", err)
+		return
+	}
+	c.put(blobKey(address), sealed)
+}
+
+func (c *Client) put(key string, data []byte) {
+	size := int64(len(data))
+	if c.maxSize > 0 && size > c.maxSize {
+		return
+	}
+	c.mu.Lock()
+	_, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok {
+		return
+	}
+
+	p := filepath.Join(c.dir, key)
+	err := os.MkdirAll(filepath.Dir(p), dirMode)
+	if err != nil {
+		c.logger.Warningf("cache: could not create directory for %s: %v", key, err)
+		return
+	}
+	err = writeFileAtomic(p, data)
+	if err != nil {
+		c.logger.Warningf("cache: could not store %s: %v", key, err)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; ok {
+		return
+	}
+	c.clock++
+	e := &entry{
+		key:      key,
+		size:     size,
+		added:    c.clock,
+		accessed: c.clock,
+	}
+	c.entries[key] = e
+	c.queue.add(e)
+	c.size += size
+	c.evict()
+}
+
+func (c *Client) remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	c.drop(e)
+}
+
+// evict removes entries as long as the cache is bigger than the maximum size.
+// It must be called with the lock held.
+func (c *Client) evict() {
+	if c.maxSize <= 0 {
+		return
+	}
+	for c.size > c.maxSize {
+		e := c.queue.victim()
+		if e == nil { // skipcq: TCV-001
+			return
+		}
+		c.drop(e)
+		c.stats.Evictions++
+	}
+}
+
+// drop removes an entry from the index and the disk. It must be called with the lock held.
+func (c *Client) drop(e *entry) {
+	c.queue.remove(e)
+	delete(c.entries, e.key)
+	c.size -= e.size
+	err := os.Remove(filepath.Join(c.dir, e.key))
+	if err != nil && !os.IsNotExist(err) {
+		c.logger.Warningf("cache: could not remove %s: %v", e.key, err)
+	}
+}
+
+// load indexes the content present in the cache directory. The modification time of the
+// files is used to restore the order of the entries.
+func (c *Client) load() error {
+	type found struct {
+		key     string
+		size    int64
+		modTime time.Time
+	}
+	var files []found
+	for _, d := range []string{blobsDir, chunksDir} {
+		root := filepath.Join(c.dir, d)
+		err := filepath.WalkDir(root, func(path string, de fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if de.IsDir() {
+				return nil
+			}
+			info, err := de.Info()
+			if err != nil {
+				return err
+			}
+			key, err := filepath.Rel(c.dir, path)
+			if err != nil { // skipcq: TCV-001
+				return err
+			}
+			// leftovers of an interrupted write
+			if filepath.Base(key)[0] == '.' {
+				return os.Remove(path)
+			}
+			files = append(files, found{key: filepath.ToSlash(key), size: info.Size(), modTime: info.ModTime()})
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, f := range files {
+		c.clock++
+		e := &entry{
+			key:      f.key,
+			size:     f.size,
+			added:    c.clock,
+			accessed: c.clock,
+		}
+		c.entries[f.key] = e
+		c.queue.add(e)
+		c.size += f.size
+	}
+	c.evict()
+	return nil
+}
+
+// blobKey names a blob by a hash of its reference, as the reference of an encrypted blob
+// holds its decryption key
+func blobKey(address []byte) string {
+	h := sha256.Sum256(append([]byte(blobsDir), address...))
+	return shardedKey(blobsDir, hex.EncodeToString(h[:]))
+}
+
+// blobCipher is keyed by a hash of the reference of the blob, which is different from
+// the one of its name
+func blobCipher(address []byte) (cipher.AEAD, error) {
+	key := sha256.Sum256(address)
+	block, err := aes.NewCipher(key[:])
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealBlob encrypts a blob for the disk, the nonce is put in front of it
+func sealBlob(address, data []byte) ([]byte, error) {
+	aead, err := blobCipher(address)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(data)+aead.Overhead())
+	_, err = rand.Read(nonce)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, data, nil), nil
+}
+
+func openBlob(address, sealed []byte) ([]byte, error) {
+	aead, err := blobCipher(address)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("cache entry too short")
+	}
+	return aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+}
+
+func chunkKey(address []byte) string {
+	return shardedKey(chunksDir, swarm.NewAddress(address).String())
+}
+
+func shardedKey(dir, ref string) string {
+	return dir + "/" + ref[:2] + "/" + ref
+}
+
+// writeFileAtomic writes to a temporary file and renames it, so that a crash never
+// leaves a partially written entry behind.
+func writeFileAtomic(name string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	err = os.Chmod(tmp.Name(), fileMode)
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
@@ -0,0 +1,321 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/soc"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/bee/mock"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/cache"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func TestCache(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.New(io.Discard, logrus.ErrorLevel)
+
+	t.Run("blob-hit-and-miss", func(t *testing.T) {
+		backend := mock.NewMockBeeClient()
+		c, err := cache.NewClient(backend, cache.Options{Dir: t.TempDir(), MaxSize: 1 << 20}, logger)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ref, err := backend.UploadBlob(ctx, []byte("not cached yet"), 0, false, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 3; i++ {
+			data, code, err := c.DownloadBlob(ctx, ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if code != 200 || string(data) != "not cached yet" {
+				t.Fatalf("unexpected response %d %s", code, data)
+			}
+		}
+		stats := c.Stats()
+		if stats.Misses != 1 || stats.Hits != 2 || stats.Entries != 1 {
+			t.Fatalf("unexpected stats %+v", stats)
+		}
+
+		// uploads are written through
+		ref, err = c.UploadBlob(ctx, []byte("written through"), 0, false, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _, err = c.DownloadBlob(ctx, ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.Stats().Hits != 3 {
+			t.Fatalf("uploaded blob should be a hit %+v", c.Stats())
+		}
+
+		// misses are not cached
+		_, _, err = c.DownloadBlob(ctx, make([]byte, 32))
+		if err == nil {
+			t.Fatal("expected error for missing blob")
+		}
+		if c.Stats().Entries != 2 {
+			t.Fatalf("missing blob should not be cached %+v", c.Stats())
+		}
+	})
+
+	t.Run("persist-across-restart", func(t *testing.T) {
+		dir := t.TempDir()
+		c, err := cache.NewClient(mock.NewMockBeeClient(), cache.Options{Dir: dir, MaxSize: 1 << 20}, logger)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ref, err := c.UploadBlob(ctx, []byte("persistent"), 0, false, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ch, err := utils.NewChunkWithSpan([]byte("chunk"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = c.UploadChunk(ctx, ch, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = c.DownloadChunk(ctx, ch.Address().Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// an empty backend proves the content is served from disk
+		c, err = cache.NewClient(mock.NewMockBeeClient(), cache.Options{Dir: dir, MaxSize: 1 << 20}, logger)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.Stats().Entries != 2 {
+			t.Fatalf("expected 2 entries after restart, got %+v", c.Stats())
+		}
+		data, _, err := c.DownloadBlob(ctx, ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != "persistent" {
+			t.Fatalf("unexpected data %s", data)
+		}
+		data, err = c.DownloadChunk(ctx, ch.Address().Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, ch.Data()) {
+			t.Fatal("chunk data mismatch")
+		}
+	})
+
+	t.Run("eviction", func(t *testing.T) {
+		for _, tc := range []struct {
+			policy  string
+			evicted int
+		}{
+			{policy: cache.PolicyLRU, evicted: 1},
+			{policy: cache.PolicyLFU, evicted: 1},
+			{policy: cache.PolicyFIFO, evicted: 0},
+		} {
+			t.Run(tc.policy, func(t *testing.T) {
+				c, err := cache.NewClient(mock.NewMockBeeClient(), cache.Options{Dir: t.TempDir(), MaxSize: 300, Policy: tc.policy}, logger)
+				if err != nil {
+					t.Fatal(err)
+				}
+				// the blobs take 100 bytes on disk with the nonce and tag of their encryption
+				refs := make([][]byte, 3)
+				for i := range refs {
+					refs[i], err = c.UploadBlob(ctx, bytes.Repeat([]byte{byte(i)}, 72), 0, false, false)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+				// use the first blob twice, so that the second one is the least recent and least frequent
+				for i := 0; i < 2; i++ {
+					_, _, err = c.DownloadBlob(ctx, refs[0])
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+				_, err = c.UploadBlob(ctx, bytes.Repeat([]byte{3}, 72), 0, false, false)
+				if err != nil {
+					t.Fatal(err)
+				}
+				stats := c.Stats()
+				if stats.Evictions != 1 || stats.Size > 300 {
+					t.Fatalf("unexpected stats %+v", stats)
+				}
+
+				hits := stats.Hits
+				_, _, err = c.DownloadBlob(ctx, refs[tc.evicted])
+				if err != nil {
+					t.Fatal(err)
+				}
+				if c.Stats().Hits != hits {
+					t.Fatalf("blob %d should have been evicted", tc.evicted)
+				}
+			})
+		}
+	})
+
+	t.Run("encrypted-at-rest", func(t *testing.T) {
+		dir := t.TempDir()
+		c, err := cache.NewClient(mock.NewMockBeeClient(), cache.Options{Dir: dir}, logger)
+		if err != nil {
+			t.Fatal(err)
+		}
+		plaintext := []byte("secret inode with block references")
+		ref, err := c.UploadBlob(ctx, plaintext, 0, true, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files := 0
+		err = filepath.WalkDir(dir, func(path string, de fs.DirEntry, err error) error {
+			if err != nil || de.IsDir() {
+				return err
+			}
+			files++
+			if strings.Contains(path, hex.EncodeToString(ref)) {
+				t.Fatalf("cache entry %s is named by the reference", path)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if bytes.Contains(data, plaintext) {
+				t.Fatalf("cache entry %s holds the plaintext", path)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if files != 1 {
+			t.Fatalf("expected one cache entry, found %d", files)
+		}
+
+		hits := c.Stats().Hits
+		data, _, err := c.DownloadBlob(ctx, ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, plaintext) || c.Stats().Hits != hits+1 {
+			t.Fatalf("unexpected cached blob %s", data)
+		}
+	})
+
+	t.Run("unknown-policy", func(t *testing.T) {
+		_, err := cache.NewClient(mock.NewMockBeeClient(), cache.Options{Dir: t.TempDir(), Policy: "random"}, logger)
+		if err != cache.ErrUnknownPolicy {
+			t.Fatalf("expected unknown policy error, got %v", err)
+		}
+	})
+
+	t.Run("soc-ttl", func(t *testing.T) {
+		backend := mock.NewMockBeeClient()
+		c, err := cache.NewClient(backend, cache.Options{Dir: t.TempDir(), FeedTTL: 200 * time.Millisecond}, logger)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key, err := crypto.GenerateSecp256k1Key()
+		if err != nil {
+			t.Fatal(err)
+		}
+		signer := crypto.NewDefaultSigner(key)
+		id := utils.HashString("soc-ttl")
+
+		addr := uploadSOC(t, backend, signer, id, []byte("first"))
+		data, err := c.DownloadChunk(ctx, addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertSOCPayload(t, data, "first")
+
+		// an update by someone else is only seen after the ttl expired
+		uploadSOC(t, backend, signer, id, []byte("second"))
+		data, err = c.DownloadChunk(ctx, addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertSOCPayload(t, data, "first")
+		time.Sleep(250 * time.Millisecond)
+		data, err = c.DownloadChunk(ctx, addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertSOCPayload(t, data, "second")
+
+		// an update through the cache is seen immediately
+		uploadSOC(t, c, signer, id, []byte("third"))
+		data, err = c.DownloadChunk(ctx, addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertSOCPayload(t, data, "third")
+
+		if c.Stats().Entries != 0 {
+			t.Fatalf("socs should not be stored on disk %+v", c.Stats())
+		}
+	})
+}
+
+func uploadSOC(t *testing.T, client blockstore.Client, signer crypto.Signer, id, payload []byte) []byte {
+	t.Helper()
+	ctx := context.Background()
+	ch, err := utils.NewChunkWithSpan(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sch, err := soc.New(id, ch).Sign(signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner, err := signer.EthereumAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature := sch.Data()[swarm.HashSize : swarm.HashSize+swarm.SocSignatureSize]
+	addr, err := client.UploadSOC(ctx, hex.EncodeToString(owner.Bytes()), hex.EncodeToString(id), hex.EncodeToString(signature), ch.Data())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return addr
+}
+
+func assertSOCPayload(t *testing.T, data []byte, want string) {
+	t.Helper()
+	// id + signature + span + payload
+	got := data[swarm.SocMinChunkSize:]
+	if string(got) != want {
+		t.Fatalf("expected soc payload %s, got %s", want, got)
+	}
+}
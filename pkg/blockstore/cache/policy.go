@@ -0,0 +1,120 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"container/heap"
+	"fmt"
+)
+
+const (
+	// PolicyLRU evicts the least recently used entry first
+	PolicyLRU = "lru"
+	// PolicyLFU evicts the least frequently used entry first
+	PolicyLFU = "lfu"
+	// PolicyFIFO evicts the oldest entry first
+	PolicyFIFO = "fifo"
+)
+
+// ErrUnknownPolicy is returned when an eviction policy is not supported
+var ErrUnknownPolicy = fmt.Errorf("unknown eviction policy")
+
+type entry struct {
+	key      string
+	size     int64
+	added    uint64
+	accessed uint64
+	hits     uint64
+	index    int
+}
+
+// lessFunc reports whether entry a should be evicted before entry b
+type lessFunc func(a, b *entry) bool
+
+func policyLessFunc(policy string) (lessFunc, error) {
+	switch policy {
+	case PolicyLRU, "":
+		return func(a, b *entry) bool {
+			return a.accessed < b.accessed
+		}, nil
+	case PolicyLFU:
+		return func(a, b *entry) bool {
+			if a.hits == b.hits {
+				return a.accessed < b.accessed
+			}
+			return a.hits < b.hits
+		}, nil
+	case PolicyFIFO:
+		return func(a, b *entry) bool {
+			return a.added < b.added
+		}, nil
+	default:
+		return nil, ErrUnknownPolicy
+	}
+}
+
+// evictionQueue is a heap of entries ordered by the eviction policy, the next
+// entry to evict is always at the top.
+type evictionQueue struct {
+	entries []*entry
+	less    lessFunc
+}
+
+func (q *evictionQueue) Len() int { return len(q.entries) }
+
+func (q *evictionQueue) Less(i, j int) bool { return q.less(q.entries[i], q.entries[j]) }
+
+func (q *evictionQueue) Swap(i, j int) {
+	q.entries[i], q.entries[j] = q.entries[j], q.entries[i]
+	q.entries[i].index = i
+	q.entries[j].index = j
+}
+
+func (q *evictionQueue) Push(x interface{}) {
+	e := x.(*entry)
+	e.index = len(q.entries)
+	q.entries = append(q.entries, e)
+}
+
+func (q *evictionQueue) Pop() interface{} {
+	old := q.entries
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	e.index = -1
+	q.entries = old[:n-1]
+	return e
+}
+
+func (q *evictionQueue) add(e *entry) {
+	heap.Push(q, e)
+}
+
+func (q *evictionQueue) update(e *entry) {
+	heap.Fix(q, e.index)
+}
+
+func (q *evictionQueue) remove(e *entry) {
+	heap.Remove(q, e.index)
+}
+
+func (q *evictionQueue) victim() *entry {
+	if len(q.entries) == 0 {
+		return nil
+	}
+	return q.entries[0]
+}
@@ -18,19 +18,77 @@ package blockstore
 
 import (
 	"context"
+	"errors"
 
 	"github.com/ethersphere/bee/pkg/swarm"
 )
 
-// Client is the interface for block store
+// Client is the interface for block store. All the calls that reach the store take a
+// context, so that abandoned requests stop using the store.
 type Client interface {
 	CheckConnection() bool
-	UploadSOC(owner string, id string, signature string, data []byte) (address []byte, err error)
-	UploadChunk(ch swarm.Chunk, pin bool) (address []byte, err error)
-	UploadBlob(data []byte, tag uint32, pin bool, encrypt bool) (address []byte, err error)
+	UploadSOC(ctx context.Context, owner string, id string, signature string, data []byte) (address []byte, err error)
+	UploadSOCs(ctx context.Context, socs []SOC) (addresses [][]byte, err error)
+	UploadChunk(ctx context.Context, ch swarm.Chunk, pin bool) (address []byte, err error)
+	UploadChunks(ctx context.Context, chs []swarm.Chunk, tag uint32, pin bool) error
+	UploadBlob(ctx context.Context, data []byte, tag uint32, pin bool, encrypt bool) (address []byte, err error)
+	UploadBlobs(ctx context.Context, blobs [][]byte, tag uint32, pin bool, encrypt bool) (addresses [][]byte, err error)
 	DownloadChunk(ctx context.Context, address []byte) (data []byte, err error)
-	DownloadBlob(address []byte) (data []byte, respCode int, err error)
-	DeleteReference(address []byte) error
-	CreateTag(address []byte) (uint32, error)
-	GetTag(tag uint32) (int64, int64, int64, error)
+	DownloadBlob(ctx context.Context, address []byte) (data []byte, respCode int, err error)
+	DeleteReference(ctx context.Context, address []byte) error
+	CreateTag(ctx context.Context, address []byte) (uint32, error)
+	GetTag(ctx context.Context, tag uint32) (int64, int64, int64, error)
+}
+
+// SOC is a signed single owner chunk for UploadSOCs, with the same fields as UploadSOC
+type SOC struct {
+	Owner     string
+	ID        string
+	Signature string
+	Data      []byte
+}
+
+// Status is the state of a blockstore client as shown by the health endpoint
+type Status struct {
+	Name      string   `json:"name,omitempty"`
+	Connected bool     `json:"connected"`
+	Breaker   string   `json:"breaker,omitempty"`
+	Failures  int      `json:"consecutiveFailures,omitempty"`
+	Nodes     []Status `json:"nodes,omitempty"`
+	// Pending is the number of writes waiting in the journal for the store to be reachable
+	Pending int `json:"pendingOperations,omitempty"`
+	// Failed is the number of journaled writes the store rejected, which are not replayed
+	Failed int `json:"failedOperations,omitempty"`
+}
+
+// StatusReporter is implemented by clients that can report more than the connection state
+type StatusReporter interface {
+	Status() Status
+}
+
+// PostageBatch is the state of a postage batch that pays for the uploads
+type PostageBatch struct {
+	// Node is the pool node that owns the batch
+	Node    string `json:"node,omitempty"`
+	BatchID string `json:"batchID"`
+	Label   string `json:"label,omitempty"`
+	// Utilization is the used share of the batch capacity, from 0 to 1
+	Utilization float64 `json:"utilization"`
+	// TTL is the number of seconds until the batch expires, -1 if bee does not know it
+	TTL     int64 `json:"ttl"`
+	Usable  bool  `json:"usable"`
+	Expired bool  `json:"expired"`
+	// Active is set on the batch that new uploads are stamped with
+	Active bool `json:"active"`
+	// Exhausted batches are not selected for uploads any more
+	Exhausted bool   `json:"exhausted"`
+	Warning   string `json:"warning,omitempty"`
+}
+
+// ErrPostageUnsupported is returned for postage queries to a store that does not use postage batches
+var ErrPostageUnsupported = errors.New("blockstore does not use postage batches")
+
+// PostageReporter is implemented by clients that pay for uploads with postage batches
+type PostageReporter interface {
+	PostageBatches(ctx context.Context) ([]PostageBatch, error)
 }
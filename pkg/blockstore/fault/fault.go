@@ -0,0 +1,470 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fault injects latency, errors, partial failures, corrupted payloads and
+// dropped writes into a blockstore.Client, to test how the upper layers cope with them.
+package fault
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/soc"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/splitter"
+)
+
+// ErrInjected is the error returned by a fault without an error of its own
+var ErrInjected = errors.New("fault: injected error")
+
+// Op is a blockstore.Client call that faults are injected into
+type Op string
+
+// The calls of blockstore.Client. The batch calls are separate from the single ones.
+const (
+	OpCheckConnection Op = "CheckConnection"
+	OpUploadSOC       Op = "UploadSOC"
+	OpUploadSOCs      Op = "UploadSOCs"
+	OpUploadChunk     Op = "UploadChunk"
+	OpUploadChunks    Op = "UploadChunks"
+	OpUploadBlob      Op = "UploadBlob"
+	OpUploadBlobs     Op = "UploadBlobs"
+	OpDownloadChunk   Op = "DownloadChunk"
+	OpDownloadBlob    Op = "DownloadBlob"
+	OpDeleteReference Op = "DeleteReference"
+	OpCreateTag       Op = "CreateTag"
+	OpGetTag          Op = "GetTag"
+)
+
+var (
+	// SOCUploads are the calls that update feeds
+	SOCUploads = []Op{OpUploadSOC, OpUploadSOCs}
+	// BlobUploads are the calls that store files, inodes and collection data
+	BlobUploads = []Op{OpUploadBlob, OpUploadBlobs}
+	// Uploads are all the calls that write content
+	Uploads = []Op{OpUploadSOC, OpUploadSOCs, OpUploadChunk, OpUploadChunks, OpUploadBlob, OpUploadBlobs}
+	// Downloads are all the calls that read content
+	Downloads = []Op{OpDownloadChunk, OpDownloadBlob}
+)
+
+// Kind is the kind of fault to inject
+type Kind int
+
+const (
+	// Error fails the call without reaching the backend
+	Error Kind = iota
+	// Latency delays the call, then the next rules are checked and the call proceeds
+	Latency
+	// Partial stores the first half of a batch and then fails. Single calls fail.
+	Partial
+	// Corrupt flips a byte of the downloaded data, or of a blob before it is uploaded
+	Corrupt
+	// Drop reports a write as successful without storing it, like a lost feed update
+	Drop
+)
+
+// Rule injects a fault into the matching calls
+type Rule struct {
+	// Ops are the calls the rule matches, all calls if empty
+	Ops  []Op
+	Kind Kind
+	// After skips the first matching calls
+	After int
+	// Times is the number of faults the rule injects, unlimited if 0
+	Times int
+	// Probability is the chance that a matching call gets the fault, drawn from the
+	// seeded random source. Every matching call gets it if 0.
+	Probability float64
+	// Latency is the delay of a Latency fault
+	Latency time.Duration
+	// Err is returned by Error and Partial faults, ErrInjected if nil
+	Err error
+}
+
+type rule struct {
+	Rule
+	seen    int
+	applied int
+}
+
+// Client is a blockstore.Client decorator that injects faults into the calls to its
+// backend. Faults are injected by the rules, checked in order for every call. Given the
+// same seed, rules and sequence of calls, the same faults are injected.
+type Client struct {
+	backend blockstore.Client
+
+	mu       sync.Mutex
+	rng      *rand.Rand
+	rules    []*rule
+	injected map[Op]int
+}
+
+// NewClient creates a client that injects faults into the calls to the backend
+func NewClient(backend blockstore.Client, seed int64, rules ...Rule) *Client {
+	c := &Client{
+		backend: backend,
+		rng:     rand.New(rand.NewSource(seed)), // skipcq: GSC-G404
+	}
+	c.SetRules(rules...)
+	return c
+}
+
+// SetRules replaces the rules. No faults are injected without rules.
+func (c *Client) SetRules(rules ...Rule) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rules = make([]*rule, len(rules))
+	for i, r := range rules {
+		c.rules[i] = &rule{Rule: r}
+	}
+	c.injected = make(map[Op]int)
+}
+
+// Injected returns the number of faults injected into the given calls, or into all calls
+func (c *Client) Injected(ops ...Op) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for op, count := range c.injected {
+		if len(ops) == 0 || contains(ops, op) {
+			n += count
+		}
+	}
+	return n
+}
+
+// CheckConnection reports the backend as unreachable on an Error fault
+func (c *Client) CheckConnection() bool {
+	f, err := c.fault(context.Background(), OpCheckConnection)
+	if err != nil || f != nil {
+		return false
+	}
+	return c.backend.CheckConnection()
+}
+
+// UploadSOC uploads a soc to the backend
+func (c *Client) UploadSOC(ctx context.Context, owner, id, signature string, data []byte) (address []byte, err error) {
+	f, err := c.fault(ctx, OpUploadSOC)
+	if err != nil {
+		return nil, err
+	}
+	if f != nil {
+		if f.Kind == Drop {
+			return socAddress(owner, id)
+		}
+		// a corrupted soc does not pass validation, so it fails like the other faults
+		return nil, f.err()
+	}
+	return c.backend.UploadSOC(ctx, owner, id, signature, data)
+}
+
+// UploadSOCs uploads the socs to the backend
+func (c *Client) UploadSOCs(ctx context.Context, socs []blockstore.SOC) ([][]byte, error) {
+	f, err := c.fault(ctx, OpUploadSOCs)
+	if err != nil {
+		return nil, err
+	}
+	if f != nil {
+		switch f.Kind {
+		case Drop:
+			addresses := make([][]byte, len(socs))
+			for i, s := range socs {
+				addresses[i], err = socAddress(s.Owner, s.ID)
+				if err != nil { // skipcq: TCV-001
+					return nil, err
+				}
+			}
+			return addresses, nil
+		case Partial:
+			_, err = c.backend.UploadSOCs(ctx, socs[:len(socs)/2])
+			if err != nil {
+				return nil, err
+			}
+			return nil, f.err()
+		default:
+			return nil, f.err()
+		}
+	}
+	return c.backend.UploadSOCs(ctx, socs)
+}
+
+// UploadChunk uploads a chunk to the backend
+func (c *Client) UploadChunk(ctx context.Context, ch swarm.Chunk, pin bool) (address []byte, err error) {
+	f, err := c.fault(ctx, OpUploadChunk)
+	if err != nil {
+		return nil, err
+	}
+	if f != nil {
+		if f.Kind == Drop {
+			return ch.Address().Bytes(), nil
+		}
+		return nil, f.err()
+	}
+	return c.backend.UploadChunk(ctx, ch, pin)
+}
+
+// UploadChunks uploads the chunks to the backend
+func (c *Client) UploadChunks(ctx context.Context, chs []swarm.Chunk, tag uint32, pin bool) error {
+	f, err := c.fault(ctx, OpUploadChunks)
+	if err != nil {
+		return err
+	}
+	if f != nil {
+		switch f.Kind {
+		case Drop:
+			return nil
+		case Partial:
+			err = c.backend.UploadChunks(ctx, chs[:len(chs)/2], tag, pin)
+			if err != nil {
+				return err
+			}
+			return f.err()
+		default:
+			return f.err()
+		}
+	}
+	return c.backend.UploadChunks(ctx, chs, tag, pin)
+}
+
+// UploadBlob uploads a blob to the backend
+func (c *Client) UploadBlob(ctx context.Context, data []byte, tag uint32, pin, encrypt bool) (address []byte, err error) {
+	f, err := c.fault(ctx, OpUploadBlob)
+	if err != nil {
+		return nil, err
+	}
+	if f != nil {
+		switch f.Kind {
+		case Drop:
+			return blobAddress(data, encrypt)
+		case Corrupt:
+			data = c.corrupt(data)
+		default:
+			return nil, f.err()
+		}
+	}
+	return c.backend.UploadBlob(ctx, data, tag, pin, encrypt)
+}
+
+// UploadBlobs uploads the blobs to the backend
+func (c *Client) UploadBlobs(ctx context.Context, blobs [][]byte, tag uint32, pin, encrypt bool) ([][]byte, error) {
+	f, err := c.fault(ctx, OpUploadBlobs)
+	if err != nil {
+		return nil, err
+	}
+	if f != nil {
+		switch f.Kind {
+		case Drop:
+			addresses := make([][]byte, len(blobs))
+			for i, data := range blobs {
+				addresses[i], err = blobAddress(data, encrypt)
+				if err != nil { // skipcq: TCV-001
+					return nil, err
+				}
+			}
+			return addresses, nil
+		case Partial:
+			_, err = c.backend.UploadBlobs(ctx, blobs[:len(blobs)/2], tag, pin, encrypt)
+			if err != nil {
+				return nil, err
+			}
+			return nil, f.err()
+		case Corrupt:
+			corrupted := make([][]byte, len(blobs))
+			copy(corrupted, blobs)
+			i := c.intn(len(blobs))
+			corrupted[i] = c.corrupt(blobs[i])
+			blobs = corrupted
+		default:
+			return nil, f.err()
+		}
+	}
+	return c.backend.UploadBlobs(ctx, blobs, tag, pin, encrypt)
+}
+
+// DownloadChunk downloads a chunk from the backend
+func (c *Client) DownloadChunk(ctx context.Context, address []byte) (data []byte, err error) {
+	f, err := c.fault(ctx, OpDownloadChunk)
+	if err != nil {
+		return nil, err
+	}
+	if f != nil && f.Kind != Corrupt {
+		return nil, f.err()
+	}
+	data, err = c.backend.DownloadChunk(ctx, address)
+	if err != nil || f == nil {
+		return data, err
+	}
+	return c.corrupt(data), nil
+}
+
+// DownloadBlob downloads a blob from the backend
+func (c *Client) DownloadBlob(ctx context.Context, address []byte) ([]byte, int, error) {
+	f, err := c.fault(ctx, OpDownloadBlob)
+	if err != nil {
+		return nil, 0, err
+	}
+	if f != nil && f.Kind != Corrupt {
+		return nil, 0, f.err()
+	}
+	data, respCode, err := c.backend.DownloadBlob(ctx, address)
+	if err != nil || f == nil {
+		return data, respCode, err
+	}
+	return c.corrupt(data), respCode, nil
+}
+
+// DeleteReference deletes the reference in the backend
+func (c *Client) DeleteReference(ctx context.Context, address []byte) error {
+	f, err := c.fault(ctx, OpDeleteReference)
+	if err != nil {
+		return err
+	}
+	if f != nil {
+		if f.Kind == Drop {
+			return nil
+		}
+		return f.err()
+	}
+	return c.backend.DeleteReference(ctx, address)
+}
+
+// CreateTag creates a tag in the backend
+func (c *Client) CreateTag(ctx context.Context, address []byte) (uint32, error) {
+	f, err := c.fault(ctx, OpCreateTag)
+	if err != nil {
+		return 0, err
+	}
+	if f != nil {
+		return 0, f.err()
+	}
+	return c.backend.CreateTag(ctx, address)
+}
+
+// GetTag gets a tag from the backend
+func (c *Client) GetTag(ctx context.Context, tag uint32) (int64, int64, int64, error) {
+	f, err := c.fault(ctx, OpGetTag)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	if f != nil {
+		return 0, 0, 0, f.err()
+	}
+	return c.backend.GetTag(ctx, tag)
+}
+
+// fault checks the rules for the call. It waits out the Latency faults and returns the
+// first other fault to inject, if any. The error is set if the context is done while waiting.
+func (c *Client) fault(ctx context.Context, op Op) (*Rule, error) {
+	var (
+		delay time.Duration
+		found *Rule
+	)
+	c.mu.Lock()
+	for _, r := range c.rules {
+		if len(r.Ops) > 0 && !contains(r.Ops, op) {
+			continue
+		}
+		r.seen++
+		if r.seen <= r.After || (r.Times > 0 && r.applied >= r.Times) {
+			continue
+		}
+		if r.Probability > 0 && c.rng.Float64() >= r.Probability {
+			continue
+		}
+		r.applied++
+		c.injected[op]++
+		if r.Kind == Latency {
+			delay += r.Latency
+			continue
+		}
+		found = &r.Rule
+		break
+	}
+	c.mu.Unlock()
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return found, nil
+}
+
+// corrupt returns a copy of the data with one byte flipped
+func (c *Client) corrupt(data []byte) []byte {
+	if len(data) == 0 {
+		return data
+	}
+	corrupted := make([]byte, len(data))
+	copy(corrupted, data)
+	corrupted[c.intn(len(data))] ^= 0xff
+	return corrupted
+}
+
+func (c *Client) intn(n int) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rng.Intn(n)
+}
+
+func (r *Rule) err() error {
+	if r.Err != nil {
+		return r.Err
+	}
+	return ErrInjected
+}
+
+func socAddress(owner, id string) ([]byte, error) {
+	ownerBytes, err := hex.DecodeString(owner)
+	if err != nil {
+		return nil, err
+	}
+	idBytes, err := hex.DecodeString(id)
+	if err != nil {
+		return nil, err
+	}
+	addr, err := soc.CreateAddress(idBytes, ownerBytes)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	return addr.Bytes(), nil
+}
+
+func blobAddress(data []byte, encrypt bool) ([]byte, error) {
+	ref, err := splitter.Split(func(swarm.Chunk) error { return nil }, data, encrypt)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	return ref.Bytes(), nil
+}
+
+func contains(ops []Op, op Op) bool {
+	for _, o := range ops {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,197 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fault_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/soc"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/bee/mock"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/fault"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+)
+
+func TestFault(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("after-and-times", func(t *testing.T) {
+		c := fault.NewClient(mock.NewMockBeeClient(), 1, fault.Rule{
+			Ops:   fault.BlobUploads,
+			Kind:  fault.Error,
+			After: 1,
+			Times: 2,
+		})
+		var failed []int
+		for i := 0; i < 5; i++ {
+			_, err := c.UploadBlob(ctx, []byte{byte(i)}, 0, false, false)
+			if err != nil {
+				if !errors.Is(err, fault.ErrInjected) {
+					t.Fatal(err)
+				}
+				failed = append(failed, i)
+			}
+		}
+		if len(failed) != 2 || failed[0] != 1 || failed[1] != 2 {
+			t.Fatalf("expected the second and third upload to fail, got %v", failed)
+		}
+		if c.Injected(fault.OpUploadBlob) != 2 || c.Injected(fault.OpDownloadBlob) != 0 {
+			t.Fatalf("unexpected injected count %d", c.Injected())
+		}
+	})
+
+	t.Run("seeded-probability", func(t *testing.T) {
+		run := func() []bool {
+			c := fault.NewClient(mock.NewMockBeeClient(), 42, fault.Rule{Kind: fault.Error, Probability: 0.5})
+			results := make([]bool, 50)
+			for i := range results {
+				_, err := c.UploadBlob(ctx, []byte{byte(i)}, 0, false, false)
+				results[i] = err != nil
+			}
+			return results
+		}
+		first, second := run(), run()
+		failures := 0
+		for i := range first {
+			if first[i] != second[i] {
+				t.Fatalf("same seed injected different faults at call %d", i)
+			}
+			if first[i] {
+				failures++
+			}
+		}
+		if failures == 0 || failures == len(first) {
+			t.Fatalf("expected some of the calls to fail, got %d", failures)
+		}
+	})
+
+	t.Run("latency", func(t *testing.T) {
+		c := fault.NewClient(mock.NewMockBeeClient(), 1, fault.Rule{
+			Ops:     fault.Downloads,
+			Kind:    fault.Latency,
+			Latency: time.Hour,
+		})
+		ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		_, _, err := c.DownloadBlob(ctx, make([]byte, 32))
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected the delay to end with the context, got %v", err)
+		}
+	})
+
+	t.Run("partial-batch", func(t *testing.T) {
+		backend := mock.NewMockBeeClient()
+		c := fault.NewClient(backend, 1, fault.Rule{Ops: []fault.Op{fault.OpUploadChunks}, Kind: fault.Partial})
+		var chs []swarm.Chunk
+		for i := 0; i < 4; i++ {
+			ch, err := utils.NewChunkWithSpan([]byte{byte(i)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			chs = append(chs, ch)
+		}
+		err := c.UploadChunks(ctx, chs, 0, false)
+		if !errors.Is(err, fault.ErrInjected) {
+			t.Fatalf("expected injected error, got %v", err)
+		}
+		for i, ch := range chs {
+			_, err := backend.DownloadChunk(ctx, ch.Address().Bytes())
+			if (err == nil) != (i < 2) {
+				t.Fatalf("only the first half should be stored, chunk %d: %v", i, err)
+			}
+		}
+	})
+
+	t.Run("corrupt-download", func(t *testing.T) {
+		c := fault.NewClient(mock.NewMockBeeClient(), 1)
+		ref, err := c.UploadBlob(ctx, []byte("payload"), 0, false, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.SetRules(fault.Rule{Ops: fault.Downloads, Kind: fault.Corrupt, Times: 1})
+		data, _, err := c.DownloadBlob(ctx, ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Equal(data, []byte("payload")) || len(data) != len("payload") {
+			t.Fatalf("expected corrupted payload, got %q", data)
+		}
+		data, _, err = c.DownloadBlob(ctx, ref)
+		if err != nil || string(data) != "payload" {
+			t.Fatalf("the stored data should not change, got %q: %v", data, err)
+		}
+	})
+
+	t.Run("drop-soc", func(t *testing.T) {
+		backend := mock.NewMockBeeClient()
+		c := fault.NewClient(backend, 1, fault.Rule{Ops: fault.SOCUploads, Kind: fault.Drop})
+		s := newSOC(t, []byte("dropped"))
+		addr, err := c.UploadSOC(ctx, s.Owner, s.ID, s.Signature, s.Data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = backend.DownloadChunk(ctx, addr)
+		if err == nil {
+			t.Fatal("dropped soc should not be stored")
+		}
+
+		// the address is the one the soc would have been stored at
+		c.SetRules()
+		stored, err := c.UploadSOC(ctx, s.Owner, s.ID, s.Signature, s.Data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(addr, stored) {
+			t.Fatal("dropped soc should report the soc address")
+		}
+	})
+}
+
+func newSOC(t *testing.T, payload []byte) blockstore.SOC {
+	t.Helper()
+	key, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := crypto.NewDefaultSigner(key)
+	id := utils.HashString("fault")
+	ch, err := utils.NewChunkWithSpan(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sch, err := soc.New(id, ch).Sign(signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner, err := signer.EthereumAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return blockstore.SOC{
+		Owner:     hex.EncodeToString(owner.Bytes()),
+		ID:        hex.EncodeToString(id),
+		Signature: hex.EncodeToString(sch.Data()[swarm.HashSize : swarm.HashSize+swarm.SocSignatureSize]),
+		Data:      ch.Data(),
+	}
+}
@@ -0,0 +1,667 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package journal keeps writes in a durable write-ahead journal while the backend of a
+// blockstore.Client is unreachable and replays them in order once it is back.
+package journal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/bee"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/local"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/splitter"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+)
+
+const (
+	journalFile = "journal.log"
+	doneFile    = "journal.done"
+	// deadFile keeps the operations the backend rejected, with their chunks
+	deadFile   = "journal.dead"
+	stagingDir = "staging"
+	dirMode    = 0700
+	fileMode   = 0600
+
+	// DefaultReplayInterval is how often the backend is checked while operations are pending
+	DefaultReplayInterval = 5 * time.Second
+
+	replayTimeout = time.Minute
+
+	// maxRejections is the number of replays an operation the reachable backend rejects
+	// gets before it is moved to the dead letter file, so that a failure that lasts a bit
+	// longer than the backend being down is not taken for a rejection
+	maxRejections = 3
+)
+
+const (
+	opSOC    = "soc"
+	opChunks = "chunks"
+	opDelete = "delete"
+)
+
+// Options configures the journal
+type Options struct {
+	// Dir is the directory of the journal and of the content written while offline
+	Dir string
+	// ReplayInterval is how often the backend is checked while operations are pending
+	ReplayInterval time.Duration
+}
+
+// op is a journaled write. Chunks are kept in the staging store, the journal only
+// lists their addresses.
+type op struct {
+	Seq       uint64   `json:"seq"`
+	Kind      string   `json:"kind"`
+	Owner     string   `json:"owner,omitempty"`
+	ID        string   `json:"id,omitempty"`
+	Signature string   `json:"signature,omitempty"`
+	Data      []byte   `json:"data,omitempty"`
+	Addresses [][]byte `json:"addresses,omitempty"`
+	Pin       bool     `json:"pin,omitempty"`
+
+	// rejections counts the replays the reachable backend rejected
+	rejections int
+}
+
+// deadOp is an operation the backend rejected. The chunks of the operation are kept with
+// it, as the staging store is removed once the journal is replayed.
+type deadOp struct {
+	Op     *op      `json:"op"`
+	Chunks [][]byte `json:"chunks,omitempty"`
+	Error  string   `json:"error"`
+	Time   int64    `json:"time"`
+}
+
+// Client is a blockstore.Client that writes through to its backend while the backend is
+// reachable. When a write fails because the backend is unreachable, the write is stored
+// locally and recorded in the journal, and the locally computed address is returned.
+// Once there are pending operations, all writes go to the journal to keep them in order,
+// until a background task has replayed the journal to the backend.
+//
+// Blobs written while offline are split and, if requested, encrypted locally, and are
+// replayed chunk by chunk. If they are pinned, bee pins each of their chunks.
+type Client struct {
+	backend  blockstore.Client
+	dir      string
+	interval time.Duration
+	logger   logging.Logger
+
+	// stageMu is held while content is staged and journaled, so that the staging store
+	// is not removed under a write
+	stageMu sync.RWMutex
+	mu      sync.Mutex // guards the fields below and the journal file
+	staging *local.Client
+	file    *os.File
+	ops     []*op
+	seq     uint64
+	dead    int
+
+	replayMu sync.Mutex
+	quit     chan struct{}
+	done     chan struct{}
+}
+
+// NewClient creates a journal over the given backend. Operations left in the journal
+// directory by an earlier run are replayed as soon as the backend is reachable.
+func NewClient(backend blockstore.Client, opts Options, logger logging.Logger) (*Client, error) {
+	interval := opts.ReplayInterval
+	if interval <= 0 {
+		interval = DefaultReplayInterval
+	}
+	err := os.MkdirAll(opts.Dir, dirMode)
+	if err != nil {
+		return nil, err
+	}
+	staging, err := local.NewClient(filepath.Join(opts.Dir, stagingDir), logger)
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{
+		backend:  backend,
+		dir:      opts.Dir,
+		interval: interval,
+		logger:   logger,
+		staging:  staging,
+		quit:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	err = c.load()
+	if err != nil {
+		return nil, err
+	}
+	c.file, err = os.OpenFile(filepath.Join(c.dir, journalFile), os.O_CREATE|os.O_APPEND|os.O_WRONLY, fileMode)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.ops) > 0 {
+		logger.Infof("journal: %d operations pending from an earlier run", len(c.ops))
+	}
+	if c.dead > 0 {
+		logger.Warningf("journal: %d operations were rejected by the blockstore, see %s", c.dead, filepath.Join(c.dir, deadFile))
+	}
+	go c.run()
+	return c, nil
+}
+
+// Close stops the background replay. Pending operations stay in the journal.
+func (c *Client) Close() error {
+	close(c.quit)
+	<-c.done
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.file.Close()
+}
+
+// Pending returns the number of operations that are not replayed to the backend yet
+func (c *Client) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.ops)
+}
+
+// Failed returns the number of operations the backend rejected, which are kept in the
+// dead letter file instead of being replayed
+func (c *Client) Failed() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.dead
+}
+
+// Replay replays the pending operations in order. It stops at the first operation that
+// fails, so that it is replayed again later. An operation that the reachable backend
+// keeps rejecting, like one with an invalid postage stamp, is moved to the dead letter
+// file, so that it does not hold back the operations after it.
+func (c *Client) Replay(ctx context.Context) error {
+	c.replayMu.Lock()
+	defer c.replayMu.Unlock()
+	for {
+		c.mu.Lock()
+		if len(c.ops) == 0 {
+			c.mu.Unlock()
+			return nil
+		}
+		next := c.ops[0]
+		c.mu.Unlock()
+
+		err := c.replay(ctx, next)
+		if err != nil {
+			if c.transient(ctx, err) {
+				return err
+			}
+			next.rejections++
+			if next.rejections < maxRejections {
+				return err
+			}
+			err = c.deadLetter(ctx, next, err)
+			if err != nil { // skipcq: TCV-001
+				return err
+			}
+		}
+		err = c.complete(next)
+		if err != nil { // skipcq: TCV-001
+			return err
+		}
+	}
+}
+
+// CheckConnection checks the connection of the backend
+func (c *Client) CheckConnection() bool {
+	return c.backend.CheckConnection()
+}
+
+// Status reports the status of the backend and the number of pending operations
+func (c *Client) Status() blockstore.Status {
+	var status blockstore.Status
+	if r, ok := c.backend.(blockstore.StatusReporter); ok {
+		status = r.Status()
+	} else {
+		status = blockstore.Status{Connected: c.backend.CheckConnection()}
+	}
+	status.Pending = c.Pending()
+	status.Failed = c.Failed()
+	return status
+}
+
+// PostageBatches reports the postage batches of the backend
+func (c *Client) PostageBatches(ctx context.Context) ([]blockstore.PostageBatch, error) {
+	if r, ok := c.backend.(blockstore.PostageReporter); ok {
+		return r.PostageBatches(ctx)
+	}
+	return nil, blockstore.ErrPostageUnsupported
+}
+
+// UploadSOC uploads a soc to the backend, or journals it if the backend is unreachable
+func (c *Client) UploadSOC(ctx context.Context, owner, id, signature string, data []byte) (address []byte, err error) {
+	if c.Pending() == 0 {
+		address, err = c.backend.UploadSOC(ctx, owner, id, signature, data)
+		if !c.offline(ctx, err) {
+			return address, err
+		}
+	}
+	return c.journalSOC(ctx, blockstore.SOC{Owner: owner, ID: id, Signature: signature, Data: data})
+}
+
+// UploadSOCs uploads the socs to the backend, or journals them if the backend is unreachable
+func (c *Client) UploadSOCs(ctx context.Context, socs []blockstore.SOC) ([][]byte, error) {
+	if c.Pending() == 0 {
+		addresses, err := c.backend.UploadSOCs(ctx, socs)
+		if !c.offline(ctx, err) {
+			return addresses, err
+		}
+	}
+	addresses := make([][]byte, len(socs))
+	for i, s := range socs {
+		address, err := c.journalSOC(ctx, s)
+		if err != nil {
+			return nil, err
+		}
+		addresses[i] = address
+	}
+	return addresses, nil
+}
+
+// UploadChunk uploads a chunk to the backend, or journals it if the backend is unreachable
+func (c *Client) UploadChunk(ctx context.Context, ch swarm.Chunk, pin bool) (address []byte, err error) {
+	if c.Pending() == 0 {
+		address, err = c.backend.UploadChunk(ctx, ch, pin)
+		if !c.offline(ctx, err) {
+			return address, err
+		}
+	}
+	err = c.journalChunks(ctx, []swarm.Chunk{ch}, pin)
+	if err != nil {
+		return nil, err
+	}
+	return ch.Address().Bytes(), nil
+}
+
+// UploadChunks uploads the chunks to the backend, or journals them if the backend is unreachable
+func (c *Client) UploadChunks(ctx context.Context, chs []swarm.Chunk, tag uint32, pin bool) error {
+	if c.Pending() == 0 {
+		err := c.backend.UploadChunks(ctx, chs, tag, pin)
+		if !c.offline(ctx, err) {
+			return err
+		}
+	}
+	return c.journalChunks(ctx, chs, pin)
+}
+
+// UploadBlob uploads a blob to the backend. If the backend is unreachable, the blob is
+// split locally and its chunks are journaled.
+func (c *Client) UploadBlob(ctx context.Context, data []byte, tag uint32, pin, encrypt bool) (address []byte, err error) {
+	if c.Pending() == 0 {
+		address, err = c.backend.UploadBlob(ctx, data, tag, pin, encrypt)
+		if !c.offline(ctx, err) {
+			return address, err
+		}
+	}
+	return c.journalBlob(ctx, data, pin, encrypt)
+}
+
+// UploadBlobs uploads the blobs to the backend, or journals them like UploadBlob
+func (c *Client) UploadBlobs(ctx context.Context, blobs [][]byte, tag uint32, pin, encrypt bool) ([][]byte, error) {
+	if c.Pending() == 0 {
+		addresses, err := c.backend.UploadBlobs(ctx, blobs, tag, pin, encrypt)
+		if !c.offline(ctx, err) {
+			return addresses, err
+		}
+	}
+	addresses := make([][]byte, len(blobs))
+	for i, data := range blobs {
+		address, err := c.journalBlob(ctx, data, pin, encrypt)
+		if err != nil {
+			return nil, err
+		}
+		addresses[i] = address
+	}
+	return addresses, nil
+}
+
+// DownloadChunk returns a chunk written while offline or downloads it from the backend
+func (c *Client) DownloadChunk(ctx context.Context, address []byte) (data []byte, err error) {
+	if c.Pending() > 0 {
+		data, err = c.stagingStore().DownloadChunk(ctx, address)
+		if err == nil {
+			return data, nil
+		}
+	}
+	return c.backend.DownloadChunk(ctx, address)
+}
+
+// DownloadBlob returns a blob written while offline or downloads it from the backend
+func (c *Client) DownloadBlob(ctx context.Context, address []byte) ([]byte, int, error) {
+	if c.Pending() > 0 {
+		data, respCode, err := c.stagingStore().DownloadBlob(ctx, address)
+		if err == nil && respCode == http.StatusOK {
+			return data, respCode, nil
+		}
+	}
+	return c.backend.DownloadBlob(ctx, address)
+}
+
+// DeleteReference deletes the reference in the backend, or journals the deletion if the
+// backend is unreachable
+func (c *Client) DeleteReference(ctx context.Context, address []byte) error {
+	if c.Pending() == 0 {
+		err := c.backend.DeleteReference(ctx, address)
+		if !c.offline(ctx, err) {
+			return err
+		}
+	}
+	return c.append(&op{Kind: opDelete, Addresses: [][]byte{address}})
+}
+
+// CreateTag creates a tag in the backend. Writes that are journaled are not tracked, so
+// no tag is created while operations are pending.
+func (c *Client) CreateTag(ctx context.Context, address []byte) (uint32, error) {
+	if c.Pending() > 0 {
+		return 0, nil
+	}
+	tag, err := c.backend.CreateTag(ctx, address)
+	if c.offline(ctx, err) {
+		return 0, nil
+	}
+	return tag, err
+}
+
+// GetTag gets a tag from the backend
+func (c *Client) GetTag(ctx context.Context, tag uint32) (int64, int64, int64, error) {
+	return c.backend.GetTag(ctx, tag)
+}
+
+// offline tells if a write failed because the backend is unreachable
+func (c *Client) offline(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	return !c.backend.CheckConnection()
+}
+
+func (c *Client) journalSOC(ctx context.Context, s blockstore.SOC) ([]byte, error) {
+	c.stageMu.RLock()
+	defer c.stageMu.RUnlock()
+	// the staging store validates the soc and computes its address
+	address, err := c.stagingStore().UploadSOC(ctx, s.Owner, s.ID, s.Signature, s.Data)
+	if err != nil {
+		return nil, err
+	}
+	err = c.append(&op{Kind: opSOC, Owner: s.Owner, ID: s.ID, Signature: s.Signature, Data: s.Data})
+	if err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+func (c *Client) journalChunks(ctx context.Context, chs []swarm.Chunk, pin bool) error {
+	c.stageMu.RLock()
+	defer c.stageMu.RUnlock()
+	addresses := make([][]byte, len(chs))
+	for i, ch := range chs {
+		_, err := c.stagingStore().UploadChunk(ctx, ch, false)
+		if err != nil {
+			return err
+		}
+		addresses[i] = ch.Address().Bytes()
+	}
+	return c.append(&op{Kind: opChunks, Addresses: addresses, Pin: pin})
+}
+
+func (c *Client) journalBlob(ctx context.Context, data []byte, pin, encrypt bool) ([]byte, error) {
+	c.stageMu.RLock()
+	defer c.stageMu.RUnlock()
+	var addresses [][]byte
+	ref, err := splitter.Split(func(ch swarm.Chunk) error {
+		_, err := c.stagingStore().UploadChunk(ctx, ch, false)
+		if err != nil {
+			return err
+		}
+		addresses = append(addresses, ch.Address().Bytes())
+		return nil
+	}, data, encrypt)
+	if err != nil {
+		return nil, err
+	}
+	err = c.append(&op{Kind: opChunks, Addresses: addresses, Pin: pin})
+	if err != nil {
+		return nil, err
+	}
+	return ref.Bytes(), nil
+}
+
+// append writes the operation to the journal and syncs it to disk
+func (c *Client) append(o *op) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	o.Seq = c.seq + 1
+	line, err := json.Marshal(o)
+	if err != nil { // skipcq: TCV-001
+		return err
+	}
+	_, err = c.file.Write(append(line, '\n'))
+	if err != nil {
+		return err
+	}
+	err = c.file.Sync()
+	if err != nil { // skipcq: TCV-001
+		return err
+	}
+	c.seq = o.Seq
+	c.ops = append(c.ops, o)
+	if len(c.ops) == 1 {
+		c.logger.Warningf("journal: blockstore unreachable, writes are journaled")
+	}
+	return nil
+}
+
+func (c *Client) replay(ctx context.Context, o *op) error {
+	switch o.Kind {
+	case opSOC:
+		_, err := c.backend.UploadSOC(ctx, o.Owner, o.ID, o.Signature, o.Data)
+		return err
+	case opChunks:
+		chs := make([]swarm.Chunk, len(o.Addresses))
+		for i, address := range o.Addresses {
+			data, err := c.stagingStore().DownloadChunk(ctx, address)
+			if err != nil { // skipcq: TCV-001
+				return err
+			}
+			chs[i] = swarm.NewChunk(swarm.NewAddress(address), data)
+		}
+		return c.backend.UploadChunks(ctx, chs, 0, o.Pin)
+	case opDelete:
+		return c.backend.DeleteReference(ctx, o.Addresses[0])
+	default: // skipcq: TCV-001
+		c.logger.Errorf("journal: skipping unknown operation %s", o.Kind)
+		return nil
+	}
+}
+
+// transient tells if an operation failed because the backend could not be reached, and
+// not because the backend rejected it
+func (c *Client) transient(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, bee.ErrCircuitOpen) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return !c.backend.CheckConnection()
+}
+
+// deadLetter moves an operation the backend rejected to the dead letter file
+func (c *Client) deadLetter(ctx context.Context, o *op, cause error) error {
+	d := &deadOp{Op: o, Error: cause.Error(), Time: time.Now().Unix()}
+	if o.Kind == opChunks {
+		for _, address := range o.Addresses {
+			data, err := c.stagingStore().DownloadChunk(ctx, address)
+			if err != nil { // skipcq: TCV-001
+				return err
+			}
+			d.Chunks = append(d.Chunks, data)
+		}
+	}
+	line, err := json.Marshal(d)
+	if err != nil { // skipcq: TCV-001
+		return err
+	}
+	f, err := os.OpenFile(filepath.Join(c.dir, deadFile), os.O_CREATE|os.O_APPEND|os.O_WRONLY, fileMode)
+	if err != nil { // skipcq: TCV-001
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(line, '\n'))
+	if err != nil { // skipcq: TCV-001
+		return err
+	}
+	err = f.Sync()
+	if err != nil { // skipcq: TCV-001
+		return err
+	}
+	c.mu.Lock()
+	c.dead++
+	c.mu.Unlock()
+	c.logger.Errorf("journal: blockstore rejected operation %d (%s), it is moved to %s: %v", o.Seq, o.Kind, deadFile, cause)
+	return nil
+}
+
+// complete removes a replayed or rejected operation. When the journal is empty, the journal file and
+// the staged content are removed.
+func (c *Client) complete(o *op) error {
+	c.stageMu.Lock()
+	defer c.stageMu.Unlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ops = c.ops[1:]
+	if len(c.ops) > 0 {
+		return writeFileAtomic(filepath.Join(c.dir, doneFile), []byte(strconv.FormatUint(o.Seq, 10)))
+	}
+
+	c.logger.Infof("journal: all operations replayed")
+	err := c.file.Truncate(0)
+	if err != nil { // skipcq: TCV-001
+		return err
+	}
+	err = os.Remove(filepath.Join(c.dir, doneFile))
+	if err != nil && !os.IsNotExist(err) { // skipcq: TCV-001
+		return err
+	}
+	c.seq = 0
+	staging := filepath.Join(c.dir, stagingDir)
+	err = os.RemoveAll(staging)
+	if err != nil { // skipcq: TCV-001
+		return err
+	}
+	c.staging, err = local.NewClient(staging, c.logger)
+	return err
+}
+
+// load reads the operations that are not replayed yet and counts the rejected ones
+func (c *Client) load() error {
+	dead, err := os.ReadFile(filepath.Join(c.dir, deadFile))
+	if err == nil {
+		c.dead = bytes.Count(dead, []byte{'\n'})
+	} else if !os.IsNotExist(err) { // skipcq: TCV-001
+		return err
+	}
+
+	var done uint64
+	data, err := os.ReadFile(filepath.Join(c.dir, doneFile))
+	if err == nil {
+		done, err = strconv.ParseUint(string(bytes.TrimSpace(data)), 10, 64)
+		if err != nil { // skipcq: TCV-001
+			return err
+		}
+	} else if !os.IsNotExist(err) { // skipcq: TCV-001
+		return err
+	}
+
+	name := filepath.Join(c.dir, journalFile)
+	data, err = os.ReadFile(name)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil { // skipcq: TCV-001
+		return err
+	}
+	valid := 0
+	for valid < len(data) {
+		end := bytes.IndexByte(data[valid:], '\n')
+		o := &op{}
+		if end < 0 || json.Unmarshal(data[valid:valid+end], o) != nil {
+			// a write that was cut off by a crash was never acknowledged
+			c.logger.Warningf("journal: dropping incomplete operation")
+			return os.Truncate(name, int64(valid))
+		}
+		valid += end + 1
+		c.seq = o.Seq
+		if o.Seq > done {
+			c.ops = append(c.ops, o)
+		}
+	}
+	return nil
+}
+
+// run replays the journal whenever operations are pending and the backend is reachable
+func (c *Client) run() {
+	defer close(c.done)
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.quit:
+			return
+		case <-ticker.C:
+		}
+		if c.Pending() == 0 || !c.backend.CheckConnection() {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), replayTimeout)
+		err := c.Replay(ctx)
+		cancel()
+		if err != nil {
+			c.logger.Warningf("journal: replay stopped with %d operations pending: %v", c.Pending(), err)
+		}
+	}
+}
+
+func (c *Client) stagingStore() *local.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.staging
+}
+
+func writeFileAtomic(name string, data []byte) error {
+	tmp := name + ".tmp"
+	err := os.WriteFile(tmp, data, fileMode)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, name)
+}
@@ -0,0 +1,357 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package journal_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/soc"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/journal"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/local"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	errUnreachable = errors.New("connection refused")
+	errRejected    = errors.New("batch is overissued")
+)
+
+// backend is a local store that can be taken offline. It rejects the chunks that hold
+// the word "rejected".
+type backend struct {
+	*local.Client
+	down int32
+}
+
+func newBackend(t *testing.T) *backend {
+	t.Helper()
+	c, err := local.NewClient(t.TempDir(), logging.New(io.Discard, logrus.ErrorLevel))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &backend{Client: c}
+}
+
+func (b *backend) setDown(down bool) {
+	v := int32(0)
+	if down {
+		v = 1
+	}
+	atomic.StoreInt32(&b.down, v)
+}
+
+func (b *backend) isDown() bool {
+	return atomic.LoadInt32(&b.down) == 1
+}
+
+func (b *backend) CheckConnection() bool {
+	return !b.isDown()
+}
+
+func (b *backend) UploadSOC(ctx context.Context, owner, id, signature string, data []byte) ([]byte, error) {
+	if b.isDown() {
+		return nil, errUnreachable
+	}
+	return b.Client.UploadSOC(ctx, owner, id, signature, data)
+}
+
+func (b *backend) UploadChunks(ctx context.Context, chs []swarm.Chunk, tag uint32, pin bool) error {
+	if b.isDown() {
+		return errUnreachable
+	}
+	for _, ch := range chs {
+		if bytes.Contains(ch.Data(), []byte("rejected")) {
+			return errRejected
+		}
+	}
+	return b.Client.UploadChunks(ctx, chs, tag, pin)
+}
+
+func (b *backend) UploadBlob(ctx context.Context, data []byte, tag uint32, pin, encrypt bool) ([]byte, error) {
+	if b.isDown() {
+		return nil, errUnreachable
+	}
+	return b.Client.UploadBlob(ctx, data, tag, pin, encrypt)
+}
+
+func (b *backend) DeleteReference(ctx context.Context, address []byte) error {
+	if b.isDown() {
+		return errUnreachable
+	}
+	return b.Client.DeleteReference(ctx, address)
+}
+
+func TestJournal(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.New(io.Discard, logrus.ErrorLevel)
+
+	t.Run("journal-and-replay", func(t *testing.T) {
+		b := newBackend(t)
+		c, err := journal.NewClient(b, journal.Options{Dir: t.TempDir(), ReplayInterval: time.Hour}, logger)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer c.Close()
+
+		online, err := c.UploadBlob(ctx, []byte("online"), 0, true, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.Pending() != 0 || !b.IsPinned(online) {
+			t.Fatal("online write should go to the backend")
+		}
+
+		b.setDown(true)
+		ref, err := c.UploadBlob(ctx, []byte("offline"), 0, true, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ref) != 64 {
+			t.Fatalf("expected an encrypted reference, got %d bytes", len(ref))
+		}
+		socAddr := uploadSOC(t, c, utils.HashString("journal"), []byte("feed update"))
+		err = c.DeleteReference(ctx, online)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.Pending() != 3 {
+			t.Fatalf("expected 3 pending operations, got %d", c.Pending())
+		}
+		if c.Status().Pending != 3 {
+			t.Fatalf("status should report the pending operations %+v", c.Status())
+		}
+
+		// journaled content is readable before it is replayed
+		data, _, err := c.DownloadBlob(ctx, ref)
+		if err != nil || string(data) != "offline" {
+			t.Fatalf("unexpected journaled blob %s: %v", data, err)
+		}
+		data, err = c.DownloadChunk(ctx, socAddr)
+		if err != nil || !bytes.HasSuffix(data, []byte("feed update")) {
+			t.Fatalf("unexpected journaled soc %s: %v", data, err)
+		}
+
+		// writes keep their order while operations are pending
+		b.setDown(false)
+		ref2, err := c.UploadBlob(ctx, []byte("queued"), 0, false, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.Pending() != 4 {
+			t.Fatalf("expected 4 pending operations, got %d", c.Pending())
+		}
+
+		err = c.Replay(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.Pending() != 0 {
+			t.Fatalf("expected no pending operations, got %d", c.Pending())
+		}
+		for ref, want := range map[string]string{string(ref): "offline", string(ref2): "queued"} {
+			data, _, err := b.DownloadBlob(ctx, []byte(ref))
+			if err != nil || string(data) != want {
+				t.Fatalf("blob was not replayed %s: %v", data, err)
+			}
+		}
+		if !b.IsPinned(ref[:swarm.HashSize]) {
+			t.Fatal("pinned blob should be pinned in the backend")
+		}
+		_, err = b.DownloadChunk(ctx, socAddr)
+		if err != nil {
+			t.Fatalf("soc was not replayed: %v", err)
+		}
+		if b.IsPinned(online) {
+			t.Fatal("deletion was not replayed")
+		}
+	})
+
+	t.Run("dead-letter", func(t *testing.T) {
+		b := newBackend(t)
+		dir := t.TempDir()
+		c, err := journal.NewClient(b, journal.Options{Dir: dir, ReplayInterval: time.Hour}, logger)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.setDown(true)
+		_, err = c.UploadBlob(ctx, []byte("rejected"), 0, true, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		accepted, err := c.UploadBlob(ctx, []byte("accepted"), 0, true, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// failures while the backend is unreachable are retried without limit
+		for i := 0; i < 5; i++ {
+			if c.Replay(ctx) == nil {
+				t.Fatal("replay should fail while the backend is down")
+			}
+		}
+		if c.Pending() != 2 || c.Failed() != 0 {
+			t.Fatalf("expected 2 pending and no failed operations, got %d and %d", c.Pending(), c.Failed())
+		}
+
+		// a rejected operation is retried a few times, then it stops holding back the journal
+		b.setDown(false)
+		for i := 0; i < 2; i++ {
+			err = c.Replay(ctx)
+			if !errors.Is(err, errRejected) {
+				t.Fatalf("expected the rejection, got %v", err)
+			}
+		}
+		err = c.Replay(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.Pending() != 0 || c.Failed() != 1 || c.Status().Failed != 1 {
+			t.Fatalf("expected the rejected operation to be failed, got %+v", c.Status())
+		}
+		data, _, err := b.DownloadBlob(ctx, accepted)
+		if err != nil || string(data) != "accepted" {
+			t.Fatalf("operation after the rejected one was not replayed %s: %v", data, err)
+		}
+		dead, err := os.ReadFile(filepath.Join(dir, "journal.dead"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(dead, []byte(errRejected.Error())) || bytes.Count(dead, []byte{'\n'}) != 1 {
+			t.Fatalf("unexpected dead letter file %s", dead)
+		}
+
+		// writes go to the backend again, and the failed operations are still reported
+		// after a restart
+		_, err = c.UploadBlob(ctx, []byte("online"), 0, true, false)
+		if err != nil || c.Pending() != 0 {
+			t.Fatalf("write should go to the backend: %v", err)
+		}
+		err = c.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err = journal.NewClient(b, journal.Options{Dir: dir, ReplayInterval: time.Hour}, logger)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer c.Close()
+		if c.Failed() != 1 {
+			t.Fatalf("expected 1 failed operation after restart, got %d", c.Failed())
+		}
+	})
+
+	t.Run("persist-across-restart", func(t *testing.T) {
+		b := newBackend(t)
+		dir := t.TempDir()
+		c, err := journal.NewClient(b, journal.Options{Dir: dir, ReplayInterval: time.Hour}, logger)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.setDown(true)
+		var refs [][]byte
+		for _, data := range []string{"first", "second"} {
+			ref, err := c.UploadBlob(ctx, []byte(data), 0, true, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			refs = append(refs, ref)
+		}
+		err = c.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// a write cut off by a crash is dropped
+		f, err := os.OpenFile(filepath.Join(dir, "journal.log"), os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = f.WriteString(`{"seq":3,"kind":"chu`)
+		_ = f.Close()
+
+		c, err = journal.NewClient(b, journal.Options{Dir: dir, ReplayInterval: 10 * time.Millisecond}, logger)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer c.Close()
+		if c.Pending() != 2 {
+			t.Fatalf("expected 2 pending operations after restart, got %d", c.Pending())
+		}
+		ref, err := c.UploadBlob(ctx, []byte("third"), 0, true, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		refs = append(refs, ref)
+
+		// the background task replays once the backend is back
+		b.setDown(false)
+		deadline := time.Now().Add(5 * time.Second)
+		for c.Pending() > 0 && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if c.Pending() != 0 {
+			t.Fatalf("journal was not replayed, %d pending", c.Pending())
+		}
+		for i, want := range []string{"first", "second", "third"} {
+			data, _, err := b.DownloadBlob(ctx, refs[i])
+			if err != nil || string(data) != want {
+				t.Fatalf("blob was not replayed %s: %v", data, err)
+			}
+		}
+	})
+}
+
+func uploadSOC(t *testing.T, client blockstore.Client, id, payload []byte) []byte {
+	t.Helper()
+	key, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := crypto.NewDefaultSigner(key)
+	ch, err := utils.NewChunkWithSpan(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sch, err := soc.New(id, ch).Sign(signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner, err := signer.EthereumAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature := sch.Data()[swarm.HashSize : swarm.HashSize+swarm.SocSignatureSize]
+	addr, err := client.UploadSOC(context.Background(), hex.EncodeToString(owner.Bytes()), hex.EncodeToString(id), hex.EncodeToString(signature), ch.Data())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return addr
+}
@@ -0,0 +1,455 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/file/joiner"
+	"github.com/ethersphere/bee/pkg/soc"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/splitter"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	chunksDir = "chunks"
+	pinsDir   = "pins"
+	tagsDir   = "tags"
+	dirMode   = 0700
+	fileMode  = 0600
+)
+
+var (
+	// ErrNotFound is returned when a chunk or blob is not in the local store.
+	// The message matches the one from the bee client so that the feed lookup
+	// treats a missing chunk the same way for both the backends.
+	ErrNotFound = errors.New("error downloading data")
+
+	// ErrTagNotFound is returned when a tag is not present in the local store
+	ErrTagNotFound = errors.New("tag not found")
+)
+
+type tag struct {
+	UID       uint32    `json:"uid"`
+	Address   string    `json:"address"`
+	StartedAt time.Time `json:"startedAt"`
+	Total     int64     `json:"total"`
+	Processed int64     `json:"processed"`
+	Synced    int64     `json:"synced"`
+}
+
+// Client is a local filesystem backed store that satisfies blockstore.Client.
+// Chunks are addressed exactly like they are in Swarm, so that the content
+// can later be pushed to a bee node without changing any reference.
+type Client struct {
+	root   string
+	tagMu  sync.Mutex
+	logger logging.Logger
+}
+
+// NewClient creates a new local store rooted at the given directory. The directory
+// is created if it does not exist.
+func NewClient(root string, logger logging.Logger) (*Client, error) {
+	for _, d := range []string{chunksDir, pinsDir, tagsDir} {
+		err := os.MkdirAll(filepath.Join(root, d), dirMode)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &Client{
+		root:   root,
+		logger: logger,
+	}, nil
+}
+
+// CheckConnection checks if the store directory is accessible.
+func (s *Client) CheckConnection() bool {
+	info, err := os.Stat(filepath.Join(s.root, chunksDir))
+	if err != nil {
+		return false
+	}
+	return info.IsDir()
+}
+
+// UploadSOC validates and stores a Single Owner Chunk. Uploading a soc with the same
+// owner and id overwrites the previous one.
+func (s *Client) UploadSOC(ctx context.Context, owner, id, signature string, data []byte) (address []byte, err error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	to := time.Now()
+	ch, err := utils.NewChunkWithoutSpan(data)
+	if err != nil {
+		return nil, err
+	}
+	idBytes, err := hex.DecodeString(id)
+	if err != nil {
+		return nil, err
+	}
+	ownerBytes, err := hex.DecodeString(owner)
+	if err != nil {
+		return nil, err
+	}
+	signatureBytes, err := hex.DecodeString(signature)
+	if err != nil {
+		return nil, err
+	}
+	signed, err := soc.NewSigned(idBytes, ch, ownerBytes, signatureBytes)
+	if err != nil {
+		return nil, err
+	}
+	signedChunk, err := signed.Chunk()
+	if err != nil {
+		return nil, err
+	}
+	if !soc.Valid(signedChunk) {
+		return nil, fmt.Errorf("soc chunk failed in validation")
+	}
+	err = s.putChunk(signedChunk)
+	if err != nil {
+		return nil, err
+	}
+	err = s.pin(signedChunk.Address())
+	if err != nil {
+		return nil, err
+	}
+	fields := logrus.Fields{
+		"reference": signedChunk.Address().String(),
+		"duration":  time.Since(to).String(),
+	}
+	s.logger.WithFields(fields).Log(logrus.DebugLevel, "upload soc: ")
+	return signedChunk.Address().Bytes(), nil
+}
+
+// UploadChunk stores a content addressed chunk.
+func (s *Client) UploadChunk(ctx context.Context, ch swarm.Chunk, pin bool) (address []byte, err error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	err = s.putChunk(ch)
+	if err != nil {
+		return nil, err
+	}
+	if pin {
+		err = s.pin(ch.Address())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ch.Address().Bytes(), nil
+}
+
+// UploadSOCs stores the socs one by one
+func (s *Client) UploadSOCs(ctx context.Context, socs []blockstore.SOC) ([][]byte, error) {
+	return blockstore.UploadSOCsParallel(ctx, s, socs)
+}
+
+// UploadChunks stores the content addressed chunks and counts them in the tag.
+func (s *Client) UploadChunks(ctx context.Context, chs []swarm.Chunk, tag uint32, pin bool) error {
+	for _, ch := range chs {
+		_, err := s.UploadChunk(ctx, ch, pin)
+		if err != nil {
+			return err
+		}
+	}
+	if tag > 0 {
+		err := s.incrementTag(tag, int64(len(chs)))
+		if err != nil {
+			s.logger.Warningf("local store: could not update tag %d: %v", tag, err)
+		}
+	}
+	return nil
+}
+
+// UploadBlobs splits and stores the blobs in parallel
+func (s *Client) UploadBlobs(ctx context.Context, blobs [][]byte, tag uint32, pin, encrypt bool) ([][]byte, error) {
+	return blockstore.UploadBlobsParallel(ctx, s, blobs, tag, pin, encrypt)
+}
+
+// DownloadChunk reads a chunk with given address from the store.
+func (s *Client) DownloadChunk(ctx context.Context, address []byte) (data []byte, err error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return s.getChunk(swarm.NewAddress(address))
+}
+
+// UploadBlob splits the data into chunks using the same pipeline as bee, stores them
+// and returns the root reference. If encrypt is set the reference is 64 bytes long and
+// contains the decryption key. Splitting stops as soon as the context is done.
+func (s *Client) UploadBlob(ctx context.Context, data []byte, tag uint32, pin, encrypt bool) (address []byte, err error) {
+	to := time.Now()
+	var count int64
+	ref, err := splitter.Split(func(ch swarm.Chunk) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		count++
+		if s.hasChunk(ch.Address()) {
+			return nil
+		}
+		return s.putChunk(ch)
+	}, data, encrypt)
+	if err != nil {
+		return nil, err
+	}
+	if pin {
+		err = s.pin(ref)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if tag > 0 {
+		err = s.incrementTag(tag, count)
+		if err != nil {
+			s.logger.Warningf("local store: could not update tag %d: %v", tag, err)
+		}
+	}
+	fields := logrus.Fields{
+		"reference": ref.String(),
+		"size":      len(data),
+		"duration":  time.Since(to).String(),
+	}
+	s.logger.WithFields(fields).Log(logrus.DebugLevel, "upload blob: ")
+	return ref.Bytes(), nil
+}
+
+// DownloadBlob joins the chunks of a blob stored with UploadBlob.
+func (s *Client) DownloadBlob(ctx context.Context, address []byte) ([]byte, int, error) {
+	to := time.Now()
+	j, _, err := joiner.New(ctx, &chunkGetter{s: s}, swarm.NewAddress(address))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, http.StatusNotFound, ErrNotFound
+		}
+		return nil, http.StatusInternalServerError, err
+	}
+	data, err := io.ReadAll(j)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, http.StatusNotFound, ErrNotFound
+		}
+		return nil, http.StatusInternalServerError, err
+	}
+	fields := logrus.Fields{
+		"reference": swarm.NewAddress(address).String(),
+		"size":      len(data),
+		"duration":  time.Since(to).String(),
+	}
+	s.logger.WithFields(fields).Log(logrus.DebugLevel, "download blob: ")
+	return data, http.StatusOK, nil
+}
+
+// DeleteReference unpins a reference. Like in bee, the content itself stays readable,
+// as the chunks might be shared with other pinned content.
+func (s *Client) DeleteReference(ctx context.Context, address []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	err := os.Remove(s.pinPath(swarm.NewAddress(address)))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// CreateTag creates a tag for given address
+func (s *Client) CreateTag(ctx context.Context, address []byte) (uint32, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	s.tagMu.Lock()
+	defer s.tagMu.Unlock()
+	t := &tag{
+		StartedAt: time.Now(),
+	}
+	if len(address) > 0 {
+		t.Address = swarm.NewAddress(address).String()
+	}
+	for {
+		t.UID = uint32(time.Now().UnixNano())
+		if t.UID == 0 {
+			continue
+		}
+		if _, err := os.Stat(s.tagPath(t.UID)); os.IsNotExist(err) {
+			break
+		}
+	}
+	return t.UID, s.storeTag(t)
+}
+
+// GetTag gets sync status of a given tag. As there is no network, every
+// stored chunk is reported as synced.
+func (s *Client) GetTag(ctx context.Context, uid uint32) (int64, int64, int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, 0, 0, err
+	}
+	s.tagMu.Lock()
+	defer s.tagMu.Unlock()
+	t, err := s.loadTag(uid)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return t.Total, t.Processed, t.Synced, nil
+}
+
+// IsPinned checks if a reference is pinned in the store
+func (s *Client) IsPinned(address []byte) bool {
+	_, err := os.Stat(s.pinPath(swarm.NewAddress(address)))
+	return err == nil
+}
+
+func (s *Client) incrementTag(uid uint32, count int64) error {
+	s.tagMu.Lock()
+	defer s.tagMu.Unlock()
+	t, err := s.loadTag(uid)
+	if err != nil {
+		return err
+	}
+	t.Total += count
+	t.Processed += count
+	t.Synced += count
+	return s.storeTag(t)
+}
+
+func (s *Client) loadTag(uid uint32) (*tag, error) {
+	data, err := os.ReadFile(s.tagPath(uid))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrTagNotFound
+		}
+		return nil, err
+	}
+	t := &tag{}
+	err = json.Unmarshal(data, t)
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (s *Client) storeTag(t *tag) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.tagPath(t.UID), data)
+}
+
+func (s *Client) putChunk(ch swarm.Chunk) error {
+	p := s.chunkPath(ch.Address())
+	err := os.MkdirAll(filepath.Dir(p), dirMode)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(p, ch.Data())
+}
+
+func (s *Client) getChunk(addr swarm.Address) ([]byte, error) {
+	data, err := os.ReadFile(s.chunkPath(addr))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+func (s *Client) hasChunk(addr swarm.Address) bool {
+	_, err := os.Stat(s.chunkPath(addr))
+	return err == nil
+}
+
+func (s *Client) pin(addr swarm.Address) error {
+	return writeFileAtomic(s.pinPath(addr), []byte{})
+}
+
+// chunkPath shards the chunks in sub directories by the first byte of the address
+// to keep the directories small.
+func (s *Client) chunkPath(addr swarm.Address) string {
+	a := addr.String()
+	return filepath.Join(s.root, chunksDir, a[:2], a)
+}
+
+func (s *Client) pinPath(addr swarm.Address) string {
+	return filepath.Join(s.root, pinsDir, addr.String())
+}
+
+func (s *Client) tagPath(uid uint32) string {
+	return filepath.Join(s.root, tagsDir, fmt.Sprintf("%d", uid))
+}
+
+// writeFileAtomic writes to a temporary file and renames it, so that a crash never
+// leaves a partially written chunk behind.
+func writeFileAtomic(name string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	err = os.Chmod(tmp.Name(), fileMode)
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
+
+// chunkGetter adapts the store to storage.Getter for the bee joiner
+type chunkGetter struct {
+	s *Client
+}
+
+func (g *chunkGetter) Get(ctx context.Context, _ storage.ModeGet, addr swarm.Address) (swarm.Chunk, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	data, err := g.s.getChunk(addr)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, storage.ErrNotFound
+		}
+		return nil, err
+	}
+	return swarm.NewChunk(addr, data), nil
+}
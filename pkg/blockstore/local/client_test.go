@@ -0,0 +1,187 @@
+package local_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io"
+	"testing"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/account"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/local"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+func TestLocalStore(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.New(io.Discard, logrus.ErrorLevel)
+
+	t.Run("blob-roundtrip", func(t *testing.T) {
+		client, err := local.NewClient(t.TempDir(), logger)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, encrypt := range []bool{false, true} {
+			data := make([]byte, 1024*1024+17)
+			_, err = rand.Read(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ref, err := client.UploadBlob(ctx, data, 0, true, encrypt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if encrypt && len(ref) != 64 {
+				t.Fatalf("expected encrypted reference, got %d bytes", len(ref))
+			}
+			got, code, err := client.DownloadBlob(ctx, ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if code != 200 {
+				t.Fatalf("unexpected response code %d", code)
+			}
+			if !bytes.Equal(data, got) {
+				t.Fatal("downloaded data does not match")
+			}
+			if !client.IsPinned(ref) {
+				t.Fatal("reference should be pinned")
+			}
+			err = client.DeleteReference(ctx, ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if client.IsPinned(ref) {
+				t.Fatal("reference should not be pinned")
+			}
+		}
+	})
+
+	t.Run("swarm-compatible-address", func(t *testing.T) {
+		client, err := local.NewClient(t.TempDir(), logger)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data := []byte("hello world")
+		ref, err := client.UploadBlob(ctx, data, 0, false, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ch, err := utils.NewChunkWithSpan(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(ref, ch.Address().Bytes()) {
+			t.Fatal("blob address is not a swarm address")
+		}
+		chData, err := client.DownloadChunk(ctx, ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(chData, ch.Data()) {
+			t.Fatal("chunk data mismatch")
+		}
+	})
+
+	t.Run("persist-across-restart", func(t *testing.T) {
+		dir := t.TempDir()
+		client, err := local.NewClient(dir, logger)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tag, err := client.CreateTag(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data := []byte("persistent data")
+		ref, err := client.UploadBlob(ctx, data, tag, true, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		client, err = local.NewClient(dir, logger)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, _, err := client.DownloadBlob(ctx, ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, got) {
+			t.Fatal("downloaded data does not match")
+		}
+		total, _, synced, err := client.GetTag(ctx, tag)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total == 0 || total != synced {
+			t.Fatalf("unexpected tag status %d/%d", synced, total)
+		}
+	})
+
+	t.Run("feed", func(t *testing.T) {
+		client, err := local.NewClient(t.TempDir(), logger)
+		if err != nil {
+			t.Fatal(err)
+		}
+		acc := account.New(logger)
+		_, _, err = acc.CreateUserAccount("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		fd := feed.New(acc.GetUserAccountInfo(), client, logger)
+		user := acc.GetAddress(account.UserAccountIndex)
+		topic := utils.HashString("topic")
+		_, err = fd.CreateFeed(ctx, topic, user, []byte("first"), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, data, err := fd.GetFeedData(ctx, topic, user, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != "first" {
+			t.Fatalf("unexpected feed data %s", data)
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		client, err := local.NewClient(t.TempDir(), logger)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ref := make([]byte, 32)
+		_, err = client.DownloadChunk(ctx, ref)
+		if err != local.ErrNotFound {
+			t.Fatalf("expected not found, got %v", err)
+		}
+		_, _, err = client.DownloadBlob(ctx, ref)
+		if err == nil {
+			t.Fatal("expected error for missing blob")
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		client, err := local.NewClient(t.TempDir(), logger)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cctx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err = client.UploadBlob(cctx, make([]byte, 1024*1024), 0, false, false)
+		if err != context.Canceled {
+			t.Fatalf("expected cancelled upload, got %v", err)
+		}
+		ref, err := client.UploadBlob(ctx, []byte("stored"), 0, false, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _, err = client.DownloadBlob(cctx, ref)
+		if err == nil {
+			t.Fatal("expected cancelled download to fail")
+		}
+	})
+}
@@ -0,0 +1,588 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"hash/fnv"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/soc"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/bee"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultProbeInterval is the default minimum time between two health checks of an unhealthy node
+	DefaultProbeInterval = 10 * time.Second
+
+	tagCacheSize = 1024
+)
+
+var (
+	// ErrNoNodes is returned when a pool is created without nodes
+	ErrNoNodes = errors.New("pool needs at least one node")
+)
+
+// Node is a blockstore behind the pool, usually a bee client for one endpoint
+type Node struct {
+	Name   string
+	Client blockstore.Client
+}
+
+// Options configures the pool
+type Options struct {
+	// ProbeInterval is the minimum time between two health checks of an unhealthy node
+	ProbeInterval time.Duration
+}
+
+type node struct {
+	Node
+	healthy   int32
+	probing   int32
+	lastProbe int64
+}
+
+func (n *node) isHealthy() bool {
+	return atomic.LoadInt32(&n.healthy) == 1
+}
+
+func (n *node) setHealthy(healthy bool) bool {
+	var v int32
+	if healthy {
+		v = 1
+	}
+	return atomic.SwapInt32(&n.healthy, v) != v
+}
+
+// Client balances the blockstore calls over a pool of nodes.
+//
+// Blobs are read and written round-robin over the healthy nodes. Chunks and SOCs are
+// placed by rendezvous hashing on their address, so that a feed update is always
+// written to and read back from the same node while it is healthy. A call that fails
+// because the node is unreachable is retried on the next node and the node is skipped
+// until a health check with CheckConnection finds it up again.
+type Client struct {
+	nodes         []*node
+	probeInterval time.Duration
+	next          uint32
+	tags          *lru.Cache
+	logger        logging.Logger
+}
+
+// NewClient creates a pool over the given nodes and checks their health
+func NewClient(nodes []Node, opts Options, logger logging.Logger) (*Client, error) {
+	if len(nodes) == 0 {
+		return nil, ErrNoNodes
+	}
+	if opts.ProbeInterval <= 0 {
+		opts.ProbeInterval = DefaultProbeInterval
+	}
+	tags, err := lru.New(tagCacheSize)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	c := &Client{
+		probeInterval: opts.ProbeInterval,
+		tags:          tags,
+		logger:        logger,
+	}
+	for i, n := range nodes {
+		if n.Name == "" {
+			n.Name = strconv.Itoa(i)
+		}
+		c.nodes = append(c.nodes, &node{Node: n})
+	}
+	c.CheckConnection()
+	return c, nil
+}
+
+// CheckConnection checks the health of every node and reports if any of them is up
+func (c *Client) CheckConnection() bool {
+	var wg sync.WaitGroup
+	for _, n := range c.nodes {
+		wg.Add(1)
+		go func(n *node) {
+			defer wg.Done()
+			c.probe(n)
+		}(n)
+	}
+	wg.Wait()
+	for _, n := range c.nodes {
+		if n.isHealthy() {
+			return true
+		}
+	}
+	return false
+}
+
+// Status reports the state of every node. The pool is connected while any node is up.
+func (c *Client) Status() blockstore.Status {
+	status := blockstore.Status{
+		Nodes: make([]blockstore.Status, len(c.nodes)),
+	}
+	var wg sync.WaitGroup
+	for i, n := range c.nodes {
+		wg.Add(1)
+		go func(i int, n *node) {
+			defer wg.Done()
+			var s blockstore.Status
+			if r, ok := n.Client.(blockstore.StatusReporter); ok {
+				s = r.Status()
+			} else {
+				s.Connected = n.Client.CheckConnection()
+			}
+			s.Name = n.Name
+			c.mark(n, s.Connected && s.Breaker != bee.BreakerOpen)
+			status.Nodes[i] = s
+		}(i, n)
+	}
+	wg.Wait()
+	for _, s := range status.Nodes {
+		status.Connected = status.Connected || (s.Connected && s.Breaker != bee.BreakerOpen)
+	}
+	return status
+}
+
+// PostageBatches reports the postage batches of all the reachable nodes, as every node
+// stamps its uploads with its own batches
+func (c *Client) PostageBatches(ctx context.Context) ([]blockstore.PostageBatch, error) {
+	var (
+		batches  []blockstore.PostageBatch
+		reported bool
+		lastErr  = blockstore.ErrPostageUnsupported
+	)
+	for _, n := range c.nodes {
+		r, ok := n.Client.(blockstore.PostageReporter)
+		if !ok {
+			continue
+		}
+		nodeBatches, err := r.PostageBatches(ctx)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		reported = true
+		for _, b := range nodeBatches {
+			b.Node = n.Name
+			batches = append(batches, b)
+		}
+	}
+	if !reported {
+		return nil, lastErr
+	}
+	return batches, nil
+}
+
+// UploadSOC writes the SOC to the node its address hashes to
+func (c *Client) UploadSOC(ctx context.Context, owner, id, signature string, data []byte) (address []byte, err error) {
+	err = c.try(ctx, c.byAddress(socKey(owner, id)), func(n *node) error {
+		address, err = n.Client.UploadSOC(ctx, owner, id, signature, data)
+		return err
+	})
+	return address, err
+}
+
+// UploadSOCs sends one batch to every node that some of the SOC addresses hash to
+func (c *Client) UploadSOCs(ctx context.Context, socs []blockstore.SOC) ([][]byte, error) {
+	addresses := make([][]byte, len(socs))
+	for _, g := range c.group(len(socs), func(i int) []byte { return socKey(socs[i].Owner, socs[i].ID) }) {
+		batch := make([]blockstore.SOC, len(g.indexes))
+		for j, i := range g.indexes {
+			batch[j] = socs[i]
+		}
+		err := c.try(ctx, g.nodes, func(n *node) error {
+			addrs, err := n.Client.UploadSOCs(ctx, batch)
+			if err != nil {
+				return err
+			}
+			for j, i := range g.indexes {
+				addresses[i] = addrs[j]
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return addresses, nil
+}
+
+// UploadChunk writes the chunk to the node its address hashes to
+func (c *Client) UploadChunk(ctx context.Context, ch swarm.Chunk, pin bool) (address []byte, err error) {
+	err = c.try(ctx, c.byAddress(ch.Address().Bytes()), func(n *node) error {
+		address, err = n.Client.UploadChunk(ctx, ch, pin)
+		return err
+	})
+	return address, err
+}
+
+// UploadChunks sends the chunks to the node that created the tag, or one batch to every
+// node that some of the chunk addresses hash to
+func (c *Client) UploadChunks(ctx context.Context, chs []swarm.Chunk, tag uint32, pin bool) error {
+	groups := []group{{nodes: c.roundRobin()}}
+	if tn, ok := c.tagNode(tag); ok {
+		groups[0].nodes = append([]*node{tn}, without(groups[0].nodes, tn)...)
+		for i := range chs {
+			groups[0].indexes = append(groups[0].indexes, i)
+		}
+	} else {
+		groups = c.group(len(chs), func(i int) []byte { return chs[i].Address().Bytes() })
+	}
+	for _, g := range groups {
+		batch := make([]swarm.Chunk, len(g.indexes))
+		for j, i := range g.indexes {
+			batch[j] = chs[i]
+		}
+		err := c.try(ctx, g.nodes, func(n *node) error {
+			t := tag
+			if tn, ok := c.tagNode(tag); ok && tn != n {
+				t = 0
+			}
+			return n.Client.UploadChunks(ctx, batch, t, pin)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UploadBlob writes the blob to the next healthy node, or to the node that created the tag
+func (c *Client) UploadBlob(ctx context.Context, data []byte, tag uint32, pin, encrypt bool) (address []byte, err error) {
+	nodes := c.roundRobin()
+	if tn, ok := c.tagNode(tag); ok {
+		nodes = append([]*node{tn}, without(nodes, tn)...)
+	}
+	err = c.try(ctx, nodes, func(n *node) error {
+		t := tag
+		if tn, ok := c.tagNode(tag); ok && tn != n {
+			// tags are local to a node, the upload is not tracked after a failover
+			t = 0
+		}
+		address, err = n.Client.UploadBlob(ctx, data, t, pin, encrypt)
+		return err
+	})
+	return address, err
+}
+
+// UploadBlobs writes the whole batch to the next healthy node, or to the node that created the tag
+func (c *Client) UploadBlobs(ctx context.Context, blobs [][]byte, tag uint32, pin, encrypt bool) (addresses [][]byte, err error) {
+	nodes := c.roundRobin()
+	if tn, ok := c.tagNode(tag); ok {
+		nodes = append([]*node{tn}, without(nodes, tn)...)
+	}
+	err = c.try(ctx, nodes, func(n *node) error {
+		t := tag
+		if tn, ok := c.tagNode(tag); ok && tn != n {
+			t = 0
+		}
+		addresses, err = n.Client.UploadBlobs(ctx, blobs, t, pin, encrypt)
+		return err
+	})
+	return addresses, err
+}
+
+// DownloadChunk reads the chunk from the node its address hashes to
+func (c *Client) DownloadChunk(ctx context.Context, address []byte) (data []byte, err error) {
+	err = c.try(ctx, c.byAddress(address), func(n *node) error {
+		data, err = n.Client.DownloadChunk(ctx, address)
+		return err
+	})
+	return data, err
+}
+
+// DownloadBlob reads the blob from the next healthy node
+func (c *Client) DownloadBlob(ctx context.Context, address []byte) (data []byte, respCode int, err error) {
+	err = c.try(ctx, c.roundRobin(), func(n *node) error {
+		data, respCode, err = n.Client.DownloadBlob(ctx, address)
+		if err != nil && respCode >= http.StatusInternalServerError {
+			return &unavailableError{err: err}
+		}
+		return err
+	})
+	return data, respCode, err
+}
+
+// DeleteReference unpins the reference on every healthy node, as it is not known which
+// of them pinned it
+func (c *Client) DeleteReference(ctx context.Context, address []byte) error {
+	nodes := make([]*node, 0, len(c.nodes))
+	for _, n := range c.nodes {
+		if n.isHealthy() {
+			nodes = append(nodes, n)
+		}
+	}
+	if len(nodes) == 0 {
+		nodes = c.nodes
+	}
+	var firstErr error
+	deleted := false
+	for _, n := range nodes {
+		err := n.Client.DeleteReference(ctx, address)
+		if err == nil {
+			deleted = true
+			continue
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if c.unavailable(err) {
+			c.mark(n, false)
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	if deleted {
+		return nil
+	}
+	return firstErr
+}
+
+// CreateTag creates the tag on the next healthy node and remembers the node for the upload
+func (c *Client) CreateTag(ctx context.Context, address []byte) (tag uint32, err error) {
+	err = c.try(ctx, c.roundRobin(), func(n *node) error {
+		tag, err = n.Client.CreateTag(ctx, address)
+		if err == nil {
+			c.tags.Add(tag, n)
+		}
+		return err
+	})
+	return tag, err
+}
+
+// GetTag reads the tag from the node that created it
+func (c *Client) GetTag(ctx context.Context, tag uint32) (total, processed, synced int64, err error) {
+	nodes := c.roundRobin()
+	if tn, ok := c.tagNode(tag); ok {
+		nodes = []*node{tn}
+	}
+	err = c.try(ctx, nodes, func(n *node) error {
+		total, processed, synced, err = n.Client.GetTag(ctx, tag)
+		return err
+	})
+	return total, processed, synced, err
+}
+
+// unavailableError marks an error that says the node is down, not that the request is wrong
+type unavailableError struct {
+	err error
+}
+
+func (e *unavailableError) Error() string { return e.err.Error() }
+
+func (e *unavailableError) Unwrap() error { return e.err }
+
+// try calls fn on the nodes in order until one of them answers
+func (c *Client) try(ctx context.Context, nodes []*node, fn func(n *node) error) error {
+	var err error
+	for _, n := range nodes {
+		err = fn(n)
+		if err == nil {
+			c.mark(n, true)
+			return nil
+		}
+		var ue *unavailableError
+		if errors.As(err, &ue) {
+			err = ue.err
+		} else if !c.unavailable(err) {
+			return err
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+		c.mark(n, false)
+		c.logger.WithFields(logrus.Fields{
+			"node":  n.Name,
+			"error": err.Error(),
+		}).Log(logrus.DebugLevel, "failing over to the next node: ")
+	}
+	return err
+}
+
+// unavailable tells if the error says the node could not be reached
+func (*Client) unavailable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, bee.ErrCircuitOpen) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+func (c *Client) mark(n *node, healthy bool) {
+	if !n.setHealthy(healthy) {
+		return
+	}
+	if healthy {
+		c.logger.Infof("bee node %s is up", n.Name)
+	} else {
+		c.logger.Warningf("bee node %s is down", n.Name)
+	}
+}
+
+// probe runs CheckConnection on the node
+func (c *Client) probe(n *node) {
+	atomic.StoreInt64(&n.lastProbe, time.Now().UnixNano())
+	c.mark(n, n.Client.CheckConnection())
+}
+
+// probeDue checks the unhealthy nodes in the background once their probe interval passed
+func (c *Client) probeDue() {
+	now := time.Now().UnixNano()
+	for _, n := range c.nodes {
+		if n.isHealthy() || now-atomic.LoadInt64(&n.lastProbe) < int64(c.probeInterval) {
+			continue
+		}
+		if !atomic.CompareAndSwapInt32(&n.probing, 0, 1) {
+			continue
+		}
+		go func(n *node) {
+			defer atomic.StoreInt32(&n.probing, 0)
+			c.probe(n)
+		}(n)
+	}
+}
+
+// roundRobin returns the nodes starting with the next one in turn, healthy nodes first
+func (c *Client) roundRobin() []*node {
+	c.probeDue()
+	start := int(atomic.AddUint32(&c.next, 1) % uint32(len(c.nodes)))
+	nodes := make([]*node, 0, len(c.nodes))
+	nodes = append(nodes, c.nodes[start:]...)
+	nodes = append(nodes, c.nodes[:start]...)
+	return c.healthyFirst(nodes)
+}
+
+// byAddress returns the nodes ordered by their rendezvous hash with the address,
+// healthy nodes first
+func (c *Client) byAddress(address []byte) []*node {
+	c.probeDue()
+	type scored struct {
+		n     *node
+		score uint64
+	}
+	scores := make([]scored, len(c.nodes))
+	for i, n := range c.nodes {
+		h := fnv.New64a()
+		_, _ = h.Write(address)
+		_, _ = h.Write([]byte(n.Name))
+		scores[i] = scored{n: n, score: binary.BigEndian.Uint64(h.Sum(nil))}
+	}
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].score > scores[j].score
+	})
+	nodes := make([]*node, len(scores))
+	for i, s := range scores {
+		nodes[i] = s.n
+	}
+	return c.healthyFirst(nodes)
+}
+
+// group is a part of a batch that goes to the same node first
+type group struct {
+	nodes   []*node
+	indexes []int
+}
+
+// group splits a batch by the node its addresses hash to, keeping the order of the batch
+func (c *Client) group(n int, address func(i int) []byte) []group {
+	var groups []group
+	byNode := make(map[*node]int)
+	for i := 0; i < n; i++ {
+		nodes := c.byAddress(address(i))
+		g, ok := byNode[nodes[0]]
+		if !ok {
+			g = len(groups)
+			byNode[nodes[0]] = g
+			groups = append(groups, group{nodes: nodes})
+		}
+		groups[g].indexes = append(groups[g].indexes, i)
+	}
+	return groups
+}
+
+// healthyFirst keeps the order of the nodes, but moves the unhealthy ones to the end.
+// They are still tried as the last resort.
+func (*Client) healthyFirst(nodes []*node) []*node {
+	ordered := make([]*node, 0, len(nodes))
+	for _, n := range nodes {
+		if n.isHealthy() {
+			ordered = append(ordered, n)
+		}
+	}
+	for _, n := range nodes {
+		if !n.isHealthy() {
+			ordered = append(ordered, n)
+		}
+	}
+	return ordered
+}
+
+func (c *Client) tagNode(tag uint32) (*node, bool) {
+	if tag == 0 {
+		return nil, false
+	}
+	v, ok := c.tags.Get(tag)
+	if !ok {
+		return nil, false
+	}
+	return v.(*node), true
+}
+
+// socKey is the address of the SOC, which is what its reads are placed by
+func socKey(owner, id string) []byte {
+	ownerBytes, err := hex.DecodeString(owner)
+	if err != nil {
+		return []byte(owner + id)
+	}
+	idBytes, err := hex.DecodeString(id)
+	if err != nil {
+		return []byte(owner + id)
+	}
+	addr, err := soc.CreateAddress(idBytes, ownerBytes)
+	if err != nil {
+		return []byte(owner + id)
+	}
+	return addr.Bytes()
+}
+
+func without(nodes []*node, n *node) []*node {
+	out := make([]*node, 0, len(nodes))
+	for _, o := range nodes {
+		if o != n {
+			out = append(out, o)
+		}
+	}
+	return out
+}
@@ -0,0 +1,240 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool_test
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/soc"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/bee/mock"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/pool"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// testNode shares one store with the other nodes, counts its calls and can be taken down
+type testNode struct {
+	*mock.BeeClient
+	calls int32
+	down  int32
+}
+
+func (n *testNode) unreachable() error {
+	atomic.AddInt32(&n.calls, 1)
+	if atomic.LoadInt32(&n.down) == 1 {
+		return &net.OpError{Op: "dial", Net: "tcp", Err: fmt.Errorf("connection refused")}
+	}
+	return nil
+}
+
+func (n *testNode) CheckConnection() bool {
+	return atomic.LoadInt32(&n.down) == 0
+}
+
+func (n *testNode) UploadSOC(ctx context.Context, owner, id, signature string, data []byte) ([]byte, error) {
+	if err := n.unreachable(); err != nil {
+		return nil, err
+	}
+	return n.BeeClient.UploadSOC(ctx, owner, id, signature, data)
+}
+
+func (n *testNode) DownloadChunk(ctx context.Context, address []byte) ([]byte, error) {
+	if err := n.unreachable(); err != nil {
+		return nil, err
+	}
+	return n.BeeClient.DownloadChunk(ctx, address)
+}
+
+func (n *testNode) DownloadBlob(ctx context.Context, address []byte) ([]byte, int, error) {
+	if err := n.unreachable(); err != nil {
+		return nil, 404, err
+	}
+	return n.BeeClient.DownloadBlob(ctx, address)
+}
+
+func newTestPool(t *testing.T, count int, probeInterval time.Duration) (*pool.Client, []*testNode) {
+	t.Helper()
+	store := mock.NewMockBeeClient()
+	testNodes := make([]*testNode, count)
+	nodes := make([]pool.Node, count)
+	for i := range nodes {
+		testNodes[i] = &testNode{BeeClient: store}
+		nodes[i] = pool.Node{Name: fmt.Sprintf("node-%d", i), Client: testNodes[i]}
+	}
+	p, err := pool.NewClient(nodes, pool.Options{ProbeInterval: probeInterval}, logging.New(io.Discard, logrus.ErrorLevel))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p, testNodes
+}
+
+func resetCalls(nodes []*testNode) {
+	for _, n := range nodes {
+		atomic.StoreInt32(&n.calls, 0)
+	}
+}
+
+func TestPool(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("no-nodes", func(t *testing.T) {
+		_, err := pool.NewClient(nil, pool.Options{}, logging.New(io.Discard, logrus.ErrorLevel))
+		if err != pool.ErrNoNodes {
+			t.Fatalf("expected no nodes error, got %v", err)
+		}
+	})
+
+	t.Run("reads-are-spread", func(t *testing.T) {
+		p, nodes := newTestPool(t, 3, time.Minute)
+		ref, err := p.UploadBlob(ctx, []byte("spread"), 0, false, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 6; i++ {
+			data, _, err := p.DownloadBlob(ctx, ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != "spread" {
+				t.Fatalf("unexpected data %s", data)
+			}
+		}
+		for i, n := range nodes {
+			if n.calls != 2 {
+				t.Fatalf("node %d got %d reads, expected 2", i, n.calls)
+			}
+		}
+	})
+
+	t.Run("soc-writes-and-reads-use-one-node", func(t *testing.T) {
+		p, nodes := newTestPool(t, 3, time.Minute)
+		addr := uploadSOC(t, p, "pinned", []byte("feed"))
+		for i := 0; i < 5; i++ {
+			_, err := p.DownloadChunk(ctx, addr)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		used := 0
+		for _, n := range nodes {
+			switch n.calls {
+			case 0:
+			case 6:
+				used++
+			default:
+				t.Fatalf("soc calls should go to one node, got %d", n.calls)
+			}
+		}
+		if used != 1 {
+			t.Fatalf("expected one node to be used, got %d", used)
+		}
+	})
+
+	t.Run("failover", func(t *testing.T) {
+		p, nodes := newTestPool(t, 2, 50*time.Millisecond)
+		ref, err := p.UploadBlob(ctx, []byte("failover"), 0, false, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		atomic.StoreInt32(&nodes[0].down, 1)
+		for i := 0; i < 4; i++ {
+			_, _, err := p.DownloadBlob(ctx, ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		if nodes[0].calls != 1 || nodes[1].calls != 4 {
+			t.Fatalf("down node should be skipped after the first failure, got %d and %d calls", nodes[0].calls, nodes[1].calls)
+		}
+		status := p.Status()
+		if !status.Connected || len(status.Nodes) != 2 || status.Nodes[0].Connected || status.Nodes[0].Name != "node-0" {
+			t.Fatalf("unexpected status %+v", status)
+		}
+
+		// the node is used again once a health check finds it up
+		atomic.StoreInt32(&nodes[0].down, 0)
+		time.Sleep(60 * time.Millisecond)
+		_, _, err = p.DownloadBlob(ctx, ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+		resetCalls(nodes)
+		for i := 0; i < 4; i++ {
+			_, _, err := p.DownloadBlob(ctx, ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		if nodes[0].calls != 2 || nodes[1].calls != 2 {
+			t.Fatalf("recovered node should get reads again, got %d and %d calls", nodes[0].calls, nodes[1].calls)
+		}
+	})
+
+	t.Run("not-found-does-not-fail-over", func(t *testing.T) {
+		p, nodes := newTestPool(t, 3, time.Minute)
+		_, err := p.DownloadChunk(ctx, make([]byte, 32))
+		if err == nil {
+			t.Fatal("expected error for missing chunk")
+		}
+		total := int32(0)
+		for _, n := range nodes {
+			total += n.calls
+		}
+		if total != 1 {
+			t.Fatalf("expected a single call, got %d", total)
+		}
+	})
+}
+
+func uploadSOC(t *testing.T, p *pool.Client, topic string, payload []byte) []byte {
+	t.Helper()
+	key, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := crypto.NewDefaultSigner(key)
+	ch, err := utils.NewChunkWithSpan(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := utils.HashString(topic)
+	sch, err := soc.New(id, ch).Sign(signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner, err := signer.EthereumAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature := sch.Data()[swarm.HashSize : swarm.HashSize+swarm.SocSignatureSize]
+	addr, err := p.UploadSOC(context.Background(), hex.EncodeToString(owner.Bytes()), hex.EncodeToString(id), hex.EncodeToString(signature), ch.Data())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return addr
+}
@@ -0,0 +1,524 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/file/joiner"
+	"github.com/ethersphere/bee/pkg/soc"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/splitter"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultRegion is used to sign the requests when no region is configured
+	DefaultRegion = "us-east-1"
+
+	chunksPrefix   = "chunks/"
+	blobsPrefix    = "blobs/"
+	tagsPrefix     = "tags/"
+	requestTimeout = 60 * time.Second
+	// blobRecordHeader is the size of the address and length in front of every chunk
+	// of a blob object
+	blobRecordHeader = swarm.HashSize + 4
+)
+
+var (
+	// ErrNotFound is returned when a chunk or blob is not in the bucket. The message
+	// matches the one from the bee client so that the feed lookup treats a missing
+	// chunk the same way for all the backends.
+	ErrNotFound = errors.New("error downloading data")
+
+	// ErrTagNotFound is returned when a tag is not present in the bucket
+	ErrTagNotFound = errors.New("tag not found")
+
+	errObjectNotFound = errors.New("object not found")
+)
+
+// Options configures the bucket of the S3 client
+type Options struct {
+	// Endpoint is the url of the S3 compatible service, like http://localhost:9000
+	Endpoint  string
+	Bucket    string
+	Region    string
+	AccessKey string
+	SecretKey string
+	// Prefix is prepended to every object key, so that a bucket can be shared
+	Prefix string
+}
+
+type tag struct {
+	UID       uint32    `json:"uid"`
+	Address   string    `json:"address"`
+	StartedAt time.Time `json:"startedAt"`
+	Total     int64     `json:"total"`
+	Processed int64     `json:"processed"`
+	Synced    int64     `json:"synced"`
+}
+
+type s3Error struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// Client keeps the data in an S3 compatible bucket and satisfies blockstore.Client.
+// Chunks and socs are objects keyed by their swarm address, so a soc upload with the
+// same owner and id overwrites the previous one. The chunks of a blob are kept together
+// in a single object keyed by the root address, and tags are emulated with small json
+// objects. There are no pins: everything stays in the bucket until DeleteReference
+// removes it.
+type Client struct {
+	endpoint *url.URL
+	bucket   string
+	prefix   string
+	signer   *signer
+	client   *http.Client
+	tagMu    sync.Mutex
+	logger   logging.Logger
+}
+
+// NewClient creates a client for the bucket. The bucket must exist.
+func NewClient(opts Options, logger logging.Logger) (*Client, error) {
+	if opts.Endpoint == "" || opts.Bucket == "" {
+		return nil, fmt.Errorf("s3 endpoint and bucket are required")
+	}
+	endpoint, err := url.Parse(strings.TrimSuffix(opts.Endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid s3 endpoint %s: %w", opts.Endpoint, err)
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return nil, fmt.Errorf("invalid s3 endpoint %s", opts.Endpoint)
+	}
+	region := opts.Region
+	if region == "" {
+		region = DefaultRegion
+	}
+	prefix := strings.Trim(opts.Prefix, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	return &Client{
+		endpoint: endpoint,
+		bucket:   opts.Bucket,
+		prefix:   prefix,
+		signer: &signer{
+			accessKey: opts.AccessKey,
+			secretKey: opts.SecretKey,
+			region:    region,
+		},
+		client: &http.Client{Timeout: requestTimeout},
+		logger: logger,
+	}, nil
+}
+
+// CheckConnection checks if the bucket is reachable with the configured credentials
+func (s *Client) CheckConnection() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	resp, err := s.do(ctx, http.MethodHead, "", nil)
+	if err != nil {
+		return false
+	}
+	_ = resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
+
+// UploadSOC validates and stores a Single Owner Chunk. The object is keyed by the soc
+// address, so uploading a soc with the same owner and id overwrites the previous one.
+func (s *Client) UploadSOC(ctx context.Context, owner, id, signature string, data []byte) (address []byte, err error) {
+	to := time.Now()
+	ch, err := utils.NewChunkWithoutSpan(data)
+	if err != nil {
+		return nil, err
+	}
+	idBytes, err := hex.DecodeString(id)
+	if err != nil {
+		return nil, err
+	}
+	ownerBytes, err := hex.DecodeString(owner)
+	if err != nil {
+		return nil, err
+	}
+	signatureBytes, err := hex.DecodeString(signature)
+	if err != nil {
+		return nil, err
+	}
+	signed, err := soc.NewSigned(idBytes, ch, ownerBytes, signatureBytes)
+	if err != nil {
+		return nil, err
+	}
+	signedChunk, err := signed.Chunk()
+	if err != nil {
+		return nil, err
+	}
+	if !soc.Valid(signedChunk) {
+		return nil, fmt.Errorf("soc chunk failed in validation")
+	}
+	err = s.putObject(ctx, chunksPrefix+signedChunk.Address().String(), signedChunk.Data())
+	if err != nil {
+		return nil, err
+	}
+	fields := logrus.Fields{
+		"reference": signedChunk.Address().String(),
+		"duration":  time.Since(to).String(),
+	}
+	s.logger.WithFields(fields).Log(logrus.DebugLevel, "upload soc: ")
+	return signedChunk.Address().Bytes(), nil
+}
+
+// UploadSOCs stores the socs with parallel requests
+func (s *Client) UploadSOCs(ctx context.Context, socs []blockstore.SOC) ([][]byte, error) {
+	return blockstore.UploadSOCsParallel(ctx, s, socs)
+}
+
+// UploadChunk stores a content addressed chunk
+func (s *Client) UploadChunk(ctx context.Context, ch swarm.Chunk, _ bool) (address []byte, err error) {
+	err = s.putObject(ctx, chunksPrefix+ch.Address().String(), ch.Data())
+	if err != nil {
+		return nil, err
+	}
+	return ch.Address().Bytes(), nil
+}
+
+// UploadChunks stores the chunks with parallel requests and counts them in the tag
+func (s *Client) UploadChunks(ctx context.Context, chs []swarm.Chunk, tag uint32, pin bool) error {
+	err := blockstore.UploadChunksParallel(ctx, s, chs, pin)
+	if err != nil {
+		return err
+	}
+	if tag > 0 {
+		err = s.incrementTag(ctx, tag, int64(len(chs)))
+		if err != nil {
+			s.logger.Warningf("s3 store: could not update tag %d: %v", tag, err)
+		}
+	}
+	return nil
+}
+
+// UploadBlob splits the data into chunks using the same pipeline as bee and stores
+// them as one object. If encrypt is set the chunks are encrypted before they leave
+// the server and the reference is 64 bytes long and contains the decryption key.
+func (s *Client) UploadBlob(ctx context.Context, data []byte, tag uint32, _, encrypt bool) (address []byte, err error) {
+	to := time.Now()
+	var (
+		buf   bytes.Buffer
+		count int64
+	)
+	ref, err := splitter.Split(func(ch swarm.Chunk) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		count++
+		var header [blobRecordHeader]byte
+		copy(header[:swarm.HashSize], ch.Address().Bytes())
+		binary.BigEndian.PutUint32(header[swarm.HashSize:], uint32(len(ch.Data())))
+		buf.Write(header[:])
+		buf.Write(ch.Data())
+		return nil
+	}, data, encrypt)
+	if err != nil {
+		return nil, err
+	}
+	err = s.putObject(ctx, blobsPrefix+hex.EncodeToString(ref.Bytes()[:swarm.HashSize]), buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if tag > 0 {
+		err = s.incrementTag(ctx, tag, count)
+		if err != nil {
+			s.logger.Warningf("s3 store: could not update tag %d: %v", tag, err)
+		}
+	}
+	fields := logrus.Fields{
+		"reference": ref.String(),
+		"size":      len(data),
+		"duration":  time.Since(to).String(),
+	}
+	s.logger.WithFields(fields).Log(logrus.DebugLevel, "upload blob: ")
+	return ref.Bytes(), nil
+}
+
+// UploadBlobs stores the blobs with parallel requests
+func (s *Client) UploadBlobs(ctx context.Context, blobs [][]byte, tag uint32, pin, encrypt bool) ([][]byte, error) {
+	return blockstore.UploadBlobsParallel(ctx, s, blobs, tag, pin, encrypt)
+}
+
+// DownloadChunk reads a chunk or soc with given address from the bucket
+func (s *Client) DownloadChunk(ctx context.Context, address []byte) (data []byte, err error) {
+	data, err = s.getObject(ctx, chunksPrefix+swarm.NewAddress(address).String())
+	if errors.Is(err, errObjectNotFound) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+// DownloadBlob joins the chunks of a blob. Blobs stored with UploadBlob are read with
+// a single request, blobs uploaded chunk by chunk are read from the chunk objects.
+func (s *Client) DownloadBlob(ctx context.Context, address []byte) ([]byte, int, error) {
+	to := time.Now()
+	if len(address) < swarm.HashSize {
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid reference length %d", len(address))
+	}
+	getter := &chunkGetter{s: s}
+	object, err := s.getObject(ctx, blobsPrefix+hex.EncodeToString(address[:swarm.HashSize]))
+	switch {
+	case err == nil:
+		getter.chunks, err = parseBlob(object)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+	case !errors.Is(err, errObjectNotFound):
+		return nil, http.StatusInternalServerError, err
+	}
+	j, _, err := joiner.New(ctx, getter, swarm.NewAddress(address))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, http.StatusNotFound, ErrNotFound
+		}
+		return nil, http.StatusInternalServerError, err
+	}
+	data, err := io.ReadAll(j)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, http.StatusNotFound, ErrNotFound
+		}
+		return nil, http.StatusInternalServerError, err
+	}
+	fields := logrus.Fields{
+		"reference": swarm.NewAddress(address).String(),
+		"size":      len(data),
+		"duration":  time.Since(to).String(),
+	}
+	s.logger.WithFields(fields).Log(logrus.DebugLevel, "download blob: ")
+	return data, http.StatusOK, nil
+}
+
+// DeleteReference removes the blob or chunk objects of a reference from the bucket.
+// Unlike unpinning in bee, the content is gone right away.
+func (s *Client) DeleteReference(ctx context.Context, address []byte) error {
+	if len(address) > swarm.HashSize {
+		address = address[:swarm.HashSize]
+	}
+	a := hex.EncodeToString(address)
+	err := s.deleteObject(ctx, blobsPrefix+a)
+	if err != nil {
+		return err
+	}
+	return s.deleteObject(ctx, chunksPrefix+a)
+}
+
+// CreateTag creates a tag object for given address
+func (s *Client) CreateTag(ctx context.Context, address []byte) (uint32, error) {
+	t := &tag{
+		StartedAt: time.Now(),
+	}
+	if len(address) > 0 {
+		t.Address = swarm.NewAddress(address).String()
+	}
+	var uid [4]byte
+	for t.UID == 0 {
+		_, err := rand.Read(uid[:])
+		if err != nil {
+			return 0, err
+		}
+		t.UID = binary.BigEndian.Uint32(uid[:])
+	}
+	s.tagMu.Lock()
+	defer s.tagMu.Unlock()
+	return t.UID, s.storeTag(ctx, t)
+}
+
+// GetTag gets sync status of a given tag. Every stored chunk is reported as synced, as
+// the bucket has nothing to sync.
+func (s *Client) GetTag(ctx context.Context, uid uint32) (int64, int64, int64, error) {
+	s.tagMu.Lock()
+	defer s.tagMu.Unlock()
+	t, err := s.loadTag(ctx, uid)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return t.Total, t.Processed, t.Synced, nil
+}
+
+func (s *Client) incrementTag(ctx context.Context, uid uint32, count int64) error {
+	s.tagMu.Lock()
+	defer s.tagMu.Unlock()
+	t, err := s.loadTag(ctx, uid)
+	if err != nil {
+		return err
+	}
+	t.Total += count
+	t.Processed += count
+	t.Synced += count
+	return s.storeTag(ctx, t)
+}
+
+func (s *Client) loadTag(ctx context.Context, uid uint32) (*tag, error) {
+	data, err := s.getObject(ctx, fmt.Sprintf("%s%d", tagsPrefix, uid))
+	if err != nil {
+		if errors.Is(err, errObjectNotFound) {
+			return nil, ErrTagNotFound
+		}
+		return nil, err
+	}
+	t := &tag{}
+	err = json.Unmarshal(data, t)
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (s *Client) storeTag(ctx context.Context, t *tag) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	return s.putObject(ctx, fmt.Sprintf("%s%d", tagsPrefix, t.UID), data)
+}
+
+func (s *Client) putObject(ctx context.Context, key string, data []byte) error {
+	resp, err := s.do(ctx, http.MethodPut, key, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return responseError(http.MethodPut, key, resp)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+func (s *Client) getObject(ctx context.Context, key string) ([]byte, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil, errObjectNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(http.MethodGet, key, resp)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// deleteObject removes an object, a missing object is not an error
+func (s *Client) deleteObject(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return responseError(http.MethodDelete, key, resp)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// do sends a signed path style request for the object key, or for the bucket if the
+// key is empty
+func (s *Client) do(ctx context.Context, method, key string, body []byte) (*http.Response, error) {
+	u := *s.endpoint
+	u.Path = u.Path + "/" + s.bucket
+	if key != "" {
+		u.Path += "/" + s.prefix + key
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	payloadHash := emptyPayloadHash
+	if len(body) > 0 {
+		payloadHash = hashHex(body)
+	}
+	s.signer.sign(req, payloadHash, time.Now())
+	return s.client.Do(req)
+}
+
+func responseError(method, key string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	e := &s3Error{}
+	if xml.Unmarshal(body, e) == nil && e.Code != "" {
+		return fmt.Errorf("s3 %s %s: %s: %s", method, key, e.Code, e.Message)
+	}
+	return fmt.Errorf("s3 %s %s: %s", method, key, resp.Status)
+}
+
+// parseBlob reads the chunks of a blob object, each stored as address, length and data
+func parseBlob(object []byte) (map[string][]byte, error) {
+	chunks := make(map[string][]byte)
+	for len(object) > 0 {
+		if len(object) < blobRecordHeader {
+			return nil, fmt.Errorf("corrupt blob object")
+		}
+		addr := object[:swarm.HashSize]
+		size := int(binary.BigEndian.Uint32(object[swarm.HashSize:blobRecordHeader]))
+		object = object[blobRecordHeader:]
+		if size > len(object) {
+			return nil, fmt.Errorf("corrupt blob object")
+		}
+		chunks[string(addr)] = object[:size]
+		object = object[size:]
+	}
+	return chunks, nil
+}
+
+// chunkGetter adapts the chunks of a blob object to storage.Getter for the bee joiner.
+// Chunks that are not in the blob are read from the chunk objects.
+type chunkGetter struct {
+	s      *Client
+	chunks map[string][]byte
+}
+
+func (g *chunkGetter) Get(ctx context.Context, _ storage.ModeGet, addr swarm.Address) (swarm.Chunk, error) {
+	if data, ok := g.chunks[string(addr.Bytes())]; ok {
+		return swarm.NewChunk(addr, data), nil
+	}
+	data, err := g.s.DownloadChunk(ctx, addr.Bytes())
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, storage.ErrNotFound
+		}
+		return nil, err
+	}
+	return swarm.NewChunk(addr, data), nil
+}
@@ -0,0 +1,276 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/soc"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/s3"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/s3/mock"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/splitter"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	accessKey = "minioadmin"
+	bucket    = "fairos"
+)
+
+func newClient(t *testing.T, prefix string) (*s3.Client, *mock.Server) {
+	t.Helper()
+	server := mock.NewServer(accessKey, bucket)
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+	c, err := s3.NewClient(s3.Options{
+		Endpoint:  ts.URL,
+		Bucket:    bucket,
+		AccessKey: accessKey,
+		SecretKey: "minioadmin",
+		Prefix:    prefix,
+	}, logging.New(io.Discard, logrus.ErrorLevel))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, server
+}
+
+func TestS3(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("connection", func(t *testing.T) {
+		c, _ := newClient(t, "")
+		if !c.CheckConnection() {
+			t.Fatal("expected the bucket to be reachable")
+		}
+		server := mock.NewServer("other", bucket)
+		ts := httptest.NewServer(server)
+		defer ts.Close()
+		c, err := s3.NewClient(s3.Options{Endpoint: ts.URL, Bucket: bucket, AccessKey: accessKey}, logging.New(io.Discard, logrus.ErrorLevel))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.CheckConnection() {
+			t.Fatal("wrong credentials should not connect")
+		}
+		_, err = c.UploadBlob(ctx, []byte("denied"), 0, true, false)
+		if err == nil || !strings.Contains(err.Error(), "AccessDenied") {
+			t.Fatalf("expected access denied, got %v", err)
+		}
+	})
+
+	t.Run("blob-roundtrip", func(t *testing.T) {
+		c, server := newClient(t, "pods")
+		data := make([]byte, 3*swarm.ChunkSize+100)
+		_, err := rand.Read(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, encrypt := range []bool{false, true} {
+			ref, err := c.UploadBlob(ctx, data, 0, true, encrypt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, code, err := c.DownloadBlob(ctx, ref)
+			if err != nil || code != http.StatusOK {
+				t.Fatalf("download failed with %d: %v", code, err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Fatal("blob content mismatch")
+			}
+			object, ok := server.Object(bucket, "pods/blobs/"+hex.EncodeToString(ref[:swarm.HashSize]))
+			if !ok {
+				t.Fatalf("blob should be a single object, got %v", server.Keys(bucket))
+			}
+			if encrypt && bytes.Contains(object, data[:swarm.ChunkSize]) {
+				t.Fatal("encrypted blob should not be stored in plain text")
+			}
+		}
+	})
+
+	t.Run("swarm-compatible-address", func(t *testing.T) {
+		c, _ := newClient(t, "")
+		data := []byte("same address as in swarm")
+		ref, err := c.UploadBlob(ctx, data, 0, false, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := splitter.Split(func(swarm.Chunk) error { return nil }, data, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(ref, want.Bytes()) {
+			t.Fatal("reference should match the swarm address")
+		}
+	})
+
+	t.Run("blob-from-chunks", func(t *testing.T) {
+		c, _ := newClient(t, "")
+		data := make([]byte, 2*swarm.ChunkSize)
+		_, err := rand.Read(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var chs []swarm.Chunk
+		ref, err := splitter.Split(func(ch swarm.Chunk) error {
+			chs = append(chs, ch)
+			return nil
+		}, data, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tag, err := c.CreateTag(ctx, ref.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = c.UploadChunks(ctx, chs, tag, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, _, err := c.DownloadBlob(ctx, ref.Bytes())
+		if err != nil || !bytes.Equal(got, data) {
+			t.Fatalf("blob uploaded chunk by chunk should be readable: %v", err)
+		}
+		total, processed, synced, err := c.GetTag(ctx, tag)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != int64(len(chs)) || processed != total || synced != total {
+			t.Fatalf("unexpected tag %d %d %d", total, processed, synced)
+		}
+	})
+
+	t.Run("soc-overwrite", func(t *testing.T) {
+		c, _ := newClient(t, "")
+		key, err := crypto.GenerateSecp256k1Key()
+		if err != nil {
+			t.Fatal(err)
+		}
+		signer := crypto.NewDefaultSigner(key)
+		id := utils.HashString("feed")
+		var addresses [][]byte
+		for _, payload := range []string{"first", "second"} {
+			addresses = append(addresses, uploadSOC(t, c, signer, id, []byte(payload)))
+		}
+		if !bytes.Equal(addresses[0], addresses[1]) {
+			t.Fatal("same owner and id should give the same address")
+		}
+		data, err := c.DownloadChunk(ctx, addresses[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.HasSuffix(data, []byte("second")) {
+			t.Fatalf("expected the latest soc, got %q", data)
+		}
+
+		forged, err := utils.NewChunkWithSpan([]byte("forged"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = c.UploadSOC(ctx, hex.EncodeToString(make([]byte, 20)), hex.EncodeToString(id), hex.EncodeToString(make([]byte, swarm.SocSignatureSize)), forged.Data())
+		if err == nil {
+			t.Fatal("invalid soc should be rejected")
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		c, _ := newClient(t, "")
+		addr := make([]byte, swarm.HashSize)
+		addr[0] = 1
+		_, err := c.DownloadChunk(ctx, addr)
+		if !errors.Is(err, s3.ErrNotFound) {
+			t.Fatalf("expected not found, got %v", err)
+		}
+		_, code, err := c.DownloadBlob(ctx, addr)
+		if !errors.Is(err, s3.ErrNotFound) || code != http.StatusNotFound {
+			t.Fatalf("expected not found, got %d: %v", code, err)
+		}
+		_, _, _, err = c.GetTag(ctx, 1)
+		if !errors.Is(err, s3.ErrTagNotFound) {
+			t.Fatalf("expected tag not found, got %v", err)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		c, server := newClient(t, "")
+		ref, err := c.UploadBlob(ctx, []byte("to be deleted"), 0, true, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = c.DeleteReference(ctx, ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _, err = c.DownloadBlob(ctx, ref)
+		if !errors.Is(err, s3.ErrNotFound) {
+			t.Fatalf("expected deleted blob to be gone, got %v", err)
+		}
+		if keys := server.Keys(bucket); len(keys) != 0 {
+			t.Fatalf("expected an empty bucket, got %v", keys)
+		}
+		// deleting again is not an error
+		err = c.DeleteReference(ctx, ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		c, _ := newClient(t, "")
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := c.UploadBlob(ctx, []byte("cancelled"), 0, false, false)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected cancelled upload, got %v", err)
+		}
+	})
+}
+
+func uploadSOC(t *testing.T, c *s3.Client, signer crypto.Signer, id, payload []byte) []byte {
+	t.Helper()
+	ch, err := utils.NewChunkWithSpan(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sch, err := soc.New(id, ch).Sign(signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner, err := signer.EthereumAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature := sch.Data()[swarm.HashSize : swarm.HashSize+swarm.SocSignatureSize]
+	addr, err := c.UploadSOC(context.Background(), hex.EncodeToString(owner.Bytes()), hex.EncodeToString(id), hex.EncodeToString(signature), ch.Data())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return addr
+}
@@ -0,0 +1,99 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package splitter splits data into swarm chunks the same way bee does for the /bytes
+// endpoint, so that blobs can be stored chunk by chunk.
+package splitter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethersphere/bee/pkg/encryption"
+	"github.com/ethersphere/bee/pkg/file/pipeline"
+	"github.com/ethersphere/bee/pkg/file/pipeline/bmt"
+	enc "github.com/ethersphere/bee/pkg/file/pipeline/encryption"
+	"github.com/ethersphere/bee/pkg/file/pipeline/feeder"
+	"github.com/ethersphere/bee/pkg/file/pipeline/hashtrie"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// The pipelines below are the same as the ones bee uses for the /bytes endpoint.
+// They are rebuilt here with a minimal store writer, because the bee store writer
+// pulls in the whole tags and p2p dependency tree.
+
+var errInvalidData = errors.New("splitter: invalid data")
+
+// PutFunc is called with every chunk of the split data
+type PutFunc func(ch swarm.Chunk) error
+
+func newPipeline(put PutFunc, encrypt bool) pipeline.Interface {
+	if encrypt {
+		tw := hashtrie.NewHashTrieWriter(swarm.ChunkSize, 64, swarm.HashSize+encryption.KeyLength, func() pipeline.ChainWriter {
+			return enc.NewEncryptionWriter(encryption.NewChunkEncrypter(), bmt.NewBmtWriter(newStoreWriter(put, nil)))
+		})
+		b := bmt.NewBmtWriter(newStoreWriter(put, tw))
+		return feeder.NewChunkFeederWriter(swarm.ChunkSize, enc.NewEncryptionWriter(encryption.NewChunkEncrypter(), b))
+	}
+	tw := hashtrie.NewHashTrieWriter(swarm.ChunkSize, swarm.Branches, swarm.HashSize, func() pipeline.ChainWriter {
+		return bmt.NewBmtWriter(newStoreWriter(put, nil))
+	})
+	return feeder.NewChunkFeederWriter(swarm.ChunkSize, bmt.NewBmtWriter(newStoreWriter(put, tw)))
+}
+
+// Split runs the data through the pipeline and returns the root reference
+func Split(put PutFunc, data []byte, encrypt bool) (swarm.Address, error) {
+	p := newPipeline(put, encrypt)
+	n, err := p.Write(data)
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+	if n < len(data) {
+		return swarm.ZeroAddress, fmt.Errorf("pipeline short write: %d mismatches %d", n, len(data))
+	}
+	sum, err := p.Sum()
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+	return swarm.NewAddress(sum), nil
+}
+
+type storeWriter struct {
+	put  PutFunc
+	next pipeline.ChainWriter
+}
+
+func newStoreWriter(put PutFunc, next pipeline.ChainWriter) pipeline.ChainWriter {
+	return &storeWriter{put: put, next: next}
+}
+
+func (w *storeWriter) ChainWrite(p *pipeline.PipeWriteArgs) error {
+	if p.Ref == nil || p.Data == nil {
+		return errInvalidData
+	}
+	err := w.put(swarm.NewChunk(swarm.NewAddress(p.Ref), p.Data))
+	if err != nil {
+		return err
+	}
+	if w.next == nil {
+		return nil
+	}
+	return w.next.ChainWrite(p)
+}
+
+func (w *storeWriter) Sum() ([]byte, error) {
+	return w.next.Sum()
+}
@@ -18,6 +18,7 @@ package collection
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"path/filepath"
@@ -35,9 +36,16 @@ type Batch struct {
 	idx           *Index
 	memDb         *Manifest
 	manifestStack []*Manifest
+	pending       []pendingManifest
 	storageCount  uint64
 }
 
+// pendingManifest is a manifest serialised for storing in the next flush
+type pendingManifest struct {
+	name string
+	data []byte
+}
+
 // NewBatch creates a new batch index to be used in a KV table or a Document database.
 func NewBatch(idx *Index) (*Batch, error) {
 	return &Batch{
@@ -46,13 +54,13 @@ func NewBatch(idx *Index) (*Batch, error) {
 }
 
 // PutNumber inserts index as a number.
-func (b *Batch) PutNumber(key float64, refValue []byte, apnd, memory bool) error {
+func (b *Batch) PutNumber(ctx context.Context, key float64, refValue []byte, apnd, memory bool) error {
 	stringKey := fmt.Sprintf("%020.20g", key)
-	return b.Put(stringKey, refValue, apnd, memory)
+	return b.Put(ctx, stringKey, refValue, apnd, memory)
 }
 
 // Put creates an index entry given a key string and value.
-func (b *Batch) Put(key string, refValue []byte, apnd, memory bool) error {
+func (b *Batch) Put(ctx context.Context, key string, refValue []byte, apnd, memory bool) error {
 	if b.idx.isReadOnlyFeed() { // skipcq: TCV-001
 		return ErrReadOnlyIndex
 	}
@@ -66,8 +74,6 @@ func (b *Batch) Put(key string, refValue []byte, apnd, memory bool) error {
 		}
 		b.memDb = manifest
 	}
-	ctx := context.Background()
-
 	stringKey := key
 	if b.idx.indexType == NumberIndex {
 		i, err := strconv.ParseInt(stringKey, 10, 64)
@@ -80,7 +86,7 @@ func (b *Batch) Put(key string, refValue []byte, apnd, memory bool) error {
 }
 
 // Get extracts an index value from an index given a key.
-func (b *Batch) Get(key string) ([][]byte, error) {
+func (b *Batch) Get(ctx context.Context, key string) ([][]byte, error) {
 	if b.memDb == nil {
 		return nil, ErrEntryNotFound
 	}
@@ -94,7 +100,7 @@ func (b *Batch) Get(key string) ([][]byte, error) {
 			stringKey = fmt.Sprintf("%020d", i)
 		}
 
-		_, manifest, i, err := b.idx.findManifest(nil, b.memDb, stringKey)
+		_, manifest, i, err := b.idx.findManifest(ctx, nil, b.memDb, stringKey)
 		if err != nil {
 			return nil, err
 		}
@@ -105,13 +111,13 @@ func (b *Batch) Get(key string) ([][]byte, error) {
 
 // DelNumber deletes a number index key and value.
 // skipcq: TCV-001
-func (b *Batch) DelNumber(key float64) ([][]byte, error) {
+func (b *Batch) DelNumber(ctx context.Context, key float64) ([][]byte, error) {
 	stringKey := fmt.Sprintf("%020.20g", key)
-	return b.Del(stringKey)
+	return b.Del(ctx, stringKey)
 }
 
 // Del deletes a index entry.
-func (b *Batch) Del(key string) ([][]byte, error) {
+func (b *Batch) Del(ctx context.Context, key string) ([][]byte, error) {
 	if b.idx.isReadOnlyFeed() { // skipcq: TCV-001
 		return nil, ErrReadOnlyIndex
 	}
@@ -132,7 +138,7 @@ func (b *Batch) Del(key string) ([][]byte, error) {
 			}
 			stringKey = fmt.Sprintf("%020d", i)
 		}
-		parentManifest, manifest, i, err := b.idx.findManifest(nil, b.memDb, stringKey)
+		parentManifest, manifest, i, err := b.idx.findManifest(ctx, nil, b.memDb, stringKey)
 		if err != nil { // skipcq: TCV-001
 			return nil, err
 		}
@@ -159,7 +165,7 @@ func (b *Batch) Del(key string) ([][]byte, error) {
 }
 
 // Write commits the raw index file in to the Swarm network.
-func (b *Batch) Write(podFile string) (*Manifest, error) {
+func (b *Batch) Write(ctx context.Context, podFile string) (*Manifest, error) {
 	if b.idx.isReadOnlyFeed() { // skipcq: TCV-001
 		return nil, ErrReadOnlyIndex
 	}
@@ -168,26 +174,26 @@ func (b *Batch) Write(podFile string) (*Manifest, error) {
 	}
 
 	if b.memDb.dirtyFlag {
-		diskManifest, err := b.idx.loadManifest(b.memDb.Name, b.idx.encryptionPassword)
+		diskManifest, err := b.idx.loadManifest(ctx, b.memDb.Name, b.idx.encryptionPassword)
 		if err != nil && errors.Is(err, ErrNoManifestFound) { // skipcq: TCV-001
 			return nil, err
 		}
 		diskManifest.PodFile = podFile
 		b.memDb.PodFile = podFile
 		b.idx.podFile = podFile
-		return b.mergeAndWriteManifest(diskManifest, b.memDb)
+		return b.mergeAndWriteManifest(ctx, diskManifest, b.memDb)
 	}
 	return b.memDb, nil // skipcq: TCV-001
 }
 
-func (b *Batch) mergeAndWriteManifest(diskManifest, memManifest *Manifest) (*Manifest, error) {
+func (b *Batch) mergeAndWriteManifest(ctx context.Context, diskManifest, memManifest *Manifest) (*Manifest, error) {
 	// merge the mem manifest with the disk version
 	if memManifest.dirtyFlag {
 		for _, dirtyEntry := range memManifest.Entries {
 			diskManifest.dirtyFlag = true
 			b.idx.addEntryToManifestSortedLexicographically(diskManifest, dirtyEntry)
 			if dirtyEntry.EType == IntermediateEntry && dirtyEntry.Manifest != nil { // skipcq: TCV-001
-				err := b.storeMemoryManifest(dirtyEntry.Manifest, 0)
+				err := b.storeMemoryManifest(ctx, dirtyEntry.Manifest, 0)
 				if err != nil {
 					return nil, err
 				}
@@ -201,25 +207,31 @@ func (b *Batch) mergeAndWriteManifest(diskManifest, memManifest *Manifest) (*Man
 			dirtyEntry.Manifest = nil
 		}
 
+		err := b.emptyManifestStack(ctx)
+		if err != nil { // skipcq: TCV-001
+			return nil, err
+		}
+
+		// store all the new manifests in one batch before the disk manifest points to them
+		err = b.flushManifests(ctx)
+		if err != nil { // skipcq: TCV-001
+			return nil, err
+		}
+
 		if diskManifest.dirtyFlag {
 			// save th disk manifest
-			err := b.idx.updateManifest(diskManifest, b.idx.encryptionPassword)
+			err := b.idx.updateManifest(ctx, diskManifest, b.idx.encryptionPassword)
 			if err != nil { // skipcq: TCV-001
 				return nil, err
 			}
 		}
 
-		err := b.emptyManifestStack()
-		if err != nil { // skipcq: TCV-001
-			return nil, err
-		}
-
 		return diskManifest, nil
 	}
 	return diskManifest, nil
 }
 
-func (b *Batch) emptyManifestStack() error {
+func (b *Batch) emptyManifestStack(ctx context.Context) error {
 	var tempStack []*Manifest
 
 	// copy the data to tempStack
@@ -227,21 +239,21 @@ func (b *Batch) emptyManifestStack() error {
 	b.manifestStack = nil
 
 	for _, man := range tempStack { // skipcq: TCV-001
-		err := b.storeMemoryManifest(man, 0)
+		err := b.storeMemoryManifest(ctx, man, 0)
 		if err != nil {
 			return err
 		}
 	}
 
 	if len(b.manifestStack) > 0 { // skipcq: TCV-001
-		return b.emptyManifestStack()
+		return b.emptyManifestStack(ctx)
 	}
 
 	return nil
 }
 
 // skipcq: TCV-001
-func (b *Batch) storeMemoryManifest(manifest *Manifest, depth int) error {
+func (b *Batch) storeMemoryManifest(ctx context.Context, manifest *Manifest, depth int) error {
 	/*
 		var wg sync.WaitGroup
 		errC := make(chan error)
@@ -262,7 +274,7 @@ func (b *Batch) storeMemoryManifest(manifest *Manifest, depth int) error {
 			// defer func() {
 			//	 wg.Done()
 			// }()
-			err := b.storeMemoryManifest(entry.Manifest, depth+1)
+			err := b.storeMemoryManifest(ctx, entry.Manifest, depth+1)
 			if err != nil {
 				return err
 			}
@@ -284,9 +296,8 @@ func (b *Batch) storeMemoryManifest(manifest *Manifest, depth int) error {
 	//	 return err
 	// }
 
-	// store this manifest
-	// go func() {
-	err := b.idx.storeManifest(manifest, b.idx.encryptionPassword)
+	// queue this manifest, it is stored with the others in flushManifests
+	err := b.queueManifest(manifest)
 	if err != nil {
 		return err
 	}
@@ -299,3 +310,29 @@ func (b *Batch) storeMemoryManifest(manifest *Manifest, depth int) error {
 	// }()
 	return nil
 }
+
+// queueManifest serialises the manifest right away, as its in-memory children are
+// detached after it is queued
+func (b *Batch) queueManifest(manifest *Manifest) error {
+	data, err := json.Marshal(manifest)
+	if err != nil { // skipcq: TCV-001
+		return ErrManifestUnmarshall
+	}
+	b.pending = append(b.pending, pendingManifest{name: manifest.Name, data: data})
+	return nil
+}
+
+// flushManifests stores the queued manifests with one blob and one feed batch
+func (b *Batch) flushManifests(ctx context.Context) error {
+	if len(b.pending) == 0 {
+		return nil
+	}
+	names := make([]string, len(b.pending))
+	data := make([][]byte, len(b.pending))
+	for i, p := range b.pending {
+		names[i] = p.name
+		data[i] = p.data
+	}
+	b.pending = nil
+	return b.idx.storeManifests(ctx, names, data, b.idx.encryptionPassword)
+}
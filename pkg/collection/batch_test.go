@@ -18,6 +18,7 @@ package collection_test
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"testing"
 
@@ -32,6 +33,7 @@ import (
 )
 
 func TestBatchIndex(t *testing.T) {
+	ctx := context.Background()
 	mockClient := mock.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -54,20 +56,20 @@ func TestBatchIndex(t *testing.T) {
 		}
 
 		batchDocs := addBatchDocs(t, batch, mockClient)
-		_, err = batch.Write("")
+		_, err = batch.Write(ctx, "")
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// create the iterator
-		itr, err := index.NewStringIterator("", "", 100)
+		itr, err := index.NewStringIterator(ctx, "", "", 100)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// iterate through the keys and check for the values returned
 		count := 0
-		for itr.Next() {
+		for itr.Next(ctx) {
 			value := getValue(t, itr.Value(), mockClient)
 			if !bytes.Equal(batchDocs[itr.StringKey()], value) {
 				t.Fatalf("expected value %s but got %s for the key %s", string(batchDocs[itr.StringKey()]), string(value), itr.StringKey())
@@ -91,20 +93,20 @@ func TestBatchIndex(t *testing.T) {
 		}
 
 		batchDocs := addBatchDocs(t, batch, mockClient)
-		_, err = batch.Write("")
+		_, err = batch.Write(ctx, "")
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// create the iterator
-		itr, err := index.NewStringIterator("", "", 100)
+		itr, err := index.NewStringIterator(ctx, "", "", 100)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// iterate through the keys and check for the values returned
 		count := 0
-		for itr.Next() {
+		for itr.Next(ctx) {
 			value := getValue(t, itr.Value(), mockClient)
 			if !bytes.Equal(batchDocs[itr.StringKey()], value) {
 				t.Fatalf("expected value %s but got %s for the key %s", string(batchDocs[itr.StringKey()]), string(value), itr.StringKey())
@@ -127,37 +129,37 @@ func TestBatchIndex(t *testing.T) {
 			t.Fatal(err)
 		}
 		_ = addBatchDocs(t, batch, mockClient)
-		_, err = batch.Write("")
+		_, err = batch.Write(ctx, "")
 		if err != nil {
 			t.Fatal(err)
 		}
 		// create the iterator
-		itr, err := index.NewStringIterator("", "", 100)
+		itr, err := index.NewStringIterator(ctx, "", "", 100)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// iterate through the keys and check for the values returned
-		for itr.Next() {
-			_, err = batch.Del(itr.StringKey())
+		for itr.Next(ctx) {
+			_, err = batch.Del(ctx, itr.StringKey())
 			if err != nil {
 				t.Fatal(err)
 			}
 		}
-		_, err = batch.Write("")
+		_, err = batch.Write(ctx, "")
 		if err != nil {
 			t.Fatal(err)
 		}
-		index2, err := collection.OpenIndex("pod1", "testdb_batch_2", "key", podPassword, fd, ai, user, mockClient, logger)
+		index2, err := collection.OpenIndex(ctx, "pod1", "testdb_batch_2", "key", podPassword, fd, ai, user, mockClient, logger)
 		if err != nil {
 			t.Fatal(err)
 		}
 		// create the iterator
-		itr2, err := index2.NewStringIterator("", "", 100)
+		itr2, err := index2.NewStringIterator(ctx, "", "", 100)
 		if err != nil {
 			t.Fatal(err)
 		}
-		if itr2.Next() {
+		if itr2.Next(ctx) {
 			t.Fatal("should be not element")
 		}
 	})
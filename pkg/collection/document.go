@@ -114,7 +114,7 @@ func NewDocumentStore(podName string, fd *feed.API, ai *account.Info, user utils
 }
 
 // CreateDocumentDB creates a new document database and its related indexes.
-func (d *Document) CreateDocumentDB(dbName, encryptionPassword string, indexes map[string]IndexType, mutable bool) error {
+func (d *Document) CreateDocumentDB(ctx context.Context, dbName, encryptionPassword string, indexes map[string]IndexType, mutable bool) error {
 	d.logger.Info("creating document db: ", dbName)
 	if d.fd.IsReadOnlyFeed() {
 		d.logger.Errorf("creating document db: %v", ErrReadOnlyIndex)
@@ -128,7 +128,7 @@ func (d *Document) CreateDocumentDB(dbName, encryptionPassword string, indexes m
 	}
 
 	// load the existing db's and see if this name is already there
-	docTables, err := d.LoadDocumentDBSchemas(encryptionPassword)
+	docTables, err := d.LoadDocumentDBSchemas(ctx, encryptionPassword)
 	if err != nil { // skipcq: TCV-001
 		return err
 	}
@@ -139,7 +139,7 @@ func (d *Document) CreateDocumentDB(dbName, encryptionPassword string, indexes m
 
 	// since this db is not present already, create the table
 	d.logger.Info("creating simple index: ", DefaultIndexFieldName)
-	err = CreateIndex(d.podName, dbName, DefaultIndexFieldName, encryptionPassword, StringIndex, d.fd, d.user, d.client, mutable)
+	err = CreateIndex(ctx, d.podName, dbName, DefaultIndexFieldName, encryptionPassword, StringIndex, d.fd, d.user, d.client, mutable)
 	if err != nil { // skipcq: TCV-001
 		return err
 	}
@@ -158,7 +158,7 @@ func (d *Document) CreateDocumentDB(dbName, encryptionPassword string, indexes m
 	// Now add the other indexes to simpleIndexes array
 	for fieldName, fieldType := range indexes {
 		// create the simple index
-		err = CreateIndex(d.podName, dbName, fieldName, encryptionPassword, fieldType, d.fd, d.user, d.client, mutable)
+		err = CreateIndex(ctx, d.podName, dbName, fieldName, encryptionPassword, fieldType, d.fd, d.user, d.client, mutable)
 		if err != nil { // skipcq: TCV-001
 			return err
 		}
@@ -179,15 +179,19 @@ func (d *Document) CreateDocumentDB(dbName, encryptionPassword string, indexes m
 	}
 
 	// add the simple indexes to the schema
-	docTables[dbName] = DBSchema{
-		Name:          dbName,
-		Mutable:       mutable,
-		SimpleIndexes: simpleIndexes,
-		MapIndexes:    mapIndexes,
-		ListIndexes:   listIndexes,
-	}
-
-	err = d.storeDocumentDBSchemas(encryptionPassword, docTables)
+	err = d.modifyDocumentDBSchemas(ctx, encryptionPassword, func(docTables map[string]DBSchema) error {
+		if _, ok := docTables[dbName]; ok { // skipcq: TCV-001
+			return ErrDocumentDBAlreadyPresent
+		}
+		docTables[dbName] = DBSchema{
+			Name:          dbName,
+			Mutable:       mutable,
+			SimpleIndexes: simpleIndexes,
+			MapIndexes:    mapIndexes,
+			ListIndexes:   listIndexes,
+		}
+		return nil
+	})
 	if err != nil { // skipcq: TCV-001
 		d.logger.Errorf("creating document db: %v", err.Error())
 		return err
@@ -197,7 +201,7 @@ func (d *Document) CreateDocumentDB(dbName, encryptionPassword string, indexes m
 }
 
 // OpenDocumentDB open a document database and its related indexes.
-func (d *Document) OpenDocumentDB(dbName, encryptionPassword string) error {
+func (d *Document) OpenDocumentDB(ctx context.Context, dbName, encryptionPassword string) error {
 	d.logger.Info("opening document db: ", dbName)
 	// check if the db is already present and opened
 	if d.IsDBOpened(dbName) { // skipcq: TCV-001
@@ -206,7 +210,7 @@ func (d *Document) OpenDocumentDB(dbName, encryptionPassword string) error {
 	}
 
 	// load the existing db's and see if this name is present
-	docTables, err := d.LoadDocumentDBSchemas(encryptionPassword)
+	docTables, err := d.LoadDocumentDBSchemas(ctx, encryptionPassword)
 	if err != nil { // skipcq: TCV-001
 		d.logger.Errorf("opening document db: %v", err.Error())
 		return err
@@ -221,7 +225,7 @@ func (d *Document) OpenDocumentDB(dbName, encryptionPassword string) error {
 	simpleIndexs := make(map[string]*Index)
 	for _, si := range schema.SimpleIndexes {
 		d.logger.Info("opening simple index: ", si.FieldName)
-		idx, err := OpenIndex(d.podName, dbName, si.FieldName, encryptionPassword, d.fd, d.ai, d.user, d.client, d.logger)
+		idx, err := OpenIndex(ctx, d.podName, dbName, si.FieldName, encryptionPassword, d.fd, d.ai, d.user, d.client, d.logger)
 		if err != nil { // skipcq: TCV-001
 			d.logger.Errorf("opening simple index: %v", err.Error())
 			return err
@@ -233,7 +237,7 @@ func (d *Document) OpenDocumentDB(dbName, encryptionPassword string) error {
 	mapIndexs := make(map[string]*Index)
 	for _, mi := range schema.MapIndexes {
 		d.logger.Info("opening map index: ", mi.FieldName)
-		idx, err := OpenIndex(d.podName, dbName, mi.FieldName, encryptionPassword, d.fd, d.ai, d.user, d.client, d.logger)
+		idx, err := OpenIndex(ctx, d.podName, dbName, mi.FieldName, encryptionPassword, d.fd, d.ai, d.user, d.client, d.logger)
 		if err != nil { // skipcq: TCV-001
 			d.logger.Errorf("opening map index: %v", err.Error())
 			return err
@@ -245,7 +249,7 @@ func (d *Document) OpenDocumentDB(dbName, encryptionPassword string) error {
 	listIndexes := make(map[string]*Index)
 	for _, li := range schema.ListIndexes {
 		d.logger.Info("opening list index: ", li.FieldName)
-		idx, err := OpenIndex(d.podName, dbName, li.FieldName, encryptionPassword, d.fd, d.ai, d.user, d.client, d.logger)
+		idx, err := OpenIndex(ctx, d.podName, dbName, li.FieldName, encryptionPassword, d.fd, d.ai, d.user, d.client, d.logger)
 		if err != nil { // skipcq: TCV-001
 			d.logger.Errorf("opening list index: %v", err.Error())
 			return err
@@ -269,7 +273,7 @@ func (d *Document) OpenDocumentDB(dbName, encryptionPassword string) error {
 }
 
 // DeleteDocumentDB a document DB, all its data and its related indxes.
-func (d *Document) DeleteDocumentDB(dbName, encryptionPassword string) error {
+func (d *Document) DeleteDocumentDB(ctx context.Context, dbName, encryptionPassword string) error {
 	d.logger.Info("deleting document db: ", dbName)
 	if d.fd.IsReadOnlyFeed() { // skipcq: TCV-001
 		d.logger.Errorf("deleting document db: %v", ErrReadOnlyIndex)
@@ -277,7 +281,7 @@ func (d *Document) DeleteDocumentDB(dbName, encryptionPassword string) error {
 	}
 
 	// load the existing db's and see if this name is already there
-	docTables, err := d.LoadDocumentDBSchemas(encryptionPassword)
+	docTables, err := d.LoadDocumentDBSchemas(ctx, encryptionPassword)
 	if err != nil { // skipcq: TCV-001
 		d.logger.Errorf("deleting document db: %v", err.Error())
 		return err
@@ -292,7 +296,7 @@ func (d *Document) DeleteDocumentDB(dbName, encryptionPassword string) error {
 
 	// open and delete the indexes
 	if !d.IsDBOpened(dbName) {
-		err = d.OpenDocumentDB(dbName, encryptionPassword)
+		err = d.OpenDocumentDB(ctx, dbName, encryptionPassword)
 		if err != nil { // skipcq: TCV-001
 			d.logger.Errorf("deleting document db: %v", err.Error())
 			return err
@@ -304,7 +308,7 @@ func (d *Document) DeleteDocumentDB(dbName, encryptionPassword string) error {
 	//TODO: before deleting the indexes, unpin all the documents referenced in the ID index
 	for _, si := range docDB.simpleIndexes {
 		d.logger.Info("deleting simple index: ", si.name, si.indexType)
-		err = si.DeleteIndex(encryptionPassword)
+		err = si.DeleteIndex(ctx, encryptionPassword)
 		if err != nil { // skipcq: TCV-001
 			d.logger.Errorf("deleting simple index: %v", err.Error())
 			return err
@@ -312,7 +316,7 @@ func (d *Document) DeleteDocumentDB(dbName, encryptionPassword string) error {
 	}
 	for _, mi := range docDB.mapIndexes {
 		d.logger.Info("deleting map index: ", mi.name, mi.indexType)
-		err = mi.DeleteIndex(encryptionPassword)
+		err = mi.DeleteIndex(ctx, encryptionPassword)
 		if err != nil { // skipcq: TCV-001
 			d.logger.Errorf("deleting map index: %v", err.Error())
 			return err
@@ -320,22 +324,18 @@ func (d *Document) DeleteDocumentDB(dbName, encryptionPassword string) error {
 	}
 	for _, li := range docDB.listIndexes {
 		d.logger.Info("deleting list index: ", li.name, li.indexType)
-		err = li.DeleteIndex(encryptionPassword)
+		err = li.DeleteIndex(ctx, encryptionPassword)
 		if err != nil { // skipcq: TCV-001
 			d.logger.Errorf("deleting map index: %v", err.Error())
 			return err
 		}
 	}
 
-	// delete the document db from the DB file
-	delete(docTables, dbName)
-
-	if len(docTables) == 0 {
-		docTables = map[string]DBSchema{}
-	}
-
-	// store the rest of the document db
-	err = d.storeDocumentDBSchemas(encryptionPassword, docTables)
+	// delete the document db from the DB file and store the rest of the document db
+	err = d.modifyDocumentDBSchemas(ctx, encryptionPassword, func(docTables map[string]DBSchema) error {
+		delete(docTables, dbName)
+		return nil
+	})
 	if err != nil { // skipcq: TCV-001
 		d.logger.Errorf("deleting document db: ", err.Error())
 		return err
@@ -346,23 +346,24 @@ func (d *Document) DeleteDocumentDB(dbName, encryptionPassword string) error {
 }
 
 // DeleteAllDocumentDBs deletes all document DBs, all their data and related indxes.
-func (d *Document) DeleteAllDocumentDBs(encryptionPassword string) error {
+func (d *Document) DeleteAllDocumentDBs(ctx context.Context, encryptionPassword string) error {
 	if d.fd.IsReadOnlyFeed() { // skipcq: TCV-001
 		d.logger.Errorf("deleting document db: %v", ErrReadOnlyIndex)
 		return ErrReadOnlyIndex
 	}
 
 	// load the existing db's and see if this name is already there
-	docTables, err := d.LoadDocumentDBSchemas(encryptionPassword)
+	docTables, err := d.LoadDocumentDBSchemas(ctx, encryptionPassword)
 	if err != nil { // skipcq: TCV-001
 		d.logger.Errorf("deleting document db: %v", err.Error())
 		return err
 	}
 
+	var deleted []string
 	for dbName := range docTables {
 		// open and delete the indexes
 		if !d.IsDBOpened(dbName) {
-			err = d.OpenDocumentDB(dbName, encryptionPassword)
+			err = d.OpenDocumentDB(ctx, dbName, encryptionPassword)
 			if err != nil { // skipcq: TCV-001
 				d.logger.Errorf("deleting document db: %v", err.Error())
 				return err
@@ -374,7 +375,7 @@ func (d *Document) DeleteAllDocumentDBs(encryptionPassword string) error {
 		//TODO: before deleting the indexes, unpin all the documents referenced in the ID index
 		for _, si := range docDB.simpleIndexes {
 			d.logger.Info("deleting simple index: ", si.name, si.indexType)
-			err = si.DeleteIndex(encryptionPassword)
+			err = si.DeleteIndex(ctx, encryptionPassword)
 			if err != nil { // skipcq: TCV-001
 				d.logger.Errorf("deleting simple index: %v", err.Error())
 				return err
@@ -382,7 +383,7 @@ func (d *Document) DeleteAllDocumentDBs(encryptionPassword string) error {
 		}
 		for _, mi := range docDB.mapIndexes {
 			d.logger.Info("deleting map index: ", mi.name, mi.indexType)
-			err = mi.DeleteIndex(encryptionPassword)
+			err = mi.DeleteIndex(ctx, encryptionPassword)
 			if err != nil { // skipcq: TCV-001
 				d.logger.Errorf("deleting map index: %v", err.Error())
 				return err
@@ -390,20 +391,24 @@ func (d *Document) DeleteAllDocumentDBs(encryptionPassword string) error {
 		}
 		for _, li := range docDB.listIndexes {
 			d.logger.Info("deleting list index: ", li.name, li.indexType)
-			err = li.DeleteIndex(encryptionPassword)
+			err = li.DeleteIndex(ctx, encryptionPassword)
 			if err != nil { // skipcq: TCV-001
 				d.logger.Errorf("deleting map index: %v", err.Error())
 				return err
 			}
 		}
 
-		// delete the document db from the DB file
-		delete(docTables, dbName)
-
+		deleted = append(deleted, dbName)
 		d.logger.Info("deleted document db: ", dbName)
 	}
-	docTables = map[string]DBSchema{}
-	err = d.storeDocumentDBSchemas(encryptionPassword, docTables)
+
+	// the document dbs created by other writers in the meantime are kept
+	err = d.modifyDocumentDBSchemas(ctx, encryptionPassword, func(docTables map[string]DBSchema) error {
+		for _, dbName := range deleted {
+			delete(docTables, dbName)
+		}
+		return nil
+	})
 	if err != nil { // skipcq: TCV-001
 		d.logger.Errorf("deleting document db: ", err.Error())
 		return err
@@ -412,7 +417,7 @@ func (d *Document) DeleteAllDocumentDBs(encryptionPassword string) error {
 }
 
 // Count counts the number of document in a document DB which matches a given expression
-func (d *Document) Count(dbName, expr string) (uint64, error) {
+func (d *Document) Count(ctx context.Context, dbName, expr string) (uint64, error) {
 	d.logger.Info("counting document db: ", dbName, expr)
 	db := d.getOpenedDb(dbName)
 	if db == nil { // skipcq: TCV-001
@@ -427,7 +432,7 @@ func (d *Document) Count(dbName, expr string) (uint64, error) {
 			d.logger.Errorf("counting document db: %v", ErrIndexNotPresent)
 			return 0, ErrIndexNotPresent
 		}
-		return idx.CountIndex(idx.encryptionPassword)
+		return idx.CountIndex(ctx, idx.encryptionPassword)
 	}
 
 	// count documents based on expression
@@ -452,14 +457,14 @@ func (d *Document) Count(dbName, expr string) (uint64, error) {
 
 	switch idx.indexType {
 	case StringIndex:
-		itr, err := idx.NewStringIterator(fieldValue, "", -1)
+		itr, err := idx.NewStringIterator(ctx, fieldValue, "", -1)
 		if err != nil { // skipcq: TCV-001
 			d.logger.Errorf("counting document db: ", err.Error())
 			return 0, err
 		}
 		switch operator {
 		case "=":
-			itr.Next()
+			itr.Next(ctx)
 			re, err := regexp.Compile(fieldValue)
 			if err != nil { // skipcq: TCV-001
 				d.logger.Errorf("counting document db: %v", err.Error())
@@ -478,7 +483,7 @@ func (d *Document) Count(dbName, expr string) (uint64, error) {
 				return 0, err
 			}
 
-			for itr.Next() {
+			for itr.Next(ctx) {
 				matched := re.Match([]byte(itr.StringKey()))
 				if matched {
 					refs := itr.ValueAll()
@@ -489,21 +494,21 @@ func (d *Document) Count(dbName, expr string) (uint64, error) {
 			return count, nil
 		}
 	case MapIndex, ListIndex:
-		itr, err := idx.NewStringIterator(fieldValue, "", -1)
+		itr, err := idx.NewStringIterator(ctx, fieldValue, "", -1)
 		if err != nil { // skipcq: TCV-001
 			d.logger.Errorf("counting document db: ", err.Error())
 			return 0, err
 		}
 		switch operator {
 		case "=":
-			itr.Next()
+			itr.Next(ctx)
 			refs := itr.ValueAll()
 			count := uint64(len(refs))
 			d.logger.Info("counting document db: ", dbName, expr, count)
 			return count, nil
 		case "=>": // skipcq: TCV-001
 			var count uint64
-			for itr.Next() {
+			for itr.Next(ctx) {
 				refs := itr.ValueAll()
 				count = count + uint64(len(refs))
 			}
@@ -511,7 +516,7 @@ func (d *Document) Count(dbName, expr string) (uint64, error) {
 			return count, nil
 		case ">": // skipcq: TCV-001
 			var count uint64
-			for itr.Next() {
+			for itr.Next(ctx) {
 				if itr.StringKey() == fieldValue {
 					continue
 				}
@@ -527,21 +532,21 @@ func (d *Document) Count(dbName, expr string) (uint64, error) {
 			d.logger.Errorf("counting document db: ", err.Error())
 			return 0, err
 		}
-		itr, err := idx.NewIntIterator(start, -1, -1)
+		itr, err := idx.NewIntIterator(ctx, start, -1, -1)
 		if err != nil { // skipcq: TCV-001
 			d.logger.Errorf("counting document db: ", err.Error())
 			return 0, err
 		}
 		switch operator {
 		case "=":
-			itr.Next()
+			itr.Next(ctx)
 			refs := itr.ValueAll()
 			count := uint64(len(refs))
 			d.logger.Info("counting document db: ", dbName, expr, count)
 			return count, nil
 		case "=>":
 			var count uint64
-			for itr.Next() {
+			for itr.Next(ctx) {
 				refs := itr.ValueAll()
 				count = count + uint64(len(refs))
 			}
@@ -549,7 +554,7 @@ func (d *Document) Count(dbName, expr string) (uint64, error) {
 			return count, nil
 		case ">":
 			var count uint64
-			for itr.Next() {
+			for itr.Next(ctx) {
 				if itr.IntegerKey() == start {
 					continue
 				}
@@ -570,7 +575,7 @@ func (d *Document) Count(dbName, expr string) (uint64, error) {
 }
 
 // Put inserts a document in to a document database.
-func (d *Document) Put(dbName string, doc []byte) error {
+func (d *Document) Put(ctx context.Context, dbName string, doc []byte) error {
 	d.logger.Info("inserting in to document db: ", dbName, len(doc))
 	if d.fd.IsReadOnlyFeed() { // skipcq: TCV-001
 		d.logger.Errorf("inserting in to document db: ", ErrReadOnlyIndex)
@@ -614,12 +619,12 @@ func (d *Document) Put(dbName string, doc []byte) error {
 			return ErrInvalidDocumentId
 		} else {
 			idIndex := db.simpleIndexes[DefaultIndexFieldName]
-			refs, err := idIndex.Get(v)
+			refs, err := idIndex.Get(ctx, v)
 			if err != nil {
 				break
 			}
 			if len(refs) > 0 {
-				err = d.Del(dbName, v)
+				err = d.Del(ctx, dbName, v)
 				if err != nil { // skipcq: TCV-001
 					d.logger.Errorf("inserting in to document db: ", err.Error())
 					return err
@@ -633,7 +638,7 @@ func (d *Document) Put(dbName string, doc []byte) error {
 	}
 
 	// upload the document
-	ref, err := d.client.UploadBlob(doc, 0, true, true)
+	ref, err := d.client.UploadBlob(ctx, doc, 0, true, true)
 	if err != nil { // skipcq: TCV-001
 		d.logger.Errorf("inserting in to document db: ", err.Error())
 		return err
@@ -659,7 +664,7 @@ func (d *Document) Put(dbName string, doc []byte) error {
 			if field == DefaultIndexFieldName {
 				apnd = false
 			}
-			err := index.Put(v.(string), ref, StringIndex, apnd)
+			err := index.Put(ctx, v.(string), ref, StringIndex, apnd)
 			if err != nil { // skipcq: TCV-001
 				d.logger.Errorf("inserting in to document db: ", err.Error())
 				return err
@@ -670,7 +675,7 @@ func (d *Document) Put(dbName string, doc []byte) error {
 			for keyField, vf := range valMap {
 				valueField := vf.(string)
 				mapField := keyField + valueField
-				err := index.Put(mapField, ref, StringIndex, true)
+				err := index.Put(ctx, mapField, ref, StringIndex, true)
 				if err != nil { // skipcq: TCV-001
 					d.logger.Errorf("inserting in to document db: ", err.Error())
 					return err
@@ -680,7 +685,7 @@ func (d *Document) Put(dbName string, doc []byte) error {
 		case ListIndex:
 			valList := v.([]interface{})
 			for _, listVal := range valList {
-				err := index.Put(listVal.(string), ref, StringIndex, true)
+				err := index.Put(ctx, listVal.(string), ref, StringIndex, true)
 				if err != nil {
 					d.logger.Errorf("inserting in to document db: ", err.Error())
 					return err
@@ -690,7 +695,7 @@ func (d *Document) Put(dbName string, doc []byte) error {
 		case NumberIndex:
 			val := v.(float64)
 			// valStr := strconv.FormatFloat(val, 'f', 6, 64)
-			err := index.PutNumber(val, ref, NumberIndex, true)
+			err := index.PutNumber(ctx, val, ref, NumberIndex, true)
 			if err != nil { // skipcq: TCV-001
 				d.logger.Errorf("inserting in to document db: ", err.Error())
 				return err
@@ -708,7 +713,7 @@ func (d *Document) Put(dbName string, doc []byte) error {
 }
 
 // Get retrieves a specific document from a document database matching the dcument id.
-func (d *Document) Get(dbName, id, podPassword string) ([]byte, error) {
+func (d *Document) Get(ctx context.Context, dbName, id, podPassword string) ([]byte, error) {
 	d.logger.Info("getting from document db: ", dbName, id)
 	db := d.getOpenedDb(dbName)
 	if db == nil { // skipcq: TCV-001
@@ -717,7 +722,7 @@ func (d *Document) Get(dbName, id, podPassword string) ([]byte, error) {
 	}
 
 	idIndex := db.simpleIndexes[DefaultIndexFieldName]
-	reference, err := idIndex.Get(id)
+	reference, err := idIndex.Get(ctx, id)
 	if err != nil {
 		d.logger.Errorf("getting from document db: ", err.Error())
 		return nil, err
@@ -729,7 +734,7 @@ func (d *Document) Get(dbName, id, podPassword string) ([]byte, error) {
 	}
 
 	if idIndex.mutable {
-		data, _, err := d.client.DownloadBlob(reference[0])
+		data, _, err := d.client.DownloadBlob(ctx, reference[0])
 		if err != nil { // skipcq: TCV-001
 			d.logger.Errorf("getting from document db: ", err.Error())
 			return nil, err
@@ -744,7 +749,7 @@ func (d *Document) Get(dbName, id, podPassword string) ([]byte, error) {
 			return nil, err
 		}
 
-		data, err := d.getLineFromFile(idIndex.podFile, podPassword, seekOffset)
+		data, err := d.getLineFromFile(ctx, idIndex.podFile, podPassword, seekOffset)
 		if err != nil {
 			d.logger.Errorf("getting from document db: ", err.Error())
 			return nil, err
@@ -756,7 +761,7 @@ func (d *Document) Get(dbName, id, podPassword string) ([]byte, error) {
 }
 
 // Del deletes a specific document from a document database matching a document id.
-func (d *Document) Del(dbName, id string) error {
+func (d *Document) Del(ctx context.Context, dbName, id string) error {
 	d.logger.Info("deleting from document db: ", dbName, id)
 	if d.fd.IsReadOnlyFeed() { // skipcq: TCV-001
 		d.logger.Errorf("deleting from document db: ", ErrReadOnlyIndex)
@@ -776,7 +781,7 @@ func (d *Document) Del(dbName, id string) error {
 
 	// get the "id" index and retrieve the original document
 	idx := db.simpleIndexes[DefaultIndexFieldName]
-	refs, err := idx.Get(id)
+	refs, err := idx.Get(ctx, id)
 	if err != nil { // skipcq: TCV-001
 		if errors.Is(err, ErrEntryNotFound) {
 			return nil
@@ -787,7 +792,7 @@ func (d *Document) Del(dbName, id string) error {
 		return nil
 	}
 
-	data, _, err := d.client.DownloadBlob(refs[0])
+	data, _, err := d.client.DownloadBlob(ctx, refs[0])
 	if err != nil { // skipcq: TCV-001
 		d.logger.Errorf("deleting from document db: ", err.Error())
 		return err
@@ -806,7 +811,7 @@ func (d *Document) Del(dbName, id string) error {
 		v := docMap[field] // it is already checked to be present
 		switch index.indexType {
 		case StringIndex:
-			_, err := index.Delete(v.(string))
+			_, err := index.Delete(ctx, v.(string))
 			if err != nil { // skipcq: TCV-001
 				d.logger.Errorf("deleting from document db: ", err.Error())
 				return err
@@ -817,7 +822,7 @@ func (d *Document) Del(dbName, id string) error {
 			for keyField, valueField := range valMap {
 				vf := valueField.(string)
 				mapField := keyField + vf
-				_, err := index.Delete(mapField)
+				_, err := index.Delete(ctx, mapField)
 				if err != nil {
 					d.logger.Errorf("deleting from document db: ", err.Error())
 					return err
@@ -827,7 +832,7 @@ func (d *Document) Del(dbName, id string) error {
 		case ListIndex: // skipcq: TCV-001
 			valList := v.([]interface{})
 			for _, listVal := range valList {
-				_, err := index.Delete(listVal.(string))
+				_, err := index.Delete(ctx, listVal.(string))
 				if err != nil {
 					d.logger.Errorf("deleting from document db: ", err.Error())
 					return err
@@ -837,7 +842,7 @@ func (d *Document) Del(dbName, id string) error {
 		case NumberIndex:
 			val := v.(float64)
 			// valStr := strconv.FormatFloat(val, 'f', 6, 64)
-			_, err := index.DeleteNumber(val)
+			_, err := index.DeleteNumber(ctx, val)
 			if err != nil { // skipcq: TCV-001
 				d.logger.Errorf("deleting from document db: ", err.Error())
 				return err
@@ -853,7 +858,7 @@ func (d *Document) Del(dbName, id string) error {
 	}
 
 	// delete the original data (unpin)
-	err = d.client.DeleteReference(refs[0])
+	err = d.client.DeleteReference(ctx, refs[0])
 	if err != nil { // skipcq: TCV-001
 		d.logger.Errorf("deleting from document db: ", err.Error())
 		return err
@@ -864,7 +869,7 @@ func (d *Document) Del(dbName, id string) error {
 }
 
 // Find selects a number of rows from a document database matching an expression.
-func (d *Document) Find(dbName, expr, podPassword string, limit int) ([][]byte, error) {
+func (d *Document) Find(ctx context.Context, dbName, expr, podPassword string, limit int) ([][]byte, error) {
 	d.logger.Info("finding from document db: ", dbName, expr, limit)
 	db := d.getOpenedDb(dbName)
 	if db == nil { // skipcq: TCV-001
@@ -879,7 +884,7 @@ func (d *Document) Find(dbName, expr, podPassword string, limit int) ([][]byte,
 			d.logger.Errorf("finding from document db: ", ErrIndexNotPresent)
 			return nil, ErrIndexNotPresent
 		}
-		return idx.Get("")
+		return idx.Get(ctx, "")
 	}
 
 	fieldName, operator, fieldValue, err := d.resolveExpression(expr)
@@ -905,14 +910,14 @@ func (d *Document) Find(dbName, expr, podPassword string, limit int) ([][]byte,
 	var references [][]byte
 	switch idx.indexType {
 	case StringIndex:
-		itr, err := idx.NewStringIterator(fieldValue, "", -1)
+		itr, err := idx.NewStringIterator(ctx, fieldValue, "", -1)
 		if err != nil { // skipcq: TCV-001
 			d.logger.Errorf("finding from document db: ", err.Error())
 			return nil, err
 		}
 		switch operator {
 		case "=":
-			itr.Next()
+			itr.Next(ctx)
 			re, err := regexp.Compile(fieldValue)
 			if err != nil { // skipcq: TCV-001
 				d.logger.Errorf("finding from document db: %v", err.Error())
@@ -929,7 +934,7 @@ func (d *Document) Find(dbName, expr, podPassword string, limit int) ([][]byte,
 				return nil, err
 			}
 
-			for itr.Next() {
+			for itr.Next(ctx) {
 				matched := re.Match([]byte(itr.StringKey()))
 				if matched {
 					refs := itr.ValueAll()
@@ -940,17 +945,17 @@ func (d *Document) Find(dbName, expr, podPassword string, limit int) ([][]byte,
 			return nil, fmt.Errorf("operator is not available: %s", operator)
 		}
 	case MapIndex, ListIndex:
-		itr, err := idx.NewStringIterator(fieldValue, "", int64(limit))
+		itr, err := idx.NewStringIterator(ctx, fieldValue, "", int64(limit))
 		if err != nil { // skipcq: TCV-001
 			d.logger.Errorf("finding from document db: ", err.Error())
 			return nil, err
 		}
 		switch operator {
 		case "=":
-			itr.Next()
+			itr.Next(ctx)
 			references = itr.ValueAll()
 		case "=>": // skipcq: TCV-001
-			for itr.Next() {
+			for itr.Next(ctx) {
 				if limit > 0 && references != nil && len(references) > limit {
 					break
 				}
@@ -958,7 +963,7 @@ func (d *Document) Find(dbName, expr, podPassword string, limit int) ([][]byte,
 				references = append(references, refs...)
 			}
 		case ">": // skipcq: TCV-001
-			for itr.Next() {
+			for itr.Next(ctx) {
 				if limit > 0 && references != nil && len(references) > limit {
 					break
 				}
@@ -982,17 +987,17 @@ func (d *Document) Find(dbName, expr, podPassword string, limit int) ([][]byte,
 		} else if operator == "!=" {
 			start = -1
 		}
-		itr, err := idx.NewIntIterator(start, -1, int64(limit))
+		itr, err := idx.NewIntIterator(ctx, start, -1, int64(limit))
 		if err != nil { // skipcq: TCV-001
 			d.logger.Errorf("finding from document db: ", err.Error())
 			return nil, err
 		}
 		switch operator {
 		case "=":
-			itr.Next()
+			itr.Next(ctx)
 			references = itr.ValueAll()
 		case "!=":
-			for itr.Next() {
+			for itr.Next(ctx) {
 				if limit > 0 && references != nil && len(references) > limit { // skipcq: TCV-001
 					break
 				}
@@ -1012,7 +1017,7 @@ func (d *Document) Find(dbName, expr, podPassword string, limit int) ([][]byte,
 				references = append(references, refs...)
 			}
 		case "=>":
-			for itr.Next() {
+			for itr.Next(ctx) {
 				if limit > 0 && references != nil && len(references) > limit { // skipcq: TCV-001
 					break
 				}
@@ -1025,7 +1030,7 @@ func (d *Document) Find(dbName, expr, podPassword string, limit int) ([][]byte,
 				d.logger.Errorf("finding from document db: ", err.Error())
 				break
 			}
-			for itr.Next() {
+			for itr.Next(ctx) {
 				if limit > 0 && references != nil && len(references) > limit { // skipcq: TCV-001
 					break
 				}
@@ -1045,7 +1050,7 @@ func (d *Document) Find(dbName, expr, podPassword string, limit int) ([][]byte,
 				d.logger.Errorf("finding from document db: ", err.Error())
 				break
 			}
-			for itr.Next() {
+			for itr.Next(ctx) {
 				if limit > 0 && references != nil && len(references) > limit { // skipcq: TCV-001
 					break
 				}
@@ -1060,7 +1065,7 @@ func (d *Document) Find(dbName, expr, podPassword string, limit int) ([][]byte,
 				references = append(references, refs...)
 			}
 		case ">":
-			for itr.Next() {
+			for itr.Next(ctx) {
 				if limit > 0 && references != nil && len(references) > limit { // skipcq: TCV-001
 					break
 				}
@@ -1090,7 +1095,7 @@ func (d *Document) Find(dbName, expr, podPassword string, limit int) ([][]byte,
 			}
 			wg.Add(1)
 			et := newEntryTask(d.client, &docs, ref, mtx)
-			err := et.Execute(context.TODO())
+			err := et.Execute(ctx)
 			if err != nil { // skipcq: TCV-001
 				d.logger.Errorf("finding from document db: ", err.Error())
 			}
@@ -1110,7 +1115,7 @@ func (d *Document) Find(dbName, expr, podPassword string, limit int) ([][]byte,
 				d.logger.Errorf("getting from document db: ", err.Error())
 				return nil, err
 			}
-			data, err := d.getLineFromFile(idx.podFile, podPassword, seekOffset)
+			data, err := d.getLineFromFile(ctx, idx.podFile, podPassword, seekOffset)
 			if err != nil {
 				d.logger.Errorf("finding from document db: ", err.Error())
 				return nil, err
@@ -1123,13 +1128,20 @@ func (d *Document) Find(dbName, expr, podPassword string, limit int) ([][]byte,
 }
 
 // LoadDocumentDBSchemas loads the schema of all documents belonging to a pod.
-func (d *Document) LoadDocumentDBSchemas(encryptionPassword string) (map[string]DBSchema, error) {
+func (d *Document) LoadDocumentDBSchemas(ctx context.Context, encryptionPassword string) (map[string]DBSchema, error) {
+	_, collections, err := d.loadDocumentDBSchemas(ctx, encryptionPassword)
+	return collections, err
+}
+
+// loadDocumentDBSchemas loads the schemas and the reference of the feed update they are
+// stored in
+func (d *Document) loadDocumentDBSchemas(ctx context.Context, encryptionPassword string) ([]byte, map[string]DBSchema, error) {
 	collections := make(map[string]DBSchema)
 	topic := utils.HashString(documentFile)
-	_, data, err := d.fd.GetFeedData(topic, d.user, []byte(encryptionPassword))
+	ref, data, err := d.fd.GetFeedData(ctx, topic, d.user, []byte(encryptionPassword))
 	if err != nil {
 		if err.Error() != "feed does not exist or was not updated yet" { // skipcq: TCV-001
-			return collections, err
+			return nil, collections, err
 		}
 	}
 
@@ -1141,18 +1153,39 @@ func (d *Document) LoadDocumentDBSchemas(encryptionPassword string) (map[string]
 			break
 		}
 		if err != nil { // skipcq: TCV-001
-			return nil, fmt.Errorf("loading collections: %v", err.Error())
+			return nil, nil, fmt.Errorf("loading collections: %v", err.Error())
 		}
 		line = strings.Trim(line, "\n")
 
 		var schema DBSchema
 		err = json.Unmarshal([]byte(line), &schema)
 		if err != nil { // skipcq: TCV-001
-			return nil, ErrUnmarshallingDBSchema
+			return nil, nil, ErrUnmarshallingDBSchema
 		}
 		collections[schema.Name] = schema
 	}
-	return collections, nil
+	return ref, collections, nil
+}
+
+// modifyDocumentDBSchemas applies a change to the latest schemas and stores them. A change
+// that conflicts with the change of another writer is applied again to the newer schemas.
+func (d *Document) modifyDocumentDBSchemas(ctx context.Context, encryptionPassword string, change func(docTables map[string]DBSchema) error) error {
+	for retries := 0; ; retries++ {
+		ref, docTables, err := d.loadDocumentDBSchemas(ctx, encryptionPassword)
+		if err != nil { // skipcq: TCV-001
+			return err
+		}
+		err = change(docTables)
+		if err != nil { // skipcq: TCV-001
+			return err
+		}
+		err = d.storeDocumentDBSchemas(ctx, encryptionPassword, docTables, ref)
+		if errors.Is(err, feed.ErrConflict) && retries < feed.ConflictRetries {
+			d.logger.Debugf("merging concurrent change of the document db schemas")
+			continue
+		}
+		return err
+	}
 }
 
 // IsDBOpened is used to check if a document DB is opened or not.
@@ -1165,7 +1198,9 @@ func (d *Document) IsDBOpened(dbName string) bool {
 	return false
 }
 
-func (d *Document) storeDocumentDBSchemas(encryptionPassword string, collections map[string]DBSchema) error {
+// storeDocumentDBSchemas stores the schemas, if the latest schemas are the ones at the
+// expected reference
+func (d *Document) storeDocumentDBSchemas(ctx context.Context, encryptionPassword string, collections map[string]DBSchema, expected []byte) error {
 	buf := bytes.NewBuffer(nil)
 	collectionLen := len(collections)
 	if collectionLen > 0 {
@@ -1178,7 +1213,7 @@ func (d *Document) storeDocumentDBSchemas(encryptionPassword string, collections
 		}
 	}
 	topic := utils.HashString(documentFile)
-	_, err := d.fd.UpdateFeed(topic, d.user, buf.Bytes(), []byte(encryptionPassword))
+	_, err := d.fd.UpdateFeedIf(ctx, topic, d.user, expected, buf.Bytes(), []byte(encryptionPassword))
 	if err != nil { // skipcq: TCV-001
 		return err
 	}
@@ -1232,7 +1267,7 @@ func (*Document) resolveExpression(expr string) (string, string, string, error)
 }
 
 // CreateDocBatch creates a batch index instead of normal index. This is used when doing a bulk insert.
-func (d *Document) CreateDocBatch(dbName, podPassword string) (*DocBatch, error) {
+func (d *Document) CreateDocBatch(ctx context.Context, dbName, podPassword string) (*DocBatch, error) {
 	d.logger.Info("creating batch for inserting in document db: ", dbName)
 	if d.fd.IsReadOnlyFeed() { // skipcq: TCV-001
 		d.logger.Errorf("creating batch: ", ErrReadOnlyIndex)
@@ -1240,7 +1275,7 @@ func (d *Document) CreateDocBatch(dbName, podPassword string) (*DocBatch, error)
 	}
 
 	// see if the document db is empty
-	data, err := d.Find(dbName, "", podPassword, 1)
+	data, err := d.Find(ctx, dbName, "", podPassword, 1)
 	if err != nil {
 		if !errors.Is(err, ErrEntryNotFound) { // skipcq: TCV-001
 			d.logger.Errorf("creating simple batch index: ", err.Error())
@@ -1295,7 +1330,7 @@ func (d *Document) CreateDocBatch(dbName, podPassword string) (*DocBatch, error)
 }
 
 // DocBatchPut is used to insert a single document to the batch index.
-func (d *Document) DocBatchPut(docBatch *DocBatch, doc []byte, index int64) error {
+func (d *Document) DocBatchPut(ctx context.Context, docBatch *DocBatch, doc []byte, index int64) error {
 	if d.fd.IsReadOnlyFeed() { // skipcq: TCV-001
 		d.logger.Errorf("inserting in batch: ", ErrReadOnlyIndex)
 		return ErrReadOnlyIndex
@@ -1344,11 +1379,11 @@ func (d *Document) DocBatchPut(docBatch *DocBatch, doc []byte, index int64) erro
 				return ErrInvalidDocumentId
 			} else {
 				idBatchIndex := docBatch.batches[DefaultIndexFieldName]
-				refs, err := idBatchIndex.Get(valStr)
+				refs, err := idBatchIndex.Get(ctx, valStr)
 				if err == nil { // skipcq: TCV-001
 					// found a doc with the same id, so remove it and all the indexes
 					if len(refs) > 0 {
-						data, _, err := d.client.DownloadBlob(refs[0])
+						data, _, err := d.client.DownloadBlob(ctx, refs[0])
 						if err != nil {
 							d.logger.Errorf("inserting in batch: ", err.Error())
 							return err
@@ -1366,7 +1401,7 @@ func (d *Document) DocBatchPut(docBatch *DocBatch, doc []byte, index int64) erro
 							v1 := oldDocMap[field] // it is already checked to be present
 							switch batchIndex.idx.indexType {
 							case StringIndex:
-								_, err := batchIndex.Del(v1.(string))
+								_, err := batchIndex.Del(ctx, v1.(string))
 								if err != nil {
 									d.logger.Errorf("inserting in batch: ", err.Error())
 									return err
@@ -1376,7 +1411,7 @@ func (d *Document) DocBatchPut(docBatch *DocBatch, doc []byte, index int64) erro
 								for keyField, valueField := range valMap {
 									vf := valueField.(string)
 									mapField := keyField + vf
-									_, err := batchIndex.Del(mapField)
+									_, err := batchIndex.Del(ctx, mapField)
 									if err != nil {
 										d.logger.Errorf("inserting in batch: ", err.Error())
 										return err
@@ -1385,7 +1420,7 @@ func (d *Document) DocBatchPut(docBatch *DocBatch, doc []byte, index int64) erro
 							case ListIndex:
 								valList := v1.([]interface{})
 								for _, listVal := range valList {
-									_, err := batchIndex.Del(listVal.(string))
+									_, err := batchIndex.Del(ctx, listVal.(string))
 									if err != nil {
 										d.logger.Errorf("inserting in batch: ", err.Error())
 										return err
@@ -1394,7 +1429,7 @@ func (d *Document) DocBatchPut(docBatch *DocBatch, doc []byte, index int64) erro
 							case NumberIndex:
 								val := v1.(float64)
 								// valStr = strconv.FormatFloat(val, 'f', 6, 64)
-								_, err := batchIndex.DelNumber(val)
+								_, err := batchIndex.DelNumber(ctx, val)
 								if err != nil {
 									d.logger.Errorf("inserting in batch: ", err.Error())
 									return err
@@ -1408,7 +1443,7 @@ func (d *Document) DocBatchPut(docBatch *DocBatch, doc []byte, index int64) erro
 							}
 						}
 
-						err = d.client.DeleteReference(refs[0])
+						err = d.client.DeleteReference(ctx, refs[0])
 						if err != nil {
 							d.logger.Errorf("inserting in batch: ", err.Error())
 							return err
@@ -1419,7 +1454,7 @@ func (d *Document) DocBatchPut(docBatch *DocBatch, doc []byte, index int64) erro
 			}
 
 			// upload the document
-			ref, err = d.client.UploadBlob(doc, 0, true, true)
+			ref, err = d.client.UploadBlob(ctx, doc, 0, true, true)
 			if err != nil { // skipcq: TCV-001
 				d.logger.Errorf("inserting in batch: ", err.Error())
 				return err
@@ -1455,7 +1490,7 @@ func (d *Document) DocBatchPut(docBatch *DocBatch, doc []byte, index int64) erro
 					if field == DefaultIndexFieldName {
 						apnd = false
 					}
-					err := batchIndex.Put(valStr1, ref, apnd, memory)
+					err := batchIndex.Put(ctx, valStr1, ref, apnd, memory)
 					if err != nil { // skipcq: TCV-001
 						d.logger.Errorf("inserting in batch: ", err.Error())
 						return err
@@ -1465,7 +1500,7 @@ func (d *Document) DocBatchPut(docBatch *DocBatch, doc []byte, index int64) erro
 					for keyField, valueField := range valMap {
 						vf := valueField.(string)
 						mapField := keyField + vf
-						err := batchIndex.Put(mapField, ref, true, memory)
+						err := batchIndex.Put(ctx, mapField, ref, true, memory)
 						if err != nil { // skipcq: TCV-001
 							d.logger.Errorf("inserting in batch: ", err.Error())
 							return err
@@ -1475,7 +1510,7 @@ func (d *Document) DocBatchPut(docBatch *DocBatch, doc []byte, index int64) erro
 					valList := v.([]interface{})
 					for _, listVal := range valList {
 						listField := listVal.(string)
-						err := batchIndex.Put(listField, ref, true, memory)
+						err := batchIndex.Put(ctx, listField, ref, true, memory)
 						if err != nil { // skipcq: TCV-001
 							d.logger.Errorf("inserting in batch: ", err.Error())
 							return err
@@ -1484,13 +1519,13 @@ func (d *Document) DocBatchPut(docBatch *DocBatch, doc []byte, index int64) erro
 				case NumberIndex:
 					switch v1 := v.(type) {
 					case string: // skipcq: TCV-001
-						err := batchIndex.Put(v1, ref, true, memory)
+						err := batchIndex.Put(ctx, v1, ref, true, memory)
 						if err != nil {
 							d.logger.Errorf("inserting in batch: ", err.Error())
 							return err
 						}
 					case float64:
-						err := batchIndex.PutNumber(v1, ref, true, memory)
+						err := batchIndex.PutNumber(ctx, v1, ref, true, memory)
 						if err != nil { // skipcq: TCV-001
 							d.logger.Errorf("inserting in batch: ", err.Error())
 							return err
@@ -1516,14 +1551,14 @@ func (d *Document) DocBatchPut(docBatch *DocBatch, doc []byte, index int64) erro
 }
 
 // DocBatchWrite commits the batch index into the Swarm network.
-func (d *Document) DocBatchWrite(docBatch *DocBatch, podFile string) error {
+func (d *Document) DocBatchWrite(ctx context.Context, docBatch *DocBatch, podFile string) error {
 	d.logger.Info("writing batch: ", docBatch.db.name)
 	if d.fd.IsReadOnlyFeed() { // skipcq: TCV-001
 		d.logger.Errorf("writing batch: ", ErrReadOnlyIndex)
 		return ErrReadOnlyIndex
 	}
 	for _, batch := range docBatch.batches {
-		man, err := batch.Write(podFile)
+		man, err := batch.Write(ctx, podFile)
 		if err != nil { // skipcq: TCV-001
 			d.logger.Errorf("writing batch: ", err.Error())
 			return err
@@ -1538,9 +1573,9 @@ func (d *Document) DocBatchWrite(docBatch *DocBatch, podFile string) error {
 
 // DocFileIndex indexes an existing json file in the pod with the document DB.
 // skipcq: TCV-001
-func (d *Document) DocFileIndex(dbName, podFile, podPassword string) error {
+func (d *Document) DocFileIndex(ctx context.Context, dbName, podFile, podPassword string) error {
 	d.logger.Info("Indexing file to db: ", podFile, dbName)
-	reader, err := d.file.OpenFileForIndex(podFile, podPassword)
+	reader, err := d.file.OpenFileForIndex(ctx, podFile, podPassword)
 	if err != nil {
 		d.logger.Errorf("Indexing file: ", err.Error())
 		return err
@@ -1551,7 +1586,7 @@ func (d *Document) DocFileIndex(dbName, podFile, podPassword string) error {
 		return err
 	}
 
-	batch, err := d.CreateDocBatch(dbName, podPassword)
+	batch, err := d.CreateDocBatch(ctx, dbName, podPassword)
 	if err != nil {
 		d.logger.Errorf("Indexing file: ", err.Error())
 		return err
@@ -1569,7 +1604,7 @@ func (d *Document) DocFileIndex(dbName, podFile, podPassword string) error {
 			return err
 		}
 
-		err = d.DocBatchPut(batch, data, seekIndex)
+		err = d.DocBatchPut(ctx, batch, data, seekIndex)
 		if err != nil {
 			d.logger.Errorf("Indexing file: ", err.Error())
 			return err
@@ -1582,7 +1617,7 @@ func (d *Document) DocFileIndex(dbName, podFile, podPassword string) error {
 		}
 	}
 
-	err = d.DocBatchWrite(batch, podFile)
+	err = d.DocBatchWrite(ctx, batch, podFile)
 	if err != nil {
 		d.logger.Errorf("Indexing file: ", err.Error())
 		return err
@@ -1592,8 +1627,8 @@ func (d *Document) DocFileIndex(dbName, podFile, podPassword string) error {
 }
 
 // skipcq: TCV-001
-func (d *Document) getLineFromFile(podFile, podPassword string, seekOffset uint64) ([]byte, error) {
-	reader, err := d.file.OpenFileForIndex(podFile, podPassword)
+func (d *Document) getLineFromFile(ctx context.Context, podFile, podPassword string, seekOffset uint64) ([]byte, error) {
+	reader, err := d.file.OpenFileForIndex(ctx, podFile, podPassword)
 	if err != nil {
 		d.logger.Errorf("getting  line: ", err.Error())
 		return nil, err
@@ -1628,8 +1663,8 @@ func newEntryTask(c blockstore.Client, allData *[][]byte, ref []byte, mtx sync.L
 }
 
 // Execute
-func (et *entryTask) Execute(context.Context) error {
-	data, _, err := et.c.DownloadBlob(et.ref)
+func (et *entryTask) Execute(ctx context.Context) error {
+	data, _, err := et.c.DownloadBlob(ctx, et.ref)
 	if err != nil {
 		return err
 	}
@@ -47,6 +47,7 @@ type TestDocument struct {
 }
 
 func TestDocumentStore(t *testing.T) {
+	ctx := context.Background()
 	mockClient := mock.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -68,7 +69,7 @@ func TestDocumentStore(t *testing.T) {
 	t.Run("create_document_db_errors", func(t *testing.T) {
 		nilFd := feed.New(&account.Info{}, mockClient, logger)
 		nilDocStore := collection.NewDocumentStore("pod1", nilFd, ai, user, file, tm, mockClient, logger)
-		err := nilDocStore.CreateDocumentDB("docdb_err", podPassword, nil, true)
+		err := nilDocStore.CreateDocumentDB(ctx, "docdb_err", podPassword, nil, true)
 		if !errors.Is(err, collection.ErrReadOnlyIndex) {
 			t.Fatal("should be readonly index")
 		}
@@ -76,14 +77,14 @@ func TestDocumentStore(t *testing.T) {
 		// create a document DB
 		createDocumentDBs(t, []string{"docdb_err"}, docStore, nil, podPassword)
 
-		err = docStore.CreateDocumentDB("docdb_err", podPassword, nil, true)
+		err = docStore.CreateDocumentDB(ctx, "docdb_err", podPassword, nil, true)
 		if !errors.Is(err, collection.ErrDocumentDBAlreadyPresent) {
 			t.Fatal("db should be present already")
 		}
 
-		err = docStore.OpenDocumentDB("docdb_err", podPassword)
+		err = docStore.OpenDocumentDB(ctx, "docdb_err", podPassword)
 		require.NoError(t, err)
-		err = docStore.CreateDocumentDB("docdb_err", podPassword, nil, true)
+		err = docStore.CreateDocumentDB(ctx, "docdb_err", podPassword, nil, true)
 		if !errors.Is(err, collection.ErrDocumentDBAlreadyOpened) {
 			t.Fatal("db should be opened already")
 		}
@@ -106,14 +107,14 @@ func TestDocumentStore(t *testing.T) {
 		checkIfDBsExists(t, []string{"docdb_1_1", "docdb_1_2", "docdb_1_3"}, docStore, podPassword)
 
 		// delete the db in the middle
-		err = docStore.DeleteDocumentDB("docdb_1_2", podPassword)
+		err = docStore.DeleteDocumentDB(ctx, "docdb_1_2", podPassword)
 		require.NoError(t, err)
 
 		// check if other two db exists
 		checkIfDBsExists(t, []string{"docdb_1_1", "docdb_1_3"}, docStore, podPassword)
-		err = docStore.DeleteDocumentDB("docdb_1_1", podPassword)
+		err = docStore.DeleteDocumentDB(ctx, "docdb_1_1", podPassword)
 		require.NoError(t, err)
-		err = docStore.DeleteDocumentDB("docdb_1_3", podPassword)
+		err = docStore.DeleteDocumentDB(ctx, "docdb_1_3", podPassword)
 		require.NoError(t, err)
 		checkIfDBNotExists(t, "docdb_1_1", podPassword, docStore)
 		checkIfDBNotExists(t, "docdb_1_3", podPassword, docStore)
@@ -125,12 +126,12 @@ func TestDocumentStore(t *testing.T) {
 		checkIfDBsExists(t, []string{"docdb_1_1", "docdb_1_2", "docdb_1_3"}, docStore, podPassword)
 
 		// delete the db in the middle
-		err = docStore.DeleteDocumentDB("docdb_1_2", podPassword)
+		err = docStore.DeleteDocumentDB(ctx, "docdb_1_2", podPassword)
 		require.NoError(t, err)
 
 		// check if other two db exists
 		checkIfDBsExists(t, []string{"docdb_1_1", "docdb_1_3"}, docStore, podPassword)
-		err = docStore.DeleteAllDocumentDBs(podPassword)
+		err = docStore.DeleteAllDocumentDBs(ctx, podPassword)
 		require.NoError(t, err)
 
 		checkIfDBNotExists(t, "docdb_1_1", podPassword, docStore)
@@ -182,7 +183,7 @@ func TestDocumentStore(t *testing.T) {
 		// create a document DB
 		createDocumentDBs(t, []string{"docdb_3"}, docStore, nil, podPassword)
 
-		err := docStore.OpenDocumentDB("docdb_3", podPassword)
+		err := docStore.OpenDocumentDB(ctx, "docdb_3", podPassword)
 		require.NoError(t, err)
 
 		// check if the DB is opened properly
@@ -193,10 +194,10 @@ func TestDocumentStore(t *testing.T) {
 
 	t.Run("put_immutable_error", func(t *testing.T) {
 		// create a document DB
-		err := docStore.CreateDocumentDB("doc_do_immutable", podPassword, nil, false)
+		err := docStore.CreateDocumentDB(ctx, "doc_do_immutable", podPassword, nil, false)
 		require.NoError(t, err)
 
-		err = docStore.OpenDocumentDB("doc_do_immutable", podPassword)
+		err = docStore.OpenDocumentDB(ctx, "doc_do_immutable", podPassword)
 		require.NoError(t, err)
 
 		// create a json document
@@ -210,7 +211,7 @@ func TestDocumentStore(t *testing.T) {
 		require.NoError(t, err)
 
 		// insert the docment in the DB
-		err = docStore.Put("doc_do_immutable", data)
+		err = docStore.Put(ctx, "doc_do_immutable", data)
 		if !errors.Is(err, collection.ErrModifyingImmutableDocDB) {
 			t.Fatal("db is immutable")
 		}
@@ -220,7 +221,7 @@ func TestDocumentStore(t *testing.T) {
 		// create a document DB
 		createDocumentDBs(t, []string{"docdb_4"}, docStore, nil, podPassword)
 
-		err := docStore.OpenDocumentDB("docdb_4", podPassword)
+		err := docStore.OpenDocumentDB(ctx, "docdb_4", podPassword)
 		require.NoError(t, err)
 
 		invalidType := struct {
@@ -232,7 +233,7 @@ func TestDocumentStore(t *testing.T) {
 		data, err := json.Marshal(invalidType)
 		require.NoError(t, err)
 
-		err = docStore.Put("docdb_4", data)
+		err = docStore.Put(ctx, "docdb_4", data)
 		if !errors.Is(err, collection.ErrDocumentDBIndexFieldNotPresent) {
 			t.Fatal("index is not present")
 		}
@@ -247,7 +248,7 @@ func TestDocumentStore(t *testing.T) {
 		require.NoError(t, err)
 
 		// insert the docment in the DB
-		err = docStore.Put("docdb_4", data)
+		err = docStore.Put(ctx, "docdb_4", data)
 		if !errors.Is(err, collection.ErrInvalidDocumentId) {
 			t.Fatal("index is invalid")
 		}
@@ -263,11 +264,11 @@ func TestDocumentStore(t *testing.T) {
 		require.NoError(t, err)
 
 		// insert the docment in the DB
-		err = docStore.Put("docdb_4", data)
+		err = docStore.Put(ctx, "docdb_4", data)
 		require.NoError(t, err)
 
 		// get the data and test if the retreived data is okay
-		gotData, err := docStore.Get("docdb_4", "1", podPassword)
+		gotData, err := docStore.Get(ctx, "docdb_4", "1", podPassword)
 		require.NoError(t, err)
 
 		var doc TestDocument
@@ -291,14 +292,14 @@ func TestDocumentStore(t *testing.T) {
 		si["tag_list"] = collection.ListIndex
 		createDocumentDBs(t, []string{"docdb_5"}, docStore, si, podPassword)
 
-		err := docStore.OpenDocumentDB("docdb_5", podPassword)
+		err := docStore.OpenDocumentDB(ctx, "docdb_5", podPassword)
 		require.NoError(t, err)
 
 		// Add documents
 		createTestDocuments(t, docStore, "docdb_5")
 
 		// get string index and check if the documents returned are okay
-		docs, err := docStore.Get("docdb_5", "2", podPassword)
+		docs, err := docStore.Get(ctx, "docdb_5", "2", podPassword)
 		require.NoError(t, err)
 
 		var gotDoc TestDocument
@@ -324,13 +325,13 @@ func TestDocumentStore(t *testing.T) {
 		si["tag_list"] = collection.ListIndex
 		createDocumentDBs(t, []string{"docdb_6"}, docStore, si, podPassword)
 
-		err := docStore.OpenDocumentDB("docdb_6", podPassword)
+		err := docStore.OpenDocumentDB(ctx, "docdb_6", podPassword)
 		require.NoError(t, err)
 
 		// Add documents
 		createTestDocuments(t, docStore, "docdb_6")
 
-		count1, err := docStore.Count("docdb_6", "")
+		count1, err := docStore.Count(ctx, "docdb_6", "")
 		require.NoError(t, err)
 
 		if count1 != 6 {
@@ -348,21 +349,21 @@ func TestDocumentStore(t *testing.T) {
 		si["tag_list"] = collection.ListIndex
 		createDocumentDBs(t, []string{"docdb_7"}, docStore, si, podPassword)
 
-		err := docStore.OpenDocumentDB("docdb_7", podPassword)
+		err := docStore.OpenDocumentDB(ctx, "docdb_7", podPassword)
 		require.NoError(t, err)
 
 		// Add documents
 		createTestDocuments(t, docStore, "docdb_7")
 
 		// String count
-		count1, err := docStore.Count("docdb_7", "first_name=>John")
+		count1, err := docStore.Count(ctx, "docdb_7", "first_name=>John")
 		require.NoError(t, err)
 
 		if count1 != 2 {
 			t.Fatalf("expected count %d, got %d", 2, count1)
 		}
 
-		count1, err = docStore.Count("docdb_7", "tag_map=tgf11:tgv11")
+		count1, err = docStore.Count(ctx, "docdb_7", "tag_map=tgf11:tgv11")
 		require.NoError(t, err)
 
 		if count1 != 1 {
@@ -370,7 +371,7 @@ func TestDocumentStore(t *testing.T) {
 		}
 
 		// Number =
-		count2, err := docStore.Count("docdb_7", "age=25")
+		count2, err := docStore.Count(ctx, "docdb_7", "age=25")
 		require.NoError(t, err)
 
 		if count2 != 3 {
@@ -378,7 +379,7 @@ func TestDocumentStore(t *testing.T) {
 		}
 
 		// Number =>
-		count3, err := docStore.Count("docdb_7", "age=>30")
+		count3, err := docStore.Count(ctx, "docdb_7", "age=>30")
 		require.NoError(t, err)
 
 		if count3 != 3 {
@@ -386,7 +387,7 @@ func TestDocumentStore(t *testing.T) {
 		}
 
 		// Number >
-		count4, err := docStore.Count("docdb_7", "age>30")
+		count4, err := docStore.Count(ctx, "docdb_7", "age>30")
 		require.NoError(t, err)
 
 		if count4 != 2 {
@@ -403,14 +404,14 @@ func TestDocumentStore(t *testing.T) {
 		si["tag_list"] = collection.ListIndex
 		createDocumentDBs(t, []string{"docdb_8"}, docStore, si, podPassword)
 
-		err := docStore.OpenDocumentDB("docdb_8", podPassword)
+		err := docStore.OpenDocumentDB(ctx, "docdb_8", podPassword)
 		require.NoError(t, err)
 
 		// Add documents
 		createTestDocuments(t, docStore, "docdb_8")
 
 		// String =>
-		docs, err := docStore.Find("docdb_8", "first_name=>John", podPassword, -1)
+		docs, err := docStore.Find(ctx, "docdb_8", "first_name=>John", podPassword, -1)
 		require.NoError(t, err)
 
 		if len(docs) != 2 {
@@ -438,7 +439,7 @@ func TestDocumentStore(t *testing.T) {
 		}
 
 		// tag
-		docs, err = docStore.Find("docdb_8", "tag_map=tgf21:tgv21", podPassword, -1)
+		docs, err = docStore.Find(ctx, "docdb_8", "tag_map=tgf21:tgv21", podPassword, -1)
 		require.NoError(t, err)
 
 		if len(docs) != 1 {
@@ -456,7 +457,7 @@ func TestDocumentStore(t *testing.T) {
 		}
 
 		// Number =
-		docs, err = docStore.Find("docdb_8", "age=25", podPassword, -1)
+		docs, err = docStore.Find(ctx, "docdb_8", "age=25", podPassword, -1)
 		require.NoError(t, err)
 
 		if len(docs) != 3 {
@@ -492,7 +493,7 @@ func TestDocumentStore(t *testing.T) {
 		}
 
 		// Number = with limit
-		docs, err = docStore.Find("docdb_8", "age=25", podPassword, 2)
+		docs, err = docStore.Find(ctx, "docdb_8", "age=25", podPassword, 2)
 		require.NoError(t, err)
 
 		if len(docs) != 2 {
@@ -516,7 +517,7 @@ func TestDocumentStore(t *testing.T) {
 		}
 
 		// Number =>
-		docs, err = docStore.Find("docdb_8", "age=>30", podPassword, -1)
+		docs, err = docStore.Find(ctx, "docdb_8", "age=>30", podPassword, -1)
 		require.NoError(t, err)
 		if len(docs) != 3 {
 			t.Fatalf("expected count %d, got %d", 3, len(docs))
@@ -539,7 +540,7 @@ func TestDocumentStore(t *testing.T) {
 		}
 
 		// Number >
-		docs, err = docStore.Find("docdb_8", "age>30", podPassword, -1)
+		docs, err = docStore.Find(ctx, "docdb_8", "age>30", podPassword, -1)
 		require.NoError(t, err)
 		if len(docs) != 2 {
 			t.Fatalf("expected count %d, got %d", 2, len(docs))
@@ -553,38 +554,38 @@ func TestDocumentStore(t *testing.T) {
 			t.Fatalf("invalid json data received")
 		}
 
-		docs, err = docStore.Find("docdb_8", "tag_map=>tgf11:tgv11", podPassword, -1)
+		docs, err = docStore.Find(ctx, "docdb_8", "tag_map=>tgf11:tgv11", podPassword, -1)
 		require.NoError(t, err)
 
 		assert.Equal(t, len(docs), 12)
 
-		docs, err = docStore.Find("docdb_8", "tag_map>tgf11:tgv11", podPassword, -1)
+		docs, err = docStore.Find(ctx, "docdb_8", "tag_map>tgf11:tgv11", podPassword, -1)
 		require.NoError(t, err)
 
 		assert.Equal(t, len(docs), 11)
 
-		docs, err = docStore.Find("docdb_8", "tag_map=>tgf41:tgv41", podPassword, -1)
+		docs, err = docStore.Find(ctx, "docdb_8", "tag_map=>tgf41:tgv41", podPassword, -1)
 		require.NoError(t, err)
 
 		assert.Equal(t, len(docs), 6)
 
-		docs, err = docStore.Find("docdb_8", "tag_map>tgf41:tgv41", podPassword, -1)
+		docs, err = docStore.Find(ctx, "docdb_8", "tag_map>tgf41:tgv41", podPassword, -1)
 		require.NoError(t, err)
 
 		assert.Equal(t, len(docs), 5)
 
-		docs, err = docStore.Find("docdb_8", "age<=30", podPassword, -1)
+		docs, err = docStore.Find(ctx, "docdb_8", "age<=30", podPassword, -1)
 		require.NoError(t, err)
 
 		assert.Equal(t, len(docs), 4)
 
-		docs, err = docStore.Find("docdb_8", "age<30", podPassword, -1)
+		docs, err = docStore.Find(ctx, "docdb_8", "age<30", podPassword, -1)
 		require.NoError(t, err)
 
 		assert.Equal(t, len(docs), 3)
 
 		// Number !=
-		docs, err = docStore.Find("docdb_8", "age!=25", podPassword, -1)
+		docs, err = docStore.Find(ctx, "docdb_8", "age!=25", podPassword, -1)
 		require.NoError(t, err)
 		if len(docs) != 3 {
 			t.Fatalf("expected count %d, got %d", 3, len(docs))
@@ -601,7 +602,7 @@ func TestDocumentStore(t *testing.T) {
 		}
 
 		// String !=
-		_, err = docStore.Find("docdb_8", "first_name!=Bob", podPassword, -1)
+		_, err = docStore.Find(ctx, "docdb_8", "first_name!=Bob", podPassword, -1)
 		if err == nil {
 			t.Fatal("should not be err ", err)
 		}
@@ -614,7 +615,7 @@ func TestDocumentStore(t *testing.T) {
 		si["age"] = collection.NumberIndex
 		createDocumentDBs(t, []string{"docdb_9"}, docStore, si, podPassword)
 
-		err := docStore.OpenDocumentDB("docdb_9", podPassword)
+		err := docStore.OpenDocumentDB(ctx, "docdb_9", podPassword)
 		require.NoError(t, err)
 
 		// Add document and get to see if it is added
@@ -624,7 +625,7 @@ func TestDocumentStore(t *testing.T) {
 		var list1 []string
 		list1 = append(list1, "lst11", "lst12")
 		addDocument(t, docStore, "docdb_9", "1", "John", "Doe", 45, tag1, list1)
-		docs, err := docStore.Get("docdb_9", "1", podPassword)
+		docs, err := docStore.Get(ctx, "docdb_9", "1", podPassword)
 		require.NoError(t, err)
 		var gotDoc TestDocument
 		err = json.Unmarshal(docs, &gotDoc)
@@ -637,9 +638,9 @@ func TestDocumentStore(t *testing.T) {
 		}
 
 		// del document
-		err = docStore.Del("docdb_9", "1")
+		err = docStore.Del(ctx, "docdb_9", "1")
 		require.NoError(t, err)
-		_, err = docStore.Get("docdb_9", "1", podPassword)
+		_, err = docStore.Get(ctx, "docdb_9", "1", podPassword)
 		if !errors.Is(err, collection.ErrEntryNotFound) {
 			t.Fatal(err)
 		}
@@ -654,7 +655,7 @@ func TestDocumentStore(t *testing.T) {
 		si["tag_list"] = collection.ListIndex
 		createDocumentDBs(t, []string{"docdb_99"}, docStore, si, podPassword)
 
-		err := docStore.OpenDocumentDB("docdb_99", podPassword)
+		err := docStore.OpenDocumentDB(ctx, "docdb_99", podPassword)
 		require.NoError(t, err)
 
 		// Add document and get to see if it is added
@@ -664,7 +665,7 @@ func TestDocumentStore(t *testing.T) {
 		var list1 []string
 		list1 = append(list1, "lst11", "lst12")
 		addDocument(t, docStore, "docdb_99", "1", "John", "Doe", 45, tag1, list1)
-		docs, err := docStore.Get("docdb_99", "1", podPassword)
+		docs, err := docStore.Get(ctx, "docdb_99", "1", podPassword)
 		require.NoError(t, err)
 		var gotDoc TestDocument
 		err = json.Unmarshal(docs, &gotDoc)
@@ -677,9 +678,9 @@ func TestDocumentStore(t *testing.T) {
 		}
 
 		// del document
-		err = docStore.Del("docdb_99", "1")
+		err = docStore.Del(ctx, "docdb_99", "1")
 		require.NoError(t, err)
-		_, err = docStore.Get("docdb_99", "1", podPassword)
+		_, err = docStore.Get(ctx, "docdb_99", "1", podPassword)
 		if !errors.Is(err, collection.ErrEntryNotFound) {
 			t.Fatal(err)
 		}
@@ -692,7 +693,7 @@ func TestDocumentStore(t *testing.T) {
 		si["age"] = collection.NumberIndex
 		createDocumentDBs(t, []string{"docdb_10"}, docStore, si, podPassword)
 
-		err := docStore.OpenDocumentDB("docdb_10", podPassword)
+		err := docStore.OpenDocumentDB(ctx, "docdb_10", podPassword)
 		require.NoError(t, err)
 
 		tag1 := make(map[string]string)
@@ -704,14 +705,14 @@ func TestDocumentStore(t *testing.T) {
 		addDocument(t, docStore, "docdb_10", "1", "John", "Doe", 25, tag1, list1)
 
 		// count the total docs using id field
-		count1, err := docStore.Count("docdb_10", "")
+		count1, err := docStore.Count(ctx, "docdb_10", "")
 		require.NoError(t, err)
 		if count1 != 1 {
 			t.Fatalf("expected count %d, got %d", 1, count1)
 		}
 
 		// count the total docs using another index to make sure we don't have it any index
-		docs, err := docStore.Find("docdb_10", "age=>20", podPassword, -1)
+		docs, err := docStore.Find(ctx, "docdb_10", "age=>20", podPassword, -1)
 		require.NoError(t, err)
 		if len(docs) != 1 {
 			t.Fatalf("expected count %d, got %d", 1, len(docs))
@@ -727,10 +728,10 @@ func TestDocumentStore(t *testing.T) {
 		si["tag_list"] = collection.ListIndex
 		createDocumentDBs(t, []string{"docdb_11"}, docStore, si, podPassword)
 
-		err := docStore.OpenDocumentDB("docdb_11", podPassword)
+		err := docStore.OpenDocumentDB(ctx, "docdb_11", podPassword)
 		require.NoError(t, err)
 
-		docBatch, err := docStore.CreateDocBatch("docdb_11", podPassword)
+		docBatch, err := docStore.CreateDocBatch(ctx, "docdb_11", podPassword)
 		require.NoError(t, err)
 
 		tag1 := make(map[string]string)
@@ -758,30 +759,30 @@ func TestDocumentStore(t *testing.T) {
 		list4 = append(list4, "lst41", "lst42")
 		addBatchDocument(t, docStore, docBatch, "4", "John", "Doe", 35, tag4, list4) // this tests the overwriting in batch
 
-		err = docStore.DocBatchWrite(docBatch, "")
+		err = docStore.DocBatchWrite(ctx, docBatch, "")
 		require.NoError(t, err)
 
 		// count the total docs using id field
-		count1, err := docStore.Count("docdb_11", "")
+		count1, err := docStore.Count(ctx, "docdb_11", "")
 		require.NoError(t, err)
 		if count1 != 4 {
 			t.Fatalf("expected count %d, got %d", 4, count1)
 		}
 
 		// count the total docs using another index to make sure we don't have it any index
-		docs, err := docStore.Find("docdb_11", "age=>20", podPassword, -1)
+		docs, err := docStore.Find(ctx, "docdb_11", "age=>20", podPassword, -1)
 		require.NoError(t, err)
 		if len(docs) != 4 {
 			t.Fatalf("expected count %d, got %d", 3, len(docs))
 		}
 
 		// tag
-		docs, err = docStore.Find("docdb_11", "tag_map=tgf21:tgv21", podPassword, -1)
+		docs, err = docStore.Find(ctx, "docdb_11", "tag_map=tgf21:tgv21", podPassword, -1)
 		require.NoError(t, err)
 		if len(docs) != 1 {
 			t.Fatalf("expected count %d, got %d", 1, len(docs))
 		}
-		err = docStore.DeleteDocumentDB("docdb_11", podPassword)
+		err = docStore.DeleteDocumentDB(ctx, "docdb_11", podPassword)
 		require.NoError(t, err)
 	})
 	/*
@@ -877,16 +878,18 @@ func TestDocumentStore(t *testing.T) {
 }
 
 func createDocumentDBs(t *testing.T, dbNames []string, docStore *collection.Document, si map[string]collection.IndexType, podPassword string) {
+	ctx := context.Background()
 	t.Helper()
 	for _, dbName := range dbNames {
-		err := docStore.CreateDocumentDB(dbName, podPassword, si, true)
+		err := docStore.CreateDocumentDB(ctx, dbName, podPassword, si, true)
 		require.NoError(t, err)
 	}
 }
 
 func checkIfDBsExists(t *testing.T, dbNames []string, docStore *collection.Document, podPassword string) {
+	ctx := context.Background()
 	t.Helper()
-	tables, err := docStore.LoadDocumentDBSchemas(podPassword)
+	tables, err := docStore.LoadDocumentDBSchemas(ctx, podPassword)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -898,8 +901,9 @@ func checkIfDBsExists(t *testing.T, dbNames []string, docStore *collection.Docum
 }
 
 func checkIfDBNotExists(t *testing.T, tableName, podPassword string, docStore *collection.Document) {
+	ctx := context.Background()
 	t.Helper()
-	tables, err := docStore.LoadDocumentDBSchemas(podPassword)
+	tables, err := docStore.LoadDocumentDBSchemas(ctx, podPassword)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -909,8 +913,9 @@ func checkIfDBNotExists(t *testing.T, tableName, podPassword string, docStore *c
 }
 
 func loadSchemaAndCheckSimpleIndexCount(t *testing.T, docStore *collection.Document, dbName, podPassword string, count int) collection.DBSchema {
+	ctx := context.Background()
 	t.Helper()
-	tables, err := docStore.LoadDocumentDBSchemas(podPassword)
+	tables, err := docStore.LoadDocumentDBSchemas(ctx, podPassword)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -975,6 +980,7 @@ func createTestDocuments(t *testing.T, docStore *collection.Document, dbName str
 }
 
 func addDocument(t *testing.T, docStore *collection.Document, dbName, id, fname, lname string, age float64, tagMap map[string]string, tagList []string) {
+	ctx := context.Background()
 	t.Helper()
 	// create the doc
 	doc := &TestDocument{
@@ -993,13 +999,14 @@ func addDocument(t *testing.T, docStore *collection.Document, dbName, id, fname,
 	}
 
 	// insert the docment in the DB
-	err = docStore.Put(dbName, data)
+	err = docStore.Put(ctx, dbName, data)
 	if err != nil {
 		t.Fatal(err)
 	}
 }
 
 func addBatchDocument(t *testing.T, docStore *collection.Document, docBatch *collection.DocBatch, id, fname, lname string, age float64, tagMap map[string]string, tagList []string) {
+	ctx := context.Background()
 	t.Helper()
 	t.Run("valid-json", func(t *testing.T) {
 		// create the doc
@@ -1017,7 +1024,7 @@ func addBatchDocument(t *testing.T, docStore *collection.Document, docBatch *col
 		require.NoError(t, err)
 
 		// insert the document in the batch
-		err = docStore.DocBatchPut(docBatch, data, 0)
+		err = docStore.DocBatchPut(ctx, docBatch, data, 0)
 		require.NoError(t, err)
 	})
 	t.Run("invalid-json", func(t *testing.T) {
@@ -1036,7 +1043,7 @@ func addBatchDocument(t *testing.T, docStore *collection.Document, docBatch *col
 		require.NoError(t, err)
 
 		// insert the document in the batch
-		err = docStore.DocBatchPut(docBatch, data, 0)
+		err = docStore.DocBatchPut(ctx, docBatch, data, 0)
 		if err != collection.ErrUnknownJsonFormat {
 			t.Fatal(err)
 		}
@@ -25,6 +25,7 @@ type Manifest struct {
 	CreationTime int64     `json:"creation_time"`
 	Entries      []*Entry  `json:"entries,omitempty"`
 	dirtyFlag    bool
+	feedRef      []byte // the feed update the manifest was loaded from
 }
 
 // Entry
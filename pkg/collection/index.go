@@ -19,6 +19,7 @@ package collection
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"runtime"
@@ -106,13 +107,13 @@ var (
 )
 
 // CreateIndex creates a common index file to be used in kv or document tables.
-func CreateIndex(podName, collectionName, indexName, encryptionPassword string, indexType IndexType, fd *feed.API, user utils.Address, client blockstore.Client, mutable bool) error {
+func CreateIndex(ctx context.Context, podName, collectionName, indexName, encryptionPassword string, indexType IndexType, fd *feed.API, user utils.Address, client blockstore.Client, mutable bool) error {
 	if fd.IsReadOnlyFeed() { // skipcq: TCV-001
 		return ErrReadOnlyIndex
 	}
 	actualIndexName := podName + collectionName + indexName
 	topic := utils.HashString(actualIndexName)
-	_, oldData, err := fd.GetFeedData(topic, user, []byte(encryptionPassword))
+	_, oldData, err := fd.GetFeedData(ctx, topic, user, []byte(encryptionPassword))
 	if err == nil && len(oldData) != 0 && string(oldData) != utils.DeletedFeedMagicWord {
 		// if the feed is present, and it has some data means there index is still valid
 		return ErrIndexAlreadyPresent
@@ -126,19 +127,19 @@ func CreateIndex(podName, collectionName, indexName, encryptionPassword string,
 		return ErrManifestUnmarshall
 	}
 
-	ref, err := client.UploadBlob(data, 0, true, true)
+	ref, err := client.UploadBlob(ctx, data, 0, true, true)
 	if err != nil { // skipcq: TCV-001
 		return ErrManifestUnmarshall
 	}
 
 	if string(oldData) == utils.DeletedFeedMagicWord { // skipcq: TCV-001
-		_, err = fd.UpdateFeed(topic, user, ref, []byte(encryptionPassword))
+		_, err = fd.UpdateFeed(ctx, topic, user, ref, []byte(encryptionPassword))
 		if err != nil {
 			return ErrManifestCreate
 		}
 		return nil
 	}
-	_, err = fd.CreateFeed(topic, user, ref, []byte(encryptionPassword))
+	_, err = fd.CreateFeed(ctx, topic, user, ref, []byte(encryptionPassword))
 	if err != nil { // skipcq: TCV-001
 		return ErrManifestCreate
 	}
@@ -146,9 +147,9 @@ func CreateIndex(podName, collectionName, indexName, encryptionPassword string,
 }
 
 // OpenIndex open the index and load any index in to the memory.
-func OpenIndex(podName, collectionName, indexName, podPassword string, fd *feed.API, ai *account.Info, user utils.Address, client blockstore.Client, logger logging.Logger) (*Index, error) {
+func OpenIndex(ctx context.Context, podName, collectionName, indexName, podPassword string, fd *feed.API, ai *account.Info, user utils.Address, client blockstore.Client, logger logging.Logger) (*Index, error) {
 	actualIndexName := podName + collectionName + indexName
-	manifest := getRootManifestOfIndex(actualIndexName, podPassword, fd, user, client) // this will load the entire Manifest for immutable indexes
+	manifest := getRootManifestOfIndex(ctx, actualIndexName, podPassword, fd, user, client) // this will load the entire Manifest for immutable indexes
 	if manifest == nil {
 		return nil, ErrIndexNotPresent
 	}
@@ -171,18 +172,18 @@ func OpenIndex(podName, collectionName, indexName, podPassword string, fd *feed.
 }
 
 // DeleteIndex delete the index from file and all its entries.
-func (idx *Index) DeleteIndex(encryptionPassword string) error {
+func (idx *Index) DeleteIndex(ctx context.Context, encryptionPassword string) error {
 	if idx.isReadOnlyFeed() { // skipcq: TCV-001
 		return ErrReadOnlyIndex
 	}
-	manifest := getRootManifestOfIndex(idx.name, encryptionPassword, idx.feed, idx.user, idx.client)
+	manifest := getRootManifestOfIndex(ctx, idx.name, encryptionPassword, idx.feed, idx.user, idx.client)
 	if manifest == nil {
 		return ErrIndexNotPresent
 	}
 
 	// erase the top Manifest
 	topic := utils.HashString(idx.name)
-	_, err := idx.feed.UpdateFeed(topic, idx.user, []byte(utils.DeletedFeedMagicWord), []byte(encryptionPassword))
+	_, err := idx.feed.UpdateFeed(ctx, topic, idx.user, []byte(utils.DeletedFeedMagicWord), []byte(encryptionPassword))
 	if err != nil { // skipcq: TCV-001
 		return ErrDeleteingIndex
 	}
@@ -190,9 +191,9 @@ func (idx *Index) DeleteIndex(encryptionPassword string) error {
 }
 
 // CountIndex counts the entries in an index.
-func (idx *Index) CountIndex(encryptionPassword string) (uint64, error) {
+func (idx *Index) CountIndex(ctx context.Context, encryptionPassword string) (uint64, error) {
 	if idx.memDB == nil || idx.memDB.Entries == nil {
-		manifest, err := idx.loadManifest(idx.name, encryptionPassword)
+		manifest, err := idx.loadManifest(ctx, idx.name, encryptionPassword)
 		if err != nil {
 			return 0, err
 		}
@@ -206,7 +207,7 @@ func (idx *Index) CountIndex(encryptionPassword string) (uint64, error) {
 	idx.count = 0
 	errC := make(chan error, 1) // get only one error
 	workers := make(chan bool, NoOfParallelWorkers)
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	idx.loadIndexAndCount(ctx, cancel, workers, idx.memDB, encryptionPassword, errC)
@@ -229,7 +230,7 @@ func (idx *Index) loadIndexAndCount(ctx context.Context, cancel context.CancelFu
 			var newManifest *Manifest
 			if entry.Manifest == nil {
 
-				man, err := idx.loadManifest(manifest.Name+entry.Name, encryptionPassword)
+				man, err := idx.loadManifest(ctx, manifest.Name+entry.Name, encryptionPassword)
 				if err != nil { // skipcq: TCV-001
 					idx.logger.Error("Manifest load error: ", manifest.Name+entry.Name)
 					return
@@ -248,15 +249,15 @@ func (idx *Index) loadIndexAndCount(ctx context.Context, cancel context.CancelFu
 }
 
 // Manifest related functions
-func (idx *Index) loadManifest(manifestPath, encryptionPassword string) (*Manifest, error) {
+func (idx *Index) loadManifest(ctx context.Context, manifestPath, encryptionPassword string) (*Manifest, error) {
 	// get feed data and unmarshall the Manifest
 	idx.logger.Info("loading Manifest: ", manifestPath)
 	topic := utils.HashString(manifestPath)
-	_, refData, err := idx.feed.GetFeedData(topic, idx.user, []byte(encryptionPassword))
+	feedRef, refData, err := idx.feed.GetFeedData(ctx, topic, idx.user, []byte(encryptionPassword))
 	if err != nil { // skipcq: TCV-001
 		return nil, ErrNoManifestFound
 	}
-	data, respCode, err := idx.client.DownloadBlob(refData)
+	data, respCode, err := idx.client.DownloadBlob(ctx, refData)
 	if err != nil { // skipcq: TCV-001
 		return nil, ErrNoManifestFound
 	}
@@ -269,11 +270,15 @@ func (idx *Index) loadManifest(manifestPath, encryptionPassword string) (*Manife
 	if err != nil { // skipcq: TCV-001
 		return nil, ErrManifestUnmarshall
 	}
+	manifest.feedRef = feedRef
 
 	return &manifest, nil
 }
 
-func (idx *Index) updateManifest(manifest *Manifest, encryptionPassword string) error {
+// updateManifest stores a changed Manifest. A Manifest that was loaded from its feed is
+// only stored if no other writer has updated the feed since, otherwise feed.ErrConflict
+// is returned.
+func (idx *Index) updateManifest(ctx context.Context, manifest *Manifest, encryptionPassword string) error {
 	// marshall and update the Manifest in the feed
 	idx.logger.Info("updating Manifest: ", manifest.Name)
 	data, err := json.Marshal(manifest)
@@ -281,20 +286,27 @@ func (idx *Index) updateManifest(manifest *Manifest, encryptionPassword string)
 		return ErrManifestUnmarshall
 	}
 
-	ref, err := idx.client.UploadBlob(data, 0, true, true)
+	ref, err := idx.client.UploadBlob(ctx, data, 0, true, true)
 	if err != nil { // skipcq: TCV-001
 		return ErrManifestUnmarshall
 	}
 
 	topic := utils.HashString(manifest.Name)
-	_, err = idx.feed.UpdateFeed(topic, idx.user, ref, []byte(encryptionPassword))
+	if manifest.feedRef != nil {
+		_, err = idx.feed.UpdateFeedIf(ctx, topic, idx.user, manifest.feedRef, ref, []byte(encryptionPassword))
+	} else {
+		_, err = idx.feed.UpdateFeed(ctx, topic, idx.user, ref, []byte(encryptionPassword))
+	}
+	if errors.Is(err, feed.ErrConflict) {
+		return err
+	}
 	if err != nil { // skipcq: TCV-001
 		return ErrManifestCreate
 	}
 	return nil
 }
 
-func (idx *Index) storeManifest(manifest *Manifest, encryptionPassword string) error {
+func (idx *Index) storeManifest(ctx context.Context, manifest *Manifest, encryptionPassword string) error {
 	// marshall and store the Manifest as new feed
 	data, err := json.Marshal(manifest)
 	if err != nil { // skipcq: TCV-001
@@ -303,7 +315,7 @@ func (idx *Index) storeManifest(manifest *Manifest, encryptionPassword string) e
 	logStr := fmt.Sprintf("storing Manifest: %s, data len = %d", manifest.Name, len(data))
 	idx.logger.Debug(logStr)
 
-	ref, err := idx.client.UploadBlob(data, 0, true, true)
+	ref, err := idx.client.UploadBlob(ctx, data, 0, true, true)
 	//TODO: once the tags issue is fixed i bytes.
 	// remove the error string check
 	if err != nil { // skipcq: TCV-001
@@ -312,7 +324,27 @@ func (idx *Index) storeManifest(manifest *Manifest, encryptionPassword string) e
 	}
 
 	topic := utils.HashString(manifest.Name)
-	_, err = idx.feed.CreateFeed(topic, idx.user, ref, []byte(encryptionPassword))
+	_, err = idx.feed.CreateFeed(ctx, topic, idx.user, ref, []byte(encryptionPassword))
+	if err != nil { // skipcq: TCV-001
+		return ErrManifestCreate
+	}
+	return nil
+}
+
+// storeManifests stores serialised manifests as new feeds, uploading all of them in one batch
+func (idx *Index) storeManifests(ctx context.Context, names []string, data [][]byte, encryptionPassword string) error {
+	idx.logger.Debug(fmt.Sprintf("storing %d Manifests", len(names)))
+	refs, err := idx.client.UploadBlobs(ctx, data, 0, true, true)
+	if err != nil { // skipcq: TCV-001
+		idx.logger.Errorf("uploadBlobs failed in storeManifests : %s", err.Error())
+		return ErrManifestCreate
+	}
+
+	topics := make([][]byte, len(names))
+	for i, name := range names {
+		topics[i] = utils.HashString(name)
+	}
+	_, err = idx.feed.CreateFeeds(ctx, topics, idx.user, refs, []byte(encryptionPassword))
 	if err != nil { // skipcq: TCV-001
 		return ErrManifestCreate
 	}
@@ -345,14 +377,14 @@ func longestCommonPrefix(str1, str2 string) (string, string, string) {
 	return str1[:matchLen], str1[matchLen:], str2[matchLen:]
 }
 
-func getRootManifestOfIndex(actualIndexName, encryptionPassword string, fd *feed.API, user utils.Address, client blockstore.Client) *Manifest {
+func getRootManifestOfIndex(ctx context.Context, actualIndexName, encryptionPassword string, fd *feed.API, user utils.Address, client blockstore.Client) *Manifest {
 	var manifest Manifest
 	topic := utils.HashString(actualIndexName)
-	_, addr, err := fd.GetFeedData(topic, user, []byte(encryptionPassword))
+	_, addr, err := fd.GetFeedData(ctx, topic, user, []byte(encryptionPassword))
 	if err != nil {
 		return nil
 	}
-	data, _, err := client.DownloadBlob(addr)
+	data, _, err := client.DownloadBlob(ctx, addr)
 	if err != nil {
 		return nil
 	}
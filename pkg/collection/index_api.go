@@ -24,6 +24,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
 )
 
 const (
@@ -34,13 +36,13 @@ const (
 )
 
 // PutNumber inserts an entry in to index with a number as a key.
-func (idx *Index) PutNumber(key float64, refValue []byte, idxType IndexType, apnd bool) error {
+func (idx *Index) PutNumber(ctx context.Context, key float64, refValue []byte, idxType IndexType, apnd bool) error {
 	stringKey := fmt.Sprintf("%020.20g", key)
-	return idx.Put(stringKey, refValue, idxType, apnd)
+	return idx.Put(ctx, stringKey, refValue, idxType, apnd)
 }
 
 // Put inserts an entry in to index with a string as key.
-func (idx *Index) Put(key string, refValue []byte, idxType IndexType, apnd bool) error {
+func (idx *Index) Put(ctx context.Context, key string, refValue []byte, idxType IndexType, apnd bool) error {
 	if idx.isReadOnlyFeed() { // skipcq: TCV-001
 		return ErrReadOnlyIndex
 	}
@@ -49,26 +51,34 @@ func (idx *Index) Put(key string, refValue []byte, idxType IndexType, apnd bool)
 		return ErrCannotModifyImmutableIndex
 	}
 
-	// get the first feed of the Index
-	manifest, err := idx.loadManifest(idx.name, idx.encryptionPassword)
-	if err != nil { // skipcq: TCV-001
+	// a put that conflicts with the put of another writer is done again on the newer
+	// manifests
+	for retries := 0; ; retries++ {
+		// get the first feed of the Index
+		manifest, err := idx.loadManifest(ctx, idx.name, idx.encryptionPassword)
+		if err != nil { // skipcq: TCV-001
+			return err
+		}
+
+		err = idx.addOrUpdateStringEntry(ctx, manifest, key, idxType, refValue, false, apnd)
+		if errors.Is(err, feed.ErrConflict) && retries < feed.ConflictRetries {
+			idx.logger.Debugf("merging concurrent put of %s in %s", key, idx.name)
+			continue
+		}
 		return err
 	}
-
-	ctx := context.Background()
-	return idx.addOrUpdateStringEntry(ctx, manifest, key, idxType, refValue, false, apnd)
 }
 
 // GetNumber retrieves an element from the index where the key is of type number.
 // skipcq: TCV-001
-func (idx *Index) GetNumber(key float64) ([][]byte, error) {
+func (idx *Index) GetNumber(ctx context.Context, key float64) ([][]byte, error) {
 	stringKey := fmt.Sprintf("%020.20g", key)
-	return idx.Get(stringKey)
+	return idx.Get(ctx, stringKey)
 }
 
 // Get retrieves an element from the index where the key is of type string.
-func (idx *Index) Get(key string) ([][]byte, error) {
-	_, manifest, i, err := idx.seekManifestAndEntry(key)
+func (idx *Index) Get(ctx context.Context, key string) ([][]byte, error) {
+	_, manifest, i, err := idx.seekManifestAndEntry(ctx, key)
 	if err != nil {
 		return nil, err
 	}
@@ -77,13 +87,13 @@ func (idx *Index) Get(key string) ([][]byte, error) {
 }
 
 // DeleteNumber removes an entry from index where the key is of type number.
-func (idx *Index) DeleteNumber(key float64) ([][]byte, error) {
+func (idx *Index) DeleteNumber(ctx context.Context, key float64) ([][]byte, error) {
 	stringKey := fmt.Sprintf("%020.20g", key)
-	return idx.Delete(stringKey)
+	return idx.Delete(ctx, stringKey)
 }
 
 // Delete removes an entry from index where the key is of type string.
-func (idx *Index) Delete(key string) ([][]byte, error) {
+func (idx *Index) Delete(ctx context.Context, key string) ([][]byte, error) {
 	if idx.isReadOnlyFeed() { // skipcq: TCV-001
 		return nil, ErrReadOnlyIndex
 	}
@@ -92,7 +102,20 @@ func (idx *Index) Delete(key string) ([][]byte, error) {
 		return nil, ErrCannotModifyImmutableIndex
 	}
 
-	_, manifest, i, err := idx.seekManifestAndEntry(key)
+	// a delete that conflicts with the change of another writer is done again on the
+	// newer manifests
+	for retries := 0; ; retries++ {
+		deletedRef, err := idx.delete(ctx, key)
+		if errors.Is(err, feed.ErrConflict) && retries < feed.ConflictRetries {
+			idx.logger.Debugf("merging concurrent delete of %s in %s", key, idx.name)
+			continue
+		}
+		return deletedRef, err
+	}
+}
+
+func (idx *Index) delete(ctx context.Context, key string) ([][]byte, error) {
+	_, manifest, i, err := idx.seekManifestAndEntry(ctx, key)
 	if err != nil {
 		return nil, err
 	}
@@ -103,7 +126,7 @@ func (idx *Index) Delete(key string) ([][]byte, error) {
 		// then we have to remove the intermediate node in the parent Manifest
 		// so that the entire branch goes kaboom
 		parentEntryKey := filepath.Base(manifest.Name)
-		parentManifest, err := idx.loadManifest(filepath.Dir(manifest.Name), idx.encryptionPassword)
+		parentManifest, err := idx.loadManifest(ctx, filepath.Dir(manifest.Name), idx.encryptionPassword)
 		if err != nil {
 			return nil, err
 		}
@@ -114,7 +137,7 @@ func (idx *Index) Delete(key string) ([][]byte, error) {
 				break
 			}
 		}
-		err = idx.updateManifest(parentManifest, idx.encryptionPassword)
+		err = idx.updateManifest(ctx, parentManifest, idx.encryptionPassword)
 		if err != nil { // skipcq: TCV-001
 			return nil, err
 		}
@@ -122,7 +145,7 @@ func (idx *Index) Delete(key string) ([][]byte, error) {
 	}
 
 	manifest.Entries = append(manifest.Entries[:i], manifest.Entries[i+1:]...)
-	err = idx.updateManifest(manifest, idx.encryptionPassword)
+	err = idx.updateManifest(ctx, manifest, idx.encryptionPassword)
 	if err != nil {
 		return nil, err
 	}
@@ -180,7 +203,7 @@ func (idx *Index) addOrUpdateStringEntry(ctx context.Context, manifest *Manifest
 
 			// store the new Manifest with two leaves
 			if !memory {
-				err := idx.storeManifest(&newManifest, idx.encryptionPassword)
+				err := idx.storeManifest(ctx, &newManifest, idx.encryptionPassword)
 				if err != nil { // skipcq: TCV-001
 					return err
 				}
@@ -220,7 +243,7 @@ func (idx *Index) addOrUpdateStringEntry(ctx context.Context, manifest *Manifest
 				}
 				idx.addEntryToManifestSortedLexicographically(&newManifest, entry2)
 				if !memory {
-					err := idx.storeManifest(&newManifest, idx.encryptionPassword)
+					err := idx.storeManifest(ctx, &newManifest, idx.encryptionPassword)
 					if err != nil { // skipcq: TCV-001
 						return err
 					}
@@ -240,7 +263,7 @@ func (idx *Index) addOrUpdateStringEntry(ctx context.Context, manifest *Manifest
 			} else if len(keySuffix) > 0 {
 				// load the entry's Manifest and add the keySuffix as a new leaf
 				if !memory {
-					intermediateManifest, err := idx.loadManifest(manifest.Name+entry.Name, idx.encryptionPassword)
+					intermediateManifest, err := idx.loadManifest(ctx, manifest.Name+entry.Name, idx.encryptionPassword)
 					if err != nil { // skipcq: TCV-001
 						return err
 					}
@@ -252,7 +275,7 @@ func (idx *Index) addOrUpdateStringEntry(ctx context.Context, manifest *Manifest
 			} else if entrySuffix == "" && keySuffix == "" {
 				// load the entry's Manifest and add the keySuffix as a new leaf
 				if !memory {
-					intermediateManifest, err := idx.loadManifest(manifest.Name+prefix, idx.encryptionPassword)
+					intermediateManifest, err := idx.loadManifest(ctx, manifest.Name+prefix, idx.encryptionPassword)
 					if err != nil { // skipcq: TCV-001
 						return err
 					}
@@ -283,7 +306,7 @@ func (idx *Index) addOrUpdateStringEntry(ctx context.Context, manifest *Manifest
 				}
 				idx.addEntryToManifestSortedLexicographically(&newManifest, entry2)
 				if !memory {
-					err := idx.storeManifest(&newManifest, idx.encryptionPassword)
+					err := idx.storeManifest(ctx, &newManifest, idx.encryptionPassword)
 					if err != nil { // skipcq: TCV-001
 						return err
 					}
@@ -317,7 +340,7 @@ func (idx *Index) addOrUpdateStringEntry(ctx context.Context, manifest *Manifest
 	}
 
 	if entryAdded && !memory {
-		return idx.updateManifest(manifest, idx.encryptionPassword)
+		return idx.updateManifest(ctx, manifest, idx.encryptionPassword)
 	}
 	return nil // skipcq: TCV-001
 }
@@ -378,22 +401,22 @@ func (*Index) addEntryToManifestSortedLexicographically(manifest *Manifest, entr
 	manifest.Entries = entries
 }
 
-func (idx *Index) seekManifestAndEntry(key string) (*Manifest, *Manifest, int, error) {
+func (idx *Index) seekManifestAndEntry(ctx context.Context, key string) (*Manifest, *Manifest, int, error) {
 
 	// load the first Manifest of the index
-	fm, err := idx.loadManifest(idx.name, idx.encryptionPassword)
+	fm, err := idx.loadManifest(ctx, idx.name, idx.encryptionPassword)
 	if err != nil && !errors.Is(err, ErrNoManifestFound) { // skipcq: TCV-001
 		return nil, nil, 0, err
 	}
 
 	// if there are any elements in the index, then search for the entry
 	if fm.Entries != nil && len(fm.Entries) > 0 {
-		return idx.findManifest(nil, fm, key)
+		return idx.findManifest(ctx, nil, fm, key)
 	}
 	return nil, nil, 0, ErrEntryNotFound
 }
 
-func (idx *Index) findManifest(grandParentManifest, parentManifest *Manifest, key string) (*Manifest, *Manifest, int, error) {
+func (idx *Index) findManifest(ctx context.Context, grandParentManifest, parentManifest *Manifest, key string) (*Manifest, *Manifest, int, error) {
 	if parentManifest != nil {
 		for i, entry := range parentManifest.Entries {
 
@@ -417,14 +440,14 @@ func (idx *Index) findManifest(grandParentManifest, parentManifest *Manifest, ke
 				if entry.Manifest == nil {
 					childManifestPath := parentManifest.Name + entry.Name
 					var err error
-					childManifest, err = idx.loadManifest(childManifestPath, idx.encryptionPassword)
+					childManifest, err = idx.loadManifest(ctx, childManifestPath, idx.encryptionPassword)
 					if err != nil { // skipcq: TCV-001
 						return nil, nil, 0, err
 					}
 				} else { // skipcq: TCV-001
 					childManifest = entry.Manifest
 				}
-				return idx.findManifest(parentManifest, childManifest, childKey)
+				return idx.findManifest(ctx, parentManifest, childManifest, childKey)
 
 			}
 		}
@@ -18,6 +18,7 @@ package collection_test
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"net/http"
@@ -34,6 +35,7 @@ import (
 )
 
 func TestIndexAPI(t *testing.T) {
+	ctx := context.Background()
 	mockClient := mock.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -70,7 +72,7 @@ func TestIndexAPI(t *testing.T) {
 		kvMap := addLotOfDocs(t, index, mockClient)
 
 		// find the count
-		count, err := index.CountIndex(podPassword)
+		count, err := index.CountIndex(ctx, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -141,25 +143,27 @@ func TestIndexAPI(t *testing.T) {
 }
 
 func addDoc(t *testing.T, key string, value []byte, index *collection.Index, client *mock.BeeClient, apnd bool) {
-	ref, err := client.UploadBlob(value, 0, false, false)
+	ctx := context.Background()
+	ref, err := client.UploadBlob(ctx, value, 0, false, false)
 	if err != nil {
 		t.Fatalf("could not add doc %s:%s, %v", key, value, err)
 	}
-	err = index.Put(key, ref, collection.StringIndex, apnd)
+	err = index.Put(ctx, key, ref, collection.StringIndex, apnd)
 	if err != nil {
 		t.Fatalf("could not add doc in index: %s:%s, %v", key, ref, err)
 	}
 }
 
 func getDoc(t *testing.T, key string, index *collection.Index, client *mock.BeeClient) []byte {
-	ref, err := index.Get(key)
+	ctx := context.Background()
+	ref, err := index.Get(ctx, key)
 	if err != nil {
 		if errors.Is(err, collection.ErrEntryNotFound) {
 			return nil
 		}
 		t.Fatal(err)
 	}
-	data, respCode, err := client.DownloadBlob(ref[0])
+	data, respCode, err := client.DownloadBlob(ctx, ref[0])
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -169,7 +173,8 @@ func getDoc(t *testing.T, key string, index *collection.Index, client *mock.BeeC
 	return data
 }
 func getAllDocs(t *testing.T, key string, index *collection.Index, client *mock.BeeClient) [][]byte {
-	refs, err := index.Get(key)
+	ctx := context.Background()
+	refs, err := index.Get(ctx, key)
 	if err != nil {
 		if errors.Is(err, collection.ErrEntryNotFound) {
 			return nil
@@ -179,7 +184,7 @@ func getAllDocs(t *testing.T, key string, index *collection.Index, client *mock.
 
 	var data [][]byte
 	for _, ref := range refs {
-		buf, respCode, err := client.DownloadBlob(ref)
+		buf, respCode, err := client.DownloadBlob(ctx, ref)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -192,7 +197,8 @@ func getAllDocs(t *testing.T, key string, index *collection.Index, client *mock.
 }
 
 func getValue(t *testing.T, ref []byte, client *mock.BeeClient) []byte {
-	data, respCode, err := client.DownloadBlob(ref)
+	ctx := context.Background()
+	data, respCode, err := client.DownloadBlob(ctx, ref)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -203,11 +209,12 @@ func getValue(t *testing.T, ref []byte, client *mock.BeeClient) []byte {
 }
 
 func delDoc(t *testing.T, key string, index *collection.Index, client *mock.BeeClient) []byte {
-	ref, err := index.Delete(key)
+	ctx := context.Background()
+	ref, err := index.Delete(ctx, key)
 	if err != nil {
 		t.Fatal(err)
 	}
-	data, respCode, err := client.DownloadBlob(ref[0])
+	data, respCode, err := client.DownloadBlob(ctx, ref[0])
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -253,6 +260,7 @@ func addLotOfDocs(t *testing.T, index *collection.Index, client *mock.BeeClient)
 }
 
 func addBatchDocs(t *testing.T, batch *collection.Batch, client *mock.BeeClient) map[string][]byte {
+	ctx := context.Background()
 	kvMap := make(map[string][]byte)
 	kvMap["key1"] = []byte("value1")
 	kvMap["key11"] = []byte("value11")
@@ -277,11 +285,11 @@ func addBatchDocs(t *testing.T, batch *collection.Batch, client *mock.BeeClient)
 
 	// add the documents
 	for k, v := range kvMap {
-		ref, err := client.UploadBlob(v, 0, false, false)
+		ref, err := client.UploadBlob(ctx, v, 0, false, false)
 		if err != nil {
 			t.Fatalf("could not add doc %s:%s, %v", k, ref, err)
 		}
-		err = batch.Put(k, ref, false, false)
+		err = batch.Put(ctx, k, ref, false, false)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -18,6 +18,7 @@ package collection_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
@@ -36,6 +37,7 @@ import (
 )
 
 func TestIndex(t *testing.T) {
+	ctx := context.Background()
 	mockClient := mock.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -49,7 +51,7 @@ func TestIndex(t *testing.T) {
 	podPassword, _ := utils.GetRandString(pod.PasswordLength)
 	t.Run("create_index", func(t *testing.T) {
 		//  create an index
-		err := collection.CreateIndex("pod1", "testdb_index_0", "key", podPassword, collection.StringIndex, fd, user, mockClient, true)
+		err := collection.CreateIndex(ctx, "pod1", "testdb_index_0", "key", podPassword, collection.StringIndex, fd, user, mockClient, true)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -62,13 +64,13 @@ func TestIndex(t *testing.T) {
 
 	t.Run("create_and_open_index", func(t *testing.T) {
 		//  create an index
-		err := collection.CreateIndex("pod1", "testdb_index_1", "key", podPassword, collection.StringIndex, fd, user, mockClient, true)
+		err := collection.CreateIndex(ctx, "pod1", "testdb_index_1", "key", podPassword, collection.StringIndex, fd, user, mockClient, true)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// Open the index
-		_, err = collection.OpenIndex("pod1", "testdb_index_1", "key", podPassword, fd, ai, user, mockClient, logger)
+		_, err = collection.OpenIndex(ctx, "pod1", "testdb_index_1", "key", podPassword, fd, ai, user, mockClient, logger)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -80,7 +82,7 @@ func TestIndex(t *testing.T) {
 		kvMap := addLotOfDocs(t, index, mockClient)
 
 		// open the index again, simulating like another instance
-		index1, err := collection.OpenIndex("pod1", "testdb_index_2", "key", podPassword, fd, acc.GetUserAccountInfo(), acc.GetAddress(account.UserAccountIndex), mockClient, logger)
+		index1, err := collection.OpenIndex(ctx, "pod1", "testdb_index_2", "key", podPassword, fd, acc.GetUserAccountInfo(), acc.GetAddress(account.UserAccountIndex), mockClient, logger)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -101,13 +103,13 @@ func TestIndex(t *testing.T) {
 
 	t.Run("create_already_present_index", func(t *testing.T) {
 		//  create an index
-		err := collection.CreateIndex("pod1", "testdb_index_3", "key", podPassword, collection.StringIndex, fd, user, mockClient, true)
+		err := collection.CreateIndex(ctx, "pod1", "testdb_index_3", "key", podPassword, collection.StringIndex, fd, user, mockClient, true)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		//  create an index
-		err = collection.CreateIndex("pod1", "testdb_index_3", "key", podPassword, collection.StringIndex, fd, user, mockClient, true)
+		err = collection.CreateIndex(ctx, "pod1", "testdb_index_3", "key", podPassword, collection.StringIndex, fd, user, mockClient, true)
 		if !errors.Is(err, collection.ErrIndexAlreadyPresent) {
 			t.Fatal(err)
 		}
@@ -115,7 +117,7 @@ func TestIndex(t *testing.T) {
 
 	t.Run("open_index_without_creating_it", func(t *testing.T) {
 		// Open the index
-		_, err = collection.OpenIndex("pod1", "testdb_index_4", "key", podPassword, fd, ai, user, mockClient, logger)
+		_, err = collection.OpenIndex(ctx, "pod1", "testdb_index_4", "key", podPassword, fd, ai, user, mockClient, logger)
 		if err != collection.ErrIndexNotPresent {
 			t.Fatal(err)
 		}
@@ -123,19 +125,19 @@ func TestIndex(t *testing.T) {
 
 	t.Run("create_and_delete_index", func(t *testing.T) {
 		//  create an index
-		err := collection.CreateIndex("pod1", "testdb_index_5", "key", podPassword, collection.StringIndex, fd, user, mockClient, true)
+		err := collection.CreateIndex(ctx, "pod1", "testdb_index_5", "key", podPassword, collection.StringIndex, fd, user, mockClient, true)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// Open the index
-		idx, err := collection.OpenIndex("pod1", "testdb_index_5", "key", podPassword, fd, ai, user, mockClient, logger)
+		idx, err := collection.OpenIndex(ctx, "pod1", "testdb_index_5", "key", podPassword, fd, ai, user, mockClient, logger)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// delete Index
-		err = idx.DeleteIndex(podPassword)
+		err = idx.DeleteIndex(ctx, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -143,21 +145,21 @@ func TestIndex(t *testing.T) {
 
 	t.Run("delete_index_without_creating_it", func(t *testing.T) {
 		// simulate index not present by creating and deleting it
-		err := collection.CreateIndex("pod1", "testdb_index_6", "key", podPassword, collection.StringIndex, fd, user, mockClient, true)
+		err := collection.CreateIndex(ctx, "pod1", "testdb_index_6", "key", podPassword, collection.StringIndex, fd, user, mockClient, true)
 		if err != nil {
 			t.Fatal(err)
 		}
-		idx, err := collection.OpenIndex("pod1", "testdb_index_6", "key", podPassword, fd, ai, user, mockClient, logger)
+		idx, err := collection.OpenIndex(ctx, "pod1", "testdb_index_6", "key", podPassword, fd, ai, user, mockClient, logger)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = idx.DeleteIndex(podPassword)
+		err = idx.DeleteIndex(ctx, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// delete Index which is not present
-		err = idx.DeleteIndex(podPassword)
+		err = idx.DeleteIndex(ctx, podPassword)
 		if err != collection.ErrIndexNotPresent {
 			t.Fatal(err)
 		}
@@ -165,12 +167,12 @@ func TestIndex(t *testing.T) {
 
 	t.Run("count_docs", func(t *testing.T) {
 		// create index and add some docs
-		err := collection.CreateIndex("pod1", "testdb_index_7", "key", podPassword, collection.StringIndex, fd, user, mockClient, true)
+		err := collection.CreateIndex(ctx, "pod1", "testdb_index_7", "key", podPassword, collection.StringIndex, fd, user, mockClient, true)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		idx, err := collection.OpenIndex("pod1", "testdb_index_7", "key", podPassword, fd, ai, user, mockClient, logger)
+		idx, err := collection.OpenIndex(ctx, "pod1", "testdb_index_7", "key", podPassword, fd, ai, user, mockClient, logger)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -182,7 +184,7 @@ func TestIndex(t *testing.T) {
 		}
 
 		// count and check the count
-		count, err := idx.CountIndex(podPassword)
+		count, err := idx.CountIndex(ctx, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -196,11 +198,12 @@ func TestIndex(t *testing.T) {
 }
 
 func isIndexPresent(t *testing.T, podName, collectionName, indexName, encryptionPassword string, fd *feed.API, user utils.Address, client blockstore.Client) bool {
+	ctx := context.Background()
 	actualIndexName := podName + collectionName + indexName
 	topic := utils.HashString(actualIndexName)
-	_, addr, err := fd.GetFeedData(topic, user, []byte(encryptionPassword))
+	_, addr, err := fd.GetFeedData(ctx, topic, user, []byte(encryptionPassword))
 	if err == nil && len(addr) != 0 {
-		data, _, err := client.DownloadBlob(addr)
+		data, _, err := client.DownloadBlob(ctx, addr)
 		if err != nil {
 			return false
 		}
@@ -218,7 +221,8 @@ func isIndexPresent(t *testing.T, podName, collectionName, indexName, encryption
 }
 
 func putDocInIndex(t *testing.T, index *collection.Index, key, value string, idxTYpe collection.IndexType, apnd bool) {
-	err := index.Put(key, []byte(value), idxTYpe, apnd)
+	ctx := context.Background()
+	err := index.Put(ctx, key, []byte(value), idxTYpe, apnd)
 	if err != nil {
 		t.Fatalf("could not add doc in index: %s:%s, %v", key, value, err)
 	}
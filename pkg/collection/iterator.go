@@ -17,6 +17,7 @@ limitations under the License.
 package collection
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strconv"
@@ -45,11 +46,11 @@ type ManifestState struct {
 }
 
 // NewStringIterator creates a new iterator object which is used to create new index iterators.
-func (idx *Index) NewStringIterator(start, end string, limit int64) (*Iterator, error) {
+func (idx *Index) NewStringIterator(ctx context.Context, start, end string, limit int64) (*Iterator, error) {
 	var manifest *Manifest
 	if idx.mutable {
 		// get the first feed of the Index
-		mf, err := idx.loadManifest(idx.name, idx.encryptionPassword)
+		mf, err := idx.loadManifest(ctx, idx.name, idx.encryptionPassword)
 		if err != nil { // skipcq: TCV-001
 			return nil, ErrEmptyIndex
 		}
@@ -70,7 +71,7 @@ func (idx *Index) NewStringIterator(start, end string, limit int64) (*Iterator,
 	}
 
 	if itr.startPrefix != "" {
-		err := itr.Seek(itr.startPrefix)
+		err := itr.Seek(ctx, itr.startPrefix)
 		if err != nil { // skipcq: TCV-001
 			return nil, err
 		}
@@ -87,11 +88,11 @@ func (idx *Index) NewStringIterator(start, end string, limit int64) (*Iterator,
 }
 
 // NewIntIterator creates a new index iterator with start prefix, endPrefix and the limit to iterate.
-func (idx *Index) NewIntIterator(start, end, limit int64) (*Iterator, error) {
+func (idx *Index) NewIntIterator(ctx context.Context, start, end, limit int64) (*Iterator, error) {
 	var manifest *Manifest
 	if idx.mutable {
 		// get the first feed of the Index
-		mf, err := idx.loadManifest(idx.name, idx.encryptionPassword)
+		mf, err := idx.loadManifest(ctx, idx.name, idx.encryptionPassword)
 		if err != nil { // skipcq: TCV-001
 			return nil, ErrEmptyIndex
 		}
@@ -122,7 +123,7 @@ func (idx *Index) NewIntIterator(start, end, limit int64) (*Iterator, error) {
 	}
 
 	if itr.startPrefix != "" {
-		err := itr.Seek(itr.startPrefix)
+		err := itr.Seek(ctx, itr.startPrefix)
 		if err != nil { // skipcq: TCV-001
 			return nil, err
 		}
@@ -139,10 +140,10 @@ func (idx *Index) NewIntIterator(start, end, limit int64) (*Iterator, error) {
 }
 
 // Seek seeks to the given key prefix.
-func (itr *Iterator) Seek(key string) error {
+func (itr *Iterator) Seek(ctx context.Context, key string) error {
 	var manifest *Manifest
 	if itr.index.mutable {
-		mf, err := itr.index.loadManifest(itr.index.name, itr.index.encryptionPassword)
+		mf, err := itr.index.loadManifest(ctx, itr.index.name, itr.index.encryptionPassword)
 		if err != nil { // skipcq: TCV-001
 			return err
 		}
@@ -154,7 +155,7 @@ func (itr *Iterator) Seek(key string) error {
 	// Set the index type here from the Manifest
 	itr.indexType = manifest.IdxType
 
-	err := itr.seekStringKey(manifest, key)
+	err := itr.seekStringKey(ctx, manifest, key)
 	if err != nil { // skipcq: TCV-001
 		return err
 	}
@@ -163,8 +164,8 @@ func (itr *Iterator) Seek(key string) error {
 }
 
 // Next moves the seek pointer one step ahead.
-func (itr *Iterator) Next() bool {
-	return itr.nextStringKey()
+func (itr *Iterator) Next(ctx context.Context) bool {
+	return itr.nextStringKey(ctx)
 }
 
 // StringKey
@@ -192,7 +193,7 @@ func (itr *Iterator) ValueAll() [][]byte {
 }
 
 // Non-API functions
-func (itr *Iterator) seekStringKey(manifest *Manifest, key string) error {
+func (itr *Iterator) seekStringKey(ctx context.Context, manifest *Manifest, key string) error {
 	// if there are any elements in the index, then search for the entry
 	if len(manifest.Entries) > 0 {
 		for i, entry := range manifest.Entries {
@@ -238,7 +239,7 @@ func (itr *Iterator) seekStringKey(manifest *Manifest, key string) error {
 				var childManifest *Manifest
 				if itr.index.mutable || entry.Manifest == nil {
 					// now load the child Manifest and re-seek
-					cf, err := itr.index.loadManifest(manifest.Name+entry.Name, itr.index.encryptionPassword)
+					cf, err := itr.index.loadManifest(ctx, manifest.Name+entry.Name, itr.index.encryptionPassword)
 					if err != nil { // skipcq: TCV-001
 						return err
 					}
@@ -248,7 +249,7 @@ func (itr *Iterator) seekStringKey(manifest *Manifest, key string) error {
 				}
 
 				childKey := strings.TrimPrefix(key, entry.Name)
-				err := itr.seekStringKey(childManifest, childKey)
+				err := itr.seekStringKey(ctx, childManifest, childKey)
 				if err != nil { // skipcq: TCV-001
 					if errors.Is(err, ErrEntryNotFound) {
 						return nil
@@ -274,7 +275,7 @@ func (itr *Iterator) seekStringKey(manifest *Manifest, key string) error {
 						var childManifest *Manifest
 						if itr.index.mutable {
 							// now load the child Manifest and re-seek
-							cf, err := itr.index.loadManifest(manifest.Name+entry.Name, itr.index.encryptionPassword)
+							cf, err := itr.index.loadManifest(ctx, manifest.Name+entry.Name, itr.index.encryptionPassword)
 							if err != nil {
 								return err
 							}
@@ -284,7 +285,7 @@ func (itr *Iterator) seekStringKey(manifest *Manifest, key string) error {
 						}
 
 						childKey := key[len(reducedKey):]
-						err := itr.seekStringKey(childManifest, childKey)
+						err := itr.seekStringKey(ctx, childManifest, childKey)
 						if err != nil {
 							if errors.Is(err, ErrEntryNotFound) {
 								return nil
@@ -299,7 +300,7 @@ func (itr *Iterator) seekStringKey(manifest *Manifest, key string) error {
 	return ErrEntryNotFound // skipcq: TCV-001
 }
 
-func (itr *Iterator) nextStringKey() bool {
+func (itr *Iterator) nextStringKey(ctx context.Context) bool {
 	// don't go beyond the limit
 	if itr.limit >= 0 {
 		if itr.givenUntilNow >= itr.limit {
@@ -367,7 +368,7 @@ func (itr *Iterator) nextStringKey() bool {
 	if entry.EType == IntermediateEntry {
 		var newManifest *Manifest
 		if itr.index.mutable {
-			mf, err := itr.index.loadManifest(manifestState.currentManifest.Name+entry.Name, itr.index.encryptionPassword)
+			mf, err := itr.index.loadManifest(ctx, manifestState.currentManifest.Name+entry.Name, itr.index.encryptionPassword)
 			if err != nil { // skipcq: TCV-001
 				itr.error = err
 				return false
@@ -382,7 +383,7 @@ func (itr *Iterator) nextStringKey() bool {
 			currentIndex:    0,
 		}
 		itr.manifestStack = append(itr.manifestStack, newManifestState)
-		return itr.nextStringKey()
+		return itr.nextStringKey(ctx)
 	}
 	return false // skipcq: TCV-001
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package collection_test
 
 import (
+	"context"
 	"crypto/rand"
 	"fmt"
 	"io"
@@ -37,6 +38,7 @@ import (
 )
 
 func TestIndexIterator(t *testing.T) {
+	ctx := context.Background()
 	mockClient := mock.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -58,14 +60,14 @@ func TestIndexIterator(t *testing.T) {
 		sortedKeys, sortedValues := sortLexicographically(t, keys, values)
 
 		// iterate with "no end key" and "no limit"
-		itr, err := idx.NewStringIterator("0", "", -1)
+		itr, err := idx.NewStringIterator(ctx, "0", "", -1)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// check the iteration is in order
 		for i := 0; i < int(actualCount); i++ {
-			if itr.Next() {
+			if itr.Next(ctx) {
 				key := sortedKeys[i]
 				value := sortedValues[i]
 				if itr.StringKey() != key {
@@ -88,14 +90,14 @@ func TestIndexIterator(t *testing.T) {
 		sortedKeys, sortedValues := sortLexicographically(t, keys, values)
 
 		// iterate with "no end key" and "no limit"
-		itr, err := idx.NewStringIterator("0", "", -1)
+		itr, err := idx.NewStringIterator(ctx, "0", "", -1)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// check the iteration is in order
 		for i := 0; i < int(actualCount); i++ {
-			if itr.Next() {
+			if itr.Next(ctx) {
 				key := sortedKeys[i]
 				value := sortedValues[i]
 				if itr.StringKey() != key {
@@ -118,14 +120,14 @@ func TestIndexIterator(t *testing.T) {
 		sortedKeys, sortedValues := sortLexicographically(t, keys, values)
 
 		// iterate with "no end key" and "no limit"
-		itr, err := idx.NewStringIterator("0", "20", -1)
+		itr, err := idx.NewStringIterator(ctx, "0", "20", -1)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// check the iteration is in order until the end key
 		for i := 0; i < 14; i++ {
-			if itr.Next() {
+			if itr.Next(ctx) {
 				key := sortedKeys[i]
 				value := sortedValues[i]
 				if itr.StringKey() != key {
@@ -138,7 +140,7 @@ func TestIndexIterator(t *testing.T) {
 		}
 
 		// do ite.Next() after end key to see that it should not return anything
-		if itr.Next() {
+		if itr.Next(ctx) {
 			t.Fatalf("iterating beyond end key")
 		}
 	})
@@ -152,7 +154,7 @@ func TestIndexIterator(t *testing.T) {
 		sortedKeys, sortedValues := sortLexicographically(t, keys, values)
 
 		// iterate with "no end key" and "no limit"
-		itr, err := idx.NewStringIterator("00", "20", -1)
+		itr, err := idx.NewStringIterator(ctx, "00", "20", -1)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -160,7 +162,7 @@ func TestIndexIterator(t *testing.T) {
 		// check the iteration is in order until the end key
 		// skip the first key since "0" is lexicographically smaller than "00"
 		for i := 1; i < 14; i++ {
-			if itr.Next() {
+			if itr.Next(ctx) {
 				key := sortedKeys[i]
 				value := sortedValues[i]
 				if itr.StringKey() != key {
@@ -173,7 +175,7 @@ func TestIndexIterator(t *testing.T) {
 		}
 
 		// do ite.Next() after end key to see that it should not return anything
-		if itr.Next() {
+		if itr.Next(ctx) {
 			t.Fatalf("iterating beyond end key")
 		}
 	})
@@ -188,14 +190,14 @@ func TestIndexIterator(t *testing.T) {
 		sortedKeys, sortedValues := sortLexicographically(t, keys, values)
 
 		// iterate with "no end key" and "no limit"
-		itr, err := idx.NewStringIterator("0", "20", 10)
+		itr, err := idx.NewStringIterator(ctx, "0", "20", 10)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// check the iteration is in order until the end key
 		for i := 0; i < 10; i++ {
-			if itr.Next() {
+			if itr.Next(ctx) {
 				key := sortedKeys[i]
 				value := sortedValues[i]
 				if itr.StringKey() != key {
@@ -208,7 +210,7 @@ func TestIndexIterator(t *testing.T) {
 		}
 
 		// do ite.Next() after end key to see that it should not return anything
-		if itr.Next() {
+		if itr.Next(ctx) {
 			t.Fatalf("iterating beyng limit")
 		}
 	})
@@ -222,14 +224,14 @@ func TestIndexIterator(t *testing.T) {
 		_, _ = addDocsForNumberIteration(t, idx, actualCount)
 
 		// iterate with "no end key" and "no limit"
-		itr, err := idx.NewIntIterator(0, -1, -1)
+		itr, err := idx.NewIntIterator(ctx, 0, -1, -1)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// check the iteration is in order
 		for i := 0; i < int(actualCount); i++ {
-			if itr.Next() {
+			if itr.Next(ctx) {
 				key := strconv.Itoa(i)
 				value := "value" + strconv.Itoa(i)
 				if itr.IntegerKey() != int64(i) {
@@ -253,14 +255,14 @@ func TestIndexIterator(t *testing.T) {
 		sort.Ints(values)
 
 		// iterate with "no end key" and "no limit"
-		itr, err := idx.NewIntIterator(0, -1, -1)
+		itr, err := idx.NewIntIterator(ctx, 0, -1, -1)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// check the iteration is in order
 		for i, k := range keys {
-			if itr.Next() {
+			if itr.Next(ctx) {
 				key := fmt.Sprintf("%020.20g", float64(k))
 				value := fmt.Sprintf("%d", values[i])
 				if itr.IntegerKey() != int64(k) {
@@ -286,14 +288,14 @@ func TestIndexIterator(t *testing.T) {
 		_, _ = addDocsForNumberIteration(t, idx, actualCount)
 
 		// iterate with "no end key" and "no limit"
-		itr, err := idx.NewIntIterator(0, 22, -1)
+		itr, err := idx.NewIntIterator(ctx, 0, 22, -1)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// check the iteration is in order
 		for i := 0; i <= 22; i++ {
-			if itr.Next() {
+			if itr.Next(ctx) {
 				key := fmt.Sprintf("%d", i)
 				value := "value" + strconv.Itoa(i)
 				if itr.IntegerKey() != int64(i) {
@@ -306,7 +308,7 @@ func TestIndexIterator(t *testing.T) {
 		}
 
 		// do ite.Next() after end key to see that it should not return anything
-		if itr.Next() {
+		if itr.Next(ctx) {
 			t.Fatalf("iterating beyond end key")
 		}
 	})
@@ -320,14 +322,14 @@ func TestIndexIterator(t *testing.T) {
 		_, _ = addDocsForNumberIteration(t, idx, actualCount)
 
 		// iterate with "no end key" and "no limit"
-		itr, err := idx.NewIntIterator(0, 22, 10)
+		itr, err := idx.NewIntIterator(ctx, 0, 22, 10)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// check the iteration is in order
 		for i := 0; i < 10; i++ {
-			if itr.Next() {
+			if itr.Next(ctx) {
 				key := strconv.Itoa(i)
 				value := "value" + strconv.Itoa(i)
 				if itr.IntegerKey() != int64(i) {
@@ -340,7 +342,7 @@ func TestIndexIterator(t *testing.T) {
 		}
 
 		// do ite.Next() after end key to see that it should not return anything
-		if itr.Next() {
+		if itr.Next(ctx) {
 			t.Fatalf("iterating beyond end key")
 		}
 	})
@@ -442,11 +444,12 @@ func sortLexicographically(t *testing.T, keys, values []string) ([]string, []str
 
 func createAndOpenIndex(t *testing.T, podName, collectionName, podPassword string, indexType collection.IndexType, fd *feed.API, user utils.Address,
 	client blockstore.Client, ai *account.Info, logger logging.Logger) *collection.Index {
-	err := collection.CreateIndex(podName, collectionName, "key", podPassword, indexType, fd, user, client, true)
+	ctx := context.Background()
+	err := collection.CreateIndex(ctx, podName, collectionName, "key", podPassword, indexType, fd, user, client, true)
 	if err != nil {
 		t.Fatal(err)
 	}
-	idx, err := collection.OpenIndex(podName, collectionName, "key", podPassword, fd, ai, user, client, logger)
+	idx, err := collection.OpenIndex(ctx, podName, collectionName, "key", podPassword, fd, ai, user, client, logger)
 	if err != nil {
 		t.Fatal(err)
 	}
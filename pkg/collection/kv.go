@@ -19,6 +19,8 @@ package collection
 import (
 	"bufio"
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"strconv"
@@ -79,13 +81,13 @@ func NewKeyValueStore(podName string, fd *feed.API, ai *account.Info, user utils
 }
 
 // CreateKVTable creates the key value table  with a given index type.
-func (kv *KeyValue) CreateKVTable(name, encryptionPassword string, indexType IndexType) error {
+func (kv *KeyValue) CreateKVTable(ctx context.Context, name, encryptionPassword string, indexType IndexType) error {
 	if kv.fd.IsReadOnlyFeed() { // skipcq: TCV-001
 		return ErrReadOnlyIndex
 	}
 
 	// load the existing db's and see if this name is already there
-	kvtables, err := kv.LoadKVTables(encryptionPassword)
+	kvtables, err := kv.LoadKVTables(ctx, encryptionPassword)
 	if err != nil { // skipcq: TCV-001
 		return err
 	}
@@ -94,23 +96,28 @@ func (kv *KeyValue) CreateKVTable(name, encryptionPassword string, indexType Ind
 	}
 
 	//  since this tables is not present already, create the index required for this table
-	err = CreateIndex(kv.podName, defaultCollectionName, name, encryptionPassword, indexType, kv.fd, kv.user, kv.client, true)
+	err = CreateIndex(ctx, kv.podName, defaultCollectionName, name, encryptionPassword, indexType, kv.fd, kv.user, kv.client, true)
 	if err != nil { // skipcq: TCV-001
 		return err
 	}
 
 	// record the table as created
-	kvtables[name] = []string{indexType.String()}
-	return kv.storeKVTables(kvtables, encryptionPassword)
+	return kv.modifyKVTables(ctx, encryptionPassword, func(kvtables map[string][]string) error {
+		if _, ok := kvtables[name]; ok { // skipcq: TCV-001
+			return ErrKvTableAlreadyPresent
+		}
+		kvtables[name] = []string{indexType.String()}
+		return nil
+	})
 }
 
 // DeleteKVTable deletes a given key value table with all it's index and data entries.
-func (kv *KeyValue) DeleteKVTable(name, encryptionPassword string) error {
+func (kv *KeyValue) DeleteKVTable(ctx context.Context, name, encryptionPassword string) error {
 	if kv.fd.IsReadOnlyFeed() { // skipcq: TCV-001
 		return ErrReadOnlyIndex
 	}
 
-	kvtables, err := kv.LoadKVTables(encryptionPassword)
+	kvtables, err := kv.LoadKVTables(ctx, encryptionPassword)
 	if err != nil { // skipcq: TCV-001
 		return err
 	}
@@ -122,36 +129,39 @@ func (kv *KeyValue) DeleteKVTable(name, encryptionPassword string) error {
 	kv.openKVTMu.Lock()
 	defer kv.openKVTMu.Unlock()
 	if table, ok := kv.openKVTables[name]; ok {
-		err = table.index.DeleteIndex(encryptionPassword)
+		err = table.index.DeleteIndex(ctx, encryptionPassword)
 		if err != nil { // skipcq: TCV-001
 			return err
 		}
 		delete(kv.openKVTables, name)
 	} else {
-		idx, err := OpenIndex(kv.podName, defaultCollectionName, name, encryptionPassword, kv.fd, kv.ai, kv.user, kv.client, kv.logger)
+		idx, err := OpenIndex(ctx, kv.podName, defaultCollectionName, name, encryptionPassword, kv.fd, kv.ai, kv.user, kv.client, kv.logger)
 		if err != nil { // skipcq: TCV-001
 			return err
 		}
-		err = idx.DeleteIndex(encryptionPassword)
+		err = idx.DeleteIndex(ctx, encryptionPassword)
 		if err != nil { // skipcq: TCV-001
 			return err
 		}
 	}
-	delete(kvtables, name)
-	return kv.storeKVTables(kvtables, encryptionPassword)
+	return kv.modifyKVTables(ctx, encryptionPassword, func(kvtables map[string][]string) error {
+		delete(kvtables, name)
+		return nil
+	})
 }
 
 // DeleteAllKVTables deletes all key value tables with all their index and data entries.
-func (kv *KeyValue) DeleteAllKVTables(encryptionPassword string) error {
+func (kv *KeyValue) DeleteAllKVTables(ctx context.Context, encryptionPassword string) error {
 	if kv.fd.IsReadOnlyFeed() { // skipcq: TCV-001
 		return ErrReadOnlyIndex
 	}
 
-	kvtables, err := kv.LoadKVTables(encryptionPassword)
+	kvtables, err := kv.LoadKVTables(ctx, encryptionPassword)
 	if err != nil { // skipcq: TCV-001
 		return err
 	}
 
+	var deleted []string
 	for name := range kvtables {
 		if _, ok := kvtables[name]; !ok {
 			return ErrKVTableNotPresent
@@ -160,30 +170,36 @@ func (kv *KeyValue) DeleteAllKVTables(encryptionPassword string) error {
 		kv.openKVTMu.Lock()
 		defer kv.openKVTMu.Unlock()
 		if table, ok := kv.openKVTables[name]; ok {
-			err = table.index.DeleteIndex(encryptionPassword)
+			err = table.index.DeleteIndex(ctx, encryptionPassword)
 			if err != nil { // skipcq: TCV-001
 				return err
 			}
 			delete(kv.openKVTables, name)
 		} else {
-			idx, err := OpenIndex(kv.podName, defaultCollectionName, name, encryptionPassword, kv.fd, kv.ai, kv.user, kv.client, kv.logger)
+			idx, err := OpenIndex(ctx, kv.podName, defaultCollectionName, name, encryptionPassword, kv.fd, kv.ai, kv.user, kv.client, kv.logger)
 			if err != nil { // skipcq: TCV-001
 				return err
 			}
-			err = idx.DeleteIndex(encryptionPassword)
+			err = idx.DeleteIndex(ctx, encryptionPassword)
 			if err != nil { // skipcq: TCV-001
 				return err
 			}
 		}
-		delete(kvtables, name)
+		deleted = append(deleted, name)
 	}
 
-	return kv.storeKVTables(kvtables, encryptionPassword)
+	// the tables created by other writers in the meantime are kept
+	return kv.modifyKVTables(ctx, encryptionPassword, func(kvtables map[string][]string) error {
+		for _, name := range deleted {
+			delete(kvtables, name)
+		}
+		return nil
+	})
 }
 
 // OpenKVTable open a given key value table and loads the index.
-func (kv *KeyValue) OpenKVTable(name, encryptionPassword string) error {
-	kvtables, err := kv.LoadKVTables(encryptionPassword)
+func (kv *KeyValue) OpenKVTable(ctx context.Context, name, encryptionPassword string) error {
+	kvtables, err := kv.LoadKVTables(ctx, encryptionPassword)
 	if err != nil { // skipcq: TCV-001
 		return err
 	}
@@ -193,12 +209,12 @@ func (kv *KeyValue) OpenKVTable(name, encryptionPassword string) error {
 	}
 	idxType := toIndexTypeEnum(values[0])
 
-	idx, err := OpenIndex(kv.podName, defaultCollectionName, name, encryptionPassword, kv.fd, kv.ai, kv.user, kv.client, kv.logger)
+	idx, err := OpenIndex(ctx, kv.podName, defaultCollectionName, name, encryptionPassword, kv.fd, kv.ai, kv.user, kv.client, kv.logger)
 	if err != nil { // skipcq: TCV-001
 		return err
 	}
 
-	hdr, err := idx.Get(CSVHeaderKey)
+	hdr, err := idx.Get(ctx, CSVHeaderKey)
 	var columns []string
 	if err == nil && len(hdr) >= 1 { // skipcq: TCV-001
 		columns = strings.Split(string(hdr[0]), ",")
@@ -217,11 +233,11 @@ func (kv *KeyValue) OpenKVTable(name, encryptionPassword string) error {
 }
 
 // KVCount counts the number of entries in the given key value table.
-func (kv *KeyValue) KVCount(name, encryptionPassword string) (*TableKeyCount, error) {
+func (kv *KeyValue) KVCount(ctx context.Context, name, encryptionPassword string) (*TableKeyCount, error) {
 	kv.openKVTMu.Lock()
 	defer kv.openKVTMu.Unlock()
 	if table, ok := kv.openKVTables[name]; ok {
-		count, err := table.index.CountIndex(table.index.encryptionPassword)
+		count, err := table.index.CountIndex(ctx, table.index.encryptionPassword)
 		if err != nil {
 			return nil, err
 		}
@@ -230,11 +246,11 @@ func (kv *KeyValue) KVCount(name, encryptionPassword string) (*TableKeyCount, er
 			TableName: name,
 		}, nil
 	} else {
-		idx, err := OpenIndex(kv.podName, defaultCollectionName, name, encryptionPassword, kv.fd, kv.ai, kv.user, kv.client, kv.logger)
+		idx, err := OpenIndex(ctx, kv.podName, defaultCollectionName, name, encryptionPassword, kv.fd, kv.ai, kv.user, kv.client, kv.logger)
 		if err != nil {
 			return nil, err
 		}
-		count, err := idx.CountIndex(idx.encryptionPassword)
+		count, err := idx.CountIndex(ctx, idx.encryptionPassword)
 		if err != nil {
 			return nil, err
 		}
@@ -246,7 +262,7 @@ func (kv *KeyValue) KVCount(name, encryptionPassword string) (*TableKeyCount, er
 }
 
 // KVPut inserts a given key and value in to the KV table.
-func (kv *KeyValue) KVPut(name, key string, value []byte) error {
+func (kv *KeyValue) KVPut(ctx context.Context, name, key string, value []byte) error {
 	if kv.fd.IsReadOnlyFeed() { // skipcq: TCV-001
 		return ErrReadOnlyIndex
 	}
@@ -256,19 +272,19 @@ func (kv *KeyValue) KVPut(name, key string, value []byte) error {
 	if table, ok := kv.openKVTables[name]; ok {
 		switch table.indexType {
 		case StringIndex:
-			return table.index.Put(key, value, StringIndex, false)
+			return table.index.Put(ctx, key, value, StringIndex, false)
 		case NumberIndex:
 			fkey, err := strconv.ParseFloat(key, 64)
 			if err != nil {
 				return ErrKVKeyNotANumber
 			}
-			return table.index.PutNumber(fkey, value, NumberIndex, false)
+			return table.index.PutNumber(ctx, fkey, value, NumberIndex, false)
 		case BytesIndex:
-			ref, err := kv.client.UploadBlob(value, 0, true, true)
+			ref, err := kv.client.UploadBlob(ctx, value, 0, true, true)
 			if err != nil { // skipcq: TCV-001
 				return err
 			}
-			return table.index.Put(key, ref, StringIndex, false)
+			return table.index.Put(ctx, key, ref, StringIndex, false)
 		default: // skipcq: TCV-001
 			return ErrKVInvalidIndexType
 		}
@@ -277,16 +293,16 @@ func (kv *KeyValue) KVPut(name, key string, value []byte) error {
 }
 
 // KVGet retrieves a value from the KV table given a key.
-func (kv *KeyValue) KVGet(name, key string) ([]string, []byte, error) {
+func (kv *KeyValue) KVGet(ctx context.Context, name, key string) ([]string, []byte, error) {
 	kv.openKVTMu.Lock()
 	defer kv.openKVTMu.Unlock()
 	if table, ok := kv.openKVTables[name]; ok {
-		value, err := table.index.Get(key)
+		value, err := table.index.Get(ctx, key)
 		if err != nil {
 			return nil, nil, err
 		}
 		if table.indexType == BytesIndex {
-			data, _, err := kv.client.DownloadBlob(value[0])
+			data, _, err := kv.client.DownloadBlob(ctx, value[0])
 			if err != nil { // skipcq: TCV-001
 				return nil, nil, err
 			}
@@ -298,7 +314,7 @@ func (kv *KeyValue) KVGet(name, key string) ([]string, []byte, error) {
 }
 
 // KVDelete removed a key value entry from the KV table given a key.
-func (kv *KeyValue) KVDelete(name, key string) ([]byte, error) {
+func (kv *KeyValue) KVDelete(ctx context.Context, name, key string) ([]byte, error) {
 	if kv.fd.IsReadOnlyFeed() { // skipcq: TCV-001
 		return nil, ErrReadOnlyIndex
 	}
@@ -306,7 +322,7 @@ func (kv *KeyValue) KVDelete(name, key string) ([]byte, error) {
 	kv.openKVTMu.Lock()
 	defer kv.openKVTMu.Unlock()
 	if table, ok := kv.openKVTables[name]; ok {
-		refs, err := table.index.Delete(key)
+		refs, err := table.index.Delete(ctx, key)
 		if err != nil {
 			return nil, err
 		}
@@ -330,7 +346,7 @@ func (kv *KeyValue) KVBatch(name string, columns []string) (*Batch, error) {
 }
 
 // KVBatchPut inserts a key and value in to the memory for batch.
-func (kv *KeyValue) KVBatchPut(batch *Batch, key string, value []byte) error {
+func (kv *KeyValue) KVBatchPut(ctx context.Context, batch *Batch, key string, value []byte) error {
 	if kv.fd.IsReadOnlyFeed() { // skipcq: TCV-001
 		return ErrReadOnlyIndex
 	}
@@ -342,26 +358,26 @@ func (kv *KeyValue) KVBatchPut(batch *Batch, key string, value []byte) error {
 			table.columns = strings.Split(string(value), ",")
 		}
 	}
-	return batch.Put(key, value, false, false)
+	return batch.Put(ctx, key, value, false, false)
 }
 
 // KVBatchWrite commits all the batch entries in to the key value table.
-func (kv *KeyValue) KVBatchWrite(batch *Batch) error {
+func (kv *KeyValue) KVBatchWrite(ctx context.Context, batch *Batch) error {
 	if kv.fd.IsReadOnlyFeed() { // skipcq: TCV-001
 		return ErrReadOnlyIndex
 	}
-	_, err := batch.Write("")
+	_, err := batch.Write(ctx, "")
 	return err
 }
 
 // KVSeek seek to given key with start prefix and prepare for iterating the table.
-func (kv *KeyValue) KVSeek(name, start, end string, limit int64) (*Iterator, error) {
+func (kv *KeyValue) KVSeek(ctx context.Context, name, start, end string, limit int64) (*Iterator, error) {
 	kv.openKVTMu.Lock()
 	defer kv.openKVTMu.Unlock()
 	if table, ok := kv.openKVTables[name]; ok {
 		switch table.indexType {
 		case StringIndex:
-			itr, err := table.index.NewStringIterator(start, end, limit)
+			itr, err := table.index.NewStringIterator(ctx, start, end, limit)
 			if err != nil { // skipcq: TCV-001
 				return nil, err
 			}
@@ -376,7 +392,7 @@ func (kv *KeyValue) KVSeek(name, start, end string, limit int64) (*Iterator, err
 			if err != nil { // skipcq: TCV-001
 				return nil, err
 			}
-			itr, err := table.index.NewIntIterator(startInt, endInt, limit)
+			itr, err := table.index.NewIntIterator(ctx, startInt, endInt, limit)
 			if err != nil { // skipcq: TCV-001
 				return nil, err
 			}
@@ -393,14 +409,14 @@ func (kv *KeyValue) KVSeek(name, start, end string, limit int64) (*Iterator, err
 }
 
 // KVGetNext retrieve the next key value pair in the iteration.
-func (kv *KeyValue) KVGetNext(name string) ([]string, string, []byte, error) {
+func (kv *KeyValue) KVGetNext(ctx context.Context, name string) ([]string, string, []byte, error) {
 	kv.openKVTMu.Lock()
 	defer kv.openKVTMu.Unlock()
 	if table, ok := kv.openKVTables[name]; ok {
 		if kv.iterator == nil {
 			return nil, "", nil, ErrKVNilIterator
 		}
-		ok := kv.iterator.Next()
+		ok := kv.iterator.Next(ctx)
 		if !ok {
 			return nil, "", nil, ErrNoNextElement
 		}
@@ -410,13 +426,20 @@ func (kv *KeyValue) KVGetNext(name string) ([]string, string, []byte, error) {
 }
 
 // LoadKVTables Loads the list of KV tables.
-func (kv *KeyValue) LoadKVTables(encryptionPassword string) (map[string][]string, error) {
+func (kv *KeyValue) LoadKVTables(ctx context.Context, encryptionPassword string) (map[string][]string, error) {
+	_, collections, err := kv.loadKVTables(ctx, encryptionPassword)
+	return collections, err
+}
+
+// loadKVTables loads the list of KV tables and the reference of the feed update it is
+// stored in
+func (kv *KeyValue) loadKVTables(ctx context.Context, encryptionPassword string) ([]byte, map[string][]string, error) {
 	collections := make(map[string][]string)
 	topic := utils.HashString(kvFile)
-	_, data, err := kv.fd.GetFeedData(topic, kv.user, []byte(encryptionPassword))
+	ref, data, err := kv.fd.GetFeedData(ctx, topic, kv.user, []byte(encryptionPassword))
 	if err != nil {
 		if err.Error() != "feed does not exist or was not updated yet" { // skipcq: TCV-001
-			return collections, err
+			return nil, collections, err
 		}
 	}
 
@@ -428,16 +451,39 @@ func (kv *KeyValue) LoadKVTables(encryptionPassword string) (map[string][]string
 			break
 		}
 		if err != nil { // skipcq: TCV-001
-			return nil, fmt.Errorf("loading collections: %w", err)
+			return nil, nil, fmt.Errorf("loading collections: %w", err)
 		}
 		line = strings.Trim(line, "\n")
 		lines := strings.Split(line, ",")
 		collections[lines[0]] = lines[1:]
 	}
-	return collections, nil
+	return ref, collections, nil
+}
+
+// modifyKVTables applies a change to the latest list of KV tables and stores it. A change
+// that conflicts with the change of another writer is applied again to the newer list.
+func (kv *KeyValue) modifyKVTables(ctx context.Context, encryptionPassword string, change func(kvtables map[string][]string) error) error {
+	for retries := 0; ; retries++ {
+		ref, kvtables, err := kv.loadKVTables(ctx, encryptionPassword)
+		if err != nil { // skipcq: TCV-001
+			return err
+		}
+		err = change(kvtables)
+		if err != nil { // skipcq: TCV-001
+			return err
+		}
+		err = kv.storeKVTables(ctx, kvtables, ref, encryptionPassword)
+		if errors.Is(err, feed.ErrConflict) && retries < feed.ConflictRetries {
+			kv.logger.Debugf("merging concurrent change of the kv tables")
+			continue
+		}
+		return err
+	}
 }
 
-func (kv *KeyValue) storeKVTables(collections map[string][]string, encryptionPassword string) error {
+// storeKVTables stores the list of KV tables, if the latest list is the one at the
+// expected reference
+func (kv *KeyValue) storeKVTables(ctx context.Context, collections map[string][]string, expected []byte, encryptionPassword string) error {
 	buf := bytes.NewBuffer(nil)
 	collectionLen := len(collections)
 	if collectionLen > 0 {
@@ -452,7 +498,7 @@ func (kv *KeyValue) storeKVTables(collections map[string][]string, encryptionPas
 	if buf.Len() == 0 {
 		data = []byte(utils.DeletedFeedMagicWord)
 	}
-	_, err := kv.fd.UpdateFeed(topic, kv.user, data, []byte(encryptionPassword))
+	_, err := kv.fd.UpdateFeedIf(ctx, topic, kv.user, expected, data, []byte(encryptionPassword))
 	if err != nil { // skipcq: TCV-001
 		return err
 	}
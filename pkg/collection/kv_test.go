@@ -18,6 +18,7 @@ package collection_test
 
 import (
 	"bytes"
+	"context"
 	"crypto/rand"
 	"errors"
 	"fmt"
@@ -43,6 +44,7 @@ func TestMain(m *testing.M) {
 }
 
 func TestKeyValueStore(t *testing.T) {
+	ctx := context.Background()
 	mockClient := mock.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -56,60 +58,60 @@ func TestKeyValueStore(t *testing.T) {
 	kvStore := collection.NewKeyValueStore("pod1", fd, ai, user, mockClient, logger)
 	podPassword, _ := utils.GetRandString(pod.PasswordLength)
 	t.Run("table_not_opened", func(t *testing.T) {
-		err := kvStore.CreateKVTable("kv_table_1314", podPassword, collection.StringIndex)
+		err := kvStore.CreateKVTable(ctx, "kv_table_1314", podPassword, collection.StringIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		err = kvStore.CreateKVTable("kv_table_1314", podPassword, collection.StringIndex)
+		err = kvStore.CreateKVTable(ctx, "kv_table_1314", podPassword, collection.StringIndex)
 		if !errors.Is(err, collection.ErrKvTableAlreadyPresent) {
 			t.Fatal("table should be already present")
 		}
 
-		_, _, _, err = kvStore.KVGetNext("kv_table_1314")
+		_, _, _, err = kvStore.KVGetNext(ctx, "kv_table_1314")
 		if !errors.Is(err, collection.ErrKVTableNotOpened) {
 			t.Fatal("open table")
 		}
-		err = kvStore.OpenKVTable("kv_table_1314", podPassword)
+		err = kvStore.OpenKVTable(ctx, "kv_table_1314", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 		// delete so that they don't show up in other testcases
-		err = kvStore.DeleteKVTable("kv_table_1314", podPassword)
+		err = kvStore.DeleteKVTable(ctx, "kv_table_1314", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 	})
 
 	t.Run("nil_itr", func(t *testing.T) {
-		err := kvStore.CreateKVTable("kv_table_1312", podPassword, collection.StringIndex)
+		err := kvStore.CreateKVTable(ctx, "kv_table_1312", podPassword, collection.StringIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.OpenKVTable("kv_table_1312", podPassword)
+		err = kvStore.OpenKVTable(ctx, "kv_table_1312", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		_, _, _, err = kvStore.KVGetNext("kv_table_1312")
+		_, _, _, err = kvStore.KVGetNext(ctx, "kv_table_1312")
 		if !errors.Is(err, collection.ErrKVNilIterator) {
 			t.Fatal("found iterator")
 		}
 
 		// delete so that they don't show up in other testcases
-		err = kvStore.DeleteKVTable("kv_table_1312", podPassword)
+		err = kvStore.DeleteKVTable(ctx, "kv_table_1312", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 	})
 
 	t.Run("create_kv_table_with_string_index", func(t *testing.T) {
-		err := kvStore.CreateKVTable("kv_table_0", podPassword, collection.StringIndex)
+		err := kvStore.CreateKVTable(ctx, "kv_table_0", podPassword, collection.StringIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		tables, err := kvStore.LoadKVTables(podPassword)
+		tables, err := kvStore.LoadKVTables(ctx, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -124,19 +126,19 @@ func TestKeyValueStore(t *testing.T) {
 		}
 
 		// delete so that they don't show up in other testcases
-		err = kvStore.DeleteKVTable("kv_table_0", podPassword)
+		err = kvStore.DeleteKVTable(ctx, "kv_table_0", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 	})
 
 	t.Run("create_kv_table_with_number_index", func(t *testing.T) {
-		err := kvStore.CreateKVTable("kv_table_1", podPassword, collection.NumberIndex)
+		err := kvStore.CreateKVTable(ctx, "kv_table_1", podPassword, collection.NumberIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		tables, err := kvStore.LoadKVTables(podPassword)
+		tables, err := kvStore.LoadKVTables(ctx, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -151,24 +153,24 @@ func TestKeyValueStore(t *testing.T) {
 		}
 
 		// delete so that they don't show up in other testcases
-		err = kvStore.DeleteKVTable("kv_table_1", podPassword)
+		err = kvStore.DeleteKVTable(ctx, "kv_table_1", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 	})
 
 	t.Run("check_delete", func(t *testing.T) {
-		err := kvStore.CreateKVTable("kv_table_2", podPassword, collection.StringIndex)
+		err := kvStore.CreateKVTable(ctx, "kv_table_2", podPassword, collection.StringIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		err = kvStore.DeleteKVTable("kv_table_2", podPassword)
+		err = kvStore.DeleteKVTable(ctx, "kv_table_2", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		tables, err := kvStore.LoadKVTables(podPassword)
+		tables, err := kvStore.LoadKVTables(ctx, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -180,20 +182,20 @@ func TestKeyValueStore(t *testing.T) {
 	})
 
 	t.Run("create_multiple_kv_tables_and_delete", func(t *testing.T) {
-		err := kvStore.CreateKVTable("kv_table_31", podPassword, collection.StringIndex)
+		err := kvStore.CreateKVTable(ctx, "kv_table_31", podPassword, collection.StringIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.CreateKVTable("kv_table_32", podPassword, collection.NumberIndex)
+		err = kvStore.CreateKVTable(ctx, "kv_table_32", podPassword, collection.NumberIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.CreateKVTable("kv_table_33", podPassword, collection.StringIndex)
+		err = kvStore.CreateKVTable(ctx, "kv_table_33", podPassword, collection.StringIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		tables, err := kvStore.LoadKVTables(podPassword)
+		tables, err := kvStore.LoadKVTables(ctx, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -228,12 +230,12 @@ func TestKeyValueStore(t *testing.T) {
 		}
 
 		// delete the last table
-		err = kvStore.DeleteKVTable("kv_table_33", podPassword)
+		err = kvStore.DeleteKVTable(ctx, "kv_table_33", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		tables, err = kvStore.LoadKVTables(podPassword)
+		tables, err = kvStore.LoadKVTables(ctx, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -260,19 +262,19 @@ func TestKeyValueStore(t *testing.T) {
 	})
 
 	t.Run("create_open_and_delete", func(t *testing.T) {
-		err := kvStore.CreateKVTable("kv_table_4", podPassword, collection.StringIndex)
+		err := kvStore.CreateKVTable(ctx, "kv_table_4", podPassword, collection.StringIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// open the table
-		err = kvStore.OpenKVTable("kv_table_4", podPassword)
+		err = kvStore.OpenKVTable(ctx, "kv_table_4", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// delete the opened table
-		err = kvStore.DeleteKVTable("kv_table_4", podPassword)
+		err = kvStore.DeleteKVTable(ctx, "kv_table_4", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -281,52 +283,52 @@ func TestKeyValueStore(t *testing.T) {
 
 	t.Run("delete_without_create", func(t *testing.T) {
 		// delete the last table
-		err = kvStore.DeleteKVTable("kv_table_5", podPassword)
+		err = kvStore.DeleteKVTable(ctx, "kv_table_5", podPassword)
 		if !errors.Is(err, collection.ErrKVTableNotPresent) {
 			t.Fatal("was able to delete table without creating it")
 		}
 	})
 
 	t.Run("open_table", func(t *testing.T) {
-		err := kvStore.CreateKVTable("kv_table_6", podPassword, collection.StringIndex)
+		err := kvStore.CreateKVTable(ctx, "kv_table_6", podPassword, collection.StringIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.OpenKVTable("kv_table_6", podPassword)
+		err = kvStore.OpenKVTable(ctx, "kv_table_6", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// do a put to see if the table is opened
-		err = kvStore.KVPut("kv_table_6", "key1", []byte("value1"))
+		err = kvStore.KVPut(ctx, "kv_table_6", "key1", []byte("value1"))
 		if err != nil {
 			t.Fatal(err)
 		}
 	})
 
 	t.Run("open_without_create", func(t *testing.T) {
-		err = kvStore.OpenKVTable("kv_table_7", podPassword)
+		err = kvStore.OpenKVTable(ctx, "kv_table_7", podPassword)
 		if !errors.Is(err, collection.ErrKVTableNotPresent) {
 			t.Fatal("was able to open table without creating it")
 		}
 	})
 
 	t.Run("put_string_index", func(t *testing.T) {
-		err := kvStore.CreateKVTable("kv_table_8", podPassword, collection.StringIndex)
+		err := kvStore.CreateKVTable(ctx, "kv_table_8", podPassword, collection.StringIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.OpenKVTable("kv_table_8", podPassword)
+		err = kvStore.OpenKVTable(ctx, "kv_table_8", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.KVPut("kv_table_8", "key1", []byte("value1"))
+		err = kvStore.KVPut(ctx, "kv_table_8", "key1", []byte("value1"))
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// get the value to see if it is present
-		columns, value, err := kvStore.KVGet("kv_table_8", "key1")
+		columns, value, err := kvStore.KVGet(ctx, "kv_table_8", "key1")
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -337,7 +339,7 @@ func TestKeyValueStore(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		countObject, err := kvStore.KVCount("kv_table_8", podPassword)
+		countObject, err := kvStore.KVCount(ctx, "kv_table_8", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -347,25 +349,25 @@ func TestKeyValueStore(t *testing.T) {
 	})
 
 	t.Run("put_bytes_index", func(t *testing.T) {
-		err := kvStore.CreateKVTable("kv_table_bytes", podPassword, collection.BytesIndex)
+		err := kvStore.CreateKVTable(ctx, "kv_table_bytes", podPassword, collection.BytesIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
-		_, _, err = kvStore.KVGet("kv_table_bytes", "key1")
+		_, _, err = kvStore.KVGet(ctx, "kv_table_bytes", "key1")
 		if !errors.Is(err, collection.ErrKVTableNotOpened) {
 			t.Fatal("kv table open")
 		}
-		err = kvStore.OpenKVTable("kv_table_bytes", podPassword)
+		err = kvStore.OpenKVTable(ctx, "kv_table_bytes", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.KVPut("kv_table_bytes", "key1", []byte("value1"))
+		err = kvStore.KVPut(ctx, "kv_table_bytes", "key1", []byte("value1"))
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// get the value to see if it is present
-		columns, value, err := kvStore.KVGet("kv_table_bytes", "key1")
+		columns, value, err := kvStore.KVGet(ctx, "kv_table_bytes", "key1")
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -376,7 +378,7 @@ func TestKeyValueStore(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		countObject, err := kvStore.KVCount("kv_table_bytes", podPassword)
+		countObject, err := kvStore.KVCount(ctx, "kv_table_bytes", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -386,21 +388,21 @@ func TestKeyValueStore(t *testing.T) {
 	})
 
 	t.Run("put_chinese_string_index", func(t *testing.T) {
-		err := kvStore.CreateKVTable("kv_table_9", podPassword, collection.StringIndex)
+		err := kvStore.CreateKVTable(ctx, "kv_table_9", podPassword, collection.StringIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.OpenKVTable("kv_table_9", podPassword)
+		err = kvStore.OpenKVTable(ctx, "kv_table_9", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.KVPut("kv_table_9", "立顯榮朝士", []byte("value1"))
+		err = kvStore.KVPut(ctx, "kv_table_9", "立顯榮朝士", []byte("value1"))
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// get the value to see if it is present
-		columns, value, err := kvStore.KVGet("kv_table_9", "立顯榮朝士")
+		columns, value, err := kvStore.KVGet(ctx, "kv_table_9", "立顯榮朝士")
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -413,40 +415,40 @@ func TestKeyValueStore(t *testing.T) {
 	})
 
 	t.Run("put_string_in_number_index", func(t *testing.T) {
-		err := kvStore.CreateKVTable("kv_table_10", podPassword, collection.NumberIndex)
+		err := kvStore.CreateKVTable(ctx, "kv_table_10", podPassword, collection.NumberIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.OpenKVTable("kv_table_10", podPassword)
+		err = kvStore.OpenKVTable(ctx, "kv_table_10", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.KVPut("kv_table_10", "key1", []byte("value1"))
+		err = kvStore.KVPut(ctx, "kv_table_10", "key1", []byte("value1"))
 		if !errors.Is(err, collection.ErrKVKeyNotANumber) {
 			t.Fatal("invalid number given as key for a number index")
 		}
 	})
 
 	t.Run("put_get_del_get_string_index", func(t *testing.T) {
-		err := kvStore.CreateKVTable("kv_table_11", podPassword, collection.StringIndex)
+		err := kvStore.CreateKVTable(ctx, "kv_table_11", podPassword, collection.StringIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.OpenKVTable("kv_table_11", podPassword)
+		err = kvStore.OpenKVTable(ctx, "kv_table_11", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.KVPut("kv_table_11", "key1", []byte("value1"))
+		err = kvStore.KVPut(ctx, "kv_table_11", "key1", []byte("value1"))
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.KVPut("kv_table_11", "key2", []byte("value2"))
+		err = kvStore.KVPut(ctx, "kv_table_11", "key2", []byte("value2"))
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// get the value to see if it is present
-		columns, value, err := kvStore.KVGet("kv_table_11", "key1")
+		columns, value, err := kvStore.KVGet(ctx, "kv_table_11", "key1")
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -458,13 +460,13 @@ func TestKeyValueStore(t *testing.T) {
 		}
 
 		// delete the key
-		_, err = kvStore.KVDelete("kv_table_11", "key1")
+		_, err = kvStore.KVDelete(ctx, "kv_table_11", "key1")
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// get it again and make sure it is not there
-		_, _, err = kvStore.KVGet("kv_table_11", "key1")
+		_, _, err = kvStore.KVGet(ctx, "kv_table_11", "key1")
 		if !errors.Is(err, collection.ErrEntryNotFound) {
 			t.Fatalf("found the deleted entry")
 		}
@@ -472,39 +474,39 @@ func TestKeyValueStore(t *testing.T) {
 	})
 
 	t.Run("put_without_opening_table", func(t *testing.T) {
-		err := kvStore.CreateKVTable("kv_table_12", podPassword, collection.StringIndex)
+		err := kvStore.CreateKVTable(ctx, "kv_table_12", podPassword, collection.StringIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.KVPut("kv_table_12", "key1", []byte("value1"))
+		err = kvStore.KVPut(ctx, "kv_table_12", "key1", []byte("value1"))
 		if !errors.Is(err, collection.ErrKVTableNotOpened) {
 			t.Fatalf("could put without opening the table")
 		}
 	})
 
 	t.Run("delete_non_existent_string_index", func(t *testing.T) {
-		err := kvStore.CreateKVTable("kv_table_13", podPassword, collection.StringIndex)
+		err := kvStore.CreateKVTable(ctx, "kv_table_13", podPassword, collection.StringIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.OpenKVTable("kv_table_13", podPassword)
+		err = kvStore.OpenKVTable(ctx, "kv_table_13", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.KVPut("kv_table_13", "key1", []byte("value1"))
+		err = kvStore.KVPut(ctx, "kv_table_13", "key1", []byte("value1"))
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// delete a non existent key
-		_, err = kvStore.KVDelete("kv_table_13", "key2")
+		_, err = kvStore.KVDelete(ctx, "kv_table_13", "key2")
 		if !errors.Is(err, collection.ErrEntryNotFound) {
 			t.Fatalf("found a non existent entry")
 		}
 	})
 
 	t.Run("batch_without_open", func(t *testing.T) {
-		err := kvStore.CreateKVTable("kv_table_batch_1", podPassword, collection.StringIndex)
+		err := kvStore.CreateKVTable(ctx, "kv_table_batch_1", podPassword, collection.StringIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -516,11 +518,11 @@ func TestKeyValueStore(t *testing.T) {
 	})
 
 	t.Run("batch_columns_and_get_values", func(t *testing.T) {
-		err := kvStore.CreateKVTable("kv_table_batch_2", podPassword, collection.StringIndex)
+		err := kvStore.CreateKVTable(ctx, "kv_table_batch_2", podPassword, collection.StringIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.OpenKVTable("kv_table_batch_2", podPassword)
+		err = kvStore.OpenKVTable(ctx, "kv_table_batch_2", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -532,17 +534,17 @@ func TestKeyValueStore(t *testing.T) {
 		}
 
 		value := []byte("v1,v2,v3")
-		err = batch.Put("key1", value, false, false)
+		err = batch.Put(ctx, "key1", value, false, false)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		_, err = batch.Write("")
+		_, err = batch.Write(ctx, "")
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		gotColumns, gotValue, err := kvStore.KVGet("kv_table_batch_2", "key1")
+		gotColumns, gotValue, err := kvStore.KVGet(ctx, "kv_table_batch_2", "key1")
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -561,11 +563,11 @@ func TestKeyValueStore(t *testing.T) {
 	})
 
 	t.Run("batch_put_columns_and_get_values", func(t *testing.T) {
-		err := kvStore.CreateKVTable("kv_table_batch_9", podPassword, collection.StringIndex)
+		err := kvStore.CreateKVTable(ctx, "kv_table_batch_9", podPassword, collection.StringIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.OpenKVTable("kv_table_batch_9", podPassword)
+		err = kvStore.OpenKVTable(ctx, "kv_table_batch_9", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -577,22 +579,22 @@ func TestKeyValueStore(t *testing.T) {
 		}
 
 		value := []byte("v1,v2,v3")
-		err = kvStore.KVBatchPut(batch, "key1", value)
+		err = kvStore.KVBatchPut(ctx, batch, "key1", value)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		err = batch.Put("key1", value, false, false)
+		err = batch.Put(ctx, "key1", value, false, false)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		err = kvStore.KVBatchWrite(batch)
+		err = kvStore.KVBatchWrite(ctx, batch)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		gotColumns, gotValue, err := kvStore.KVGet("kv_table_batch_9", "key1")
+		gotColumns, gotValue, err := kvStore.KVGet(ctx, "kv_table_batch_9", "key1")
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -611,11 +613,11 @@ func TestKeyValueStore(t *testing.T) {
 	})
 
 	t.Run("count_columns_and_get_values", func(t *testing.T) {
-		err := kvStore.CreateKVTable("kv_table_batch_count", podPassword, collection.StringIndex)
+		err := kvStore.CreateKVTable(ctx, "kv_table_batch_count", podPassword, collection.StringIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
-		countObject, err := kvStore.KVCount("kv_table_batch_count", podPassword)
+		countObject, err := kvStore.KVCount(ctx, "kv_table_batch_count", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -625,11 +627,11 @@ func TestKeyValueStore(t *testing.T) {
 	})
 
 	t.Run("Iterate_string_keys", func(t *testing.T) {
-		err := kvStore.CreateKVTable("kv_table_Itr_0", podPassword, collection.StringIndex)
+		err := kvStore.CreateKVTable(ctx, "kv_table_Itr_0", podPassword, collection.StringIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.OpenKVTable("kv_table_Itr_0", podPassword)
+		err = kvStore.OpenKVTable(ctx, "kv_table_Itr_0", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -640,14 +642,14 @@ func TestKeyValueStore(t *testing.T) {
 		}
 		sortedKeys, sortedValues := sortLexicographically(t, keys, values)
 
-		itr, err := kvStore.KVSeek("kv_table_Itr_0", "", "", -1)
+		itr, err := kvStore.KVSeek(ctx, "kv_table_Itr_0", "", "", -1)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// check the order of the keys
 		for i := 0; i < 100; i++ {
-			itr.Next()
+			itr.Next(ctx)
 			if itr.StringKey() != sortedKeys[i] {
 				t.Fatal("keys do not match", itr.StringKey(), sortedKeys[i])
 			}
@@ -661,11 +663,11 @@ func TestKeyValueStore(t *testing.T) {
 		tableNo := 0
 	research:
 		tableNo++
-		err := kvStore.CreateKVTable(fmt.Sprintf("kv_table_Itr_01%d", tableNo), podPassword, collection.StringIndex)
+		err := kvStore.CreateKVTable(ctx, fmt.Sprintf("kv_table_Itr_01%d", tableNo), podPassword, collection.StringIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.OpenKVTable(fmt.Sprintf("kv_table_Itr_01%d", tableNo), podPassword)
+		err = kvStore.OpenKVTable(ctx, fmt.Sprintf("kv_table_Itr_01%d", tableNo), podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -675,7 +677,7 @@ func TestKeyValueStore(t *testing.T) {
 		}
 		sortedKeys, sortedValues := sortLexicographically(t, keys, values)
 
-		itr, err := kvStore.KVSeek(fmt.Sprintf("kv_table_Itr_01%d", tableNo), "B", "", 10)
+		itr, err := kvStore.KVSeek(ctx, fmt.Sprintf("kv_table_Itr_01%d", tableNo), "B", "", 10)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -694,7 +696,7 @@ func TestKeyValueStore(t *testing.T) {
 
 		// check the order of the keys
 		for i := startIndex; i < startIndex+10; i++ {
-			itr.Next()
+			itr.Next(ctx)
 			if itr.StringKey() != sortedKeys[i] {
 				t.Fatalf("key mismatch: %s : %s\n", itr.StringKey(), sortedKeys[i])
 			}
@@ -704,7 +706,7 @@ func TestKeyValueStore(t *testing.T) {
 		}
 
 		// do ite.Next() after limit to see that it should not return anything
-		if itr.Next() {
+		if itr.Next(ctx) {
 			t.Fatalf("iterating beyond limit")
 		}
 
@@ -714,11 +716,11 @@ func TestKeyValueStore(t *testing.T) {
 		tableNo := 0
 	research:
 		tableNo++
-		err := kvStore.CreateKVTable(fmt.Sprintf("kv_table_Itr_1%d", tableNo), podPassword, collection.StringIndex)
+		err := kvStore.CreateKVTable(ctx, fmt.Sprintf("kv_table_Itr_1%d", tableNo), podPassword, collection.StringIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.OpenKVTable(fmt.Sprintf("kv_table_Itr_1%d", tableNo), podPassword)
+		err = kvStore.OpenKVTable(ctx, fmt.Sprintf("kv_table_Itr_1%d", tableNo), podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -755,7 +757,7 @@ func TestKeyValueStore(t *testing.T) {
 		if !matched {
 			goto research
 		}
-		itr, err := kvStore.KVSeek(fmt.Sprintf("kv_table_Itr_1%d", tableNo), startPrefix, endPrefix, -1)
+		itr, err := kvStore.KVSeek(ctx, fmt.Sprintf("kv_table_Itr_1%d", tableNo), startPrefix, endPrefix, -1)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -764,7 +766,7 @@ func TestKeyValueStore(t *testing.T) {
 		}
 		// check the order of the keys
 		for i := startIndex; i < endIndex; i++ {
-			itr.Next()
+			itr.Next(ctx)
 			if itr.StringKey() != sortedKeys[i] {
 				t.Fatal("keys do not match", itr.StringKey(), sortedKeys[i])
 			}
@@ -774,7 +776,7 @@ func TestKeyValueStore(t *testing.T) {
 		}
 
 		// do ite.Next() after end to see that it should not return anything
-		if itr.Next() {
+		if itr.Next(ctx) {
 			t.Fatalf("iterating beyond end %s %v", itr.StringKey(), string(itr.Value()))
 		}
 
@@ -782,11 +784,11 @@ func TestKeyValueStore(t *testing.T) {
 
 	t.Run("Iterate_seek_start_end_string_keys_over_a_known_failing_keys", func(t *testing.T) {
 		tableNo := 486
-		err := kvStore.CreateKVTable(fmt.Sprintf("kv_table_Itr_1%d", tableNo), podPassword, collection.StringIndex)
+		err := kvStore.CreateKVTable(ctx, fmt.Sprintf("kv_table_Itr_1%d", tableNo), podPassword, collection.StringIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.OpenKVTable(fmt.Sprintf("kv_table_Itr_1%d", tableNo), podPassword)
+		err = kvStore.OpenKVTable(ctx, fmt.Sprintf("kv_table_Itr_1%d", tableNo), podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -810,7 +812,7 @@ func TestKeyValueStore(t *testing.T) {
 			"DQCUdYBL2xDT",
 		}
 		for _, i := range list {
-			err = kvStore.KVPut(fmt.Sprintf("kv_table_Itr_1%d", tableNo), i, []byte(i))
+			err = kvStore.KVPut(ctx, fmt.Sprintf("kv_table_Itr_1%d", tableNo), i, []byte(i))
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -836,13 +838,13 @@ func TestKeyValueStore(t *testing.T) {
 				}
 			}
 		}
-		itr, err := kvStore.KVSeek(fmt.Sprintf("kv_table_Itr_1%d", tableNo), startPrefix, endPrefix, -1)
+		itr, err := kvStore.KVSeek(ctx, fmt.Sprintf("kv_table_Itr_1%d", tableNo), startPrefix, endPrefix, -1)
 		if err != nil {
 			t.Fatal(err)
 		}
 		// check the order of the keys
 		for i := startIndex; i < endIndex; i++ {
-			itr.Next()
+			itr.Next(ctx)
 			if itr.StringKey() != list[i] {
 				t.Fatal("keys do not match", itr.StringKey(), list[i])
 			}
@@ -852,18 +854,18 @@ func TestKeyValueStore(t *testing.T) {
 		}
 
 		// do ite.Next() after end to see that it should not return anything
-		if itr.Next() {
+		if itr.Next(ctx) {
 			t.Fatalf("iterating beyond end %s %v", itr.StringKey(), string(itr.Value()))
 		}
 
 	})
 
 	t.Run("Iterate_string_of_numbers_keys", func(t *testing.T) {
-		err := kvStore.CreateKVTable("kv_table_Itr_3", podPassword, collection.StringIndex)
+		err := kvStore.CreateKVTable(ctx, "kv_table_Itr_3", podPassword, collection.StringIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.OpenKVTable("kv_table_Itr_3", podPassword)
+		err = kvStore.OpenKVTable(ctx, "kv_table_Itr_3", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -874,14 +876,14 @@ func TestKeyValueStore(t *testing.T) {
 		}
 		sortedKeys, sortedValues := sortLexicographically(t, keys, values)
 
-		itr, err := kvStore.KVSeek("kv_table_Itr_3", "", "", -1)
+		itr, err := kvStore.KVSeek(ctx, "kv_table_Itr_3", "", "", -1)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// check the order of the keys
 		for i := 0; i < 100; i++ {
-			itr.Next()
+			itr.Next(ctx)
 			if itr.StringKey() != sortedKeys[i] {
 				t.Fatal("keys do not match", itr.StringKey(), sortedKeys[i])
 			}
@@ -892,11 +894,11 @@ func TestKeyValueStore(t *testing.T) {
 	})
 
 	t.Run("Iterate_numbers_keys", func(t *testing.T) {
-		err := kvStore.CreateKVTable("kv_table_Itr_4", podPassword, collection.NumberIndex)
+		err := kvStore.CreateKVTable(ctx, "kv_table_Itr_4", podPassword, collection.NumberIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.OpenKVTable("kv_table_Itr_4", podPassword)
+		err = kvStore.OpenKVTable(ctx, "kv_table_Itr_4", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -908,14 +910,14 @@ func TestKeyValueStore(t *testing.T) {
 		sort.Ints(keys)
 		sort.Ints(values)
 
-		itr, err := kvStore.KVSeek("kv_table_Itr_4", "-1", "-1", -1)
+		itr, err := kvStore.KVSeek(ctx, "kv_table_Itr_4", "-1", "-1", -1)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// check the order of the keys
 		for i := 0; i < 100; i++ {
-			itr.Next()
+			itr.Next(ctx)
 			if itr.IntegerKey() != int64(keys[i]) {
 				t.Fatal("keys do not match", itr.StringKey(), keys[i])
 			}
@@ -926,11 +928,11 @@ func TestKeyValueStore(t *testing.T) {
 	})
 
 	t.Run("Iterate_numbers_start_end_keys", func(t *testing.T) {
-		err := kvStore.CreateKVTable("kv_table_Itr_5", podPassword, collection.NumberIndex)
+		err := kvStore.CreateKVTable(ctx, "kv_table_Itr_5", podPassword, collection.NumberIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.OpenKVTable("kv_table_Itr_5", podPassword)
+		err = kvStore.OpenKVTable(ctx, "kv_table_Itr_5", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -942,7 +944,7 @@ func TestKeyValueStore(t *testing.T) {
 		sort.Ints(keys)
 		sort.Ints(values)
 
-		itr, err := kvStore.KVSeek("kv_table_Itr_5", "10", "200", -1)
+		itr, err := kvStore.KVSeek(ctx, "kv_table_Itr_5", "10", "200", -1)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -963,7 +965,7 @@ func TestKeyValueStore(t *testing.T) {
 
 		// check the order of the keys
 		for i := startIndex; i < endIndex; i++ {
-			itr.Next()
+			itr.Next(ctx)
 			if itr.IntegerKey() != int64(keys[i]) {
 				t.Fatal("keys do not match", itr.StringKey(), keys[i])
 			}
@@ -973,17 +975,17 @@ func TestKeyValueStore(t *testing.T) {
 		}
 
 		// do ite.Next() after end to see that it should not return anything
-		if itr.Next() {
+		if itr.Next(ctx) {
 			t.Fatalf("iterating beyond end")
 		}
 	})
 
 	t.Run("Iterate_numbers_start_and_limit_keys", func(t *testing.T) {
-		err := kvStore.CreateKVTable("kv_table_Itr_6", podPassword, collection.NumberIndex)
+		err := kvStore.CreateKVTable(ctx, "kv_table_Itr_6", podPassword, collection.NumberIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.OpenKVTable("kv_table_Itr_6", podPassword)
+		err = kvStore.OpenKVTable(ctx, "kv_table_Itr_6", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -1003,14 +1005,14 @@ func TestKeyValueStore(t *testing.T) {
 			}
 		}
 
-		itr, err := kvStore.KVSeek("kv_table_Itr_6", "50", "-1", 10)
+		itr, err := kvStore.KVSeek(ctx, "kv_table_Itr_6", "50", "-1", 10)
 		if err != nil && !errors.Is(err, collection.ErrEntryNotFound) {
 			t.Fatal(err)
 		}
 
 		// check the order of the keys
 		for i := startIndex; i < startIndex+10; i++ {
-			itr.Next()
+			itr.Next(ctx)
 			if itr.IntegerKey() != int64(keys[i]) {
 				t.Fatal("keys do not match", itr.StringKey(), keys[i])
 			}
@@ -1020,116 +1022,116 @@ func TestKeyValueStore(t *testing.T) {
 		}
 
 		// do ite.Next() after limit to see that it should not return anything
-		if itr.Next() {
+		if itr.Next(ctx) {
 			t.Fatalf("iterating beyond limit")
 		}
 	})
 
 	t.Run("get_non_existent_string_index", func(t *testing.T) {
-		err := kvStore.CreateKVTable("kv_table_1313", podPassword, collection.StringIndex)
+		err := kvStore.CreateKVTable(ctx, "kv_table_1313", podPassword, collection.StringIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.OpenKVTable("kv_table_1313", podPassword)
+		err = kvStore.OpenKVTable(ctx, "kv_table_1313", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.KVPut("kv_table_1313", "key1", []byte("value1"))
+		err = kvStore.KVPut(ctx, "kv_table_1313", "key1", []byte("value1"))
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		_, err = kvStore.KVSeek("kv_table_1313", "key1", "", -1)
+		_, err = kvStore.KVSeek(ctx, "kv_table_1313", "key1", "", -1)
 		if err != nil {
 			t.Fatal(err)
 		}
 		// this should have value
-		_, _, _, err = kvStore.KVGetNext("kv_table_1313")
+		_, _, _, err = kvStore.KVGetNext(ctx, "kv_table_1313")
 		if err != nil {
 			t.Fatal(err)
 		}
 		// this should not have value
-		_, _, _, err = kvStore.KVGetNext("kv_table_1313")
+		_, _, _, err = kvStore.KVGetNext(ctx, "kv_table_1313")
 		if !errors.Is(err, collection.ErrNoNextElement) {
 			t.Fatal("found a nonexistent key")
 		}
 	})
 
 	t.Run("err_byte_index", func(t *testing.T) {
-		err := kvStore.CreateKVTable("kv_table_1316", podPassword, collection.BytesIndex)
+		err := kvStore.CreateKVTable(ctx, "kv_table_1316", podPassword, collection.BytesIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.OpenKVTable("kv_table_1316", podPassword)
+		err = kvStore.OpenKVTable(ctx, "kv_table_1316", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.KVPut("kv_table_1316", "key1", []byte("value1"))
+		err = kvStore.KVPut(ctx, "kv_table_1316", "key1", []byte("value1"))
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		_, err = kvStore.KVSeek("kv_table_1316", "key1", "", -1)
+		_, err = kvStore.KVSeek(ctx, "kv_table_1316", "key1", "", -1)
 		if !errors.Is(err, collection.ErrKVIndexTypeNotSupported) {
 			t.Fatal("unsupported index")
 		}
 	})
 
 	t.Run("err_seek_list_index", func(t *testing.T) {
-		err := kvStore.CreateKVTable("kv_table_1317", podPassword, collection.ListIndex)
+		err := kvStore.CreateKVTable(ctx, "kv_table_1317", podPassword, collection.ListIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.OpenKVTable("kv_table_1317", podPassword)
+		err = kvStore.OpenKVTable(ctx, "kv_table_1317", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		_, err = kvStore.KVSeek("kv_table_1317", "key1", "", -1)
+		_, err = kvStore.KVSeek(ctx, "kv_table_1317", "key1", "", -1)
 		if !errors.Is(err, collection.ErrKVInvalidIndexType) {
 			t.Fatal("invalid index")
 		}
 	})
 
 	t.Run("err_seek_map_index", func(t *testing.T) {
-		err := kvStore.CreateKVTable("kv_table_1318", podPassword, collection.MapIndex)
+		err := kvStore.CreateKVTable(ctx, "kv_table_1318", podPassword, collection.MapIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.OpenKVTable("kv_table_1318", podPassword)
+		err = kvStore.OpenKVTable(ctx, "kv_table_1318", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		_, err = kvStore.KVSeek("kv_table_1318", "key1", "", -1)
+		_, err = kvStore.KVSeek(ctx, "kv_table_1318", "key1", "", -1)
 		if !errors.Is(err, collection.ErrKVInvalidIndexType) {
 			t.Fatal("invalid index")
 		}
 	})
 
 	t.Run("err_seek_invalid_index", func(t *testing.T) {
-		err := kvStore.CreateKVTable("kv_table_1319", podPassword, collection.InvalidIndex)
+		err := kvStore.CreateKVTable(ctx, "kv_table_1319", podPassword, collection.InvalidIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = kvStore.OpenKVTable("kv_table_1319", podPassword)
+		err = kvStore.OpenKVTable(ctx, "kv_table_1319", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		_, err = kvStore.KVSeek("kv_table_1319", "key1", "", -1)
+		_, err = kvStore.KVSeek(ctx, "kv_table_1319", "key1", "", -1)
 		if !errors.Is(err, collection.ErrKVInvalidIndexType) {
 			t.Fatal("invalid index")
 		}
 	})
 
 	t.Run("seek_unopened_table", func(t *testing.T) {
-		err := kvStore.CreateKVTable("kv_table_1320", podPassword, collection.ListIndex)
+		err := kvStore.CreateKVTable(ctx, "kv_table_1320", podPassword, collection.ListIndex)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		_, err = kvStore.KVSeek("kv_table_1320", "key1", "", -1)
+		_, err = kvStore.KVSeek(ctx, "kv_table_1320", "key1", "", -1)
 		if !errors.Is(err, collection.ErrKVTableNotOpened) {
 			t.Fatal("table open")
 		}
@@ -1137,6 +1139,7 @@ func TestKeyValueStore(t *testing.T) {
 }
 
 func addRandomStrings(t *testing.T, kvStore *collection.KeyValue, count int, tableName string) ([]string, []string, error) {
+	ctx := context.Background()
 	var keys []string
 	var values []string
 	for i := 0; i < count; i++ {
@@ -1156,7 +1159,7 @@ func addRandomStrings(t *testing.T, kvStore *collection.KeyValue, count int, tab
 			}
 		}
 
-		err = kvStore.KVPut(tableName, key, []byte(key))
+		err = kvStore.KVPut(ctx, tableName, key, []byte(key))
 		if err != nil {
 			return nil, nil, err
 		}
@@ -1167,6 +1170,7 @@ func addRandomStrings(t *testing.T, kvStore *collection.KeyValue, count int, tab
 }
 
 func addRandomNumbersAsString(t *testing.T, kvStore *collection.KeyValue, count int, tableName string) ([]string, []string, error) {
+	ctx := context.Background()
 	var keys []string
 	var values []string
 	for i := 0; i < count; i++ {
@@ -1183,7 +1187,7 @@ func addRandomNumbersAsString(t *testing.T, kvStore *collection.KeyValue, count
 			}
 		}
 
-		err = kvStore.KVPut(tableName, strKey, []byte(strKey))
+		err = kvStore.KVPut(ctx, tableName, strKey, []byte(strKey))
 		if err != nil {
 			return nil, nil, err
 		}
@@ -1194,6 +1198,7 @@ func addRandomNumbersAsString(t *testing.T, kvStore *collection.KeyValue, count
 }
 
 func addRandomNumbers(t *testing.T, kvStore *collection.KeyValue, count int, tableName string) ([]int, []int, error) {
+	ctx := context.Background()
 	var keys []int
 	var values []int
 	for i := 0; i < count; i++ {
@@ -1210,7 +1215,7 @@ func addRandomNumbers(t *testing.T, kvStore *collection.KeyValue, count int, tab
 			}
 		}
 
-		err = kvStore.KVPut(tableName, strKey, []byte(strKey))
+		err = kvStore.KVPut(ctx, tableName, strKey, []byte(strKey))
 		if err != nil {
 			return nil, nil, err
 		}
@@ -0,0 +1,112 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collection
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+)
+
+const feedNotFound = "feed does not exist or was not updated yet"
+
+// References marks the manifests of all the kv tables and the values stored in them.
+// Unlike opening a table, any error aborts the walk, so that no live reference is missed.
+func (kv *KeyValue) References(ctx context.Context, encryptionPassword string, mark func(ref []byte)) error {
+	tables, err := kv.LoadKVTables(ctx, encryptionPassword)
+	if err != nil {
+		return err
+	}
+	for name := range tables {
+		err = indexReferences(ctx, kv.podName+defaultCollectionName+name, encryptionPassword, kv.fd, kv.user, kv.client, mark)
+		if err != nil {
+			return fmt.Errorf("kv table %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// References marks the manifests of all the indexes of the document dbs and the documents
+func (d *Document) References(ctx context.Context, encryptionPassword string, mark func(ref []byte)) error {
+	schemas, err := d.LoadDocumentDBSchemas(ctx, encryptionPassword)
+	if err != nil {
+		return err
+	}
+	for dbName, schema := range schemas {
+		indexes := map[string]bool{DefaultIndexFieldName: true}
+		for _, list := range [][]SIndex{schema.SimpleIndexes, schema.MapIndexes, schema.ListIndexes} {
+			for _, si := range list {
+				indexes[si.FieldName] = true
+			}
+		}
+		for indexName := range indexes {
+			err = indexReferences(ctx, d.podName+dbName+indexName, encryptionPassword, d.fd, d.user, d.client, mark)
+			if err != nil {
+				return fmt.Errorf("document db %s: %w", dbName, err)
+			}
+		}
+	}
+	return nil
+}
+
+// indexReferences walks the manifest tree of an index. Every value of a leaf entry is
+// marked, as leaves hold either the value itself or the reference of a stored blob.
+func indexReferences(ctx context.Context, manifestName, encryptionPassword string, fd *feed.API, user utils.Address, client blockstore.Client, mark func(ref []byte)) error {
+	topic := utils.HashString(manifestName)
+	_, ref, err := fd.GetFeedData(ctx, topic, user, []byte(encryptionPassword))
+	if err != nil {
+		if err.Error() == feedNotFound {
+			return nil
+		}
+		return err
+	}
+	if string(ref) == utils.DeletedFeedMagicWord {
+		return nil
+	}
+	mark(ref)
+
+	data, respCode, err := client.DownloadBlob(ctx, ref)
+	if err != nil {
+		return err
+	}
+	if respCode != http.StatusOK { // skipcq: TCV-001
+		return ErrNoManifestFound
+	}
+	var manifest Manifest
+	err = json.Unmarshal(data, &manifest)
+	if err != nil { // skipcq: TCV-001
+		return ErrManifestUnmarshall
+	}
+	for _, entry := range manifest.Entries {
+		if entry.EType == IntermediateEntry {
+			err = indexReferences(ctx, manifest.Name+entry.Name, encryptionPassword, fd, user, client, mark)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		for _, r := range entry.Ref {
+			mark(r)
+		}
+	}
+	return nil
+}
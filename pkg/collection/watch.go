@@ -0,0 +1,94 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collection
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+)
+
+// WatchTopics returns the feed topics of the manifests of a kv table. Every change of
+// the table updates one of them.
+func (kv *KeyValue) WatchTopics(ctx context.Context, name, encryptionPassword string) ([][]byte, error) {
+	tables, err := kv.LoadKVTables(ctx, encryptionPassword)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	if _, ok := tables[name]; !ok {
+		return nil, ErrKVTableNotPresent
+	}
+	return manifestTopics(ctx, kv.podName+defaultCollectionName+name, encryptionPassword, kv.fd, kv.user, kv.client)
+}
+
+// WatchTopics returns the feed topics of the manifests of the default index of a
+// document db, which every added or removed document updates.
+func (d *Document) WatchTopics(ctx context.Context, dbName, encryptionPassword string) ([][]byte, error) {
+	schemas, err := d.LoadDocumentDBSchemas(ctx, encryptionPassword)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	if _, ok := schemas[dbName]; !ok {
+		return nil, ErrDocumentDBNotPresent
+	}
+	return manifestTopics(ctx, d.podName+dbName+DefaultIndexFieldName, encryptionPassword, d.fd, d.user, d.client)
+}
+
+// manifestTopics walks the manifest tree of an index and returns the topic of every
+// manifest
+func manifestTopics(ctx context.Context, manifestName, encryptionPassword string, fd *feed.API, user utils.Address, client blockstore.Client) ([][]byte, error) {
+	topic := utils.HashString(manifestName)
+	topics := [][]byte{topic}
+	_, ref, err := fd.GetFeedData(ctx, topic, user, []byte(encryptionPassword))
+	if err != nil {
+		if err.Error() == feedNotFound {
+			return topics, nil
+		}
+		return nil, err
+	}
+	if string(ref) == utils.DeletedFeedMagicWord {
+		return topics, nil
+	}
+
+	data, respCode, err := client.DownloadBlob(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if respCode != http.StatusOK { // skipcq: TCV-001
+		return nil, ErrNoManifestFound
+	}
+	var manifest Manifest
+	err = json.Unmarshal(data, &manifest)
+	if err != nil { // skipcq: TCV-001
+		return nil, ErrManifestUnmarshall
+	}
+	for _, entry := range manifest.Entries {
+		if entry.EType != IntermediateEntry {
+			continue
+		}
+		children, err := manifestTopics(ctx, manifest.Name+entry.Name, encryptionPassword, fd, user, client)
+		if err != nil {
+			return nil, err
+		}
+		topics = append(topics, children...)
+	}
+	return topics, nil
+}
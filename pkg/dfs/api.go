@@ -47,6 +47,13 @@ type API struct {
 
 // NewDfsAPI is the main entry point for the df controller.
 func NewDfsAPI(apiUrl, postageBlockId string, ensConfig *contracts.Config, logger logging.Logger) (*API, error) {
+	c := bee.NewBeeClient(apiUrl, postageBlockId, logger)
+	return NewDfsAPIWithClient(c, ensConfig, logger)
+}
+
+// NewDfsAPIWithClient creates the df controller over an already configured blockstore client.
+// This is used to run the dfs on a blockstore other than a single bee node.
+func NewDfsAPIWithClient(c blockstore.Client, ensConfig *contracts.Config, logger logging.Logger) (*API, error) {
 	ens, err := ethClient.New(ensConfig, logger)
 	if err != nil {
 		if errors.Is(err, ethClient.ErrWrongChainID) {
@@ -54,7 +61,6 @@ func NewDfsAPI(apiUrl, postageBlockId string, ensConfig *contracts.Config, logge
 		}
 		return nil, errEthClient
 	}
-	c := bee.NewBeeClient(apiUrl, postageBlockId, logger)
 	if !c.CheckConnection() {
 		return nil, ErrBeeClient
 	}
@@ -81,6 +87,22 @@ func NewMockDfsAPI(client blockstore.Client, users *user.Users, logger logging.L
 	}
 }
 
+// BlockstoreStatus returns the status of the blockstore client
+func (a *API) BlockstoreStatus() blockstore.Status {
+	if r, ok := a.client.(blockstore.StatusReporter); ok {
+		return r.Status()
+	}
+	return blockstore.Status{Connected: a.client.CheckConnection()}
+}
+
+// PostageBatches returns the state of the postage batches that pay for the uploads
+func (a *API) PostageBatches(ctx context.Context) ([]blockstore.PostageBatch, error) {
+	if r, ok := a.client.(blockstore.PostageReporter); ok {
+		return r.PostageBatches(ctx)
+	}
+	return nil, blockstore.ErrPostageUnsupported
+}
+
 // Close stops the taskmanager
 func (a *API) Close() error {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
@@ -0,0 +1,71 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dfs
+
+import (
+	"context"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/pod"
+)
+
+// CopyFile is a controller function which validates if the user is logged-in,
+// both pods are open and copies a file to the destination pod, which is the source
+// pod when dstPodName is empty. The blocks of the file are not uploaded again.
+func (a *API) CopyFile(ctx context.Context, podName, podFileWithPath, dstPodName, dstFileWithPath, sessionId string) error {
+	source, dst, err := a.copyPodInfos(podName, dstPodName, sessionId)
+	if err != nil {
+		return err
+	}
+	podFileWithPath, err = source.GetDirectory().ResolvePath(podFileWithPath)
+	if err != nil {
+		return err
+	}
+	return pod.CopyFile(ctx, source, dst, podFileWithPath, dstFileWithPath)
+}
+
+// CopyDirectory is a controller function which validates if the user is logged-in,
+// both pods are open and copies a directory tree to the destination pod, which is the
+// source pod when dstPodName is empty
+func (a *API) CopyDirectory(ctx context.Context, podName, directoryNameWithPath, dstPodName, dstDirectoryNameWithPath, sessionId string) error {
+	source, dst, err := a.copyPodInfos(podName, dstPodName, sessionId)
+	if err != nil {
+		return err
+	}
+	directoryNameWithPath, err = source.GetDirectory().ResolvePath(directoryNameWithPath)
+	if err != nil {
+		return err
+	}
+	return pod.CopyDirectory(ctx, source, dst, directoryNameWithPath, dstDirectoryNameWithPath)
+}
+
+func (a *API) copyPodInfos(podName, dstPodName, sessionId string) (*pod.Info, *pod.Info, error) {
+	source, err := a.openPodInfo(podName, sessionId)
+	if err != nil {
+		return nil, nil, err
+	}
+	dst := source
+	if dstPodName != "" && dstPodName != podName {
+		dst, err = a.openPodInfo(dstPodName, sessionId)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if dst.GetAccountInfo().IsReadOnlyPod() {
+		return nil, nil, errReadOnlyPod
+	}
+	return source, dst, nil
+}
@@ -16,10 +16,11 @@ limitations under the License.
 
 package dfs
 
+import "context"
 import "github.com/fairdatasociety/fairOS-dfs/pkg/collection"
 
 // DocCreate is a controller function which does all the checks before creating a documentDB.
-func (a *API) DocCreate(sessionId, podName, name string, indexes map[string]collection.IndexType, mutable bool) error {
+func (a *API) DocCreate(ctx context.Context, sessionId, podName, name string, indexes map[string]collection.IndexType, mutable bool) error {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -36,11 +37,11 @@ func (a *API) DocCreate(sessionId, podName, name string, indexes map[string]coll
 		return err
 	}
 
-	return podInfo.GetDocStore().CreateDocumentDB(name, podInfo.GetPodPassword(), indexes, mutable)
+	return podInfo.GetDocStore().CreateDocumentDB(ctx, name, podInfo.GetPodPassword(), indexes, mutable)
 }
 
 // DocOpen is a controller function which does all the checks before opening a documentDB.
-func (a *API) DocOpen(sessionId, podName, name string) error {
+func (a *API) DocOpen(ctx context.Context, sessionId, podName, name string) error {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -57,11 +58,11 @@ func (a *API) DocOpen(sessionId, podName, name string) error {
 		return err
 	}
 
-	return podInfo.GetDocStore().OpenDocumentDB(name, podInfo.GetPodPassword())
+	return podInfo.GetDocStore().OpenDocumentDB(ctx, name, podInfo.GetPodPassword())
 }
 
 // DocDelete is a controller function which does all the checks before deleting a documentDB.
-func (a *API) DocDelete(sessionId, podName, name string) error {
+func (a *API) DocDelete(ctx context.Context, sessionId, podName, name string) error {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -78,12 +79,12 @@ func (a *API) DocDelete(sessionId, podName, name string) error {
 		return err
 	}
 
-	return podInfo.GetDocStore().DeleteDocumentDB(name, podInfo.GetPodPassword())
+	return podInfo.GetDocStore().DeleteDocumentDB(ctx, name, podInfo.GetPodPassword())
 }
 
 // DocList is a controller function which does all the checks before listing all the
 // documentDB available in the pod.
-func (a *API) DocList(sessionId, podName string) (map[string]collection.DBSchema, error) {
+func (a *API) DocList(ctx context.Context, sessionId, podName string) (map[string]collection.DBSchema, error) {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -100,12 +101,12 @@ func (a *API) DocList(sessionId, podName string) (map[string]collection.DBSchema
 		return nil, err
 	}
 
-	return podInfo.GetDocStore().LoadDocumentDBSchemas(podInfo.GetPodPassword())
+	return podInfo.GetDocStore().LoadDocumentDBSchemas(ctx, podInfo.GetPodPassword())
 }
 
 // DocCount is a controller function which does all the checks before counting
 // all the documents ina documentDB.
-func (a *API) DocCount(sessionId, podName, name, expr string) (*collection.TableKeyCount, error) {
+func (a *API) DocCount(ctx context.Context, sessionId, podName, name, expr string) (*collection.TableKeyCount, error) {
 	keyCount := &collection.TableKeyCount{TableName: name}
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
@@ -123,7 +124,7 @@ func (a *API) DocCount(sessionId, podName, name, expr string) (*collection.Table
 		return keyCount, err
 	}
 
-	count, err := podInfo.GetDocStore().Count(name, expr)
+	count, err := podInfo.GetDocStore().Count(ctx, name, expr)
 	if err != nil {
 		return keyCount, err
 	}
@@ -133,7 +134,7 @@ func (a *API) DocCount(sessionId, podName, name, expr string) (*collection.Table
 
 // DocPut is a controller function which does all the checks before inserting
 // a document in the documentDB.
-func (a *API) DocPut(sessionId, podName, name string, value []byte) error {
+func (a *API) DocPut(ctx context.Context, sessionId, podName, name string, value []byte) error {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -150,12 +151,12 @@ func (a *API) DocPut(sessionId, podName, name string, value []byte) error {
 		return err
 	}
 
-	return podInfo.GetDocStore().Put(name, value)
+	return podInfo.GetDocStore().Put(ctx, name, value)
 }
 
 // DocGet is a controller function which does all the checks before retrieving
 // // a document in the documentDB.
-func (a *API) DocGet(sessionId, podName, name, id string) ([]byte, error) {
+func (a *API) DocGet(ctx context.Context, sessionId, podName, name, id string) ([]byte, error) {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -172,12 +173,12 @@ func (a *API) DocGet(sessionId, podName, name, id string) ([]byte, error) {
 		return nil, err
 	}
 
-	return podInfo.GetDocStore().Get(name, id, podInfo.GetPodPassword())
+	return podInfo.GetDocStore().Get(ctx, name, id, podInfo.GetPodPassword())
 }
 
 // DocDel is a controller function which does all the checks before deleting
 // a documentDB.
-func (a *API) DocDel(sessionId, podName, name, id string) error {
+func (a *API) DocDel(ctx context.Context, sessionId, podName, name, id string) error {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -194,12 +195,12 @@ func (a *API) DocDel(sessionId, podName, name, id string) error {
 		return err
 	}
 
-	return podInfo.GetDocStore().Del(name, id)
+	return podInfo.GetDocStore().Del(ctx, name, id)
 }
 
 // DocFind is a controller function which does all the checks before finding
 // records from a documentDB.
-func (a *API) DocFind(sessionId, podName, name, expr string, limit int) ([][]byte, error) {
+func (a *API) DocFind(ctx context.Context, sessionId, podName, name, expr string, limit int) ([][]byte, error) {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -216,11 +217,11 @@ func (a *API) DocFind(sessionId, podName, name, expr string, limit int) ([][]byt
 		return nil, err
 	}
 
-	return podInfo.GetDocStore().Find(name, expr, podInfo.GetPodPassword(), limit)
+	return podInfo.GetDocStore().Find(ctx, name, expr, podInfo.GetPodPassword(), limit)
 }
 
 // DocBatch initiates a batch inserting session.
-func (a *API) DocBatch(sessionId, podName, name string) (*collection.DocBatch, error) {
+func (a *API) DocBatch(ctx context.Context, sessionId, podName, name string) (*collection.DocBatch, error) {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -237,11 +238,11 @@ func (a *API) DocBatch(sessionId, podName, name string) (*collection.DocBatch, e
 		return nil, err
 	}
 
-	return podInfo.GetDocStore().CreateDocBatch(name, podInfo.GetPodPassword())
+	return podInfo.GetDocStore().CreateDocBatch(ctx, name, podInfo.GetPodPassword())
 }
 
 // DocBatchPut inserts records in to a document batch.
-func (a *API) DocBatchPut(sessionId, podName string, doc []byte, docBatch *collection.DocBatch) error {
+func (a *API) DocBatchPut(ctx context.Context, sessionId, podName string, doc []byte, docBatch *collection.DocBatch) error {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -258,11 +259,11 @@ func (a *API) DocBatchPut(sessionId, podName string, doc []byte, docBatch *colle
 		return err
 	}
 
-	return podInfo.GetDocStore().DocBatchPut(docBatch, doc, 0)
+	return podInfo.GetDocStore().DocBatchPut(ctx, docBatch, doc, 0)
 }
 
 // DocBatchWrite commits the batch document insert.
-func (a *API) DocBatchWrite(sessionId, podName string, docBatch *collection.DocBatch) error {
+func (a *API) DocBatchWrite(ctx context.Context, sessionId, podName string, docBatch *collection.DocBatch) error {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -279,11 +280,11 @@ func (a *API) DocBatchWrite(sessionId, podName string, docBatch *collection.DocB
 		return err
 	}
 
-	return podInfo.GetDocStore().DocBatchWrite(docBatch, "")
+	return podInfo.GetDocStore().DocBatchWrite(ctx, docBatch, "")
 }
 
 // DocIndexJson indexes a json files in to the document DB.
-func (a *API) DocIndexJson(sessionId, podName, name, podFileWithPath string) error {
+func (a *API) DocIndexJson(ctx context.Context, sessionId, podName, name, podFileWithPath string) error {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -305,5 +306,5 @@ func (a *API) DocIndexJson(sessionId, podName, name, podFileWithPath string) err
 		return ErrFileNotPresent
 	}
 
-	return podInfo.GetDocStore().DocFileIndex(name, podFileWithPath, podInfo.GetPodPassword())
+	return podInfo.GetDocStore().DocFileIndex(ctx, name, podFileWithPath, podInfo.GetPodPassword())
 }
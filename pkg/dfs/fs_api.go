@@ -17,6 +17,7 @@ limitations under the License.
 package dfs
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"path/filepath"
@@ -30,7 +31,7 @@ import (
 
 // Mkdir is a controller function which validates if the user is logged-in,
 // pod is open and calls the make directory function in the dir object.
-func (a *API) Mkdir(podName, dirToCreateWithPath, sessionId string) error {
+func (a *API) Mkdir(ctx context.Context, podName, dirToCreateWithPath, sessionId string) error {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -48,12 +49,12 @@ func (a *API) Mkdir(podName, dirToCreateWithPath, sessionId string) error {
 		return err
 	}
 	directory := podInfo.GetDirectory()
-	return directory.MkDir(dirToCreateWithPath, podPassword)
+	return directory.MkDir(ctx, dirToCreateWithPath, podPassword)
 }
 
 // RenameDir is a controller function which validates if the user is logged-in,
 // pod is open and calls the rename directory function in the dir object.
-func (a *API) RenameDir(podName, dirToRenameWithPath, newName, sessionId string) error {
+func (a *API) RenameDir(ctx context.Context, podName, dirToRenameWithPath, newName, sessionId string) error {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -71,12 +72,12 @@ func (a *API) RenameDir(podName, dirToRenameWithPath, newName, sessionId string)
 		return err
 	}
 	directory := podInfo.GetDirectory()
-	return directory.RenameDir(dirToRenameWithPath, newName, podInfo.GetPodPassword())
+	return directory.RenameDir(ctx, dirToRenameWithPath, newName, podInfo.GetPodPassword())
 }
 
 // IsDirPresent is a controller function which validates if the user is logged-in,
 // pod is open and calls the dir object to check if the directory is present.
-func (a *API) IsDirPresent(podName, directoryNameWithPath, sessionId string) (bool, error) {
+func (a *API) IsDirPresent(ctx context.Context, podName, directoryNameWithPath, sessionId string) (bool, error) {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -94,14 +95,17 @@ func (a *API) IsDirPresent(podName, directoryNameWithPath, sessionId string) (bo
 		return false, err
 	}
 	directory := podInfo.GetDirectory()
-	directoryNameWithPath = filepath.ToSlash(directoryNameWithPath)
-	dirPresent := directory.IsDirectoryPresent(directoryNameWithPath, podPassword)
+	directoryNameWithPath, err = directory.ResolvePath(directoryNameWithPath)
+	if err != nil {
+		return false, err
+	}
+	dirPresent := directory.IsDirectoryPresent(ctx, directoryNameWithPath, podPassword)
 	return dirPresent, nil
 }
 
 // RmDir is a controller function which validates if the user is logged-in,
 // pod is open and calls the dir object to remove the supplied directory.
-func (a *API) RmDir(podName, directoryNameWithPath, sessionId string) error {
+func (a *API) RmDir(ctx context.Context, podName, directoryNameWithPath, sessionId string) error {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -119,12 +123,12 @@ func (a *API) RmDir(podName, directoryNameWithPath, sessionId string) error {
 		return err
 	}
 	directory := podInfo.GetDirectory()
-	return directory.RmDir(directoryNameWithPath, podInfo.GetPodPassword())
+	return directory.RmDir(ctx, directoryNameWithPath, podInfo.GetPodPassword())
 }
 
 // ListDir is a controller function which validates if the user is logged-in,
 // pod is open and calls the dir object to list the contents of the supplied directory.
-func (a *API) ListDir(podName, currentDir, sessionId string) ([]dir.Entry, []f.Entry, error) {
+func (a *API) ListDir(ctx context.Context, podName, currentDir, sessionId string) ([]dir.Entry, []f.Entry, error) {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -142,18 +146,22 @@ func (a *API) ListDir(podName, currentDir, sessionId string) ([]dir.Entry, []f.E
 		return nil, nil, err
 	}
 	directory := podInfo.GetDirectory()
+	currentDir, err = directory.ResolvePath(currentDir)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	// check if directory present
 	totalPath := utils.CombinePathAndFile(currentDir, "")
 	if directory.GetDirFromDirectoryMap(totalPath) == nil {
 		return nil, nil, dir.ErrDirectoryNotPresent
 	}
-	dEntries, fileList, err := directory.ListDir(currentDir, podInfo.GetPodPassword())
+	dEntries, fileList, err := directory.ListDir(ctx, currentDir, podInfo.GetPodPassword())
 	if err != nil {
 		return nil, nil, err
 	}
 	file := podInfo.GetFile()
-	fEntries, err := file.ListFiles(fileList, podInfo.GetPodPassword())
+	fEntries, err := file.ListFiles(ctx, fileList, podInfo.GetPodPassword())
 	if err != nil {
 		return nil, nil, err
 	}
@@ -162,7 +170,7 @@ func (a *API) ListDir(podName, currentDir, sessionId string) ([]dir.Entry, []f.E
 
 // DirectoryStat is a controller function which validates if the user is logged-in,
 // pod is open and calls the dir object to get the information about the given directory.
-func (a *API) DirectoryStat(podName, directoryName, sessionId string) (*dir.Stats, error) {
+func (a *API) DirectoryStat(ctx context.Context, podName, directoryName, sessionId string) (*dir.Stats, error) {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -180,13 +188,38 @@ func (a *API) DirectoryStat(podName, directoryName, sessionId string) (*dir.Stat
 		return nil, err
 	}
 	directory := podInfo.GetDirectory()
-	ds, err := directory.DirStat(podName, podInfo.GetPodPassword(), directoryName)
+	directoryName, err = directory.ResolvePath(directoryName)
+	if err != nil {
+		return nil, err
+	}
+	ds, err := directory.DirStat(ctx, podName, podInfo.GetPodPassword(), directoryName)
 	if err != nil {
 		return nil, err
 	}
 	return ds, nil
 }
 
+// DirectoryHistory is a controller function which validates if the user is logged-in,
+// pod is open and lists all the revisions of the inode of the given directory.
+func (a *API) DirectoryHistory(ctx context.Context, podName, directoryName, sessionId string) ([]dir.InodeRevision, error) {
+	// get the logged-in user information
+	ui := a.users.GetLoggedInUserInfo(sessionId)
+	if ui == nil {
+		return nil, ErrUserNotLoggedIn
+	}
+
+	// check if pod open
+	if !ui.IsPodOpen(podName) {
+		return nil, ErrPodNotOpen
+	}
+
+	podInfo, _, err := ui.GetPod().GetPodInfoFromPodMap(podName)
+	if err != nil {
+		return nil, err
+	}
+	return podInfo.GetDirectory().GetInodeHistory(ctx, directoryName, podInfo.GetPodPassword())
+}
+
 // DirectoryInode is a controller function which validates if the user is logged-in,
 // pod is open and calls the dir object to get the inode info about the given directory.
 func (a *API) DirectoryInode(podName, directoryName, sessionId string) (*dir.Inode, error) {
@@ -217,7 +250,7 @@ func (a *API) DirectoryInode(podName, directoryName, sessionId string) (*dir.Ino
 
 // ChmodDir is a controller function which validates if the user is logged-in,
 // pod is open and calls changes mode of a directory
-func (a *API) ChmodDir(podName, directoryNameWithPath, sessionId string, mode uint32) error {
+func (a *API) ChmodDir(ctx context.Context, podName, directoryNameWithPath, sessionId string, mode uint32) error {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -242,13 +275,13 @@ func (a *API) ChmodDir(podName, directoryNameWithPath, sessionId string, mode ui
 
 	// get status of the file
 	directory := podInfo.GetDirectory()
-	return directory.Chmod(directoryNameWithPath, podInfo.GetPodPassword(), mode)
+	return directory.Chmod(ctx, directoryNameWithPath, podInfo.GetPodPassword(), mode)
 }
 
 // DeleteFile is a controller function which validates if the user is logged-in,
 // pod is open and delete the file. It also removes the file entry from the parent
 // directory.
-func (a *API) DeleteFile(podName, podFileWithPath, sessionId string) error {
+func (a *API) DeleteFile(ctx context.Context, podName, podFileWithPath, sessionId string) error {
 	// get the loggedin user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -272,7 +305,7 @@ func (a *API) DeleteFile(podName, podFileWithPath, sessionId string) error {
 	directory := podInfo.GetDirectory()
 
 	file := podInfo.GetFile()
-	err = file.RmFile(podFileWithPath, podInfo.GetPodPassword())
+	err = file.RmFile(ctx, podFileWithPath, podInfo.GetPodPassword())
 	if err != nil {
 		if err == f.ErrDeletedFeed {
 			return pod.ErrInvalidFile
@@ -283,12 +316,12 @@ func (a *API) DeleteFile(podName, podFileWithPath, sessionId string) error {
 	// update the directory by removing the file from it
 	fileDir := filepath.Dir(podFileWithPath)
 	fileName := filepath.Base(podFileWithPath)
-	return directory.RemoveEntryFromDir(fileDir, podInfo.GetPodPassword(), fileName, true)
+	return directory.RemoveEntryFromDir(ctx, fileDir, podInfo.GetPodPassword(), fileName, true)
 }
 
 // FileStat is a controller function which validates if the user is logged-in,
 // pod is open and gets the information about the file.
-func (a *API) FileStat(podName, podFileWithPath, sessionId string) (*f.Stats, error) {
+func (a *API) FileStat(ctx context.Context, podName, podFileWithPath, sessionId string) (*f.Stats, error) {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -304,18 +337,43 @@ func (a *API) FileStat(podName, podFileWithPath, sessionId string) (*f.Stats, er
 	if err != nil {
 		return nil, err
 	}
+	podFileWithPath, err = podInfo.GetDirectory().ResolvePath(podFileWithPath)
+	if err != nil {
+		return nil, err
+	}
 	file := podInfo.GetFile()
-	ds, err := file.GetStats(podName, podFileWithPath, podInfo.GetPodPassword())
+	ds, err := file.GetStats(ctx, podName, podFileWithPath, podInfo.GetPodPassword())
 	if err != nil {
 		return nil, err
 	}
 	return ds, nil
 }
 
+// FileHistory is a controller function which validates if the user is logged-in,
+// pod is open and lists all the revisions of the metadata of the given file.
+func (a *API) FileHistory(ctx context.Context, podName, podFileWithPath, sessionId string) ([]f.MetaRevision, error) {
+	// get the logged-in user information
+	ui := a.users.GetLoggedInUserInfo(sessionId)
+	if ui == nil {
+		return nil, ErrUserNotLoggedIn
+	}
+
+	// check if pod open
+	if !ui.IsPodOpen(podName) {
+		return nil, ErrPodNotOpen
+	}
+
+	podInfo, _, err := ui.GetPod().GetPodInfoFromPodMap(podName)
+	if err != nil {
+		return nil, err
+	}
+	return podInfo.GetFile().GetMetaHistory(ctx, podFileWithPath, podInfo.GetPodPassword())
+}
+
 // UploadFile is a controller function which validates if the user is logged-in,
 //
 //	pod is open and calls the upload function.
-func (a *API) UploadFile(podName, podFileName, sessionId string, fileSize int64, fd io.Reader, podPath, compression string, blockSize uint32, overwrite bool) error {
+func (a *API) UploadFile(ctx context.Context, podName, podFileName, sessionId string, fileSize int64, fd io.Reader, podPath, compression string, blockSize uint32, overwrite bool) error {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -335,40 +393,53 @@ func (a *API) UploadFile(podName, podFileName, sessionId string, fileSize int64,
 	directory := podInfo.GetDirectory()
 	podPath = filepath.ToSlash(podPath)
 
-	// check if file exists, then backup the file
-	totalPath := utils.CombinePathAndFile(podPath, podFileName)
-	alreadyPresent := file.IsFileAlreadyPresent(totalPath)
-
-	if alreadyPresent {
-		if !overwrite {
-			m, err := file.BackupFromFileName(totalPath, podInfo.GetPodPassword())
-			if err != nil {
-				return err
-			}
-			err = directory.AddEntryToDir(podPath, podInfo.GetPodPassword(), m.Name, true)
-			if err != nil {
-				return err
-			}
-		}
-		err = directory.RemoveEntryFromDir(podPath, podInfo.GetPodPassword(), podFileName, true)
-		if err != nil {
-			return err
-		}
+	err = replaceFile(ctx, podInfo, podPath, podFileName, overwrite)
+	if err != nil {
+		return err
 	}
 
-	err = file.Upload(fd, podFileName, fileSize, blockSize, podPath, compression, podInfo.GetPodPassword())
+	err = file.Upload(ctx, fd, podFileName, fileSize, blockSize, podPath, compression, podInfo.GetPodPassword())
 	if err != nil {
 		return err
 	}
 
 	// add the file to the directory metadata
-	return directory.AddEntryToDir(podPath, podInfo.GetPodPassword(), podFileName, true)
+	return directory.AddEntryToDir(ctx, podPath, podInfo.GetPodPassword(), podFileName, true)
+}
+
+// replaceFile makes way for a new file of the given name. An existing file is backed up,
+// or just removed from the directory when it is overwritten. A symbolic link of the name
+// is not replaced.
+func replaceFile(ctx context.Context, podInfo *pod.Info, podPath, podFileName string, overwrite bool) error {
+	file := podInfo.GetFile()
+	directory := podInfo.GetDirectory()
+	totalPath := utils.CombinePathAndFile(podPath, podFileName)
+	_, err := directory.LinkStat(podInfo.GetPodName(), totalPath)
+	if err == nil {
+		return dir.ErrLinkAlreadyPresent
+	}
+
+	// check if file exists, then backup the file
+	if !file.IsFileAlreadyPresent(totalPath) {
+		return nil
+	}
+	if !overwrite {
+		m, err := file.BackupFromFileName(ctx, totalPath, podInfo.GetPodPassword())
+		if err != nil {
+			return err
+		}
+		err = directory.AddEntryToDir(ctx, podPath, podInfo.GetPodPassword(), m.Name, true)
+		if err != nil {
+			return err
+		}
+	}
+	return directory.RemoveEntryFromDir(ctx, podPath, podInfo.GetPodPassword(), podFileName, true)
 }
 
 // RenameFile is a controller function which validates if the user is logged-in,
 //
 //	pod is open and calls renaming of a file
-func (a *API) RenameFile(podName, fileNameWithPath, newFileNameWithPath, sessionId string) error {
+func (a *API) RenameFile(ctx context.Context, podName, fileNameWithPath, newFileNameWithPath, sessionId string) error {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -398,7 +469,7 @@ func (a *API) RenameFile(podName, fileNameWithPath, newFileNameWithPath, session
 		return ErrFileAlreadyPresent
 	}
 
-	m, err := file.RenameFromFileName(fileNameWithPath, newFileNameWithPath, podInfo.GetPodPassword())
+	m, err := file.RenameFromFileName(ctx, fileNameWithPath, newFileNameWithPath, podInfo.GetPodPassword())
 	if err != nil {
 		return err
 	}
@@ -406,17 +477,17 @@ func (a *API) RenameFile(podName, fileNameWithPath, newFileNameWithPath, session
 	newPrnt := filepath.ToSlash(filepath.Dir(newFileNameWithPath))
 
 	// add the file to the directory metadata
-	err = directory.AddEntryToDir(newPrnt, podInfo.GetPodPassword(), m.Name, true)
+	err = directory.AddEntryToDir(ctx, newPrnt, podInfo.GetPodPassword(), m.Name, true)
 	if err != nil {
 		return err
 	}
 
-	return directory.RemoveEntryFromDir(oldPrnt, podInfo.GetPodPassword(), filepath.Base(fileNameWithPath), true)
+	return directory.RemoveEntryFromDir(ctx, oldPrnt, podInfo.GetPodPassword(), filepath.Base(fileNameWithPath), true)
 }
 
 // DownloadFile is a controller function which validates if the user is logged-in,
 // pod is open and calls the download function.
-func (a *API) DownloadFile(podName, podFileWithPath, sessionId string) (io.ReadCloser, uint64, error) {
+func (a *API) DownloadFile(ctx context.Context, podName, podFileWithPath, sessionId string) (io.ReadCloser, uint64, error) {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -439,9 +510,14 @@ func (a *API) DownloadFile(podName, podFileWithPath, sessionId string) (io.ReadC
 		return nil, 0, err
 	}
 
+	podFileWithPath, err = podInfo.GetDirectory().ResolvePath(podFileWithPath)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	// download the file by creating the reader
 	file := podInfo.GetFile()
-	reader, size, err := file.Download(podFileWithPath, podInfo.GetPodPassword())
+	reader, size, err := file.Download(ctx, podFileWithPath, podInfo.GetPodPassword())
 	if err != nil {
 		return nil, 0, err
 	}
@@ -451,7 +527,7 @@ func (a *API) DownloadFile(podName, podFileWithPath, sessionId string) (io.ReadC
 // WriteAtFile is a controller function which writes a file from a given offset
 //
 //	pod is open and calls writeAt of a file
-func (a *API) WriteAtFile(podName, fileNameWithPath, sessionId string, update io.Reader, offset uint64, truncate bool) (int, error) {
+func (a *API) WriteAtFile(ctx context.Context, podName, fileNameWithPath, sessionId string, update io.Reader, offset uint64, truncate bool) (int, error) {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -474,12 +550,12 @@ func (a *API) WriteAtFile(podName, fileNameWithPath, sessionId string, update io
 		return 0, ErrFileNotPresent
 	}
 
-	return file.WriteAt(fileNameWithPath, podInfo.GetPodPassword(), update, offset, truncate)
+	return file.WriteAt(ctx, fileNameWithPath, podInfo.GetPodPassword(), update, offset, truncate)
 }
 
 // ReadSeekCloser is a controller function which validates if the user is logged-in,
 // pod is open and calls the download function.
-func (a *API) ReadSeekCloser(podName, podFileWithPath, sessionId string) (io.ReadSeekCloser, uint64, error) {
+func (a *API) ReadSeekCloser(ctx context.Context, podName, podFileWithPath, sessionId string) (io.ReadSeekCloser, uint64, error) {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -502,18 +578,60 @@ func (a *API) ReadSeekCloser(podName, podFileWithPath, sessionId string) (io.Rea
 		return nil, 0, err
 	}
 
+	podFileWithPath, err = podInfo.GetDirectory().ResolvePath(podFileWithPath)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	// download the file by creating the reader
 	file := podInfo.GetFile()
-	reader, size, err := file.ReadSeeker(podFileWithPath, podInfo.GetPodPassword())
+	reader, size, err := file.ReadSeeker(ctx, podFileWithPath, podInfo.GetPodPassword())
 	if err != nil {
 		return nil, 0, err
 	}
 	return reader, size, nil
 }
 
+// OpenFile is a controller function which validates if the user is logged-in,
+// pod is open and opens a file for reading like ReadSeekCloser. It also returns the
+// metadata of the content that is read.
+func (a *API) OpenFile(ctx context.Context, podName, podFileWithPath, sessionId string) (io.ReadSeekCloser, *f.MetaData, error) {
+	// get the logged-in user information
+	ui := a.users.GetLoggedInUserInfo(sessionId)
+	if ui == nil {
+		return nil, nil, ErrUserNotLoggedIn
+	}
+
+	// check if pod open
+	if !ui.IsPodOpen(podName) {
+		return nil, nil, ErrPodNotOpen
+	}
+
+	// get podInfo and construct the path
+	podInfo, _, err := ui.GetPod().GetPodInfoFromPodMap(podName)
+	if err != nil {
+		return nil, nil, err
+	}
+	podFileWithPath, err = podInfo.GetDirectory().ResolvePath(podFileWithPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	return podInfo.GetFile().Open(ctx, podFileWithPath, podInfo.GetPodPassword())
+}
+
+// VerifyFile is a controller function which validates if the user is logged-in,
+// pod is open and checks the content of a file against its checksums
+func (a *API) VerifyFile(ctx context.Context, podName, podFileWithPath, sessionId string) (*f.Verification, error) {
+	podInfo, err := a.openPodInfo(podName, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	return podInfo.GetFile().Verify(ctx, podFileWithPath, podInfo.GetPodPassword())
+}
+
 // ShareFile is a controller function which validates if the user is logged-in,
 // pod is open and calls the shareFile function.
-func (a *API) ShareFile(podName, podFileWithPath, destinationUser, sessionId string) (string, error) {
+func (a *API) ShareFile(ctx context.Context, podName, podFileWithPath, destinationUser, sessionId string) (string, error) {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -531,7 +649,7 @@ func (a *API) ShareFile(podName, podFileWithPath, destinationUser, sessionId str
 		return "", err
 	}
 
-	sharingRef, err := a.users.ShareFileWithUser(podName, podInfo.GetPodPassword(), podFileWithPath, destinationUser, ui, ui.GetPod(), podInfo.GetAccountInfo().GetAddress())
+	sharingRef, err := a.users.ShareFileWithUser(ctx, podName, podInfo.GetPodPassword(), podFileWithPath, destinationUser, ui, ui.GetPod(), podInfo.GetAccountInfo().GetAddress())
 	if err != nil {
 		return "", err
 	}
@@ -541,7 +659,7 @@ func (a *API) ShareFile(podName, podFileWithPath, destinationUser, sessionId str
 // ReceiveFile is a controller function which validates if the user is logged-in,
 // pod is open and calls the ReceiveFile function to get the shared file in to the
 // given pod.
-func (a *API) ReceiveFile(podName, sessionId string, sharingRef utils.SharingReference, dir string) (string, error) {
+func (a *API) ReceiveFile(ctx context.Context, podName, sessionId string, sharingRef utils.SharingReference, dir string) (string, error) {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -553,24 +671,24 @@ func (a *API) ReceiveFile(podName, sessionId string, sharingRef utils.SharingRef
 		return "", ErrPodNotOpen
 	}
 
-	return a.users.ReceiveFileFromUser(podName, sharingRef, ui, ui.GetPod(), dir)
+	return a.users.ReceiveFileFromUser(ctx, podName, sharingRef, ui, ui.GetPod(), dir)
 }
 
 // ReceiveInfo is a controller function which validates if the user is logged-in,
 // calls the ReceiveInfo function to display the shared file's information.
-func (a *API) ReceiveInfo(sessionId string, sharingRef utils.SharingReference) (*user.ReceiveFileInfo, error) {
+func (a *API) ReceiveInfo(ctx context.Context, sessionId string, sharingRef utils.SharingReference) (*user.ReceiveFileInfo, error) {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
 		return nil, ErrUserNotLoggedIn
 	}
 
-	return a.users.ReceiveFileInfo(sharingRef)
+	return a.users.ReceiveFileInfo(ctx, sharingRef)
 }
 
 // StatusFile is a controller function which validates if the user is logged-in,
 // pod is open and calls the sync status of file function.
-func (a *API) StatusFile(podName, podFileWithPath, sessionId string) (int64, int64, int64, error) {
+func (a *API) StatusFile(ctx context.Context, podName, podFileWithPath, sessionId string) (int64, int64, int64, error) {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -595,12 +713,12 @@ func (a *API) StatusFile(podName, podFileWithPath, sessionId string) (int64, int
 
 	// get status of the file
 	file := podInfo.GetFile()
-	return file.Status(podFileWithPath, podInfo.GetPodPassword())
+	return file.Status(ctx, podFileWithPath, podInfo.GetPodPassword())
 }
 
 // ChmodFile is a controller function which validates if the user is logged-in,
 // pod is open and calls changes mode of a file
-func (a *API) ChmodFile(podName, podFileWithPath, sessionId string, mode uint32) error {
+func (a *API) ChmodFile(ctx context.Context, podName, podFileWithPath, sessionId string, mode uint32) error {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -625,5 +743,5 @@ func (a *API) ChmodFile(podName, podFileWithPath, sessionId string, mode uint32)
 
 	// get status of the file
 	file := podInfo.GetFile()
-	return file.Chmod(podFileWithPath, podInfo.GetPodPassword(), mode)
+	return file.Chmod(ctx, podFileWithPath, podInfo.GetPodPassword(), mode)
 }
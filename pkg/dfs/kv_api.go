@@ -17,11 +17,12 @@ limitations under the License.
 package dfs
 
 import (
+	"context"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/collection"
 )
 
 // KVCreate does validation checks and calls the create KVtable function.
-func (a *API) KVCreate(sessionId, podName, name string, indexType collection.IndexType) error {
+func (a *API) KVCreate(ctx context.Context, sessionId, podName, name string, indexType collection.IndexType) error {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -38,11 +39,11 @@ func (a *API) KVCreate(sessionId, podName, name string, indexType collection.Ind
 		return err
 	}
 
-	return podInfo.GetKVStore().CreateKVTable(name, podInfo.GetPodPassword(), indexType)
+	return podInfo.GetKVStore().CreateKVTable(ctx, name, podInfo.GetPodPassword(), indexType)
 }
 
 // KVDelete does validation checks and calls the delete KVtable function.
-func (a *API) KVDelete(sessionId, podName, name string) error {
+func (a *API) KVDelete(ctx context.Context, sessionId, podName, name string) error {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -59,11 +60,11 @@ func (a *API) KVDelete(sessionId, podName, name string) error {
 		return err
 	}
 
-	return podInfo.GetKVStore().DeleteKVTable(name, podInfo.GetPodPassword())
+	return podInfo.GetKVStore().DeleteKVTable(ctx, name, podInfo.GetPodPassword())
 }
 
 // KVOpen does validation checks and calls the open KVtable function.
-func (a *API) KVOpen(sessionId, podName, name string) error {
+func (a *API) KVOpen(ctx context.Context, sessionId, podName, name string) error {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -80,11 +81,11 @@ func (a *API) KVOpen(sessionId, podName, name string) error {
 		return err
 	}
 
-	return podInfo.GetKVStore().OpenKVTable(name, podInfo.GetPodPassword())
+	return podInfo.GetKVStore().OpenKVTable(ctx, name, podInfo.GetPodPassword())
 }
 
 // KVList does validation checks and calls the list KVtable function.
-func (a *API) KVList(sessionId, podName string) (map[string][]string, error) {
+func (a *API) KVList(ctx context.Context, sessionId, podName string) (map[string][]string, error) {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -100,11 +101,11 @@ func (a *API) KVList(sessionId, podName string) (map[string][]string, error) {
 		return nil, err
 	}
 
-	return podInfo.GetKVStore().LoadKVTables(podInfo.GetPodPassword())
+	return podInfo.GetKVStore().LoadKVTables(ctx, podInfo.GetPodPassword())
 }
 
 // KVCount does validation checks and calls the count KVtable function.
-func (a *API) KVCount(sessionId, podName, name string) (*collection.TableKeyCount, error) {
+func (a *API) KVCount(ctx context.Context, sessionId, podName, name string) (*collection.TableKeyCount, error) {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -121,11 +122,11 @@ func (a *API) KVCount(sessionId, podName, name string) (*collection.TableKeyCoun
 		return nil, err
 	}
 
-	return podInfo.GetKVStore().KVCount(name, podInfo.GetPodPassword())
+	return podInfo.GetKVStore().KVCount(ctx, name, podInfo.GetPodPassword())
 }
 
 // KVPut does validation checks and calls the put KVtable function.
-func (a *API) KVPut(sessionId, podName, name, key string, value []byte) error {
+func (a *API) KVPut(ctx context.Context, sessionId, podName, name, key string, value []byte) error {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -142,11 +143,11 @@ func (a *API) KVPut(sessionId, podName, name, key string, value []byte) error {
 		return err
 	}
 
-	return podInfo.GetKVStore().KVPut(name, key, value)
+	return podInfo.GetKVStore().KVPut(ctx, name, key, value)
 }
 
 // KVGet does validation checks and calls the get KVtable function.
-func (a *API) KVGet(sessionId, podName, name, key string) ([]string, []byte, error) {
+func (a *API) KVGet(ctx context.Context, sessionId, podName, name, key string) ([]string, []byte, error) {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -163,11 +164,11 @@ func (a *API) KVGet(sessionId, podName, name, key string) ([]string, []byte, err
 		return nil, nil, err
 	}
 
-	return podInfo.GetKVStore().KVGet(name, key)
+	return podInfo.GetKVStore().KVGet(ctx, name, key)
 }
 
 // KVDel does validation checks and calls the delete KVtable function.
-func (a *API) KVDel(sessionId, podName, name, key string) ([]byte, error) {
+func (a *API) KVDel(ctx context.Context, sessionId, podName, name, key string) ([]byte, error) {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -183,7 +184,7 @@ func (a *API) KVDel(sessionId, podName, name, key string) ([]byte, error) {
 		return nil, err
 	}
 
-	return podInfo.GetKVStore().KVDelete(name, key)
+	return podInfo.GetKVStore().KVDelete(ctx, name, key)
 }
 
 // KVBatch does validation checks and calls the batch KVtable function.
@@ -208,7 +209,7 @@ func (a *API) KVBatch(sessionId, podName, name string, columns []string) (*colle
 }
 
 // KVBatchPut does validation checks and calls the batch put KVtable function.
-func (a *API) KVBatchPut(sessionId, podName, key string, value []byte, batch *collection.Batch) error {
+func (a *API) KVBatchPut(ctx context.Context, sessionId, podName, key string, value []byte, batch *collection.Batch) error {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -220,11 +221,11 @@ func (a *API) KVBatchPut(sessionId, podName, key string, value []byte, batch *co
 		return ErrPodNotOpen
 	}
 
-	return batch.Put(key, value, false, false)
+	return batch.Put(ctx, key, value, false, false)
 }
 
 // KVBatchWrite does validation checks and calls the batch write KVtable function.
-func (a *API) KVBatchWrite(sessionId, podName string, batch *collection.Batch) error {
+func (a *API) KVBatchWrite(ctx context.Context, sessionId, podName string, batch *collection.Batch) error {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -236,12 +237,12 @@ func (a *API) KVBatchWrite(sessionId, podName string, batch *collection.Batch) e
 		return ErrPodNotOpen
 	}
 
-	_, err := batch.Write("")
+	_, err := batch.Write(ctx, "")
 	return err
 }
 
 // KVSeek does validation checks and calls the seek KVtable function.
-func (a *API) KVSeek(sessionId, podName, name, start, end string, limit int64) (*collection.Iterator, error) {
+func (a *API) KVSeek(ctx context.Context, sessionId, podName, name, start, end string, limit int64) (*collection.Iterator, error) {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -258,11 +259,11 @@ func (a *API) KVSeek(sessionId, podName, name, start, end string, limit int64) (
 		return nil, err
 	}
 
-	return podInfo.GetKVStore().KVSeek(name, start, end, limit)
+	return podInfo.GetKVStore().KVSeek(ctx, name, start, end, limit)
 }
 
 // KVGetNext does validation checks and calls the get next KVtable function.
-func (a *API) KVGetNext(sessionId, podName, name string) ([]string, string, []byte, error) {
+func (a *API) KVGetNext(ctx context.Context, sessionId, podName, name string) ([]string, string, []byte, error) {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -279,5 +280,5 @@ func (a *API) KVGetNext(sessionId, podName, name string) ([]string, string, []by
 		return nil, "", nil, err
 	}
 
-	return podInfo.GetKVStore().KVGetNext(name)
+	return podInfo.GetKVStore().KVGetNext(ctx, name)
 }
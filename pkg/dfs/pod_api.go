@@ -20,13 +20,20 @@ import (
 	"context"
 	"encoding/hex"
 
+	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/pod"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/user"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
 )
 
 // CreatePod
-func (a *API) CreatePod(podName, sessionId string) (*pod.Info, error) {
+func (a *API) CreatePod(ctx context.Context, podName, sessionId string) (*pod.Info, error) {
+	return a.CreatePodWithFeedType(ctx, podName, feed.EpochFeed, sessionId)
+}
+
+// CreatePodWithFeedType creates a pod that keeps its files and directories in feeds of
+// the given type
+func (a *API) CreatePodWithFeedType(ctx context.Context, podName string, feedType feed.Type, sessionId string) (*pod.Info, error) {
 	// get the loggedin user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -34,7 +41,7 @@ func (a *API) CreatePod(podName, sessionId string) (*pod.Info, error) {
 	}
 
 	// open the pod
-	pi, err := a.prepareOwnPod(ui, podName)
+	pi, err := a.prepareOwnPod(ctx, ui, podName, feedType)
 	if err != nil {
 		return nil, err
 	}
@@ -45,7 +52,7 @@ func (a *API) CreatePod(podName, sessionId string) (*pod.Info, error) {
 }
 
 // DeletePod deletes a pod
-func (a *API) DeletePod(podName, sessionId string) error {
+func (a *API) DeletePod(ctx context.Context, podName, sessionId string) error {
 	// get the loggedin user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -63,7 +70,7 @@ func (a *API) DeletePod(podName, sessionId string) error {
 	// check if this is a shared pod
 	if podInfo.GetFeed().IsReadOnlyFeed() {
 		// delete the pod and close if it is opened
-		err = ui.GetPod().DeleteSharedPod(podName)
+		err = ui.GetPod().DeleteSharedPod(ctx, podName)
 		if err != nil {
 			return err
 		}
@@ -76,13 +83,13 @@ func (a *API) DeletePod(podName, sessionId string) error {
 		return nil
 	}
 
-	err = directory.RmRootDir(podInfo.GetPodPassword())
+	err = directory.RmRootDir(ctx, podInfo.GetPodPassword())
 	if err != nil {
 		return err
 	}
 
 	// delete the pod and close if it is opened
-	err = ui.GetPod().DeleteOwnPod(podName)
+	err = ui.GetPod().DeleteOwnPod(ctx, podName)
 	if err != nil {
 		return err
 	}
@@ -97,7 +104,7 @@ func (a *API) DeletePod(podName, sessionId string) error {
 }
 
 // OpenPod
-func (a *API) OpenPod(podName, sessionId string) (*pod.Info, error) {
+func (a *API) OpenPod(ctx context.Context, podName, sessionId string) (*pod.Info, error) {
 	// get the loggedin user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -112,11 +119,11 @@ func (a *API) OpenPod(podName, sessionId string) (*pod.Info, error) {
 		return podInfo, nil
 	}
 	// open the pod
-	pi, err := ui.GetPod().OpenPod(podName)
+	pi, err := ui.GetPod().OpenPod(ctx, podName)
 	if err != nil {
 		return nil, err
 	}
-	err = pi.GetDirectory().AddRootDir(pi.GetPodName(), pi.GetPodPassword(), pi.GetPodAddress(), pi.GetFeed())
+	err = pi.GetDirectory().AddRootDir(ctx, pi.GetPodName(), pi.GetPodPassword(), pi.GetPodAddress(), pi.GetFeed())
 	if err != nil {
 		return nil, err
 	}
@@ -145,7 +152,7 @@ func (a *API) OpenPodAsync(ctx context.Context, podName, sessionId string) (*pod
 	if err != nil {
 		return nil, err
 	}
-	err = pi.GetDirectory().AddRootDir(pi.GetPodName(), pi.GetPodPassword(), pi.GetPodAddress(), pi.GetFeed())
+	err = pi.GetDirectory().AddRootDir(ctx, pi.GetPodName(), pi.GetPodPassword(), pi.GetPodAddress(), pi.GetFeed())
 	if err != nil {
 		return nil, err
 	}
@@ -155,7 +162,7 @@ func (a *API) OpenPodAsync(ctx context.Context, podName, sessionId string) (*pod
 }
 
 // ClosePod
-func (a *API) ClosePod(podName, sessionId string) error {
+func (a *API) ClosePod(ctx context.Context, podName, sessionId string) error {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -168,7 +175,7 @@ func (a *API) ClosePod(podName, sessionId string) error {
 	}
 
 	// close the pod
-	err := ui.GetPod().ClosePod(podName)
+	err := ui.GetPod().ClosePod(ctx, podName)
 	if err != nil {
 		return err
 	}
@@ -195,7 +202,7 @@ func (a *API) PodStat(podName, sessionId string) (*pod.Stat, error) {
 }
 
 // SyncPod
-func (a *API) SyncPod(podName, sessionId string) error {
+func (a *API) SyncPod(ctx context.Context, podName, sessionId string) error {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -208,7 +215,7 @@ func (a *API) SyncPod(podName, sessionId string) error {
 	}
 
 	// sync the pod
-	err := ui.GetPod().SyncPod(podName)
+	err := ui.GetPod().SyncPod(ctx, podName)
 	if err != nil {
 		return err
 	}
@@ -236,8 +243,24 @@ func (a *API) SyncPodAsync(ctx context.Context, podName, sessionId string) error
 	return nil
 }
 
+// PodGC unpins the content of the pod that is no longer referenced, or only reports it with dryRun
+func (a *API) PodGC(ctx context.Context, podName, sessionId string, dryRun bool) (*pod.GCReport, error) {
+	// get the logged-in user information
+	ui := a.users.GetLoggedInUserInfo(sessionId)
+	if ui == nil {
+		return nil, ErrUserNotLoggedIn
+	}
+
+	// check if pod open
+	if !ui.IsPodOpen(podName) {
+		return nil, ErrPodNotOpen
+	}
+
+	return ui.GetPod().CollectGarbage(ctx, podName, dryRun)
+}
+
 // ListPods
-func (a *API) ListPods(sessionId string) ([]string, []string, error) {
+func (a *API) ListPods(ctx context.Context, sessionId string) ([]string, []string, error) {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -245,7 +268,7 @@ func (a *API) ListPods(sessionId string) ([]string, []string, error) {
 	}
 
 	// list pods of a user
-	pods, sharedPods, err := ui.GetPod().ListPods()
+	pods, sharedPods, err := ui.GetPod().ListPods(ctx)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -253,7 +276,7 @@ func (a *API) ListPods(sessionId string) ([]string, []string, error) {
 }
 
 // PodList lists all available pods in json format
-func (a *API) PodList(sessionId string) (*pod.List, error) {
+func (a *API) PodList(ctx context.Context, sessionId string) (*pod.List, error) {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -261,11 +284,11 @@ func (a *API) PodList(sessionId string) (*pod.List, error) {
 	}
 
 	// list pods of a user
-	return ui.GetPod().PodList()
+	return ui.GetPod().PodList(ctx)
 }
 
 // PodShare
-func (a *API) PodShare(podName, sharedPodName, sessionId string) (string, error) {
+func (a *API) PodShare(ctx context.Context, podName, sharedPodName, sessionId string) (string, error) {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -273,7 +296,7 @@ func (a *API) PodShare(podName, sharedPodName, sessionId string) (string, error)
 	}
 
 	// get the pod stat
-	address, err := ui.GetPod().PodShare(podName, sharedPodName)
+	address, err := ui.GetPod().PodShare(ctx, podName, sharedPodName)
 	if err != nil {
 		return "", err
 	}
@@ -281,38 +304,38 @@ func (a *API) PodShare(podName, sharedPodName, sessionId string) (string, error)
 }
 
 // PodReceiveInfo
-func (a *API) PodReceiveInfo(sessionId string, ref utils.Reference) (*pod.ShareInfo, error) {
+func (a *API) PodReceiveInfo(ctx context.Context, sessionId string, ref utils.Reference) (*pod.ShareInfo, error) {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
 		return nil, ErrUserNotLoggedIn
 	}
 
-	return ui.GetPod().ReceivePodInfo(ref)
+	return ui.GetPod().ReceivePodInfo(ctx, ref)
 }
 
 // PodReceive
-func (a *API) PodReceive(sessionId, sharedPodName string, ref utils.Reference) (*pod.Info, error) {
+func (a *API) PodReceive(ctx context.Context, sessionId, sharedPodName string, ref utils.Reference) (*pod.Info, error) {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
 		return nil, ErrUserNotLoggedIn
 	}
 
-	return ui.GetPod().ReceivePod(sharedPodName, ref)
+	return ui.GetPod().ReceivePod(ctx, sharedPodName, ref)
 }
 
 // IsPodExist
-func (a *API) IsPodExist(podName, sessionId string) bool {
+func (a *API) IsPodExist(ctx context.Context, podName, sessionId string) bool {
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
 		return false
 	}
-	return ui.GetPod().IsPodPresent(podName)
+	return ui.GetPod().IsPodPresent(ctx, podName)
 }
 
 // ForkPod
-func (a *API) ForkPod(podName, forkName, sessionId string) error {
+func (a *API) ForkPod(ctx context.Context, podName, forkName, sessionId string) error {
 	// get the loggedin user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -327,20 +350,25 @@ func (a *API) ForkPod(podName, forkName, sessionId string) error {
 		return pod.ErrBlankPodName
 	}
 
-	if ui.GetPod().IsPodPresent(forkName) {
+	if ui.GetPod().IsPodPresent(ctx, forkName) {
 		return pod.ErrForkAlreadyExists
 	}
 
-	_, err := a.prepareOwnPod(ui, forkName)
+	// the fork keeps the feed type of the pod
+	podInfo, _, err := ui.GetPod().GetPodInfoFromPodMap(podName)
+	if err != nil { // skipcq: TCV-001
+		return err
+	}
+	_, err = a.prepareOwnPod(ctx, ui, forkName, podInfo.GetFeed().Type())
 	if err != nil {
 		return err
 	}
 
-	return ui.GetPod().PodFork(podName, forkName)
+	return ui.GetPod().PodFork(ctx, podName, forkName)
 }
 
 // ForkPodFromRef
-func (a *API) ForkPodFromRef(forkName, refString, sessionId string) error {
+func (a *API) ForkPodFromRef(ctx context.Context, forkName, refString, sessionId string) error {
 	// get the loggedin user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
@@ -354,36 +382,36 @@ func (a *API) ForkPodFromRef(forkName, refString, sessionId string) error {
 		return pod.ErrBlankPodName
 	}
 
-	if ui.GetPod().IsPodPresent(forkName) {
+	if ui.GetPod().IsPodPresent(ctx, forkName) {
 		return pod.ErrForkAlreadyExists
 	}
 
-	_, err := a.prepareOwnPod(ui, forkName)
+	_, err := a.prepareOwnPod(ctx, ui, forkName, feed.EpochFeed)
 	if err != nil {
 		return err
 	}
 
-	return ui.GetPod().PodForkFromRef(forkName, refString)
+	return ui.GetPod().PodForkFromRef(ctx, forkName, refString)
 }
 
-func (a *API) prepareOwnPod(ui *user.Info, podName string) (*pod.Info, error) {
+func (a *API) prepareOwnPod(ctx context.Context, ui *user.Info, podName string, feedType feed.Type) (*pod.Info, error) {
 	podPasswordBytes, _ := utils.GetRandBytes(pod.PasswordLength)
 	podPassword := hex.EncodeToString(podPasswordBytes)
 
 	// create the pod
-	_, err := ui.GetPod().CreatePod(podName, "", podPassword)
+	_, err := ui.GetPod().CreatePodWithFeedType(ctx, podName, "", podPassword, feedType)
 	if err != nil {
 		return nil, err
 	}
 
 	// open the pod
-	pi, err := ui.GetPod().OpenPod(podName)
+	pi, err := ui.GetPod().OpenPod(ctx, podName)
 	if err != nil {
 		return nil, err
 	}
 
 	// create the root directory
-	err = pi.GetDirectory().MkRootDir(pi.GetPodName(), podPassword, pi.GetPodAddress(), pi.GetFeed())
+	err = pi.GetDirectory().MkRootDir(ctx, pi.GetPodName(), podPassword, pi.GetPodAddress(), pi.GetFeed())
 	if err != nil {
 		return nil, err
 	}
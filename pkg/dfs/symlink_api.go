@@ -0,0 +1,59 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dfs
+
+import (
+	"context"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/dir"
+)
+
+// Symlink is a controller function which validates if the user is logged-in,
+// pod is open and creates a symbolic link to the target
+func (a *API) Symlink(ctx context.Context, podName, target, linkWithPath, sessionId string) error {
+	podInfo, err := a.openPodInfo(podName, sessionId)
+	if err != nil {
+		return err
+	}
+	if podInfo.GetAccountInfo().IsReadOnlyPod() {
+		return errReadOnlyPod
+	}
+	return podInfo.GetDirectory().Symlink(ctx, target, linkWithPath, podInfo.GetPodPassword())
+}
+
+// RemoveSymlink is a controller function which validates if the user is logged-in,
+// pod is open and removes a symbolic link
+func (a *API) RemoveSymlink(ctx context.Context, podName, linkWithPath, sessionId string) error {
+	podInfo, err := a.openPodInfo(podName, sessionId)
+	if err != nil {
+		return err
+	}
+	if podInfo.GetAccountInfo().IsReadOnlyPod() {
+		return errReadOnlyPod
+	}
+	return podInfo.GetDirectory().RemoveSymlink(ctx, linkWithPath, podInfo.GetPodPassword())
+}
+
+// LinkStat is a controller function which validates if the user is logged-in,
+// pod is open and returns the information of a symbolic link, not of its target
+func (a *API) LinkStat(_ context.Context, podName, linkWithPath, sessionId string) (*dir.LinkStats, error) {
+	podInfo, err := a.openPodInfo(podName, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	return podInfo.GetDirectory().LinkStat(podName, linkWithPath)
+}
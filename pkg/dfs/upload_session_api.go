@@ -0,0 +1,100 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dfs
+
+import (
+	"context"
+	"io"
+
+	f "github.com/fairdatasociety/fairOS-dfs/pkg/file"
+)
+
+// CreateUploadSession is a controller function which validates if the user is logged-in,
+// pod is open and starts a resumable upload of a file
+func (a *API) CreateUploadSession(ctx context.Context, podName, podFileName, sessionId string, fileSize int64, podPath, compression string, blockSize uint32) (*f.UploadSession, error) {
+	podInfo, err := a.openPodInfo(podName, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	if podInfo.GetAccountInfo().IsReadOnlyPod() {
+		return nil, errReadOnlyPod
+	}
+	return podInfo.GetFile().CreateUploadSession(ctx, podFileName, fileSize, blockSize, podPath, compression, podInfo.GetPodPassword())
+}
+
+// GetUploadSession is a controller function which validates if the user is logged-in,
+// pod is open and returns an upload session with the blocks that are stored
+func (a *API) GetUploadSession(ctx context.Context, podName, uploadId, sessionId string) (*f.UploadSession, error) {
+	podInfo, err := a.openPodInfo(podName, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	return podInfo.GetFile().GetUploadSession(ctx, uploadId, podInfo.GetPodPassword())
+}
+
+// UploadPart is a controller function which validates if the user is logged-in,
+// pod is open and stores the blocks of a part of an upload session
+func (a *API) UploadPart(ctx context.Context, podName, uploadId, sessionId string, offset uint64, fd io.Reader) (*f.UploadSession, error) {
+	podInfo, err := a.openPodInfo(podName, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	return podInfo.GetFile().UploadPart(ctx, uploadId, offset, fd, podInfo.GetPodPassword())
+}
+
+// FinishUploadSession is a controller function which validates if the user is logged-in,
+// pod is open and creates the file of a complete upload session in its directory
+func (a *API) FinishUploadSession(ctx context.Context, podName, uploadId, sessionId string, overwrite bool) (*f.MetaData, error) {
+	podInfo, err := a.openPodInfo(podName, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	file := podInfo.GetFile()
+	s, err := file.GetUploadSession(ctx, uploadId, podInfo.GetPodPassword())
+	if err != nil {
+		return nil, err
+	}
+	if !s.Complete() {
+		return nil, f.ErrUploadIncomplete
+	}
+
+	err = replaceFile(ctx, podInfo, s.Path, s.Name, overwrite)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := file.FinishUploadSession(ctx, uploadId, podInfo.GetPodPassword())
+	if err != nil {
+		return nil, err
+	}
+
+	// add the file to the directory metadata
+	err = podInfo.GetDirectory().AddEntryToDir(ctx, meta.Path, podInfo.GetPodPassword(), meta.Name, true)
+	if err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+// AbortUploadSession is a controller function which validates if the user is logged-in,
+// pod is open and removes an upload session
+func (a *API) AbortUploadSession(ctx context.Context, podName, uploadId, sessionId string) error {
+	podInfo, err := a.openPodInfo(podName, sessionId)
+	if err != nil {
+		return err
+	}
+	return podInfo.GetFile().AbortUploadSession(ctx, uploadId, podInfo.GetPodPassword())
+}
@@ -17,17 +17,18 @@ limitations under the License.
 package dfs
 
 import (
+	"context"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/user"
 )
 
 // CreateUserV2 is a controller function which calls the create user function from the user object.
-func (a *API) CreateUserV2(userName, passPhrase, mnemonic, sessionId string) (string, string, string, string, *user.Info, error) {
-	return a.users.CreateNewUserV2(userName, passPhrase, mnemonic, sessionId, a.tm)
+func (a *API) CreateUserV2(ctx context.Context, userName, passPhrase, mnemonic, sessionId string) (string, string, string, string, *user.Info, error) {
+	return a.users.CreateNewUserV2(ctx, userName, passPhrase, mnemonic, sessionId, a.tm)
 }
 
 // LoginUserV2 is a controller function which calls the users login function.
-func (a *API) LoginUserV2(userName, passPhrase, sessionId string) (*user.Info, string, string, error) {
-	return a.users.LoginUserV2(userName, passPhrase, a.client, a.tm, sessionId)
+func (a *API) LoginUserV2(ctx context.Context, userName, passPhrase, sessionId string) (*user.Info, string, string, error) {
+	return a.users.LoginUserV2(ctx, userName, passPhrase, a.client, a.tm, sessionId)
 }
 
 // LoadLiteUser is a controller function which loads user from mnemonic and doesn't store any user info on chain
@@ -47,14 +48,14 @@ func (a *API) LogoutUser(sessionId string) error {
 }
 
 // DeleteUserV2 is a controller function which deletes a logged-in user.
-func (a *API) DeleteUserV2(passPhrase, sessionId string) error {
+func (a *API) DeleteUserV2(ctx context.Context, passPhrase, sessionId string) error {
 	// get the logged-in user information
 	ui := a.users.GetLoggedInUserInfo(sessionId)
 	if ui == nil {
 		return ErrUserNotLoggedIn
 	}
 
-	return a.users.DeleteUserV2(ui.GetUserName(), passPhrase, sessionId, ui)
+	return a.users.DeleteUserV2(ctx, ui.GetUserName(), passPhrase, sessionId, ui)
 }
 
 // IsUserNameAvailableV2 checks if a given username is available in this dfs server.
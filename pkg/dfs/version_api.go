@@ -0,0 +1,91 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dfs
+
+import (
+	"context"
+	"io"
+
+	f "github.com/fairdatasociety/fairOS-dfs/pkg/file"
+)
+
+// SetVersionPolicy is a controller function which validates if the user is logged-in,
+// pod is open and sets if and for how long the previous versions of files are kept
+func (a *API) SetVersionPolicy(ctx context.Context, podName, sessionId string, policy f.VersionPolicy) error {
+	podInfo, err := a.openPodInfo(podName, sessionId)
+	if err != nil {
+		return err
+	}
+	if podInfo.GetAccountInfo().IsReadOnlyPod() {
+		return errReadOnlyPod
+	}
+	return podInfo.GetFile().SetVersionPolicy(ctx, policy, podInfo.GetPodPassword())
+}
+
+// GetVersionPolicy is a controller function which validates if the user is logged-in,
+// pod is open and returns the version policy of the pod
+func (a *API) GetVersionPolicy(ctx context.Context, podName, sessionId string) (*f.VersionPolicy, error) {
+	podInfo, err := a.openPodInfo(podName, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	return podInfo.GetFile().GetVersionPolicy(ctx, podInfo.GetPodPassword())
+}
+
+// FileVersions is a controller function which validates if the user is logged-in,
+// pod is open and lists the previous versions of a file
+func (a *API) FileVersions(ctx context.Context, podName, podFileWithPath, sessionId string) ([]f.FileVersion, error) {
+	podInfo, err := a.openPodInfo(podName, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	return podInfo.GetFile().ListVersions(ctx, podFileWithPath, podInfo.GetPodPassword())
+}
+
+// DownloadFileVersion is a controller function which validates if the user is logged-in,
+// pod is open and returns a reader for a version of a file, 0 being the current one
+func (a *API) DownloadFileVersion(ctx context.Context, podName, podFileWithPath, sessionId string, version int) (io.ReadCloser, uint64, error) {
+	podInfo, err := a.openPodInfo(podName, sessionId)
+	if err != nil {
+		return nil, 0, err
+	}
+	return podInfo.GetFile().DownloadVersion(ctx, podFileWithPath, version, podInfo.GetPodPassword())
+}
+
+// RestoreFileVersion is a controller function which validates if the user is logged-in,
+// pod is open and makes a version of a file its current content
+func (a *API) RestoreFileVersion(ctx context.Context, podName, podFileWithPath, sessionId string, version int) error {
+	podInfo, err := a.openPodInfo(podName, sessionId)
+	if err != nil {
+		return err
+	}
+	if podInfo.GetAccountInfo().IsReadOnlyPod() {
+		return errReadOnlyPod
+	}
+	_, err = podInfo.GetFile().RestoreVersion(ctx, podFileWithPath, version, podInfo.GetPodPassword())
+	return err
+}
+
+// DiffFileVersions is a controller function which validates if the user is logged-in,
+// pod is open and lists the bytes that differ between two versions of a file
+func (a *API) DiffFileVersions(ctx context.Context, podName, podFileWithPath, sessionId string, from, to int) (*f.VersionDiff, error) {
+	podInfo, err := a.openPodInfo(podName, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	return podInfo.GetFile().DiffVersions(ctx, podFileWithPath, from, to, podInfo.GetPodPassword())
+}
@@ -0,0 +1,100 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dfs
+
+import (
+	"context"
+	"time"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/dir"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/pod"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+)
+
+// WatchDir is a controller function which validates if the user is logged-in, pod is open
+// and the directory is present, and returns a watcher for the entries of the directory.
+// The watcher polls at the given interval once it runs.
+func (a *API) WatchDir(podName, dirPath, sessionId string, interval time.Duration) (*feed.Watcher, error) {
+	podInfo, err := a.openPodInfo(podName, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	totalPath := utils.CombinePathAndFile(dirPath, "")
+	if podInfo.GetDirectory().GetDirFromDirectoryMap(totalPath) == nil {
+		return nil, dir.ErrDirectoryNotPresent
+	}
+	topics := [][]byte{utils.HashString(totalPath)}
+	return podInfo.GetFeed().NewWatcher(podInfo.GetPodAddress(), func(context.Context) ([][]byte, error) {
+		return topics, nil
+	}, interval), nil
+}
+
+// WatchKVTable is a controller function which validates if the user is logged-in, pod is
+// open and the table is present, and returns a watcher for the changes of the table.
+// The watcher polls at the given interval once it runs.
+func (a *API) WatchKVTable(ctx context.Context, sessionId, podName, name string, interval time.Duration) (*feed.Watcher, error) {
+	podInfo, err := a.openPodInfo(podName, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	kvStore := podInfo.GetKVStore()
+	_, err = kvStore.WatchTopics(ctx, name, podInfo.GetPodPassword())
+	if err != nil {
+		return nil, err
+	}
+	return podInfo.GetFeed().NewWatcher(podInfo.GetPodAddress(), func(ctx context.Context) ([][]byte, error) {
+		return kvStore.WatchTopics(ctx, name, podInfo.GetPodPassword())
+	}, interval), nil
+}
+
+// WatchDocDB is a controller function which validates if the user is logged-in, pod is
+// open and the document db is present, and returns a watcher for the documents added to
+// or removed from the db. The watcher polls at the given interval once it runs.
+func (a *API) WatchDocDB(ctx context.Context, sessionId, podName, name string, interval time.Duration) (*feed.Watcher, error) {
+	podInfo, err := a.openPodInfo(podName, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	docStore := podInfo.GetDocStore()
+	_, err = docStore.WatchTopics(ctx, name, podInfo.GetPodPassword())
+	if err != nil {
+		return nil, err
+	}
+	return podInfo.GetFeed().NewWatcher(podInfo.GetPodAddress(), func(ctx context.Context) ([][]byte, error) {
+		return docStore.WatchTopics(ctx, name, podInfo.GetPodPassword())
+	}, interval), nil
+}
+
+func (a *API) openPodInfo(podName, sessionId string) (*pod.Info, error) {
+	// get the logged-in user information
+	ui := a.users.GetLoggedInUserInfo(sessionId)
+	if ui == nil {
+		return nil, ErrUserNotLoggedIn
+	}
+
+	// check if pod open
+	if !ui.IsPodOpen(podName) {
+		return nil, ErrPodNotOpen
+	}
+
+	podInfo, _, err := ui.GetPod().GetPodInfoFromPodMap(podName)
+	if err != nil {
+		return nil, err
+	}
+	return podInfo, nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dfs
+
+import "context"
+
+// FileXattrs is a controller function which validates if the user is logged-in,
+// pod is open and returns the extended attributes of a file
+func (a *API) FileXattrs(_ context.Context, podName, podFileWithPath, sessionId string) (map[string]string, error) {
+	podInfo, err := a.openPodInfo(podName, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	return podInfo.GetFile().Xattrs(podFileWithPath)
+}
+
+// SetFileXattr is a controller function which validates if the user is logged-in,
+// pod is open and sets an extended attribute of a file
+func (a *API) SetFileXattr(ctx context.Context, podName, podFileWithPath, sessionId, name, value string) error {
+	podInfo, err := a.openPodInfo(podName, sessionId)
+	if err != nil {
+		return err
+	}
+	if podInfo.GetAccountInfo().IsReadOnlyPod() {
+		return errReadOnlyPod
+	}
+	return podInfo.GetFile().SetXattr(ctx, podFileWithPath, podInfo.GetPodPassword(), name, value)
+}
+
+// RemoveFileXattr is a controller function which validates if the user is logged-in,
+// pod is open and removes an extended attribute of a file
+func (a *API) RemoveFileXattr(ctx context.Context, podName, podFileWithPath, sessionId, name string) error {
+	podInfo, err := a.openPodInfo(podName, sessionId)
+	if err != nil {
+		return err
+	}
+	if podInfo.GetAccountInfo().IsReadOnlyPod() {
+		return errReadOnlyPod
+	}
+	return podInfo.GetFile().RemoveXattr(ctx, podFileWithPath, podInfo.GetPodPassword(), name)
+}
+
+// DirectoryXattrs is a controller function which validates if the user is logged-in,
+// pod is open and returns the extended attributes of a directory
+func (a *API) DirectoryXattrs(_ context.Context, podName, directoryNameWithPath, sessionId string) (map[string]string, error) {
+	podInfo, err := a.openPodInfo(podName, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	return podInfo.GetDirectory().Xattrs(directoryNameWithPath)
+}
+
+// SetDirectoryXattr is a controller function which validates if the user is logged-in,
+// pod is open and sets an extended attribute of a directory
+func (a *API) SetDirectoryXattr(ctx context.Context, podName, directoryNameWithPath, sessionId, name, value string) error {
+	podInfo, err := a.openPodInfo(podName, sessionId)
+	if err != nil {
+		return err
+	}
+	if podInfo.GetAccountInfo().IsReadOnlyPod() {
+		return errReadOnlyPod
+	}
+	return podInfo.GetDirectory().SetXattr(ctx, directoryNameWithPath, podInfo.GetPodPassword(), name, value)
+}
+
+// RemoveDirectoryXattr is a controller function which validates if the user is logged-in,
+// pod is open and removes an extended attribute of a directory
+func (a *API) RemoveDirectoryXattr(ctx context.Context, podName, directoryNameWithPath, sessionId, name string) error {
+	podInfo, err := a.openPodInfo(podName, sessionId)
+	if err != nil {
+		return err
+	}
+	if podInfo.GetAccountInfo().IsReadOnlyPod() {
+		return errReadOnlyPod
+	}
+	return podInfo.GetDirectory().RemoveXattr(ctx, directoryNameWithPath, podInfo.GetPodPassword(), name)
+}
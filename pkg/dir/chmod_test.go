@@ -19,6 +19,7 @@ import (
 )
 
 func TestChmod(t *testing.T) {
+	ctx := context.Background()
 	mockClient := bm.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -43,36 +44,36 @@ func TestChmod(t *testing.T) {
 		dirObject := dir.NewDirectory("pod1", mockClient, fd, user, mockFile, tm, logger)
 
 		// make root dir so that other directories can be added
-		err = dirObject.MkRootDir("pod1", podPassword, user, fd)
+		err = dirObject.MkRootDir(ctx, "pod1", podPassword, user, fd)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// populate the directory with few directory and files
-		err := dirObject.MkDir("/dirToChmod", podPassword)
+		err := dirObject.MkDir(ctx, "/dirToChmod", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = dirObject.MkDir("/dirToChmod/subDir1", podPassword)
+		err = dirObject.MkDir(ctx, "/dirToChmod/subDir1", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = dirObject.MkDir("/dirToChmod/subDir2", podPassword)
+		err = dirObject.MkDir(ctx, "/dirToChmod/subDir2", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 		// just add dummy file entry as file listing is not tested here
-		err = dirObject.AddEntryToDir("/dirToChmod", podPassword, "file1", true)
+		err = dirObject.AddEntryToDir(ctx, "/dirToChmod", podPassword, "file1", true)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = dirObject.AddEntryToDir("/dirToChmod", podPassword, "file2", true)
+		err = dirObject.AddEntryToDir(ctx, "/dirToChmod", podPassword, "file2", true)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// stat the directory
-		dirStats, err := dirObject.DirStat("pod1", podPassword, "/dirToChmod")
+		dirStats, err := dirObject.DirStat(ctx, "pod1", podPassword, "/dirToChmod")
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -81,12 +82,12 @@ func TestChmod(t *testing.T) {
 			t.Fatal("default mode mismatch")
 		}
 
-		err = dirObject.Chmod("/dirToChmod", podPassword, 0664)
+		err = dirObject.Chmod(ctx, "/dirToChmod", podPassword, 0664)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		dirStats, err = dirObject.DirStat("pod1", podPassword, "/dirToChmod")
+		dirStats, err = dirObject.DirStat(ctx, "pod1", podPassword, "/dirToChmod")
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -99,14 +99,16 @@ func (d *Directory) RemoveAllFromDirectoryMap() {
 }
 
 type syncTask struct {
+	ctx         context.Context
 	d           *Directory
 	path        string
 	podPassword string
 	wg          *sync.WaitGroup
 }
 
-func newSyncTask(d *Directory, path, podPassword string, wg *sync.WaitGroup) *syncTask {
+func newSyncTask(ctx context.Context, d *Directory, path, podPassword string, wg *sync.WaitGroup) *syncTask {
 	return &syncTask{
+		ctx:         ctx,
 		d:           d,
 		path:        path,
 		wg:          wg,
@@ -117,7 +119,7 @@ func newSyncTask(d *Directory, path, podPassword string, wg *sync.WaitGroup) *sy
 // Execute
 func (st *syncTask) Execute(context.Context) error {
 	defer st.wg.Done()
-	return st.d.file.LoadFileMeta(st.path, st.podPassword)
+	return st.d.file.LoadFileMeta(st.ctx, st.path, st.podPassword)
 }
 
 // Name
@@ -126,6 +128,7 @@ func (st *syncTask) Name() string {
 }
 
 type lsTask struct {
+	ctx         context.Context
 	d           *Directory
 	podPassword string
 	topic       []byte
@@ -135,8 +138,9 @@ type lsTask struct {
 	wg          *sync.WaitGroup
 }
 
-func newLsTask(d *Directory, topic []byte, path, podPassword string, l *[]Entry, mtx sync.Locker, wg *sync.WaitGroup) *lsTask {
+func newLsTask(ctx context.Context, d *Directory, topic []byte, path, podPassword string, l *[]Entry, mtx sync.Locker, wg *sync.WaitGroup) *lsTask {
 	return &lsTask{
+		ctx:         ctx,
 		d:           d,
 		podPassword: podPassword,
 		topic:       topic,
@@ -150,7 +154,7 @@ func newLsTask(d *Directory, topic []byte, path, podPassword string, l *[]Entry,
 // Execute
 func (lt *lsTask) Execute(context.Context) error {
 	defer lt.wg.Done()
-	_, data, err := lt.d.fd.GetFeedData(lt.topic, lt.d.getAddress(), []byte(lt.podPassword))
+	_, data, err := lt.d.fd.GetFeedData(lt.ctx, lt.topic, lt.d.getAddress(), []byte(lt.podPassword))
 	if err != nil { // skipcq: TCV-001
 		return fmt.Errorf("list dir : %v", err)
 	}
@@ -166,6 +170,7 @@ func (lt *lsTask) Execute(context.Context) error {
 		AccessTime:       strconv.FormatInt(dirInode.Meta.AccessTime, 10),
 		ModificationTime: strconv.FormatInt(dirInode.Meta.ModificationTime, 10),
 		Mode:             dirInode.Meta.Mode,
+		Xattrs:           utils.CopyXattrs(dirInode.Meta.Xattrs),
 	}
 	lt.d.AddToDirectoryMap(lt.path, dirInode)
 	lt.mtx.Lock()
@@ -17,13 +17,14 @@ limitations under the License.
 package dir
 
 import (
+	"context"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
 )
 
 // IsDirectoryPresent this function check if a given directory is present inside the pod.
-func (d *Directory) IsDirectoryPresent(directoryNameWithPath, podPassword string) bool {
+func (d *Directory) IsDirectoryPresent(ctx context.Context, directoryNameWithPath, podPassword string) bool {
 	topic := utils.HashString(directoryNameWithPath)
-	_, metaBytes, err := d.fd.GetFeedData(topic, d.userAddress, []byte(podPassword))
+	_, metaBytes, err := d.fd.GetFeedData(ctx, topic, d.userAddress, []byte(podPassword))
 	if string(metaBytes) == utils.DeletedFeedMagicWord {
 		return false
 	}
@@ -36,6 +36,7 @@ import (
 )
 
 func TestDirPresent(t *testing.T) {
+	ctx := context.Background()
 	mockClient := bm.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -60,29 +61,29 @@ func TestDirPresent(t *testing.T) {
 		dirObject := dir.NewDirectory("pod1", mockClient, fd, user, mockFile, tm, logger)
 
 		// make root dir so that other directories can be added
-		err = dirObject.MkRootDir("pod1", podPassword, user, fd)
+		err = dirObject.MkRootDir(ctx, "pod1", podPassword, user, fd)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// create a new dir
-		err := dirObject.MkDir("/baseDir", podPassword)
+		err := dirObject.MkDir(ctx, "/baseDir", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// check if dir is present
-		present := dirObject.IsDirectoryPresent("/baseDir", podPassword)
+		present := dirObject.IsDirectoryPresent(ctx, "/baseDir", podPassword)
 		if !present {
 			t.Fatalf("directory is not present")
 		}
 
-		err = dirObject.RmDir("/baseDir", podPassword)
+		err = dirObject.RmDir(ctx, "/baseDir", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		present = dirObject.IsDirectoryPresent("/baseDir", podPassword)
+		present = dirObject.IsDirectoryPresent(ctx, "/baseDir", podPassword)
 		if present {
 			t.Fatalf("directory is present")
 		}
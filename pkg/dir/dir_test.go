@@ -1,8 +1,10 @@
 package dir_test
 
 import (
+	"bytes"
 	"context"
 	"io"
+	"sync"
 	"testing"
 	"time"
 
@@ -12,6 +14,7 @@ import (
 	"github.com/plexsysio/taskmanager"
 
 	"github.com/fairdatasociety/fairOS-dfs/pkg/account"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore"
 	bm "github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/bee/mock"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/dir"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
@@ -20,6 +23,7 @@ import (
 )
 
 func TestDirRmAllFromMap(t *testing.T) {
+	ctx := context.Background()
 	mockClient := bm.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -44,19 +48,19 @@ func TestDirRmAllFromMap(t *testing.T) {
 		dirObject := dir.NewDirectory("pod1", mockClient, fd, user, mockFile, tm, logger)
 
 		// make root dir so that other directories can be added
-		err = dirObject.MkRootDir("pod1", podPassword, user, fd)
+		err = dirObject.MkRootDir(ctx, "pod1", podPassword, user, fd)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// create a new dir
-		err := dirObject.MkDir("/baseDir", podPassword)
+		err := dirObject.MkDir(ctx, "/baseDir", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// check if dir is present
-		present := dirObject.IsDirectoryPresent("/baseDir", podPassword)
+		present := dirObject.IsDirectoryPresent(ctx, "/baseDir", podPassword)
 		if !present {
 			t.Fatalf("directory is not present")
 		}
@@ -68,3 +72,113 @@ func TestDirRmAllFromMap(t *testing.T) {
 		}
 	})
 }
+
+// interleavingClient runs the change of another writer once a chunk has been
+// downloaded a given number of times
+type interleavingClient struct {
+	blockstore.Client
+	mu     sync.Mutex
+	target []byte
+	seen   int
+	after  int
+	other  func()
+}
+
+func (c *interleavingClient) DownloadChunk(ctx context.Context, address []byte) ([]byte, error) {
+	data, err := c.Client.DownloadChunk(ctx, address)
+	var other func()
+	c.mu.Lock()
+	if bytes.Equal(address, c.target) {
+		c.seen++
+		if c.seen == c.after {
+			other, c.other = c.other, nil
+		}
+	}
+	c.mu.Unlock()
+	if other != nil {
+		other()
+	}
+	return data, err
+}
+
+func TestDirConcurrentWriters(t *testing.T) {
+	ctx := context.Background()
+	mockClient := bm.NewMockBeeClient()
+	logger := logging.New(io.Discard, 0)
+	acc := account.New(logger)
+	_, _, err := acc.CreateUserAccount("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pod1AccountInfo, err := acc.CreatePodAccount(1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := acc.GetAddress(1)
+	mockFile := fm.NewMockFile()
+	tm := taskmanager.New(1, 10, time.Second*15, logger)
+	defer func() {
+		_ = tm.Stop(context.Background())
+	}()
+	podPassword, _ := utils.GetRandString(pod.PasswordLength)
+
+	client1 := &interleavingClient{Client: mockClient}
+	fd1 := feed.New(pod1AccountInfo, client1, logger)
+	dirObject1 := dir.NewDirectory("pod1", client1, fd1, user, mockFile, tm, logger)
+	err = dirObject1.MkRootDir(ctx, "pod1", podPassword, user, fd1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fd2 := feed.New(pod1AccountInfo, mockClient, logger)
+	dirObject2 := dir.NewDirectory("pod1", mockClient, fd2, user, mockFile, tm, logger)
+	err = dirObject2.SyncDirectory(ctx, "/", podPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// count the downloads of the latest root update needed to read the root
+	ref, _, err := fd1.GetFeedData(ctx, utils.HashString("/"), user, []byte(podPassword))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client1.mu.Lock()
+	client1.target = ref
+	client1.mu.Unlock()
+	_, _, err = fd1.GetFeedData(ctx, utils.HashString("/"), user, []byte(podPassword))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the second writer adds its directory right after the first one has read the root
+	client1.mu.Lock()
+	client1.after, client1.seen = client1.seen, 0
+	client1.other = func() {
+		err := dirObject2.MkDir(ctx, "/dir2", podPassword)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	client1.mu.Unlock()
+	err = dirObject1.MkDir(ctx, "/dir1", podPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// both directories are kept
+	reader := dir.NewDirectory("pod1", mockClient, feed.New(pod1AccountInfo, mockClient, logger), user, mockFile, tm, logger)
+	err = reader.SyncDirectory(ctx, "/", podPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, _, err := reader.ListDir(ctx, "/", podPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make(map[string]bool)
+	for _, entry := range entries {
+		names[entry.Name] = true
+	}
+	if len(entries) != 2 || !names["dir1"] || !names["dir2"] {
+		t.Fatalf("expected both directories, got %v", entries)
+	}
+}
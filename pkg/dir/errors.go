@@ -27,6 +27,16 @@ var (
 	ErrDirectoryAlreadyPresent = errors.New("directory name already present")
 	//ErrDirectoryNotPresent
 	ErrDirectoryNotPresent = errors.New("directory not present")
+	//ErrEntryAlreadyPresent
+	ErrEntryAlreadyPresent = errors.New("entry already present in the directory")
 	//ErrInvalidFileOrDirectoryName
 	ErrInvalidFileOrDirectoryName = errors.New("invalid file or directory name")
+	//ErrInvalidLinkTarget
+	ErrInvalidLinkTarget = errors.New("invalid symbolic link target")
+	//ErrLinkAlreadyPresent
+	ErrLinkAlreadyPresent = errors.New("file, directory or link of the same name already present")
+	//ErrLinkNotPresent
+	ErrLinkNotPresent = errors.New("symbolic link not present")
+	//ErrTooManyLinks
+	ErrTooManyLinks = errors.New("too many levels of symbolic links")
 )
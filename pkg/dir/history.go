@@ -0,0 +1,94 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dir
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+)
+
+// InodeRevision is a revision of the inode of a directory
+type InodeRevision struct {
+	// Timestamp is the modification time of the revision. A deletion carries no time, so
+	// it is the earliest time the deletion can have happened at.
+	Timestamp int64  `json:"timestamp"`
+	Reference string `json:"reference"`
+	Deleted   bool   `json:"deleted,omitempty"`
+	Inode     *Inode `json:"inode,omitempty"`
+}
+
+// GetInodeHistory lists all the revisions of the inode of a directory, oldest first
+func (d *Directory) GetInodeHistory(ctx context.Context, dirNameWithPath, podPassword string) ([]InodeRevision, error) {
+	topic := utils.HashString(dirNameWithPath)
+	history, err := d.fd.GetFeedHistory(ctx, topic, d.getAddress(), []byte(podPassword))
+	if err != nil {
+		return nil, err
+	}
+	revisions := make([]InodeRevision, len(history))
+	var timestamp int64
+	for i, h := range history {
+		v := InodeRevision{
+			Reference: hex.EncodeToString(h.Reference),
+		}
+		t := int64(h.Timestamp)
+		if string(h.Data) == utils.DeletedFeedMagicWord {
+			v.Deleted = true
+		} else {
+			v.Inode = &Inode{}
+			err = json.Unmarshal(h.Data, v.Inode)
+			if err != nil { // skipcq: TCV-001
+				return nil, fmt.Errorf("dir history: %v", err)
+			}
+			if v.Inode.Meta != nil {
+				t = v.Inode.Meta.ModificationTime
+			}
+		}
+		// the revisions are in order, so a revision is never older than the previous one
+		if t > timestamp {
+			timestamp = t
+		}
+		v.Timestamp = timestamp
+		revisions[i] = v
+	}
+	return revisions, nil
+}
+
+// GetInodeAt returns the inode of a directory as it was at the given unix time
+func (d *Directory) GetInodeAt(ctx context.Context, dirNameWithPath, podPassword string, timestamp int64) (*Inode, error) {
+	revisions, err := d.GetInodeHistory(ctx, dirNameWithPath, podPassword)
+	if err != nil {
+		return nil, err
+	}
+	var found *InodeRevision
+	for i := range revisions {
+		if revisions[i].Timestamp > timestamp {
+			break
+		}
+		found = &revisions[i]
+	}
+	if found == nil {
+		return nil, ErrDirectoryNotPresent
+	}
+	if found.Deleted {
+		return nil, ErrResourceDeleted
+	}
+	return found.Inode, nil
+}
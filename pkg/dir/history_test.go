@@ -0,0 +1,94 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dir_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/account"
+	bm "github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/bee/mock"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/dir"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
+	fm "github.com/fairdatasociety/fairOS-dfs/pkg/file/mock"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/pod"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+	"github.com/plexsysio/taskmanager"
+)
+
+func TestHistory(t *testing.T) {
+	ctx := context.Background()
+	mockClient := bm.NewMockBeeClient()
+	logger := logging.New(io.Discard, 0)
+	acc := account.New(logger)
+	_, _, err := acc.CreateUserAccount("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pod1AccountInfo, err := acc.CreatePodAccount(1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fd := feed.New(pod1AccountInfo, mockClient, logger)
+	user := acc.GetAddress(1)
+	tm := taskmanager.New(1, 10, time.Second*15, logger)
+	defer func() {
+		_ = tm.Stop(context.Background())
+	}()
+	podPassword, _ := utils.GetRandString(pod.PasswordLength)
+	dirObject := dir.NewDirectory("pod1", mockClient, fd, user, fm.NewMockFile(), tm, logger)
+	err = dirObject.MkRootDir(ctx, "pod1", podPassword, user, fd)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, d := range []string{"/dir1", "/dir1/sub1", "/dir1/sub2"} {
+		err = dirObject.MkDir(ctx, d, podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	revisions, err := dirObject.GetInodeHistory(ctx, "/dir1", podPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revisions) != 3 {
+		t.Fatalf("expected 3 revisions, got %d", len(revisions))
+	}
+	for i, r := range revisions {
+		if r.Deleted || r.Inode == nil || len(r.Inode.FileOrDirNames) != i {
+			t.Fatalf("revision %d should have %d entries", i, i)
+		}
+	}
+
+	inode, err := dirObject.GetInodeAt(ctx, "/dir1", podPassword, time.Now().Unix())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inode.FileOrDirNames) != 2 {
+		t.Fatal("expected the latest inode")
+	}
+	_, err = dirObject.GetInodeAt(ctx, "/dir1", podPassword, revisions[0].Timestamp-1)
+	if !errors.Is(err, dir.ErrDirectoryNotPresent) {
+		t.Fatalf("expected directory not present before mkdir, got %v", err)
+	}
+}
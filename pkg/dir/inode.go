@@ -27,6 +27,8 @@ import (
 type Inode struct {
 	Meta           *MetaData `json:"meta"`
 	FileOrDirNames []string  `json:"fileOrDirNames"`
+	// Links are the targets of the "_L_" entries, by name
+	Links map[string]*Link `json:"links,omitempty"`
 }
 
 var (
@@ -17,8 +17,10 @@ limitations under the License.
 package dir
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -32,23 +34,25 @@ const (
 
 // Entry
 type Entry struct {
-	Name             string `json:"name"`
-	ContentType      string `json:"contentType"`
-	Size             string `json:"size,omitempty"`
-	Mode             uint32 `json:"mode"`
-	BlockSize        string `json:"blockSize,omitempty"`
-	CreationTime     string `json:"creationTime"`
-	ModificationTime string `json:"modificationTime"`
-	AccessTime       string `json:"accessTime"`
+	Name             string            `json:"name"`
+	ContentType      string            `json:"contentType"`
+	Size             string            `json:"size,omitempty"`
+	Mode             uint32            `json:"mode"`
+	BlockSize        string            `json:"blockSize,omitempty"`
+	CreationTime     string            `json:"creationTime"`
+	ModificationTime string            `json:"modificationTime"`
+	AccessTime       string            `json:"accessTime"`
+	Xattrs           map[string]string `json:"xattrs,omitempty"`
+	Target           string            `json:"target,omitempty"`
 }
 
 // ListDir given a directory, this function lists all the children (directory) inside the given directory.
 // it also creates a list of files inside the directory and gives it back, so that the file listing
 // function can give information about those files.
-func (d *Directory) ListDir(dirNameWithPath, podPassword string) ([]Entry, []string, error) {
+func (d *Directory) ListDir(ctx context.Context, dirNameWithPath, podPassword string) ([]Entry, []string, error) {
 	dirNameWithPath = filepath.ToSlash(dirNameWithPath)
 	topic := utils.HashString(dirNameWithPath)
-	_, data, err := d.fd.GetFeedData(topic, d.getAddress(), []byte(podPassword))
+	_, data, err := d.fd.GetFeedData(ctx, topic, d.getAddress(), []byte(podPassword))
 	if err != nil { // skipcq: TCV-001
 		if dirNameWithPath == utils.PathSeparator {
 			return nil, nil, nil
@@ -72,7 +76,7 @@ func (d *Directory) ListDir(dirNameWithPath, podPassword string) ([]Entry, []str
 			dirPath := utils.CombinePathAndFile(dirNameWithPath, dirName)
 			dirTopic := utils.HashString(dirPath)
 			wg.Add(1)
-			lsTask := newLsTask(d, dirTopic, dirPath, podPassword, listEntries, mtx, wg)
+			lsTask := newLsTask(ctx, d, dirTopic, dirPath, podPassword, listEntries, mtx, wg)
 			_, err := d.syncManager.Go(lsTask)
 			if err != nil {
 				return nil, nil, fmt.Errorf("list dir : %v", err)
@@ -81,6 +85,24 @@ func (d *Directory) ListDir(dirNameWithPath, podPassword string) ([]Entry, []str
 			fileName := strings.TrimPrefix(fileOrDirName, "_F_")
 			filePath := utils.CombinePathAndFile(dirNameWithPath, fileName)
 			files = append(files, filePath)
+		} else if strings.HasPrefix(fileOrDirName, linkPrefix) {
+			linkName := strings.TrimPrefix(fileOrDirName, linkPrefix)
+			link := dirInode.Links[linkName]
+			if link == nil { // skipcq: TCV-001
+				continue
+			}
+			creationTime := strconv.FormatInt(link.CreationTime, 10)
+			mtx.Lock()
+			*listEntries = append(*listEntries, Entry{
+				Name:             linkName,
+				ContentType:      MineTypeSymlink,
+				Mode:             linkMode,
+				CreationTime:     creationTime,
+				ModificationTime: creationTime,
+				AccessTime:       creationTime,
+				Target:           link.Target,
+			})
+			mtx.Unlock()
 		}
 	}
 	wg.Wait()
@@ -39,6 +39,7 @@ import (
 )
 
 func TestListDirectory(t *testing.T) {
+	ctx := context.Background()
 	mockClient := bm.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -63,11 +64,11 @@ func TestListDirectory(t *testing.T) {
 		dirObject := dir.NewDirectory("pod1", mockClient, fd, user, mockFile, tm, logger)
 
 		// make root dir so that other directories can be added
-		err = dirObject.MkRootDir("pod1", podPassword, user, fd)
+		err = dirObject.MkRootDir(ctx, "pod1", podPassword, user, fd)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err := dirObject.MkDir("/", podPassword)
+		err := dirObject.MkDir(ctx, "/", podPassword)
 		if !errors.Is(err, dir.ErrInvalidDirectoryName) {
 			t.Fatal("invalid dir name", err)
 		}
@@ -75,55 +76,59 @@ func TestListDirectory(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = dirObject.MkDir("/"+longDirName, podPassword)
+		err = dirObject.MkDir(ctx, "/"+longDirName, podPassword)
 		if !errors.Is(err, dir.ErrTooLongDirectoryName) {
 			t.Fatal("dir name too long")
 		}
 
 		// create some dir and files
-		err = dirObject.MkDir("/parentDir", podPassword)
+		err = dirObject.MkDir(ctx, "/parentDir", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = dirObject.MkDir("/parentDir", podPassword)
+		err = dirObject.MkDir(ctx, "/parentDir", podPassword)
 		if !errors.Is(err, dir.ErrDirectoryAlreadyPresent) {
 			t.Fatal("dir already present")
 		}
 		// populate the directory with few directory and files
-		err = dirObject.MkDir("/parentDir/subDir1", podPassword)
+		err = dirObject.MkDir(ctx, "/parentDir/subDir1", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = dirObject.MkDir("/parentDir/subDir2", podPassword)
+		err = dirObject.MkDir(ctx, "/parentDir/subDir2", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		err = dirObject.AddEntryToDir("", podPassword, "file1", true)
+		err = dirObject.AddEntryToDir(ctx, "", podPassword, "file1", true)
 		if !errors.Is(err, dir.ErrInvalidDirectoryName) {
 			t.Fatal("invalid dir name")
 		}
-		err = dirObject.AddEntryToDir("/parentDir", podPassword, "", true)
+		err = dirObject.AddEntryToDir(ctx, "/parentDir", podPassword, "", true)
 		if !errors.Is(err, dir.ErrInvalidFileOrDirectoryName) {
 			t.Fatal("invalid file or dir name")
 		}
-		err = dirObject.AddEntryToDir("/parentDir-not-available", podPassword, "file1", true)
+		err = dirObject.AddEntryToDir(ctx, "/parentDir-not-available", podPassword, "file1", true)
 		if !errors.Is(err, dir.ErrDirectoryNotPresent) {
 			t.Fatal("parent not available")
 		}
 
 		// just add dummy file entry as file listing is not tested here
-		err = dirObject.AddEntryToDir("/parentDir", podPassword, "file1", true)
+		err = dirObject.AddEntryToDir(ctx, "/parentDir", podPassword, "file1", true)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = dirObject.AddEntryToDir("/parentDir", podPassword, "file2", true)
+		err = dirObject.AddEntryToDir(ctx, "/parentDir", podPassword, "file2", true)
 		if err != nil {
 			t.Fatal(err)
 		}
+		err = dirObject.AddEntryToDir(ctx, "/parentDir", podPassword, "file2", true)
+		if !errors.Is(err, dir.ErrEntryAlreadyPresent) {
+			t.Fatal("entry already present", err)
+		}
 
 		// validate dir listing
-		dirEntries, files, err := dirObject.ListDir("/parentDir", podPassword)
+		dirEntries, files, err := dirObject.ListDir(ctx, "/parentDir", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -159,28 +164,28 @@ func TestListDirectory(t *testing.T) {
 		dirObject := dir.NewDirectory("pod1", mockClient, fd, user, mockFile, tm, logger)
 
 		// make root dir so that other directories can be added
-		err = dirObject.MkRootDir("pod1", podPassword, user, fd)
+		err = dirObject.MkRootDir(ctx, "pod1", podPassword, user, fd)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// create dir
-		err = dirObject.MkDir("/parentDir", podPassword)
+		err = dirObject.MkDir(ctx, "/parentDir", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 		// populate the directory with few directory and files
-		err = dirObject.MkDir("/parentDir/subDir1", podPassword)
+		err = dirObject.MkDir(ctx, "/parentDir/subDir1", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 		dirObject2 := dir.NewDirectory("pod1", mockClient, fd, user, mockFile, tm, logger)
-		err = dirObject2.AddRootDir("pod1", podPassword, user, fd)
+		err = dirObject2.AddRootDir(ctx, "pod1", podPassword, user, fd)
 		if err != nil {
 			t.Fatal(err)
 		}
 		// validate dir listing
-		dirs, _, err := dirObject2.ListDir("/parentDir", podPassword)
+		dirs, _, err := dirObject2.ListDir(ctx, "/parentDir", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -30,4 +30,6 @@ type MetaData struct {
 	AccessTime       int64  `json:"accessTime"`
 	ModificationTime int64  `json:"modificationTime"`
 	Mode             uint32 `json:"mode"`
+	// Xattrs are the extended attributes set by the applications
+	Xattrs map[string]string `json:"xattrs,omitempty"`
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package dir
 
 import (
+	"context"
 	"encoding/json"
 	"path/filepath"
 	"strings"
@@ -34,7 +35,7 @@ const (
 )
 
 // MkDir
-func (d *Directory) MkDir(dirToCreateWithPath, podPassword string) error {
+func (d *Directory) MkDir(ctx context.Context, dirToCreateWithPath, podPassword string) error {
 	parentPath := filepath.ToSlash(filepath.Dir(dirToCreateWithPath))
 	dirName := filepath.Base(dirToCreateWithPath)
 
@@ -56,7 +57,7 @@ func (d *Directory) MkDir(dirToCreateWithPath, podPassword string) error {
 		return ErrDirectoryNotPresent
 	}
 
-	if d.GetDirFromDirectoryMap(totalPath) != nil {
+	if d.GetDirFromDirectoryMap(totalPath) != nil || d.link(parentPath, dirName) != nil {
 		return ErrDirectoryAlreadyPresent
 	}
 
@@ -80,14 +81,14 @@ func (d *Directory) MkDir(dirToCreateWithPath, podPassword string) error {
 	}
 
 	// upload the metadata as blob
-	previousAddr, _, err := d.fd.GetFeedData(topic, d.userAddress, []byte(podPassword))
+	previousAddr, _, err := d.fd.GetFeedData(ctx, topic, d.userAddress, []byte(podPassword))
 	if err == nil && previousAddr != nil {
-		_, err = d.fd.UpdateFeed(topic, d.userAddress, data, []byte(podPassword))
+		_, err = d.fd.UpdateFeed(ctx, topic, d.userAddress, data, []byte(podPassword))
 		if err != nil { // skipcq: TCV-001
 			return err
 		}
 	} else {
-		_, err = d.fd.CreateFeed(topic, d.userAddress, data, []byte(podPassword))
+		_, err = d.fd.CreateFeed(ctx, topic, d.userAddress, data, []byte(podPassword))
 		if err != nil { // skipcq: TCV-001
 			return err
 		}
@@ -96,37 +97,15 @@ func (d *Directory) MkDir(dirToCreateWithPath, podPassword string) error {
 	d.AddToDirectoryMap(totalPath, dirInode)
 
 	// get the parent directory entry and add this new directory to its list of children
-	parentHash := utils.HashString(utils.CombinePathAndFile(parentPath, ""))
 	dirName = "_D_" + dirName
-	_, parentData, err := d.fd.GetFeedData(parentHash, d.userAddress, []byte(podPassword))
-	if err != nil {
-		return err
-	}
-
-	// unmarshall the data and add the directory entry to the parent
-	var parentDirInode *Inode
-	err = json.Unmarshal(parentData, &parentDirInode)
-	if err != nil { // skipcq: TCV-001
-		return err
-	}
-	parentDirInode.FileOrDirNames = append(parentDirInode.FileOrDirNames, dirName)
-
-	// marshall it back and update the parent feed
-	parentData, err = json.Marshal(parentDirInode)
-	if err != nil { // skipcq: TCV-001
-		return err
-	}
-
-	_, err = d.fd.UpdateFeed(parentHash, d.userAddress, parentData, []byte(podPassword))
-	if err != nil { // skipcq: TCV-001
-		return err
-	}
-	d.AddToDirectoryMap(parentPath, parentDirInode)
-	return nil
+	return d.modifyInode(ctx, utils.CombinePathAndFile(parentPath, ""), podPassword, func(parentDirInode *Inode) error {
+		parentDirInode.FileOrDirNames = append(parentDirInode.FileOrDirNames, dirName)
+		return nil
+	})
 }
 
 // MkRootDir
-func (d *Directory) MkRootDir(podName, podPassword string, podAddress utils.Address, fd *feed.API) error {
+func (d *Directory) MkRootDir(ctx context.Context, podName, podPassword string, podAddress utils.Address, fd *feed.API) error {
 	// create the root parent dir
 	now := time.Now().Unix()
 	meta := MetaData{
@@ -147,14 +126,14 @@ func (d *Directory) MkRootDir(podName, podPassword string, podAddress utils.Addr
 	}
 	parentPath := utils.CombinePathAndFile(utils.PathSeparator, "")
 	parentHash := utils.HashString(parentPath)
-	addr, data, err := d.fd.GetFeedData(parentHash, d.userAddress, []byte(podPassword))
+	addr, data, err := d.fd.GetFeedData(ctx, parentHash, d.userAddress, []byte(podPassword))
 	if err == nil && addr != nil && data != nil {
-		_, err = fd.UpdateFeed(parentHash, podAddress, parentData, []byte(podPassword))
+		_, err = fd.UpdateFeed(ctx, parentHash, podAddress, parentData, []byte(podPassword))
 		if err != nil { // skipcq: TCV-001
 			return err
 		}
 	} else {
-		_, err = fd.CreateFeed(parentHash, podAddress, parentData, []byte(podPassword))
+		_, err = fd.CreateFeed(ctx, parentHash, podAddress, parentData, []byte(podPassword))
 		if err != nil { // skipcq: TCV-001
 			return err
 		}
@@ -164,10 +143,10 @@ func (d *Directory) MkRootDir(podName, podPassword string, podAddress utils.Addr
 }
 
 // AddRootDir
-func (d *Directory) AddRootDir(podName, podPassword string, podAddress utils.Address, fd *feed.API) error {
+func (d *Directory) AddRootDir(ctx context.Context, podName, podPassword string, podAddress utils.Address, fd *feed.API) error {
 	parentPath := utils.CombinePathAndFile(utils.PathSeparator, "")
 	parentHash := utils.HashString(parentPath)
-	_, parentDataBytes, err := fd.GetFeedData(parentHash, podAddress, []byte(podPassword))
+	_, parentDataBytes, err := fd.GetFeedData(ctx, parentHash, podAddress, []byte(podPassword))
 	if err != nil {
 		return err
 	}
@@ -36,6 +36,7 @@ import (
 )
 
 func TestMkdir(t *testing.T) {
+	ctx := context.Background()
 	mockClient := bm.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -61,19 +62,19 @@ func TestMkdir(t *testing.T) {
 		dirObject := dir.NewDirectory("pod1", mockClient, fd, user, mockFile, tm, logger)
 
 		// make root dir so that other directories can be added
-		err = dirObject.MkRootDir("pod1", podPassword, user, fd)
+		err = dirObject.MkRootDir(ctx, "pod1", podPassword, user, fd)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// create a new dir
-		err := dirObject.MkDir("/baseDir", podPassword)
+		err := dirObject.MkDir(ctx, "/baseDir", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// validate dir
-		dirs, _, err := dirObject.ListDir("/", podPassword)
+		dirs, _, err := dirObject.ListDir(ctx, "/", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -90,34 +91,34 @@ func TestMkdir(t *testing.T) {
 		dirObject := dir.NewDirectory("pod1", mockClient, fd, user, mockFile, tm, logger)
 
 		// make root dir so that other directories can be added
-		err = dirObject.MkRootDir("pod1", podPassword, user, fd)
+		err = dirObject.MkRootDir(ctx, "pod1", podPassword, user, fd)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// try to create a new dir without creating root
-		err := dirObject.MkDir("/baseDir/baseDir2/baseDir3/baseDir4", podPassword)
+		err := dirObject.MkDir(ctx, "/baseDir/baseDir2/baseDir3/baseDir4", podPassword)
 		if err == nil || err != dir.ErrDirectoryNotPresent {
 			t.Fatal(err)
 		}
 
-		err = dirObject.MkDir("/baseDir", podPassword)
+		err = dirObject.MkDir(ctx, "/baseDir", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		err = dirObject.MkDir("/baseDir/baseDir2", podPassword)
+		err = dirObject.MkDir(ctx, "/baseDir/baseDir2", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		err = dirObject.MkDir("/baseDir/baseDir2/baseDir3", podPassword)
+		err = dirObject.MkDir(ctx, "/baseDir/baseDir2/baseDir3", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// validate dir
-		dirs, _, err := dirObject.ListDir("/baseDir", podPassword)
+		dirs, _, err := dirObject.ListDir(ctx, "/baseDir", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -128,7 +129,7 @@ func TestMkdir(t *testing.T) {
 			t.Fatalf("invalid directory name")
 		}
 
-		dirs, _, err = dirObject.ListDir("/baseDir/baseDir2", podPassword)
+		dirs, _, err = dirObject.ListDir(ctx, "/baseDir/baseDir2", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
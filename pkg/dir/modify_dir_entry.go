@@ -17,17 +17,20 @@ limitations under the License.
 package dir
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
 )
 
 // AddEntryToDir adds a new entry (directory/file) to a given directory.
 // This is typically called when a new directory is created under the given directory or
 // a new file is uploaded under the given directory.
-func (d *Directory) AddEntryToDir(parentDir, podPassword, itemToAdd string, isFile bool) error {
+func (d *Directory) AddEntryToDir(ctx context.Context, parentDir, podPassword, itemToAdd string, isFile bool) error {
 	// validation checks of the arguments
 	if parentDir == "" {
 		return ErrInvalidDirectoryName
@@ -42,45 +45,38 @@ func (d *Directory) AddEntryToDir(parentDir, podPassword, itemToAdd string, isFi
 		return ErrDirectoryNotPresent
 	}
 
-	// get the latest meta from swarm
-	topic := utils.HashString(parentDir)
-	_, data, err := d.fd.GetFeedData(topic, d.userAddress, []byte(podPassword))
-	if err != nil { // skipcq: TCV-001
-		return fmt.Errorf("modify dir entry: %v", err)
-	}
-
-	var dirInode Inode
-	err = json.Unmarshal(data, &dirInode)
-	if err != nil { // skipcq: TCV-001
-		return fmt.Errorf("modify dir entry : %v", err)
-	}
-
 	// add file or directory entry
 	if isFile {
 		itemToAdd = "_F_" + itemToAdd
 	} else { // skipcq: TCV-001
 		itemToAdd = "_D_" + itemToAdd
 	}
-	dirInode.FileOrDirNames = append(dirInode.FileOrDirNames, itemToAdd)
-	dirInode.Meta.ModificationTime = time.Now().Unix()
-
-	// update the feed of the dir and the data structure with the latest info
-	data, err = json.Marshal(dirInode)
-	if err != nil { // skipcq: TCV-001
-		return fmt.Errorf("modify dir entry : %v", err)
-	}
-	_, err = d.fd.UpdateFeed(topic, d.userAddress, data, []byte(podPassword))
-	if err != nil { // skipcq: TCV-001
-		return fmt.Errorf("modify dir entry : %v", err)
-	}
-	d.AddToDirectoryMap(parentDir, &dirInode)
-	return nil
+	retry := false
+	err := d.modifyInode(ctx, parentDir, podPassword, func(dirInode *Inode) error {
+		for _, fileOrDirName := range dirInode.FileOrDirNames {
+			if fileOrDirName == itemToAdd {
+				// the entry of an earlier attempt may have landed before its conflict
+				if retry {
+					return nil
+				}
+				return ErrEntryAlreadyPresent
+			}
+		}
+		retry = true
+		dirInode.FileOrDirNames = append(dirInode.FileOrDirNames, itemToAdd)
+		dirInode.Meta.ModificationTime = time.Now().Unix()
+		return nil
+	})
+	if err != nil && err != ErrEntryAlreadyPresent { // skipcq: TCV-001
+		return fmt.Errorf("modify dir entry: %w", err)
+	}
+	return err
 }
 
 // RemoveEntryFromDir removes an entry (directory/file) under the given directory.
 // This is typically called when a  directory is deleted under the given directory or
 // a file is removed under the given directory.
-func (d *Directory) RemoveEntryFromDir(parentDir, podPassword, itemToDelete string, isFile bool) error {
+func (d *Directory) RemoveEntryFromDir(ctx context.Context, parentDir, podPassword, itemToDelete string, isFile bool) error {
 	// validation checks of the arguments
 	if parentDir == "" { // skipcq: TCV-001
 		return ErrInvalidDirectoryName
@@ -90,41 +86,65 @@ func (d *Directory) RemoveEntryFromDir(parentDir, podPassword, itemToDelete stri
 		return ErrInvalidFileOrDirectoryName
 	}
 
-	parentHash := utils.HashString(parentDir)
-	_, parentData, err := d.fd.GetFeedData(parentHash, d.userAddress, []byte(podPassword))
-	if err != nil { // skipcq: TCV-001
-		return err
-	}
-
-	var parentDirInode *Inode
-	err = json.Unmarshal(parentData, &parentDirInode)
-	if err != nil { // skipcq: TCV-001
-		return err
-	}
-
 	if isFile {
 		itemToDelete = "_F_" + itemToDelete
 	} else {
 		itemToDelete = "_D_" + itemToDelete
 	}
-	var fileNames []string
-	for _, fileOrDirName := range parentDirInode.FileOrDirNames {
-		if fileOrDirName != itemToDelete {
-			fileNames = append(fileNames, fileOrDirName)
+	return d.modifyInode(ctx, parentDir, podPassword, func(parentDirInode *Inode) error {
+		var fileNames []string
+		for _, fileOrDirName := range parentDirInode.FileOrDirNames {
+			if fileOrDirName != itemToDelete {
+				fileNames = append(fileNames, fileOrDirName)
+			}
 		}
-	}
+		parentDirInode.FileOrDirNames = fileNames
+		parentDirInode.Meta.ModificationTime = time.Now().Unix()
+		return nil
+	})
+}
 
-	parentDirInode.FileOrDirNames = fileNames
-	parentDirInode.Meta.ModificationTime = time.Now().Unix()
+// modifyInode applies a change to the latest inode of a directory and stores it, if no
+// other writer has updated the inode in the meantime. Otherwise, the change is applied
+// again to the newer inode, so that the entries of both writers are kept.
+func (d *Directory) modifyInode(ctx context.Context, dirNameWithPath, podPassword string, change func(dirInode *Inode) error) error {
+	topic := utils.HashString(dirNameWithPath)
+	for retries := 0; ; retries++ {
+		ref, data, err := d.fd.GetFeedData(ctx, topic, d.getAddress(), []byte(podPassword))
+		if err != nil { // skipcq: TCV-001
+			return err
+		}
+		if string(data) == utils.DeletedFeedMagicWord {
+			return ErrDirectoryNotPresent
+		}
 
-	parentData, err = json.Marshal(parentDirInode)
-	if err != nil { // skipcq: TCV-001
-		return err
-	}
-	_, err = d.fd.UpdateFeed(parentHash, d.userAddress, parentData, []byte(podPassword))
-	if err != nil { // skipcq: TCV-001
-		return err
+		var dirInode Inode
+		err = json.Unmarshal(data, &dirInode)
+		if err != nil { // skipcq: TCV-001
+			return err
+		}
+		if dirInode.Meta == nil { // skipcq: TCV-001
+			return ErrDirectoryNotPresent
+		}
+		err = change(&dirInode)
+		if err != nil {
+			return err
+		}
+
+		// update the feed of the dir and the data structure with the latest info
+		data, err = json.Marshal(dirInode)
+		if err != nil { // skipcq: TCV-001
+			return err
+		}
+		_, err = d.fd.UpdateFeedIf(ctx, topic, d.getAddress(), ref, data, []byte(podPassword))
+		if errors.Is(err, feed.ErrConflict) && retries < feed.ConflictRetries {
+			d.logger.Debugf("merging concurrent update of %s", dirNameWithPath)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		d.AddToDirectoryMap(dirNameWithPath, &dirInode)
+		return nil
 	}
-	d.AddToDirectoryMap(parentDir, parentDirInode)
-	return nil
 }
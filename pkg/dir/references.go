@@ -0,0 +1,60 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dir
+
+import (
+	"context"
+	"strings"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+)
+
+// References walks the directory tree from the latest inodes in the pod, not from the
+// directory map, and marks the references of every file in it. Unlike a sync, any error
+// aborts the walk, so that no live reference is missed.
+func (d *Directory) References(ctx context.Context, dirNameWithPath, podPassword string, mark func(ref []byte)) error {
+	topic := utils.HashString(utils.CombinePathAndFile(dirNameWithPath, ""))
+	_, data, err := d.fd.GetFeedData(ctx, topic, d.userAddress, []byte(podPassword))
+	if err != nil {
+		if err.Error() == "feed does not exist or was not updated yet" {
+			return nil
+		}
+		return err
+	}
+
+	var dirInode Inode
+	err = dirInode.Unmarshal(data)
+	if err == ErrResourceDeleted {
+		return nil
+	}
+	if err != nil { // skipcq: TCV-001
+		return err
+	}
+	for _, fileOrDirName := range dirInode.FileOrDirNames {
+		if strings.HasPrefix(fileOrDirName, "_F_") {
+			fileName := strings.TrimPrefix(fileOrDirName, "_F_")
+			err = d.file.References(ctx, utils.CombinePathAndFile(dirNameWithPath, fileName), podPassword, mark)
+		} else if strings.HasPrefix(fileOrDirName, "_D_") {
+			dirName := strings.TrimPrefix(fileOrDirName, "_D_")
+			err = d.References(ctx, utils.CombinePathAndFile(dirNameWithPath, dirName), podPassword, mark)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package dir
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"path/filepath"
@@ -13,7 +14,7 @@ import (
 )
 
 // RenameDir
-func (d *Directory) RenameDir(dirNameWithPath, newDirNameWithPath, podPassword string) error {
+func (d *Directory) RenameDir(ctx context.Context, dirNameWithPath, newDirNameWithPath, podPassword string) error {
 	dirNameWithPath = filepath.ToSlash(dirNameWithPath)
 	newDirNameWithPath = filepath.ToSlash(newDirNameWithPath)
 	parentPath := filepath.ToSlash(filepath.Dir(dirNameWithPath))
@@ -48,14 +49,14 @@ func (d *Directory) RenameDir(dirNameWithPath, newDirNameWithPath, podPassword s
 		return ErrDirectoryAlreadyPresent
 	}
 
-	err := d.mapChildrenToNewPath(dirNameWithPath, newDirNameWithPath, podPassword)
+	err := d.mapChildrenToNewPath(ctx, dirNameWithPath, newDirNameWithPath, podPassword)
 	if err != nil { // skipcq: TCV-001
 		return err
 	}
 
 	topic := utils.HashString(dirNameWithPath)
 	newTopic := utils.HashString(newDirNameWithPath)
-	_, inodeData, err := d.fd.GetFeedData(topic, d.userAddress, []byte(podPassword))
+	_, inodeData, err := d.fd.GetFeedData(ctx, topic, d.userAddress, []byte(podPassword))
 	if err != nil {
 		return err
 	}
@@ -77,14 +78,14 @@ func (d *Directory) RenameDir(dirNameWithPath, newDirNameWithPath, podPassword s
 		return err
 	}
 
-	previousAddr, _, err := d.fd.GetFeedData(newTopic, d.userAddress, []byte(podPassword))
+	previousAddr, _, err := d.fd.GetFeedData(ctx, newTopic, d.userAddress, []byte(podPassword))
 	if err == nil && previousAddr != nil {
-		_, err = d.fd.UpdateFeed(newTopic, d.userAddress, fileMetaBytes, []byte(podPassword))
+		_, err = d.fd.UpdateFeed(ctx, newTopic, d.userAddress, fileMetaBytes, []byte(podPassword))
 		if err != nil { // skipcq: TCV-001
 			return err
 		}
 	} else {
-		_, err = d.fd.CreateFeed(newTopic, d.userAddress, fileMetaBytes, []byte(podPassword))
+		_, err = d.fd.CreateFeed(ctx, newTopic, d.userAddress, fileMetaBytes, []byte(podPassword))
 		if err != nil { // skipcq: TCV-001
 			return err
 		}
@@ -92,29 +93,29 @@ func (d *Directory) RenameDir(dirNameWithPath, newDirNameWithPath, podPassword s
 
 	// delete old meta
 	// update with utils.DeletedFeedMagicWord
-	_, err = d.fd.UpdateFeed(topic, d.userAddress, []byte(utils.DeletedFeedMagicWord), []byte(podPassword))
+	_, err = d.fd.UpdateFeed(ctx, topic, d.userAddress, []byte(utils.DeletedFeedMagicWord), []byte(podPassword))
 	if err != nil { // skipcq: TCV-001
 		return err
 	}
 	d.RemoveFromDirectoryMap(dirNameWithPath)
 
 	// get the parent directory entry and add this new directory to its list of children
-	err = d.RemoveEntryFromDir(parentPath, podPassword, dirName, false)
+	err = d.RemoveEntryFromDir(ctx, parentPath, podPassword, dirName, false)
 	if err != nil {
 		return err
 	}
-	err = d.AddEntryToDir(newParentPath, podPassword, newDirName, false)
+	err = d.AddEntryToDir(ctx, newParentPath, podPassword, newDirName, false)
 	if err != nil {
 		return err
 	}
 
-	err = d.SyncDirectory(parentPath, podPassword)
+	err = d.SyncDirectory(ctx, parentPath, podPassword)
 	if err != nil {
 		return err
 	}
 
 	if parentPath != newParentPath {
-		err = d.SyncDirectory(newParentPath, podPassword)
+		err = d.SyncDirectory(ctx, newParentPath, podPassword)
 		if err != nil {
 			return err
 		}
@@ -122,7 +123,7 @@ func (d *Directory) RenameDir(dirNameWithPath, newDirNameWithPath, podPassword s
 	return nil
 }
 
-func (d *Directory) mapChildrenToNewPath(totalPath, newTotalPath, podPassword string) error {
+func (d *Directory) mapChildrenToNewPath(ctx context.Context, totalPath, newTotalPath, podPassword string) error {
 	dirInode := d.GetDirFromDirectoryMap(totalPath)
 	for _, fileOrDirName := range dirInode.FileOrDirNames {
 		if strings.HasPrefix(fileOrDirName, "_F_") {
@@ -130,7 +131,7 @@ func (d *Directory) mapChildrenToNewPath(totalPath, newTotalPath, podPassword st
 			filePath := utils.CombinePathAndFile(totalPath, fileName)
 			newFilePath := utils.CombinePathAndFile(newTotalPath, fileName)
 			topic := utils.HashString(filePath)
-			_, metaBytes, err := d.fd.GetFeedData(topic, d.userAddress, []byte(podPassword))
+			_, metaBytes, err := d.fd.GetFeedData(ctx, topic, d.userAddress, []byte(podPassword))
 			if err != nil {
 				return err
 			}
@@ -153,14 +154,14 @@ func (d *Directory) mapChildrenToNewPath(totalPath, newTotalPath, podPassword st
 				return err
 			}
 
-			previousAddr, _, err := d.fd.GetFeedData(newTopic, d.userAddress, []byte(podPassword))
+			previousAddr, _, err := d.fd.GetFeedData(ctx, newTopic, d.userAddress, []byte(podPassword))
 			if err == nil && previousAddr != nil {
-				_, err = d.fd.UpdateFeed(newTopic, d.userAddress, fileMetaBytes, []byte(podPassword))
+				_, err = d.fd.UpdateFeed(ctx, newTopic, d.userAddress, fileMetaBytes, []byte(podPassword))
 				if err != nil { // skipcq: TCV-001
 					return err
 				}
 			} else {
-				_, err = d.fd.CreateFeed(newTopic, d.userAddress, fileMetaBytes, []byte(podPassword))
+				_, err = d.fd.CreateFeed(ctx, newTopic, d.userAddress, fileMetaBytes, []byte(podPassword))
 				if err != nil { // skipcq: TCV-001
 					return err
 				}
@@ -168,7 +169,13 @@ func (d *Directory) mapChildrenToNewPath(totalPath, newTotalPath, podPassword st
 
 			// delete old meta
 			// update with utils.DeletedFeedMagicWord
-			_, err = d.fd.UpdateFeed(topic, d.userAddress, []byte(utils.DeletedFeedMagicWord), []byte(podPassword))
+			_, err = d.fd.UpdateFeed(ctx, topic, d.userAddress, []byte(utils.DeletedFeedMagicWord), []byte(podPassword))
+			if err != nil { // skipcq: TCV-001
+				return err
+			}
+
+			// keep the version history of the file at its new path
+			err = d.file.MoveVersions(ctx, filePath, newFilePath, podPassword)
 			if err != nil { // skipcq: TCV-001
 				return err
 			}
@@ -176,13 +183,13 @@ func (d *Directory) mapChildrenToNewPath(totalPath, newTotalPath, podPassword st
 			dirName := strings.TrimPrefix(fileOrDirName, "_D_")
 			pathWithDir := utils.CombinePathAndFile(totalPath, dirName)
 			newPathWithDir := utils.CombinePathAndFile(newTotalPath, dirName)
-			err := d.mapChildrenToNewPath(pathWithDir, newPathWithDir, podPassword)
+			err := d.mapChildrenToNewPath(ctx, pathWithDir, newPathWithDir, podPassword)
 			if err != nil { // skipcq: TCV-001
 				return err
 			}
 			topic := utils.HashString(pathWithDir)
 			newTopic := utils.HashString(newPathWithDir)
-			_, inodeData, err := d.fd.GetFeedData(topic, d.userAddress, []byte(podPassword))
+			_, inodeData, err := d.fd.GetFeedData(ctx, topic, d.userAddress, []byte(podPassword))
 			if err != nil {
 				return err
 			}
@@ -199,14 +206,14 @@ func (d *Directory) mapChildrenToNewPath(totalPath, newTotalPath, podPassword st
 			if err != nil { // skipcq: TCV-001
 				return err
 			}
-			previousAddr, _, err := d.fd.GetFeedData(newTopic, d.userAddress, []byte(podPassword))
+			previousAddr, _, err := d.fd.GetFeedData(ctx, newTopic, d.userAddress, []byte(podPassword))
 			if err == nil && previousAddr != nil {
-				_, err = d.fd.UpdateFeed(newTopic, d.userAddress, fileMetaBytes, []byte(podPassword))
+				_, err = d.fd.UpdateFeed(ctx, newTopic, d.userAddress, fileMetaBytes, []byte(podPassword))
 				if err != nil { // skipcq: TCV-001
 					return err
 				}
 			} else {
-				_, err = d.fd.CreateFeed(newTopic, d.userAddress, fileMetaBytes, []byte(podPassword))
+				_, err = d.fd.CreateFeed(ctx, newTopic, d.userAddress, fileMetaBytes, []byte(podPassword))
 				if err != nil { // skipcq: TCV-001
 					return err
 				}
@@ -214,7 +221,7 @@ func (d *Directory) mapChildrenToNewPath(totalPath, newTotalPath, podPassword st
 
 			// delete old meta
 			// update with utils.DeletedFeedMagicWord
-			_, err = d.fd.UpdateFeed(topic, d.userAddress, []byte(utils.DeletedFeedMagicWord), []byte(podPassword))
+			_, err = d.fd.UpdateFeed(ctx, topic, d.userAddress, []byte(utils.DeletedFeedMagicWord), []byte(podPassword))
 			if err != nil { // skipcq: TCV-001
 				return err
 			}
@@ -21,6 +21,7 @@ import (
 )
 
 func TestRenameDirectory(t *testing.T) {
+	ctx := context.Background()
 	mockClient := bm.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -44,11 +45,11 @@ func TestRenameDirectory(t *testing.T) {
 		podPassword, _ := utils.GetRandString(pod.PasswordLength)
 		dirObject := dir.NewDirectory("pod1", mockClient, fd, user, fileObject, tm, logger)
 		// make root dir so that other directories can be added
-		err = dirObject.MkRootDir("pod1", podPassword, user, fd)
+		err = dirObject.MkRootDir(ctx, "pod1", podPassword, user, fd)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err := dirObject.MkDir("/", podPassword)
+		err := dirObject.MkDir(ctx, "/", podPassword)
 		if !errors.Is(err, dir.ErrInvalidDirectoryName) {
 			t.Fatal("invalid dir name")
 		}
@@ -56,62 +57,62 @@ func TestRenameDirectory(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = dirObject.MkDir("/"+longDirName, podPassword)
+		err = dirObject.MkDir(ctx, "/"+longDirName, podPassword)
 		if !errors.Is(err, dir.ErrTooLongDirectoryName) {
 			t.Fatal("dir name too long")
 		}
 
 		// create some dir and files
-		err = dirObject.MkDir("/parentDir", podPassword)
+		err = dirObject.MkDir(ctx, "/parentDir", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = dirObject.MkDir("/parentDir", podPassword)
+		err = dirObject.MkDir(ctx, "/parentDir", podPassword)
 		if !errors.Is(err, dir.ErrDirectoryAlreadyPresent) {
 			t.Fatal("dir already present")
 		}
 		// populate the directory with few directory and files
-		err = dirObject.MkDir("/parentDir/subDir1", podPassword)
+		err = dirObject.MkDir(ctx, "/parentDir/subDir1", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = dirObject.MkDir("/parentDir/subDir2", podPassword)
+		err = dirObject.MkDir(ctx, "/parentDir/subDir2", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		r := new(bytes.Buffer)
-		err = fileObject.Upload(r, "file1", 0, 100, "/parentDir", "", podPassword)
+		err = fileObject.Upload(ctx, r, "file1", 0, 100, "/parentDir", "", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = fileObject.Upload(r, "file2", 0, 100, "/parentDir", "", podPassword)
+		err = fileObject.Upload(ctx, r, "file2", 0, 100, "/parentDir", "", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = fileObject.Upload(r, "file2", 0, 100, "/parentDir/subDir2", "", podPassword)
+		err = fileObject.Upload(ctx, r, "file2", 0, 100, "/parentDir/subDir2", "", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 		// just add dummy file entry as file listing is not tested here
-		err = dirObject.AddEntryToDir("/parentDir", podPassword, "file1", true)
+		err = dirObject.AddEntryToDir(ctx, "/parentDir", podPassword, "file1", true)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = dirObject.AddEntryToDir("/parentDir", podPassword, "file2", true)
+		err = dirObject.AddEntryToDir(ctx, "/parentDir", podPassword, "file2", true)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = dirObject.AddEntryToDir("/parentDir/subDir2", podPassword, "file2", true)
+		err = dirObject.AddEntryToDir(ctx, "/parentDir/subDir2", podPassword, "file2", true)
 		if err != nil {
 			t.Fatal(err)
 		}
 		// rename
-		err = dirObject.RenameDir("/parentDir", "/parentNew", podPassword)
+		err = dirObject.RenameDir(ctx, "/parentDir", "/parentNew", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
-		dirEntries, _, err := dirObject.ListDir("/", podPassword)
+		dirEntries, _, err := dirObject.ListDir(ctx, "/", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -119,17 +120,17 @@ func TestRenameDirectory(t *testing.T) {
 			t.Fatal("rename failed for parentDir")
 		}
 
-		err = dirObject.MkDir("/parent", podPassword)
+		err = dirObject.MkDir(ctx, "/parent", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = dirObject.RenameDir("/parentNew", "/parent", podPassword)
+		err = dirObject.RenameDir(ctx, "/parentNew", "/parent", podPassword)
 		if !errors.Is(err, dir.ErrDirectoryAlreadyPresent) {
 			t.Fatal("directory name should already be present")
 		}
 
 		// validate dir listing
-		dirEntries, files, err := dirObject.ListDir("/parentNew", podPassword)
+		dirEntries, files, err := dirObject.ListDir(ctx, "/parentNew", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -162,7 +163,7 @@ func TestRenameDirectory(t *testing.T) {
 			t.Fatalf("invalid file name")
 		}
 
-		_, files, err = dirObject.ListDir("/parentNew/subDir2", podPassword)
+		_, files, err = dirObject.ListDir(ctx, "/parentNew/subDir2", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -173,7 +174,7 @@ func TestRenameDirectory(t *testing.T) {
 			t.Fatal("file name mismatch /parentNew/subDir2")
 		}
 
-		_, n, err := fileObject.Download("/parentNew/subDir2/file2", podPassword)
+		_, n, err := fileObject.Download(ctx, "/parentNew/subDir2/file2", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -187,11 +188,11 @@ func TestRenameDirectory(t *testing.T) {
 		podPassword, _ := utils.GetRandString(pod.PasswordLength)
 		dirObject := dir.NewDirectory("pod1", mockClient, fd, user, fileObject, tm, logger)
 		// make root dir so that other directories can be added
-		err = dirObject.MkRootDir("pod1", podPassword, user, fd)
+		err = dirObject.MkRootDir(ctx, "pod1", podPassword, user, fd)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err := dirObject.MkDir("/", podPassword)
+		err := dirObject.MkDir(ctx, "/", podPassword)
 		if !errors.Is(err, dir.ErrInvalidDirectoryName) {
 			t.Fatal("invalid dir name")
 		}
@@ -199,59 +200,59 @@ func TestRenameDirectory(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = dirObject.MkDir("/"+longDirName, podPassword)
+		err = dirObject.MkDir(ctx, "/"+longDirName, podPassword)
 		if !errors.Is(err, dir.ErrTooLongDirectoryName) {
 			t.Fatal("dir name too long")
 		}
 
 		// create some dir and files
-		err = dirObject.MkDir("/parentDir", podPassword)
+		err = dirObject.MkDir(ctx, "/parentDir", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// populate the directory with few directory and files
-		err = dirObject.MkDir("/parentDir/subDir1", podPassword)
+		err = dirObject.MkDir(ctx, "/parentDir/subDir1", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = dirObject.MkDir("/parentDir/subDir1/subDir11", podPassword)
+		err = dirObject.MkDir(ctx, "/parentDir/subDir1/subDir11", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = dirObject.MkDir("/parentDir/subDir1/subDir11/sub111", podPassword)
+		err = dirObject.MkDir(ctx, "/parentDir/subDir1/subDir11/sub111", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = dirObject.MkDir("/parentDir/subDir2", podPassword)
+		err = dirObject.MkDir(ctx, "/parentDir/subDir2", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		r := new(bytes.Buffer)
-		err = fileObject.Upload(r, "file1", 0, 100, "/parentDir/subDir1/subDir11/sub111", "", podPassword)
+		err = fileObject.Upload(ctx, r, "file1", 0, 100, "/parentDir/subDir1/subDir11/sub111", "", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// just add dummy file entry as file listing is not tested here
-		err = dirObject.AddEntryToDir("/parentDir/subDir1/subDir11/sub111", podPassword, "file1", true)
+		err = dirObject.AddEntryToDir(ctx, "/parentDir/subDir1/subDir11/sub111", podPassword, "file1", true)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// rename
-		err = dirObject.RenameDir("/parentDir/subDir1/subDir11/sub111", "/parentDir/subDir2/sub111", podPassword)
+		err = dirObject.RenameDir(ctx, "/parentDir/subDir1/subDir11/sub111", "/parentDir/subDir2/sub111", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		_, _, err = dirObject.ListDir("/parentDir/subDir1/subDir11/sub111", podPassword)
+		_, _, err = dirObject.ListDir(ctx, "/parentDir/subDir1/subDir11/sub111", podPassword)
 		if err == nil {
 			t.Fatal("should fail")
 		}
 
-		dirEntries, files, err := dirObject.ListDir("/parentDir", podPassword)
+		dirEntries, files, err := dirObject.ListDir(ctx, "/parentDir", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -275,7 +276,7 @@ func TestRenameDirectory(t *testing.T) {
 			t.Fatal("wrong list of directories")
 		}
 
-		dirEntries, files, err = dirObject.ListDir("/parentDir/subDir1", podPassword)
+		dirEntries, files, err = dirObject.ListDir(ctx, "/parentDir/subDir1", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -297,7 +298,7 @@ func TestRenameDirectory(t *testing.T) {
 			t.Fatal("wrong list of directories")
 		}
 
-		dirEntries, files, err = dirObject.ListDir("/parentDir/subDir2", podPassword)
+		dirEntries, files, err = dirObject.ListDir(ctx, "/parentDir/subDir2", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -318,7 +319,7 @@ func TestRenameDirectory(t *testing.T) {
 			t.Fatal("wrong list of directories")
 		}
 
-		dirEntries, files, err = dirObject.ListDir("/parentDir/subDir2/sub111", podPassword)
+		dirEntries, files, err = dirObject.ListDir(ctx, "/parentDir/subDir2/sub111", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -334,12 +335,12 @@ func TestRenameDirectory(t *testing.T) {
 			t.Fatal("wrong list of files")
 		}
 
-		err = dirObject.RenameDir("/parentDir/subDir2/sub111", "/parentDir/sub111", podPassword)
+		err = dirObject.RenameDir(ctx, "/parentDir/subDir2/sub111", "/parentDir/sub111", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		dirEntries, files, err = dirObject.ListDir("/parentDir", podPassword)
+		dirEntries, files, err = dirObject.ListDir(ctx, "/parentDir", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -363,7 +364,7 @@ func TestRenameDirectory(t *testing.T) {
 		}
 
 		// validate dir listing
-		dirEntries, files, err = dirObject.ListDir("/parentDir/subDir1", podPassword)
+		dirEntries, files, err = dirObject.ListDir(ctx, "/parentDir/subDir1", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -385,7 +386,7 @@ func TestRenameDirectory(t *testing.T) {
 			t.Fatal("wrong list of directories")
 		}
 
-		dirEntries, files, err = dirObject.ListDir("/parentDir/subDir2", podPassword)
+		dirEntries, files, err = dirObject.ListDir(ctx, "/parentDir/subDir2", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -397,12 +398,12 @@ func TestRenameDirectory(t *testing.T) {
 			t.Fatalf("invalid files entry count")
 		}
 
-		_, _, err = dirObject.ListDir("/parentDir/subDir2/sub111", podPassword)
+		_, _, err = dirObject.ListDir(ctx, "/parentDir/subDir2/sub111", podPassword)
 		if err == nil {
 			t.Fatal("should be err")
 		}
 
-		dirEntries, files, err = dirObject.ListDir("/parentDir/sub111", podPassword)
+		dirEntries, files, err = dirObject.ListDir(ctx, "/parentDir/sub111", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -422,9 +423,78 @@ func TestRenameDirectory(t *testing.T) {
 		if files[0] != "/parentDir/sub111/file1" {
 			t.Fatal("wrong list of files")
 		}
-		err = dirObject.RenameDir("/parentDir/sub111", "/parentDir/subDir2/sub111", podPassword)
+		err = dirObject.RenameDir(ctx, "/parentDir/sub111", "/parentDir/subDir2/sub111", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 	})
+
+	t.Run("rename-dir-with-versions", func(t *testing.T) {
+		pod2AccountInfo, err := acc.CreatePodAccount(2, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fd2 := feed.New(pod2AccountInfo, mockClient, logger)
+		user2 := acc.GetAddress(2)
+		fileObject := file.NewFile("pod2", mockClient, fd2, user2, tm, logger)
+		podPassword, _ := utils.GetRandString(pod.PasswordLength)
+		dirObject := dir.NewDirectory("pod2", mockClient, fd2, user2, fileObject, tm, logger)
+		err = dirObject.MkRootDir(ctx, "pod2", podPassword, user2, fd2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = fileObject.SetVersionPolicy(ctx, file.VersionPolicy{Enabled: true}, podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = dirObject.MkDir(ctx, "/versioned", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = dirObject.MkDir(ctx, "/versioned/sub", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, content := range []string{"first", "second"} {
+			err = fileObject.Upload(ctx, bytes.NewReader([]byte(content)), "file1", int64(len(content)), 100, "/versioned/sub", "", podPassword)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		err = dirObject.AddEntryToDir(ctx, "/versioned/sub", podPassword, "file1", true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = dirObject.RenameDir(ctx, "/versioned", "/renamed", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// open the pod again so the file is loaded from its new path
+		fileObject = file.NewFile("pod2", mockClient, fd2, user2, tm, logger)
+		err = fileObject.LoadFileMeta(ctx, "/renamed/sub/file1", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		versions, err := fileObject.ListVersions(ctx, "/renamed/sub/file1", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(versions) != 1 || versions[0].Size != uint64(len("first")) {
+			t.Fatalf("versions should follow the renamed directory, got %+v", versions)
+		}
+		reader, _, err := fileObject.DownloadVersion(ctx, "/renamed/sub/file1", versions[0].Version, podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(reader)
+		_ = reader.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != "first" {
+			t.Fatalf("unexpected version content %q", data)
+		}
+	})
 }
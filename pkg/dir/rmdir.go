@@ -17,6 +17,7 @@ limitations under the License.
 package dir
 
 import (
+	"context"
 	"path/filepath"
 	"strings"
 
@@ -24,7 +25,7 @@ import (
 )
 
 // RmDir removes a given directory and all the entries (file/directory) under that.
-func (d *Directory) RmDir(directoryNameWithPath, podPassword string) error {
+func (d *Directory) RmDir(ctx context.Context, directoryNameWithPath, podPassword string) error {
 	if directoryNameWithPath == "" {
 		return ErrInvalidDirectoryName
 	}
@@ -56,11 +57,11 @@ func (d *Directory) RmDir(directoryNameWithPath, podPassword string) error {
 			if strings.HasPrefix(fileOrDirName, "_F_") {
 				fileName := strings.TrimPrefix(fileOrDirName, "_F_")
 				filePath := utils.CombinePathAndFile(directoryNameWithPath, fileName)
-				err := d.file.RmFile(filePath, podPassword)
+				err := d.file.RmFile(ctx, filePath, podPassword)
 				if err != nil { // skipcq: TCV-001
 					return err
 				}
-				err = d.RemoveEntryFromDir(directoryNameWithPath, podPassword, fileName, true)
+				err = d.RemoveEntryFromDir(ctx, directoryNameWithPath, podPassword, fileName, true)
 				if err != nil { // skipcq: TCV-001
 					return err
 				}
@@ -69,7 +70,7 @@ func (d *Directory) RmDir(directoryNameWithPath, podPassword string) error {
 				path := utils.CombinePathAndFile(directoryNameWithPath, dirName)
 				d.logger.Infof(directoryNameWithPath)
 
-				err := d.RmDir(path, podPassword)
+				err := d.RmDir(ctx, path, podPassword)
 				if err != nil { // skipcq: TCV-001
 					return err
 				}
@@ -79,7 +80,7 @@ func (d *Directory) RmDir(directoryNameWithPath, podPassword string) error {
 
 	// remove the feed and clear the data structure
 	topic := utils.HashString(totalPath)
-	_, err := d.fd.UpdateFeed(topic, d.userAddress, []byte(utils.DeletedFeedMagicWord), []byte(podPassword))
+	_, err := d.fd.UpdateFeed(ctx, topic, d.userAddress, []byte(utils.DeletedFeedMagicWord), []byte(podPassword))
 	if err != nil { // skipcq: TCV-001
 		return err
 	}
@@ -90,11 +91,11 @@ func (d *Directory) RmDir(directoryNameWithPath, podPassword string) error {
 	}
 	// remove the directory entry from the parent dir
 
-	return d.RemoveEntryFromDir(parentPath, podPassword, dirToDelete, false)
+	return d.RemoveEntryFromDir(ctx, parentPath, podPassword, dirToDelete, false)
 }
 
 // RmRootDir removes root directory and all the entries (file/directory) under that.
-func (d *Directory) RmRootDir(podPassword string) error {
+func (d *Directory) RmRootDir(ctx context.Context, podPassword string) error {
 	dirToDelete := utils.PathSeparator
 
 	// check if directory present
@@ -111,11 +112,11 @@ func (d *Directory) RmRootDir(podPassword string) error {
 			if strings.HasPrefix(fileOrDirName, "_F_") {
 				fileName := strings.TrimPrefix(fileOrDirName, "_F_")
 				filePath := utils.CombinePathAndFile(dirToDelete, fileName)
-				err := d.file.RmFile(filePath, podPassword)
+				err := d.file.RmFile(ctx, filePath, podPassword)
 				if err != nil { // skipcq: TCV-001
 					return err
 				}
-				err = d.RemoveEntryFromDir(dirToDelete, podPassword, fileName, true)
+				err = d.RemoveEntryFromDir(ctx, dirToDelete, podPassword, fileName, true)
 				if err != nil { // skipcq: TCV-001
 					return err
 				}
@@ -124,7 +125,7 @@ func (d *Directory) RmRootDir(podPassword string) error {
 				path := utils.CombinePathAndFile(dirToDelete, dirName)
 				d.logger.Infof(dirToDelete)
 
-				err := d.RmDir(path, podPassword)
+				err := d.RmDir(ctx, path, podPassword)
 				if err != nil { // skipcq: TCV-001
 					return err
 				}
@@ -134,7 +135,7 @@ func (d *Directory) RmRootDir(podPassword string) error {
 
 	// remove the feed and clear the data structure
 	topic := utils.HashString(totalPath)
-	_, err := d.fd.UpdateFeed(topic, d.userAddress, []byte(utils.DeletedFeedMagicWord), []byte(podPassword))
+	_, err := d.fd.UpdateFeed(ctx, topic, d.userAddress, []byte(utils.DeletedFeedMagicWord), []byte(podPassword))
 	if err != nil { // skipcq: TCV-001
 		return err
 	}
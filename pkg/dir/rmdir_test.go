@@ -38,6 +38,7 @@ import (
 )
 
 func TestRmdir(t *testing.T) {
+	ctx := context.Background()
 	mockClient := bm.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -63,39 +64,39 @@ func TestRmdir(t *testing.T) {
 		dirObject := dir.NewDirectory("pod1", mockClient, fd, user, mockFile, tm, logger)
 
 		// make root dir so that other directories can be added
-		err = dirObject.MkRootDir("pod1", podPassword, user, fd)
+		err = dirObject.MkRootDir(ctx, "pod1", podPassword, user, fd)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// create a new dir
-		err := dirObject.MkDir("/dirToRemove", podPassword)
+		err := dirObject.MkDir(ctx, "/dirToRemove", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		err = dirObject.RmDir("", podPassword)
+		err = dirObject.RmDir(ctx, "", podPassword)
 		if !errors.Is(err, dir.ErrInvalidDirectoryName) {
 			t.Fatal("invalid dir name")
 		}
 
-		err = dirObject.RmDir("asdasd", podPassword)
+		err = dirObject.RmDir(ctx, "asdasd", podPassword)
 		if !errors.Is(err, dir.ErrInvalidDirectoryName) {
 			t.Fatal("invalid dir name")
 		}
-		err = dirObject.RmDir("/asdasd", podPassword)
+		err = dirObject.RmDir(ctx, "/asdasd", podPassword)
 		if !errors.Is(err, dir.ErrDirectoryNotPresent) {
 			t.Fatal("dir not present")
 		}
 
 		// now delete the directory
-		err = dirObject.RmDir("/dirToRemove", podPassword)
+		err = dirObject.RmDir(ctx, "/dirToRemove", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// verify if the directory is actually removed
-		dirEntry, _, err := dirObject.ListDir("/", podPassword)
+		dirEntry, _, err := dirObject.ListDir(ctx, "/", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -103,7 +104,7 @@ func TestRmdir(t *testing.T) {
 			t.Fatalf("could not delete directory")
 		}
 
-		err = dirObject.RmDir("/", podPassword)
+		err = dirObject.RmDir(ctx, "/", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -113,36 +114,36 @@ func TestRmdir(t *testing.T) {
 		dirObject := dir.NewDirectory("pod1", mockClient, fd, user, mockFile, tm, logger)
 
 		// make root dir so that other directories can be added
-		err = dirObject.MkRootDir("pod1", podPassword, user, fd)
+		err = dirObject.MkRootDir(ctx, "pod1", podPassword, user, fd)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// create a new dir
-		err := dirObject.MkDir("/dirToRemove1", podPassword)
+		err := dirObject.MkDir(ctx, "/dirToRemove1", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 		// create a new dir
-		err = dirObject.MkDir("/dirToRemove1/dirToRemove2", podPassword)
+		err = dirObject.MkDir(ctx, "/dirToRemove1/dirToRemove2", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 		// create a new dir
-		err = dirObject.MkDir("/dirToRemove1/dirToRemove2/dirToRemove", podPassword)
+		err = dirObject.MkDir(ctx, "/dirToRemove1/dirToRemove2/dirToRemove", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// make sure directories were created
-		dirEntry, _, err := dirObject.ListDir("/dirToRemove1", podPassword)
+		dirEntry, _, err := dirObject.ListDir(ctx, "/dirToRemove1", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 		if dirEntry == nil {
 			t.Fatal("nested directory \"/dirToRemove1/dirToRemove2\" was not created")
 		}
-		dirEntry, _, err = dirObject.ListDir("/dirToRemove1/dirToRemove2", podPassword)
+		dirEntry, _, err = dirObject.ListDir(ctx, "/dirToRemove1/dirToRemove2", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -151,13 +152,13 @@ func TestRmdir(t *testing.T) {
 		}
 
 		// now delete the directory
-		err = dirObject.RmDir("/dirToRemove1", podPassword)
+		err = dirObject.RmDir(ctx, "/dirToRemove1", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// verify if the directory is actually removed
-		dirEntry, _, err = dirObject.ListDir("/", podPassword)
+		dirEntry, _, err = dirObject.ListDir(ctx, "/", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -168,6 +169,7 @@ func TestRmdir(t *testing.T) {
 }
 
 func TestRmRootDirByPath(t *testing.T) {
+	ctx := context.Background()
 	mockClient := bm.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -192,36 +194,36 @@ func TestRmRootDirByPath(t *testing.T) {
 		dirObject := dir.NewDirectory("pod1", mockClient, fd, user, mockFile, tm, logger)
 
 		// make root dir so that other directories can be added
-		err = dirObject.MkRootDir("pod1", podPassword, user, fd)
+		err = dirObject.MkRootDir(ctx, "pod1", podPassword, user, fd)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// create a new dir
-		err := dirObject.MkDir("/dirToRemove1", podPassword)
+		err := dirObject.MkDir(ctx, "/dirToRemove1", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 		// create a new dir
-		err = dirObject.MkDir("/dirToRemove1/dirToRemove2", podPassword)
+		err = dirObject.MkDir(ctx, "/dirToRemove1/dirToRemove2", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 		// create a new dir
-		err = dirObject.MkDir("/dirToRemove1/dirToRemove2/dirToRemove", podPassword)
+		err = dirObject.MkDir(ctx, "/dirToRemove1/dirToRemove2/dirToRemove", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// make sure directories were created
-		dirEntry, _, err := dirObject.ListDir("/dirToRemove1", podPassword)
+		dirEntry, _, err := dirObject.ListDir(ctx, "/dirToRemove1", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 		if dirEntry == nil {
 			t.Fatal("nested directory \"/dirToRemove1/dirToRemove2\" was not created")
 		}
-		dirEntry, _, err = dirObject.ListDir("/dirToRemove1/dirToRemove2", podPassword)
+		dirEntry, _, err = dirObject.ListDir(ctx, "/dirToRemove1/dirToRemove2", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -230,11 +232,11 @@ func TestRmRootDirByPath(t *testing.T) {
 		}
 
 		fileName := "file1"
-		err = dirObject.AddEntryToDir("/dirToRemove1", podPassword, fileName, true)
+		err = dirObject.AddEntryToDir(ctx, "/dirToRemove1", podPassword, fileName, true)
 		if err != nil {
 			t.Fatal(err)
 		}
-		_, fileEntry, err := dirObject.ListDir("/dirToRemove1", podPassword)
+		_, fileEntry, err := dirObject.ListDir(ctx, "/dirToRemove1", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -242,13 +244,13 @@ func TestRmRootDirByPath(t *testing.T) {
 			t.Fatal("there should a file entry")
 		}
 		// now delete the root directory
-		err = dirObject.RmDir("/", podPassword)
+		err = dirObject.RmDir(ctx, "/", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// verify if the directory is actually removed
-		dirEntry, _, err = dirObject.ListDir("/", podPassword)
+		dirEntry, _, err = dirObject.ListDir(ctx, "/", podPassword)
 		if err != nil && !strings.HasSuffix(err.Error(), dir.ErrResourceDeleted.Error()) {
 			t.Fatal("root directory was not deleted")
 		}
@@ -259,6 +261,7 @@ func TestRmRootDirByPath(t *testing.T) {
 }
 
 func TestRmRootDir(t *testing.T) {
+	ctx := context.Background()
 	mockClient := bm.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -284,23 +287,23 @@ func TestRmRootDir(t *testing.T) {
 		dirObject := dir.NewDirectory("pod1", mockClient, fd, user, mockFile, tm, logger)
 
 		// make root dir so that other directories can be added
-		err = dirObject.MkRootDir("pod1", podPassword, user, fd)
+		err = dirObject.MkRootDir(ctx, "pod1", podPassword, user, fd)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// create a new dir
-		err := dirObject.MkDir("/dirToRemove1", podPassword)
+		err := dirObject.MkDir(ctx, "/dirToRemove1", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 		// create a new dir
-		err = dirObject.MkDir("/dirToRemove1/dirToRemove2", podPassword)
+		err = dirObject.MkDir(ctx, "/dirToRemove1/dirToRemove2", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 		// create a new dir
-		err = dirObject.MkDir("/dirToRemove1/dirToRemove2/dirToRemove", podPassword)
+		err = dirObject.MkDir(ctx, "/dirToRemove1/dirToRemove2/dirToRemove", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -310,14 +313,14 @@ func TestRmRootDir(t *testing.T) {
 		}
 
 		// make sure directories were created
-		dirEntry, _, err := dirObject.ListDir("/dirToRemove1", podPassword)
+		dirEntry, _, err := dirObject.ListDir(ctx, "/dirToRemove1", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 		if dirEntry == nil {
 			t.Fatal("nested directory \"/dirToRemove1/dirToRemove2\" was not created")
 		}
-		dirEntry, _, err = dirObject.ListDir("/dirToRemove1/dirToRemove2", podPassword)
+		dirEntry, _, err = dirObject.ListDir(ctx, "/dirToRemove1/dirToRemove2", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -326,11 +329,11 @@ func TestRmRootDir(t *testing.T) {
 		}
 
 		fileName := "file1"
-		err = dirObject.AddEntryToDir("/", podPassword, fileName, true)
+		err = dirObject.AddEntryToDir(ctx, "/", podPassword, fileName, true)
 		if err != nil {
 			t.Fatal(err)
 		}
-		_, fileEntry, err := dirObject.ListDir("/", podPassword)
+		_, fileEntry, err := dirObject.ListDir(ctx, "/", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -339,13 +342,13 @@ func TestRmRootDir(t *testing.T) {
 		}
 
 		// now delete the root directory
-		err = dirObject.RmRootDir(podPassword)
+		err = dirObject.RmRootDir(ctx, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// verify if the directory is actually removed
-		dirEntry, _, err = dirObject.ListDir("/", podPassword)
+		dirEntry, _, err = dirObject.ListDir(ctx, "/", podPassword)
 		if err != nil && !strings.HasSuffix(err.Error(), dir.ErrResourceDeleted.Error()) {
 			t.Fatal("root directory was not deleted")
 		}
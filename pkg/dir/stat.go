@@ -17,6 +17,7 @@ limitations under the License.
 package dir
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strconv"
@@ -27,21 +28,22 @@ import (
 
 // Stats represents a given directory
 type Stats struct {
-	PodName          string `json:"podName"`
-	DirPath          string `json:"dirPath"`
-	DirName          string `json:"dirName"`
-	Mode             uint32 `json:"mode"`
-	CreationTime     string `json:"creationTime"`
-	ModificationTime string `json:"modificationTime"`
-	AccessTime       string `json:"accessTime"`
-	NoOfDirectories  string `json:"noOfDirectories"`
-	NoOfFiles        string `json:"noOfFiles"`
+	PodName          string            `json:"podName"`
+	DirPath          string            `json:"dirPath"`
+	DirName          string            `json:"dirName"`
+	Mode             uint32            `json:"mode"`
+	CreationTime     string            `json:"creationTime"`
+	ModificationTime string            `json:"modificationTime"`
+	AccessTime       string            `json:"accessTime"`
+	NoOfDirectories  string            `json:"noOfDirectories"`
+	NoOfFiles        string            `json:"noOfFiles"`
+	Xattrs           map[string]string `json:"xattrs,omitempty"`
 }
 
 // DirStat returns all the information related to a given directory.
-func (d *Directory) DirStat(podName, podPassword, dirNameWithPath string) (*Stats, error) {
+func (d *Directory) DirStat(ctx context.Context, podName, podPassword, dirNameWithPath string) (*Stats, error) {
 	topic := utils.HashString(dirNameWithPath)
-	_, data, err := d.fd.GetFeedData(topic, d.getAddress(), []byte(podPassword))
+	_, data, err := d.fd.GetFeedData(ctx, topic, d.getAddress(), []byte(podPassword))
 	if err != nil { // skipcq: TCV-001
 		return nil, fmt.Errorf("dir stat: %v", err)
 	}
@@ -80,5 +82,6 @@ func (d *Directory) DirStat(podName, podPassword, dirNameWithPath string) (*Stat
 		AccessTime:       strconv.FormatInt(meta.AccessTime, 10),
 		NoOfDirectories:  strconv.FormatInt(int64(dirs), 10),
 		NoOfFiles:        strconv.FormatInt(int64(files), 10),
+		Xattrs:           utils.CopyXattrs(meta.Xattrs),
 	}, nil
 }
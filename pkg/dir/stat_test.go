@@ -38,6 +38,7 @@ import (
 )
 
 func TestStat(t *testing.T) {
+	ctx := context.Background()
 	mockClient := bm.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -62,36 +63,36 @@ func TestStat(t *testing.T) {
 		dirObject := dir.NewDirectory("pod1", mockClient, fd, user, mockFile, tm, logger)
 
 		// make root dir so that other directories can be added
-		err = dirObject.MkRootDir("pod1", podPassword, user, fd)
+		err = dirObject.MkRootDir(ctx, "pod1", podPassword, user, fd)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// populate the directory with few directory and files
-		err := dirObject.MkDir("/dirToStat", podPassword)
+		err := dirObject.MkDir(ctx, "/dirToStat", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = dirObject.MkDir("/dirToStat/subDir1", podPassword)
+		err = dirObject.MkDir(ctx, "/dirToStat/subDir1", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = dirObject.MkDir("/dirToStat/subDir2", podPassword)
+		err = dirObject.MkDir(ctx, "/dirToStat/subDir2", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 		// just add dummy file entry as file listing is not tested here
-		err = dirObject.AddEntryToDir("/dirToStat", podPassword, "file1", true)
+		err = dirObject.AddEntryToDir(ctx, "/dirToStat", podPassword, "file1", true)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = dirObject.AddEntryToDir("/dirToStat", podPassword, "file2", true)
+		err = dirObject.AddEntryToDir(ctx, "/dirToStat", podPassword, "file2", true)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// stat the directory
-		dirStats, err := dirObject.DirStat("pod1", podPassword, "/dirToStat")
+		dirStats, err := dirObject.DirStat(ctx, "pod1", podPassword, "/dirToStat")
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -116,12 +117,12 @@ func TestStat(t *testing.T) {
 			t.Fatalf("invalid files count")
 		}
 
-		err = dirObject.RmDir("/dirToStat", podPassword)
+		err = dirObject.RmDir(ctx, "/dirToStat", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		_, err = dirObject.DirStat("pod1", podPassword, "/dirToStat")
+		_, err = dirObject.DirStat(ctx, "pod1", podPassword, "/dirToStat")
 		if !errors.Is(err, dir.ErrDirectoryNotPresent) {
 			t.Fatal("dir should not be present")
 		}
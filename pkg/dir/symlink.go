@@ -0,0 +1,205 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dir
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+)
+
+const (
+	//S_IFLNK
+	S_IFLNK = 0120000
+	//MineTypeSymlink
+	MineTypeSymlink = "inode/symlink"
+	// MaxLinkHops is the number of symbolic links a path lookup follows before it
+	// gives up, which ends the lookups of paths with a link loop
+	MaxLinkHops = 40
+	// MaxLinkTargetLength is the longest target path a symbolic link can have
+	MaxLinkTargetLength = 1024
+
+	linkMode   = S_IFLNK | 0777
+	linkPrefix = "_L_"
+)
+
+// Link is a symbolic link inside a directory. The target is a path in the pod, relative
+// to the directory of the link if it does not start with a separator.
+type Link struct {
+	Target       string `json:"target"`
+	CreationTime int64  `json:"creationTime"`
+}
+
+// LinkStats represents a given symbolic link, not the entry it points to
+type LinkStats struct {
+	PodName      string `json:"podName"`
+	LinkPath     string `json:"linkPath"`
+	LinkName     string `json:"linkName"`
+	Target       string `json:"target"`
+	Mode         uint32 `json:"mode"`
+	CreationTime string `json:"creationTime"`
+}
+
+// Symlink creates a symbolic link to the target. The target does not need to exist.
+func (d *Directory) Symlink(ctx context.Context, target, linkWithPath, podPassword string) error {
+	parentPath, linkName, err := d.splitLinkPath(linkWithPath)
+	if err != nil {
+		return err
+	}
+	if target == "" || len(target) > MaxLinkTargetLength {
+		return ErrInvalidLinkTarget
+	}
+	target = filepath.ToSlash(target)
+
+	return d.modifyInode(ctx, parentPath, podPassword, func(parentInode *Inode) error {
+		for _, fileOrDirName := range parentInode.FileOrDirNames {
+			if fileOrDirName == "_F_"+linkName || fileOrDirName == "_D_"+linkName || fileOrDirName == linkPrefix+linkName {
+				return ErrLinkAlreadyPresent
+			}
+		}
+		now := time.Now().Unix()
+		if parentInode.Links == nil {
+			parentInode.Links = map[string]*Link{}
+		}
+		parentInode.Links[linkName] = &Link{
+			Target:       target,
+			CreationTime: now,
+		}
+		parentInode.FileOrDirNames = append(parentInode.FileOrDirNames, linkPrefix+linkName)
+		parentInode.Meta.ModificationTime = now
+		return nil
+	})
+}
+
+// RemoveSymlink removes a symbolic link, leaving its target as it is
+func (d *Directory) RemoveSymlink(ctx context.Context, linkWithPath, podPassword string) error {
+	parentPath, linkName, err := d.splitLinkPath(linkWithPath)
+	if err != nil {
+		return err
+	}
+	return d.modifyInode(ctx, parentPath, podPassword, func(parentInode *Inode) error {
+		if parentInode.Links[linkName] == nil {
+			return ErrLinkNotPresent
+		}
+		delete(parentInode.Links, linkName)
+		var fileNames []string
+		for _, fileOrDirName := range parentInode.FileOrDirNames {
+			if fileOrDirName != linkPrefix+linkName {
+				fileNames = append(fileNames, fileOrDirName)
+			}
+		}
+		parentInode.FileOrDirNames = fileNames
+		parentInode.Meta.ModificationTime = time.Now().Unix()
+		return nil
+	})
+}
+
+// LinkStat returns the information of a symbolic link itself, like lstat. The links in
+// the directories of the path are followed, the link at the end of it is not.
+func (d *Directory) LinkStat(podName, linkWithPath string) (*LinkStats, error) {
+	resolved, err := d.resolve(linkWithPath, false)
+	if err != nil {
+		return nil, err
+	}
+	parentPath, linkName := path.Split(resolved)
+	parentPath = path.Clean(parentPath)
+	link := d.link(parentPath, linkName)
+	if link == nil {
+		return nil, ErrLinkNotPresent
+	}
+	return &LinkStats{
+		PodName:      podName,
+		LinkPath:     parentPath,
+		LinkName:     linkName,
+		Target:       link.Target,
+		Mode:         linkMode,
+		CreationTime: strconv.FormatInt(link.CreationTime, 10),
+	}, nil
+}
+
+// ResolvePath returns the path of the entry a path refers to, once the symbolic links
+// in it are followed. The entry itself does not need to exist.
+func (d *Directory) ResolvePath(podPath string) (string, error) {
+	return d.resolve(podPath, true)
+}
+
+func (d *Directory) resolve(podPath string, followLast bool) (string, error) {
+	hops := 0
+	resolved := utils.PathSeparator
+	names := splitPath(podPath)
+	for len(names) > 0 {
+		name := names[0]
+		names = names[1:]
+		link := d.link(resolved, name)
+		if link == nil || (len(names) == 0 && !followLast) {
+			resolved = path.Join(resolved, name)
+			continue
+		}
+		hops++
+		if hops > MaxLinkHops {
+			return "", ErrTooManyLinks
+		}
+
+		// the lookup starts again from the root with the target in front of the rest
+		target := link.Target
+		if !path.IsAbs(target) {
+			target = path.Join(resolved, target)
+		}
+		names = append(splitPath(target), names...)
+		resolved = utils.PathSeparator
+	}
+	return resolved, nil
+}
+
+// link returns the symbolic link of the given name in a directory, nil if there is none
+func (d *Directory) link(dirNameWithPath, name string) *Link {
+	dirInode := d.GetDirFromDirectoryMap(dirNameWithPath)
+	if dirInode == nil {
+		return nil
+	}
+	return dirInode.Links[name]
+}
+
+func (d *Directory) splitLinkPath(linkWithPath string) (string, string, error) {
+	parentPath, linkName := path.Split(path.Clean(utils.PathSeparator + filepath.ToSlash(linkWithPath)))
+	if linkName == "" {
+		return "", "", ErrInvalidFileOrDirectoryName
+	}
+	if len(linkName) > nameLength {
+		return "", "", ErrTooLongDirectoryName
+	}
+	parentPath = path.Clean(parentPath)
+	if d.GetDirFromDirectoryMap(parentPath) == nil {
+		return "", "", fmt.Errorf("symlink: %w", ErrDirectoryNotPresent)
+	}
+	return parentPath, linkName, nil
+}
+
+// splitPath returns the names in a path, without the empty ones
+func splitPath(podPath string) []string {
+	podPath = path.Clean(utils.PathSeparator + filepath.ToSlash(podPath))
+	if podPath == utils.PathSeparator {
+		return nil
+	}
+	return strings.Split(strings.TrimPrefix(podPath, utils.PathSeparator), utils.PathSeparator)
+}
@@ -0,0 +1,249 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dir_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/account"
+	bm "github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/bee/mock"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/dir"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
+	fm "github.com/fairdatasociety/fairOS-dfs/pkg/file/mock"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/pod"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+	"github.com/plexsysio/taskmanager"
+)
+
+func TestSymlink(t *testing.T) {
+	ctx := context.Background()
+	mockClient := bm.NewMockBeeClient()
+	logger := logging.New(io.Discard, 0)
+	acc := account.New(logger)
+	_, _, err := acc.CreateUserAccount("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pod1AccountInfo, err := acc.CreatePodAccount(1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fd := feed.New(pod1AccountInfo, mockClient, logger)
+	user := acc.GetAddress(1)
+	mockFile := fm.NewMockFile()
+	tm := taskmanager.New(1, 10, time.Second*15, logger)
+	defer func() {
+		_ = tm.Stop(context.Background())
+	}()
+	podPassword, _ := utils.GetRandString(pod.PasswordLength)
+	dirObject := dir.NewDirectory("pod1", mockClient, fd, user, mockFile, tm, logger)
+	err = dirObject.MkRootDir(ctx, "pod1", podPassword, user, fd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dirPath := range []string{"/releases", "/releases/v1", "/releases/v2", "/releases/v2/docs"} {
+		err = dirObject.MkDir(ctx, dirPath, podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = dirObject.AddEntryToDir(ctx, "/releases/v2", podPassword, "file1", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("resolve", func(t *testing.T) {
+		err := dirObject.Symlink(ctx, "/releases/v2", "/latest", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// relative targets are relative to the directory of the link
+		err = dirObject.Symlink(ctx, "v1", "/releases/previous", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = dirObject.Symlink(ctx, "../latest/docs", "/releases/docs", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// a link to a link
+		err = dirObject.Symlink(ctx, "latest", "/current", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for podPath, expected := range map[string]string{
+			"/":                   "/",
+			"/releases/v1":        "/releases/v1",
+			"/latest":             "/releases/v2",
+			"/latest/":            "/releases/v2",
+			"/latest/file1":       "/releases/v2/file1",
+			"/releases/previous":  "/releases/v1",
+			"/releases/docs":      "/releases/v2/docs",
+			"/current/docs":       "/releases/v2/docs",
+			"/latest/missing/any": "/releases/v2/missing/any",
+		} {
+			resolved, err := dirObject.ResolvePath(podPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resolved != expected {
+				t.Fatalf("%s resolved to %s instead of %s", podPath, resolved, expected)
+			}
+		}
+	})
+
+	t.Run("loop", func(t *testing.T) {
+		err := dirObject.Symlink(ctx, "/loop2", "/loop1", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = dirObject.Symlink(ctx, "/loop1", "/loop2", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = dirObject.ResolvePath("/loop1/file1")
+		if !errors.Is(err, dir.ErrTooManyLinks) {
+			t.Fatalf("expected %v, got %v", dir.ErrTooManyLinks, err)
+		}
+
+		// the link itself can still be looked at
+		stat, err := dirObject.LinkStat("pod1", "/loop1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stat.Target != "/loop2" {
+			t.Fatalf("invalid link stat %+v", stat)
+		}
+	})
+
+	t.Run("stat", func(t *testing.T) {
+		// the links of the parents are followed, the last one is not
+		_, err := dirObject.LinkStat("pod1", "/current/docs")
+		if !errors.Is(err, dir.ErrLinkNotPresent) {
+			t.Fatalf("expected %v, got %v", dir.ErrLinkNotPresent, err)
+		}
+		stat, err := dirObject.LinkStat("pod1", "/current")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stat.PodName != "pod1" || stat.LinkPath != "/" || stat.LinkName != "current" || stat.Target != "latest" || stat.Mode != dir.S_IFLNK|0777 {
+			t.Fatalf("invalid link stat %+v", stat)
+		}
+		_, err = dirObject.LinkStat("pod1", "/releases/v1")
+		if !errors.Is(err, dir.ErrLinkNotPresent) {
+			t.Fatalf("expected %v, got %v", dir.ErrLinkNotPresent, err)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		err := dirObject.Symlink(ctx, "/releases/v1", "/releases/v2", podPassword)
+		if !errors.Is(err, dir.ErrLinkAlreadyPresent) {
+			t.Fatalf("expected %v, got %v", dir.ErrLinkAlreadyPresent, err)
+		}
+		err = dirObject.Symlink(ctx, "/releases/v1", "/releases/v2/file1", podPassword)
+		if !errors.Is(err, dir.ErrLinkAlreadyPresent) {
+			t.Fatalf("expected %v, got %v", dir.ErrLinkAlreadyPresent, err)
+		}
+		err = dirObject.Symlink(ctx, "/releases/v1", "/latest", podPassword)
+		if !errors.Is(err, dir.ErrLinkAlreadyPresent) {
+			t.Fatalf("expected %v, got %v", dir.ErrLinkAlreadyPresent, err)
+		}
+		err = dirObject.Symlink(ctx, "", "/empty", podPassword)
+		if !errors.Is(err, dir.ErrInvalidLinkTarget) {
+			t.Fatalf("expected %v, got %v", dir.ErrInvalidLinkTarget, err)
+		}
+		err = dirObject.Symlink(ctx, "/releases/v1", "/missing/link", podPassword)
+		if !errors.Is(err, dir.ErrDirectoryNotPresent) {
+			t.Fatalf("expected %v, got %v", dir.ErrDirectoryNotPresent, err)
+		}
+		err = dirObject.MkDir(ctx, "/latest", podPassword)
+		if !errors.Is(err, dir.ErrDirectoryAlreadyPresent) {
+			t.Fatalf("expected %v, got %v", dir.ErrDirectoryAlreadyPresent, err)
+		}
+	})
+
+	t.Run("ls", func(t *testing.T) {
+		// the links are kept with the directory
+		dirObject2 := dir.NewDirectory("pod1", mockClient, fd, user, mockFile, tm, logger)
+		err := dirObject2.AddRootDir(ctx, "pod1", podPassword, user, fd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = dirObject2.SyncDirectory(ctx, "/", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resolved, err := dirObject2.ResolvePath("/current/docs")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resolved != "/releases/v2/docs" {
+			t.Fatalf("resolved to %s", resolved)
+		}
+
+		dirs, _, err := dirObject2.ListDir(ctx, "/releases", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		links := map[string]string{}
+		for _, entry := range dirs {
+			if entry.ContentType == dir.MineTypeSymlink {
+				links[entry.Name] = entry.Target
+			}
+		}
+		if len(dirs) != 4 || len(links) != 2 || links["previous"] != "v1" || links["docs"] != "../latest/docs" {
+			t.Fatalf("invalid entries %v", dirs)
+		}
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		err := dirObject.RemoveSymlink(ctx, "/latest", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = dirObject.RemoveSymlink(ctx, "/latest", podPassword)
+		if !errors.Is(err, dir.ErrLinkNotPresent) {
+			t.Fatalf("expected %v, got %v", dir.ErrLinkNotPresent, err)
+		}
+		err = dirObject.RemoveSymlink(ctx, "/releases/v1", podPassword)
+		if !errors.Is(err, dir.ErrLinkNotPresent) {
+			t.Fatalf("expected %v, got %v", dir.ErrLinkNotPresent, err)
+		}
+
+		// the links to the removed link dangle, the target is kept
+		resolved, err := dirObject.ResolvePath("/current")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resolved != "/latest" {
+			t.Fatalf("resolved to %s", resolved)
+		}
+		if dirObject.GetDirFromDirectoryMap("/releases/v2") == nil {
+			t.Fatal("the target of the link should be kept")
+		}
+		err = dirObject.MkDir(ctx, "/latest", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -25,9 +25,9 @@ import (
 )
 
 // SyncDirectory syncs all the latest entries under a given directory.
-func (d *Directory) SyncDirectory(dirNameWithPath, podPassword string) error {
+func (d *Directory) SyncDirectory(ctx context.Context, dirNameWithPath, podPassword string) error {
 	topic := utils.HashString(utils.CombinePathAndFile(dirNameWithPath, ""))
-	_, data, err := d.fd.GetFeedData(topic, d.userAddress, []byte(podPassword))
+	_, data, err := d.fd.GetFeedData(ctx, topic, d.userAddress, []byte(podPassword))
 	if err != nil { // skipcq: TCV-001
 		return nil // pod is empty
 	}
@@ -43,7 +43,7 @@ func (d *Directory) SyncDirectory(dirNameWithPath, podPassword string) error {
 		if strings.HasPrefix(fileOrDirName, "_F_") {
 			fileName := strings.TrimPrefix(fileOrDirName, "_F_")
 			filePath := utils.CombinePathAndFile(dirNameWithPath, fileName)
-			err := d.file.LoadFileMeta(filePath, podPassword)
+			err := d.file.LoadFileMeta(ctx, filePath, podPassword)
 			if err != nil { // skipcq: TCV-001
 				d.logger.Errorf("loading metadata failed %s: %s", filePath, err.Error())
 			}
@@ -52,7 +52,7 @@ func (d *Directory) SyncDirectory(dirNameWithPath, podPassword string) error {
 			path := utils.CombinePathAndFile(dirNameWithPath, dirName)
 			d.logger.Infof(dirNameWithPath)
 
-			err = d.SyncDirectory(path, podPassword)
+			err = d.SyncDirectory(ctx, path, podPassword)
 			if err != nil { // skipcq: TCV-001
 				return err
 			}
@@ -64,7 +64,7 @@ func (d *Directory) SyncDirectory(dirNameWithPath, podPassword string) error {
 // SyncDirectoryAsync syncs all the latest entries under a given directory concurrently.
 func (d *Directory) SyncDirectoryAsync(ctx context.Context, dirNameWithPath, podPassword string, wg *sync.WaitGroup) error {
 	topic := utils.HashString(utils.CombinePathAndFile(dirNameWithPath, ""))
-	_, data, err := d.fd.GetFeedData(topic, d.userAddress, []byte(podPassword))
+	_, data, err := d.fd.GetFeedData(ctx, topic, d.userAddress, []byte(podPassword))
 	if err != nil { // skipcq: TCV-001
 		return nil // pod is empty
 	}
@@ -82,7 +82,7 @@ func (d *Directory) SyncDirectoryAsync(ctx context.Context, dirNameWithPath, pod
 			wg.Add(1)
 			fileName := strings.TrimPrefix(fileOrDirName, "_F_")
 			filePath := utils.CombinePathAndFile(dirNameWithPath, fileName)
-			syncTask := newSyncTask(d, filePath, podPassword, wg)
+			syncTask := newSyncTask(ctx, d, filePath, podPassword, wg)
 			_, err = d.syncManager.Go(syncTask)
 			if err != nil { // skipcq: TCV-001
 				return err
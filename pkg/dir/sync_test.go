@@ -40,6 +40,7 @@ func TestMain(m *testing.M) {
 }
 
 func TestSync(t *testing.T) {
+	ctx := context.Background()
 	mockClient := bm.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -64,30 +65,30 @@ func TestSync(t *testing.T) {
 		dirObject := dir.NewDirectory("pod1", mockClient, fd, user, mockFile, tm, logger)
 
 		// make root dir so that other directories can be added
-		err = dirObject.MkRootDir("pod1", podPassword, user, fd)
+		err = dirObject.MkRootDir(ctx, "pod1", podPassword, user, fd)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// populate the directory with few directory and files
-		err := dirObject.MkDir("/dirToStat", podPassword)
+		err := dirObject.MkDir(ctx, "/dirToStat", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = dirObject.MkDir("/dirToStat/subDir1", podPassword)
+		err = dirObject.MkDir(ctx, "/dirToStat/subDir1", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = dirObject.MkDir("/dirToStat/subDir2", podPassword)
+		err = dirObject.MkDir(ctx, "/dirToStat/subDir2", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 		// just add dummy file entry as file listing is not tested here
-		err = dirObject.AddEntryToDir("/dirToStat", podPassword, "file1", true)
+		err = dirObject.AddEntryToDir(ctx, "/dirToStat", podPassword, "file1", true)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = dirObject.AddEntryToDir("/dirToStat", podPassword, "file2", true)
+		err = dirObject.AddEntryToDir(ctx, "/dirToStat", podPassword, "file2", true)
 		if err != nil {
 			t.Fatal(err)
 		}
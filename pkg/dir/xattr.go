@@ -0,0 +1,79 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dir
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+)
+
+// Xattrs returns the extended attributes of a directory
+func (d *Directory) Xattrs(dirNameWithPath string) (map[string]string, error) {
+	dirInode := d.GetDirFromDirectoryMap(utils.CombinePathAndFile(dirNameWithPath, ""))
+	if dirInode == nil || dirInode.Meta == nil {
+		return nil, ErrDirectoryNotPresent
+	}
+	return utils.CopyXattrs(dirInode.Meta.Xattrs), nil
+}
+
+// SetXattr sets an extended attribute of a directory
+func (d *Directory) SetXattr(ctx context.Context, dirNameWithPath, podPassword, name, value string) error {
+	return d.modifyXattrs(ctx, dirNameWithPath, podPassword, func(xattrs map[string]string) (map[string]string, error) {
+		return utils.SetXattr(xattrs, name, value)
+	})
+}
+
+// RemoveXattr removes an extended attribute of a directory
+func (d *Directory) RemoveXattr(ctx context.Context, dirNameWithPath, podPassword, name string) error {
+	return d.modifyXattrs(ctx, dirNameWithPath, podPassword, func(xattrs map[string]string) (map[string]string, error) {
+		return utils.RemoveXattr(xattrs, name)
+	})
+}
+
+// SetXattrs replaces all the extended attributes of a directory
+func (d *Directory) SetXattrs(ctx context.Context, dirNameWithPath, podPassword string, xattrs map[string]string) error {
+	err := utils.CheckXattrs(xattrs)
+	if err != nil {
+		return err
+	}
+	return d.modifyXattrs(ctx, dirNameWithPath, podPassword, func(map[string]string) (map[string]string, error) {
+		return utils.CopyXattrs(xattrs), nil
+	})
+}
+
+func (d *Directory) modifyXattrs(ctx context.Context, dirNameWithPath, podPassword string, change func(xattrs map[string]string) (map[string]string, error)) error {
+	dirNameWithPath = utils.CombinePathAndFile(dirNameWithPath, "")
+	if d.GetDirFromDirectoryMap(dirNameWithPath) == nil {
+		return ErrDirectoryNotPresent
+	}
+	err := d.modifyInode(ctx, dirNameWithPath, podPassword, func(dirInode *Inode) error {
+		xattrs, err := change(dirInode.Meta.Xattrs)
+		if err != nil {
+			return err
+		}
+		dirInode.Meta.Xattrs = xattrs
+		dirInode.Meta.AccessTime = time.Now().Unix()
+		return nil
+	})
+	if err != nil && err != ErrDirectoryNotPresent { // skipcq: TCV-001
+		return fmt.Errorf("dir xattr: %w", err)
+	}
+	return err
+}
@@ -0,0 +1,138 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dir_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/account"
+	bm "github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/bee/mock"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/dir"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
+	fm "github.com/fairdatasociety/fairOS-dfs/pkg/file/mock"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/pod"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+	"github.com/plexsysio/taskmanager"
+)
+
+func TestXattr(t *testing.T) {
+	ctx := context.Background()
+	mockClient := bm.NewMockBeeClient()
+	logger := logging.New(io.Discard, 0)
+	acc := account.New(logger)
+	_, _, err := acc.CreateUserAccount("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pod1AccountInfo, err := acc.CreatePodAccount(1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fd := feed.New(pod1AccountInfo, mockClient, logger)
+	user := acc.GetAddress(1)
+	mockFile := fm.NewMockFile()
+	tm := taskmanager.New(1, 10, time.Second*15, logger)
+	defer func() {
+		_ = tm.Stop(context.Background())
+	}()
+	podPassword, _ := utils.GetRandString(pod.PasswordLength)
+	dirObject := dir.NewDirectory("pod1", mockClient, fd, user, mockFile, tm, logger)
+	err = dirObject.MkRootDir(ctx, "pod1", podPassword, user, fd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dirObject.MkDir(ctx, "/parentDir", podPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dirObject.MkDir(ctx, "/parentDir/subDir1", podPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = dirObject.SetXattr(ctx, "/parentDir/subDir1", podPassword, "user.tag", "blue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dirObject.SetXattr(ctx, "/parentDir/subDir1", podPassword, "user.owner", "me")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dirObject.RemoveXattr(ctx, "/parentDir/subDir1", podPassword, "user.owner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dirObject.RemoveXattr(ctx, "/parentDir/subDir1", podPassword, "user.owner")
+	if !errors.Is(err, utils.ErrXattrNotFound) {
+		t.Fatalf("expected %v, got %v", utils.ErrXattrNotFound, err)
+	}
+	err = dirObject.SetXattr(ctx, "/parentDir/subDir1", podPassword, "", "value")
+	if !errors.Is(err, utils.ErrInvalidXattrName) {
+		t.Fatalf("expected %v, got %v", utils.ErrInvalidXattrName, err)
+	}
+	err = dirObject.SetXattr(ctx, "/parentDir/subDir2", podPassword, "user.tag", "blue")
+	if !errors.Is(err, dir.ErrDirectoryNotPresent) {
+		t.Fatalf("expected %v, got %v", dir.ErrDirectoryNotPresent, err)
+	}
+
+	xattrs, err := dirObject.Xattrs("/parentDir/subDir1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(xattrs) != 1 || xattrs["user.tag"] != "blue" {
+		t.Fatalf("unexpected xattrs %v", xattrs)
+	}
+	stats, err := dirObject.DirStat(ctx, "pod1", podPassword, "/parentDir/subDir1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats.Xattrs) != 1 || stats.Xattrs["user.tag"] != "blue" {
+		t.Fatalf("unexpected stats xattrs %v", stats.Xattrs)
+	}
+
+	// the attributes are stored with the metadata of the directory
+	dirObject2 := dir.NewDirectory("pod1", mockClient, fd, user, mockFile, tm, logger)
+	err = dirObject2.AddRootDir(ctx, "pod1", podPassword, user, fd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dirs, _, err := dirObject2.ListDir(ctx, "/parentDir", podPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dirs) != 1 || dirs[0].Xattrs["user.tag"] != "blue" {
+		t.Fatalf("unexpected entries %v", dirs)
+	}
+
+	// and they move with the directory
+	err = dirObject.RenameDir(ctx, "/parentDir/subDir1", "/parentDir/renamed", podPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xattrs, err = dirObject.Xattrs("/parentDir/renamed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if xattrs["user.tag"] != "blue" {
+		t.Fatalf("unexpected xattrs %v", xattrs)
+	}
+}
@@ -53,6 +53,7 @@ var (
 type API struct {
 	handler     *Handler
 	accountInfo *account.Info
+	feedType    Type
 	logger      logging.Logger
 }
 
@@ -69,30 +70,82 @@ type request struct {
 
 // New create the main feed object which is used to create/update/delete feeds.
 func New(accountInfo *account.Info, client blockstore.Client, logger logging.Logger) *API {
+	return NewWithType(accountInfo, client, EpochFeed, logger)
+}
+
+// NewWithType creates the feed object for feeds of the given type
+func NewWithType(accountInfo *account.Info, client blockstore.Client, feedType Type, logger logging.Logger) *API {
 	bmtPool := bmtlegacy.NewTreePool(hashFunc, swarm.Branches, bmtlegacy.PoolSize)
 	return &API{
 		handler:     NewHandler(accountInfo, client, bmtPool),
 		accountInfo: accountInfo,
+		feedType:    feedType,
 		logger:      logger,
 	}
 }
 
+// Type returns the type of the feeds
+func (a *API) Type() Type {
+	return a.feedType
+}
+
 // CreateFeed creates a feed by constructing a single owner chunk. This chunk
 // can only be accessed if the pod address is known. Also, no one else can spoof this
 // chunk since this is signed by the pod.
-func (a *API) CreateFeed(topic []byte, user utils.Address, data []byte, encryptionPassword []byte) ([]byte, error) {
+func (a *API) CreateFeed(ctx context.Context, topic []byte, user utils.Address, data []byte, encryptionPassword []byte) ([]byte, error) {
+	if a.feedType == SequenceFeed {
+		return a.updateSequence(ctx, topic, user, data, encryptionPassword, nil)
+	}
+	update, err := a.newFeedSOC(topic, user, data, encryptionPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	// send the updated soc chunk to bee
+	address, err := a.handler.update(ctx, update)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+
+	return address, nil
+}
+
+// CreateFeeds creates many feeds like CreateFeed, but uploads all of their single owner
+// chunks in one batch. The addresses are returned in the order of the topics.
+func (a *API) CreateFeeds(ctx context.Context, topics [][]byte, user utils.Address, data [][]byte, encryptionPassword []byte) ([][]byte, error) {
+	if len(topics) != len(data) {
+		return nil, fmt.Errorf("got %d topics for %d payloads", len(topics), len(data))
+	}
+	if a.feedType == SequenceFeed {
+		return a.createSequences(ctx, topics, user, data, encryptionPassword)
+	}
+	updates := make([]blockstore.SOC, len(topics))
+	for i, topic := range topics {
+		update, err := a.newFeedSOC(topic, user, data[i], encryptionPassword)
+		if err != nil {
+			return nil, err
+		}
+		updates[i] = update
+	}
+
+	// send the soc chunks to bee
+	return a.handler.updateBatch(ctx, updates)
+}
+
+// newFeedSOC constructs the first single owner chunk of a feed
+func (a *API) newFeedSOC(topic []byte, user utils.Address, data []byte, encryptionPassword []byte) (blockstore.SOC, error) {
 	var req request
 
 	if a.accountInfo.GetPrivateKey() == nil {
-		return nil, ErrReadOnlyFeed
+		return blockstore.SOC{}, ErrReadOnlyFeed
 	}
 
 	if len(topic) != TopicLength {
-		return nil, ErrInvalidTopicSize
+		return blockstore.SOC{}, ErrInvalidTopicSize
 	}
 
 	if len(data) > utils.MaxChunkLength {
-		return nil, ErrInvalidPayloadSize
+		return blockstore.SOC{}, ErrInvalidPayloadSize
 	}
 
 	var err error
@@ -101,7 +154,7 @@ func (a *API) CreateFeed(topic []byte, user utils.Address, data []byte, encrypti
 	if encryptionPassword != nil { // skipcq: TCV-001
 		encryptedData, err = utils.EncryptBytes(encryptionPassword, data)
 		if err != nil { // skipcq: TCV-001
-			return nil, err
+			return blockstore.SOC{}, err
 		}
 	}
 
@@ -109,45 +162,45 @@ func (a *API) CreateFeed(topic []byte, user utils.Address, data []byte, encrypti
 	copy(req.ID.Topic[:], topic)
 	req.ID.User = user
 	req.Epoch.Level = 31
-	req.Epoch.Time = uint64(time.Now().Unix())
+	req.Epoch.Time = TimestampProvider.Now().Time
 
 	// Add initial feed data
 	req.data = encryptedData
 
 	// create the id, hash(topic, epoc)
-	id, err := a.handler.getId(req.Topic, req.Time, req.Level)
+	id, err := a.handler.getId(req.Topic, req.Time, req.Level, req.Sequence)
 	if err != nil { // skipcq: TCV-001
-		return nil, err
+		return blockstore.SOC{}, err
 	}
 
 	// get the payload id BMT(span, payload)
 	payloadId, err := a.handler.getPayloadId(encryptedData)
 	if err != nil { // skipcq: TCV-001
-		return nil, err
+		return blockstore.SOC{}, err
 	}
 
 	// create the signer and the content addressed chunk
 	signer := crypto.NewDefaultSigner(a.accountInfo.GetPrivateKey())
 	ch, err := utils.NewChunkWithSpan(encryptedData)
 	if err != nil { // skipcq: TCV-001
-		return nil, err
+		return blockstore.SOC{}, err
 	}
 	s := soc.New(id, ch)
 	sch, err := s.Sign(signer)
 	if err != nil { // skipcq: TCV-001
-		return nil, err
+		return blockstore.SOC{}, err
 	}
 
 	// generate the data to sign
 	toSignBytes, err := toSignDigest(id, ch.Address().Bytes())
 	if err != nil { // skipcq: TCV-001
-		return nil, err
+		return blockstore.SOC{}, err
 	}
 
 	// sign the chunk
 	signature, err := signer.Sign(toSignBytes)
 	if err != nil { // skipcq: TCV-001
-		return nil, err
+		return blockstore.SOC{}, err
 	}
 
 	// set the address and the data for the soc chunk
@@ -157,19 +210,13 @@ func (a *API) CreateFeed(topic []byte, user utils.Address, data []byte, encrypti
 	// set signature and binary data fields
 	_, err = a.handler.toChunkContent(&req, id, payloadId)
 	if err != nil { // skipcq: TCV-001
-		return nil, err
+		return blockstore.SOC{}, err
 	}
-	// send the updated soc chunk to bee
-	address, err := a.handler.update(id, user.ToBytes(), signature, ch.Data())
-	if err != nil { // skipcq: TCV-001
-		return nil, err
-	}
-
-	return address, nil
+	return newSOC(id, user.ToBytes(), signature, ch.Data()), nil
 }
 
 // CreateFeedFromTopic creates a soc with the topic as identifier
-func (a *API) CreateFeedFromTopic(topic []byte, user utils.Address, data []byte) ([]byte, error) {
+func (a *API) CreateFeedFromTopic(ctx context.Context, topic []byte, user utils.Address, data []byte) ([]byte, error) {
 	if a.accountInfo.GetPrivateKey() == nil {
 		return nil, ErrReadOnlyFeed
 	}
@@ -202,7 +249,7 @@ func (a *API) CreateFeedFromTopic(topic []byte, user utils.Address, data []byte)
 	}
 
 	// send the updated soc chunk to bee
-	address, err := a.handler.update(topic, user.ToBytes(), signature, ch.Data())
+	address, err := a.handler.update(ctx, newSOC(topic, user.ToBytes(), signature, ch.Data()))
 	if err != nil { // skipcq: TCV-001
 		return nil, err
 	}
@@ -211,8 +258,8 @@ func (a *API) CreateFeedFromTopic(topic []byte, user utils.Address, data []byte)
 }
 
 // GetSOCFromAddress will download the soc chunk for the given reference
-func (a *API) GetSOCFromAddress(address []byte) ([]byte, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+func (a *API) GetSOCFromAddress(ctx context.Context, address []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
 	defer cancel()
 	data, err := a.handler.client.DownloadChunk(ctx, address)
 	if err != nil {
@@ -223,15 +270,26 @@ func (a *API) GetSOCFromAddress(address []byte) ([]byte, error) {
 }
 
 // GetFeedData looks up feed from swarm
-func (a *API) GetFeedData(topic []byte, user utils.Address, encryptionPassword []byte) ([]byte, []byte, error) {
+func (a *API) GetFeedData(ctx context.Context, topic []byte, user utils.Address, encryptionPassword []byte) ([]byte, []byte, error) {
 	if len(topic) != TopicLength {
 		return nil, nil, ErrInvalidTopicSize
 	}
-	ctx := context.Background()
 	f := new(Feed)
 	f.User = user
 	copy(f.Topic[:], topic)
 
+	if a.feedType == SequenceFeed {
+		update, err := a.handler.lookupSequence(ctx, f)
+		if err != nil {
+			return nil, nil, err
+		}
+		data, err := decryptPayload(update.data, encryptionPassword)
+		if err != nil { // skipcq: TCV-001
+			return nil, nil, err
+		}
+		return update.address.Bytes(), data, nil
+	}
+
 	// create the query from values
 	q := &Query{Feed: *f}
 	q.TimeLimit = 0
@@ -255,7 +313,7 @@ func (a *API) GetFeedData(topic []byte, user utils.Address, encryptionPassword [
 }
 
 // GetFeedDataFromTopic will generate keccak256 reference of the topic+address and download soc
-func (a *API) GetFeedDataFromTopic(topic []byte, user utils.Address) ([]byte, []byte, error) {
+func (a *API) GetFeedDataFromTopic(ctx context.Context, topic []byte, user utils.Address) ([]byte, []byte, error) {
 	if len(topic) != TopicLength {
 		return nil, nil, ErrInvalidTopicSize
 	}
@@ -272,7 +330,7 @@ func (a *API) GetFeedDataFromTopic(topic []byte, user utils.Address) ([]byte, []
 	hash := h.Sum(nil)
 
 	// download soc from generated reference
-	data, err := a.GetSOCFromAddress(hash)
+	data, err := a.GetSOCFromAddress(ctx, hash)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -280,7 +338,23 @@ func (a *API) GetFeedDataFromTopic(topic []byte, user utils.Address) ([]byte, []
 }
 
 // UpdateFeed updates the contents of an already created feed.
-func (a *API) UpdateFeed(topic []byte, user utils.Address, data []byte, encryptionPassword []byte) ([]byte, error) {
+func (a *API) UpdateFeed(ctx context.Context, topic []byte, user utils.Address, data []byte, encryptionPassword []byte) ([]byte, error) {
+	return a.updateFeed(ctx, topic, user, data, encryptionPassword, nil)
+}
+
+// UpdateFeedIf updates a feed like UpdateFeed, but only if its latest update is the one at
+// the expected reference, as returned by GetFeedData. A nil reference expects a feed
+// without updates. If another writer has updated the feed meanwhile, the feed is not
+// changed and a *ConflictError is returned. Only the updates that are already retrievable
+// are seen, so this narrows the window for lost updates, but does not close it.
+func (a *API) UpdateFeedIf(ctx context.Context, topic []byte, user utils.Address, expected, data []byte, encryptionPassword []byte) ([]byte, error) {
+	return a.updateFeed(ctx, topic, user, data, encryptionPassword, &precondition{expected: expected})
+}
+
+func (a *API) updateFeed(ctx context.Context, topic []byte, user utils.Address, data []byte, encryptionPassword []byte, cond *precondition) ([]byte, error) {
+	if a.feedType == SequenceFeed {
+		return a.updateSequence(ctx, topic, user, data, encryptionPassword, cond)
+	}
 	if a.accountInfo.GetPrivateKey() == nil {
 		return nil, ErrReadOnlyFeed
 	}
@@ -303,21 +377,24 @@ func (a *API) UpdateFeed(topic []byte, user utils.Address, data []byte, encrypti
 		}
 	}
 
-	ctx := context.Background()
 	f := new(Feed)
 	f.User = user
 	copy(f.Topic[:], topic)
 
 	// get the existing request from DB
-	req, err := a.handler.newRequest(ctx, f)
+	req, latest, err := a.handler.newRequest(ctx, f)
 	if err != nil { // skipcq: TCV-001
 		return nil, err
 	}
-	req.Time = uint64(time.Now().Unix())
+	err = cond.check(latest)
+	if err != nil {
+		return nil, err
+	}
+	req.Time = TimestampProvider.Now().Time
 	req.data = encryptedData
 
 	// create the id, hash(topic, epoc)
-	id, err := a.handler.getId(req.Topic, req.Time, req.Level)
+	id, err := a.handler.getId(req.Topic, req.Time, req.Level, req.Sequence)
 	if err != nil { // skipcq: TCV-001
 		return nil, err
 	}
@@ -362,7 +439,7 @@ func (a *API) UpdateFeed(topic []byte, user utils.Address, data []byte, encrypti
 		return nil, err
 	}
 
-	address, err := a.handler.update(id, user.ToBytes(), signature, ch.Data())
+	address, err := a.handler.update(ctx, newSOC(id, user.ToBytes(), signature, ch.Data()))
 	if err != nil { // skipcq: TCV-001
 		return nil, err
 	}
@@ -370,36 +447,43 @@ func (a *API) UpdateFeed(topic []byte, user utils.Address, data []byte, encrypti
 }
 
 // DeleteFeed deleted the feed by updating with no data inside the SOC chunk.
-func (a *API) DeleteFeed(topic []byte, user utils.Address) error {
+func (a *API) DeleteFeed(ctx context.Context, topic []byte, user utils.Address) error {
 	if a.accountInfo.GetPrivateKey() == nil {
 		return ErrReadOnlyFeed
 	}
 
-	delRef, _, err := a.GetFeedData(topic, user, nil)
+	delRef, _, err := a.GetFeedData(ctx, topic, user, nil)
 	if err != nil && err.Error() != "feed does not exist or was not updated yet" { // skipcq: TCV-001
 		return err
 	}
 	if delRef != nil {
-		err = a.handler.deleteChunk(delRef)
+		err = a.handler.deleteChunk(ctx, delRef)
 		if err != nil { // skipcq: TCV-001
 			return err
 		}
 	}
+	if a.feedType == SequenceFeed {
+		// the previous update is the latest again
+		f := new(Feed)
+		f.User = user
+		copy(f.Topic[:], topic)
+		return a.handler.setIndex(f, nil)
+	}
 	return nil
 }
 
 // DeleteFeedFromTopic deleted the feed by updating with no data inside the SOC chunk.
-func (a *API) DeleteFeedFromTopic(topic []byte, user utils.Address) error {
+func (a *API) DeleteFeedFromTopic(ctx context.Context, topic []byte, user utils.Address) error {
 	if a.accountInfo.GetPrivateKey() == nil {
 		return ErrReadOnlyFeed
 	}
 
-	delRef, _, err := a.GetFeedDataFromTopic(topic, user)
+	delRef, _, err := a.GetFeedDataFromTopic(ctx, topic, user)
 	if err != nil && err.Error() != "feed does not exist or was not updated yet" { // skipcq: TCV-001
 		return err
 	}
 	if delRef != nil {
-		err = a.handler.deleteChunk(delRef)
+		err = a.handler.deleteChunk(ctx, delRef)
 		if err != nil { // skipcq: TCV-001
 			return err
 		}
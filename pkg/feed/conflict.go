@@ -0,0 +1,65 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package feed
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// ConflictRetries is the number of times a change that conflicts with the update of
+// another writer is merged into the newer update and tried again
+const ConflictRetries = 5
+
+var (
+	// ErrConflict is returned when a conditional update finds that the feed was updated
+	// by another writer
+	ErrConflict = errors.New("feed was updated by another writer")
+)
+
+// ConflictError is returned by a conditional update of a feed that has moved on
+type ConflictError struct {
+	// Expected is the reference of the update the change was based on
+	Expected []byte
+	// Latest is the reference of the latest update, nil if the feed has no update
+	Latest []byte
+}
+
+// Error implements the error interface
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%v: expected update %x, latest is %x", ErrConflict, e.Expected, e.Latest)
+}
+
+// Is makes the error match ErrConflict
+func (*ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// precondition is the expected latest update of a conditional update
+type precondition struct {
+	expected []byte
+}
+
+// check returns a ConflictError if the latest update is not the expected one. A nil
+// precondition always holds.
+func (p *precondition) check(latest []byte) error {
+	if p == nil || bytes.Equal(p.expected, latest) {
+		return nil
+	}
+	return &ConflictError{Expected: p.expected, Latest: latest}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package feed_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/account"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/bee/mock"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+)
+
+func TestUpdateFeedIf(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.New(io.Discard, 0)
+	acc := account.New(logger)
+	_, _, err := acc.CreateUserAccount("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := acc.GetAddress(account.UserAccountIndex)
+	c := &clock{now: 1600000000}
+	feed.TimestampProvider = c
+	defer func() {
+		feed.TimestampProvider = feed.NewDefaultTimestampProvider()
+	}()
+	password := []byte("password")
+
+	for _, feedType := range []feed.Type{feed.EpochFeed, feed.SequenceFeed} {
+		t.Run(feedType.String(), func(t *testing.T) {
+			client := mock.NewMockBeeClient()
+			writer1 := feed.NewWithType(acc.GetUserAccountInfo(), client, feedType, logger)
+			writer2 := feed.NewWithType(acc.GetUserAccountInfo(), client, feedType, logger)
+			topic := utils.HashString("conflict")
+
+			// a feed without updates is expected with a nil reference
+			_, err := writer1.UpdateFeedIf(ctx, topic, user, nil, []byte("first"), password)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.now++
+			_, err = writer1.UpdateFeedIf(ctx, topic, user, nil, []byte("second"), password)
+			if !errors.Is(err, feed.ErrConflict) {
+				t.Fatalf("expected a conflict for an updated feed, got %v", err)
+			}
+
+			// both writers start from the same update
+			ref1, _, err := writer1.GetFeedData(ctx, topic, user, password)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ref2, _, err := writer2.GetFeedData(ctx, topic, user, password)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(ref1, ref2) {
+				t.Fatal("writers see different updates")
+			}
+			c.now++
+			_, err = writer1.UpdateFeedIf(ctx, topic, user, ref1, []byte("from writer1"), password)
+			if err != nil {
+				t.Fatal(err)
+			}
+			latest, _, err := writer1.GetFeedData(ctx, topic, user, password)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// the second writer finds the update of the first one
+			c.now++
+			_, err = writer2.UpdateFeedIf(ctx, topic, user, ref2, []byte("from writer2"), password)
+			var conflict *feed.ConflictError
+			if !errors.As(err, &conflict) {
+				t.Fatalf("expected a conflict error, got %v", err)
+			}
+			if !bytes.Equal(conflict.Expected, ref2) || !bytes.Equal(conflict.Latest, latest) {
+				t.Fatalf("conflict reports %x, latest %x", conflict.Expected, conflict.Latest)
+			}
+			_, data, err := writer2.GetFeedData(ctx, topic, user, password)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != "from writer1" {
+				t.Fatalf("conflicting update changed the feed to %s", data)
+			}
+
+			// retrying from the latest update succeeds
+			_, err = writer2.UpdateFeedIf(ctx, topic, user, latest, []byte("from writer2"), password)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, data, err = writer1.GetFeedData(ctx, topic, user, password)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != "from writer2" {
+				t.Fatalf("expected the retried update, got %s", data)
+			}
+		})
+	}
+}
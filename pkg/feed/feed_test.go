@@ -18,6 +18,7 @@ package feed_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -37,6 +38,7 @@ func TestMain(m *testing.M) {
 }
 
 func TestFeed(t *testing.T) {
+	ctx := context.Background()
 	logger := logging.New(io.Discard, 0)
 
 	acc1 := account.New(logger)
@@ -52,18 +54,18 @@ func TestFeed(t *testing.T) {
 		fd := feed.New(accountInfo1, client, logger)
 		topic := utils.HashString("topic1")
 		data := []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
-		addr, err := fd.CreateFeed(topic, user1, data, nil)
+		addr, err := fd.CreateFeed(ctx, topic, user1, data, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
 		longTopic := append(topic, topic...) // skipcq: CRT-D0001
-		_, _, err = fd.GetFeedData(longTopic, user1, nil)
+		_, _, err = fd.GetFeedData(ctx, longTopic, user1, nil)
 		if !errors.Is(err, feed.ErrInvalidTopicSize) {
 			t.Fatal("invalid topic size")
 		}
 
 		// check if the data and address is present and is same as stored
-		rcvdAddr, rcvdData, err := fd.GetFeedData(topic, user1, nil)
+		rcvdAddr, rcvdData, err := fd.GetFeedData(ctx, topic, user1, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -88,14 +90,14 @@ func TestFeed(t *testing.T) {
 		fd1 := feed.New(accountInfo1, client, logger)
 		topic := utils.HashString("topic1")
 		data := []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
-		addr, err := fd1.CreateFeed(topic, user1, data, nil)
+		addr, err := fd1.CreateFeed(ctx, topic, user1, data, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// check if you can read the data from user2
 		fd2 := feed.New(accountInfo2, client, logger)
-		rcvdAddr, rcvdData, err := fd2.GetFeedData(topic, user1, nil)
+		rcvdAddr, rcvdData, err := fd2.GetFeedData(ctx, topic, user1, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -112,7 +114,7 @@ func TestFeed(t *testing.T) {
 		topic := utils.HashString("topic2")
 
 		// check if the data and address is present and is same as stored
-		_, _, err := fd.GetFeedData(topic, user1, nil)
+		_, _, err := fd.GetFeedData(ctx, topic, user1, nil)
 		if err != nil && err.Error() != "feed does not exist or was not updated yet" {
 			t.Fatal(err)
 		}
@@ -132,14 +134,14 @@ func TestFeed(t *testing.T) {
 		fd1 := feed.New(accountInfo1, client, logger)
 		topic := utils.HashString("topic1")
 		data := []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
-		_, err := fd1.CreateFeed(topic, user1, data, nil)
+		_, err := fd1.CreateFeed(ctx, topic, user1, data, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// check if you can read the data from user2
 		fd2 := feed.New(accountInfo2, client, logger)
-		rcvdAddr, rcvdData, err := fd2.GetFeedData(topic, user2, nil)
+		rcvdAddr, rcvdData, err := fd2.GetFeedData(ctx, topic, user2, nil)
 		if err != nil && err.Error() != "feed does not exist or was not updated yet" {
 			t.Fatal(err)
 		}
@@ -152,7 +154,7 @@ func TestFeed(t *testing.T) {
 		fd := feed.New(accountInfo1, client, logger)
 		topic := utils.HashString("topic3")
 		data := []byte{0}
-		_, err = fd.CreateFeed(topic, user1, data, nil)
+		_, err = fd.CreateFeed(ctx, topic, user1, data, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -161,11 +163,11 @@ func TestFeed(t *testing.T) {
 			buf := make([]byte, 4)
 			binary.LittleEndian.PutUint16(buf, uint16(i))
 
-			_, err = fd.UpdateFeed(topic, user1, buf, nil)
+			_, err = fd.UpdateFeed(ctx, topic, user1, buf, nil)
 			if err != nil {
 				t.Fatal(err)
 			}
-			getAddr, rcvdData, err := fd.GetFeedData(topic, user1, nil)
+			getAddr, rcvdData, err := fd.GetFeedData(ctx, topic, user1, nil)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -183,18 +185,18 @@ func TestFeed(t *testing.T) {
 		fd := feed.New(accountInfo1, client, logger)
 		topic := utils.HashString("feed-topic1")
 		data := []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
-		addr, err := fd.CreateFeedFromTopic(topic, user1, data)
+		addr, err := fd.CreateFeedFromTopic(ctx, topic, user1, data)
 		if err != nil {
 			t.Fatal(err)
 		}
 		longTopic := append(topic, topic...) // skipcq: CRT-D0001
-		_, _, err = fd.GetFeedDataFromTopic(longTopic, user1)
+		_, _, err = fd.GetFeedDataFromTopic(ctx, longTopic, user1)
 		if !errors.Is(err, feed.ErrInvalidTopicSize) {
 			t.Fatal("invalid topic size")
 		}
 
 		// check if the data and address is present and is same as stored
-		rcvdAddr, rcvdData, err := fd.GetFeedDataFromTopic(topic, user1)
+		rcvdAddr, rcvdData, err := fd.GetFeedDataFromTopic(ctx, topic, user1)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -210,16 +212,16 @@ func TestFeed(t *testing.T) {
 		fd := feed.New(accountInfo1, client, logger)
 		topic := utils.HashString("feed-topic1")
 		data := []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
-		_, err := fd.CreateFeedFromTopic(topic, user1, data)
+		_, err := fd.CreateFeedFromTopic(ctx, topic, user1, data)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = fd.DeleteFeedFromTopic(topic, user1)
+		err = fd.DeleteFeedFromTopic(ctx, topic, user1)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		_, _, err = fd.GetFeedDataFromTopic(topic, user1)
+		_, _, err = fd.GetFeedDataFromTopic(ctx, topic, user1)
 		if err != nil && err.Error() != "error downloading data" {
 			t.Fatal("error should be \"error downloading data\"")
 		}
@@ -232,13 +234,13 @@ func TestFeed(t *testing.T) {
 		topic := utils.HashString("feed-topic1")
 		data := []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
 
-		_, err = nilFd.CreateFeed(topic, user1, data, nil)
+		_, err = nilFd.CreateFeed(ctx, topic, user1, data, nil)
 		if !errors.Is(err, feed.ErrReadOnlyFeed) {
 			t.Fatal("read only feed")
 		}
 
 		longTopic := append(topic, topic...) // skipcq: CRT-D0001
-		_, err = fd.CreateFeed(longTopic, user1, data, nil)
+		_, err = fd.CreateFeed(ctx, longTopic, user1, data, nil)
 		if !errors.Is(err, feed.ErrInvalidTopicSize) {
 			t.Fatal("invalid topic size")
 		}
@@ -247,7 +249,7 @@ func TestFeed(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		_, err = fd.CreateFeed(topic, user1, longData, nil)
+		_, err = fd.CreateFeed(ctx, topic, user1, longData, nil)
 		if !errors.Is(err, feed.ErrInvalidPayloadSize) {
 			t.Fatal("invalid payload size")
 		}
@@ -260,13 +262,13 @@ func TestFeed(t *testing.T) {
 		topic := utils.HashString("feed-topic1")
 		data := []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
 
-		_, err = nilFd.CreateFeedFromTopic(topic, user1, data)
+		_, err = nilFd.CreateFeedFromTopic(ctx, topic, user1, data)
 		if !errors.Is(err, feed.ErrReadOnlyFeed) {
 			t.Fatal("read only feed")
 		}
 
 		longTopic := append(topic, topic...) // skipcq: CRT-D0001
-		_, err = fd.CreateFeedFromTopic(longTopic, user1, data)
+		_, err = fd.CreateFeedFromTopic(ctx, longTopic, user1, data)
 		if !errors.Is(err, feed.ErrInvalidTopicSize) {
 			t.Fatal("invalid topic size")
 		}
@@ -275,7 +277,7 @@ func TestFeed(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		_, err = fd.CreateFeedFromTopic(topic, user1, longData)
+		_, err = fd.CreateFeedFromTopic(ctx, topic, user1, longData)
 		if !errors.Is(err, feed.ErrInvalidPayloadSize) {
 			t.Fatal("invalid payload size")
 		}
@@ -288,13 +290,13 @@ func TestFeed(t *testing.T) {
 		topic := utils.HashString("feed-topic1")
 		data := []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
 
-		_, err = nilFd.UpdateFeed(topic, user1, data, nil)
+		_, err = nilFd.UpdateFeed(ctx, topic, user1, data, nil)
 		if !errors.Is(err, feed.ErrReadOnlyFeed) {
 			t.Fatal("read only feed")
 		}
 
 		longTopic := append(topic, topic...) // skipcq: CRT-D0001
-		_, err = fd.UpdateFeed(longTopic, user1, data, nil)
+		_, err = fd.UpdateFeed(ctx, longTopic, user1, data, nil)
 		if !errors.Is(err, feed.ErrInvalidTopicSize) {
 			t.Fatal("invalid topic size")
 		}
@@ -303,7 +305,7 @@ func TestFeed(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		_, err = fd.UpdateFeed(topic, user1, longData, nil)
+		_, err = fd.UpdateFeed(ctx, topic, user1, longData, nil)
 		if !errors.Is(err, feed.ErrInvalidPayloadSize) {
 			t.Fatal("invalid payload size")
 		}
@@ -316,17 +318,17 @@ func TestFeed(t *testing.T) {
 		topic := utils.HashString("feed-topic1")
 		data := []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
 
-		err = nilFd.DeleteFeed(topic, user1)
+		err = nilFd.DeleteFeed(ctx, topic, user1)
 		if !errors.Is(err, feed.ErrReadOnlyFeed) {
 			t.Fatal("read only feed")
 		}
 
-		_, err = fd.CreateFeed(topic, user1, data, nil)
+		_, err = fd.CreateFeed(ctx, topic, user1, data, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		err = fd.DeleteFeed(topic, user1)
+		err = fd.DeleteFeed(ctx, topic, user1)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -335,9 +337,184 @@ func TestFeed(t *testing.T) {
 	t.Run("feed-from-topic-delete-errors", func(t *testing.T) {
 		nilFd := feed.New(&account.Info{}, client, logger)
 		topic := utils.HashString("feed-topic1")
-		err = nilFd.DeleteFeedFromTopic(topic, user1)
+		err = nilFd.DeleteFeedFromTopic(ctx, topic, user1)
 		if !errors.Is(err, feed.ErrReadOnlyFeed) {
 			t.Fatal("read only feed")
 		}
 	})
 }
+
+// clock is a timestamp provider that is moved by the test
+type clock struct {
+	now uint64
+}
+
+func (c *clock) Now() feed.Timestamp {
+	return feed.Timestamp{Time: c.now}
+}
+
+func TestFeedHistory(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.New(io.Discard, 0)
+	acc := account.New(logger)
+	_, _, err := acc.CreateUserAccount("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := acc.GetAddress(account.UserAccountIndex)
+	c := &clock{}
+	feed.TimestampProvider = c
+	defer func() {
+		feed.TimestampProvider = feed.NewDefaultTimestampProvider()
+	}()
+	fd := feed.New(acc.GetUserAccountInfo(), mock.NewMockBeeClient(), logger)
+	topic := utils.HashString("history")
+	password := []byte("password")
+
+	// two of the updates are in the same second
+	start := uint64(1600000000)
+	times := []uint64{start, start + 10, start + 1000, start + 100000, start + 100000, start + 5000000}
+	for i, now := range times {
+		c.now = now
+		data := []byte(fmt.Sprintf("update %d", i))
+		if i == 0 {
+			_, err = fd.CreateFeed(ctx, topic, user, data, password)
+		} else {
+			_, err = fd.UpdateFeed(ctx, topic, user, data, password)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	c.now = start + 6000000
+
+	history, err := fd.GetFeedHistory(ctx, topic, user, password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != len(times) {
+		t.Fatalf("expected %d updates, got %d", len(times), len(history))
+	}
+	for i, h := range history {
+		if string(h.Data) != fmt.Sprintf("update %d", i) {
+			t.Fatalf("update %d is out of order: %s", i, h.Data)
+		}
+		if h.Timestamp > times[i] {
+			t.Fatalf("update %d is dated after it was made", i)
+		}
+		if i > 0 && h.Timestamp < history[i-1].Timestamp {
+			t.Fatalf("update %d is dated before the previous one", i)
+		}
+	}
+	addr, latest, err := fd.GetFeedData(ctx, topic, user, password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(addr, history[len(history)-1].Reference) || !bytes.Equal(latest, history[len(history)-1].Data) {
+		t.Fatal("the last update should be the latest one")
+	}
+
+	// before the epoch of an update, the previous update is read
+	_, data, err := fd.GetFeedDataAt(ctx, topic, user, history[3].Epoch.Time-1, password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, history[2].Data) {
+		t.Fatalf("expected %s, got %s", history[2].Data, data)
+	}
+	_, data, err = fd.GetFeedDataAt(ctx, topic, user, 0, password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, latest) {
+		t.Fatalf("expected the latest update, got %s", data)
+	}
+
+	_, err = fd.GetFeedHistory(ctx, utils.HashString("missing"), user, password)
+	if err == nil {
+		t.Fatal("expected an error for a feed without updates")
+	}
+}
+
+func TestSameSecondUpdates(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.New(io.Discard, 0)
+	acc := account.New(logger)
+	_, _, err := acc.CreateUserAccount("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := acc.GetAddress(account.UserAccountIndex)
+	c := &clock{now: 1600000000}
+	feed.TimestampProvider = c
+	defer func() {
+		feed.TimestampProvider = feed.NewDefaultTimestampProvider()
+	}()
+	client := mock.NewMockBeeClient()
+	fd := feed.New(acc.GetUserAccountInfo(), client, logger)
+	topic := utils.HashString("same-second")
+	password := []byte("password")
+
+	// the updates of one second use up the lower levels and then share the lowest one
+	count := 0
+	update := func() {
+		t.Helper()
+		data := []byte(fmt.Sprintf("update %d", count))
+		if count == 0 {
+			_, err = fd.CreateFeed(ctx, topic, user, data, password)
+		} else {
+			_, err = fd.UpdateFeed(ctx, topic, user, data, password)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		count++
+
+		// the newest write is read by the writer and by a reader without a cache
+		reader := feed.New(acc.GetUserAccountInfo(), client, logger)
+		for _, f := range []*feed.API{fd, reader} {
+			_, latest, err := f.GetFeedData(ctx, topic, user, password)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(latest, data) {
+				t.Fatalf("expected %s, got %s", data, latest)
+			}
+		}
+	}
+	update()
+	c.now++
+	for i := 0; i < 40; i++ {
+		update()
+	}
+
+	// no update overwrote another one
+	history, err := fd.GetFeedHistory(ctx, topic, user, password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != count {
+		t.Fatalf("expected %d updates, got %d", count, len(history))
+	}
+	refs := make(map[string]bool)
+	for i, h := range history {
+		if string(h.Data) != fmt.Sprintf("update %d", i) {
+			t.Fatalf("update %d is out of order: %s", i, h.Data)
+		}
+		refs[string(h.Reference)] = true
+	}
+	if len(refs) != count || history[count-1].Sequence == 0 {
+		t.Fatalf("expected %d distinct updates, got %d", count, len(refs))
+	}
+
+	// the updates of a later second are placed in the epochs again
+	c.now++
+	update()
+	_, data, err := fd.GetFeedDataAt(ctx, topic, user, c.now-1, password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != fmt.Sprintf("update %d", count-2) {
+		t.Fatalf("expected the last update of the previous second, got %s", data)
+	}
+}
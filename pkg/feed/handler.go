@@ -45,6 +45,8 @@ type Handler struct {
 	HashSize    int
 	cache       map[uint64]*CacheEntry
 	cacheLock   sync.RWMutex
+	indexes     map[uint64]*sequenceUpdate
+	indexLock   sync.RWMutex
 }
 
 // hashPool contains a pool of ready hashers
@@ -66,6 +68,7 @@ func NewHandler(accountInfo *account.Info, client blockstore.Client, hasherPool
 		client:      client,
 		hasherPool:  hasherPool,
 		cache:       make(map[uint64]*CacheEntry),
+		indexes:     make(map[uint64]*sequenceUpdate),
 	}
 	for i := 0; i < hasherCount; i++ {
 		hashfunc := crypto.SHA256.New()
@@ -77,17 +80,31 @@ func NewHandler(accountInfo *account.Info, client blockstore.Client, hasherPool
 	return fh
 }
 
-func (h *Handler) update(id, owner, signature, data []byte) ([]byte, error) {
+func newSOC(id, owner, signature, data []byte) blockstore.SOC {
+	return blockstore.SOC{
+		Owner:     utils.Encode(owner),
+		ID:        utils.Encode(id),
+		Signature: utils.Encode(signature),
+		Data:      data,
+	}
+}
+
+func (h *Handler) update(ctx context.Context, update blockstore.SOC) ([]byte, error) {
 	// send the SOC chunk
-	addr, err := h.client.UploadSOC(utils.Encode(owner), utils.Encode(id), utils.Encode(signature), data)
+	addr, err := h.client.UploadSOC(ctx, update.Owner, update.ID, update.Signature, update.Data)
 	if err != nil {
 		return nil, err
 	}
 	return addr, nil
 }
 
-func (h *Handler) deleteChunk(ref []byte) error {
-	return h.client.DeleteReference(ref)
+func (h *Handler) updateBatch(ctx context.Context, updates []blockstore.SOC) ([][]byte, error) {
+	// send the SOC chunks in one batch
+	return h.client.UploadSOCs(ctx, updates)
+}
+
+func (h *Handler) deleteChunk(ctx context.Context, ref []byte) error {
+	return h.client.DeleteReference(ctx, ref)
 }
 
 // GetContent retrieves the data payload of the last synced update of the feed
@@ -110,7 +127,15 @@ func (h *Handler) GetContent(feed *Feed) (swarm.Address, []byte, error) {
 // See the `query` documentation and helper functions:
 // `NewQueryLatest` and `NewQuery`
 func (h *Handler) Lookup(ctx context.Context, query *Query) (*CacheEntry, error) {
+	request, err := h.lookup(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return h.updateCache(request)
+}
 
+// lookup finds the update for the query without changing the cache
+func (h *Handler) lookup(ctx context.Context, query *Query) (*request, error) {
 	timeLimit := query.TimeLimit
 	if timeLimit == 0 { // if time limit is set to zero, the user wants to get the latest update
 		timeLimit = TimestampProvider.Now().Time
@@ -137,31 +162,12 @@ func (h *Handler) Lookup(ctx context.Context, query *Query) (*CacheEntry, error)
 	// The callback will be called every time the lookup algorithm needs to guess
 	requestPtr, err := lookup.Lookup(ctx, timeLimit, query.Hint, func(ctx context.Context, epoch lookup.Epoch, now uint64) (interface{}, error) {
 		atomic.AddInt32(&readCount, 1)
-		id := ID{
-			Feed:  query.Feed,
-			Epoch: epoch,
-		}
-		ctx, cancel := context.WithTimeout(ctx, defaultRetrieveTimeout)
-		defer cancel()
-
-		addr, err := h.getAddress(id.Topic, query.Feed.User, epoch)
-		if err != nil { // skipcq: TCV-001
-			return nil, err
-		}
-		data, err := h.client.DownloadChunk(ctx, addr.Bytes())
-		if err != nil {
-			if errors.Is(err, context.DeadlineExceeded) || err.Error() == "error downloading data" { // chunk not found
-				return nil, nil
-			}
+		request, err := h.readEpoch(ctx, query, epoch)
+		if err != nil || request == nil {
 			return nil, err
 		}
-		ch := swarm.NewChunk(addr, data)
-		var request request
-		if err := h.fromChunk(ch, &request, query, &id); err != nil {
-			return nil, nil
-		}
 		if request.Time <= timeLimit {
-			return &request, nil
+			return request, nil
 		}
 		return nil, nil // skipcq: TCV-001
 	})
@@ -172,7 +178,76 @@ func (h *Handler) Lookup(ctx context.Context, query *Query) (*CacheEntry, error)
 	if request == nil {
 		return nil, NewError(errNotFound, "feed does not exist or was not updated yet")
 	}
-	return h.updateCache(request)
+	return h.latestInEpoch(ctx, query, request)
+}
+
+// latestInEpoch returns the latest of the updates that share the epoch of the given
+// one. Only the lowest level epochs hold more than one update. The walk starts at the
+// cached update if it is in the same epoch.
+func (h *Handler) latestInEpoch(ctx context.Context, query *Query, first *request) (*request, error) {
+	if first.Level != lookup.LowestLevel {
+		return first, nil
+	}
+	latest := first
+	entry, err := h.get(&query.Feed)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	if entry != nil && entry.Sequence > 0 && entry.Epoch.Equals(first.Epoch) {
+		cached, err := h.readUpdate(ctx, query, first.Epoch, entry.Sequence)
+		if err != nil { // skipcq: TCV-001
+			return nil, err
+		}
+		if cached != nil {
+			latest = cached
+		}
+	}
+	for {
+		next, err := h.readUpdate(ctx, query, first.Epoch, latest.Sequence+1)
+		if err != nil { // skipcq: TCV-001
+			return nil, err
+		}
+		if next == nil {
+			return latest, nil
+		}
+		latest = next
+	}
+}
+
+// readEpoch reads the first update of the feed in the given epoch. It returns nil if
+// there is no update in the epoch.
+func (h *Handler) readEpoch(ctx context.Context, query *Query, epoch lookup.Epoch) (*request, error) {
+	return h.readUpdate(ctx, query, epoch, 0)
+}
+
+// readUpdate reads the update of the feed with the given sequence in the epoch. It
+// returns nil if there is no such update.
+func (h *Handler) readUpdate(ctx context.Context, query *Query, epoch lookup.Epoch, sequence uint64) (*request, error) {
+	id := ID{
+		Feed:     query.Feed,
+		Epoch:    epoch,
+		Sequence: sequence,
+	}
+	ctx, cancel := context.WithTimeout(ctx, defaultRetrieveTimeout)
+	defer cancel()
+
+	addr, err := h.getAddress(id.Topic, query.Feed.User, epoch, sequence)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	data, err := h.client.DownloadChunk(ctx, addr.Bytes())
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) || err.Error() == "error downloading data" { // chunk not found
+			return nil, nil
+		}
+		return nil, err
+	}
+	ch := swarm.NewChunk(addr, data)
+	var request request
+	if err := h.fromChunk(ch, &request, query, &id); err != nil {
+		return nil, nil
+	}
+	return &request, nil
 }
 
 // fromChunk populates this structure from chunk data. It does not verify the signature is valid.
@@ -197,6 +272,7 @@ func (*Handler) fromChunk(chunk swarm.Chunk, r *request, q *Query, id *ID) error
 	r.Feed = q.Feed
 	r.User = q.User
 	r.Epoch = id.Epoch
+	r.Sequence = id.Sequence
 	return nil
 }
 
@@ -237,8 +313,8 @@ func hashFunc() hash.Hash {
 	return sha3.NewLegacyKeccak256()
 }
 
-func (h *Handler) getAddress(topic Topic, user utils.Address, epoch lookup.Epoch) (swarm.Address, error) {
-	id, err := h.getId(topic, epoch.Time, epoch.Level)
+func (h *Handler) getAddress(topic Topic, user utils.Address, epoch lookup.Epoch, sequence uint64) (swarm.Address, error) {
+	id, err := h.getId(topic, epoch.Time, epoch.Level, sequence)
 	if err != nil { // skipcq: TCV-001
 		return swarm.ZeroAddress, err
 	}
@@ -275,10 +351,11 @@ func (h *Handler) toChunkContent(req *request, id, payloadId []byte) ([]byte, er
 
 // newRequest prepares a request structure with all the necessary information to
 // just add the desired data and sign it.
-// The resulting structure can then be signed and passed to Handler.Update to be verified and sent
-func (h *Handler) newRequest(ctx context.Context, feed *Feed) (request2 *request, err error) {
+// The resulting structure can then be signed and passed to Handler.Update to be verified and sent.
+// The address of the latest update is returned too, nil if the feed has no update.
+func (h *Handler) newRequest(ctx context.Context, feed *Feed) (request2 *request, latest []byte, err error) {
 	if feed == nil {
-		return nil, NewError(errInvalidValue, "feed cannot be nil")
+		return nil, nil, NewError(errInvalidValue, "feed cannot be nil")
 	}
 
 	now := TimestampProvider.Now().Time
@@ -290,10 +367,10 @@ func (h *Handler) newRequest(ctx context.Context, feed *Feed) (request2 *request
 	if err != nil {
 		feedErr, ok := err.(*Error)
 		if !ok {
-			return nil, err
+			return nil, nil, err
 		}
 		if feedErr.code != errNotFound {
-			return nil, err
+			return nil, nil, err
 		}
 		// not finding updates means that there is a network error
 		// or that the feed really does not have updates
@@ -304,20 +381,36 @@ func (h *Handler) newRequest(ctx context.Context, feed *Feed) (request2 *request
 	// if we already have an update, then find next epoch
 	if feedUpdate != nil {
 		request2.Epoch = lookup.GetNextEpoch(feedUpdate.Epoch, now)
+		if request2.Epoch.Equals(feedUpdate.Epoch) {
+			// there is no lower epoch left in this second, so the update follows the
+			// latest one in the same epoch
+			request2.Sequence = feedUpdate.Sequence + 1
+		}
+		latest = feedUpdate.lastKey
 	} else {
 		request2.Epoch = lookup.GetFirstEpoch(now)
 	}
 
-	return request2, nil
+	return request2, latest, nil
 }
 
-func (h *Handler) getId(topic Topic, time uint64, level uint8) ([]byte, error) {
-	bufId := make([]byte, TopicLength+lookup.EpochLength)
+// getId returns the id of an update, hash(topic, epoch). The updates that follow the
+// first one in an epoch have their sequence number appended.
+func (h *Handler) getId(topic Topic, time uint64, level uint8, sequence uint64) ([]byte, error) {
+	idLen := TopicLength + lookup.EpochLength
+	if sequence > 0 {
+		idLen += indexLength
+	}
+	bufId := make([]byte, idLen)
 	var cursor int
 	copy(bufId[cursor:cursor+TopicLength], topic[:TopicLength])
 	cursor += TopicLength
 	eid := epocId(time, level)
 	copy(bufId[cursor:cursor+lookup.EpochLength], eid[:])
+	cursor += lookup.EpochLength
+	if sequence > 0 {
+		binary.BigEndian.PutUint64(bufId[cursor:], sequence)
+	}
 	hasher := bmtlegacy.New(h.hasherPool)
 	hasher.Reset()
 	_, err := hasher.Write(bufId)
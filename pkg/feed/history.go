@@ -0,0 +1,246 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package feed
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/feed/lookup"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+)
+
+// HistoryEntry is an update of a feed
+type HistoryEntry struct {
+	// Epoch is the epoch of an update of an epoch feed
+	Epoch lookup.Epoch `json:"epoch"`
+	// Index is the index of an update of a sequence feed
+	Index uint64 `json:"index,omitempty"`
+	// Sequence orders the updates of an epoch feed that share the lowest level epoch
+	Sequence uint64 `json:"sequence,omitempty"`
+	// Timestamp is the earliest time the update can have been made at. The epochs only
+	// keep the time at the resolution of their level, so it is not the exact time. The
+	// updates of a sequence feed carry their exact time.
+	Timestamp uint64 `json:"timestamp"`
+	Reference []byte `json:"reference"`
+	Data      []byte `json:"-"`
+}
+
+// GetFeedHistory lists all the updates of a topic, oldest first, with their decrypted
+// payload. Every update is placed in an epoch whose parent epoch also holds an update,
+// so the updates are found by walking down the epoch tree from the highest level.
+func (a *API) GetFeedHistory(ctx context.Context, topic []byte, user utils.Address, encryptionPassword []byte) ([]*HistoryEntry, error) {
+	if len(topic) != TopicLength {
+		return nil, ErrInvalidTopicSize
+	}
+	q := &Query{}
+	q.User = user
+	copy(q.Topic[:], topic)
+	if a.feedType == SequenceFeed {
+		return a.sequenceHistory(ctx, &q.Feed, encryptionPassword)
+	}
+
+	var (
+		mu      sync.Mutex
+		found   []*request
+		pending []lookup.Epoch
+	)
+	now := TimestampProvider.Now().Time
+	for base := uint64(0); base <= now; base += 1 << lookup.HighestLevel {
+		pending = append(pending, lookup.Epoch{Time: base, Level: lookup.HighestLevel})
+	}
+	for len(pending) > 0 {
+		epochs := pending
+		pending = nil
+		err := blockstore.Parallel(ctx, len(epochs), func(ctx context.Context, i int) error {
+			req, err := a.handler.readEpoch(ctx, q, epochs[i])
+			if err != nil || req == nil {
+				return err
+			}
+			if epochs[i].Level == lookup.LowestLevel {
+				// the later updates of the same second follow in the epoch
+				for sequence := uint64(1); ; sequence++ {
+					next, err := a.handler.readUpdate(ctx, q, epochs[i], sequence)
+					if err != nil { // skipcq: TCV-001
+						return err
+					}
+					if next == nil {
+						break
+					}
+					mu.Lock()
+					found = append(found, next)
+					mu.Unlock()
+				}
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			found = append(found, req)
+			if epochs[i].Level > lookup.LowestLevel {
+				base, level := epochs[i].Base(), epochs[i].Level-1
+				pending = append(pending,
+					lookup.Epoch{Time: base, Level: level},
+					lookup.Epoch{Time: base + 1<<level, Level: level})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(found) == 0 {
+		return nil, NewError(errNotFound, "feed does not exist or was not updated yet")
+	}
+
+	// a later update is either in a later epoch, in a lower level of the same epoch or
+	// later in the sequence of the same epoch
+	sort.Slice(found, func(i, j int) bool {
+		bi, bj := found[i].Epoch.Base(), found[j].Epoch.Base()
+		if bi != bj {
+			return bi < bj
+		}
+		if found[i].Level != found[j].Level {
+			return found[i].Level > found[j].Level
+		}
+		return found[i].Sequence < found[j].Sequence
+	})
+	history := make([]*HistoryEntry, len(found))
+	var timestamp uint64
+	for i, req := range found {
+		if base := req.Epoch.Base(); base > timestamp {
+			timestamp = base
+		}
+		data, err := decryptPayload(req.data, encryptionPassword)
+		if err != nil { // skipcq: TCV-001
+			return nil, err
+		}
+		history[i] = &HistoryEntry{
+			Epoch:     lookup.Epoch{Time: req.Epoch.Base(), Level: req.Level},
+			Sequence:  req.Sequence,
+			Timestamp: timestamp,
+			Reference: req.idAddr.Bytes(),
+			Data:      data,
+		}
+	}
+	return history, nil
+}
+
+// GetFeedDataAt reads the update of a topic that was the latest at the given time, with
+// the resolution of the epochs. A time of 0 reads the latest update.
+func (a *API) GetFeedDataAt(ctx context.Context, topic []byte, user utils.Address, timestamp uint64, encryptionPassword []byte) ([]byte, []byte, error) {
+	if len(topic) != TopicLength {
+		return nil, nil, ErrInvalidTopicSize
+	}
+	f := new(Feed)
+	f.User = user
+	copy(f.Topic[:], topic)
+	if a.feedType == SequenceFeed {
+		return a.sequenceDataAt(ctx, f, timestamp, encryptionPassword)
+	}
+
+	// the cache keeps the latest update, so an older one is not stored there
+	req, err := a.handler.lookup(ctx, NewQuery(f, timestamp, lookup.NoClue))
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := decryptPayload(req.data, encryptionPassword)
+	if err != nil { // skipcq: TCV-001
+		return nil, nil, err
+	}
+	return req.idAddr.Bytes(), data, nil
+}
+
+// sequenceHistory reads all the updates of a sequence feed up to the latest one
+func (a *API) sequenceHistory(ctx context.Context, f *Feed, encryptionPassword []byte) ([]*HistoryEntry, error) {
+	latest, err := a.handler.lookupSequence(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	updates := make([]*sequenceUpdate, latest.index+1)
+	updates[latest.index] = latest
+	err = blockstore.Parallel(ctx, int(latest.index), func(ctx context.Context, i int) error {
+		update, err := a.handler.readIndex(ctx, f, uint64(i))
+		updates[i] = update
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]*HistoryEntry, 0, len(updates))
+	for _, update := range updates {
+		// an update can only be missing if it was deleted
+		if update == nil {
+			continue
+		}
+		data, err := decryptPayload(update.data, encryptionPassword)
+		if err != nil { // skipcq: TCV-001
+			return nil, err
+		}
+		history = append(history, &HistoryEntry{
+			Index:     update.index,
+			Timestamp: update.timestamp,
+			Reference: update.address.Bytes(),
+			Data:      data,
+		})
+	}
+	return history, nil
+}
+
+// sequenceDataAt bisects the updates of a sequence feed for the latest one at the given
+// time
+func (a *API) sequenceDataAt(ctx context.Context, f *Feed, timestamp uint64, encryptionPassword []byte) ([]byte, []byte, error) {
+	latest, err := a.handler.lookupSequence(ctx, f)
+	if err != nil {
+		return nil, nil, err
+	}
+	found := latest
+	if timestamp != 0 && latest.timestamp > timestamp {
+		found = nil
+		low, high := uint64(0), latest.index
+		for low < high {
+			mid := low + (high-low)/2
+			update, err := a.handler.readIndex(ctx, f, mid)
+			if err != nil { // skipcq: TCV-001
+				return nil, nil, err
+			}
+			if update != nil && update.timestamp <= timestamp {
+				found = update
+				low = mid + 1
+			} else {
+				high = mid
+			}
+		}
+	}
+	if found == nil {
+		return nil, nil, NewError(errNotFound, "feed does not exist or was not updated yet")
+	}
+	data, err := decryptPayload(found.data, encryptionPassword)
+	if err != nil { // skipcq: TCV-001
+		return nil, nil, err
+	}
+	return found.address.Bytes(), data, nil
+}
+
+// decryptPayload decrypts the payload of an update, a deleted feed is returned as is
+func decryptPayload(data, encryptionPassword []byte) ([]byte, error) {
+	if encryptionPassword == nil || string(data) == utils.DeletedFeedMagicWord {
+		return data, nil
+	}
+	return utils.DecryptBytes(encryptionPassword, data)
+}
@@ -24,4 +24,7 @@ import (
 type ID struct {
 	Feed         `json:"feed"`
 	lookup.Epoch `json:"epoch"`
+	// Sequence numbers the updates that are made in the same lowest level epoch, as they
+	// cannot be placed in a lower one. The first update of an epoch has sequence 0.
+	Sequence uint64 `json:"sequence,omitempty"`
 }
@@ -0,0 +1,335 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package feed
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	indexLength     = 8
+	timestampLength = 8
+)
+
+// sequenceUpdate is an update of a sequence feed. The updates are numbered from 0 and
+// are stored like the updates of the sequential feeds of bee, so their payload starts
+// with the big endian time of the update.
+type sequenceUpdate struct {
+	index     uint64
+	address   swarm.Address
+	timestamp uint64
+	data      []byte
+}
+
+// sequenceId calculates the soc id of an update, keccak256(topic, index)
+func sequenceId(topic Topic, index uint64) []byte {
+	buf := make([]byte, TopicLength+indexLength)
+	copy(buf, topic[:])
+	binary.BigEndian.PutUint64(buf[TopicLength:], index)
+	h := sha3.NewLegacyKeccak256()
+	_, _ = h.Write(buf)
+	return h.Sum(nil)
+}
+
+// readIndex reads the update of the feed at the given index. It returns nil if there is
+// no update at the index.
+func (h *Handler) readIndex(ctx context.Context, feed *Feed, index uint64) (*sequenceUpdate, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultRetrieveTimeout)
+	defer cancel()
+
+	addr, err := toSignDigest(sequenceId(feed.Topic, index), feed.User[:])
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	data, err := h.client.DownloadChunk(ctx, addr)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) || err.Error() == "error downloading data" { // chunk not found
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	cursor := idLength + signatureLength
+	if len(data) < cursor+utils.SpanLength+timestampLength { // skipcq: TCV-001
+		return nil, nil
+	}
+	span := binary.LittleEndian.Uint64(data[cursor : cursor+utils.SpanLength])
+	cursor += utils.SpanLength
+	if span < timestampLength || span > uint64(len(data)-cursor) { // skipcq: TCV-001
+		return nil, nil
+	}
+	payload := make([]byte, span-timestampLength)
+	copy(payload, data[cursor+timestampLength:uint64(cursor)+span])
+	return &sequenceUpdate{
+		index:     index,
+		address:   swarm.NewAddress(addr),
+		timestamp: binary.BigEndian.Uint64(data[cursor : cursor+timestampLength]),
+		data:      payload,
+	}, nil
+}
+
+// lookupSequence finds the latest update of a sequence feed. A feed seen before is
+// read from the cached index onwards, which costs one read if there is no new update.
+func (h *Handler) lookupSequence(ctx context.Context, feed *Feed) (*sequenceUpdate, error) {
+	latest, err := h.getIndex(feed)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	if latest == nil {
+		latest, err = h.searchSequence(ctx, feed)
+		if err != nil {
+			return nil, err
+		}
+		if latest == nil {
+			return nil, NewError(errNotFound, "feed does not exist or was not updated yet")
+		}
+	} else {
+		// the pod can be updated by another client too
+		for {
+			next, err := h.readIndex(ctx, feed, latest.index+1)
+			if err != nil { // skipcq: TCV-001
+				return nil, err
+			}
+			if next == nil {
+				break
+			}
+			latest = next
+		}
+	}
+	err = h.setIndex(feed, latest)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	return latest, nil
+}
+
+// searchSequence finds the latest update of a feed without a known index. The index is
+// doubled until there is no update and the latest update is then bisected.
+func (h *Handler) searchSequence(ctx context.Context, feed *Feed) (*sequenceUpdate, error) {
+	latest, err := h.readIndex(ctx, feed, 0)
+	if err != nil || latest == nil {
+		return nil, err
+	}
+	missing := uint64(1)
+	for {
+		update, err := h.readIndex(ctx, feed, missing)
+		if err != nil { // skipcq: TCV-001
+			return nil, err
+		}
+		if update == nil {
+			break
+		}
+		latest = update
+		missing *= 2
+	}
+	for latest.index+1 < missing {
+		mid := latest.index + (missing-latest.index)/2
+		update, err := h.readIndex(ctx, feed, mid)
+		if err != nil { // skipcq: TCV-001
+			return nil, err
+		}
+		if update == nil {
+			missing = mid
+		} else {
+			latest = update
+		}
+	}
+	return latest, nil
+}
+
+// getIndex returns the cached latest update of a sequence feed
+func (h *Handler) getIndex(feed *Feed) (*sequenceUpdate, error) {
+	mapKey, err := feed.mapKey()
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	h.indexLock.RLock()
+	defer h.indexLock.RUnlock()
+	return h.indexes[mapKey], nil
+}
+
+// setIndex caches the latest update of a sequence feed
+func (h *Handler) setIndex(feed *Feed, update *sequenceUpdate) error {
+	mapKey, err := feed.mapKey()
+	if err != nil { // skipcq: TCV-001
+		return err
+	}
+	h.indexLock.Lock()
+	defer h.indexLock.Unlock()
+	if update == nil {
+		delete(h.indexes, mapKey)
+		return nil
+	}
+	h.indexes[mapKey] = update
+	return nil
+}
+
+// nextIndex returns the index of the next update of a sequence feed and the address of
+// the latest update, nil if the feed has no update
+func (h *Handler) nextIndex(ctx context.Context, feed *Feed) (uint64, []byte, error) {
+	latest, err := h.lookupSequence(ctx, feed)
+	if err != nil {
+		if feedErr, ok := err.(*Error); ok && feedErr.code == errNotFound {
+			return 0, nil, nil
+		}
+		return 0, nil, err
+	}
+	return latest.index + 1, latest.address.Bytes(), nil
+}
+
+// newSequenceSOC constructs the single owner chunk of the update of a sequence feed at
+// the given index
+func (a *API) newSequenceSOC(feed *Feed, index uint64, data []byte) (blockstore.SOC, *sequenceUpdate, error) {
+	timestamp := TimestampProvider.Now().Time
+	content := make([]byte, timestampLength+len(data))
+	binary.BigEndian.PutUint64(content, timestamp)
+	copy(content[timestampLength:], data)
+	if len(content) > swarm.ChunkSize {
+		return blockstore.SOC{}, nil, ErrInvalidPayloadSize
+	}
+
+	// create the signer and the content addressed chunk
+	id := sequenceId(feed.Topic, index)
+	signer := crypto.NewDefaultSigner(a.accountInfo.GetPrivateKey())
+	ch, err := utils.NewChunkWithSpan(content)
+	if err != nil { // skipcq: TCV-001
+		return blockstore.SOC{}, nil, err
+	}
+
+	// sign the chunk
+	toSignBytes, err := toSignDigest(id, ch.Address().Bytes())
+	if err != nil { // skipcq: TCV-001
+		return blockstore.SOC{}, nil, err
+	}
+	signature, err := signer.Sign(toSignBytes)
+	if err != nil { // skipcq: TCV-001
+		return blockstore.SOC{}, nil, err
+	}
+
+	addr, err := toSignDigest(id, feed.User[:])
+	if err != nil { // skipcq: TCV-001
+		return blockstore.SOC{}, nil, err
+	}
+	update := &sequenceUpdate{
+		index:     index,
+		address:   swarm.NewAddress(addr),
+		timestamp: timestamp,
+		data:      data,
+	}
+	return newSOC(id, feed.User.ToBytes(), signature, ch.Data()), update, nil
+}
+
+// updateSequence adds an update after the latest update of a sequence feed
+func (a *API) updateSequence(ctx context.Context, topic []byte, user utils.Address, data []byte, encryptionPassword []byte, cond *precondition) ([]byte, error) {
+	encryptedData, err := a.sequencePayload(topic, data, encryptionPassword)
+	if err != nil {
+		return nil, err
+	}
+	f := new(Feed)
+	f.User = user
+	copy(f.Topic[:], topic)
+
+	index, latest, err := a.handler.nextIndex(ctx, f)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	err = cond.check(latest)
+	if err != nil {
+		return nil, err
+	}
+	s, update, err := a.newSequenceSOC(f, index, encryptedData)
+	if err != nil {
+		return nil, err
+	}
+	address, err := a.handler.update(ctx, s)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	err = a.handler.setIndex(f, update)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	return address, nil
+}
+
+// createSequences adds an update to many sequence feeds and uploads them in one batch
+func (a *API) createSequences(ctx context.Context, topics [][]byte, user utils.Address, data [][]byte, encryptionPassword []byte) ([][]byte, error) {
+	feeds := make([]*Feed, len(topics))
+	payloads := make([][]byte, len(topics))
+	for i, topic := range topics {
+		payload, err := a.sequencePayload(topic, data[i], encryptionPassword)
+		if err != nil {
+			return nil, err
+		}
+		payloads[i] = payload
+		feeds[i] = new(Feed)
+		feeds[i].User = user
+		copy(feeds[i].Topic[:], topic)
+	}
+
+	socs := make([]blockstore.SOC, len(topics))
+	updates := make([]*sequenceUpdate, len(topics))
+	err := blockstore.Parallel(ctx, len(topics), func(ctx context.Context, i int) error {
+		index, _, err := a.handler.nextIndex(ctx, feeds[i])
+		if err != nil { // skipcq: TCV-001
+			return err
+		}
+		socs[i], updates[i], err = a.newSequenceSOC(feeds[i], index, payloads[i])
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// send the soc chunks in one batch
+	addresses, err := a.handler.updateBatch(ctx, socs)
+	if err != nil {
+		return nil, err
+	}
+	for i, f := range feeds {
+		err = a.handler.setIndex(f, updates[i])
+		if err != nil { // skipcq: TCV-001
+			return nil, err
+		}
+	}
+	return addresses, nil
+}
+
+// sequencePayload checks and encrypts the payload of an update of a sequence feed
+func (a *API) sequencePayload(topic, data, encryptionPassword []byte) ([]byte, error) {
+	if a.accountInfo.GetPrivateKey() == nil {
+		return nil, ErrReadOnlyFeed
+	}
+	if len(topic) != TopicLength {
+		return nil, ErrInvalidTopicSize
+	}
+	if len(data) > utils.MaxChunkLength {
+		return nil, ErrInvalidPayloadSize
+	}
+	if encryptionPassword == nil || string(data) == utils.DeletedFeedMagicWord {
+		return data, nil
+	}
+	return utils.EncryptBytes(encryptionPassword, data)
+}
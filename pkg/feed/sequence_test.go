@@ -0,0 +1,233 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package feed_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/feeds"
+	"github.com/ethersphere/bee/pkg/feeds/sequence"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/account"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/bee/mock"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+)
+
+// countingClient counts the chunk downloads
+type countingClient struct {
+	blockstore.Client
+	downloads int64
+}
+
+func (c *countingClient) DownloadChunk(ctx context.Context, address []byte) ([]byte, error) {
+	atomic.AddInt64(&c.downloads, 1)
+	return c.Client.DownloadChunk(ctx, address)
+}
+
+// chunkGetter serves the chunks of a client to the feed lookups of bee
+type chunkGetter struct {
+	client blockstore.Client
+}
+
+func (g *chunkGetter) Get(ctx context.Context, _ storage.ModeGet, addr swarm.Address) (swarm.Chunk, error) {
+	data, err := g.client.DownloadChunk(ctx, addr.Bytes())
+	if err != nil {
+		return nil, storage.ErrNotFound
+	}
+	return swarm.NewChunk(addr, data), nil
+}
+
+func TestSequenceFeed(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.New(io.Discard, 0)
+	acc := account.New(logger)
+	_, _, err := acc.CreateUserAccount("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := acc.GetAddress(account.UserAccountIndex)
+	client := &countingClient{Client: mock.NewMockBeeClient()}
+	c := &clock{now: 1600000000}
+	feed.TimestampProvider = c
+	defer func() {
+		feed.TimestampProvider = feed.NewDefaultTimestampProvider()
+	}()
+	password := []byte("password")
+
+	t.Run("bee-compatible", func(t *testing.T) {
+		fd := feed.NewWithType(acc.GetUserAccountInfo(), client, feed.SequenceFeed, logger)
+		topic := utils.HashString("bee")
+		for i := 0; i < 3; i++ {
+			_, err := fd.UpdateFeed(ctx, topic, user, []byte(fmt.Sprintf("update %d", i)), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		beeFeed := feeds.New(topic, common.BytesToAddress(user.ToBytes()))
+		ch, err := feeds.Latest(ctx, sequence.NewFinder(&chunkGetter{client}, beeFeed), 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ch == nil {
+			t.Fatal("bee did not find the feed")
+		}
+		at, payload, err := feeds.FromChunk(ch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(payload) != "update 2" || at != c.now {
+			t.Fatalf("bee read %q at %d", payload, at)
+		}
+	})
+
+	t.Run("cached-index", func(t *testing.T) {
+		writer := feed.NewWithType(acc.GetUserAccountInfo(), client, feed.SequenceFeed, logger)
+		topic := utils.HashString("cached")
+		for i := 0; i < 40; i++ {
+			_, err := writer.UpdateFeed(ctx, topic, user, []byte(fmt.Sprintf("update %d", i)), password)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// the first read of another client searches for the latest index
+		reader := feed.NewWithType(acc.GetUserAccountInfo(), client, feed.SequenceFeed, logger)
+		_, data, err := reader.GetFeedData(ctx, topic, user, password)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != "update 39" {
+			t.Fatalf("expected the latest update, got %s", data)
+		}
+
+		// a read of a known feed only checks the next index
+		atomic.StoreInt64(&client.downloads, 0)
+		_, data, err = reader.GetFeedData(ctx, topic, user, password)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != "update 39" || atomic.LoadInt64(&client.downloads) != 1 {
+			t.Fatalf("got %s with %d downloads", data, client.downloads)
+		}
+
+		// the updates of the writer are found from the cached index
+		_, err = writer.UpdateFeed(ctx, topic, user, []byte("update 40"), password)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, data, err = reader.GetFeedData(ctx, topic, user, password)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != "update 40" {
+			t.Fatalf("expected the new update, got %s", data)
+		}
+	})
+
+	t.Run("history", func(t *testing.T) {
+		fd := feed.NewWithType(acc.GetUserAccountInfo(), client, feed.SequenceFeed, logger)
+		topic := utils.HashString("history")
+		start := c.now
+		for i := 0; i < 5; i++ {
+			c.now = start + uint64(i)*100
+			_, err := fd.UpdateFeed(ctx, topic, user, []byte(fmt.Sprintf("update %d", i)), password)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		history, err := fd.GetFeedHistory(ctx, topic, user, password)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(history) != 5 {
+			t.Fatalf("expected 5 updates, got %d", len(history))
+		}
+		for i, h := range history {
+			if h.Index != uint64(i) || h.Timestamp != start+uint64(i)*100 || string(h.Data) != fmt.Sprintf("update %d", i) {
+				t.Fatalf("unexpected update %d: %d %d %s", i, h.Index, h.Timestamp, h.Data)
+			}
+		}
+
+		_, data, err := fd.GetFeedDataAt(ctx, topic, user, start+250, password)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != "update 2" {
+			t.Fatalf("expected update 2, got %s", data)
+		}
+		_, _, err = fd.GetFeedDataAt(ctx, topic, user, start-1, password)
+		if err == nil {
+			t.Fatal("expected an error before the first update")
+		}
+	})
+
+	t.Run("create-feeds-and-delete", func(t *testing.T) {
+		fd := feed.NewWithType(acc.GetUserAccountInfo(), client, feed.SequenceFeed, logger)
+		topics := [][]byte{utils.HashString("batch1"), utils.HashString("batch2")}
+		addrs, err := fd.CreateFeeds(ctx, topics, user, [][]byte{[]byte("one"), []byte("two")}, password)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = fd.UpdateFeed(ctx, topics[0], user, []byte("three"), password)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr, data, err := fd.GetFeedData(ctx, topics[1], user, password)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(addr, addrs[1]) || string(data) != "two" {
+			t.Fatalf("unexpected update %s", data)
+		}
+
+		// deleting the latest update makes the previous one the latest
+		err = fd.DeleteFeed(ctx, topics[0], user)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr, data, err = fd.GetFeedData(ctx, topics[0], user, password)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(addr, addrs[0]) || string(data) != "one" {
+			t.Fatalf("expected the first update, got %s", data)
+		}
+	})
+
+	t.Run("parse-type", func(t *testing.T) {
+		for name, want := range map[string]feed.Type{"": feed.EpochFeed, "epoch": feed.EpochFeed, "Sequence": feed.SequenceFeed} {
+			got, err := feed.ParseType(name)
+			if err != nil || got != want {
+				t.Fatalf("parse %q: %v %v", name, got, err)
+			}
+		}
+		_, err := feed.ParseType("daily")
+		if err != feed.ErrUnknownFeedType {
+			t.Fatalf("expected unknown feed type, got %v", err)
+		}
+	})
+}
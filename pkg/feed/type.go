@@ -0,0 +1,61 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package feed
+
+import (
+	"errors"
+	"strings"
+)
+
+// Type is the indexing scheme of the updates of a feed
+type Type int
+
+const (
+	// EpochFeed places the updates in epochs by their time
+	EpochFeed Type = iota
+	// SequenceFeed numbers the updates, like the sequential feeds of bee
+	SequenceFeed
+)
+
+var (
+	// ErrUnknownFeedType is returned when a feed type name is not known
+	ErrUnknownFeedType = errors.New("unknown feed type")
+)
+
+// String returns the name of the feed type
+func (t Type) String() string {
+	switch t {
+	case EpochFeed:
+		return "epoch"
+	case SequenceFeed:
+		return "sequence"
+	default:
+		return ""
+	}
+}
+
+// ParseType returns the feed type of the given name. An empty name is an epoch feed.
+func ParseType(name string) (Type, error) {
+	switch strings.ToLower(name) {
+	case "", "epoch":
+		return EpochFeed, nil
+	case "sequence":
+		return SequenceFeed, nil
+	default:
+		return EpochFeed, ErrUnknownFeedType
+	}
+}
@@ -0,0 +1,155 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package feed
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+)
+
+const (
+	// DefaultWatchInterval is the time between two polls of a Watcher
+	DefaultWatchInterval = 5 * time.Second
+
+	watchEventBuffer = 16
+)
+
+// WatchEvent reports a new update of a watched topic
+type WatchEvent struct {
+	Topic []byte
+	// Reference is the address of the latest update, nil if the feed has no update
+	Reference []byte
+}
+
+// TopicsFunc returns the topics a Watcher polls. It is called before the first poll
+// and again after every poll that found a change, so the topics can follow the changes.
+type TopicsFunc func(ctx context.Context) ([][]byte, error)
+
+// Watcher polls the topics of a user and reports the ones that got a new update
+type Watcher struct {
+	api      *API
+	user     utils.Address
+	topics   TopicsFunc
+	interval time.Duration
+	latest   map[string]*watchedTopic // nil until the topic has been polled once
+	refresh  bool
+	events   chan *WatchEvent
+	pollMu   sync.Mutex
+}
+
+type watchedTopic struct {
+	reference []byte
+}
+
+// NewWatcher creates a watcher for the topics of a user, polled at the given interval
+func (a *API) NewWatcher(user utils.Address, topics TopicsFunc, interval time.Duration) *Watcher {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	return &Watcher{
+		api:      a,
+		user:     user,
+		topics:   topics,
+		interval: interval,
+		refresh:  true,
+		events:   make(chan *WatchEvent, watchEventBuffer),
+	}
+}
+
+// Events returns the channel Run sends the events to. It is closed when Run returns.
+func (w *Watcher) Events() <-chan *WatchEvent {
+	return w.events
+}
+
+// Poll looks up the latest update of every topic once and returns an event for each
+// topic that changed since the previous poll. The first poll of a topic only records
+// its latest update.
+func (w *Watcher) Poll(ctx context.Context) ([]*WatchEvent, error) {
+	w.pollMu.Lock()
+	defer w.pollMu.Unlock()
+
+	if w.refresh {
+		topics, err := w.topics(ctx)
+		if err != nil {
+			return nil, err
+		}
+		latest := make(map[string]*watchedTopic, len(topics))
+		for _, topic := range topics {
+			latest[string(topic)] = w.latest[string(topic)]
+		}
+		w.latest = latest
+	}
+
+	topics := make([][]byte, 0, len(w.latest))
+	for topic := range w.latest {
+		topics = append(topics, []byte(topic))
+	}
+	refs := make([][]byte, len(topics))
+	err := blockstore.Parallel(ctx, len(topics), func(ctx context.Context, i int) error {
+		ref, _, err := w.api.GetFeedData(ctx, topics[i], w.user, nil)
+		if err != nil && err.Error() != "feed does not exist or was not updated yet" {
+			return err
+		}
+		refs[i] = ref
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*WatchEvent
+	for i, topic := range topics {
+		previous := w.latest[string(topic)]
+		if previous != nil && !bytes.Equal(previous.reference, refs[i]) {
+			events = append(events, &WatchEvent{Topic: topic, Reference: refs[i]})
+		}
+		w.latest[string(topic)] = &watchedTopic{reference: refs[i]}
+	}
+	w.refresh = len(events) > 0
+	return events, nil
+}
+
+// Run polls the topics until the context is done and sends the events to the Events
+// channel. A failed poll is tried again at the next interval.
+func (w *Watcher) Run(ctx context.Context) {
+	defer close(w.events)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		events, err := w.Poll(ctx)
+		if err != nil {
+			w.api.logger.Debugf("watch poll failed: %v", err)
+		}
+		for _, event := range events {
+			select {
+			case w.events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package feed_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/account"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/bee/mock"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+)
+
+func TestWatcher(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.New(io.Discard, 0)
+	acc := account.New(logger)
+	_, _, err := acc.CreateUserAccount("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := acc.GetAddress(account.UserAccountIndex)
+	client := mock.NewMockBeeClient()
+	writer := feed.New(acc.GetUserAccountInfo(), client, logger)
+	reader := feed.New(acc.GetUserAccountInfo(), client, logger)
+	topic1, topic2, topic3 := utils.HashString("watch1"), utils.HashString("watch2"), utils.HashString("watch3")
+	_, err = writer.CreateFeed(ctx, topic1, user, []byte("first"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refreshes := 0
+	topics := [][]byte{topic1, topic2}
+	watcher := reader.NewWatcher(user, func(context.Context) ([][]byte, error) {
+		refreshes++
+		return topics, nil
+	}, 10*time.Millisecond)
+
+	// the first poll records the latest updates
+	events, err := watcher.Poll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 0 || refreshes != 1 {
+		t.Fatalf("expected no events, got %d after %d refreshes", len(events), refreshes)
+	}
+
+	// a feed without updates changes with its first update
+	ref, err := writer.CreateFeed(ctx, topic2, user, []byte("first"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, err = watcher.Poll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || !bytes.Equal(events[0].Topic, topic2) || !bytes.Equal(events[0].Reference, ref) {
+		t.Fatalf("expected an event for the second topic, got %v", events)
+	}
+
+	// the topics are read again after a change, a new topic is only recorded
+	topics = append(topics, topic3)
+	_, err = writer.CreateFeed(ctx, topic3, user, []byte("first"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, err = watcher.Poll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 0 || refreshes != 2 {
+		t.Fatalf("expected no events, got %d after %d refreshes", len(events), refreshes)
+	}
+
+	// run reports the updates made meanwhile
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		watcher.Run(ctx)
+		close(done)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	_, err = writer.UpdateFeed(ctx, topic3, user, []byte("second"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case event := <-watcher.Events():
+		if !bytes.Equal(event.Topic, topic3) {
+			t.Fatalf("expected an event for the third topic, got %x", event.Topic)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event for the update")
+	}
+	cancel()
+	<-done
+	if _, ok := <-watcher.Events(); ok {
+		t.Fatal("events should be closed")
+	}
+}
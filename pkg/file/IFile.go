@@ -16,14 +16,19 @@ limitations under the License.
 
 package file
 
-import "io"
+import (
+	"context"
+	"io"
+)
 
 // IFile
 type IFile interface {
-	Upload(fd io.Reader, podFileName string, fileSize int64, blockSize uint32, podPath, compression, podPassword string) error
-	Download(podFileWithPath, podPassword string) (io.ReadCloser, uint64, error)
-	ListFiles(files []string, podPassword string) ([]Entry, error)
-	GetStats(podName, podFileWithPath, podPassword string) (*Stats, error)
-	RmFile(podFileWithPath, podPassword string) error
-	LoadFileMeta(fileNameWithPath, podPassword string) error
+	Upload(ctx context.Context, fd io.Reader, podFileName string, fileSize int64, blockSize uint32, podPath, compression, podPassword string) error
+	Download(ctx context.Context, podFileWithPath, podPassword string) (io.ReadCloser, uint64, error)
+	ListFiles(ctx context.Context, files []string, podPassword string) ([]Entry, error)
+	GetStats(ctx context.Context, podName, podFileWithPath, podPassword string) (*Stats, error)
+	RmFile(ctx context.Context, podFileWithPath, podPassword string) error
+	LoadFileMeta(ctx context.Context, fileNameWithPath, podPassword string) error
+	References(ctx context.Context, podFileWithPath, podPassword string, mark func(ref []byte)) error
+	MoveVersions(ctx context.Context, fromPath, toPath, podPassword string) error
 }
@@ -0,0 +1,171 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+)
+
+var (
+	// ErrChecksumMismatch is returned when the content of a file does not match the
+	// checksum recorded when it was written
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+)
+
+// Verification is the result of checking the content of a file against its checksums
+type Verification struct {
+	FilePath          string `json:"filePath"`
+	Checksum          string `json:"checksum"`
+	BlockTreeChecksum string `json:"blockTreeChecksum"`
+	Blocks            int    `json:"blocks"`
+	// Unverified counts the blocks written before checksums were recorded
+	Unverified int   `json:"unverified"`
+	Corrupt    []int `json:"corruptBlocks"`
+	Valid      bool  `json:"valid"`
+}
+
+// blockChecksum returns the SHA-256 of the plaintext of a block
+func blockChecksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// blockTreeChecksum returns the SHA-256 of the checksums of the blocks of a file in order.
+// It is not the SHA-256 of the content, so it does not match sha256sum of the file, but it
+// changes with the content of any block and a WriteAt can update it without reading the
+// blocks it does not write. A file with a block that has no checksum has none either.
+func blockTreeChecksum(blocks []*BlockInfo) string {
+	h := sha256.New()
+	for _, b := range blocks {
+		sum, err := hex.DecodeString(b.Checksum)
+		if err != nil || len(sum) != sha256.Size {
+			return ""
+		}
+		_, _ = h.Write(sum)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// verifyBlock checks the plaintext of a block against its checksum, if it has one
+func verifyBlock(block *BlockInfo, data []byte) error {
+	if block.Checksum != "" && blockChecksum(data) != block.Checksum {
+		return ErrChecksumMismatch
+	}
+	return nil
+}
+
+// verifyInode checks that the blocks of an inode are the ones the checksum of the file
+// was computed from. The blocks are verified when they are read.
+func verifyInode(meta *MetaData, fileInode *INode) error {
+	if meta.BlockTreeChecksum != "" && blockTreeChecksum(fileInode.Blocks) != meta.BlockTreeChecksum {
+		return ErrChecksumMismatch
+	}
+	return nil
+}
+
+// contentChecksum downloads the blocks of a file in order and returns the SHA-256 of its
+// content together with its first 512 bytes, from which the content type is determined
+func (f *File) contentChecksum(ctx context.Context, blocks []*BlockInfo, compression string, blockSize uint32) (string, []byte, error) {
+	h := sha256.New()
+	var head []byte
+	for _, block := range blocks {
+		data, err := f.downloadBlock(ctx, block, compression, blockSize)
+		if err != nil {
+			return "", nil, err
+		}
+		err = verifyBlock(block, data)
+		if err != nil {
+			return "", nil, err
+		}
+		_, _ = h.Write(data)
+		if len(head) < 512 {
+			head = append(head, data...)
+		}
+	}
+	if len(head) > 512 {
+		head = head[:512]
+	}
+	return hex.EncodeToString(h.Sum(nil)), head, nil
+}
+
+// Verify downloads all the blocks of a file and checks them against their checksums and
+// the checksums of the file
+func (f *File) Verify(ctx context.Context, podFileWithPath, podPassword string) (*Verification, error) {
+	totalFilePath := utils.CombinePathAndFile(podFileWithPath, "")
+	if !f.IsFileAlreadyPresent(totalFilePath) {
+		return nil, ErrFileNotPresent
+	}
+	meta := f.GetFromFileMap(totalFilePath)
+	if meta == nil { // skipcq: TCV-001
+		return nil, ErrFileNotFound
+	}
+
+	fileInodeBytes, respCode, err := f.getClient().DownloadBlob(ctx, meta.InodeAddress)
+	if err != nil {
+		return nil, err
+	}
+	if respCode != http.StatusOK { // skipcq: TCV-001
+		return nil, errors.New("error downloading inode")
+	}
+	var fileInode INode
+	err = json.Unmarshal(fileInodeBytes, &fileInode)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+
+	v := &Verification{
+		FilePath:          totalFilePath,
+		Checksum:          meta.Checksum,
+		BlockTreeChecksum: meta.BlockTreeChecksum,
+		Blocks:            len(fileInode.Blocks),
+		Corrupt:           []int{},
+	}
+	// the content is hashed while the blocks are read, a block that cannot be read leaves
+	// the file invalid anyway
+	contentHash := sha256.New()
+	for i, b := range fileInode.Blocks {
+		if b.Checksum == "" {
+			v.Unverified++
+		}
+		data, err := f.downloadBlock(ctx, b, meta.Compression, meta.BlockSize)
+		if err != nil {
+			if ctx.Err() != nil { // skipcq: TCV-001
+				return nil, ctx.Err()
+			}
+			// a block that cannot be decompressed is corrupt too
+			f.logger.Warningf("verify: could not read block %d of %s: %v", i, totalFilePath, err)
+			v.Corrupt = append(v.Corrupt, i)
+			continue
+		}
+		if verifyBlock(b, data) != nil {
+			v.Corrupt = append(v.Corrupt, i)
+		}
+		_, _ = contentHash.Write(data)
+	}
+	v.Valid = len(v.Corrupt) == 0 && verifyInode(meta, &fileInode) == nil
+	if meta.Checksum != "" && hex.EncodeToString(contentHash.Sum(nil)) != meta.Checksum {
+		v.Valid = false
+	}
+	return v, nil
+}
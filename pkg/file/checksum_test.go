@@ -0,0 +1,278 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/account"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/bee/mock"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/fault"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/file"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/pod"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+	"github.com/plexsysio/taskmanager"
+)
+
+func TestChecksum(t *testing.T) {
+	ctx := context.Background()
+	mockClient := mock.NewMockBeeClient()
+	faultClient := fault.NewClient(mockClient, 1)
+	logger := logging.New(io.Discard, 0)
+	acc := account.New(logger)
+	_, _, err := acc.CreateUserAccount("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tm := taskmanager.New(1, 10, time.Second*15, logger)
+	defer func() {
+		_ = tm.Stop(context.Background())
+	}()
+
+	// every subtest uses a pod of its own
+	podIndex := 0
+	newFile := func(t *testing.T) (*file.File, string) {
+		t.Helper()
+		podIndex++
+		podAccountInfo, err := acc.CreatePodAccount(podIndex, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fd := feed.New(podAccountInfo, faultClient, logger)
+		podPassword, _ := utils.GetRandString(pod.PasswordLength)
+		return file.NewFile("pod1", faultClient, fd, acc.GetAddress(podIndex), tm, logger), podPassword
+	}
+	upload := func(t *testing.T, fileObject *file.File, fileName string, content []byte, podPassword string) {
+		t.Helper()
+		err := fileObject.Upload(ctx, bytes.NewReader(content), fileName, int64(len(content)), 10, "/dir1", "", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	checksum := func(t *testing.T, fileObject *file.File, podFile, podPassword string) string {
+		t.Helper()
+		stats, err := fileObject.GetStats(ctx, "pod1", podFile, podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, b := range stats.Blocks {
+			if len(b.Checksum) != 64 {
+				t.Fatalf("block %d has no checksum", i)
+			}
+		}
+		if len(stats.BlockTreeChecksum) != 64 {
+			t.Fatalf("invalid checksum %q", stats.BlockTreeChecksum)
+		}
+		return stats.BlockTreeChecksum
+	}
+	// contentSum checks that the checksum of the file is the one sha256sum prints for content
+	contentSum := func(t *testing.T, fileObject *file.File, podFile, podPassword string, content []byte) {
+		t.Helper()
+		stats, err := fileObject.GetStats(ctx, "pod1", podFile, podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sum := sha256.Sum256(content)
+		if stats.Checksum != hex.EncodeToString(sum[:]) {
+			t.Fatalf("expected checksum %x, got %q", sum, stats.Checksum)
+		}
+	}
+	verify := func(t *testing.T, fileObject *file.File, podFile, podPassword string) *file.Verification {
+		t.Helper()
+		v, err := fileObject.Verify(ctx, podFile, podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	t.Run("upload", func(t *testing.T) {
+		fileObject, podPassword := newFile(t)
+		content := []byte("the content of the file spans a few blocks")
+		upload(t, fileObject, "file1", content, podPassword)
+		sum := checksum(t, fileObject, "/dir1/file1", podPassword)
+		contentSum(t, fileObject, "/dir1/file1", podPassword, content)
+
+		v := verify(t, fileObject, "/dir1/file1", podPassword)
+		if !v.Valid || v.BlockTreeChecksum != sum || len(v.Checksum) != 64 || v.Blocks != 5 || v.Unverified != 0 || len(v.Corrupt) != 0 {
+			t.Fatalf("unexpected verification %+v", v)
+		}
+
+		// the same content has the same checksum
+		upload(t, fileObject, "file2", content, podPassword)
+		if checksum(t, fileObject, "/dir1/file2", podPassword) != sum {
+			t.Fatal("the same content should have the same checksum")
+		}
+
+		_, err := fileObject.Verify(ctx, "/dir1/file3", podPassword)
+		if !errors.Is(err, file.ErrFileNotPresent) {
+			t.Fatalf("expected %v, got %v", file.ErrFileNotPresent, err)
+		}
+	})
+
+	t.Run("writeat", func(t *testing.T) {
+		fileObject, podPassword := newFile(t)
+		upload(t, fileObject, "file1", []byte("the content of the file spans a few blocks"), podPassword)
+		before := checksum(t, fileObject, "/dir1/file1", podPassword)
+
+		_, err := fileObject.WriteAt(ctx, "/dir1/file1", podPassword, bytes.NewReader([]byte("CONTENT")), 4, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		after := checksum(t, fileObject, "/dir1/file1", podPassword)
+		if after == before {
+			t.Fatal("checksum should change with the content")
+		}
+		contentSum(t, fileObject, "/dir1/file1", podPassword, []byte("the CONTENT of the file spans a few blocks"))
+		if v := verify(t, fileObject, "/dir1/file1", podPassword); !v.Valid || v.BlockTreeChecksum != after {
+			t.Fatalf("unexpected verification %+v", v)
+		}
+
+		// the checksum is the one of the same content uploaded at once
+		upload(t, fileObject, "file2", []byte("the CONTENT of the file spans a few blocks"), podPassword)
+		if checksum(t, fileObject, "/dir1/file2", podPassword) != after {
+			t.Fatal("checksum after writeAt differs from the one of the same content")
+		}
+	})
+
+	t.Run("truncate", func(t *testing.T) {
+		fileObject, podPassword := newFile(t)
+		upload(t, fileObject, "file1", []byte("the content of the file spans a few blocks"), podPassword)
+		_, err := fileObject.WriteAt(ctx, "/dir1/file1", podPassword, bytes.NewReader([]byte("text")), 12, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		contentSum(t, fileObject, "/dir1/file1", podPassword, []byte("the content text"))
+		if v := verify(t, fileObject, "/dir1/file1", podPassword); !v.Valid {
+			t.Fatalf("unexpected verification %+v", v)
+		}
+	})
+
+	t.Run("upload-session", func(t *testing.T) {
+		fileObject, podPassword := newFile(t)
+		content := []byte("the parts of the content are sent out of order")
+		s, err := fileObject.CreateUploadSession(ctx, "file1", int64(len(content)), 10, "/dir1", "snappy", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, offset := range []int{30, 0, 20, 10, 40} {
+			end := offset + 10
+			if end > len(content) {
+				end = len(content)
+			}
+			_, err = fileObject.UploadPart(ctx, s.ID, uint64(offset), bytes.NewReader(content[offset:end]), podPassword)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		_, err = fileObject.FinishUploadSession(ctx, s.ID, podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		contentSum(t, fileObject, "/dir1/file1", podPassword, content)
+		if v := verify(t, fileObject, "/dir1/file1", podPassword); !v.Valid {
+			t.Fatalf("unexpected verification %+v", v)
+		}
+	})
+
+	t.Run("content-mismatch", func(t *testing.T) {
+		fileObject, podPassword := newFile(t)
+		upload(t, fileObject, "file1", []byte("the content of the file spans a few blocks"), podPassword)
+
+		// the blocks match their checksums but not the checksum of the content
+		meta := *fileObject.GetFromFileMap("/dir1/file1")
+		sum := sha256.Sum256([]byte("some other content"))
+		meta.Checksum = hex.EncodeToString(sum[:])
+		fileObject.AddToFileMap("/dir1/file1", &meta)
+		v := verify(t, fileObject, "/dir1/file1", podPassword)
+		if v.Valid || len(v.Corrupt) != 0 || v.Checksum != meta.Checksum {
+			t.Fatalf("unexpected verification %+v", v)
+		}
+	})
+
+	t.Run("corrupt-block", func(t *testing.T) {
+		fileObject, podPassword := newFile(t)
+		content := []byte("the content of the file spans a few blocks")
+		upload(t, fileObject, "file1", content, podPassword)
+
+		// the first blob downloaded is the inode, the second the first block
+		faultClient.SetRules(fault.Rule{Ops: []fault.Op{fault.OpDownloadBlob}, Kind: fault.Corrupt, After: 1, Times: 1})
+		defer faultClient.SetRules()
+		v := verify(t, fileObject, "/dir1/file1", podPassword)
+		if v.Valid || len(v.Corrupt) != 1 || v.Corrupt[0] != 0 {
+			t.Fatalf("unexpected verification %+v", v)
+		}
+
+		faultClient.SetRules(fault.Rule{Ops: []fault.Op{fault.OpDownloadBlob}, Kind: fault.Corrupt, After: 2, Times: 1})
+		reader, _, err := fileObject.Download(ctx, "/dir1/file1", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer reader.Close()
+		_, err = io.ReadAll(reader)
+		if !errors.Is(err, file.ErrChecksumMismatch) {
+			t.Fatalf("expected %v, got %v", file.ErrChecksumMismatch, err)
+		}
+
+		faultClient.SetRules()
+		if v := verify(t, fileObject, "/dir1/file1", podPassword); !v.Valid {
+			t.Fatalf("unexpected verification %+v", v)
+		}
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		fileObject, podPassword := newFile(t)
+		err := fileObject.SetVersionPolicy(ctx, file.VersionPolicy{Enabled: true}, podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		upload(t, fileObject, "file1", []byte("the first content of the file"), podPassword)
+		first := checksum(t, fileObject, "/dir1/file1", podPassword)
+		upload(t, fileObject, "file1", []byte("the second content of the file"), podPassword)
+		if checksum(t, fileObject, "/dir1/file1", podPassword) == first {
+			t.Fatal("checksum should change with the content")
+		}
+
+		versions, err := fileObject.ListVersions(ctx, "/dir1/file1", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(versions) != 1 || versions[0].BlockTreeChecksum != first {
+			t.Fatalf("unexpected versions %+v", versions)
+		}
+		_, err = fileObject.RestoreVersion(ctx, "/dir1/file1", versions[0].Version, podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if checksum(t, fileObject, "/dir1/file1", podPassword) != first {
+			t.Fatal("restore should bring back the checksum of the version")
+		}
+		contentSum(t, fileObject, "/dir1/file1", podPassword, []byte("the first content of the file"))
+		if v := verify(t, fileObject, "/dir1/file1", podPassword); !v.Valid {
+			t.Fatalf("unexpected verification %+v", v)
+		}
+	})
+}
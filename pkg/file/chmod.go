@@ -1,6 +1,7 @@
 package file
 
 import (
+	"context"
 	"time"
 
 	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
@@ -8,7 +9,7 @@ import (
 )
 
 // Chmod does all the validation for the existence of the file and changes file mode
-func (f *File) Chmod(podFileWithPath, podPassword string, mode uint32) error {
+func (f *File) Chmod(ctx context.Context, podFileWithPath, podPassword string, mode uint32) error {
 	// TODO check valid mode
 	if f.fd.IsReadOnlyFeed() { // skipcq: TCV-001
 		return feed.ErrReadOnlyFeed
@@ -27,7 +28,7 @@ func (f *File) Chmod(podFileWithPath, podPassword string, mode uint32) error {
 	meta.Mode = S_IFREG | mode
 	meta.AccessTime = time.Now().Unix()
 
-	err := f.updateMeta(meta, podPassword)
+	err := f.updateMeta(ctx, meta, podPassword)
 	if err != nil {
 		return err
 	}
@@ -20,6 +20,7 @@ import (
 )
 
 func TestChmod(t *testing.T) {
+	ctx := context.Background()
 	mockClient := mock.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -47,18 +48,18 @@ func TestChmod(t *testing.T) {
 		require.NoError(t, err)
 
 		// stat the file
-		stats, err := fileObject.GetStats("pod1", "/dir1/file1", podPassword)
+		stats, err := fileObject.GetStats(ctx, "pod1", "/dir1/file1", podPassword)
 		require.NoError(t, err)
 
 		assert.Equal(t, fmt.Sprintf("%o", file.S_IFREG|0600), fmt.Sprintf("%o", stats.Mode))
 
-		err = fileObject.Chmod("/dir1/file2", podPassword, 0777)
+		err = fileObject.Chmod(ctx, "/dir1/file2", podPassword, 0777)
 		assert.Equal(t, err, file.ErrFileNotPresent)
 
-		err = fileObject.Chmod("/dir1/file1", podPassword, 0777)
+		err = fileObject.Chmod(ctx, "/dir1/file1", podPassword, 0777)
 		require.NoError(t, err)
 
-		stats, err = fileObject.GetStats("pod1", "/dir1/file1", podPassword)
+		stats, err = fileObject.GetStats(ctx, "pod1", "/dir1/file1", podPassword)
 		require.NoError(t, err)
 
 		assert.Equal(t, fmt.Sprintf("%o", file.S_IFREG|0777), fmt.Sprintf("%o", stats.Mode))
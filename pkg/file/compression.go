@@ -0,0 +1,250 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/klauspost/pgzip"
+)
+
+const (
+	// noCompression marks a block stored as it is in a compressed file, because
+	// compressing it did not make it smaller
+	noCompression = "none"
+
+	minZstdLevel = 1
+	maxZstdLevel = 22
+
+	// zstdMemoryBlocks is the number of blocks the memory of a zstd decoder is limited to
+	zstdMemoryBlocks = 2
+)
+
+var (
+	//ErrInvalidCompression
+	ErrInvalidCompression = errors.New("invalid compression, should be one of gzip, snappy, zstd, zstd:<level> or lz4")
+
+	zstdEncoders sync.Map // zstd.EncoderLevel -> *zstd.Encoder
+	zstdDecoders sync.Map // block size -> *zstd.Decoder
+)
+
+// CheckCompression returns ErrInvalidCompression if the compression of a file is not
+// supported. The level of zstd, from 1 to 22, is given after a colon, like "zstd:19".
+func CheckCompression(compression string) error {
+	name, level := splitCompression(compression)
+	switch name {
+	case "", "gzip", "snappy", "lz4":
+		if level != "" {
+			return ErrInvalidCompression
+		}
+	case "zstd":
+		if level == "" {
+			return nil
+		}
+		l, err := strconv.Atoi(level)
+		if err != nil || l < minZstdLevel || l > maxZstdLevel {
+			return ErrInvalidCompression
+		}
+	default:
+		return ErrInvalidCompression
+	}
+	return nil
+}
+
+// splitCompression splits the compression of a file into the algorithm and its level
+func splitCompression(compression string) (string, string) {
+	name, level, _ := strings.Cut(compression, ":")
+	return name, level
+}
+
+// compressBlock compresses a block of a file and returns the data to store with the
+// block information. A zstd or lz4 block that does not get smaller is stored as it
+// is. Blocks of gzip and snappy files are always compressed, so that older versions
+// can still read them.
+func compressBlock(data []byte, compression string, blockSize uint32) (*BlockInfo, []byte, error) {
+	block := &BlockInfo{
+		Size:     uint32(len(data)),
+		Checksum: blockChecksum(data),
+	}
+	uploadData := data
+	if compression != "" {
+		compressed, err := Compress(data, compression, blockSize)
+		if err != nil { // skipcq: TCV-001
+			return nil, nil, err
+		}
+		name, _ := splitCompression(compression)
+		block.Compression = name
+		if len(compressed) >= len(data) && (name == "zstd" || name == "lz4") {
+			block.Compression = noCompression
+		} else {
+			uploadData = compressed
+		}
+	}
+	block.CompressedSize = uint32(len(uploadData))
+	return block, uploadData, nil
+}
+
+// decompressBlock decompresses a block with the compression recorded in it. Blocks
+// written before the compression was recorded per block use the one of the file.
+func decompressBlock(block *BlockInfo, data []byte, compression string, blockSize uint32) ([]byte, error) {
+	switch block.Compression {
+	case "":
+	case noCompression:
+		return data, nil
+	default:
+		compression = block.Compression
+	}
+	if compression == "lz4" {
+		// the LZ4 block format does not hold the size of the block, the block information does
+		decompressed, err := lz4Decompress(data, block.Size)
+		if err != nil {
+			return nil, err
+		}
+		if len(decompressed) != int(block.Size) {
+			return nil, errLZ4Corrupt
+		}
+		return decompressed, nil
+	}
+	return Decompress(data, compression, blockSize)
+}
+
+// Compress data
+func Compress(dataToCompress []byte, compression string, blockSize uint32) ([]byte, error) {
+	name, level := splitCompression(compression)
+	switch name {
+	case "gzip":
+		var b bytes.Buffer
+		w := pgzip.NewWriter(&b)
+		block := int(blockSize / 10)
+		err := w.SetConcurrency(block, 10)
+		if err != nil {
+			return nil, err
+		}
+		_, err = w.Write(dataToCompress)
+		if err != nil { // skipcq: TCV-001
+			return nil, err
+		}
+		err = w.Close()
+		if err != nil {
+			return nil, err
+		}
+		return b.Bytes(), nil
+	case "snappy":
+		return snappy.Encode(nil, dataToCompress), nil
+	case "zstd":
+		encoder, err := getZstdEncoder(level)
+		if err != nil {
+			return nil, err
+		}
+		return encoder.EncodeAll(dataToCompress, nil), nil
+	case "lz4":
+		return lz4Compress(dataToCompress)
+	}
+	return dataToCompress, nil
+}
+
+// Decompress decompresses data that is at most blockSize bytes long once decompressed
+func Decompress(dataToDecompress []byte, compression string, blockSize uint32) ([]byte, error) {
+	name, _ := splitCompression(compression)
+	switch name {
+	case "gzip":
+		br := bytes.NewReader(dataToDecompress)
+		block := int(blockSize / 10)
+		r, err := pgzip.NewReaderN(br, block, 10)
+		if err != nil { // skipcq: TCV-001
+			return nil, err
+		}
+		s, err := io.ReadAll(r)
+		if err != nil { // skipcq: TCV-001
+			return nil, err
+		}
+		err = r.Close()
+		if err != nil { // skipcq: TCV-001
+			return nil, err
+		}
+		return s, nil
+	case "snappy":
+		decoded, err := snappy.Decode(nil, dataToDecompress)
+		if err != nil { // skipcq: TCV-001
+			return nil, err
+		}
+		return decoded, nil
+	case "zstd":
+		decoder, err := getZstdDecoder(blockSize)
+		if err != nil { // skipcq: TCV-001
+			return nil, err
+		}
+		return decoder.DecodeAll(dataToDecompress, nil)
+	case "lz4":
+		return lz4Decompress(dataToDecompress, blockSize)
+	}
+	return dataToDecompress, nil
+}
+
+// getZstdEncoder returns the encoder of a level, shared by all the files. EncodeAll
+// can be called concurrently.
+func getZstdEncoder(level string) (*zstd.Encoder, error) {
+	encoderLevel := zstd.SpeedDefault
+	if level != "" {
+		l, err := strconv.Atoi(level)
+		if err != nil || l < minZstdLevel || l > maxZstdLevel {
+			return nil, ErrInvalidCompression
+		}
+		encoderLevel = zstd.EncoderLevelFromZstd(l)
+	}
+	if encoder, ok := zstdEncoders.Load(encoderLevel); ok {
+		return encoder.(*zstd.Encoder), nil
+	}
+	encoder, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(encoderLevel))
+	if err != nil { // skipcq: TCV-001
+		return nil, fmt.Errorf("zstd: %w", err)
+	}
+	actual, _ := zstdEncoders.LoadOrStore(encoderLevel, encoder)
+	return actual.(*zstd.Encoder), nil
+}
+
+// getZstdDecoder returns the decoder of a block size, shared by all the files. A block
+// never decodes to more than the block size, so the memory of the decoder is limited to
+// a few blocks and a corrupt block cannot make it allocate more. DecodeAll can be called
+// concurrently.
+func getZstdDecoder(blockSize uint32) (*zstd.Decoder, error) {
+	if decoder, ok := zstdDecoders.Load(blockSize); ok {
+		return decoder.(*zstd.Decoder), nil
+	}
+	// the window of a frame is at least zstd.MinWindowSize, however small the block
+	maxMemory := uint64(blockSize) * zstdMemoryBlocks
+	if maxMemory < zstd.MinWindowSize {
+		maxMemory = zstd.MinWindowSize
+	}
+	decoder, err := zstd.NewReader(nil, zstd.WithDecoderMaxMemory(maxMemory))
+	if err != nil { // skipcq: TCV-001
+		return nil, fmt.Errorf("zstd: %w", err)
+	}
+	actual, loaded := zstdDecoders.LoadOrStore(blockSize, decoder)
+	if loaded {
+		decoder.Close()
+	}
+	return actual.(*zstd.Decoder), nil
+}
@@ -0,0 +1,244 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/account"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/bee/mock"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/file"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/pod"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+	"github.com/pierrec/lz4/v4"
+	"github.com/plexsysio/taskmanager"
+)
+
+func TestCompress(t *testing.T) {
+	random := make([]byte, 200000)
+	_, err := rand.Read(random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// repeats further apart than the longest lz4 offset
+	repeated := append(append(append([]byte{}, random[:70000]...), bytes.Repeat([]byte("fairOS"), 1000)...), random[:70000]...)
+	inputs := map[string][]byte{
+		"empty":    {},
+		"short":    []byte("abc"),
+		"zeros":    make([]byte, 200000),
+		"random":   random,
+		"repeated": repeated,
+		"text":     bytes.Repeat([]byte("the quick brown fox jumps over the lazy dog\n"), 4500),
+	}
+
+	for _, compression := range []string{"gzip", "snappy", "zstd", "zstd:1", "zstd:19", "lz4"} {
+		for name, input := range inputs {
+			compressed, err := file.Compress(input, compression, 200000)
+			if err != nil {
+				t.Fatalf("%s %s: %v", compression, name, err)
+			}
+			output, err := file.Decompress(compressed, compression, 200000)
+			if err != nil {
+				t.Fatalf("%s %s: %v", compression, name, err)
+			}
+			if !bytes.Equal(input, output) {
+				t.Fatalf("%s %s: content mismatch", compression, name)
+			}
+			if name == "zeros" && len(compressed) > len(input)/20 {
+				t.Fatalf("%s: zeros compressed to %d bytes", compression, len(compressed))
+			}
+		}
+	}
+
+	t.Run("lz4-block-format", func(t *testing.T) {
+		// the literals "abc", a match of 9 bytes at offset 3 and the last literals "defgh"
+		block := []byte{0x35, 'a', 'b', 'c', 3, 0, 0x50, 'd', 'e', 'f', 'g', 'h'}
+		output, err := file.Decompress(block, "lz4", 17)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(output) != "abcabcabcabcdefgh" {
+			t.Fatalf("unexpected content %q", output)
+		}
+
+		for _, corrupt := range [][]byte{
+			{0x35, 'a', 'b', 'c', 4, 0, 0x50, 'd', 'e', 'f', 'g', 'h'},
+			{0x35, 'a', 'b', 'c', 3},
+			{0xf0, 255, 255, 255, 255},
+		} {
+			_, err = file.Decompress(corrupt, "lz4", 17)
+			if err == nil {
+				t.Fatalf("corrupt block %v should fail", corrupt)
+			}
+		}
+
+		// a block cannot be larger than the block size of the file
+		_, err = file.Decompress(block, "lz4", 16)
+		if err == nil {
+			t.Fatal("block larger than the block size should fail")
+		}
+
+		// the blocks are standard LZ4 blocks
+		input := bytes.Repeat([]byte("fairOS-dfs "), 100)
+		compressed, err := file.Compress(input, "lz4", 2000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decompressed := make([]byte, len(input))
+		n, err := lz4.UncompressBlock(compressed, decompressed)
+		if err != nil || !bytes.Equal(decompressed[:n], input) {
+			t.Fatalf("compressed block is not a standard LZ4 block: %v", err)
+		}
+	})
+
+	t.Run("zstd-memory-limit", func(t *testing.T) {
+		compressed, err := file.Compress(make([]byte, 100000), "zstd", 100000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// a block that decodes to more than a few blocks is rejected
+		_, err = file.Decompress(compressed, "zstd", 1000)
+		if err == nil {
+			t.Fatal("block larger than the decoder memory should fail")
+		}
+		output, err := file.Decompress(compressed, "zstd", 100000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(output) != 100000 {
+			t.Fatalf("expected 100000 bytes, got %d", len(output))
+		}
+	})
+
+	t.Run("check", func(t *testing.T) {
+		for _, compression := range []string{"", "gzip", "snappy", "zstd", "zstd:1", "zstd:22", "lz4"} {
+			if err := file.CheckCompression(compression); err != nil {
+				t.Fatalf("%q should be valid: %v", compression, err)
+			}
+		}
+		for _, compression := range []string{"zip", "zstd:0", "zstd:23", "zstd:fast", "lz4:9", "gzip:1", "ZSTD"} {
+			if err := file.CheckCompression(compression); !errors.Is(err, file.ErrInvalidCompression) {
+				t.Fatalf("%q should be invalid", compression)
+			}
+		}
+	})
+}
+
+func TestCompression(t *testing.T) {
+	ctx := context.Background()
+	mockClient := mock.NewMockBeeClient()
+	logger := logging.New(io.Discard, 0)
+	acc := account.New(logger)
+	_, _, err := acc.CreateUserAccount("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pod1AccountInfo, err := acc.CreatePodAccount(1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fd := feed.New(pod1AccountInfo, mockClient, logger)
+	user := acc.GetAddress(1)
+	tm := taskmanager.New(1, 10, time.Second*15, logger)
+	defer func() {
+		_ = tm.Stop(context.Background())
+	}()
+	podPassword, _ := utils.GetRandString(pod.PasswordLength)
+	fileObject := file.NewFile("pod1", mockClient, fd, user, tm, logger)
+
+	// blocks of random bytes do not compress, blocks of zeros do
+	random := make([]byte, 300)
+	_, err = rand.Read(random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := append(append(append([]byte{}, random[:200]...), make([]byte, 200)...), random[200:]...)
+
+	blockCompressions := func(t *testing.T, podFile string) []string {
+		t.Helper()
+		stats, err := fileObject.GetStats(ctx, "pod1", podFile, podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var compressions []string
+		for _, b := range stats.Blocks {
+			compressions = append(compressions, b.Compression)
+		}
+		return compressions
+	}
+
+	for _, compression := range []string{"zstd", "zstd:19", "lz4"} {
+		name, _, _ := strings.Cut(compression, ":")
+		t.Run(compression, func(t *testing.T) {
+			podFile := "/file-" + strings.ReplaceAll(compression, ":", "-")
+			err := fileObject.Upload(ctx, bytes.NewReader(content), podFile[1:], int64(len(content)), 100, "/", compression, podPassword)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertContent(t, fileObject, podFile, podPassword, content)
+
+			expected := []string{"none", "none", name, name, "none"}
+			compressions := blockCompressions(t, podFile)
+			if len(compressions) != len(expected) {
+				t.Fatalf("expected %d blocks, got %d", len(expected), len(compressions))
+			}
+			for i := range expected {
+				if compressions[i] != expected[i] {
+					t.Fatalf("expected blocks compressed with %v, got %v", expected, compressions)
+				}
+			}
+
+			// overwriting random bytes with zeros compresses the block
+			_, err = fileObject.WriteAt(ctx, podFile, podPassword, bytes.NewReader(make([]byte, 100)), 400, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			updated := append(append([]byte{}, content[:400]...), make([]byte, 100)...)
+			assertContent(t, fileObject, podFile, podPassword, updated)
+			if compressions := blockCompressions(t, podFile); compressions[4] != name {
+				t.Fatalf("expected the updated block to be compressed with %s, got %v", name, compressions)
+			}
+			v, err := fileObject.Verify(ctx, podFile, podPassword)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !v.Valid {
+				t.Fatalf("unexpected verification %+v", v)
+			}
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		err := fileObject.Upload(ctx, bytes.NewReader(content), "invalid", int64(len(content)), 100, "/", "zstd:99", podPassword)
+		if !errors.Is(err, file.ErrInvalidCompression) {
+			t.Fatalf("expected %v, got %v", file.ErrInvalidCompression, err)
+		}
+		_, err = fileObject.CreateUploadSession(ctx, "invalid", int64(len(content)), 100, "/", "brotli", podPassword)
+		if !errors.Is(err, file.ErrInvalidCompression) {
+			t.Fatalf("expected %v, got %v", file.ErrInvalidCompression, err)
+		}
+	})
+}
@@ -0,0 +1,127 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+)
+
+// CopyFrom copies a file of src, which can be this pod or another pod of the user, to
+// dstFileWithPath. The blocks are encrypted with keys that are part of their references and
+// not with the pod password, so the copy can refer to the blocks of the source file and
+// only its inode and metadata are written; shared is then called with every block. When
+// shared is nil the blocks are uploaded again with new keys, which is needed when the pins
+// of the source cannot be relied on, as for a pod shared by another user.
+func (f *File) CopyFrom(ctx context.Context, src *File, srcFileWithPath, dstFileWithPath, podPassword string, shared func(ref []byte)) (*MetaData, error) {
+	srcFileWithPath = utils.CombinePathAndFile(srcFileWithPath, "")
+	dstFileWithPath = utils.CombinePathAndFile(dstFileWithPath, "")
+	meta := src.GetFromFileMap(srcFileWithPath)
+	if meta == nil {
+		return nil, ErrFileNotPresent
+	}
+	if f.IsFileAlreadyPresent(dstFileWithPath) {
+		return nil, ErrFileAlreadyPresent
+	}
+
+	fileInodeBytes, respCode, err := src.getClient().DownloadBlob(ctx, meta.InodeAddress)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	if respCode != http.StatusOK { // skipcq: TCV-001
+		return nil, fmt.Errorf("could not download inode %x", meta.InodeAddress)
+	}
+	var fileInode INode
+	err = json.Unmarshal(fileInodeBytes, &fileInode)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	for _, block := range fileInode.Blocks {
+		if shared != nil {
+			shared(block.Reference.Bytes())
+			continue
+		}
+		// the stored bytes are uploaded as they are, still compressed
+		data, respCode, err := src.getClient().DownloadBlob(ctx, block.Reference.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		if respCode != http.StatusOK { // skipcq: TCV-001
+			return nil, errors.New("error downloading block")
+		}
+		ref, err := f.client.UploadBlob(ctx, data, 0, true, true)
+		if err != nil { // skipcq: TCV-001
+			return nil, err
+		}
+		block.Reference = utils.NewReference(ref)
+	}
+	fileInodeData, err := json.Marshal(fileInode)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	addr, err := f.client.UploadBlob(ctx, fileInodeData, 0, true, true)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	copied := *meta
+	copied.Path = filepath.ToSlash(filepath.Dir(dstFileWithPath))
+	copied.Name = filepath.Base(dstFileWithPath)
+	copied.CreationTime = now
+	copied.AccessTime = now
+	copied.ModificationTime = now
+	copied.InodeAddress = addr
+	copied.Xattrs = utils.CopyXattrs(meta.Xattrs)
+	copied.SharedBlocks = shared != nil
+	err = f.handleMeta(ctx, &copied, podPassword)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	f.AddToFileMap(dstFileWithPath, &copied)
+	return &copied, nil
+}
+
+// ShareBlocks marks the blocks of a file as used by a copy, so that removing the file
+// leaves them to the garbage collector
+func (f *File) ShareBlocks(ctx context.Context, podFileWithPath, podPassword string) error {
+	totalFilePath := utils.CombinePathAndFile(podFileWithPath, "")
+	meta := f.GetFromFileMap(totalFilePath)
+	if meta == nil {
+		return ErrFileNotPresent
+	}
+	if meta.SharedBlocks {
+		return nil
+	}
+
+	// the meta in the file map is replaced, not changed, as readers may hold it
+	updated := *meta
+	updated.SharedBlocks = true
+	err := f.updateMeta(ctx, &updated, podPassword)
+	if err != nil { // skipcq: TCV-001
+		return err
+	}
+	f.AddToFileMap(totalFilePath, &updated)
+	return nil
+}
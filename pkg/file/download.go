@@ -17,6 +17,7 @@ limitations under the License.
 package file
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
@@ -41,44 +42,59 @@ var (
 
 // Download does all the validation for the existence of the file and creates a
 // Reader to read the contents of the file from the pod.
-func (f *File) Download(podFileWithPath, podPassword string) (io.ReadCloser, uint64, error) {
-	return f.ReadSeeker(podFileWithPath, podPassword)
+func (f *File) Download(ctx context.Context, podFileWithPath, podPassword string) (io.ReadCloser, uint64, error) {
+	return f.ReadSeeker(ctx, podFileWithPath, podPassword)
 }
 
 // ReadSeeker does all the validation for the existence of the file and creates a
 // ReadSeekCloser to read the contents of the file from the pod.
-func (f *File) ReadSeeker(podFileWithPath, podPassword string) (io.ReadSeekCloser, uint64, error) {
+func (f *File) ReadSeeker(ctx context.Context, podFileWithPath, podPassword string) (io.ReadSeekCloser, uint64, error) {
+	reader, meta, err := f.Open(ctx, podFileWithPath, podPassword)
+	if err != nil {
+		return nil, 0, err
+	}
+	return reader, meta.Size, nil
+}
+
+// Open creates a ReadSeekCloser to read the contents of the file like ReadSeeker, and
+// also returns the metadata of the content it reads.
+func (f *File) Open(ctx context.Context, podFileWithPath, podPassword string) (io.ReadSeekCloser, *MetaData, error) {
 	// check if file present
 	totalFilePath := utils.CombinePathAndFile(podFileWithPath, "")
 	if !f.IsFileAlreadyPresent(totalFilePath) {
-		return nil, 0, ErrFileNotPresent
+		return nil, nil, ErrFileNotPresent
 	}
 
 	meta := f.GetFromFileMap(totalFilePath)
 	if meta == nil { // skipcq: TCV-001
-		return nil, 0, ErrFileNotFound
+		return nil, nil, ErrFileNotFound
 	}
 
-	fileInodeBytes, _, err := f.getClient().DownloadBlob(meta.InodeAddress)
+	fileInodeBytes, _, err := f.getClient().DownloadBlob(ctx, meta.InodeAddress)
 	if err != nil { // skipcq: TCV-001
-		return nil, 0, err
+		return nil, nil, err
 	}
 
 	var fileInode INode
 	err = json.Unmarshal(fileInodeBytes, &fileInode)
 	if err != nil { // skipcq: TCV-001
-		return nil, 0, err
+		return nil, nil, err
+	}
+	err = verifyInode(meta, &fileInode)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// need to change the access time for podFile if it is owned by user
 	if !f.fd.IsReadOnlyFeed() {
 		meta.AccessTime = time.Now().Unix()
-		err = f.updateMeta(meta, podPassword)
+		err = f.updateMeta(ctx, meta, podPassword)
 		if err != nil { // skipcq: TCV-001
-			return nil, 0, err
+			return nil, nil, err
 		}
 	}
 
-	reader := NewReader(fileInode, f.getClient(), meta.Size, meta.BlockSize, meta.Compression, false)
-	return reader, meta.Size, nil
+	reader := NewReader(ctx, fileInode, f.getClient(), meta.Size, meta.BlockSize, meta.Compression, false)
+	m := *meta
+	return reader, &m, nil
 }
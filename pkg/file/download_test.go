@@ -37,6 +37,7 @@ import (
 )
 
 func TestDownload(t *testing.T) {
+	ctx := context.Background()
 	mockClient := mock.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -67,7 +68,7 @@ func TestDownload(t *testing.T) {
 		podFile := utils.CombinePathAndFile(filePath, fileName)
 		assert.Equal(t, fileObject.IsFileAlreadyPresent(podFile), false)
 
-		_, _, err = fileObject.Download(podFile, podPassword)
+		_, _, err = fileObject.Download(ctx, podFile, podPassword)
 		assert.Equal(t, err, file.ErrFileNotPresent)
 
 		// upload a file
@@ -75,7 +76,7 @@ func TestDownload(t *testing.T) {
 		require.NoError(t, err)
 
 		// Download the file and read from reader
-		reader, _, err := fileObject.Download(podFile, podPassword)
+		reader, _, err := fileObject.Download(ctx, podFile, podPassword)
 		require.NoError(t, err)
 
 		rcvdBuffer := new(bytes.Buffer)
@@ -83,7 +84,7 @@ func TestDownload(t *testing.T) {
 		require.NoError(t, err)
 
 		// Download the file and read from reader
-		reader2, rcvdSize2, err := fileObject.Download(podFile, podPassword)
+		reader2, rcvdSize2, err := fileObject.Download(ctx, podFile, podPassword)
 		require.NoError(t, err)
 
 		rcvdBuffer2 := new(bytes.Buffer)
@@ -111,7 +112,7 @@ func TestDownload(t *testing.T) {
 		podFile := utils.CombinePathAndFile(filePath, fileName)
 		assert.Equal(t, fileObject.IsFileAlreadyPresent(podFile), false)
 
-		_, _, err = fileObject.Download(podFile, podPassword)
+		_, _, err = fileObject.Download(ctx, podFile, podPassword)
 		assert.Equal(t, err, file.ErrFileNotPresent)
 
 		// upload a file
@@ -119,7 +120,7 @@ func TestDownload(t *testing.T) {
 		require.NoError(t, err)
 
 		// Download the file and read from reader
-		reader, rcvdSize, err := fileObject.Download(podFile, podPassword)
+		reader, rcvdSize, err := fileObject.Download(ctx, podFile, podPassword)
 		require.NoError(t, err)
 
 		rcvdBuffer := new(bytes.Buffer)
@@ -147,14 +148,14 @@ func TestDownload(t *testing.T) {
 		podFile := utils.CombinePathAndFile(filePath, fileName)
 		assert.Equal(t, fileObject.IsFileAlreadyPresent(podFile), false)
 
-		_, _, err = fileObject.Download(podFile, podPassword)
+		_, _, err = fileObject.Download(ctx, podFile, podPassword)
 		assert.Equal(t, err, file.ErrFileNotPresent)
 
 		// upload a file
 		content, err := uploadFile(t, fileObject, filePath, fileName, compression, podPassword, fileSize, blockSize)
 		require.NoError(t, err)
 
-		reader, size, err := fileObject.ReadSeeker(podFile, podPassword)
+		reader, size, err := fileObject.ReadSeeker(ctx, podFile, podPassword)
 		require.NoError(t, err)
 
 		point := size / 2
@@ -42,6 +42,10 @@ type File struct {
 	fileMu      *sync.RWMutex
 	logger      logging.Logger
 	syncManager taskmanager.TaskManagerGO
+
+	// versionPolicy caches the version policy of the pod
+	versionPolicy *VersionPolicy
+	policyMu      sync.Mutex
 }
 
 // NewFile creates the base file object which has all the methods related to file manipulation.
@@ -128,6 +132,7 @@ func (f *File) DeleteFromTagMap(filePath string) { // skipcq: TCV-001
 }
 
 type lsTask struct {
+	ctx         context.Context
 	f           *File
 	topic       []byte
 	path        string
@@ -137,8 +142,9 @@ type lsTask struct {
 	wg          *sync.WaitGroup
 }
 
-func newLsTask(f *File, topic []byte, path, podPassword string, l *[]Entry, mtx sync.Locker, wg *sync.WaitGroup) *lsTask {
+func newLsTask(ctx context.Context, f *File, topic []byte, path, podPassword string, l *[]Entry, mtx sync.Locker, wg *sync.WaitGroup) *lsTask {
 	return &lsTask{
+		ctx:         ctx,
 		f:           f,
 		topic:       topic,
 		path:        path,
@@ -152,7 +158,7 @@ func newLsTask(f *File, topic []byte, path, podPassword string, l *[]Entry, mtx
 // Execute
 func (lt *lsTask) Execute(context.Context) error {
 	defer lt.wg.Done()
-	_, data, err := lt.f.fd.GetFeedData(lt.topic, lt.f.userAddress, []byte(lt.podPassword))
+	_, data, err := lt.f.fd.GetFeedData(lt.ctx, lt.topic, lt.f.userAddress, []byte(lt.podPassword))
 	if err != nil { // skipcq: TCV-001
 		return fmt.Errorf("file mtdt : %v", err)
 	}
@@ -173,6 +179,7 @@ func (lt *lsTask) Execute(context.Context) error {
 		AccessTime:       strconv.FormatInt(meta.AccessTime, 10),
 		ModificationTime: strconv.FormatInt(meta.ModificationTime, 10),
 		Mode:             meta.Mode,
+		Xattrs:           utils.CopyXattrs(meta.Xattrs),
 	}
 	lt.f.AddToFileMap(utils.CombinePathAndFile(meta.Path, meta.Name), meta)
 	lt.mtx.Lock()
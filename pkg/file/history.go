@@ -0,0 +1,91 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+)
+
+// MetaRevision is a revision of the metadata of a file
+type MetaRevision struct {
+	// Timestamp is the modification time of the revision. A deletion carries no time, so
+	// it is the earliest time the deletion can have happened at.
+	Timestamp int64     `json:"timestamp"`
+	Reference string    `json:"reference"`
+	Deleted   bool      `json:"deleted,omitempty"`
+	Meta      *MetaData `json:"meta,omitempty"`
+}
+
+// GetMetaHistory lists all the revisions of the metadata of a file, oldest first
+func (f *File) GetMetaHistory(ctx context.Context, podFileWithPath, podPassword string) ([]MetaRevision, error) {
+	topic := utils.HashString(podFileWithPath)
+	history, err := f.fd.GetFeedHistory(ctx, topic, f.userAddress, []byte(podPassword))
+	if err != nil {
+		return nil, err
+	}
+	revisions := make([]MetaRevision, len(history))
+	var timestamp int64
+	for i, h := range history {
+		v := MetaRevision{
+			Reference: hex.EncodeToString(h.Reference),
+		}
+		t := int64(h.Timestamp)
+		if string(h.Data) == utils.DeletedFeedMagicWord {
+			v.Deleted = true
+		} else {
+			err = json.Unmarshal(h.Data, &v.Meta)
+			if err != nil { // skipcq: TCV-001
+				return nil, fmt.Errorf("file history: %v", err)
+			}
+			t = v.Meta.ModificationTime
+		}
+		// the revisions are in order, so a revision is never older than the previous one
+		if t > timestamp {
+			timestamp = t
+		}
+		v.Timestamp = timestamp
+		revisions[i] = v
+	}
+	return revisions, nil
+}
+
+// GetMetaAt returns the metadata of a file as it was at the given unix time
+func (f *File) GetMetaAt(ctx context.Context, podFileWithPath, podPassword string, timestamp int64) (*MetaData, error) {
+	revisions, err := f.GetMetaHistory(ctx, podFileWithPath, podPassword)
+	if err != nil {
+		return nil, err
+	}
+	var found *MetaRevision
+	for i := range revisions {
+		if revisions[i].Timestamp > timestamp {
+			break
+		}
+		found = &revisions[i]
+	}
+	if found == nil {
+		return nil, ErrFileNotFound
+	}
+	if found.Deleted {
+		return nil, ErrDeletedFeed
+	}
+	return found.Meta, nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/account"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/bee/mock"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/file"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/pod"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+	"github.com/plexsysio/taskmanager"
+)
+
+func TestHistory(t *testing.T) {
+	ctx := context.Background()
+	mockClient := mock.NewMockBeeClient()
+	logger := logging.New(io.Discard, 0)
+	acc := account.New(logger)
+	_, _, err := acc.CreateUserAccount("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pod1AccountInfo, err := acc.CreatePodAccount(1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fd := feed.New(pod1AccountInfo, mockClient, logger)
+	user := acc.GetAddress(1)
+	tm := taskmanager.New(1, 10, time.Second*15, logger)
+	defer func() {
+		_ = tm.Stop(context.Background())
+	}()
+	podPassword, _ := utils.GetRandString(pod.PasswordLength)
+	fileObject := file.NewFile("pod1", mockClient, fd, user, tm, logger)
+
+	_, err = uploadFile(t, fileObject, "/dir1", "file1", "", podPassword, 100, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = fileObject.Chmod(ctx, "/dir1/file1", podPassword, 0444)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	revisions, err := fileObject.GetMetaHistory(ctx, "/dir1/file1", podPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revisions) != 2 {
+		t.Fatalf("expected 2 revisions, got %d", len(revisions))
+	}
+	if revisions[0].Meta.Mode == revisions[1].Meta.Mode || revisions[1].Meta.Mode != file.S_IFREG|0444 {
+		t.Fatal("the chmod should be the last revision")
+	}
+	if revisions[0].Timestamp != revisions[0].Meta.ModificationTime || revisions[1].Timestamp < revisions[0].Timestamp {
+		t.Fatal("revisions should be dated by the modification time")
+	}
+
+	meta, err := fileObject.GetMetaAt(ctx, "/dir1/file1", podPassword, time.Now().Unix())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Mode != file.S_IFREG|0444 {
+		t.Fatal("expected the latest metadata")
+	}
+	_, err = fileObject.GetMetaAt(ctx, "/dir1/file1", podPassword, revisions[0].Timestamp-1)
+	if !errors.Is(err, file.ErrFileNotFound) {
+		t.Fatalf("expected file not found before the upload, got %v", err)
+	}
+}
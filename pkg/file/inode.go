@@ -25,7 +25,12 @@ type INode struct {
 
 // BlockInfo
 type BlockInfo struct {
+	// Size is the length of the plaintext of the block, which lz4 blocks do not hold
 	Size           uint32          `json:"size"`
 	CompressedSize uint32          `json:"compressedSize"`
 	Reference      utils.Reference `json:"reference"`
+	// Checksum is the hex encoded SHA-256 of the plaintext of the block
+	Checksum string `json:"checksum,omitempty"`
+	// Compression is the one the block is stored with, the one of the file if empty
+	Compression string `json:"compression,omitempty"`
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package file
 
 import (
+	"context"
 	"sync"
 
 	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
@@ -24,24 +25,25 @@ import (
 
 // Entry
 type Entry struct {
-	Name             string `json:"name"`
-	ContentType      string `json:"contentType"`
-	Size             string `json:"size,omitempty"`
-	BlockSize        string `json:"blockSize,omitempty"`
-	CreationTime     string `json:"creationTime"`
-	ModificationTime string `json:"modificationTime"`
-	AccessTime       string `json:"accessTime"`
-	Mode             uint32 `json:"mode"`
+	Name             string            `json:"name"`
+	ContentType      string            `json:"contentType"`
+	Size             string            `json:"size,omitempty"`
+	BlockSize        string            `json:"blockSize,omitempty"`
+	CreationTime     string            `json:"creationTime"`
+	ModificationTime string            `json:"modificationTime"`
+	AccessTime       string            `json:"accessTime"`
+	Mode             uint32            `json:"mode"`
+	Xattrs           map[string]string `json:"xattrs,omitempty"`
 }
 
 // ListFiles given a list of files, list files gives back the information related to each file.
-func (f *File) ListFiles(files []string, podPassword string) ([]Entry, error) {
+func (f *File) ListFiles(ctx context.Context, files []string, podPassword string) ([]Entry, error) {
 	fileEntries := &[]Entry{}
 	wg := new(sync.WaitGroup)
 	mtx := &sync.Mutex{}
 	for _, filePath := range files {
 		fileTopic := utils.HashString(utils.CombinePathAndFile(filePath, ""))
-		lsTask := newLsTask(f, fileTopic, filePath, podPassword, fileEntries, mtx, wg)
+		lsTask := newLsTask(ctx, f, fileTopic, filePath, podPassword, fileEntries, mtx, wg)
 		_, err := f.syncManager.Go(lsTask)
 		if err != nil { // skipcq: TCV-001
 			f.logger.Warningf("list files : %v", err)
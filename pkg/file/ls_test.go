@@ -34,6 +34,7 @@ import (
 )
 
 func TestListFiles(t *testing.T) {
+	ctx := context.Background()
 	mockClient := mock.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -74,7 +75,7 @@ func TestListFiles(t *testing.T) {
 
 		// list the files
 		fileList := []string{"/dir1/file1", "/dir1/file2", "/dir1/file3"}
-		entries, err := fileObject.ListFiles(fileList, podPassword)
+		entries, err := fileObject.ListFiles(ctx, fileList, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -0,0 +1,53 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pierrec/lz4/v4"
+)
+
+// A block compressed with lz4 is stored in the LZ4 block format,
+// https://github.com/lz4/lz4/blob/dev/doc/lz4_Block_format.md. The format does not hold
+// the size of the block, which is the Size of the BlockInfo.
+
+var errLZ4Corrupt = errors.New("lz4: corrupt block")
+
+// lz4Compress compresses a block
+func lz4Compress(src []byte) ([]byte, error) {
+	// a destination of the bound never leaves data uncompressed
+	dst := make([]byte, lz4.CompressBlockBound(len(src)))
+	var c lz4.Compressor
+	n, err := c.CompressBlock(src, dst)
+	if err != nil { // skipcq: TCV-001
+		return nil, fmt.Errorf("lz4: %w", err)
+	}
+	return dst[:n], nil
+}
+
+// lz4Decompress decompresses a block of at most maxSize bytes. A block that decompresses
+// to more is corrupt.
+func lz4Decompress(src []byte, maxSize uint32) ([]byte, error) {
+	dst := make([]byte, maxSize)
+	n, err := lz4.UncompressBlock(src, dst)
+	if err != nil {
+		return nil, errLZ4Corrupt
+	}
+	return dst[:n], nil
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package file
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -28,7 +30,7 @@ import (
 
 var (
 	//MetaVersion
-	MetaVersion uint8 = 2
+	MetaVersion uint8 = 3
 
 	//ErrDeletedFeed
 	ErrDeletedFeed = errors.New("deleted feed")
@@ -48,11 +50,23 @@ type MetaData struct {
 	ModificationTime int64  `json:"modificationTime"`
 	InodeAddress     []byte `json:"fileInodeReference"`
 	Mode             uint32 `json:"mode"`
+	// BlockTreeChecksum is the hex encoded SHA-256 of the checksums of the blocks in order.
+	// It is not the SHA-256 of the content of the file. It is empty for files written
+	// before MetaVersion 3.
+	BlockTreeChecksum string `json:"blockTreeChecksum,omitempty"`
+	// Checksum is the hex encoded SHA-256 of the content of the file, the same as sha256sum
+	// prints for it. It is empty for files written before it was recorded.
+	Checksum string `json:"checksum,omitempty"`
+	// Xattrs are the extended attributes set by the applications
+	Xattrs map[string]string `json:"xattrs,omitempty"`
+	// SharedBlocks is set when the blocks of the file are also used by a copy of it, or
+	// when they are the blocks of a file received from another user
+	SharedBlocks bool `json:"sharedBlocks,omitempty"`
 }
 
 // LoadFileMeta is used in syncing
-func (f *File) LoadFileMeta(fileNameWithPath, podPassword string) error {
-	meta, err := f.GetMetaFromFileName(fileNameWithPath, podPassword, f.userAddress)
+func (f *File) LoadFileMeta(ctx context.Context, fileNameWithPath, podPassword string) error {
+	meta, err := f.GetMetaFromFileName(ctx, fileNameWithPath, podPassword, f.userAddress)
 	if err != nil { // skipcq: TCV-001
 		if err == ErrDeletedFeed {
 			return nil
@@ -64,19 +78,28 @@ func (f *File) LoadFileMeta(fileNameWithPath, podPassword string) error {
 	return nil
 }
 
-func (f *File) handleMeta(meta *MetaData, podPassword string) error {
+func (f *File) handleMeta(ctx context.Context, meta *MetaData, podPassword string) error {
 	// check if meta is present.
 	totalPath := utils.CombinePathAndFile(meta.Path, meta.Name)
-	_, err := f.GetMetaFromFileName(totalPath, podPassword, f.userAddress)
+	previous, err := f.GetMetaFromFileName(ctx, totalPath, podPassword, f.userAddress)
 	if err != nil {
 		if err != ErrDeletedFeed {
-			return f.uploadMeta(meta, podPassword)
+			return f.uploadMeta(ctx, meta, podPassword)
 		}
 	}
-	return f.updateMeta(meta, podPassword)
+	// the content that is replaced is kept as a version. A stale meta of a file that was
+	// removed or renamed away is not the content of the file anymore.
+	if previous != nil && f.GetFromFileMap(totalPath) != nil &&
+		!bytes.Equal(previous.InodeAddress, meta.InodeAddress) {
+		err = f.recordVersion(ctx, previous, podPassword)
+		if err != nil {
+			return err
+		}
+	}
+	return f.updateMeta(ctx, meta, podPassword)
 }
 
-func (f *File) uploadMeta(meta *MetaData, podPassword string) error {
+func (f *File) uploadMeta(ctx context.Context, meta *MetaData, podPassword string) error {
 	// marshall the meta structure
 	fileMetaBytes, err := json.Marshal(meta)
 	if err != nil { // skipcq: TCV-001
@@ -86,7 +109,7 @@ func (f *File) uploadMeta(meta *MetaData, podPassword string) error {
 	// put the file meta as a feed
 	totalPath := utils.CombinePathAndFile(meta.Path, meta.Name)
 	topic := utils.HashString(totalPath)
-	_, err = f.fd.CreateFeed(topic, f.userAddress, fileMetaBytes, []byte(podPassword))
+	_, err = f.fd.CreateFeed(ctx, topic, f.userAddress, fileMetaBytes, []byte(podPassword))
 	if err != nil { // skipcq: TCV-001
 		return err
 	}
@@ -94,22 +117,22 @@ func (f *File) uploadMeta(meta *MetaData, podPassword string) error {
 	return nil
 }
 
-func (f *File) deleteMeta(meta *MetaData, podPassword string) error {
+func (f *File) deleteMeta(ctx context.Context, meta *MetaData, podPassword string) error {
 	totalPath := utils.CombinePathAndFile(meta.Path, meta.Name)
 	topic := utils.HashString(totalPath)
 	// update with utils.DeletedFeedMagicWord
-	_, err := f.fd.UpdateFeed(topic, f.userAddress, []byte(utils.DeletedFeedMagicWord), []byte(podPassword))
+	_, err := f.fd.UpdateFeed(ctx, topic, f.userAddress, []byte(utils.DeletedFeedMagicWord), []byte(podPassword))
 	if err != nil { // skipcq: TCV-001
 		return err
 	}
-	err = f.fd.DeleteFeed(topic, f.userAddress)
+	err = f.fd.DeleteFeed(ctx, topic, f.userAddress)
 	if err != nil {
 		f.logger.Warningf("failed to remove file feed %s", totalPath)
 	}
 	return nil
 }
 
-func (f *File) updateMeta(meta *MetaData, podPassword string) error {
+func (f *File) updateMeta(ctx context.Context, meta *MetaData, podPassword string) error {
 	// marshall the meta structure
 	fileMetaBytes, err := json.Marshal(meta)
 	if err != nil { // skipcq: TCV-001
@@ -119,7 +142,7 @@ func (f *File) updateMeta(meta *MetaData, podPassword string) error {
 	// put the file meta as a feed
 	totalPath := utils.CombinePathAndFile(meta.Path, meta.Name)
 	topic := utils.HashString(totalPath)
-	_, err = f.fd.UpdateFeed(topic, f.userAddress, fileMetaBytes, []byte(podPassword))
+	_, err = f.fd.UpdateFeed(ctx, topic, f.userAddress, fileMetaBytes, []byte(podPassword))
 	if err != nil { // skipcq: TCV-001
 		return err
 	}
@@ -128,13 +151,13 @@ func (f *File) updateMeta(meta *MetaData, podPassword string) error {
 }
 
 // BackupFromFileName
-func (f *File) BackupFromFileName(fileNameWithPath, podPassword string) (*MetaData, error) {
-	p, err := f.GetMetaFromFileName(fileNameWithPath, podPassword, f.userAddress)
+func (f *File) BackupFromFileName(ctx context.Context, fileNameWithPath, podPassword string) (*MetaData, error) {
+	p, err := f.GetMetaFromFileName(ctx, fileNameWithPath, podPassword, f.userAddress)
 	if err != nil {
 		return nil, err
 	}
 
-	err = f.deleteMeta(p, podPassword)
+	err = f.deleteMeta(ctx, p, podPassword)
 	if err != nil {
 		return nil, err
 	}
@@ -144,27 +167,33 @@ func (f *File) BackupFromFileName(fileNameWithPath, podPassword string) (*MetaDa
 	p.ModificationTime = time.Now().Unix()
 
 	// upload PreviousMeta
-	err = f.uploadMeta(p, podPassword)
+	err = f.uploadMeta(ctx, p, podPassword)
 	if err != nil {
 		return nil, err
 	}
 
+	// the versions stay with the backup
+	err = f.MoveVersions(ctx, fileNameWithPath, utils.CombinePathAndFile(p.Path, p.Name), podPassword)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+
 	// add file to map
 	f.AddToFileMap(utils.CombinePathAndFile(p.Path, p.Name), p)
 	return p, nil
 }
 
 // RenameFromFileName
-func (f *File) RenameFromFileName(fileNameWithPath, newFileNameWithPath, podPassword string) (*MetaData, error) {
+func (f *File) RenameFromFileName(ctx context.Context, fileNameWithPath, newFileNameWithPath, podPassword string) (*MetaData, error) {
 	fileNameWithPath = filepath.ToSlash(fileNameWithPath)
 	newFileNameWithPath = filepath.ToSlash(newFileNameWithPath)
-	p, err := f.GetMetaFromFileName(fileNameWithPath, podPassword, f.userAddress)
+	p, err := f.GetMetaFromFileName(ctx, fileNameWithPath, podPassword, f.userAddress)
 	if err != nil {
 		return nil, err
 	}
 
 	// remove old meta and from file map
-	err = f.deleteMeta(p, podPassword)
+	err = f.deleteMeta(ctx, p, podPassword)
 	if err != nil {
 		return nil, err
 	}
@@ -179,10 +208,14 @@ func (f *File) RenameFromFileName(fileNameWithPath, newFileNameWithPath, podPass
 	p.ModificationTime = time.Now().Unix()
 
 	// upload meta
-	err = f.handleMeta(p, podPassword)
+	err = f.handleMeta(ctx, p, podPassword)
 	if err != nil {
 		return nil, err
 	}
+	err = f.MoveVersions(ctx, fileNameWithPath, newFileNameWithPath, podPassword)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
 
 	// add file to map
 	f.AddToFileMap(newFileNameWithPath, p)
@@ -190,9 +223,9 @@ func (f *File) RenameFromFileName(fileNameWithPath, newFileNameWithPath, podPass
 }
 
 // GetMetaFromFileName
-func (f *File) GetMetaFromFileName(fileNameWithPath, podPassword string, userAddress utils.Address) (*MetaData, error) {
+func (f *File) GetMetaFromFileName(ctx context.Context, fileNameWithPath, podPassword string, userAddress utils.Address) (*MetaData, error) {
 	topic := utils.HashString(fileNameWithPath)
-	_, metaBytes, err := f.fd.GetFeedData(topic, userAddress, []byte(podPassword))
+	_, metaBytes, err := f.fd.GetFeedData(ctx, topic, userAddress, []byte(podPassword))
 	if err != nil {
 		return nil, err
 	}
@@ -212,6 +245,6 @@ func (f *File) GetMetaFromFileName(fileNameWithPath, podPassword string, userAdd
 }
 
 // PutMetaForFile
-func (f *File) PutMetaForFile(meta *MetaData, podPassword string) error {
-	return f.updateMeta(meta, podPassword)
+func (f *File) PutMetaForFile(ctx context.Context, meta *MetaData, podPassword string) error {
+	return f.updateMeta(ctx, meta, podPassword)
 }
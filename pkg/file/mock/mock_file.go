@@ -17,6 +17,7 @@ limitations under the License.
 package mock
 
 import (
+	"context"
 	"io"
 
 	"github.com/fairdatasociety/fairOS-dfs/pkg/file"
@@ -32,27 +33,27 @@ func NewMockFile() *File {
 }
 
 // Upload
-func (*File) Upload(_ io.Reader, _ string, _ int64, _ uint32, _, _, _ string) error {
+func (*File) Upload(_ context.Context, _ io.Reader, _ string, _ int64, _ uint32, _, _, _ string) error {
 	return nil
 }
 
 // Download
-func (*File) Download(_, _ string) (io.ReadCloser, uint64, error) {
+func (*File) Download(_ context.Context, _, _ string) (io.ReadCloser, uint64, error) {
 	return nil, 0, nil
 }
 
 // ListFiles
-func (*File) ListFiles(_ []string, _ string) ([]file.Entry, error) {
+func (*File) ListFiles(_ context.Context, _ []string, _ string) ([]file.Entry, error) {
 	return nil, nil
 }
 
 // GetStats
-func (*File) GetStats(_, _, _ string) (*file.Stats, error) {
+func (*File) GetStats(_ context.Context, _, _, _ string) (*file.Stats, error) {
 	return nil, nil
 }
 
 // RmFile
-func (*File) RmFile(_, _ string) error {
+func (*File) RmFile(_ context.Context, _, _ string) error {
 	return nil
 }
 
@@ -66,6 +67,16 @@ func (*File) AddFileToPath(_, _ string) error {
 }
 
 // LoadFileMeta
-func (*File) LoadFileMeta(_, _ string) error {
+func (*File) LoadFileMeta(_ context.Context, _, _ string) error {
+	return nil
+}
+
+// References
+func (*File) References(_ context.Context, _, _ string, _ func(ref []byte)) error {
+	return nil
+}
+
+// MoveVersions
+func (*File) MoveVersions(_ context.Context, _, _, _ string) error {
 	return nil
 }
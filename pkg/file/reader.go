@@ -17,7 +17,7 @@ limitations under the License.
 package file
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -25,9 +25,7 @@ import (
 
 	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
-	"github.com/golang/snappy"
 	lru "github.com/hashicorp/golang-lru"
-	"github.com/klauspost/pgzip"
 )
 
 const (
@@ -41,17 +39,21 @@ var (
 
 // Reader
 type Reader struct {
-	readOffset  int64
-	client      blockstore.Client
-	fileInode   INode
-	fileC       chan []byte
-	lastBlock   []byte
-	fileSize    uint64
-	blockSize   uint32
-	blockCursor uint32
-	totalSize   uint64
-	compression string
-	blockCache  *lru.Cache
+	ctx        context.Context
+	readOffset int64
+	client     blockstore.Client
+	fileInode  INode
+	fileC      chan []byte
+	lastBlock  []byte
+	fileSize   uint64
+	blockSize  uint32
+	// inodeBlockSize is the size of all but the last block, blockSize is the size of the
+	// block that is read
+	inodeBlockSize uint32
+	blockCursor    uint32
+	totalSize      uint64
+	compression    string
+	blockCache     *lru.Cache
 
 	rlBuffer      []byte
 	rlOffset      int
@@ -61,13 +63,13 @@ type Reader struct {
 // OpenFileForIndex opens file for indexing for document db from pod filepath
 // TODO test
 // skipcq: TCV-001
-func (f *File) OpenFileForIndex(podFile, podPassword string) (*Reader, error) {
+func (f *File) OpenFileForIndex(ctx context.Context, podFile, podPassword string) (*Reader, error) {
 	meta := f.GetFromFileMap(podFile)
 	if meta == nil {
 		return nil, fmt.Errorf("file not found in dfs")
 	}
 
-	encryptedFileInodeBytes, _, err := f.getClient().DownloadBlob(meta.InodeAddress)
+	encryptedFileInodeBytes, _, err := f.getClient().DownloadBlob(ctx, meta.InodeAddress)
 	if err != nil {
 		return nil, err
 	}
@@ -85,26 +87,29 @@ func (f *File) OpenFileForIndex(podFile, podPassword string) (*Reader, error) {
 		return nil, err
 	}
 
-	reader := NewReader(fileInode, f.getClient(), meta.Size, meta.BlockSize, meta.Compression, true)
+	reader := NewReader(ctx, fileInode, f.getClient(), meta.Size, meta.BlockSize, meta.Compression, true)
 	return reader, nil
 }
 
 // NewReader create a new reader object to read a file from the pod based on its configuration.
-func NewReader(fileInode INode, client blockstore.Client, fileSize uint64, blockSize uint32, compression string, cache bool) *Reader {
+// Blocks are downloaded with the given context, so the reader fails once the context is done.
+func NewReader(ctx context.Context, fileInode INode, client blockstore.Client, fileSize uint64, blockSize uint32, compression string, cache bool) *Reader {
 	var blockCache *lru.Cache
 	if cache {
 		blockCache, _ = lru.New(blockCacheSize)
 	}
 
 	r := &Reader{
-		fileInode:     fileInode,
-		client:        client,
-		fileC:         make(chan []byte),
-		fileSize:      fileSize,
-		blockSize:     blockSize,
-		compression:   compression,
-		blockCache:    blockCache,
-		rlReadNewLine: false,
+		ctx:            ctx,
+		fileInode:      fileInode,
+		client:         client,
+		fileC:          make(chan []byte),
+		fileSize:       fileSize,
+		blockSize:      blockSize,
+		inodeBlockSize: blockSize,
+		compression:    compression,
+		blockCache:     blockCache,
+		rlReadNewLine:  false,
 	}
 	return r
 }
@@ -159,7 +164,7 @@ func (r *Reader) Read(b []byte) (n int, err error) {
 				if blockIndex >= int64(len(r.fileInode.Blocks)) { // skipcq: TCV-001
 					return bytesRead, io.EOF
 				}
-				r.lastBlock, err = r.getBlock(r.fileInode.Blocks[blockIndex].Reference.Bytes(), r.compression, r.blockSize)
+				r.lastBlock, err = r.getBlock(r.fileInode.Blocks[blockIndex], r.compression, r.blockSize)
 				if err != nil { // skipcq: TCV-001
 					return bytesRead, err
 				}
@@ -197,33 +202,37 @@ func (r *Reader) Read(b []byte) (n int, err error) {
 	return 0, nil // skipcq: TCV-001
 }
 
-// Seek
+// Seek sets the offset of the next Read, relative to the start of the file, the current
+// offset or the end of the file according to whence. Seeking to the end of the file does
+// not download any block.
 func (r *Reader) Seek(seekOffset int64, whence int) (int64, error) {
-	// TODO: use whence
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		seekOffset += r.readOffset
+	case io.SeekEnd:
+		seekOffset += int64(r.fileSize)
+	default:
+		return 0, ErrInvalidOffset
+	}
 	if seekOffset < 0 || seekOffset > int64(r.fileSize) {
 		return 0, ErrInvalidOffset
 	}
 
-	// seek to start if offset is zero
-	if seekOffset == 0 {
-		blockData, err := r.getBlock(r.fileInode.Blocks[0].Reference.Bytes(), r.compression, r.blockSize)
-		if err != nil { // skipcq: TCV-001
-			return 0, err
-		}
-		r.lastBlock = blockData
+	r.rlBuffer = nil
+	r.rlOffset = 0
+	if seekOffset == int64(r.fileSize) {
+		r.lastBlock = nil
 		r.blockCursor = 0
-		r.readOffset = 0
-		r.blockSize = uint32(len(r.lastBlock))
-		r.totalSize = 0
-		r.rlBuffer = nil
-		r.rlOffset = 0
-		return 0, nil
+		r.readOffset = seekOffset
+		r.totalSize = uint64(seekOffset)
+		return seekOffset, nil
 	}
 
-	blockIndex := seekOffset / int64(r.blockSize)
-	blockOffset := seekOffset % int64(r.blockSize)
+	blockIndex := seekOffset / int64(r.inodeBlockSize)
+	blockOffset := seekOffset % int64(r.inodeBlockSize)
 
-	blockData, err := r.getBlock(r.fileInode.Blocks[blockIndex].Reference.Bytes(), r.compression, r.blockSize)
+	blockData, err := r.getBlock(r.fileInode.Blocks[blockIndex], r.compression, r.inodeBlockSize)
 	if err != nil {
 		return 0, err
 	}
@@ -232,8 +241,6 @@ func (r *Reader) Seek(seekOffset int64, whence int) (int64, error) {
 	r.readOffset = seekOffset
 	r.blockSize = uint32(len(r.lastBlock))
 	r.totalSize = uint64(seekOffset)
-	r.rlBuffer = nil
-	r.rlOffset = 0
 	return seekOffset, nil
 }
 
@@ -311,54 +318,31 @@ func (r *Reader) Close() error {
 	return nil
 }
 
-func (r *Reader) getBlock(ref []byte, compression string, blockSize uint32) ([]byte, error) {
-	refStr := utils.NewReference(ref).String()
+// getBlock downloads and decompresses a block and checks it against its checksum
+func (r *Reader) getBlock(block *BlockInfo, compression string, blockSize uint32) ([]byte, error) {
+	ref := block.Reference.Bytes()
+	refStr := block.Reference.String()
 	if r.blockCache != nil {
 		if data, found := r.blockCache.Get(refStr); found {
 			return data.([]byte), nil
 		}
 	}
-	stdoutBytes, _, err := r.client.DownloadBlob(ref)
+	stdoutBytes, _, err := r.client.DownloadBlob(r.ctx, ref)
 	if err != nil { // skipcq: TCV-001
 		return nil, err
 	}
 
-	decompressedData, err := Decompress(stdoutBytes, compression, blockSize)
+	decompressedData, err := decompressBlock(block, stdoutBytes, compression, blockSize)
 	if err != nil { // skipcq: TCV-001
 		return nil, err
 	}
+	err = verifyBlock(block, decompressedData)
+	if err != nil {
+		return nil, err
+	}
 
 	if r.blockCache != nil {
 		r.blockCache.Add(refStr, decompressedData)
 	}
 	return decompressedData, nil
 }
-
-// Decompress
-func Decompress(dataToDecompress []byte, compression string, blockSize uint32) ([]byte, error) {
-	switch compression {
-	case "gzip":
-		br := bytes.NewReader(dataToDecompress)
-		block := int(blockSize / 10)
-		r, err := pgzip.NewReaderN(br, block, 10)
-		if err != nil { // skipcq: TCV-001
-			return nil, err
-		}
-		s, err := io.ReadAll(r)
-		if err != nil { // skipcq: TCV-001
-			return nil, err
-		}
-		err = r.Close()
-		if err != nil { // skipcq: TCV-001
-			return nil, err
-		}
-		return s, nil
-	case "snappy":
-		decoded, err := snappy.Decode(nil, dataToDecompress)
-		if err != nil { // skipcq: TCV-001
-			return nil, err
-		}
-		return decoded, nil
-	}
-	return dataToDecompress, nil
-}
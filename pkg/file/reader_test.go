@@ -17,6 +17,7 @@ limitations under the License.
 package file_test
 
 import (
+	"context"
 	"crypto/rand"
 	"errors"
 	"io"
@@ -31,6 +32,7 @@ import (
 )
 
 func TestFileReader(t *testing.T) {
+	ctx := context.Background()
 	mockClient := mock.NewMockBeeClient()
 
 	t.Run("read-entire-file-shorter-than-block", func(t *testing.T) {
@@ -38,7 +40,7 @@ func TestFileReader(t *testing.T) {
 		blockSize := uint32(20)
 
 		_, fileInode := createFile(t, fileSize, blockSize, "", mockClient)
-		reader := file.NewReader(fileInode, mockClient, fileSize, blockSize, "", false)
+		reader := file.NewReader(ctx, fileInode, mockClient, fileSize, blockSize, "", false)
 		defer reader.Close()
 		_, err := reader.Seek(10, 0)
 		require.NoError(t, err)
@@ -55,7 +57,7 @@ func TestFileReader(t *testing.T) {
 		blockSize := uint32(20)
 
 		_, fileInode := createFile(t, fileSize, blockSize, "", mockClient)
-		reader := file.NewReader(fileInode, mockClient, fileSize, blockSize, "", false)
+		reader := file.NewReader(ctx, fileInode, mockClient, fileSize, blockSize, "", false)
 		defer reader.Close()
 		_, err := reader.Seek(10, 0)
 		require.NoError(t, err)
@@ -72,7 +74,7 @@ func TestFileReader(t *testing.T) {
 		blockSize := uint32(20)
 
 		_, fileInode := createFile(t, fileSize, blockSize, "", mockClient)
-		reader := file.NewReader(fileInode, mockClient, fileSize, blockSize, "", false)
+		reader := file.NewReader(ctx, fileInode, mockClient, fileSize, blockSize, "", false)
 		defer reader.Close()
 		_, err := reader.Seek(10, 0)
 		require.NoError(t, err)
@@ -89,7 +91,7 @@ func TestFileReader(t *testing.T) {
 		blockSize := uint32(20)
 
 		_, fileInode := createFile(t, fileSize, blockSize, "", mockClient)
-		reader := file.NewReader(fileInode, mockClient, fileSize, blockSize, "", false)
+		reader := file.NewReader(ctx, fileInode, mockClient, fileSize, blockSize, "", false)
 		defer reader.Close()
 		_, err := reader.Seek(16, 0)
 		if !errors.Is(err, file.ErrInvalidOffset) {
@@ -102,7 +104,7 @@ func TestFileReader(t *testing.T) {
 		blockSize := uint32(20)
 
 		_, fileInode := createFile(t, fileSize, blockSize, "", mockClient)
-		reader := file.NewReader(fileInode, mockClient, fileSize, blockSize, "", false)
+		reader := file.NewReader(ctx, fileInode, mockClient, fileSize, blockSize, "", false)
 		defer reader.Close()
 		_, err := reader.Seek(0, 0)
 		require.NoError(t, err)
@@ -114,12 +116,49 @@ func TestFileReader(t *testing.T) {
 		assert.Equal(t, n, 15)
 	})
 
+	t.Run("read-seek-whence", func(t *testing.T) {
+		fileSize := uint64(95)
+		blockSize := uint32(10)
+
+		content, fileInode := createFile(t, fileSize, blockSize, "", mockClient)
+		reader := file.NewReader(ctx, fileInode, mockClient, fileSize, blockSize, "", false)
+		defer reader.Close()
+
+		// the size of the file is found by seeking to its end
+		seekN, err := reader.Seek(0, io.SeekEnd)
+		require.NoError(t, err)
+		assert.Equal(t, int64(fileSize), seekN)
+		_, err = reader.Read(make([]byte, 1))
+		assert.Equal(t, io.EOF, err)
+
+		seekN, err = reader.Seek(-7, io.SeekEnd)
+		require.NoError(t, err)
+		assert.Equal(t, int64(88), seekN)
+		buf, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, content[88:], buf)
+
+		seekN, err = reader.Seek(25, io.SeekStart)
+		require.NoError(t, err)
+		assert.Equal(t, int64(25), seekN)
+		seekN, err = reader.Seek(10, io.SeekCurrent)
+		require.NoError(t, err)
+		assert.Equal(t, int64(35), seekN)
+		buf = make([]byte, 20)
+		_, err = io.ReadFull(reader, buf)
+		require.NoError(t, err)
+		assert.Equal(t, content[35:55], buf)
+
+		_, err = reader.Seek(-100, io.SeekCurrent)
+		assert.True(t, errors.Is(err, file.ErrInvalidOffset))
+	})
+
 	t.Run("read-entire-file", func(t *testing.T) {
 		fileSize := uint64(100)
 		blockSize := uint32(10)
 
 		b, fileInode := createFile(t, fileSize, blockSize, "", mockClient)
-		reader := file.NewReader(fileInode, mockClient, fileSize, blockSize, "", false)
+		reader := file.NewReader(ctx, fileInode, mockClient, fileSize, blockSize, "", false)
 		defer reader.Close()
 
 		outputBytes := readFileContents(t, fileSize, reader)
@@ -131,7 +170,7 @@ func TestFileReader(t *testing.T) {
 		blockSize := uint32(10)
 
 		b, fileInode := createFile(t, fileSize, blockSize, "", mockClient)
-		reader := file.NewReader(fileInode, mockClient, fileSize, blockSize, "", false)
+		reader := file.NewReader(ctx, fileInode, mockClient, fileSize, blockSize, "", false)
 		defer reader.Close()
 		outputBytes := readFileContents(t, fileSize, reader)
 		assert.Equal(t, b, outputBytes)
@@ -143,7 +182,7 @@ func TestFileReader(t *testing.T) {
 		compression := "gzip"
 
 		b, fileInode := createFile(t, fileSize, blockSize, compression, mockClient)
-		reader := file.NewReader(fileInode, mockClient, fileSize, blockSize, compression, false)
+		reader := file.NewReader(ctx, fileInode, mockClient, fileSize, blockSize, compression, false)
 		defer reader.Close()
 		outputBytes := readFileContents(t, fileSize, reader)
 		assert.Equal(t, b, outputBytes)
@@ -155,7 +194,7 @@ func TestFileReader(t *testing.T) {
 		compression := "gzip"
 
 		b, fileInode := createFile(t, fileSize, blockSize, compression, mockClient)
-		reader := file.NewReader(fileInode, mockClient, fileSize, blockSize, compression, false)
+		reader := file.NewReader(ctx, fileInode, mockClient, fileSize, blockSize, compression, false)
 		defer reader.Close()
 		outputBytes := readFileContents(t, fileSize, reader)
 		assert.Equal(t, b, outputBytes)
@@ -167,7 +206,7 @@ func TestFileReader(t *testing.T) {
 		compression := "snappy"
 
 		b, fileInode := createFile(t, fileSize, blockSize, compression, mockClient)
-		reader := file.NewReader(fileInode, mockClient, fileSize, blockSize, compression, false)
+		reader := file.NewReader(ctx, fileInode, mockClient, fileSize, blockSize, compression, false)
 		defer reader.Close()
 		outputBytes := readFileContents(t, fileSize, reader)
 		assert.Equal(t, b, outputBytes)
@@ -179,7 +218,7 @@ func TestFileReader(t *testing.T) {
 		compression := "snappy"
 
 		b, fileInode := createFile(t, fileSize, blockSize, compression, mockClient)
-		reader := file.NewReader(fileInode, mockClient, fileSize, blockSize, compression, false)
+		reader := file.NewReader(ctx, fileInode, mockClient, fileSize, blockSize, compression, false)
 		defer reader.Close()
 		outputBytes := readFileContents(t, fileSize, reader)
 		assert.Equal(t, b, outputBytes)
@@ -191,7 +230,7 @@ func TestFileReader(t *testing.T) {
 		linesPerBlock := uint32(2)
 
 		b, fileInode, _, _, _, _ := createFileWithNewlines(t, fileSize, blockSize, "", mockClient, linesPerBlock)
-		reader := file.NewReader(fileInode, mockClient, fileSize, blockSize, "", false)
+		reader := file.NewReader(ctx, fileInode, mockClient, fileSize, blockSize, "", false)
 		defer reader.Close()
 		outputBytes := readFileContentsUsingReadline(t, fileSize, reader)
 		assert.Equal(t, b, outputBytes)
@@ -203,7 +242,7 @@ func TestFileReader(t *testing.T) {
 		linesPerBlock := uint32(2)
 
 		b, fileInode, _, _, _, _ := createFileWithNewlines(t, fileSize, blockSize, "", mockClient, linesPerBlock)
-		reader := file.NewReader(fileInode, mockClient, fileSize, blockSize, "", false)
+		reader := file.NewReader(ctx, fileInode, mockClient, fileSize, blockSize, "", false)
 		defer reader.Close()
 		outputBytes := readFileContentsUsingReadline(t, fileSize, reader)
 		assert.Equal(t, b, outputBytes)
@@ -216,7 +255,7 @@ func TestFileReader(t *testing.T) {
 		compression := "snappy"
 
 		b, fileInode, _, _, _, _ := createFileWithNewlines(t, fileSize, blockSize, compression, mockClient, linesPerBlock)
-		reader := file.NewReader(fileInode, mockClient, fileSize, blockSize, compression, false)
+		reader := file.NewReader(ctx, fileInode, mockClient, fileSize, blockSize, compression, false)
 		defer reader.Close()
 		outputBytes := readFileContentsUsingReadline(t, fileSize, reader)
 		assert.Equal(t, b, outputBytes)
@@ -228,7 +267,7 @@ func TestFileReader(t *testing.T) {
 		linesPerBlock := uint32(2)
 
 		_, fileInode, lineStart, line, _, _ := createFileWithNewlines(t, fileSize, blockSize, "", mockClient, linesPerBlock)
-		reader := file.NewReader(fileInode, mockClient, fileSize, blockSize, "", false)
+		reader := file.NewReader(ctx, fileInode, mockClient, fileSize, blockSize, "", false)
 		defer reader.Close()
 		seekN, err := reader.Seek(int64(lineStart), 0)
 		require.NoError(t, err)
@@ -245,7 +284,7 @@ func TestFileReader(t *testing.T) {
 		linesPerBlock := uint32(2)
 
 		_, fileInode, _, _, lineStart, line := createFileWithNewlines(t, fileSize, blockSize, "", mockClient, linesPerBlock)
-		reader := file.NewReader(fileInode, mockClient, fileSize, blockSize, "", false)
+		reader := file.NewReader(ctx, fileInode, mockClient, fileSize, blockSize, "", false)
 		defer reader.Close()
 		seekN, err := reader.Seek(int64(lineStart), 0)
 		require.NoError(t, err)
@@ -263,7 +302,7 @@ func TestFileReader(t *testing.T) {
 		compression := "snappy"
 
 		_, fileInode, _, _, lineStart, line := createFileWithNewlines(t, fileSize, blockSize, compression, mockClient, linesPerBlock)
-		reader := file.NewReader(fileInode, mockClient, fileSize, blockSize, compression, false)
+		reader := file.NewReader(ctx, fileInode, mockClient, fileSize, blockSize, compression, false)
 		defer reader.Close()
 		seekN, err := reader.Seek(int64(lineStart), 0)
 		require.NoError(t, err)
@@ -281,7 +320,7 @@ func TestFileReader(t *testing.T) {
 		compression := "snappy"
 
 		_, fileInode, _, _, lineStart, line := createFileWithNewlines(t, fileSize, blockSize, compression, mockClient, linesPerBlock)
-		reader := file.NewReader(fileInode, mockClient, fileSize, blockSize, compression, true)
+		reader := file.NewReader(ctx, fileInode, mockClient, fileSize, blockSize, compression, true)
 		defer reader.Close()
 		seekN, err := reader.Seek(int64(lineStart), 0)
 		require.NoError(t, err)
@@ -303,6 +342,7 @@ func TestFileReader(t *testing.T) {
 }
 
 func createFile(t *testing.T, fileSize uint64, blockSize uint32, compression string, mockClient *mock.BeeClient) ([]byte, file.INode) {
+	ctx := context.Background()
 	var fileBlocks []*file.BlockInfo
 	noOfBlocks := fileSize / uint64(blockSize)
 	if fileSize%uint64(blockSize) != 0 {
@@ -329,7 +369,7 @@ func createFile(t *testing.T, fileSize uint64, blockSize uint32, compression str
 			buf = compressedData
 		}
 
-		addr, err := mockClient.UploadBlob(buf, 0, true, true)
+		addr, err := mockClient.UploadBlob(ctx, buf, 0, true, true)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -348,6 +388,7 @@ func createFile(t *testing.T, fileSize uint64, blockSize uint32, compression str
 }
 
 func createFileWithNewlines(t *testing.T, fileSize uint64, blockSize uint32, compression string, mockClient *mock.BeeClient, linesPerBlock uint32) ([]byte, file.INode, int, []byte, int, []byte) {
+	ctx := context.Background()
 	var fileBlocks []*file.BlockInfo
 	noOfBlocks := fileSize / uint64(blockSize)
 	if fileSize%uint64(blockSize) != 0 {
@@ -453,7 +494,7 @@ func createFileWithNewlines(t *testing.T, fileSize uint64, blockSize uint32, com
 			buf = compressedData
 		}
 
-		addr, err := mockClient.UploadBlob(buf, 0, true, true)
+		addr, err := mockClient.UploadBlob(ctx, buf, 0, true, true)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -0,0 +1,67 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+)
+
+// References marks the inode and the blocks of a file and of its versions. A deleted file
+// has no references.
+func (f *File) References(ctx context.Context, podFileWithPath, podPassword string, mark func(ref []byte)) error {
+	meta, err := f.GetMetaFromFileName(ctx, podFileWithPath, podPassword, f.userAddress)
+	if errors.Is(err, ErrDeletedFeed) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	err = f.inodeReferences(ctx, meta.InodeAddress, mark)
+	if err != nil {
+		return err
+	}
+	return f.versionReferences(ctx, utils.CombinePathAndFile(meta.Path, meta.Name), podPassword, mark)
+}
+
+// inodeReferences marks an inode and its blocks
+func (f *File) inodeReferences(ctx context.Context, inodeAddress []byte, mark func(ref []byte)) error {
+	mark(inodeAddress)
+
+	fileInodeBytes, respCode, err := f.client.DownloadBlob(ctx, inodeAddress)
+	if err != nil {
+		return err
+	}
+	if respCode != http.StatusOK { // skipcq: TCV-001
+		return fmt.Errorf("could not download inode %v", swarm.NewAddress(inodeAddress).String())
+	}
+	var fInode INode
+	err = json.Unmarshal(fileInodeBytes, &fInode)
+	if err != nil { // skipcq: TCV-001
+		return fmt.Errorf("could not unmarshall data in address %v", swarm.NewAddress(inodeAddress).String())
+	}
+	for _, block := range fInode.Blocks {
+		mark(block.Reference.Bytes())
+	}
+	return nil
+}
@@ -20,6 +20,7 @@ import (
 )
 
 func TestRename(t *testing.T) {
+	ctx := context.Background()
 	mockClient := mock.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -53,7 +54,7 @@ func TestRename(t *testing.T) {
 		if fileObject.IsFileAlreadyPresent(podFile) {
 			t.Fatal("file should not be present")
 		}
-		_, _, err = fileObject.Download(podFile, podPassword)
+		_, _, err = fileObject.Download(ctx, podFile, podPassword)
 		if err == nil {
 			t.Fatal("file should not be present for download")
 		}
@@ -64,7 +65,7 @@ func TestRename(t *testing.T) {
 		}
 
 		newPodFile := utils.CombinePathAndFile(filePath, newFileName)
-		_, err = fileObject.RenameFromFileName(podFile, newPodFile, podPassword)
+		_, err = fileObject.RenameFromFileName(ctx, podFile, newPodFile, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -76,7 +77,7 @@ func TestRename(t *testing.T) {
 		}
 
 		// Download the file and read from reader
-		reader, rcvdSize, err := fileObject.Download(utils.CombinePathAndFile(filePath, newFileName), podPassword)
+		reader, rcvdSize, err := fileObject.Download(ctx, utils.CombinePathAndFile(filePath, newFileName), podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -108,17 +109,17 @@ func TestRename(t *testing.T) {
 		podPassword, _ := utils.GetRandString(pod.PasswordLength)
 
 		// make root dir so that other directories can be added
-		err = dirObject.MkRootDir("pod1", podPassword, user, fd)
+		err = dirObject.MkRootDir(ctx, "pod1", podPassword, user, fd)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// populate the directory with few directory and files
-		err = dirObject.MkDir(filePath, podPassword)
+		err = dirObject.MkDir(ctx, filePath, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = dirObject.MkDir(newFilePath, podPassword)
+		err = dirObject.MkDir(ctx, newFilePath, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -138,7 +139,7 @@ func TestRename(t *testing.T) {
 		if fileObject.IsFileAlreadyPresent(newPodFile) {
 			t.Fatal("file should not be present")
 		}
-		_, err = fileObject.RenameFromFileName(podFile, newPodFile, podPassword)
+		_, err = fileObject.RenameFromFileName(ctx, podFile, newPodFile, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -154,7 +155,7 @@ func TestRename(t *testing.T) {
 			t.Fatal("new name should be present")
 		}
 		// Download the file and read from reader
-		reader, rcvdSize, err := fileObject.Download(newPodFile, podPassword)
+		reader, rcvdSize, err := fileObject.Download(ctx, newPodFile, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -17,6 +17,7 @@ limitations under the License.
 package file
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -27,16 +28,65 @@ import (
 )
 
 // RmFile deletes all the blocks of a file, and it related metadata from the Swarm network.
-func (f *File) RmFile(podFileWithPath, podPassword string) error {
+func (f *File) RmFile(ctx context.Context, podFileWithPath, podPassword string) error {
 	totalFilePath := utils.CombinePathAndFile(podFileWithPath, "")
-	meta, err := f.GetMetaFromFileName(totalFilePath, podPassword, f.userAddress)
+	meta, err := f.GetMetaFromFileName(ctx, totalFilePath, podPassword, f.userAddress)
 	if errors.Is(err, ErrDeletedFeed) { // skipcq: TCV-001
 		return nil
 	}
 	if err != nil {
 		return err
 	}
-	fileInodeBytes, respCode, err := f.client.DownloadBlob(meta.InodeAddress)
+	// a file received from another user has the inode and the blocks of the sender, which
+	// are not unpinned
+	owned, err := f.ownsReference(ctx, meta.InodeAddress)
+	if err != nil { // skipcq: TCV-001
+		return err
+	}
+	if owned {
+		err = f.deleteInode(ctx, meta)
+		if err != nil {
+			return err
+		}
+	}
+	// remove the meta
+	topic := utils.HashString(totalFilePath)
+	_, err = f.fd.UpdateFeed(ctx, topic, f.userAddress, []byte(utils.DeletedFeedMagicWord), []byte(podPassword)) // empty byte array will fail, so some 1 byte
+	if err != nil {                                                                                              // skipcq: TCV-001
+		return err
+	}
+
+	// the versions of a removed file are not kept
+	err = f.removeVersions(ctx, totalFilePath, podPassword)
+	if err != nil { // skipcq: TCV-001
+		return err
+	}
+
+	// remove the file from file map
+	f.RemoveFromFileMap(totalFilePath)
+
+	return nil
+}
+
+// refOwner is implemented by the blockstore client of a pod, which records the blobs the
+// pod uploaded
+type refOwner interface {
+	Owns(ctx context.Context, ref []byte) (bool, error)
+}
+
+// ownsReference tells if the pod uploaded the blob itself. Without a record of the
+// uploads every blob is taken as owned.
+func (f *File) ownsReference(ctx context.Context, ref []byte) (bool, error) {
+	owner, ok := f.client.(refOwner)
+	if !ok {
+		return true, nil
+	}
+	return owner.Owns(ctx, ref)
+}
+
+// deleteInode unpins the inode of a file and its blocks
+func (f *File) deleteInode(ctx context.Context, meta *MetaData) error {
+	fileInodeBytes, respCode, err := f.client.DownloadBlob(ctx, meta.InodeAddress)
 	if err != nil { // skipcq: TCV-001
 		return err
 	}
@@ -53,27 +103,21 @@ func (f *File) RmFile(podFileWithPath, podPassword string) error {
 		return fmt.Errorf("could not unmarshall data in address %v", swarm.NewAddress(meta.InodeAddress).String())
 	}
 
-	err = f.client.DeleteReference(meta.InodeAddress)
+	err = f.client.DeleteReference(ctx, meta.InodeAddress)
 	if err != nil {
 		f.logger.Errorf("could not delete file inode %s", swarm.NewAddress(meta.InodeAddress).String())
 		return fmt.Errorf("could not delete file inode %v", swarm.NewAddress(meta.InodeAddress).String())
 	}
+	// shared blocks are left to the garbage collector, which knows if a file still uses them
+	if meta.SharedBlocks {
+		return nil
+	}
 	for _, fblocks := range fInode.Blocks {
-		err = f.client.DeleteReference(fblocks.Reference.Bytes())
+		err = f.client.DeleteReference(ctx, fblocks.Reference.Bytes())
 		if err != nil { // skipcq: TCV-001
 			f.logger.Errorf("could not delete file block %s", swarm.NewAddress(fblocks.Reference.Bytes()).String())
 			return fmt.Errorf("could not delete file inode %v", swarm.NewAddress(fblocks.Reference.Bytes()).String())
 		}
 	}
-	// remove the meta
-	topic := utils.HashString(totalFilePath)
-	_, err = f.fd.UpdateFeed(topic, f.userAddress, []byte(utils.DeletedFeedMagicWord), []byte(podPassword)) // empty byte array will fail, so some 1 byte
-	if err != nil {                                                                                         // skipcq: TCV-001
-		return err
-	}
-
-	// remove the file from file map
-	f.RemoveFromFileMap(totalFilePath)
-
 	return nil
 }
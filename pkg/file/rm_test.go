@@ -19,6 +19,7 @@ package file_test
 import (
 	"context"
 	"io"
+	"strconv"
 	"testing"
 	"time"
 
@@ -32,12 +33,23 @@ import (
 	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
 
 	"github.com/fairdatasociety/fairOS-dfs/pkg/account"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/bee/mock"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
 )
 
+// otherPodClient is the blockstore client of a pod that uploaded none of the blobs
+type otherPodClient struct {
+	blockstore.Client
+}
+
+func (otherPodClient) Owns(_ context.Context, _ []byte) (bool, error) {
+	return false, nil
+}
+
 func TestRemoveFile(t *testing.T) {
+	ctx := context.Background()
 	mockClient := mock.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -58,7 +70,7 @@ func TestRemoveFile(t *testing.T) {
 		podPassword, _ := utils.GetRandString(pod.PasswordLength)
 		fileObject := file.NewFile("pod1", mockClient, fd, user, tm, logger)
 		// remove file2
-		err = fileObject.RmFile("/dir1/file2", podPassword)
+		err = fileObject.RmFile(ctx, "/dir1/file2", podPassword)
 		require.Equal(t, err.Error(), "feed does not exist or was not updated yet")
 
 		file1, _ := utils.GetRandString(12)
@@ -71,7 +83,7 @@ func TestRemoveFile(t *testing.T) {
 		require.NoError(t, err)
 
 		// remove file2
-		err = fileObject.RmFile("/dir1/"+file2, podPassword)
+		err = fileObject.RmFile(ctx, "/dir1/"+file2, podPassword)
 		require.NoError(t, err)
 
 		// validate file deletion
@@ -88,7 +100,7 @@ func TestRemoveFile(t *testing.T) {
 		if meta.Name != file1 {
 			t.Fatalf("retrieved invalid file name")
 		}
-		err := fileObject.LoadFileMeta(utils.CombinePathAndFile("/dir1", file1), podPassword)
+		err := fileObject.LoadFileMeta(ctx, utils.CombinePathAndFile("/dir1", file1), podPassword)
 		require.NoError(t, err)
 	})
 
@@ -103,7 +115,7 @@ func TestRemoveFile(t *testing.T) {
 			require.NoError(t, err)
 
 			// remove file1
-			err = fileObject.RmFile("/dir1/"+filename, podPassword)
+			err = fileObject.RmFile(ctx, "/dir1/"+filename, podPassword)
 			require.NoError(t, err)
 
 			// validate file deletion
@@ -113,4 +125,36 @@ func TestRemoveFile(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("delete-received-file", func(t *testing.T) {
+		podPassword, _ := utils.GetRandString(pod.PasswordLength)
+		fileObject := file.NewFile("pod1", mockClient, fd, user, tm, logger)
+		content, err := uploadFile(t, fileObject, "/dir1", "shared", "", podPassword, 100, 10)
+		require.NoError(t, err)
+
+		// the received file has the inode of the sender, like ReceiveFileFromUser makes it
+		pod2AccountInfo, err := acc.CreatePodAccount(2, false)
+		require.NoError(t, err)
+		fd2 := feed.New(pod2AccountInfo, mockClient, logger)
+		receiver := file.NewFile("pod2", otherPodClient{mockClient}, fd2, acc.GetAddress(2), tm, logger)
+		for i, shared := range []bool{true, false} {
+			received := *fileObject.GetFromFileMap("/dir1/shared")
+			received.Name = "received" + strconv.Itoa(i)
+			received.SharedBlocks = shared
+			receiver.AddToFileMap("/dir1/"+received.Name, &received)
+			err = receiver.PutMetaForFile(ctx, &received, podPassword)
+			require.NoError(t, err)
+
+			err = receiver.RmFile(ctx, "/dir1/"+received.Name, podPassword)
+			require.NoError(t, err)
+		}
+
+		// the sender still has the blocks
+		reader, _, err := fileObject.Download(ctx, "/dir1/shared", podPassword)
+		require.NoError(t, err)
+		defer reader.Close()
+		data, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		require.Equal(t, content, data)
+	})
 }
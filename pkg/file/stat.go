@@ -17,26 +17,32 @@ limitations under the License.
 package file
 
 import (
+	"context"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"strconv"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
 )
 
 // Stats
 type Stats struct {
-	PodName          string   `json:"podName"`
-	Mode             uint32   `json:"mode"`
-	FilePath         string   `json:"filePath"`
-	FileName         string   `json:"fileName"`
-	FileSize         string   `json:"fileSize"`
-	BlockSize        string   `json:"blockSize"`
-	Compression      string   `json:"compression"`
-	ContentType      string   `json:"contentType"`
-	CreationTime     string   `json:"creationTime"`
-	ModificationTime string   `json:"modificationTime"`
-	AccessTime       string   `json:"accessTime"`
-	Blocks           []Blocks `json:"blocks"`
+	PodName           string            `json:"podName"`
+	Mode              uint32            `json:"mode"`
+	FilePath          string            `json:"filePath"`
+	FileName          string            `json:"fileName"`
+	FileSize          string            `json:"fileSize"`
+	BlockSize         string            `json:"blockSize"`
+	Compression       string            `json:"compression"`
+	ContentType       string            `json:"contentType"`
+	CreationTime      string            `json:"creationTime"`
+	ModificationTime  string            `json:"modificationTime"`
+	AccessTime        string            `json:"accessTime"`
+	Checksum          string            `json:"checksum,omitempty"`
+	BlockTreeChecksum string            `json:"blockTreeChecksum,omitempty"`
+	Blocks            []Blocks          `json:"blocks"`
+	Xattrs            map[string]string `json:"xattrs,omitempty"`
 }
 
 // Blocks
@@ -44,17 +50,19 @@ type Blocks struct {
 	Reference      string `json:"reference"`
 	Size           string `json:"size"`
 	CompressedSize string `json:"compressedSize"`
+	Checksum       string `json:"checksum,omitempty"`
+	Compression    string `json:"compression,omitempty"`
 }
 
 // GetStats given a filename this function returns all the information about the file
 // including the block information.
-func (f *File) GetStats(podName, podFileWithPath, podPassword string) (*Stats, error) {
+func (f *File) GetStats(ctx context.Context, podName, podFileWithPath, podPassword string) (*Stats, error) {
 	meta := f.GetFromFileMap(podFileWithPath)
 	if meta == nil { // skipcq: TCV-001
 		return nil, fmt.Errorf("file not found")
 	}
 
-	fileInodeBytes, _, err := f.getClient().DownloadBlob(meta.InodeAddress)
+	fileInodeBytes, _, err := f.getClient().DownloadBlob(ctx, meta.InodeAddress)
 	if err != nil { // skipcq: TCV-001
 		return nil, err
 	}
@@ -71,21 +79,26 @@ func (f *File) GetStats(podName, podFileWithPath, podPassword string) (*Stats, e
 			Reference:      hex.EncodeToString(b.Reference.Bytes()),
 			Size:           strconv.Itoa(int(b.Size)),
 			CompressedSize: strconv.Itoa(int(b.CompressedSize)),
+			Checksum:       b.Checksum,
+			Compression:    b.Compression,
 		}
 		fileBlocks = append(fileBlocks, fb)
 	}
 	return &Stats{
-		PodName:          podName,
-		FilePath:         meta.Path,
-		FileName:         meta.Name,
-		Mode:             meta.Mode,
-		FileSize:         strconv.FormatUint(meta.Size, 10),
-		BlockSize:        strconv.Itoa(int(meta.BlockSize)),
-		Compression:      meta.Compression,
-		ContentType:      meta.ContentType,
-		CreationTime:     strconv.FormatInt(meta.CreationTime, 10),
-		ModificationTime: strconv.FormatInt(meta.ModificationTime, 10),
-		AccessTime:       strconv.FormatInt(meta.AccessTime, 10),
-		Blocks:           fileBlocks,
+		PodName:           podName,
+		FilePath:          meta.Path,
+		FileName:          meta.Name,
+		Mode:              meta.Mode,
+		FileSize:          strconv.FormatUint(meta.Size, 10),
+		BlockSize:         strconv.Itoa(int(meta.BlockSize)),
+		Compression:       meta.Compression,
+		ContentType:       meta.ContentType,
+		CreationTime:      strconv.FormatInt(meta.CreationTime, 10),
+		ModificationTime:  strconv.FormatInt(meta.ModificationTime, 10),
+		AccessTime:        strconv.FormatInt(meta.AccessTime, 10),
+		Checksum:          meta.Checksum,
+		BlockTreeChecksum: meta.BlockTreeChecksum,
+		Xattrs:            utils.CopyXattrs(meta.Xattrs),
+		Blocks:            fileBlocks,
 	}, nil
 }
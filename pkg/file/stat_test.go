@@ -36,6 +36,7 @@ import (
 )
 
 func TestStat(t *testing.T) {
+	ctx := context.Background()
 	mockClient := mock.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -65,7 +66,7 @@ func TestStat(t *testing.T) {
 		}
 
 		// stat the file
-		stats, err := fileObject.GetStats("pod1", "/dir1/file1", podPassword)
+		stats, err := fileObject.GetStats(ctx, "pod1", "/dir1/file1", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -1,11 +1,12 @@
 package file
 
 import (
+	"context"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
 )
 
 // Status does all the validation for the existence of the file and checks file sync status
-func (f *File) Status(podFileWithPath, podPassword string) (int64, int64, int64, error) {
+func (f *File) Status(ctx context.Context, podFileWithPath, podPassword string) (int64, int64, int64, error) {
 	// check if file present
 	totalFilePath := utils.CombinePathAndFile(podFileWithPath, "")
 	if !f.IsFileAlreadyPresent(totalFilePath) {
@@ -17,5 +18,5 @@ func (f *File) Status(podFileWithPath, podPassword string) (int64, int64, int64,
 		return 0, 0, 0, ErrFileTagPresent
 	}
 
-	return f.client.GetTag(tag)
+	return f.client.GetTag(ctx, tag)
 }
@@ -17,6 +17,7 @@ import (
 )
 
 func TestStatus(t *testing.T) {
+	ctx := context.Background()
 	mockClient := mock.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -45,13 +46,13 @@ func TestStatus(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		_, _, _, err := fileObject.Status("/dir1/file12", podPassword)
+		_, _, _, err := fileObject.Status(ctx, "/dir1/file12", podPassword)
 		if err == nil {
 			t.Fatal("should be error")
 		}
 
 		// status the file
-		total, _, _, err := fileObject.Status("/dir1/file1", podPassword)
+		total, _, _, err := fileObject.Status(ctx, "/dir1/file1", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -19,18 +19,18 @@ package file
 import (
 	"bufio"
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"path/filepath"
 	"runtime"
-	"sync"
 	"time"
 
 	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
-	"github.com/golang/snappy"
-	"github.com/klauspost/pgzip"
 )
 
 const (
@@ -50,7 +50,7 @@ var (
 // Upload uploads a given blob of bytes as a file in the pod. It also splits the file into number of blocks. the
 // size of the block is provided during upload. This function also does compression of the blocks gzip/snappy if it is
 // requested during the upload.
-func (f *File) Upload(fd io.Reader, podFileName string, fileSize int64, blockSize uint32, podPath, compression, podPassword string) error {
+func (f *File) Upload(ctx context.Context, fd io.Reader, podFileName string, fileSize int64, blockSize uint32, podPath, compression, podPassword string) error {
 	podPath = filepath.ToSlash(podPath)
 	// check compression gzip and blocksize
 	// pgzip does not allow block size lower or equal to 163840,
@@ -58,10 +58,14 @@ func (f *File) Upload(fd io.Reader, podFileName string, fileSize int64, blockSiz
 	if compression == "gzip" && blockSize < minBlockSizeForGzip {
 		return ErrGzipBlSize
 	}
+	err := CheckCompression(compression)
+	if err != nil {
+		return err
+	}
 	reader := bufio.NewReader(fd)
 	now := time.Now().Unix()
 
-	tag, err := f.client.CreateTag(nil)
+	tag, err := f.client.CreateTag(ctx, nil)
 	if err != nil { // skipcq: TCV-001
 		return err
 	}
@@ -80,111 +84,80 @@ func (f *File) Upload(fd io.Reader, podFileName string, fileSize int64, blockSiz
 	}
 
 	var totalLength uint64
-	i := 0
-	errC := make(chan error)
-	doneC := make(chan bool)
-	worker := make(chan bool, noOfParallelWorkers)
-	var wg sync.WaitGroup
-	refMap := make(map[int]*BlockInfo)
-	refMapMu := sync.RWMutex{}
 	var contentBytes []byte
-	wg.Add(1)
-	go func() {
-		var mainErr error
-		for {
-			if mainErr != nil { // skipcq: TCV-001
-				errC <- mainErr
-				wg.Done()
-				return
-			}
-			data := make([]byte, blockSize, blockSize+1024)
-			r, err := reader.Read(data)
-			totalLength += uint64(r)
-			if err != nil {
-				if err == io.EOF {
-					if totalLength < uint64(fileSize) { // skipcq: TCV-001
-						errC <- fmt.Errorf("invalid file length of file data received")
-						return
-					}
-					wg.Done()
-					break
-				}
-				errC <- err // skipcq: TCV-001
-				return
-			}
+	fileINode := INode{}
 
-			// determine the content type from the first 512 bytes of the file
-			if len(contentBytes) < 512 {
-				contentBytes = append(contentBytes, data[:r]...)
-				if len(contentBytes) >= 512 { // skipcq: TCV-001
-					cBytes := bytes.NewReader(contentBytes[:512])
-					cReader := bufio.NewReader(cBytes)
-					meta.ContentType = f.getContentType(cReader)
-				}
-			}
+	// the blocks are uploaded in batches, so that the blockstore can send them in few requests
+	var batch [][]byte
+	var batchBlocks []*BlockInfo
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		f.logger.Infof("Uploading blocks %d to %d", len(fileINode.Blocks), len(fileINode.Blocks)+len(batch)-1)
+		addrs, err := f.client.UploadBlobs(ctx, batch, tag, true, true)
+		if err != nil {
+			return err
+		}
+		for i, addr := range addrs {
+			batchBlocks[i].Reference = utils.NewReference(addr)
+		}
+		fileINode.Blocks = append(fileINode.Blocks, batchBlocks...)
+		batch, batchBlocks = nil, nil
+		return nil
+	}
 
-			wg.Add(1)
-			worker <- true
-			go func(counter, size int) {
-				defer func() {
-					<-worker
-					wg.Done()
-					if mainErr != nil { // skipcq: TCV-001
-						f.logger.Error("failed uploading block ", counter)
-						return
-					}
-					f.logger.Info("done uploading block ", counter)
-				}()
-
-				f.logger.Infof("Uploading %d block", counter)
-				// Compress the data
-				uploadData := data[:size]
-				if compression != "" {
-					uploadData, err = Compress(data[:size], compression, blockSize)
-					if err != nil { // skipcq: TCV-001
-						mainErr = err
-						return
-					}
+	contentHash := sha256.New()
+	for lastBlock := false; !lastBlock; {
+		// every block but the last is full, as the readers find the blocks by offset
+		data := make([]byte, blockSize, blockSize+1024)
+		r, err := io.ReadFull(reader, data)
+		totalLength += uint64(r)
+		if err != nil {
+			if err == io.EOF {
+				if totalLength < uint64(fileSize) { // skipcq: TCV-001
+					return fmt.Errorf("invalid file length of file data received")
 				}
+				break
+			}
+			if err != io.ErrUnexpectedEOF { // skipcq: TCV-001
+				return err
+			}
+			if totalLength < uint64(fileSize) { // skipcq: TCV-001
+				return fmt.Errorf("invalid file length of file data received")
+			}
+			lastBlock = true
+		}
 
-				addr, uploadErr := f.client.UploadBlob(uploadData, tag, true, true)
-				if uploadErr != nil {
-					mainErr = uploadErr
-					return
-				}
+		_, _ = contentHash.Write(data[:r])
 
-				fileBlock := &BlockInfo{
-					Size:           uint32(size),
-					CompressedSize: uint32(len(uploadData)),
-					Reference:      utils.NewReference(addr),
-				}
-
-				refMapMu.Lock()
-				defer refMapMu.Unlock()
-				refMap[counter] = fileBlock
-			}(i, r)
+		// determine the content type from the first 512 bytes of the file
+		if len(contentBytes) < 512 {
+			contentBytes = append(contentBytes, data[:r]...)
+			if len(contentBytes) >= 512 { // skipcq: TCV-001
+				cBytes := bytes.NewReader(contentBytes[:512])
+				cReader := bufio.NewReader(cBytes)
+				meta.ContentType = f.getContentType(cReader)
+			}
+		}
 
-			i++
+		// Compress the data
+		block, uploadData, err := compressBlock(data[:r], compression, blockSize)
+		if err != nil { // skipcq: TCV-001
+			return err
+		}
+		batch = append(batch, uploadData)
+		batchBlocks = append(batchBlocks, block)
+		if len(batch) >= noOfParallelWorkers {
+			err = flush()
+			if err != nil {
+				return err
+			}
 		}
-	}()
-
-	go func() {
-		wg.Wait()
-		close(doneC)
-	}()
-
-	select {
-	case <-doneC:
-		break
-	case err := <-errC: // skipcq: TCV-001
-		close(errC)
-		return err
 	}
-
-	// copy the block references to the fileInode
-	fileINode := INode{}
-	for i := 0; i < len(refMap); i++ {
-		fileINode.Blocks = append(fileINode.Blocks, refMap[i])
+	err = flush()
+	if err != nil {
+		return err
 	}
 
 	fileInodeData, err := json.Marshal(fileINode)
@@ -192,13 +165,15 @@ func (f *File) Upload(fd io.Reader, podFileName string, fileSize int64, blockSiz
 		return err
 	}
 
-	addr, err := f.client.UploadBlob(fileInodeData, 0, true, true)
+	addr, err := f.client.UploadBlob(ctx, fileInodeData, 0, true, true)
 	if err != nil { // skipcq: TCV-001
 		return err
 	}
 
 	meta.InodeAddress = addr
-	err = f.handleMeta(&meta, podPassword)
+	meta.BlockTreeChecksum = blockTreeChecksum(fileINode.Blocks)
+	meta.Checksum = hex.EncodeToString(contentHash.Sum(nil))
+	err = f.handleMeta(ctx, &meta, podPassword)
 	if err != nil { // skipcq: TCV-001
 		return err
 	}
@@ -219,29 +194,3 @@ func (*File) getContentType(bufferReader *bufio.Reader) string {
 	}
 	return http.DetectContentType(buffer)
 }
-
-// Compress data
-func Compress(dataToCompress []byte, compression string, blockSize uint32) ([]byte, error) {
-	switch compression {
-	case "gzip":
-		var b bytes.Buffer
-		w := pgzip.NewWriter(&b)
-		block := int(blockSize / 10)
-		err := w.SetConcurrency(block, 10)
-		if err != nil {
-			return nil, err
-		}
-		_, err = w.Write(dataToCompress)
-		if err != nil { // skipcq: TCV-001
-			return nil, err
-		}
-		err = w.Close()
-		if err != nil {
-			return nil, err
-		}
-		return b.Bytes(), nil
-	case "snappy":
-		return snappy.Encode(nil, dataToCompress), nil
-	}
-	return dataToCompress, nil
-}
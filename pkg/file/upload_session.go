@@ -0,0 +1,569 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+)
+
+const (
+	uploadSessionsFile    = "upload_sessions"
+	uploadSessionPrefix   = "upload_session/"
+	uploadSessionIdLength = 16
+
+	// uploadRecordsPerSnapshot is the number of batch records after which the whole
+	// session is stored again, which bounds the records that are read to load a session
+	uploadRecordsPerSnapshot = 64
+)
+
+var (
+	// ErrUploadSessionNotFound is returned for an upload session that does not exist or
+	// is finished
+	ErrUploadSessionNotFound = errors.New("upload session not found")
+
+	// ErrInvalidPart is returned for a part that does not start and end at a block boundary
+	// or that goes beyond the size of the file
+	ErrInvalidPart = errors.New("part does not start and end at a block boundary of the file")
+
+	// ErrUploadIncomplete is returned when an upload session that misses blocks is finished
+	ErrUploadIncomplete = errors.New("upload session is missing blocks")
+)
+
+// UploadSession is an upload of a file whose blocks are sent in parts, which can be
+// resumed after the client reconnects. The session is stored in the pod, so the blocks
+// that are already stored can be looked up from any connection.
+type UploadSession struct {
+	ID           string `json:"uploadId"`
+	Path         string `json:"filePath"`
+	Name         string `json:"fileName"`
+	Size         uint64 `json:"fileSize"`
+	BlockSize    uint32 `json:"blockSize"`
+	Compression  string `json:"compression"`
+	Tag          uint32 `json:"tag"`
+	CreationTime int64  `json:"creationTime"`
+	// Blocks has an entry for every block of the file, nil for a block that is not stored yet
+	Blocks []*BlockInfo `json:"blocks"`
+}
+
+// uploadRecord is a blob of the chain an upload session is stored in. A snapshot holds the
+// whole session. Every record after it holds the blocks of one stored batch and links to the
+// record before it, so that a batch is stored without writing the blocks of the whole file.
+type uploadRecord struct {
+	Prev []byte `json:"prev,omitempty"`
+	// Depth is the number of records since the snapshot
+	Depth   int                `json:"depth,omitempty"`
+	Session *UploadSession     `json:"session,omitempty"`
+	Blocks  map[int]*BlockInfo `json:"blocks,omitempty"`
+}
+
+// blockLength returns the length of the uncompressed block at the index
+func (s *UploadSession) blockLength(index int) uint64 {
+	start := uint64(index) * uint64(s.BlockSize)
+	if s.Size-start < uint64(s.BlockSize) {
+		return s.Size - start
+	}
+	return uint64(s.BlockSize)
+}
+
+// StoredBlocks returns the indexes of the blocks that are stored
+func (s *UploadSession) StoredBlocks() []int {
+	stored := []int{}
+	for i, b := range s.Blocks {
+		if b != nil {
+			stored = append(stored, i)
+		}
+	}
+	return stored
+}
+
+// NextOffset returns the offset of the first block that is not stored, or the file size
+// if all the blocks are stored
+func (s *UploadSession) NextOffset() uint64 {
+	for i, b := range s.Blocks {
+		if b == nil {
+			return uint64(i) * uint64(s.BlockSize)
+		}
+	}
+	return s.Size
+}
+
+// Complete tells if all the blocks of the file are stored
+func (s *UploadSession) Complete() bool {
+	return s.NextOffset() == s.Size
+}
+
+// CreateUploadSession starts an upload session for a file of the given size. The blocks of
+// the file are sent with UploadPart and the file is created by FinishUploadSession.
+func (f *File) CreateUploadSession(ctx context.Context, podFileName string, fileSize int64, blockSize uint32, podPath, compression, podPassword string) (*UploadSession, error) {
+	podPath = filepath.ToSlash(podPath)
+	if compression == "gzip" && blockSize < minBlockSizeForGzip {
+		return nil, ErrGzipBlSize
+	}
+	err := CheckCompression(compression)
+	if err != nil {
+		return nil, err
+	}
+	if blockSize == 0 || fileSize < 0 {
+		return nil, fmt.Errorf("invalid block size %d or file size %d", blockSize, fileSize)
+	}
+	id, err := utils.GetRandString(uploadSessionIdLength)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	tag, err := f.client.CreateTag(ctx, nil)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	s := &UploadSession{
+		ID:           id,
+		Path:         podPath,
+		Name:         podFileName,
+		Size:         uint64(fileSize),
+		BlockSize:    blockSize,
+		Compression:  compression,
+		Tag:          tag,
+		CreationTime: time.Now().Unix(),
+	}
+	blockCount := (s.Size + uint64(blockSize) - 1) / uint64(blockSize)
+	s.Blocks = make([]*BlockInfo, blockCount)
+
+	// the session is listed first, so that its blocks are never left out of the references
+	err = f.modifyUploadSessionIds(ctx, podPassword, func(ids []string) ([]string, error) {
+		return append(ids, id), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = f.storeUploadRecord(ctx, id, &uploadRecord{Session: s}, nil, podPassword)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	return s, nil
+}
+
+// GetUploadSession returns an upload session with the blocks that are stored
+func (f *File) GetUploadSession(ctx context.Context, uploadId, podPassword string) (*UploadSession, error) {
+	s, _, err := f.loadUploadSession(ctx, uploadId, podPassword)
+	return s, err
+}
+
+// ListUploadSessions returns the ids of the upload sessions of the pod that are not
+// finished or aborted
+func (f *File) ListUploadSessions(ctx context.Context, podPassword string) ([]string, error) {
+	_, ids, err := f.loadUploadSessionIds(ctx, podPassword)
+	return ids, err
+}
+
+// UploadPart stores the blocks of a part of the file that starts at the offset. The part
+// must start at a block boundary and hold whole blocks, except for the last block of the
+// file. Blocks that are sent again replace the stored ones. The blocks are recorded in the
+// session batch by batch, so a part that is cut off keeps the blocks it sent.
+func (f *File) UploadPart(ctx context.Context, uploadId string, offset uint64, fd io.Reader, podPassword string) (*UploadSession, error) {
+	s, err := f.GetUploadSession(ctx, uploadId, podPassword)
+	if err != nil {
+		return nil, err
+	}
+	if offset%uint64(s.BlockSize) != 0 || (offset >= s.Size && s.Size > 0) {
+		return nil, ErrInvalidPart
+	}
+
+	var batch [][]byte
+	var batchBlocks []*BlockInfo
+	var batchIndexes []int
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		f.logger.Infof("Uploading blocks %d to %d of upload %s", batchIndexes[0], batchIndexes[len(batchIndexes)-1], uploadId)
+		addrs, err := f.client.UploadBlobs(ctx, batch, s.Tag, true, true)
+		if err != nil {
+			return err
+		}
+		blocks := make(map[int]*BlockInfo, len(addrs))
+		for i, addr := range addrs {
+			batchBlocks[i].Reference = utils.NewReference(addr)
+			blocks[batchIndexes[i]] = batchBlocks[i]
+		}
+		err = f.addUploadBlocks(ctx, uploadId, blocks, podPassword)
+		if err != nil {
+			return err
+		}
+		batch, batchBlocks, batchIndexes = nil, nil, nil
+		return nil
+	}
+
+	index := int(offset / uint64(s.BlockSize))
+	for ; index < len(s.Blocks); index++ {
+		data := make([]byte, s.blockLength(index))
+		_, err := io.ReadFull(fd, data)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// keep the whole blocks that were received
+			flushErr := flush()
+			if flushErr != nil { // skipcq: TCV-001
+				return nil, flushErr
+			}
+			if err == io.ErrUnexpectedEOF {
+				return nil, ErrInvalidPart
+			}
+			return nil, err
+		}
+
+		block, uploadData, err := compressBlock(data, s.Compression, s.BlockSize)
+		if err != nil { // skipcq: TCV-001
+			return nil, err
+		}
+		batch = append(batch, uploadData)
+		batchIndexes = append(batchIndexes, index)
+		batchBlocks = append(batchBlocks, block)
+		if len(batch) >= noOfParallelWorkers {
+			err = flush()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	err = flush()
+	if err != nil {
+		return nil, err
+	}
+
+	// the part must not go on beyond the end of the file
+	if index == len(s.Blocks) {
+		n, _ := fd.Read(make([]byte, 1))
+		if n > 0 {
+			return nil, ErrInvalidPart
+		}
+	}
+
+	// the session has the blocks of the parts that were sent in parallel too
+	return f.GetUploadSession(ctx, uploadId, podPassword)
+}
+
+// FinishUploadSession creates the file from the blocks of a complete upload session and
+// removes the session. The file replaces an existing file of the same name.
+func (f *File) FinishUploadSession(ctx context.Context, uploadId, podPassword string) (*MetaData, error) {
+	s, err := f.GetUploadSession(ctx, uploadId, podPassword)
+	if err != nil {
+		return nil, err
+	}
+	if !s.Complete() {
+		return nil, ErrUploadIncomplete
+	}
+
+	fileINode := INode{Blocks: s.Blocks}
+	fileInodeData, err := json.Marshal(fileINode)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	addr, err := f.client.UploadBlob(ctx, fileInodeData, 0, true, true)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+
+	// the parts can be sent in any order, so the content is only hashed and its type
+	// determined from the first 512 bytes once all the blocks are stored
+	checksum, head, err := f.contentChecksum(ctx, s.Blocks, s.Compression, s.BlockSize)
+	if err != nil {
+		return nil, err
+	}
+	contentType := ""
+	if len(head) >= 512 {
+		contentType = f.getContentType(bufio.NewReader(bytes.NewReader(head)))
+	}
+
+	now := time.Now().Unix()
+	meta := &MetaData{
+		Version:           MetaVersion,
+		Path:              s.Path,
+		Name:              s.Name,
+		Size:              s.Size,
+		BlockSize:         s.BlockSize,
+		ContentType:       contentType,
+		Compression:       s.Compression,
+		CreationTime:      now,
+		AccessTime:        now,
+		ModificationTime:  now,
+		InodeAddress:      addr,
+		Mode:              S_IFREG | defaultMode,
+		BlockTreeChecksum: blockTreeChecksum(s.Blocks),
+		Checksum:          checksum,
+	}
+	err = f.handleMeta(ctx, meta, podPassword)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+
+	totalPath := utils.CombinePathAndFile(meta.Path, meta.Name)
+	f.AddToFileMap(totalPath, meta)
+	if s.Tag > 0 {
+		f.AddToTagMap(totalPath, s.Tag)
+	}
+
+	err = f.removeUploadSession(ctx, uploadId, podPassword)
+	if err != nil { // skipcq: TCV-001
+		f.logger.Warningf("failed to remove upload session %s: %v", uploadId, err)
+	}
+	return meta, nil
+}
+
+// AbortUploadSession removes an upload session. The blocks it stored are left to the
+// garbage collection of the pod.
+func (f *File) AbortUploadSession(ctx context.Context, uploadId, podPassword string) error {
+	_, err := f.GetUploadSession(ctx, uploadId, podPassword)
+	if err != nil {
+		return err
+	}
+	return f.removeUploadSession(ctx, uploadId, podPassword)
+}
+
+// UploadSessionReferences marks the stored session data and blocks of all the upload
+// sessions of the pod
+func (f *File) UploadSessionReferences(ctx context.Context, podPassword string, mark func(ref []byte)) error {
+	ids, err := f.ListUploadSessions(ctx, podPassword)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		s, records, err := f.loadUploadSession(ctx, id, podPassword)
+		if errors.Is(err, ErrUploadSessionNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		for _, ref := range records {
+			mark(ref)
+		}
+		for _, b := range s.Blocks {
+			if b != nil {
+				mark(b.Reference.Bytes())
+			}
+		}
+	}
+	return nil
+}
+
+// loadUploadSession loads an upload session from its latest snapshot and the records after
+// it. It returns the references of the records that were read.
+func (f *File) loadUploadSession(ctx context.Context, uploadId, podPassword string) (*UploadSession, [][]byte, error) {
+	_, ref, err := f.loadUploadSessionFeed(ctx, uploadId, podPassword)
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		records [][]byte
+		batches []*uploadRecord
+	)
+	for {
+		rec, err := f.downloadUploadRecord(ctx, ref)
+		if err != nil { // skipcq: TCV-001
+			return nil, nil, err
+		}
+		records = append(records, ref)
+		if rec.Session != nil {
+			s := rec.Session
+			for i := len(batches) - 1; i >= 0; i-- {
+				for index, block := range batches[i].Blocks {
+					if index >= 0 && index < len(s.Blocks) {
+						s.Blocks[index] = block
+					}
+				}
+			}
+			return s, records, nil
+		}
+		if rec.Prev == nil { // skipcq: TCV-001
+			return nil, nil, fmt.Errorf("upload session %s has no snapshot", uploadId)
+		}
+		batches = append(batches, rec)
+		ref = rec.Prev
+	}
+}
+
+func (f *File) downloadUploadRecord(ctx context.Context, ref []byte) (*uploadRecord, error) {
+	data, respCode, err := f.client.DownloadBlob(ctx, ref)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	if respCode != http.StatusOK { // skipcq: TCV-001
+		return nil, fmt.Errorf("could not download upload session %x", ref)
+	}
+	var rec uploadRecord
+	err = json.Unmarshal(data, &rec)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	return &rec, nil
+}
+
+// loadUploadSessionFeed returns the reference of the latest feed update of an upload
+// session and the reference of the latest record it points to
+func (f *File) loadUploadSessionFeed(ctx context.Context, uploadId, podPassword string) ([]byte, []byte, error) {
+	topic := utils.HashString(uploadSessionPrefix + uploadId)
+	feedRef, data, err := f.fd.GetFeedData(ctx, topic, f.userAddress, []byte(podPassword))
+	if err != nil {
+		if err.Error() == "feed does not exist or was not updated yet" {
+			return nil, nil, ErrUploadSessionNotFound
+		}
+		return nil, nil, err // skipcq: TCV-001
+	}
+	if string(data) == utils.DeletedFeedMagicWord {
+		return nil, nil, ErrUploadSessionNotFound
+	}
+	return feedRef, data, nil
+}
+
+// addUploadBlocks stores the blocks of a batch as a record after the latest one. A record
+// that conflicts with the record of another writer, like a part that is sent in parallel,
+// is linked after that record instead. Every uploadRecordsPerSnapshot records the whole
+// session is stored as a new snapshot.
+func (f *File) addUploadBlocks(ctx context.Context, uploadId string, blocks map[int]*BlockInfo, podPassword string) error {
+	for retries := 0; ; retries++ {
+		feedRef, ref, err := f.loadUploadSessionFeed(ctx, uploadId, podPassword)
+		if err != nil {
+			return err
+		}
+		head, err := f.downloadUploadRecord(ctx, ref)
+		if err != nil { // skipcq: TCV-001
+			return err
+		}
+		rec := &uploadRecord{
+			Prev:   ref,
+			Depth:  head.Depth + 1,
+			Blocks: blocks,
+		}
+		if rec.Depth >= uploadRecordsPerSnapshot {
+			s, _, err := f.loadUploadSession(ctx, uploadId, podPassword)
+			if err != nil { // skipcq: TCV-001
+				return err
+			}
+			for index, block := range blocks {
+				s.Blocks[index] = block
+			}
+			rec = &uploadRecord{Session: s}
+		}
+		err = f.storeUploadRecord(ctx, uploadId, rec, feedRef, podPassword)
+		if errors.Is(err, feed.ErrConflict) && retries < feed.ConflictRetries {
+			f.logger.Debugf("merging concurrent change of upload session %s", uploadId)
+			continue
+		}
+		return err
+	}
+}
+
+// storeUploadRecord stores a record of an upload session, if the latest feed update of the
+// session is the one at the expected reference. The records can be larger than a feed
+// update, so the feed points to the blob of the latest record.
+func (f *File) storeUploadRecord(ctx context.Context, uploadId string, rec *uploadRecord, expected []byte, podPassword string) error {
+	data, err := json.Marshal(rec)
+	if err != nil { // skipcq: TCV-001
+		return err
+	}
+	ref, err := f.client.UploadBlob(ctx, data, 0, true, true)
+	if err != nil { // skipcq: TCV-001
+		return err
+	}
+	topic := utils.HashString(uploadSessionPrefix + uploadId)
+	_, err = f.fd.UpdateFeedIf(ctx, topic, f.userAddress, expected, ref, []byte(podPassword))
+	return err
+}
+
+// removeUploadSession marks the feed of an upload session as deleted and drops it from
+// the list
+func (f *File) removeUploadSession(ctx context.Context, uploadId, podPassword string) error {
+	// the feed is not deleted, as that would make the previous update of the session the
+	// latest again
+	topic := utils.HashString(uploadSessionPrefix + uploadId)
+	_, err := f.fd.UpdateFeed(ctx, topic, f.userAddress, []byte(utils.DeletedFeedMagicWord), []byte(podPassword))
+	if err != nil { // skipcq: TCV-001
+		return err
+	}
+	return f.modifyUploadSessionIds(ctx, podPassword, func(ids []string) ([]string, error) {
+		kept := ids[:0]
+		for _, id := range ids {
+			if id != uploadId {
+				kept = append(kept, id)
+			}
+		}
+		return kept, nil
+	})
+}
+
+// loadUploadSessionIds loads the ids of the upload sessions of the pod and the reference
+// of the feed update they are stored in
+func (f *File) loadUploadSessionIds(ctx context.Context, podPassword string) ([]byte, []string, error) {
+	topic := utils.HashString(uploadSessionsFile)
+	ref, data, err := f.fd.GetFeedData(ctx, topic, f.userAddress, []byte(podPassword))
+	if err != nil {
+		if err.Error() != "feed does not exist or was not updated yet" { // skipcq: TCV-001
+			return nil, nil, err
+		}
+	}
+	ids := []string{}
+	if len(data) == 0 || string(data) == utils.DeletedFeedMagicWord {
+		return ref, ids, nil
+	}
+	for _, id := range strings.Split(string(data), "\n") {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ref, ids, nil
+}
+
+// modifyUploadSessionIds applies a change to the latest list of upload sessions and
+// stores it, merging the change with concurrent changes of other writers
+func (f *File) modifyUploadSessionIds(ctx context.Context, podPassword string, change func(ids []string) ([]string, error)) error {
+	topic := utils.HashString(uploadSessionsFile)
+	for retries := 0; ; retries++ {
+		ref, ids, err := f.loadUploadSessionIds(ctx, podPassword)
+		if err != nil { // skipcq: TCV-001
+			return err
+		}
+		ids, err = change(ids)
+		if err != nil { // skipcq: TCV-001
+			return err
+		}
+		sort.Strings(ids)
+		data := []byte(strings.Join(ids, "\n"))
+		if len(ids) == 0 {
+			data = []byte(utils.DeletedFeedMagicWord)
+		}
+		_, err = f.fd.UpdateFeedIf(ctx, topic, f.userAddress, ref, data, []byte(podPassword))
+		if errors.Is(err, feed.ErrConflict) && retries < feed.ConflictRetries {
+			f.logger.Debugf("merging concurrent change of the upload sessions")
+			continue
+		}
+		return err
+	}
+}
@@ -0,0 +1,209 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/account"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/bee/mock"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/file"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/pod"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+	"github.com/plexsysio/taskmanager"
+)
+
+// cutOffReader returns the first n bytes of the content and then fails, like a dropped
+// connection
+type cutOffReader struct {
+	r io.Reader
+}
+
+func (c *cutOffReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	if err == io.EOF {
+		return n, io.ErrClosedPipe
+	}
+	return n, err
+}
+
+func TestUploadSession(t *testing.T) {
+	ctx := context.Background()
+	mockClient := mock.NewMockBeeClient()
+	logger := logging.New(io.Discard, 0)
+	acc := account.New(logger)
+	_, _, err := acc.CreateUserAccount("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pod1AccountInfo, err := acc.CreatePodAccount(1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fd := feed.New(pod1AccountInfo, mockClient, logger)
+	user := acc.GetAddress(1)
+	tm := taskmanager.New(1, 10, time.Second*15, logger)
+	defer func() {
+		_ = tm.Stop(context.Background())
+	}()
+
+	content := make([]byte, 1050)
+	_, err = rand.Read(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blockSize := uint32(100)
+
+	t.Run("resume-after-reconnect", func(t *testing.T) {
+		podPassword, _ := utils.GetRandString(pod.PasswordLength)
+		fileObject := file.NewFile("pod1", mockClient, fd, user, tm, logger)
+		s, err := fileObject.CreateUploadSession(ctx, "file1", int64(len(content)), blockSize, "/dir1", "snappy", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(s.Blocks) != 11 || s.NextOffset() != 0 {
+			t.Fatalf("unexpected new session %+v", s)
+		}
+
+		// the last part goes first, the first part is cut off in its fourth block
+		_, err = fileObject.UploadPart(ctx, s.ID, 800, bytes.NewReader(content[800:]), podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = fileObject.UploadPart(ctx, s.ID, 0, &cutOffReader{r: bytes.NewReader(content[:350])}, podPassword)
+		if !errors.Is(err, io.ErrClosedPipe) {
+			t.Fatalf("expected the cut off part to fail, got %v", err)
+		}
+
+		// another connection sees the stored blocks
+		other := file.NewFile("pod1", mockClient, fd, user, tm, logger)
+		ids, err := other.ListUploadSessions(ctx, podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ids) != 1 || ids[0] != s.ID {
+			t.Fatalf("expected the session to be listed, got %v", ids)
+		}
+		s, err = other.GetUploadSession(ctx, s.ID, podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stored := s.StoredBlocks()
+		if len(stored) != 6 || stored[2] != 2 || stored[3] != 8 {
+			t.Fatalf("expected blocks 0-2 and 8-10 to be stored, got %v", stored)
+		}
+		if s.NextOffset() != 300 || s.Complete() {
+			t.Fatalf("expected to resume at 300, got %d", s.NextOffset())
+		}
+
+		_, err = other.FinishUploadSession(ctx, s.ID, podPassword)
+		if !errors.Is(err, file.ErrUploadIncomplete) {
+			t.Fatalf("expected an incomplete upload, got %v", err)
+		}
+		s, err = other.UploadPart(ctx, s.ID, s.NextOffset(), bytes.NewReader(content[300:800]), podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !s.Complete() {
+			t.Fatalf("expected the upload to be complete, stored %v", s.StoredBlocks())
+		}
+
+		meta, err := other.FinishUploadSession(ctx, s.ID, podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if meta.Size != uint64(len(content)) || meta.Compression != "snappy" || meta.ContentType == "" {
+			t.Fatalf("unexpected meta %+v", meta)
+		}
+		reader, _, err := other.Download(ctx, "/dir1/file1", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer reader.Close()
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, content) {
+			t.Fatal("file content mismatch")
+		}
+
+		// a finished session is gone
+		_, err = other.GetUploadSession(ctx, s.ID, podPassword)
+		if !errors.Is(err, file.ErrUploadSessionNotFound) {
+			t.Fatalf("expected the session to be removed, got %v", err)
+		}
+		ids, err = other.ListUploadSessions(ctx, podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ids) != 0 {
+			t.Fatalf("expected no sessions, got %v", ids)
+		}
+	})
+
+	t.Run("invalid-parts", func(t *testing.T) {
+		podPassword, _ := utils.GetRandString(pod.PasswordLength)
+		fileObject := file.NewFile("pod1", mockClient, fd, user, tm, logger)
+		s, err := fileObject.CreateUploadSession(ctx, "file2", int64(len(content)), blockSize, "/dir1", "", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, part := range []struct {
+			offset     uint64
+			start, end int
+		}{
+			{offset: 50, start: 50, end: 150},      // not at a block boundary
+			{offset: 1100, start: 1000, end: 1050}, // beyond the file
+			{offset: 900, start: 900, end: 1049},   // short last block
+			{offset: 1000, start: 999, end: 1050},  // longer than the file
+		} {
+			_, err = fileObject.UploadPart(ctx, s.ID, part.offset, bytes.NewReader(content[part.start:part.end]), podPassword)
+			if !errors.Is(err, file.ErrInvalidPart) {
+				t.Fatalf("expected an invalid part at %d, got %v", part.offset, err)
+			}
+		}
+		// the whole blocks of a short part are kept
+		s, err = fileObject.GetUploadSession(ctx, s.ID, podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stored := s.StoredBlocks(); len(stored) != 2 || stored[0] != 9 || stored[1] != 10 {
+			t.Fatalf("expected blocks 9 and 10 to be stored, got %v", stored)
+		}
+
+		err = fileObject.AbortUploadSession(ctx, s.ID, podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = fileObject.UploadPart(ctx, s.ID, 0, bytes.NewReader(content[:100]), podPassword)
+		if !errors.Is(err, file.ErrUploadSessionNotFound) {
+			t.Fatalf("expected the aborted session to be gone, got %v", err)
+		}
+		if fileObject.GetFromFileMap("/dir1/file2") != nil {
+			t.Fatal("aborted upload should not create the file")
+		}
+	})
+}
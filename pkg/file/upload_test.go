@@ -25,6 +25,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"testing/iotest"
 	"time"
 
 	"github.com/fairdatasociety/fairOS-dfs/pkg/pod"
@@ -39,6 +40,7 @@ import (
 )
 
 func TestUpload(t *testing.T) {
+	ctx := context.Background()
 	mockClient := mock.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -91,16 +93,16 @@ func TestUpload(t *testing.T) {
 			t.Fatalf("invalid block size in meta")
 		}
 
-		err := fileObject.LoadFileMeta(filePath+"/"+fileName, podPassword)
+		err := fileObject.LoadFileMeta(ctx, filePath+"/"+fileName, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = fileObject.LoadFileMeta(filePath+"/asd"+fileName, podPassword)
+		err = fileObject.LoadFileMeta(ctx, filePath+"/asd"+fileName, podPassword)
 		if err == nil {
 			t.Fatal("local file meta should fail")
 		}
 
-		meat2, err := fileObject.BackupFromFileName(filePath+"/"+fileName, podPassword)
+		meat2, err := fileObject.BackupFromFileName(ctx, filePath+"/"+fileName, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -300,7 +302,7 @@ func TestUpload(t *testing.T) {
 		if meta.BlockSize != blockSize {
 			t.Fatalf("invalid block size in meta")
 		}
-		reader, _, err := fileObject.Download(fp, podPassword)
+		reader, _, err := fileObject.Download(ctx, fp, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -316,9 +318,45 @@ func TestUpload(t *testing.T) {
 			t.Fatal("meta2 should be nil")
 		}
 	})
+
+	t.Run("upload-with-short-reads", func(t *testing.T) {
+		podPassword, _ := utils.GetRandString(pod.PasswordLength)
+		content := make([]byte, 100)
+		_, err := rand.Read(content)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fileObject := file.NewFile("pod1", mockClient, fd, user, tm, logger)
+
+		// the reader returns a byte at a time, the blocks are full all the same
+		err = fileObject.Upload(ctx, iotest.OneByteReader(bytes.NewReader(content)), "file1", int64(len(content)), 30, "/short", "", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stats, err := fileObject.GetStats(ctx, "pod1", "/short/file1", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(stats.Blocks) != 4 || stats.Blocks[0].Size != "30" || stats.Blocks[3].Size != "10" {
+			t.Fatalf("unexpected blocks %+v", stats.Blocks)
+		}
+		reader, _, err := fileObject.Download(ctx, "/short/file1", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer reader.Close()
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, content) {
+			t.Fatal("content mismatch")
+		}
+	})
 }
 
 func uploadFile(t *testing.T, fileObject *file.File, filePath, fileName, compression, podPassword string, fileSize int64, blockSize uint32) ([]byte, error) {
+	ctx := context.Background()
 	// create a temp file
 	fd, err := os.CreateTemp("", fileName)
 	if err != nil {
@@ -350,5 +388,5 @@ func uploadFile(t *testing.T, fileObject *file.File, filePath, fileName, compres
 	}
 
 	// upload  the temp file
-	return content, fileObject.Upload(f1, fileName, fileSize, blockSize, filePath, compression, podPassword)
+	return content, fileObject.Upload(ctx, f1, fileName, fileSize, blockSize, filePath, compression, podPassword)
 }
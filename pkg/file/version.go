@@ -0,0 +1,499 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+)
+
+const (
+	versionPolicyFile  = "version_policy"
+	fileVersionsPrefix = "file_versions"
+
+	// diffBufferSize is the number of bytes of both versions that are compared at once
+	diffBufferSize = 64 * 1024
+)
+
+var (
+	// ErrVersionNotFound is returned for a version of a file that does not exist or is
+	// no longer retained
+	ErrVersionNotFound = errors.New("file version not found")
+)
+
+// VersionPolicy tells if the previous versions of the files of a pod are kept, and for
+// how long. With neither KeepVersions nor KeepDays set, all the versions are kept.
+type VersionPolicy struct {
+	Enabled bool `json:"enabled"`
+	// KeepVersions keeps the given number of the newest versions of a file
+	KeepVersions int `json:"keepVersions,omitempty"`
+	// KeepDays keeps the versions that were replaced less than the given number of days ago
+	KeepDays int `json:"keepDays,omitempty"`
+}
+
+// retained returns the versions the policy keeps at the given unix time, oldest first
+func (p *VersionPolicy) retained(versions []FileVersion, now int64) []FileVersion {
+	if p.KeepVersions <= 0 && p.KeepDays <= 0 {
+		return versions
+	}
+	cutoff := now - int64(p.KeepDays)*24*60*60
+	kept := []FileVersion{}
+	for i, v := range versions {
+		newest := p.KeepVersions > 0 && len(versions)-i <= p.KeepVersions
+		recent := p.KeepDays > 0 && v.ReplacedTime > cutoff
+		if newest || recent {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
+// FileVersion is a previous content of a file
+type FileVersion struct {
+	Version           int    `json:"version"`
+	InodeAddress      []byte `json:"fileInodeReference"`
+	Size              uint64 `json:"fileSize"`
+	BlockSize         uint32 `json:"blockSize"`
+	ContentType       string `json:"contentType"`
+	Compression       string `json:"compression"`
+	ModificationTime  int64  `json:"modificationTime"`
+	BlockTreeChecksum string `json:"blockTreeChecksum,omitempty"`
+	Checksum          string `json:"checksum,omitempty"`
+	// ReplacedTime is when the version stopped being the content of the file
+	ReplacedTime int64 `json:"replacedTime"`
+}
+
+// versionList is the list of the versions of a file, which is stored as a blob as it can
+// be larger than a feed update
+type versionList struct {
+	// Next is the number of the next version, numbers of pruned versions are not reused
+	Next     int           `json:"next"`
+	Versions []FileVersion `json:"versions"`
+}
+
+// ByteRange is a range of bytes of a file
+type ByteRange struct {
+	Offset uint64 `json:"offset"`
+	Length uint64 `json:"length"`
+}
+
+// VersionDiff lists the bytes that differ between two versions of a file. A file that
+// grew or shrank differs in all the bytes beyond the end of the shorter version.
+type VersionDiff struct {
+	From     int         `json:"from"`
+	To       int         `json:"to"`
+	FromSize uint64      `json:"fromSize"`
+	ToSize   uint64      `json:"toSize"`
+	Changed  []ByteRange `json:"changed"`
+}
+
+// SetVersionPolicy stores the version policy of the pod. The policy applies to the files
+// that change from now on; versions that are already kept are pruned by the policy when
+// the next version of their file is kept.
+func (f *File) SetVersionPolicy(ctx context.Context, policy VersionPolicy, podPassword string) error {
+	data, err := json.Marshal(policy)
+	if err != nil { // skipcq: TCV-001
+		return err
+	}
+	topic := utils.HashString(versionPolicyFile)
+	_, err = f.fd.UpdateFeed(ctx, topic, f.userAddress, data, []byte(podPassword))
+	if err != nil {
+		return err
+	}
+	f.policyMu.Lock()
+	defer f.policyMu.Unlock()
+	f.versionPolicy = &policy
+	return nil
+}
+
+// GetVersionPolicy returns the version policy of the pod. Versioning is off in a pod
+// whose policy was never set.
+func (f *File) GetVersionPolicy(ctx context.Context, podPassword string) (*VersionPolicy, error) {
+	f.policyMu.Lock()
+	defer f.policyMu.Unlock()
+	if f.versionPolicy != nil {
+		policy := *f.versionPolicy
+		return &policy, nil
+	}
+
+	policy := VersionPolicy{}
+	topic := utils.HashString(versionPolicyFile)
+	_, data, err := f.fd.GetFeedData(ctx, topic, f.userAddress, []byte(podPassword))
+	if err != nil {
+		if err.Error() != "feed does not exist or was not updated yet" { // skipcq: TCV-001
+			return nil, err
+		}
+	} else {
+		err = json.Unmarshal(data, &policy)
+		if err != nil { // skipcq: TCV-001
+			return nil, err
+		}
+	}
+	f.versionPolicy = &policy
+	return &policy, nil
+}
+
+// ListVersions lists the previous versions of a file that are kept, oldest first
+func (f *File) ListVersions(ctx context.Context, podFileWithPath, podPassword string) ([]FileVersion, error) {
+	totalFilePath := utils.CombinePathAndFile(podFileWithPath, "")
+	if !f.IsFileAlreadyPresent(totalFilePath) {
+		return nil, ErrFileNotPresent
+	}
+	_, list, err := f.loadVersions(ctx, totalFilePath, podPassword)
+	if err != nil {
+		return nil, err
+	}
+	return list.Versions, nil
+}
+
+// DownloadVersion creates a Reader for a version of a file. Version 0 is the current
+// content of the file.
+func (f *File) DownloadVersion(ctx context.Context, podFileWithPath string, version int, podPassword string) (io.ReadCloser, uint64, error) {
+	meta, err := f.versionMeta(ctx, podFileWithPath, version, podPassword)
+	if err != nil {
+		return nil, 0, err
+	}
+	reader, err := f.openInode(ctx, meta)
+	if err != nil { // skipcq: TCV-001
+		return nil, 0, err
+	}
+	return reader, meta.Size, nil
+}
+
+// RestoreVersion makes a version of a file its current content. The content it replaces
+// is kept as a new version if versioning is on.
+func (f *File) RestoreVersion(ctx context.Context, podFileWithPath string, version int, podPassword string) (*MetaData, error) {
+	if version == 0 {
+		return nil, ErrVersionNotFound
+	}
+	restored, err := f.versionMeta(ctx, podFileWithPath, version, podPassword)
+	if err != nil {
+		return nil, err
+	}
+	totalFilePath := utils.CombinePathAndFile(podFileWithPath, "")
+	current := f.GetFromFileMap(totalFilePath)
+	if current == nil { // skipcq: TCV-001
+		return nil, ErrFileNotFound
+	}
+
+	meta := *current
+	meta.InodeAddress = restored.InodeAddress
+	meta.Size = restored.Size
+	meta.BlockSize = restored.BlockSize
+	meta.ContentType = restored.ContentType
+	meta.Compression = restored.Compression
+	meta.BlockTreeChecksum = restored.BlockTreeChecksum
+	meta.Checksum = restored.Checksum
+	meta.ModificationTime = time.Now().Unix()
+	err = f.handleMeta(ctx, &meta, podPassword)
+	if err != nil {
+		return nil, err
+	}
+	f.AddToFileMap(totalFilePath, &meta)
+	return &meta, nil
+}
+
+// DiffVersions compares two versions of a file byte by byte. Version 0 is the current
+// content of the file.
+func (f *File) DiffVersions(ctx context.Context, podFileWithPath string, from, to int, podPassword string) (*VersionDiff, error) {
+	fromMeta, err := f.versionMeta(ctx, podFileWithPath, from, podPassword)
+	if err != nil {
+		return nil, err
+	}
+	toMeta, err := f.versionMeta(ctx, podFileWithPath, to, podPassword)
+	if err != nil {
+		return nil, err
+	}
+	fromReader, err := f.openInode(ctx, fromMeta)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	defer fromReader.Close()
+	toReader, err := f.openInode(ctx, toMeta)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	defer toReader.Close()
+
+	diff := &VersionDiff{
+		From:     from,
+		To:       to,
+		FromSize: fromMeta.Size,
+		ToSize:   toMeta.Size,
+		Changed:  []ByteRange{},
+	}
+	change := func(offset, length uint64) {
+		last := len(diff.Changed) - 1
+		if last >= 0 && diff.Changed[last].Offset+diff.Changed[last].Length == offset {
+			diff.Changed[last].Length += length
+			return
+		}
+		diff.Changed = append(diff.Changed, ByteRange{Offset: offset, Length: length})
+	}
+
+	common := fromMeta.Size
+	if toMeta.Size < common {
+		common = toMeta.Size
+	}
+	fromBuf := make([]byte, diffBufferSize)
+	toBuf := make([]byte, diffBufferSize)
+	for offset := uint64(0); offset < common; {
+		n := uint64(diffBufferSize)
+		if common-offset < n {
+			n = common - offset
+		}
+		_, err = io.ReadFull(fromReader, fromBuf[:n])
+		if err != nil {
+			return nil, err
+		}
+		_, err = io.ReadFull(toReader, toBuf[:n])
+		if err != nil {
+			return nil, err
+		}
+		for i := uint64(0); i < n; i++ {
+			if fromBuf[i] != toBuf[i] {
+				change(offset+i, 1)
+			}
+		}
+		offset += n
+	}
+	if fromMeta.Size != toMeta.Size {
+		longest := fromMeta.Size
+		if toMeta.Size > longest {
+			longest = toMeta.Size
+		}
+		change(common, longest-common)
+	}
+	return diff, nil
+}
+
+// versionMeta returns the metadata of a version of a file, with the current metadata
+// for version 0
+func (f *File) versionMeta(ctx context.Context, podFileWithPath string, version int, podPassword string) (*MetaData, error) {
+	totalFilePath := utils.CombinePathAndFile(podFileWithPath, "")
+	current := f.GetFromFileMap(totalFilePath)
+	if current == nil {
+		return nil, ErrFileNotPresent
+	}
+	if version == 0 {
+		return current, nil
+	}
+	_, list, err := f.loadVersions(ctx, totalFilePath, podPassword)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range list.Versions {
+		if v.Version == version {
+			return &MetaData{
+				Version:           current.Version,
+				Path:              current.Path,
+				Name:              current.Name,
+				Size:              v.Size,
+				BlockSize:         v.BlockSize,
+				ContentType:       v.ContentType,
+				Compression:       v.Compression,
+				ModificationTime:  v.ModificationTime,
+				InodeAddress:      v.InodeAddress,
+				BlockTreeChecksum: v.BlockTreeChecksum,
+				Checksum:          v.Checksum,
+			}, nil
+		}
+	}
+	return nil, ErrVersionNotFound
+}
+
+// openInode creates a Reader for the content of the inode of the metadata
+func (f *File) openInode(ctx context.Context, meta *MetaData) (*Reader, error) {
+	fileInodeBytes, respCode, err := f.client.DownloadBlob(ctx, meta.InodeAddress)
+	if err != nil {
+		return nil, err
+	}
+	if respCode != http.StatusOK { // skipcq: TCV-001
+		return nil, fmt.Errorf("could not download inode %x", meta.InodeAddress)
+	}
+	var fileInode INode
+	err = json.Unmarshal(fileInodeBytes, &fileInode)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	err = verifyInode(meta, &fileInode)
+	if err != nil {
+		return nil, err
+	}
+	return NewReader(ctx, fileInode, f.client, meta.Size, meta.BlockSize, meta.Compression, false), nil
+}
+
+// recordVersion keeps the replaced metadata of a file as a version, if versioning is on,
+// and prunes the versions the policy no longer keeps
+func (f *File) recordVersion(ctx context.Context, replaced *MetaData, podPassword string) error {
+	policy, err := f.GetVersionPolicy(ctx, podPassword)
+	if err != nil {
+		return err
+	}
+	if !policy.Enabled {
+		return nil
+	}
+	totalPath := utils.CombinePathAndFile(replaced.Path, replaced.Name)
+	now := time.Now().Unix()
+	return f.modifyVersions(ctx, totalPath, podPassword, func(list *versionList) {
+		list.Next++
+		list.Versions = append(list.Versions, FileVersion{
+			Version:           list.Next,
+			InodeAddress:      replaced.InodeAddress,
+			Size:              replaced.Size,
+			BlockSize:         replaced.BlockSize,
+			ContentType:       replaced.ContentType,
+			Compression:       replaced.Compression,
+			ModificationTime:  replaced.ModificationTime,
+			BlockTreeChecksum: replaced.BlockTreeChecksum,
+			Checksum:          replaced.Checksum,
+			ReplacedTime:      now,
+		})
+		list.Versions = policy.retained(list.Versions, now)
+	})
+}
+
+// MoveVersions moves the versions of a file that is renamed, or whose directory is
+// renamed, to its new path
+func (f *File) MoveVersions(ctx context.Context, fromPath, toPath, podPassword string) error {
+	ref, list, err := f.loadVersions(ctx, fromPath, podPassword)
+	if err != nil || ref == nil {
+		return err
+	}
+	err = f.modifyVersions(ctx, toPath, podPassword, func(l *versionList) {
+		*l = *list
+	})
+	if err != nil { // skipcq: TCV-001
+		return err
+	}
+	return f.removeVersions(ctx, fromPath, podPassword)
+}
+
+// removeVersions drops the versions of a file. Their blocks are left to the garbage
+// collection of the pod.
+func (f *File) removeVersions(ctx context.Context, totalPath, podPassword string) error {
+	ref, _, err := f.loadVersions(ctx, totalPath, podPassword)
+	if err != nil || ref == nil {
+		return err
+	}
+	topic := utils.HashString(fileVersionsPrefix + totalPath)
+	_, err = f.fd.UpdateFeed(ctx, topic, f.userAddress, []byte(utils.DeletedFeedMagicWord), []byte(podPassword))
+	return err
+}
+
+// versionReferences marks the version list of a file and the inodes and blocks of its
+// versions
+func (f *File) versionReferences(ctx context.Context, totalPath, podPassword string, mark func(ref []byte)) error {
+	topic := utils.HashString(fileVersionsPrefix + totalPath)
+	_, data, err := f.fd.GetFeedData(ctx, topic, f.userAddress, []byte(podPassword))
+	if err != nil {
+		if err.Error() == "feed does not exist or was not updated yet" {
+			return nil
+		}
+		return err // skipcq: TCV-001
+	}
+	if string(data) == utils.DeletedFeedMagicWord {
+		return nil
+	}
+	mark(data)
+	list, err := f.downloadVersions(ctx, data)
+	if err != nil { // skipcq: TCV-001
+		return err
+	}
+	for _, v := range list.Versions {
+		err = f.inodeReferences(ctx, v.InodeAddress, mark)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadVersions loads the version list of a file and the reference of the feed update it
+// is stored in
+func (f *File) loadVersions(ctx context.Context, totalPath, podPassword string) ([]byte, *versionList, error) {
+	topic := utils.HashString(fileVersionsPrefix + totalPath)
+	feedRef, data, err := f.fd.GetFeedData(ctx, topic, f.userAddress, []byte(podPassword))
+	if err != nil {
+		if err.Error() != "feed does not exist or was not updated yet" { // skipcq: TCV-001
+			return nil, nil, err
+		}
+		return nil, &versionList{Versions: []FileVersion{}}, nil
+	}
+	if string(data) == utils.DeletedFeedMagicWord {
+		return feedRef, &versionList{Versions: []FileVersion{}}, nil
+	}
+	list, err := f.downloadVersions(ctx, data)
+	if err != nil { // skipcq: TCV-001
+		return nil, nil, err
+	}
+	return feedRef, list, nil
+}
+
+func (f *File) downloadVersions(ctx context.Context, ref []byte) (*versionList, error) {
+	data, respCode, err := f.client.DownloadBlob(ctx, ref)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	if respCode != http.StatusOK { // skipcq: TCV-001
+		return nil, fmt.Errorf("could not download file versions %x", ref)
+	}
+	list := &versionList{}
+	err = json.Unmarshal(data, list)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	if list.Versions == nil {
+		list.Versions = []FileVersion{}
+	}
+	return list, nil
+}
+
+// modifyVersions applies a change to the latest version list of a file and stores it,
+// merging the change with concurrent changes of other writers
+func (f *File) modifyVersions(ctx context.Context, totalPath, podPassword string, change func(list *versionList)) error {
+	topic := utils.HashString(fileVersionsPrefix + totalPath)
+	for retries := 0; ; retries++ {
+		feedRef, list, err := f.loadVersions(ctx, totalPath, podPassword)
+		if err != nil { // skipcq: TCV-001
+			return err
+		}
+		change(list)
+		data, err := json.Marshal(list)
+		if err != nil { // skipcq: TCV-001
+			return err
+		}
+		ref, err := f.client.UploadBlob(ctx, data, 0, true, true)
+		if err != nil { // skipcq: TCV-001
+			return err
+		}
+		_, err = f.fd.UpdateFeedIf(ctx, topic, f.userAddress, feedRef, ref, []byte(podPassword))
+		if errors.Is(err, feed.ErrConflict) && retries < feed.ConflictRetries {
+			f.logger.Debugf("merging concurrent change of the versions of %s", totalPath)
+			continue
+		}
+		return err
+	}
+}
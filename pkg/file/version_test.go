@@ -0,0 +1,253 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/account"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/bee/mock"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/file"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/pod"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+	"github.com/plexsysio/taskmanager"
+)
+
+func TestVersions(t *testing.T) {
+	ctx := context.Background()
+	mockClient := mock.NewMockBeeClient()
+	logger := logging.New(io.Discard, 0)
+	acc := account.New(logger)
+	_, _, err := acc.CreateUserAccount("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tm := taskmanager.New(1, 10, time.Second*15, logger)
+	defer func() {
+		_ = tm.Stop(context.Background())
+	}()
+
+	// every subtest uses a pod of its own
+	podIndex := 0
+	openFile := func(t *testing.T, index int) *file.File {
+		t.Helper()
+		podAccountInfo, err := acc.CreatePodAccount(index, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fd := feed.New(podAccountInfo, mockClient, logger)
+		return file.NewFile("pod1", mockClient, fd, acc.GetAddress(index), tm, logger)
+	}
+	newFile := func(t *testing.T) (*file.File, string) {
+		t.Helper()
+		podIndex++
+		podPassword, _ := utils.GetRandString(pod.PasswordLength)
+		return openFile(t, podIndex), podPassword
+	}
+
+	upload := func(t *testing.T, fileObject *file.File, content []byte, podPassword string) {
+		t.Helper()
+		err := fileObject.Upload(ctx, bytes.NewReader(content), "file1", int64(len(content)), 10, "/dir1", "", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	download := func(t *testing.T, fileObject *file.File, version int, podPassword string) []byte {
+		t.Helper()
+		reader, size, err := fileObject.DownloadVersion(ctx, "/dir1/file1", version, podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer reader.Close()
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if uint64(len(data)) != size {
+			t.Fatalf("expected %d bytes, got %d", size, len(data))
+		}
+		return data
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		fileObject, podPassword := newFile(t)
+		upload(t, fileObject, []byte("first content"), podPassword)
+		upload(t, fileObject, []byte("second content"), podPassword)
+		versions, err := fileObject.ListVersions(ctx, "/dir1/file1", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(versions) != 0 {
+			t.Fatalf("no versions should be kept without a policy, got %d", len(versions))
+		}
+	})
+
+	t.Run("overwrite-writeat-restore", func(t *testing.T) {
+		fileObject, podPassword := newFile(t)
+		err := fileObject.SetVersionPolicy(ctx, file.VersionPolicy{Enabled: true}, podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		first := []byte("the first content of the file")
+		second := []byte("the second content of the file")
+		upload(t, fileObject, first, podPassword)
+		upload(t, fileObject, second, podPassword)
+
+		_, err = fileObject.WriteAt(ctx, "/dir1/file1", podPassword, bytes.NewReader([]byte("THIRD")), 4, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		third := append([]byte{}, second...)
+		copy(third[4:], "THIRD")
+
+		versions, err := fileObject.ListVersions(ctx, "/dir1/file1", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(versions) != 2 || versions[0].Version != 1 || versions[1].Version != 2 {
+			t.Fatalf("expected two versions, got %+v", versions)
+		}
+		if versions[0].Size != uint64(len(first)) || versions[1].Size != uint64(len(second)) {
+			t.Fatalf("unexpected version sizes %+v", versions)
+		}
+		if !bytes.Equal(download(t, fileObject, 1, podPassword), first) ||
+			!bytes.Equal(download(t, fileObject, 2, podPassword), second) ||
+			!bytes.Equal(download(t, fileObject, 0, podPassword), third) {
+			t.Fatal("version content mismatch")
+		}
+
+		diff, err := fileObject.DiffVersions(ctx, "/dir1/file1", 2, 0, podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(diff.Changed) != 1 || diff.Changed[0].Offset != 4 || diff.Changed[0].Length != 5 {
+			t.Fatalf("unexpected diff %+v", diff.Changed)
+		}
+		diff, err = fileObject.DiffVersions(ctx, "/dir1/file1", 1, 2, podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		last := diff.Changed[len(diff.Changed)-1]
+		if last.Offset+last.Length != uint64(len(second)) {
+			t.Fatalf("the grown tail should differ, got %+v", diff.Changed)
+		}
+
+		// the restored version becomes the content, the content it replaces a new version
+		_, err = fileObject.RestoreVersion(ctx, "/dir1/file1", 1, podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(download(t, fileObject, 0, podPassword), first) {
+			t.Fatal("restored content mismatch")
+		}
+		versions, err = fileObject.ListVersions(ctx, "/dir1/file1", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(versions) != 3 || versions[2].Version != 3 || versions[2].Size != uint64(len(third)) {
+			t.Fatalf("expected the replaced content as version 3, got %+v", versions)
+		}
+
+		_, _, err = fileObject.DownloadVersion(ctx, "/dir1/file1", 7, podPassword)
+		if !errors.Is(err, file.ErrVersionNotFound) {
+			t.Fatalf("expected version not found, got %v", err)
+		}
+	})
+
+	t.Run("keep-versions", func(t *testing.T) {
+		fileObject, podPassword := newFile(t)
+		err := fileObject.SetVersionPolicy(ctx, file.VersionPolicy{Enabled: true, KeepVersions: 2}, podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, content := range []string{"one", "two", "three", "four", "five"} {
+			upload(t, fileObject, []byte(content), podPassword)
+		}
+		versions, err := fileObject.ListVersions(ctx, "/dir1/file1", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(versions) != 2 || versions[0].Version != 3 || versions[1].Version != 4 {
+			t.Fatalf("expected versions 3 and 4, got %+v", versions)
+		}
+		if !bytes.Equal(download(t, fileObject, 3, podPassword), []byte("three")) {
+			t.Fatal("version content mismatch")
+		}
+
+		// the policy is read again by a new file object of the pod
+		fileObject = openFile(t, podIndex)
+		policy, err := fileObject.GetVersionPolicy(ctx, podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !policy.Enabled || policy.KeepVersions != 2 {
+			t.Fatalf("unexpected policy %+v", policy)
+		}
+	})
+
+	t.Run("rename-and-rm", func(t *testing.T) {
+		fileObject, podPassword := newFile(t)
+		err := fileObject.SetVersionPolicy(ctx, file.VersionPolicy{Enabled: true}, podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		upload(t, fileObject, []byte("first"), podPassword)
+		upload(t, fileObject, []byte("second"), podPassword)
+
+		_, err = fileObject.RenameFromFileName(ctx, "/dir1/file1", "/dir1/file2", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		versions, err := fileObject.ListVersions(ctx, "/dir1/file2", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(versions) != 1 || versions[0].Size != uint64(len("first")) {
+			t.Fatalf("versions should follow the renamed file, got %+v", versions)
+		}
+
+		err = fileObject.RmFile(ctx, "/dir1/file2", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = fileObject.ListVersions(ctx, "/dir1/file2", podPassword)
+		if !errors.Is(err, file.ErrFileNotPresent) {
+			t.Fatalf("expected file not present, got %v", err)
+		}
+
+		// a new file of the same name starts without versions
+		upload(t, fileObject, []byte("again"), podPassword)
+		_, err = fileObject.RenameFromFileName(ctx, "/dir1/file1", "/dir1/file2", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		versions, err = fileObject.ListVersions(ctx, "/dir1/file2", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(versions) != 0 {
+			t.Fatalf("removed file should leave no versions, got %+v", versions)
+		}
+	})
+}
@@ -3,16 +3,22 @@ package file
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"sync"
+	"net/http"
+	"time"
 
 	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
 )
 
-// WriteAt writes a file from a given offset
-func (f *File) WriteAt(podFileWithPath, podPassword string, update io.Reader, offset uint64, truncate bool) (int, error) {
+// WriteAt writes the update to a file from a given offset, which can be at most the size
+// of the file. Only the blocks that overlap the written range are downloaded and uploaded
+// again, the references of the other blocks are reused. With truncate the file ends where
+// the update ends.
+func (f *File) WriteAt(ctx context.Context, podFileWithPath, podPassword string, update io.Reader, offset uint64, truncate bool) (int, error) {
 	// check file is present
 	totalFilePath := utils.CombinePathAndFile(podFileWithPath, "")
 	if !f.IsFileAlreadyPresent(totalFilePath) {
@@ -20,13 +26,17 @@ func (f *File) WriteAt(podFileWithPath, podPassword string, update io.Reader, of
 	}
 
 	// get file meta
-	meta := f.GetFromFileMap(totalFilePath)
-	if meta == nil { // skipcq: TCV-001
+	current := f.GetFromFileMap(totalFilePath)
+	if current == nil { // skipcq: TCV-001
 		return 0, ErrFileNotFound
 	}
+	meta := *current
+	if offset > meta.Size {
+		return 0, fmt.Errorf("wrong offset")
+	}
 
 	// download file inode (blocks info)
-	fileInodeBytes, _, err := f.getClient().DownloadBlob(meta.InodeAddress)
+	fileInodeBytes, _, err := f.getClient().DownloadBlob(ctx, meta.InodeAddress)
 	if err != nil { // skipcq: TCV-001
 		return 0, err
 	}
@@ -37,229 +47,158 @@ func (f *File) WriteAt(podFileWithPath, podPassword string, update io.Reader, of
 		return 0, err
 	}
 
-	// create file reader
-	fd := NewReader(fileInode, f.getClient(), meta.Size, meta.BlockSize, meta.Compression, false)
-	reader := &bytes.Buffer{}
-	_, err = reader.ReadFrom(fd)
-	if err != nil {
-		return 0, err
-	}
-
-	// prepare updater
-	updater := &bytes.Buffer{}
-	_, err = updater.ReadFrom(update)
-	if err != nil {
-		return 0, err
-	}
-
-	// get file size
-	dataSize := uint64(reader.Len())
-
-	// updater size
-	updaterSize := uint64(updater.Len())
-
-	if offset > dataSize {
-		return 0, fmt.Errorf("wrong offset")
-	}
+	blockSize := uint64(meta.BlockSize)
+	tag := f.LoadFromTagMap(totalFilePath)
 
-	newDataSize := dataSize
-	if truncate {
-		newDataSize = updaterSize
-	}
-	endofst := offset + updaterSize
-	if endofst > dataSize {
-		newDataSize = endofst
+	// the rewritten blocks are uploaded in batches, like in Upload
+	var batch [][]byte
+	var batchIndexes []int
+	var batchBlocks []*BlockInfo
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		f.logger.Infof("Uploading %d updated blocks from block %d", len(batch), batchIndexes[0])
+		addrs, err := f.client.UploadBlobs(ctx, batch, tag, true, true)
+		if err != nil {
+			return err
+		}
+		for i, addr := range addrs {
+			batchBlocks[i].Reference = utils.NewReference(addr)
+			if batchIndexes[i] < len(fileInode.Blocks) {
+				fileInode.Blocks[batchIndexes[i]] = batchBlocks[i]
+			} else {
+				fileInode.Blocks = append(fileInode.Blocks, batchBlocks[i])
+			}
+		}
+		batch, batchIndexes, batchBlocks = nil, nil, nil
+		return nil
 	}
 
-	startingBlock := offset / uint64(meta.BlockSize)
-	readStartPoint := startingBlock * uint64(meta.BlockSize)
-	reader.Next(int(readStartPoint))
-	blockOffset := offset - readStartPoint
-	var totalLength = readStartPoint
-	i := startingBlock
-	errC := make(chan error)
-	doneC := make(chan bool)
-	worker := make(chan bool, noOfParallelWorkers)
-	var wg sync.WaitGroup
+	var written uint64
+	index := int(offset / blockSize)
+	start := offset % blockSize
+	for {
+		// read the part of the update that falls in this block
+		data := make([]byte, blockSize)
+		n, err := io.ReadFull(update, data[start:])
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return 0, err
+		}
+		end := start + uint64(n)
+		written += uint64(n)
+		ended := err != nil
 
-	refMap := map[int]*BlockInfo{}
-	for k, v := range fileInode.Blocks {
-		refMap[k] = v
-	}
-	refMapMu := sync.RWMutex{}
-	var contentBytes []byte
-	wg.Add(1)
+		// a block that only needs truncating is rewritten too
+		if n == 0 && !(truncate && start > 0) {
+			break
+		}
 
-	tag := f.LoadFromTagMap(totalFilePath)
-	go func() {
-		var mainErr error
-		for {
-			if !(totalLength < newDataSize && updater.Len() != 0) {
-				wg.Done()
-				break
-			}
-			if mainErr != nil { // skipcq: TCV-001
-				errC <- mainErr
-				wg.Done()
-				return
-			}
-			data := []byte{}
-			n := 0
-			var err error
-			if totalLength < offset {
-				temp := make([]byte, blockOffset)
-				n, err = reader.Read(temp)
+		// keep the bytes of the block that are not overwritten
+		length := end
+		if index < len(fileInode.Blocks) {
+			existing := fileInode.Blocks[index]
+			if start > 0 || (uint64(existing.Size) > end && !(truncate && ended)) {
+				old, err := f.downloadBlock(ctx, existing, meta.Compression, meta.BlockSize)
 				if err != nil {
-					if err == io.EOF {
-						if totalLength < meta.Size { // skipcq: TCV-001
-							errC <- fmt.Errorf("invalid file length of file data received")
-							return
-						}
-						wg.Done()
-						break
-					}
-					errC <- err // skipcq: TCV-001
-					return
+					return 0, err
 				}
-				data = append(data, temp[:n]...)
-				totalLength += uint64(n)
-			}
-
-			if totalLength >= offset && totalLength < endofst && uint32(len(data)) != meta.BlockSize {
-				temp := make([]byte, meta.BlockSize-uint32(n))
-				n, err = updater.Read(temp)
+				err = verifyBlock(existing, old)
 				if err != nil {
-					if err == io.EOF {
-						if totalLength < meta.Size { // skipcq: TCV-001
-							errC <- fmt.Errorf("invalid file length of file data received")
-							return
-						}
-						wg.Done()
-						break
-					}
-					errC <- err // skipcq: TCV-001
-					return
+					return 0, err
 				}
-				data = append(data, temp[:n]...)
-				totalLength += uint64(n)
-				if reader.Len() > 0 {
-					reader.Next(n)
+				copy(data[:start], old)
+				if uint64(len(old)) > end && !(truncate && ended) {
+					copy(data[end:], old[end:])
+					length = uint64(len(old))
 				}
 			}
+		}
+		data = data[:length]
 
-			if uint32(len(data)) != meta.BlockSize && !truncate {
-				if totalLength < dataSize && uint32(len(data)) != meta.BlockSize {
-					size := meta.BlockSize
-					if dataSize < uint64(meta.BlockSize) {
-						size = uint32(dataSize)
-					}
+		// determine the content type from the first 512 bytes of the file
+		if index == 0 && len(data) >= 512 {
+			meta.ContentType = f.getContentType(bufio.NewReader(bytes.NewReader(data[:512])))
+		}
 
-					temp := make([]byte, size-uint32(len(data)))
-					n, err = reader.Read(temp)
-					if err != nil {
-						if err == io.EOF {
-							if totalLength < meta.Size { // skipcq: TCV-001
-								errC <- fmt.Errorf("invalid file length of file data received")
-								return
-							}
-							wg.Done()
-							break
-						}
-						errC <- err // skipcq: TCV-001
-						return
-					}
-					data = append(data, temp...)
-					totalLength += uint64(n)
-				}
-			}
-			// determine the content type from the first 512 bytes of the file
-			if len(contentBytes) < 512 {
-				contentBytes = append(contentBytes, data[:n]...)
-				if len(contentBytes) >= 512 { // skipcq: TCV-001
-					cBytes := bytes.NewReader(contentBytes[:512])
-					cReader := bufio.NewReader(cBytes)
-					meta.ContentType = f.getContentType(cReader)
-				}
+		block, uploadData, err := compressBlock(data, meta.Compression, meta.BlockSize)
+		if err != nil { // skipcq: TCV-001
+			return 0, err
+		}
+		batch = append(batch, uploadData)
+		batchIndexes = append(batchIndexes, index)
+		batchBlocks = append(batchBlocks, block)
+		if len(batch) >= noOfParallelWorkers {
+			err = flush()
+			if err != nil {
+				return 0, err
 			}
-
-			wg.Add(1)
-			worker <- true
-
-			go func(counter, size int) {
-				defer func() {
-					<-worker
-					wg.Done()
-					if mainErr != nil { // skipcq: TCV-001
-						return
-					}
-				}()
-				f.logger.Infof("Uploading %d block", counter)
-				// Compress the data
-				uploadData := data
-				if meta.Compression != "" {
-					uploadData, err = Compress(data, meta.Compression, meta.BlockSize)
-					if err != nil { // skipcq: TCV-001
-						mainErr = err
-						return
-					}
-				}
-
-				addr, uploadErr := f.client.UploadBlob(uploadData, tag, true, true)
-				if uploadErr != nil {
-					mainErr = uploadErr
-					return
-				}
-
-				fileBlock := &BlockInfo{
-					Size:           uint32(size),
-					CompressedSize: uint32(len(uploadData)),
-					Reference:      utils.NewReference(addr),
-				}
-
-				refMapMu.Lock()
-				defer refMapMu.Unlock()
-				refMap[counter] = fileBlock
-			}(int(i), n)
-			i++
 		}
-	}()
-
-	go func() {
-		wg.Wait()
-		close(doneC)
-	}()
-	select {
-	case <-doneC:
-		break
-	case err := <-errC: // skipcq: TCV-001
-		close(errC)
+		if ended {
+			break
+		}
+		index++
+		start = 0
+	}
+	err = flush()
+	if err != nil {
 		return 0, err
 	}
 
-	// copy the block references to the fileInode
-	fileInode.Blocks = []*BlockInfo{}
-	for j := 0; j < len(refMap); j++ {
-		fileInode.Blocks = append(fileInode.Blocks, refMap[j])
-		if truncate && i == uint64(j) {
-			break
+	endOfWrite := offset + written
+	newSize := meta.Size
+	if truncate || endOfWrite > newSize {
+		newSize = endOfWrite
+	}
+	if truncate {
+		noOfBlocks := int((newSize + blockSize - 1) / blockSize)
+		if noOfBlocks < len(fileInode.Blocks) {
+			fileInode.Blocks = fileInode.Blocks[:noOfBlocks]
 		}
 	}
+
+	// the blocks that were not written are read again to hash the whole content
+	checksum, _, err := f.contentChecksum(ctx, fileInode.Blocks, meta.Compression, meta.BlockSize)
+	if err != nil {
+		return 0, err
+	}
+
 	fileInodeData, err := json.Marshal(fileInode)
 	if err != nil { // skipcq: TCV-001
 		return 0, err
 	}
 
-	addr, err := f.client.UploadBlob(fileInodeData, 0, true, true)
+	addr, err := f.client.UploadBlob(ctx, fileInodeData, 0, true, true)
 	if err != nil { // skipcq: TCV-001
 		return 0, err
 	}
+
 	meta.InodeAddress = addr
-	meta.Size = newDataSize
+	meta.Checksum = checksum
+	meta.Size = newSize
+	// the block tree checksum stays empty while the file has blocks written before checksums
+	meta.BlockTreeChecksum = blockTreeChecksum(fileInode.Blocks)
+	if meta.BlockTreeChecksum != "" {
+		meta.Version = MetaVersion
+	}
+	meta.ModificationTime = time.Now().Unix()
 
-	err = f.handleMeta(meta, podPassword)
+	err = f.handleMeta(ctx, &meta, podPassword)
 	if err != nil { // skipcq: TCV-001
 		return 0, err
 	}
-	f.AddToFileMap(utils.CombinePathAndFile(meta.Path, meta.Name), meta)
-	return int(updaterSize), nil
+	f.AddToFileMap(utils.CombinePathAndFile(meta.Path, meta.Name), &meta)
+	return int(written), nil
+}
+
+// downloadBlock downloads and decompresses a block of a file
+func (f *File) downloadBlock(ctx context.Context, block *BlockInfo, compression string, blockSize uint32) ([]byte, error) {
+	data, respCode, err := f.getClient().DownloadBlob(ctx, block.Reference.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if respCode != http.StatusOK { // skipcq: TCV-001
+		return nil, errors.New("error downloading block")
+	}
+	return decompressBlock(block, data, compression, blockSize)
 }
@@ -27,6 +27,7 @@ func TestMain(m *testing.M) {
 }
 
 func TestWriteAt(t *testing.T) {
+	ctx := context.Background()
 	mockClient := mock.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -60,7 +61,7 @@ func TestWriteAt(t *testing.T) {
 		update := []byte("123")
 		rewrite := &bytes.Buffer{}
 		rewrite.Write(update)
-		_, err = fileObject.WriteAt(fp, podPassword, rewrite, offset, false)
+		_, err = fileObject.WriteAt(ctx, fp, podPassword, rewrite, offset, false)
 		if !errors.Is(file.ErrFileNotPresent, err) {
 			t.Fatal("file should not be present")
 		}
@@ -99,7 +100,7 @@ func TestWriteAt(t *testing.T) {
 		if meta.BlockSize != blockSize {
 			t.Fatalf("invalid block size in meta")
 		}
-		reader, _, err := fileObject.Download(fp, podPassword)
+		reader, _, err := fileObject.Download(ctx, fp, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -109,7 +110,7 @@ func TestWriteAt(t *testing.T) {
 			t.Fatal(err)
 		}
 		reader.Close()
-		reader2, _, err := fileObject.Download(fp, podPassword)
+		reader2, _, err := fileObject.Download(ctx, fp, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -118,7 +119,7 @@ func TestWriteAt(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		reader, _, err = fileObject.Download(fp, podPassword)
+		reader, _, err = fileObject.Download(ctx, fp, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -132,11 +133,11 @@ func TestWriteAt(t *testing.T) {
 		update := []byte("123")
 		rewrite := &bytes.Buffer{}
 		rewrite.Write(update)
-		_, err = fileObject.WriteAt(fp, podPassword, rewrite, offset, false)
+		_, err = fileObject.WriteAt(ctx, fp, podPassword, rewrite, offset, false)
 		if err != nil {
 			t.Fatal(err)
 		}
-		reader, _, err = fileObject.Download(fp, podPassword)
+		reader, _, err = fileObject.Download(ctx, fp, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -197,7 +198,7 @@ func TestWriteAt(t *testing.T) {
 		if meta.BlockSize != blockSize {
 			t.Fatalf("invalid block size in meta")
 		}
-		reader, _, err := fileObject.Download(fp, podPassword)
+		reader, _, err := fileObject.Download(ctx, fp, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -207,7 +208,7 @@ func TestWriteAt(t *testing.T) {
 			t.Fatal(err)
 		}
 		reader.Close()
-		reader2, _, err := fileObject.Download(fp, podPassword)
+		reader2, _, err := fileObject.Download(ctx, fp, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -216,7 +217,7 @@ func TestWriteAt(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		reader, _, err = fileObject.Download(fp, podPassword)
+		reader, _, err = fileObject.Download(ctx, fp, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -230,11 +231,11 @@ func TestWriteAt(t *testing.T) {
 		update := []byte("abcdefghijklmnop")
 		rewrite := &bytes.Buffer{}
 		rewrite.Write(update)
-		_, err = fileObject.WriteAt(fp, podPassword, rewrite, offset, false)
+		_, err = fileObject.WriteAt(ctx, fp, podPassword, rewrite, offset, false)
 		if err != nil {
 			t.Fatal(err)
 		}
-		reader, _, err = fileObject.Download(fp, podPassword)
+		reader, _, err = fileObject.Download(ctx, fp, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -257,11 +258,11 @@ func TestWriteAt(t *testing.T) {
 		offset = 0
 		rewrite = &bytes.Buffer{}
 		rewrite.Write(update)
-		_, err = fileObject.WriteAt(fp, podPassword, rewrite, offset, false)
+		_, err = fileObject.WriteAt(ctx, fp, podPassword, rewrite, offset, false)
 		if err != nil {
 			t.Fatal(err)
 		}
-		reader, _, err = fileObject.Download(fp, podPassword)
+		reader, _, err = fileObject.Download(ctx, fp, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -323,7 +324,7 @@ func TestWriteAt(t *testing.T) {
 			t.Fatalf("invalid block size in meta")
 		}
 
-		reader, _, err := fileObject.Download(fp, podPassword)
+		reader, _, err := fileObject.Download(ctx, fp, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -336,12 +337,12 @@ func TestWriteAt(t *testing.T) {
 		update := []byte("abcdefg 12345")
 		rewrite := &bytes.Buffer{}
 		rewrite.Write(update)
-		_, err = fileObject.WriteAt(fp, podPassword, rewrite, offset, true)
+		_, err = fileObject.WriteAt(ctx, fp, podPassword, rewrite, offset, true)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		reader, _, err = fileObject.Download(fp, podPassword)
+		reader, _, err = fileObject.Download(ctx, fp, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -396,7 +397,7 @@ func TestWriteAt(t *testing.T) {
 			t.Fatalf("invalid block size in meta")
 		}
 
-		err = fileObject.LoadFileMeta(filePath+"/"+fileName, podPassword)
+		err = fileObject.LoadFileMeta(ctx, filePath+"/"+fileName, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -410,14 +411,14 @@ func TestWriteAt(t *testing.T) {
 			t.Fatal(err)
 		}
 		r := bytes.NewReader(content)
-		n, err := fileObject.WriteAt(fp, podPassword, r, uint64(offset), false)
+		n, err := fileObject.WriteAt(ctx, fp, podPassword, r, uint64(offset), false)
 		if n != offset {
 			t.Fatalf("Failed to update %d bytes", offset-n)
 		}
 		if err != nil {
 			t.Fatal(err)
 		}
-		reader, _, err := fileObject.Download(fp, podPassword)
+		reader, _, err := fileObject.Download(ctx, fp, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -485,14 +486,14 @@ func TestWriteAt(t *testing.T) {
 			t.Fatal(err)
 		}
 		r := bytes.NewReader(content)
-		n, err := fileObject.WriteAt(fp, podPassword, r, uint64(offset), false)
+		n, err := fileObject.WriteAt(ctx, fp, podPassword, r, uint64(offset), false)
 		if n != offset {
 			t.Fatalf("Failed to update %d bytes", offset-n)
 		}
 		if err != nil {
 			t.Fatal(err)
 		}
-		reader, _, err := fileObject.Download(fp, podPassword)
+		reader, _, err := fileObject.Download(ctx, fp, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -530,7 +531,7 @@ func TestWriteAt(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = fileObject.LoadFileMeta(filePath+"/"+fileName, podPassword)
+		err = fileObject.LoadFileMeta(ctx, filePath+"/"+fileName, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -565,11 +566,11 @@ func TestWriteAt(t *testing.T) {
 			t.Fatal(err)
 		}
 		r := bytes.NewReader(content)
-		_, err = fileObject.WriteAt(fp, podPassword, r, uint64(offset), false)
+		_, err = fileObject.WriteAt(ctx, fp, podPassword, r, uint64(offset), false)
 		if err != nil {
 			t.Fatal(err)
 		}
-		reader, n1, err := fileObject.Download(fp, podPassword)
+		reader, n1, err := fileObject.Download(ctx, fp, podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -597,9 +598,116 @@ func TestWriteAt(t *testing.T) {
 			t.Fatal("meta2 should be nil")
 		}
 	})
+
+	t.Run("update-reuses-untouched-blocks", func(t *testing.T) {
+		fileObject := file.NewFile("pod1", mockClient, fd, user, tm, logger)
+		dt, err := uploadFile(t, fileObject, "/", "blocks", "snappy", podPassword, 100, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		before := blockReferences(t, fileObject, "/blocks", podPassword)
+
+		// the update spans the end of the fifth and the start of the sixth block
+		n, err := fileObject.WriteAt(ctx, "/blocks", podPassword, bytes.NewReader([]byte("12345")), 47, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 5 {
+			t.Fatalf("expected 5 bytes written, got %d", n)
+		}
+		after := blockReferences(t, fileObject, "/blocks", podPassword)
+		if len(after) != 10 {
+			t.Fatalf("expected 10 blocks, got %d", len(after))
+		}
+		for i := range after {
+			changed := !bytes.Equal(before[i], after[i])
+			if changed != (i == 4 || i == 5) {
+				t.Fatalf("block %d changed: %v", i, changed)
+			}
+		}
+		copy(dt[47:], "12345")
+		assertContent(t, fileObject, "/blocks", podPassword, dt)
+	})
+
+	t.Run("truncate-and-extend", func(t *testing.T) {
+		fileObject := file.NewFile("pod1", mockClient, fd, user, tm, logger)
+		dt, err := uploadFile(t, fileObject, "/", "truncate", "", podPassword, 100, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		before := blockReferences(t, fileObject, "/truncate", podPassword)
+
+		// the file ends after the update in the middle of the fourth block
+		_, err = fileObject.WriteAt(ctx, "/truncate", podPassword, bytes.NewReader([]byte("xy")), 33, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := append(append([]byte{}, dt[:33]...), "xy"...)
+		assertContent(t, fileObject, "/truncate", podPassword, expected)
+		after := blockReferences(t, fileObject, "/truncate", podPassword)
+		if len(after) != 4 || !bytes.Equal(before[0], after[0]) || !bytes.Equal(before[2], after[2]) {
+			t.Fatal("truncate should keep the first blocks and drop the tail")
+		}
+
+		// a truncate without data cuts the file at the offset
+		_, err = fileObject.WriteAt(ctx, "/truncate", podPassword, bytes.NewReader(nil), 20, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected = expected[:20]
+		assertContent(t, fileObject, "/truncate", podPassword, expected)
+
+		// writing at the end extends the file
+		extension := bytes.Repeat([]byte("z"), 25)
+		_, err = fileObject.WriteAt(ctx, "/truncate", podPassword, bytes.NewReader(extension), 20, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected = append(expected, extension...)
+		assertContent(t, fileObject, "/truncate", podPassword, expected)
+		if meta := fileObject.GetFromFileMap("/truncate"); meta.Size != 45 {
+			t.Fatalf("expected size 45, got %d", meta.Size)
+		}
+
+		_, err = fileObject.WriteAt(ctx, "/truncate", podPassword, bytes.NewReader([]byte("a")), 46, false)
+		if err == nil {
+			t.Fatal("offset beyond the end of the file should fail")
+		}
+	})
+}
+
+// blockReferences returns the block references of a file, in order
+func blockReferences(t *testing.T, fileObject *file.File, podFile, podPassword string) [][]byte {
+	t.Helper()
+	var refs [][]byte
+	err := fileObject.References(context.Background(), podFile, podPassword, func(ref []byte) {
+		refs = append(refs, ref)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the first reference is the inode
+	return refs[1:]
+}
+
+func assertContent(t *testing.T, fileObject *file.File, podFile, podPassword string, expected []byte) {
+	t.Helper()
+	reader, _, err := fileObject.Download(context.Background(), podFile, podPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, expected) {
+		t.Fatalf("content mismatch, expected %q got %q", expected, data)
+	}
 }
 
 func uploadFileKnownContent(t *testing.T, fileObject *file.File, filePath, fileName, compression, podPassword string, blockSize uint32) ([]byte, error) {
+	ctx := context.Background()
 	f1 := &bytes.Buffer{}
 	content := []byte("abcd")
 	_, err := f1.Write(content)
@@ -607,5 +715,5 @@ func uploadFileKnownContent(t *testing.T, fileObject *file.File, filePath, fileN
 		t.Fatal(err)
 	}
 	// upload  the temp file
-	return content, fileObject.Upload(f1, fileName, int64(len(content)), blockSize, filePath, compression, podPassword)
+	return content, fileObject.Upload(ctx, f1, fileName, int64(len(content)), blockSize, filePath, compression, podPassword)
 }
@@ -0,0 +1,84 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"context"
+	"time"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+)
+
+// Xattrs returns the extended attributes of a file
+func (f *File) Xattrs(podFileWithPath string) (map[string]string, error) {
+	meta := f.GetFromFileMap(utils.CombinePathAndFile(podFileWithPath, ""))
+	if meta == nil {
+		return nil, ErrFileNotPresent
+	}
+	return utils.CopyXattrs(meta.Xattrs), nil
+}
+
+// SetXattr sets an extended attribute of a file
+func (f *File) SetXattr(ctx context.Context, podFileWithPath, podPassword, name, value string) error {
+	return f.modifyXattrs(ctx, podFileWithPath, podPassword, func(xattrs map[string]string) (map[string]string, error) {
+		return utils.SetXattr(xattrs, name, value)
+	})
+}
+
+// RemoveXattr removes an extended attribute of a file
+func (f *File) RemoveXattr(ctx context.Context, podFileWithPath, podPassword, name string) error {
+	return f.modifyXattrs(ctx, podFileWithPath, podPassword, func(xattrs map[string]string) (map[string]string, error) {
+		return utils.RemoveXattr(xattrs, name)
+	})
+}
+
+// SetXattrs replaces all the extended attributes of a file
+func (f *File) SetXattrs(ctx context.Context, podFileWithPath, podPassword string, xattrs map[string]string) error {
+	err := utils.CheckXattrs(xattrs)
+	if err != nil {
+		return err
+	}
+	return f.modifyXattrs(ctx, podFileWithPath, podPassword, func(map[string]string) (map[string]string, error) {
+		return utils.CopyXattrs(xattrs), nil
+	})
+}
+
+func (f *File) modifyXattrs(ctx context.Context, podFileWithPath, podPassword string, change func(xattrs map[string]string) (map[string]string, error)) error {
+	totalFilePath := utils.CombinePathAndFile(podFileWithPath, "")
+	if !f.IsFileAlreadyPresent(totalFilePath) {
+		return ErrFileNotPresent
+	}
+	meta := f.GetFromFileMap(totalFilePath)
+	if meta == nil { // skipcq: TCV-001
+		return ErrFileNotFound
+	}
+	xattrs, err := change(meta.Xattrs)
+	if err != nil {
+		return err
+	}
+
+	// the meta in the file map is replaced, not changed, as readers may hold it
+	updated := *meta
+	updated.Xattrs = xattrs
+	updated.AccessTime = time.Now().Unix()
+	err = f.updateMeta(ctx, &updated, podPassword)
+	if err != nil {
+		return err
+	}
+	f.AddToFileMap(totalFilePath, &updated)
+	return nil
+}
@@ -0,0 +1,164 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/account"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/bee/mock"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/file"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/pod"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+	"github.com/plexsysio/taskmanager"
+)
+
+func TestXattr(t *testing.T) {
+	ctx := context.Background()
+	mockClient := mock.NewMockBeeClient()
+	logger := logging.New(io.Discard, 0)
+	acc := account.New(logger)
+	_, _, err := acc.CreateUserAccount("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	podAccountInfo, err := acc.CreatePodAccount(1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fd := feed.New(podAccountInfo, mockClient, logger)
+	user := acc.GetAddress(1)
+	tm := taskmanager.New(1, 10, time.Second*15, logger)
+	defer func() {
+		_ = tm.Stop(context.Background())
+	}()
+	podPassword, _ := utils.GetRandString(pod.PasswordLength)
+	fileObject := file.NewFile("pod1", mockClient, fd, user, tm, logger)
+	content := []byte("some content")
+	err = fileObject.Upload(ctx, bytes.NewReader(content), "file1", int64(len(content)), 10, "/dir1", "", podPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("set-get-remove", func(t *testing.T) {
+		xattrs, err := fileObject.Xattrs("/dir1/file1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(xattrs) != 0 {
+			t.Fatalf("unexpected xattrs %v", xattrs)
+		}
+
+		err = fileObject.SetXattr(ctx, "/dir1/file1", podPassword, "user.tag", "red")
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = fileObject.SetXattr(ctx, "/dir1/file1", podPassword, "user.owner", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		xattrs, err = fileObject.Xattrs("/dir1/file1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(xattrs) != 2 || xattrs["user.tag"] != "red" || xattrs["user.owner"] != "" {
+			t.Fatalf("unexpected xattrs %v", xattrs)
+		}
+
+		// the attributes are stored with the metadata of the file
+		entries, err := file.NewFile("pod1", mockClient, fd, user, tm, logger).ListFiles(ctx, []string{"/dir1/file1"}, podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 || entries[0].Xattrs["user.tag"] != "red" {
+			t.Fatalf("unexpected entries %v", entries)
+		}
+		stats, err := fileObject.GetStats(ctx, "pod1", "/dir1/file1", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(stats.Xattrs) != 2 {
+			t.Fatalf("unexpected stats xattrs %v", stats.Xattrs)
+		}
+
+		err = fileObject.RemoveXattr(ctx, "/dir1/file1", podPassword, "user.owner")
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = fileObject.RemoveXattr(ctx, "/dir1/file1", podPassword, "user.owner")
+		if !errors.Is(err, utils.ErrXattrNotFound) {
+			t.Fatalf("expected %v, got %v", utils.ErrXattrNotFound, err)
+		}
+		xattrs, err = fileObject.Xattrs("/dir1/file1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(xattrs) != 1 || xattrs["user.tag"] != "red" {
+			t.Fatalf("unexpected xattrs %v", xattrs)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		err := fileObject.SetXattr(ctx, "/dir1/file1", podPassword, "", "value")
+		if !errors.Is(err, utils.ErrInvalidXattrName) {
+			t.Fatalf("expected %v, got %v", utils.ErrInvalidXattrName, err)
+		}
+		err = fileObject.SetXattr(ctx, "/dir1/file1", podPassword, strings.Repeat("n", utils.MaxXattrNameLength+1), "value")
+		if !errors.Is(err, utils.ErrInvalidXattrName) {
+			t.Fatalf("expected %v, got %v", utils.ErrInvalidXattrName, err)
+		}
+		err = fileObject.SetXattr(ctx, "/dir1/file1", podPassword, "user.big", strings.Repeat("v", utils.MaxXattrsLength))
+		if !errors.Is(err, utils.ErrXattrsTooLarge) {
+			t.Fatalf("expected %v, got %v", utils.ErrXattrsTooLarge, err)
+		}
+		err = fileObject.SetXattr(ctx, "/dir1/file2", podPassword, "user.tag", "red")
+		if !errors.Is(err, file.ErrFileNotPresent) {
+			t.Fatalf("expected %v, got %v", file.ErrFileNotPresent, err)
+		}
+
+		// failed changes leave the attributes as they were
+		xattrs, err := fileObject.Xattrs("/dir1/file1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(xattrs) != 1 || xattrs["user.tag"] != "red" {
+			t.Fatalf("unexpected xattrs %v", xattrs)
+		}
+	})
+
+	t.Run("rename", func(t *testing.T) {
+		_, err := fileObject.RenameFromFileName(ctx, "/dir1/file1", "/dir1/renamed", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		xattrs, err := fileObject.Xattrs("/dir1/renamed")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(xattrs) != 1 || xattrs["user.tag"] != "red" {
+			t.Fatalf("unexpected xattrs %v", xattrs)
+		}
+	})
+}
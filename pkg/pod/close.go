@@ -16,9 +16,11 @@ limitations under the License.
 
 package pod
 
+import "context"
+
 // ClosePod closed an already opened pod and removes its information from directory and file
 // data structures.
-func (p *Pod) ClosePod(podName string) error {
+func (p *Pod) ClosePod(ctx context.Context, podName string) error {
 	if !p.IsPodOpened(podName) {
 		return ErrPodNotOpened
 	}
@@ -28,11 +30,19 @@ func (p *Pod) ClosePod(podName string) error {
 		return err
 	}
 
-	podIndex, err := p.getPodIndex(podName)
+	podIndex, err := p.getPodIndex(ctx, podName)
 	if err != nil { // skipcq: TCV-001
 		return err
 	}
 
+	// write the pins recorded since the last flush
+	if podInfo.ledger != nil {
+		err = podInfo.ledger.flush(ctx)
+		if err != nil { // skipcq: TCV-001
+			p.logger.Warningf("pin ledger: %v", err)
+		}
+	}
+
 	// remove from all thr maps
 	podInfo.dir.RemoveAllFromDirectoryMap()
 	podInfo.file.RemoveAllFromFileMap()
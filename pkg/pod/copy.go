@@ -0,0 +1,165 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	d "github.com/fairdatasociety/fairOS-dfs/pkg/dir"
+	f "github.com/fairdatasociety/fairOS-dfs/pkg/file"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+)
+
+// CopyFile copies a file of the source pod to dstFileWithPath in the dst pod, which can be
+// the source pod itself. The copy refers to the blocks of the source file unless the source
+// is a pod shared by another user. Blocks that a copy in another pod uses stay pinned by
+// the source pod, its garbage collector does not unpin them.
+func CopyFile(ctx context.Context, source, dst *Info, srcFileWithPath, dstFileWithPath string) error {
+	srcFileWithPath = utils.CombinePathAndFile(srcFileWithPath, "")
+	dstFileWithPath = utils.CombinePathAndFile(dstFileWithPath, "")
+	if source.GetFile().GetFromFileMap(srcFileWithPath) == nil {
+		return f.ErrFileNotPresent
+	}
+	err := checkCopyDestination(dst, dstFileWithPath)
+	if err != nil {
+		return err
+	}
+	return copyFile(ctx, source, dst, srcFileWithPath, dstFileWithPath)
+}
+
+// CopyDirectory copies a directory tree of the source pod to dstDirNameWithPath in the dst
+// pod, which can be the source pod itself. The files are copied like in CopyFile, the links
+// are copied as links.
+func CopyDirectory(ctx context.Context, source, dst *Info, srcDirNameWithPath, dstDirNameWithPath string) error {
+	srcDirNameWithPath = utils.CombinePathAndFile(srcDirNameWithPath, "")
+	dstDirNameWithPath = utils.CombinePathAndFile(dstDirNameWithPath, "")
+	if source.GetDirectory().GetDirFromDirectoryMap(srcDirNameWithPath) == nil {
+		return d.ErrDirectoryNotPresent
+	}
+	if source.GetPodName() == dst.GetPodName() &&
+		(dstDirNameWithPath == srcDirNameWithPath || strings.HasPrefix(dstDirNameWithPath, strings.TrimSuffix(srcDirNameWithPath, "/")+"/")) {
+		return ErrCopyIntoItself
+	}
+	err := checkCopyDestination(dst, dstDirNameWithPath)
+	if err != nil {
+		return err
+	}
+	return copyDirectory(ctx, source, dst, srcDirNameWithPath, dstDirNameWithPath)
+}
+
+// checkCopyDestination checks that the parent of the destination is a directory and that
+// nothing of the destination name is present
+func checkCopyDestination(dst *Info, dstPath string) error {
+	if dstPath == "/" {
+		return d.ErrDirectoryAlreadyPresent
+	}
+	if dst.GetDirectory().GetDirFromDirectoryMap(filepath.ToSlash(filepath.Dir(dstPath))) == nil {
+		return d.ErrDirectoryNotPresent
+	}
+	if dst.GetFile().IsFileAlreadyPresent(dstPath) {
+		return f.ErrFileAlreadyPresent
+	}
+	if dst.GetDirectory().GetDirFromDirectoryMap(dstPath) != nil {
+		return d.ErrDirectoryAlreadyPresent
+	}
+	if _, err := dst.GetDirectory().LinkStat(dst.GetPodName(), dstPath); err == nil {
+		return d.ErrLinkAlreadyPresent
+	}
+	return nil
+}
+
+func copyFile(ctx context.Context, source, dst *Info, srcFileWithPath, dstFileWithPath string) error {
+	// the pod keeps track of the blocks its own files share, the blocks shared with other
+	// pods are exported. The pins of a pod shared by another user are not the pod's to keep.
+	var (
+		shared   func(ref []byte)
+		exported [][]byte
+	)
+	switch {
+	case source.GetPodName() == dst.GetPodName():
+		shared = func([]byte) {}
+	case source.ledger != nil:
+		shared = func(ref []byte) {
+			exported = append(exported, ref)
+		}
+	}
+	_, err := dst.GetFile().CopyFrom(ctx, source.GetFile(), srcFileWithPath, dstFileWithPath, dst.GetPodPassword(), shared)
+	if err != nil {
+		return err
+	}
+	if len(exported) > 0 {
+		source.ledger.export(ctx, exported...)
+	}
+	if shared != nil {
+		err = source.GetFile().ShareBlocks(ctx, srcFileWithPath, source.GetPodPassword())
+		if err != nil { // skipcq: TCV-001
+			return err
+		}
+	}
+	return dst.GetDirectory().AddEntryToDir(ctx, filepath.ToSlash(filepath.Dir(dstFileWithPath)), dst.GetPodPassword(), filepath.Base(dstFileWithPath), true)
+}
+
+func copyDirectory(ctx context.Context, source, dst *Info, srcDirNameWithPath, dstDirNameWithPath string) error {
+	dirInode := source.GetDirectory().GetDirFromDirectoryMap(srcDirNameWithPath)
+	if dirInode == nil { // skipcq: TCV-001
+		return d.ErrDirectoryNotPresent
+	}
+	err := dst.GetDirectory().MkDir(ctx, dstDirNameWithPath, dst.GetPodPassword())
+	if err != nil {
+		return err
+	}
+	if dirInode.Meta != nil {
+		err = dst.GetDirectory().Chmod(ctx, dstDirNameWithPath, dst.GetPodPassword(), dirInode.Meta.Mode&^d.S_IFDIR)
+		if err != nil { // skipcq: TCV-001
+			return err
+		}
+		if len(dirInode.Meta.Xattrs) > 0 {
+			err = dst.GetDirectory().SetXattrs(ctx, dstDirNameWithPath, dst.GetPodPassword(), dirInode.Meta.Xattrs)
+			if err != nil { // skipcq: TCV-001
+				return err
+			}
+		}
+	}
+	for _, fileOrDirName := range dirInode.FileOrDirNames {
+		if strings.HasPrefix(fileOrDirName, "_F_") {
+			fileName := strings.TrimPrefix(fileOrDirName, "_F_")
+			err = copyFile(ctx, source, dst, utils.CombinePathAndFile(srcDirNameWithPath, fileName), utils.CombinePathAndFile(dstDirNameWithPath, fileName))
+			if err != nil {
+				return err
+			}
+		} else if strings.HasPrefix(fileOrDirName, "_D_") {
+			dirName := strings.TrimPrefix(fileOrDirName, "_D_")
+			err = copyDirectory(ctx, source, dst, utils.CombinePathAndFile(srcDirNameWithPath, dirName), utils.CombinePathAndFile(dstDirNameWithPath, dirName))
+			if err != nil {
+				return err
+			}
+		} else if strings.HasPrefix(fileOrDirName, "_L_") {
+			linkName := strings.TrimPrefix(fileOrDirName, "_L_")
+			link := dirInode.Links[linkName]
+			if link == nil { // skipcq: TCV-001
+				continue
+			}
+			err = dst.GetDirectory().Symlink(ctx, link.Target, utils.CombinePathAndFile(dstDirNameWithPath, linkName), dst.GetPodPassword())
+			if err != nil { // skipcq: TCV-001
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -17,12 +17,13 @@ limitations under the License.
 package pod
 
 import (
+	"context"
 	"fmt"
 )
 
 // DeleteOwnPod removed a pod and the list of pods belonging to a user.
-func (p *Pod) DeleteOwnPod(podName string) error {
-	podList, err := p.loadUserPods()
+func (p *Pod) DeleteOwnPod(ctx context.Context, podName string) error {
+	podList, err := p.loadUserPods(ctx)
 	if err != nil { // skipcq: TCV-001
 		return err
 	}
@@ -45,12 +46,12 @@ func (p *Pod) DeleteOwnPod(podName string) error {
 		return err
 	}
 
-	err = podInfo.GetDocStore().DeleteAllDocumentDBs(podInfo.GetPodPassword())
+	err = podInfo.GetDocStore().DeleteAllDocumentDBs(ctx, podInfo.GetPodPassword())
 	if err != nil {
 		return err
 	}
 
-	err = podInfo.GetKVStore().DeleteAllKVTables(podInfo.GetPodPassword())
+	err = podInfo.GetKVStore().DeleteAllKVTables(ctx, podInfo.GetPodPassword())
 	if err != nil {
 		return err
 	}
@@ -60,12 +61,12 @@ func (p *Pod) DeleteOwnPod(podName string) error {
 	p.acc.DeletePodAccount(podIndex)
 
 	// remove the pod finally
-	return p.storeUserPods(podList)
+	return p.storeUserPods(ctx, podList)
 }
 
 // DeleteSharedPod removed a pod and the list of pods shared by other users.
-func (p *Pod) DeleteSharedPod(podName string) error {
-	podList, err := p.loadUserPods()
+func (p *Pod) DeleteSharedPod(ctx context.Context, podName string) error {
+	podList, err := p.loadUserPods(ctx)
 	if err != nil { // skipcq: TCV-001
 		return err
 	}
@@ -85,5 +86,5 @@ func (p *Pod) DeleteSharedPod(podName string) error {
 	p.removePodFromPodMap(podName)
 
 	// remove the pod finally
-	return p.storeUserPods(podList)
+	return p.storeUserPods(ctx, podList)
 }
@@ -43,4 +43,10 @@ var (
 	ErrMaximumPodLimit = errors.New("maximum number of pods has reached")
 	//ErrBlankPodSharingReference
 	ErrBlankPodSharingReference = errors.New("pod sharing reference cannot be blank")
+	//ErrGCRunning
+	ErrGCRunning = errors.New("garbage collection already running for the pod")
+	//ErrGCSharedPod
+	ErrGCSharedPod = errors.New("garbage collection is not supported for shared pods")
+	//ErrCopyIntoItself
+	ErrCopyIntoItself = errors.New("cannot copy a directory into itself")
 )
@@ -1,6 +1,7 @@
 package pod
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -13,7 +14,7 @@ import (
 )
 
 // PodFork forks a pod with a different given name
-func (p *Pod) PodFork(podName, forkName string) error {
+func (p *Pod) PodFork(ctx context.Context, podName, forkName string) error {
 	podName, err := CleanPodName(podName)
 	if err != nil { // skipcq: TCV-001
 		return err
@@ -43,16 +44,16 @@ func (p *Pod) PodFork(podName, forkName string) error {
 	}
 
 	rootInode := podInfo.GetDirectory().GetDirFromDirectoryMap("/")
-	return cloneFolder(podInfo, forkInfo, "/", rootInode)
+	return cloneFolder(ctx, podInfo, forkInfo, "/", rootInode)
 }
 
 // PodForkFromRef forks a pof from a given pod sharing reference
-func (p *Pod) PodForkFromRef(forkName, refString string) error {
+func (p *Pod) PodForkFromRef(ctx context.Context, forkName, refString string) error {
 	ref, err := utils.ParseHexReference(refString)
 	if err != nil {
 		return nil
 	}
-	data, resp, err := p.client.DownloadBlob(ref.Bytes())
+	data, resp, err := p.client.DownloadBlob(ctx, ref.Bytes())
 	if err != nil { // skipcq: TCV-001
 		return err
 	}
@@ -68,7 +69,7 @@ func (p *Pod) PodForkFromRef(forkName, refString string) error {
 	address := utils.HexToAddress(shareInfo.Address)
 	accountInfo.SetAddress(address)
 
-	fd := feed.New(accountInfo, p.client, p.logger)
+	fd := feed.NewWithType(accountInfo, p.client, shareInfo.FeedType, p.logger)
 	file := f.NewFile(shareInfo.PodName, p.client, fd, accountInfo.GetAddress(), p.tm, p.logger)
 	dir := d.NewDirectory(shareInfo.PodName, p.client, fd, accountInfo.GetAddress(), file, p.tm, p.logger)
 	podInfo := &Info{
@@ -81,12 +82,12 @@ func (p *Pod) PodForkFromRef(forkName, refString string) error {
 		feed:        fd,
 	}
 
-	return p.forkPod(podInfo, forkName)
+	return p.forkPod(ctx, podInfo, forkName)
 }
 
 // PodForkCore creates a new pod with all the contents of a given pod
-func (p *Pod) forkPod(podInfo *Info, forkName string) error {
-	err := podInfo.GetDirectory().SyncDirectory("/", podInfo.GetPodPassword())
+func (p *Pod) forkPod(ctx context.Context, podInfo *Info, forkName string) error {
+	err := podInfo.GetDirectory().SyncDirectory(ctx, "/", podInfo.GetPodPassword())
 	if err != nil {
 		return err
 	}
@@ -107,27 +108,39 @@ func (p *Pod) forkPod(podInfo *Info, forkName string) error {
 
 	// sync from the root directory
 	rootInode := podInfo.GetDirectory().GetDirFromDirectoryMap("/")
-	return cloneFolder(podInfo, forkInfo, "/", rootInode)
+	return cloneFolder(ctx, podInfo, forkInfo, "/", rootInode)
 }
 
-func cloneFolder(source, dst *Info, dirNameWithPath string, dirInode *d.Inode) error {
+func cloneFolder(ctx context.Context, source, dst *Info, dirNameWithPath string, dirInode *d.Inode) error {
+	if dirInode.Meta != nil && len(dirInode.Meta.Xattrs) > 0 {
+		err := dst.GetDirectory().SetXattrs(ctx, dirNameWithPath, dst.GetPodPassword(), dirInode.Meta.Xattrs)
+		if err != nil { // skipcq: TCV-001
+			return err
+		}
+	}
 	for _, fileOrDirName := range dirInode.FileOrDirNames {
 		if strings.HasPrefix(fileOrDirName, "_F_") {
 			fileName := strings.TrimPrefix(fileOrDirName, "_F_")
 			filePath := utils.CombinePathAndFile(dirNameWithPath, fileName)
 			meta := source.GetFile().GetFromFileMap(filePath)
 
-			r, _, err := source.GetFile().Download(filePath, source.GetPodPassword())
+			r, _, err := source.GetFile().Download(ctx, filePath, source.GetPodPassword())
 			if err != nil { // skipcq: TCV-001
 				return err
 			}
 
-			err = dst.GetFile().Upload(r, meta.Name, int64(meta.Size), meta.BlockSize, meta.Path, meta.Compression, dst.GetPodPassword())
+			err = dst.GetFile().Upload(ctx, r, meta.Name, int64(meta.Size), meta.BlockSize, meta.Path, meta.Compression, dst.GetPodPassword())
 			if err != nil { // skipcq: TCV-001
 				return err
 			}
+			if len(meta.Xattrs) > 0 {
+				err = dst.GetFile().SetXattrs(ctx, filePath, dst.GetPodPassword(), meta.Xattrs)
+				if err != nil { // skipcq: TCV-001
+					return err
+				}
+			}
 
-			err = dst.GetDirectory().AddEntryToDir(dirNameWithPath, dst.GetPodPassword(), fileName, true)
+			err = dst.GetDirectory().AddEntryToDir(ctx, dirNameWithPath, dst.GetPodPassword(), fileName, true)
 			if err != nil { // skipcq: TCV-001
 				return err
 			}
@@ -135,11 +148,21 @@ func cloneFolder(source, dst *Info, dirNameWithPath string, dirInode *d.Inode) e
 			dirName := strings.TrimPrefix(fileOrDirName, "_D_")
 			path := utils.CombinePathAndFile(dirNameWithPath, dirName)
 			iNode := source.GetDirectory().GetDirFromDirectoryMap(path)
-			err := dst.GetDirectory().MkDir(path, dst.GetPodPassword())
+			err := dst.GetDirectory().MkDir(ctx, path, dst.GetPodPassword())
 			if err != nil { // skipcq: TCV-001
 				return err
 			}
-			err = cloneFolder(source, dst, path, iNode)
+			err = cloneFolder(ctx, source, dst, path, iNode)
+			if err != nil { // skipcq: TCV-001
+				return err
+			}
+		} else if strings.HasPrefix(fileOrDirName, "_L_") {
+			linkName := strings.TrimPrefix(fileOrDirName, "_L_")
+			link := dirInode.Links[linkName]
+			if link == nil { // skipcq: TCV-001
+				continue
+			}
+			err := dst.GetDirectory().Symlink(ctx, link.Target, utils.CombinePathAndFile(dirNameWithPath, linkName), dst.GetPodPassword())
 			if err != nil { // skipcq: TCV-001
 				return err
 			}
@@ -0,0 +1,177 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"context"
+	"encoding/hex"
+	"time"
+)
+
+// GCGracePeriod keeps recently recorded references out of the garbage collection, so that
+// the blocks of an upload that is not linked in to the pod yet are not unpinned
+var GCGracePeriod = 10 * time.Minute
+
+// GCReport is the outcome of a garbage collection of a pod
+type GCReport struct {
+	PodName string `json:"podName"`
+	DryRun  bool   `json:"dryRun"`
+	// Recorded is the number of distinct references the pod has pinned
+	Recorded int `json:"recorded"`
+	// Reachable references are still used by a file, directory or collection of the pod
+	Reachable int `json:"reachable"`
+	// Exported references are used by copies in other pods and are kept
+	Exported int `json:"exported"`
+	// Recent references are younger than the grace period and are kept
+	Recent int `json:"recent"`
+	// Garbage lists the references that are not reachable any more
+	Garbage []string `json:"garbage"`
+	// Unpinned and Failed count the garbage references that were, or could not be, unpinned
+	Unpinned int `json:"unpinned"`
+	Failed   int `json:"failed"`
+}
+
+// CollectGarbage unpins the blobs that the pod uploaded and that are no longer reachable
+// from its directories, files or collections. With dryRun it only reports the garbage.
+// Only the blobs uploaded since the pod records its pins are collected.
+func (p *Pod) CollectGarbage(ctx context.Context, podName string, dryRun bool) (*GCReport, error) {
+	podName, err := CleanPodName(podName)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	if !p.IsPodOpened(podName) {
+		return nil, ErrPodNotOpened
+	}
+	podInfo, _, err := p.GetPodInfoFromPodMap(podName)
+	if err != nil { // skipcq: TCV-001
+		return nil, err
+	}
+	l := podInfo.ledger
+	if l == nil {
+		return nil, ErrGCSharedPod
+	}
+	if !l.startCollecting() {
+		return nil, ErrGCRunning
+	}
+	defer l.stopCollecting()
+
+	// uploads that finish during the collection stay pending and are written afterwards
+	l.flushMu.Lock()
+	defer l.flushMu.Unlock()
+	err = l.flushLocked(ctx)
+	if err != nil {
+		return nil, err
+	}
+	entries, segments, err := l.entries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reachable, err := podInfo.references(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &GCReport{
+		PodName: podName,
+		DryRun:  dryRun,
+		Garbage: []string{},
+	}
+	exported := make(map[string]bool)
+	for _, e := range entries {
+		if e.Exported {
+			exported[string(e.Ref)] = true
+		}
+	}
+	cutoff := time.Now().Add(-GCGracePeriod).Unix()
+	seen := make(map[string]bool)
+	var keep, garbage []ledgerEntry
+	for _, e := range entries {
+		if seen[string(e.Ref)] {
+			continue
+		}
+		seen[string(e.Ref)] = true
+		e.Exported = exported[string(e.Ref)]
+		switch {
+		case reachable[string(e.Ref)]:
+			report.Reachable++
+			keep = append(keep, e)
+		case e.Exported:
+			report.Exported++
+			keep = append(keep, e)
+		case e.Created > cutoff:
+			report.Recent++
+			keep = append(keep, e)
+		default:
+			garbage = append(garbage, e)
+			report.Garbage = append(report.Garbage, hex.EncodeToString(e.Ref))
+		}
+	}
+	report.Recorded = len(seen)
+	if dryRun || len(garbage) == 0 {
+		return report, nil
+	}
+
+	for _, e := range garbage {
+		err = l.Client.DeleteReference(ctx, e.Ref)
+		if err != nil {
+			p.logger.Warningf("gc: could not unpin %s: %v", hex.EncodeToString(e.Ref), err)
+			report.Failed++
+			keep = append(keep, e)
+			continue
+		}
+		report.Unpinned++
+	}
+
+	// replace the chain with a single segment of the references that are kept
+	err = l.write(ctx, &ledgerSegment{Entries: keep}, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, ref := range segments {
+		err = l.Client.DeleteReference(ctx, ref)
+		if err != nil { // skipcq: TCV-001
+			p.logger.Warningf("gc: could not unpin ledger segment: %v", err)
+		}
+	}
+	return report, nil
+}
+
+// references returns the set of references reachable from the pod
+func (i *Info) references(ctx context.Context) (map[string]bool, error) {
+	reachable := make(map[string]bool)
+	mark := func(ref []byte) {
+		reachable[string(ref)] = true
+	}
+	err := i.dir.References(ctx, "/", i.podPassword, mark)
+	if err != nil {
+		return nil, err
+	}
+	err = i.kvStore.References(ctx, i.podPassword, mark)
+	if err != nil {
+		return nil, err
+	}
+	err = i.docStore.References(ctx, i.podPassword, mark)
+	if err != nil {
+		return nil, err
+	}
+	// the blocks of unfinished uploads are kept until the upload is finished or aborted
+	err = i.file.UploadSessionReferences(ctx, i.podPassword, mark)
+	if err != nil {
+		return nil, err
+	}
+	return reachable, nil
+}
@@ -37,6 +37,7 @@ type Info struct {
 	feed        *feed.API
 	kvStore     *collection.KeyValue
 	docStore    *collection.Document
+	ledger      *pinLedger
 }
 
 // GetPodName
@@ -0,0 +1,296 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+)
+
+const (
+	pinLedgerFile = "pinned_references"
+
+	// ledgerFlushSize is the number of recorded references that are written in one segment
+	ledgerFlushSize = 128
+)
+
+type ledgerEntry struct {
+	Ref     []byte `json:"ref"`
+	Created int64  `json:"created"`
+	// Exported references are also used by copies in other pods
+	Exported bool `json:"exported,omitempty"`
+}
+
+// ledgerSegment is a blob of recorded references, linked to the segment written before it
+type ledgerSegment struct {
+	Prev    []byte        `json:"prev,omitempty"`
+	Entries []ledgerEntry `json:"entries"`
+}
+
+// pinLedger is the blockstore client of a pod. It records the root of every blob that
+// the pod pins, so that the garbage collector knows what the pod has uploaded. The
+// records are written as a chain of encrypted segments, the latest of them in a feed.
+type pinLedger struct {
+	blockstore.Client
+	fd       *feed.API
+	user     utils.Address
+	password string
+	logger   logging.Logger
+
+	mu         sync.Mutex // guards pending, collecting and owned
+	pending    []ledgerEntry
+	collecting bool
+	// owned has the recorded references once Owns read the chain
+	owned map[string]bool
+
+	flushMu sync.Mutex // serialises the writes of the chain
+	loaded  bool
+	head    []byte
+}
+
+func newPinLedger(client blockstore.Client, fd *feed.API, user utils.Address, password string, logger logging.Logger) *pinLedger {
+	return &pinLedger{
+		Client:   client,
+		fd:       fd,
+		user:     user,
+		password: password,
+		logger:   logger,
+	}
+}
+
+// UploadBlob uploads the blob and records it if it is pinned
+func (l *pinLedger) UploadBlob(ctx context.Context, data []byte, tag uint32, pin, encrypt bool) ([]byte, error) {
+	ref, err := l.Client.UploadBlob(ctx, data, tag, pin, encrypt)
+	if err != nil {
+		return nil, err
+	}
+	if pin {
+		l.record(ctx, ref)
+	}
+	return ref, nil
+}
+
+// UploadBlobs uploads the blobs and records them if they are pinned
+func (l *pinLedger) UploadBlobs(ctx context.Context, blobs [][]byte, tag uint32, pin, encrypt bool) ([][]byte, error) {
+	refs, err := l.Client.UploadBlobs(ctx, blobs, tag, pin, encrypt)
+	if err != nil {
+		return nil, err
+	}
+	if pin {
+		l.record(ctx, refs...)
+	}
+	return refs, nil
+}
+
+func (l *pinLedger) record(ctx context.Context, refs ...[]byte) {
+	l.add(ctx, false, refs)
+}
+
+// export records references of the pod that copies in other pods use. The garbage
+// collector keeps them, as it cannot tell when the other pods stop using them.
+func (l *pinLedger) export(ctx context.Context, refs ...[]byte) {
+	l.add(ctx, true, refs)
+}
+
+func (l *pinLedger) add(ctx context.Context, exported bool, refs [][]byte) {
+	now := time.Now().Unix()
+	l.mu.Lock()
+	for _, ref := range refs {
+		l.pending = append(l.pending, ledgerEntry{Ref: ref, Created: now, Exported: exported})
+		if l.owned != nil {
+			l.owned[string(ref)] = true
+		}
+	}
+	flush := len(l.pending) >= ledgerFlushSize && !l.collecting
+	l.mu.Unlock()
+
+	if flush {
+		err := l.flush(ctx)
+		if err != nil {
+			l.logger.Warningf("pin ledger: %v", err)
+		}
+	}
+}
+
+// Owns tells if the pod recorded the reference as uploaded by itself. Blobs of other
+// pods, like the ones of a file received from another user, are never recorded, and
+// neither are the ones uploaded before the pod recorded its pins.
+func (l *pinLedger) Owns(ctx context.Context, ref []byte) (bool, error) {
+	l.mu.Lock()
+	loaded := l.owned != nil
+	l.mu.Unlock()
+	if !loaded {
+		// the chain and the pending records are read together, so that a flush does not
+		// move a record between them
+		l.flushMu.Lock()
+		entries, _, err := l.entries(ctx)
+		if err != nil {
+			l.flushMu.Unlock()
+			return false, err
+		}
+		owned := make(map[string]bool, len(entries))
+		for _, e := range entries {
+			owned[string(e.Ref)] = true
+		}
+		l.mu.Lock()
+		for _, e := range l.pending {
+			owned[string(e.Ref)] = true
+		}
+		l.owned = owned
+		l.mu.Unlock()
+		l.flushMu.Unlock()
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.owned[string(ref)], nil
+}
+
+// flush writes the pending records as a new segment
+func (l *pinLedger) flush(ctx context.Context) error {
+	l.flushMu.Lock()
+	defer l.flushMu.Unlock()
+	return l.flushLocked(ctx)
+}
+
+func (l *pinLedger) flushLocked(ctx context.Context) error {
+	l.mu.Lock()
+	entries := l.pending
+	l.pending = nil
+	l.mu.Unlock()
+	if len(entries) == 0 {
+		return nil
+	}
+
+	err := l.write(ctx, &ledgerSegment{Entries: entries}, true)
+	if err != nil {
+		// keep the records for the next flush
+		l.mu.Lock()
+		l.pending = append(entries, l.pending...)
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// write stores the segment and makes it the head of the chain. The segment is linked to
+// the current head if link is set, otherwise it starts a new chain.
+func (l *pinLedger) write(ctx context.Context, segment *ledgerSegment, link bool) error {
+	exists, err := l.loadHead(ctx)
+	if err != nil {
+		return err
+	}
+	if link {
+		segment.Prev = l.head
+	}
+	data, err := json.Marshal(segment)
+	if err != nil { // skipcq: TCV-001
+		return err
+	}
+	ref, err := l.Client.UploadBlob(ctx, data, 0, true, true)
+	if err != nil {
+		return err
+	}
+	topic := utils.HashString(pinLedgerFile)
+	if exists {
+		_, err = l.fd.UpdateFeed(ctx, topic, l.user, ref, []byte(l.password))
+	} else {
+		_, err = l.fd.CreateFeed(ctx, topic, l.user, ref, []byte(l.password))
+	}
+	if err != nil { // skipcq: TCV-001
+		return err
+	}
+	l.head = ref
+	return nil
+}
+
+// loadHead reads the latest segment reference once and tells if the ledger feed exists
+func (l *pinLedger) loadHead(ctx context.Context) (bool, error) {
+	if l.loaded {
+		return l.head != nil, nil
+	}
+	topic := utils.HashString(pinLedgerFile)
+	_, data, err := l.fd.GetFeedData(ctx, topic, l.user, []byte(l.password))
+	if err != nil {
+		if err.Error() != "feed does not exist or was not updated yet" {
+			return false, err
+		}
+		data = nil
+	}
+	l.loaded = true
+	if len(data) > 0 && string(data) != utils.DeletedFeedMagicWord {
+		l.head = data
+	}
+	return l.head != nil, nil
+}
+
+// entries reads the whole chain. It returns the records, oldest first, and the
+// references of the segments that hold them.
+func (l *pinLedger) entries(ctx context.Context) ([]ledgerEntry, [][]byte, error) {
+	if _, err := l.loadHead(ctx); err != nil {
+		return nil, nil, err
+	}
+	var (
+		entries  []ledgerEntry
+		segments [][]byte
+	)
+	for ref := l.head; ref != nil; {
+		data, respCode, err := l.Client.DownloadBlob(ctx, ref)
+		if err != nil {
+			return nil, nil, err
+		}
+		if respCode != http.StatusOK { // skipcq: TCV-001
+			return nil, nil, fmt.Errorf("pin ledger: could not download segment")
+		}
+		var segment ledgerSegment
+		err = json.Unmarshal(data, &segment)
+		if err != nil { // skipcq: TCV-001
+			return nil, nil, err
+		}
+		segments = append(segments, ref)
+		entries = append(segment.Entries, entries...)
+		ref = segment.Prev
+	}
+	return entries, segments, nil
+}
+
+// startCollecting stops the automatic flushes while a garbage collection rewrites the chain
+func (l *pinLedger) startCollecting() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.collecting {
+		return false
+	}
+	l.collecting = true
+	return true
+}
+
+func (l *pinLedger) stopCollecting() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.collecting = false
+	// the collection dropped the unpinned references from the chain
+	l.owned = nil
+}
@@ -16,9 +16,11 @@ limitations under the License.
 
 package pod
 
+import "context"
+
 // ListPods List all the available pods belonging to a user.
-func (p *Pod) ListPods() ([]string, []string, error) {
-	podList, err := p.loadUserPods()
+func (p *Pod) ListPods(ctx context.Context) ([]string, []string, error) {
+	podList, err := p.loadUserPods(ctx)
 	if err != nil { // skipcq: TCV-001
 		return nil, nil, err
 	}
@@ -37,6 +39,6 @@ func (p *Pod) ListPods() ([]string, []string, error) {
 }
 
 // List List all the available pods belonging to a user in json format.
-func (p *Pod) PodList() (*List, error) {
-	return p.loadUserPods()
+func (p *Pod) PodList(ctx context.Context) (*List, error) {
+	return p.loadUserPods(ctx)
 }
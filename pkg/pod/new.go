@@ -17,6 +17,7 @@ limitations under the License.
 package pod
 
 import (
+	"context"
 	"encoding/hex"
 	"encoding/json"
 
@@ -35,14 +36,20 @@ const (
 )
 
 // CreatePod creates a new pod for a given user.
-func (p *Pod) CreatePod(podName, addressString, podPassword string) (*Info, error) {
+func (p *Pod) CreatePod(ctx context.Context, podName, addressString, podPassword string) (*Info, error) {
+	return p.CreatePodWithFeedType(ctx, podName, addressString, podPassword, feed.EpochFeed)
+}
+
+// CreatePodWithFeedType creates a new pod whose files and directories are kept in feeds
+// of the given type. The type of a pod can not be changed later.
+func (p *Pod) CreatePodWithFeedType(ctx context.Context, podName, addressString, podPassword string, feedType feed.Type) (*Info, error) {
 	podName, err := CleanPodName(podName)
 	if err != nil {
 		return nil, err
 	}
 
 	// check if pods is present and get free index
-	podList, err := p.loadUserPods()
+	podList, err := p.loadUserPods(ctx)
 	if err != nil { // skipcq: TCV-001
 		return nil, err
 	}
@@ -61,6 +68,8 @@ func (p *Pod) CreatePod(podName, addressString, podPassword string) (*Info, erro
 	var file *f.File
 	var dir *d.Directory
 	var user utils.Address
+	var ledger *pinLedger
+	client := p.client
 	if addressString != "" {
 		if p.checkIfPodPresent(podList, podName) {
 			return nil, ErrPodAlreadyExists
@@ -74,7 +83,7 @@ func (p *Pod) CreatePod(podName, addressString, podPassword string) (*Info, erro
 		address := utils.HexToAddress(addressString)
 		accountInfo.SetAddress(address)
 
-		fd = feed.New(accountInfo, p.client, p.logger)
+		fd = feed.NewWithType(accountInfo, p.client, feedType, p.logger)
 		file = f.NewFile(podName, p.client, fd, accountInfo.GetAddress(), p.tm, p.logger)
 		dir = d.NewDirectory(podName, p.client, fd, accountInfo.GetAddress(), file, p.tm, p.logger)
 
@@ -83,9 +92,10 @@ func (p *Pod) CreatePod(podName, addressString, podPassword string) (*Info, erro
 			Name:     podName,
 			Address:  addressString,
 			Password: podPassword,
+			FeedType: feedType,
 		}
 		podList.SharedPods = append(podList.SharedPods, *sharedPod)
-		err = p.storeUserPods(podList)
+		err = p.storeUserPods(ctx, podList)
 		if err != nil { // skipcq: TCV-001
 			return nil, err
 		}
@@ -113,9 +123,12 @@ func (p *Pod) CreatePod(podName, addressString, podPassword string) (*Info, erro
 			return nil, err
 		}
 
-		fd = feed.New(accountInfo, p.client, p.logger)
-		file = f.NewFile(podName, p.client, fd, accountInfo.GetAddress(), p.tm, p.logger)
-		dir = d.NewDirectory(podName, p.client, fd, accountInfo.GetAddress(), file, p.tm, p.logger)
+		fd = feed.NewWithType(accountInfo, p.client, feedType, p.logger)
+		// record the pins of the pod for the garbage collection
+		ledger = newPinLedger(p.client, fd, accountInfo.GetAddress(), podPassword, p.logger)
+		client = ledger
+		file = f.NewFile(podName, client, fd, accountInfo.GetAddress(), p.tm, p.logger)
+		dir = d.NewDirectory(podName, client, fd, accountInfo.GetAddress(), file, p.tm, p.logger)
 
 		// store the pod file
 		pods[freeId] = podName
@@ -123,17 +136,18 @@ func (p *Pod) CreatePod(podName, addressString, podPassword string) (*Info, erro
 			Name:     podName,
 			Index:    freeId,
 			Password: podPassword,
+			FeedType: feedType,
 		}
 		podList.Pods = append(podList.Pods, *pod)
-		err = p.storeUserPods(podList)
+		err = p.storeUserPods(ctx, podList)
 		if err != nil { // skipcq: TCV-001
 			return nil, err
 		}
 		user = p.acc.GetAddress(freeId)
 	}
 
-	kvStore := c.NewKeyValueStore(podName, fd, accountInfo, user, p.client, p.logger)
-	docStore := c.NewDocumentStore(podName, fd, accountInfo, user, file, p.tm, p.client, p.logger)
+	kvStore := c.NewKeyValueStore(podName, fd, accountInfo, user, client, p.logger)
+	docStore := c.NewDocumentStore(podName, fd, accountInfo, user, file, p.tm, client, p.logger)
 
 	// create the pod info and store it in the podMap
 	podInfo := &Info{
@@ -146,16 +160,17 @@ func (p *Pod) CreatePod(podName, addressString, podPassword string) (*Info, erro
 		feed:        fd,
 		kvStore:     kvStore,
 		docStore:    docStore,
+		ledger:      ledger,
 	}
 	p.addPodToPodMap(podName, podInfo)
 	return podInfo, nil
 }
 
-func (p *Pod) loadUserPods() (*List, error) {
+func (p *Pod) loadUserPods(ctx context.Context) (*List, error) {
 	// The userAddress pod file topic should be in the name of the userAddress account
 	topic := utils.HashString(podFile)
 	privKeyBytes := crypto.FromECDSA(p.acc.GetUserAccountInfo().GetPrivateKey())
-	_, data, err := p.fd.GetFeedData(topic, p.acc.GetAddress(account.UserAccountIndex), []byte(hex.EncodeToString(privKeyBytes)))
+	_, data, err := p.fd.GetFeedData(ctx, topic, p.acc.GetAddress(account.UserAccountIndex), []byte(hex.EncodeToString(privKeyBytes)))
 	if err != nil { // skipcq: TCV-001
 		if err.Error() != "feed does not exist or was not updated yet" {
 			return nil, err
@@ -177,7 +192,7 @@ func (p *Pod) loadUserPods() (*List, error) {
 	return podList, nil
 }
 
-func (p *Pod) storeUserPods(podList *List) error {
+func (p *Pod) storeUserPods(ctx context.Context, podList *List) error {
 	data, err := json.Marshal(podList)
 	if err != nil {
 		return err
@@ -189,7 +204,7 @@ func (p *Pod) storeUserPods(podList *List) error {
 	topic := utils.HashString(podFile)
 
 	privKeyBytes := crypto.FromECDSA(p.acc.GetUserAccountInfo().GetPrivateKey())
-	_, err = p.fd.UpdateFeed(topic, p.acc.GetAddress(account.UserAccountIndex), data, []byte(hex.EncodeToString(privKeyBytes)))
+	_, err = p.fd.UpdateFeed(ctx, topic, p.acc.GetAddress(account.UserAccountIndex), data, []byte(hex.EncodeToString(privKeyBytes)))
 	if err != nil { // skipcq: TCV-001
 		return err
 	}
@@ -227,8 +242,8 @@ func (*Pod) checkIfSharedPodPresent(pods *List, podName string) bool {
 	return false
 }
 
-func (p *Pod) getPodIndex(podName string) (podIndex int, err error) {
-	podList, err := p.loadUserPods()
+func (p *Pod) getPodIndex(ctx context.Context, podName string) (podIndex int, err error) {
+	podList, err := p.loadUserPods(ctx)
 	if err != nil {
 		return -1, err
 	} // skipcq: TCV-001
@@ -31,9 +31,9 @@ import (
 // OpenPod opens a pod if it is not already opened. as part of opening the pod
 // it loads all the data structures related to the pod. Also, it syncs all the
 // files and directories under this pod from the Swarm network.
-func (p *Pod) OpenPod(podName string) (*Info, error) {
+func (p *Pod) OpenPod(ctx context.Context, podName string) (*Info, error) {
 	// check if pods is present and get the index of the pod
-	podList, err := p.loadUserPods()
+	podList, err := p.loadUserPods(ctx)
 	if err != nil { // skipcq: TCV-001
 		return nil, err
 	}
@@ -52,7 +52,9 @@ func (p *Pod) OpenPod(podName string) (*Info, error) {
 		fd          *feed.API
 		dir         *d.Directory
 		user        utils.Address
+		ledger      *pinLedger
 	)
+	client := p.client
 	if sharedPodType {
 		var addressString string
 		addressString, podPassword = p.getAddressPassword(podList, podName)
@@ -64,7 +66,7 @@ func (p *Pod) OpenPod(podName string) (*Info, error) {
 		address := utils.HexToAddress(addressString)
 		accountInfo.SetAddress(address)
 
-		fd = feed.New(accountInfo, p.client, p.logger)
+		fd = feed.NewWithType(accountInfo, p.client, p.getFeedType(podList, podName), p.logger)
 		file = f.NewFile(podName, p.client, fd, accountInfo.GetAddress(), p.tm, p.logger)
 		dir = d.NewDirectory(podName, p.client, fd, accountInfo.GetAddress(), file, p.tm, p.logger)
 
@@ -83,15 +85,18 @@ func (p *Pod) OpenPod(podName string) (*Info, error) {
 			return nil, err
 		}
 
-		fd = feed.New(accountInfo, p.client, p.logger)
-		file = f.NewFile(podName, p.client, fd, accountInfo.GetAddress(), p.tm, p.logger)
-		dir = d.NewDirectory(podName, p.client, fd, accountInfo.GetAddress(), file, p.tm, p.logger)
+		fd = feed.NewWithType(accountInfo, p.client, p.getFeedType(podList, podName), p.logger)
+		// record the pins of the pod for the garbage collection
+		ledger = newPinLedger(p.client, fd, accountInfo.GetAddress(), podPassword, p.logger)
+		client = ledger
+		file = f.NewFile(podName, client, fd, accountInfo.GetAddress(), p.tm, p.logger)
+		dir = d.NewDirectory(podName, client, fd, accountInfo.GetAddress(), file, p.tm, p.logger)
 
 		user = p.acc.GetAddress(index)
 	}
 
-	kvStore := c.NewKeyValueStore(podName, fd, accountInfo, user, p.client, p.logger)
-	docStore := c.NewDocumentStore(podName, fd, accountInfo, user, file, p.tm, p.client, p.logger)
+	kvStore := c.NewKeyValueStore(podName, fd, accountInfo, user, client, p.logger)
+	docStore := c.NewDocumentStore(podName, fd, accountInfo, user, file, p.tm, client, p.logger)
 
 	// create the pod info and store it in the podMap
 	podInfo := &Info{
@@ -104,12 +109,13 @@ func (p *Pod) OpenPod(podName string) (*Info, error) {
 		file:        file,
 		kvStore:     kvStore,
 		docStore:    docStore,
+		ledger:      ledger,
 	}
 
 	p.addPodToPodMap(podName, podInfo)
 
 	// sync the pod's files and directories
-	err = p.SyncPod(podName)
+	err = p.SyncPod(ctx, podName)
 	if err != nil && err != d.ErrResourceDeleted { // skipcq: TCV-001
 		return nil, err
 	}
@@ -121,7 +127,7 @@ func (p *Pod) OpenPod(podName string) (*Info, error) {
 // files and directories under this pod from the Swarm network.
 func (p *Pod) OpenPodAsync(ctx context.Context, podName string) (*Info, error) {
 	// check if pods is present and get the index of the pod
-	podList, err := p.loadUserPods()
+	podList, err := p.loadUserPods(ctx)
 	if err != nil { // skipcq: TCV-001
 		return nil, err
 	}
@@ -142,7 +148,9 @@ func (p *Pod) OpenPodAsync(ctx context.Context, podName string) (*Info, error) {
 		fd          *feed.API
 		dir         *d.Directory
 		user        utils.Address
+		ledger      *pinLedger
 	)
+	client := p.client
 	if sharedPodType {
 		var addressString string
 		addressString, podPassword = p.getAddressPassword(podList, podName)
@@ -154,7 +162,7 @@ func (p *Pod) OpenPodAsync(ctx context.Context, podName string) (*Info, error) {
 		address := utils.HexToAddress(addressString)
 		accountInfo.SetAddress(address)
 
-		fd = feed.New(accountInfo, p.client, p.logger)
+		fd = feed.NewWithType(accountInfo, p.client, p.getFeedType(podList, podName), p.logger)
 		file = f.NewFile(podName, p.client, fd, accountInfo.GetAddress(), p.tm, p.logger)
 		dir = d.NewDirectory(podName, p.client, fd, accountInfo.GetAddress(), file, p.tm, p.logger)
 
@@ -173,15 +181,18 @@ func (p *Pod) OpenPodAsync(ctx context.Context, podName string) (*Info, error) {
 			return nil, err
 		}
 
-		fd = feed.New(accountInfo, p.client, p.logger)
-		file = f.NewFile(podName, p.client, fd, accountInfo.GetAddress(), p.tm, p.logger)
-		dir = d.NewDirectory(podName, p.client, fd, accountInfo.GetAddress(), file, p.tm, p.logger)
+		fd = feed.NewWithType(accountInfo, p.client, p.getFeedType(podList, podName), p.logger)
+		// record the pins of the pod for the garbage collection
+		ledger = newPinLedger(p.client, fd, accountInfo.GetAddress(), podPassword, p.logger)
+		client = ledger
+		file = f.NewFile(podName, client, fd, accountInfo.GetAddress(), p.tm, p.logger)
+		dir = d.NewDirectory(podName, client, fd, accountInfo.GetAddress(), file, p.tm, p.logger)
 
 		user = p.acc.GetAddress(index)
 	}
 
-	kvStore := c.NewKeyValueStore(podName, fd, accountInfo, user, p.client, p.logger)
-	docStore := c.NewDocumentStore(podName, fd, accountInfo, user, file, p.tm, p.client, p.logger)
+	kvStore := c.NewKeyValueStore(podName, fd, accountInfo, user, client, p.logger)
+	docStore := c.NewDocumentStore(podName, fd, accountInfo, user, file, p.tm, client, p.logger)
 
 	// create the pod info and store it in the podMap
 	podInfo := &Info{
@@ -194,6 +205,7 @@ func (p *Pod) OpenPodAsync(ctx context.Context, podName string) (*Info, error) {
 		file:        file,
 		kvStore:     kvStore,
 		docStore:    docStore,
+		ledger:      ledger,
 	}
 
 	p.addPodToPodMap(podName, podInfo)
@@ -214,6 +226,20 @@ func (*Pod) getIndexPassword(podList *List, podName string) (int, string) {
 	return -1, "" // skipcq: TCV-001
 }
 
+func (*Pod) getFeedType(podList *List, podName string) feed.Type {
+	for _, pod := range podList.Pods {
+		if pod.Name == podName {
+			return pod.FeedType
+		}
+	}
+	for _, pod := range podList.SharedPods {
+		if pod.Name == podName {
+			return pod.FeedType
+		}
+	}
+	return feed.EpochFeed // skipcq: TCV-001
+}
+
 func (*Pod) getAddressPassword(podList *List, podName string) (string, string) {
 	for _, pod := range podList.SharedPods {
 		if pod.Name == podName {
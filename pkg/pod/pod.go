@@ -47,16 +47,18 @@ type Pod struct {
 
 // ListItem defines the structure for pod item
 type ListItem struct {
-	Name     string `json:"name"`
-	Index    int    `json:"index"`
-	Password string `json:"password"`
+	Name     string    `json:"name"`
+	Index    int       `json:"index"`
+	Password string    `json:"password"`
+	FeedType feed.Type `json:"feedType,omitempty"`
 }
 
 // SharedListItem defines the structure for shared pod item
 type SharedListItem struct {
-	Name     string `json:"name"`
-	Address  string `json:"address"`
-	Password string `json:"password"`
+	Name     string    `json:"name"`
+	Address  string    `json:"address"`
+	Password string    `json:"password"`
+	FeedType feed.Type `json:"feedType,omitempty"`
 }
 
 // List lists all the pods
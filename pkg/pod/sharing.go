@@ -17,27 +17,30 @@ limitations under the License.
 package pod
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 
+	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
 )
 
 // ShareInfo
 type ShareInfo struct {
-	PodName     string `json:"podName"`
-	Address     string `json:"podAddress"`
-	Password    string `json:"password"`
-	UserAddress string `json:"userAddress"`
+	PodName     string    `json:"podName"`
+	Address     string    `json:"podAddress"`
+	Password    string    `json:"password"`
+	UserAddress string    `json:"userAddress"`
+	FeedType    feed.Type `json:"feedType,omitempty"`
 }
 
 // PodShare makes a pod public by exporting all the pod related information and its
 // address. it does this by creating a sharing reference which points to the information
 // required to import this pod.
-func (p *Pod) PodShare(podName, sharedPodName string) (string, error) {
+func (p *Pod) PodShare(ctx context.Context, podName, sharedPodName string) (string, error) {
 	// check if pods is present and get the index of the pod
-	podList, err := p.loadUserPods()
+	podList, err := p.loadUserPods(ctx)
 	if err != nil { // skipcq: TCV-001
 		return "", err
 	}
@@ -66,13 +69,14 @@ func (p *Pod) PodShare(podName, sharedPodName string) (string, error) {
 		Password:    podPassword,
 		Address:     address.String(),
 		UserAddress: userAddress.String(),
+		FeedType:    p.getFeedType(podList, podName),
 	}
 
 	data, err := json.Marshal(shareInfo)
 	if err != nil { // skipcq: TCV-001
 		return "", err
 	}
-	ref, err := p.client.UploadBlob(data, 0, true, true)
+	ref, err := p.client.UploadBlob(ctx, data, 0, true, true)
 	if err != nil { // skipcq: TCV-001
 		return "", err
 	}
@@ -82,8 +86,8 @@ func (p *Pod) PodShare(podName, sharedPodName string) (string, error) {
 }
 
 // ReceivePodInfo
-func (p *Pod) ReceivePodInfo(ref utils.Reference) (*ShareInfo, error) {
-	data, resp, err := p.client.DownloadBlob(ref.Bytes())
+func (p *Pod) ReceivePodInfo(ctx context.Context, ref utils.Reference) (*ShareInfo, error) {
+	data, resp, err := p.client.DownloadBlob(ctx, ref.Bytes())
 	if err != nil { // skipcq: TCV-001
 		return nil, err
 	}
@@ -103,8 +107,8 @@ func (p *Pod) ReceivePodInfo(ref utils.Reference) (*ShareInfo, error) {
 }
 
 // ReceivePod
-func (p *Pod) ReceivePod(sharedPodName string, ref utils.Reference) (*Info, error) {
-	data, resp, err := p.client.DownloadBlob(ref.Bytes())
+func (p *Pod) ReceivePod(ctx context.Context, sharedPodName string, ref utils.Reference) (*Info, error) {
+	data, resp, err := p.client.DownloadBlob(ctx, ref.Bytes())
 	if err != nil { // skipcq: TCV-001
 		return nil, err
 	}
@@ -120,5 +124,5 @@ func (p *Pod) ReceivePod(sharedPodName string, ref utils.Reference) (*Info, erro
 	if sharedPodName != "" {
 		shareInfo.PodName = sharedPodName
 	}
-	return p.CreatePod(shareInfo.PodName, shareInfo.Address, shareInfo.Password)
+	return p.CreatePodWithFeedType(ctx, shareInfo.PodName, shareInfo.Address, shareInfo.Password, shareInfo.FeedType)
 }
@@ -20,6 +20,7 @@ package pod
 type Stat struct {
 	PodName    string `json:"podName"`
 	PodAddress string `json:"address"`
+	FeedType   string `json:"feedType"`
 }
 
 // PodStat shows all the pod related information like podname and its current address.
@@ -31,5 +32,6 @@ func (p *Pod) PodStat(podName string) (*Stat, error) {
 	return &Stat{
 		PodName:    podInfo.GetPodName(),
 		PodAddress: podInfo.userAddress.String(),
+		FeedType:   podInfo.feed.Type().String(),
 	}, nil
 }
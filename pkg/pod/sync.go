@@ -23,7 +23,7 @@ import (
 
 // SyncPod syncs the pod to the latest version by extracting the current meta information
 // of files and directories of the pod.
-func (p *Pod) SyncPod(podName string) error {
+func (p *Pod) SyncPod(ctx context.Context, podName string) error {
 	podName, err := CleanPodName(podName)
 	if err != nil { // skipcq: TCV-001
 		return err
@@ -39,7 +39,7 @@ func (p *Pod) SyncPod(podName string) error {
 	}
 
 	// sync from the root directory
-	err = podInfo.GetDirectory().SyncDirectory("/", podInfo.GetPodPassword())
+	err = podInfo.GetDirectory().SyncDirectory(ctx, "/", podInfo.GetPodPassword())
 	if err != nil {
 		return err
 	}
@@ -17,6 +17,7 @@ limitations under the License.
 package pod
 
 import (
+	"context"
 	"strings"
 
 	"github.com/fairdatasociety/fairOS-dfs/pkg/account"
@@ -35,13 +36,13 @@ func (p *Pod) IsPodOpened(podName string) bool {
 }
 
 // IsPodPresent checks if a pod is already present for user
-func (p *Pod) IsPodPresent(podName string) bool {
+func (p *Pod) IsPodPresent(ctx context.Context, podName string) bool {
 	podName, err := CleanPodName(podName)
 	if err != nil {
 		return false
 	}
 	// check if pods is present and get free index
-	podList, err := p.loadUserPods()
+	podList, err := p.loadUserPods(ctx)
 	if err != nil { // skipcq: TCV-001
 		return false
 	}
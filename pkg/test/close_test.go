@@ -34,6 +34,7 @@ import (
 )
 
 func TestClose(t *testing.T) {
+	ctx := context.Background()
 	mockClient := mock.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -54,13 +55,13 @@ func TestClose(t *testing.T) {
 	t.Run("close-pod", func(t *testing.T) {
 		// create a pod
 		podPassword, _ := utils.GetRandString(pod.PasswordLength)
-		info, err := pod1.CreatePod(podName1, "", podPassword)
+		info, err := pod1.CreatePod(ctx, podName1, "", podPassword)
 		if err != nil {
 			t.Fatalf("error creating pod %s", podName1)
 		}
 
 		// make root dir so that other directories can be added
-		err = info.GetDirectory().MkRootDir("pod1", podPassword, info.GetPodAddress(), info.GetFeed())
+		err = info.GetDirectory().MkRootDir(ctx, "pod1", podPassword, info.GetPodAddress(), info.GetFeed())
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -0,0 +1,192 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/account"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/bee/mock"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/file"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/pod"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+	"github.com/plexsysio/taskmanager"
+)
+
+func TestCopy(t *testing.T) {
+	ctx := context.Background()
+	mockClient := mock.NewMockBeeClient()
+	logger := logging.New(io.Discard, 0)
+	acc := account.New(logger)
+	_, _, err := acc.CreateUserAccount("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tm := taskmanager.New(1, 10, time.Second*15, logger)
+	defer func() {
+		_ = tm.Stop(context.Background())
+	}()
+	fd := feed.New(acc.GetUserAccountInfo(), mockClient, logger)
+	pod1 := pod.NewPod(mockClient, fd, acc, tm, logger)
+	podName1 := "test1"
+	podName2 := "test2"
+
+	gracePeriod := pod.GCGracePeriod
+	pod.GCGracePeriod = 0
+	defer func() {
+		pod.GCGracePeriod = gracePeriod
+	}()
+
+	podPassword, _ := utils.GetRandString(pod.PasswordLength)
+	info, err := pod1.CreatePod(ctx, podName1, "", podPassword)
+	if err != nil {
+		t.Fatalf("error creating pod %s", podName1)
+	}
+	err = info.GetDirectory().MkRootDir(ctx, "pod1", podPassword, info.GetPodAddress(), info.GetFeed())
+	if err != nil {
+		t.Fatal(err)
+	}
+	addFilesAndDirectories(t, info, pod1, podName1, podPassword)
+	info, err = pod1.OpenPod(ctx, podName1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = info.GetFile().SetXattr(ctx, "/parentDir/file1", podPassword, "user.tag", "red")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = info.GetDirectory().Symlink(ctx, "subDir1", "/parentDir/latest", podPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	podPassword2, _ := utils.GetRandString(pod.PasswordLength)
+	info2, err := pod1.CreatePod(ctx, podName2, "", podPassword2)
+	if err != nil {
+		t.Fatalf("error creating pod %s", podName2)
+	}
+	err = info2.GetDirectory().MkRootDir(ctx, "pod2", podPassword2, info2.GetPodAddress(), info2.GetFeed())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("copy-file", func(t *testing.T) {
+		content := readFile(t, info.GetFile(), "/parentDir/file1", podPassword)
+		err := pod.CopyFile(ctx, info, info, "/parentDir/file1", "/parentDir/file1-copy")
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = pod.CopyFile(ctx, info, info, "/parentDir/file1", "/parentDir/file1-copy")
+		if !errors.Is(err, file.ErrFileAlreadyPresent) {
+			t.Fatalf("copy over an existing file: %v", err)
+		}
+		xattrs, err := info.GetFile().Xattrs("/parentDir/file1-copy")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if xattrs["user.tag"] != "red" {
+			t.Fatalf("unexpected xattrs %v", xattrs)
+		}
+
+		// the copy still has its content when the original is removed
+		err = info.GetFile().RmFile(ctx, "/parentDir/file1", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = info.GetDirectory().RemoveEntryFromDir(ctx, "/parentDir", podPassword, "file1", true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(readFile(t, info.GetFile(), "/parentDir/file1-copy", podPassword), content) {
+			t.Fatal("copy content mismatch")
+		}
+	})
+
+	t.Run("copy-dir", func(t *testing.T) {
+		err := pod.CopyDirectory(ctx, info, info, "/parentDir", "/parentDir/subDir1/copy")
+		if !errors.Is(err, pod.ErrCopyIntoItself) {
+			t.Fatalf("copy into itself: %v", err)
+		}
+		err = pod.CopyDirectory(ctx, info, info, "/parentDir", "/copyDir")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.GetDirectory().GetDirFromDirectoryMap("/copyDir/subDir2") == nil {
+			t.Fatal("sub directory not copied")
+		}
+		resolved, err := info.GetDirectory().ResolvePath("/copyDir/latest")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resolved != "/copyDir/subDir1" {
+			t.Fatalf("link resolved to %s", resolved)
+		}
+		if !bytes.Equal(readFile(t, info.GetFile(), "/copyDir/file2", podPassword), readFile(t, info.GetFile(), "/parentDir/file2", podPassword)) {
+			t.Fatal("copy content mismatch")
+		}
+	})
+
+	t.Run("copy-across-pods", func(t *testing.T) {
+		content := readFile(t, info.GetFile(), "/parentDir/file2", podPassword)
+		err := pod.CopyFile(ctx, info, info2, "/parentDir/file2", "/file2")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(readFile(t, info2.GetFile(), "/file2", podPassword2), content) {
+			t.Fatal("copy content mismatch before gc")
+		}
+
+		// the source pod keeps the blocks the other pod uses
+		for _, path := range []string{"/parentDir/file2", "/copyDir/file2"} {
+			err = info.GetFile().RmFile(ctx, path, podPassword)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		report, err := pod1.CollectGarbage(ctx, podName1, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.Exported == 0 {
+			t.Fatalf("unexpected report %+v", report)
+		}
+		if !bytes.Equal(readFile(t, info2.GetFile(), "/file2", podPassword2), content) {
+			t.Fatal("copy content mismatch")
+		}
+	})
+}
+
+func readFile(t *testing.T, fileObject *file.File, podFileWithPath, podPassword string) []byte {
+	t.Helper()
+	reader, _, err := fileObject.Download(context.Background(), podFileWithPath, podPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
@@ -37,6 +37,7 @@ import (
 )
 
 func TestPodDelete(t *testing.T) {
+	ctx := context.Background()
 	mockClient := mock.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -58,12 +59,12 @@ func TestPodDelete(t *testing.T) {
 
 	t.Run("create-one-pod-and-del", func(t *testing.T) {
 		podPassword, _ := utils.GetRandString(pod.PasswordLength)
-		_, err := pod1.CreatePod(podName1, "", podPassword)
+		_, err := pod1.CreatePod(ctx, podName1, "", podPassword)
 		if err != nil {
 			t.Fatalf("error creating pod %s", podName1)
 		}
 
-		pods, _, err := pod1.ListPods()
+		pods, _, err := pod1.ListPods(ctx)
 		if err != nil {
 			t.Fatalf("error getting pods")
 		}
@@ -72,17 +73,17 @@ func TestPodDelete(t *testing.T) {
 			t.Fatalf("podName is not %s", podName1)
 		}
 
-		err = pod1.DeleteOwnPod(podName1)
+		err = pod1.DeleteOwnPod(ctx, podName1)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		err = pod1.DeleteOwnPod(podName1)
+		err = pod1.DeleteOwnPod(ctx, podName1)
 		if err == nil {
 			t.Fatal("pod should have been deleted")
 		}
 
-		pods, _, err = pod1.ListPods()
+		pods, _, err = pod1.ListPods(ctx)
 		if err != nil {
 			t.Fatalf("error getting pods")
 		}
@@ -102,16 +103,16 @@ func TestPodDelete(t *testing.T) {
 
 	t.Run("create-two-pod-and-del", func(t *testing.T) {
 		podPassword, _ := utils.GetRandString(pod.PasswordLength)
-		_, err := pod1.CreatePod(podName1, "", podPassword)
+		_, err := pod1.CreatePod(ctx, podName1, "", podPassword)
 		if err != nil {
 			t.Fatalf("error creating pod %s", podName1)
 		}
-		_, err = pod1.CreatePod(podName2, "", podPassword)
+		_, err = pod1.CreatePod(ctx, podName2, "", podPassword)
 		if err != nil {
 			t.Fatalf("error creating pod %s", podName1)
 		}
 
-		pods, _, err := pod1.ListPods()
+		pods, _, err := pod1.ListPods(ctx)
 		if err != nil {
 			t.Fatalf("error getting pods")
 		}
@@ -125,12 +126,12 @@ func TestPodDelete(t *testing.T) {
 			t.Fatalf("podName is not %s, got %s", podName2, pods[1])
 		}
 
-		err = pod1.DeleteOwnPod(podName1)
+		err = pod1.DeleteOwnPod(ctx, podName1)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		pods, _, err = pod1.ListPods()
+		pods, _, err = pod1.ListPods(ctx)
 		if err != nil {
 			t.Fatalf("error getting pods")
 		}
@@ -162,18 +163,18 @@ func TestPodDelete(t *testing.T) {
 		podName := "delPod"
 		for i := 0; i < 10; i++ {
 			podPassword, _ := utils.GetRandString(pod.PasswordLength)
-			pi, err := pod1.CreatePod(podName, "", podPassword)
+			pi, err := pod1.CreatePod(ctx, podName, "", podPassword)
 			if err != nil {
 				t.Fatalf("error creating pod %s", podName)
 			}
-			dbTables, err := pi.GetDocStore().LoadDocumentDBSchemas(podPassword)
+			dbTables, err := pi.GetDocStore().LoadDocumentDBSchemas(ctx, podPassword)
 			if err != nil {
 				t.Fatalf("err doc list %s", podName)
 			}
 			if len(dbTables) != 0 {
 				t.Fatal("doc tables delete failed while pod delete")
 			}
-			kvTables, err := pi.GetKVStore().LoadKVTables(podPassword)
+			kvTables, err := pi.GetKVStore().LoadKVTables(ctx, podPassword)
 			if err != nil {
 				t.Fatalf("err kv list %s", podName)
 			}
@@ -183,24 +184,24 @@ func TestPodDelete(t *testing.T) {
 			si := make(map[string]collection.IndexType)
 			si["first_name"] = collection.StringIndex
 			si["age"] = collection.NumberIndex
-			err = pi.GetDocStore().CreateDocumentDB("dbName", podPassword, si, true)
+			err = pi.GetDocStore().CreateDocumentDB(ctx, "dbName", podPassword, si, true)
 			if err != nil {
 				t.Fatal(err)
 			}
 
-			err = pi.GetKVStore().CreateKVTable("kvName", podPassword, collection.StringIndex)
+			err = pi.GetKVStore().CreateKVTable(ctx, "kvName", podPassword, collection.StringIndex)
 			if err != nil {
 				t.Fatal(err)
 			}
 
-			dbTables, err = pi.GetDocStore().LoadDocumentDBSchemas(podPassword)
+			dbTables, err = pi.GetDocStore().LoadDocumentDBSchemas(ctx, podPassword)
 			if err != nil {
 				t.Fatalf("err doc list %s", podName)
 			}
 			if len(dbTables) != 1 {
 				t.Fatal("doc tables create failed while pod delete")
 			}
-			kvTables, err = pi.GetKVStore().LoadKVTables(podPassword)
+			kvTables, err = pi.GetKVStore().LoadKVTables(ctx, podPassword)
 			if err != nil {
 				t.Fatalf("err kv list %s", podName)
 			}
@@ -208,7 +209,7 @@ func TestPodDelete(t *testing.T) {
 				t.Fatal("kv tables create failed while pod delete")
 			}
 
-			err = pod1.DeleteOwnPod(podName)
+			err = pod1.DeleteOwnPod(ctx, podName)
 			if err != nil {
 				t.Fatal(err)
 			}
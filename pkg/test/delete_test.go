@@ -32,6 +32,7 @@ import (
 )
 
 func TestDelete(t *testing.T) {
+	ctx := context.Background()
 	mockClient := mock.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	tm := taskmanager.New(1, 10, time.Second*15, logger)
@@ -43,22 +44,22 @@ func TestDelete(t *testing.T) {
 		ens := mock2.NewMockNamespaceManager()
 		// create user
 		userObject := user.NewUsers(mockClient, ens, logger)
-		_, _, _, _, ui, err := userObject.CreateNewUserV2("user1", "password1twelve", "", "", tm)
+		_, _, _, _, ui, err := userObject.CreateNewUserV2(ctx, "user1", "password1twelve", "", "", tm)
 		if err != nil {
 			t.Fatal(err)
 		}
 		// delete user with wrong password
-		err = userObject.DeleteUserV2("user1", "password11", ui.GetSessionId(), ui)
+		err = userObject.DeleteUserV2(ctx, "user1", "password11", ui.GetSessionId(), ui)
 		if err == nil {
 			t.Fatal("delete should fail")
 		}
 		// delete user invalid sessionId
-		err = userObject.DeleteUserV2("user1", "password1", "invalid_session", ui)
+		err = userObject.DeleteUserV2(ctx, "user1", "password1", "invalid_session", ui)
 		if !errors.Is(err, user.ErrUserNotLoggedIn) {
 			t.Fatal(err)
 		}
 		// delete user
-		err = userObject.DeleteUserV2("user1", "password1twelve", ui.GetSessionId(), ui)
+		err = userObject.DeleteUserV2(ctx, "user1", "password1twelve", ui.GetSessionId(), ui)
 		if err != nil {
 			t.Fatal(err)
 		}
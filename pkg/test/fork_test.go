@@ -32,6 +32,7 @@ import (
 )
 
 func TestFork(t *testing.T) {
+	ctx := context.Background()
 	mockClient := mock.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -50,12 +51,12 @@ func TestFork(t *testing.T) {
 	t.Run("fork-pod", func(t *testing.T) {
 		// create a pod
 		podPassword, _ := utils.GetRandString(pod.PasswordLength)
-		info, err := pod1.CreatePod(podName1, "", podPassword)
+		info, err := pod1.CreatePod(ctx, podName1, "", podPassword)
 		if err != nil {
 			t.Fatalf("error creating pod %s", podName1)
 		}
 		// make root dir so that other directories can be added
-		err = info.GetDirectory().MkRootDir("pod1", podPassword, info.GetPodAddress(), info.GetFeed())
+		err = info.GetDirectory().MkRootDir(ctx, "pod1", podPassword, info.GetPodAddress(), info.GetFeed())
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -64,37 +65,52 @@ func TestFork(t *testing.T) {
 		addFilesAndDirectories(t, info, pod1, podName1, podPassword)
 
 		// open the pod
-		_, err = pod1.OpenPod(podName1)
+		info, err = pod1.OpenPod(ctx, podName1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the extended attributes are forked too
+		err = info.GetDirectory().SetXattr(ctx, "/parentDir/subDir1", podPassword, "user.tag", "blue")
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = info.GetFile().SetXattr(ctx, "/parentDir/file1", podPassword, "user.tag", "red")
+		if err != nil {
+			t.Fatal(err)
+		}
+		// and so are the symbolic links
+		err = info.GetDirectory().Symlink(ctx, "subDir1", "/parentDir/latest", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// create fork pod
 		forkName := "pod1fork"
-		_, err = pod1.CreatePod(forkName, "", podPassword)
+		_, err = pod1.CreatePod(ctx, forkName, "", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// open fork pod
-		pi, err := pod1.OpenPod(forkName)
+		pi, err := pod1.OpenPod(ctx, forkName)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// create the root directory
-		err = pi.GetDirectory().MkRootDir(pi.GetPodName(), podPassword, pi.GetPodAddress(), pi.GetFeed())
+		err = pi.GetDirectory().MkRootDir(ctx, pi.GetPodName(), podPassword, pi.GetPodAddress(), pi.GetFeed())
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		err = pod1.PodFork(podName1, forkName)
+		err = pod1.PodFork(ctx, podName1, forkName)
 		if err != nil {
 			t.Fatal(err)
 
 		}
 		// open the pod ths triggers sync too
-		gotInfo, err := pod1.OpenPod(forkName)
+		gotInfo, err := pod1.OpenPod(ctx, forkName)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -111,6 +127,16 @@ func TestFork(t *testing.T) {
 		if dirInode1.Meta.Name != "subDir1" {
 			t.Fatalf("invalid dir entry")
 		}
+		if dirInode1.Meta.Xattrs["user.tag"] != "blue" {
+			t.Fatalf("invalid dir xattrs")
+		}
+		resolved, err := dirObject.ResolvePath("/parentDir/latest")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resolved != "/parentDir/subDir1" {
+			t.Fatalf("invalid link")
+		}
 		dirInode2 := dirObject.GetDirFromDirectoryMap("/parentDir/subDir2")
 		if dirInode2 == nil {
 			t.Fatalf("invalid dir entry")
@@ -139,6 +165,9 @@ func TestFork(t *testing.T) {
 		if fileMeta1.BlockSize != uint32(10) {
 			t.Fatalf("invalid block size")
 		}
+		if fileMeta1.Xattrs["user.tag"] != "red" {
+			t.Fatalf("invalid file xattrs")
+		}
 		fileMeta2 := fileObject.GetFromFileMap("/parentDir/file2")
 		if fileMeta2 == nil {
 			t.Fatalf("invalid file meta")
@@ -0,0 +1,288 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/account"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/local"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/collection"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/file"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/pod"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+	"github.com/plexsysio/taskmanager"
+)
+
+func TestGC(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.New(io.Discard, 0)
+	client, err := local.NewClient(t.TempDir(), logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acc := account.New(logger)
+	_, _, err = acc.CreateUserAccount("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tm := taskmanager.New(1, 10, time.Second*15, logger)
+	defer func() {
+		_ = tm.Stop(context.Background())
+	}()
+	fd := feed.New(acc.GetUserAccountInfo(), client, logger)
+	pod1 := pod.NewPod(client, fd, acc, tm, logger)
+	podName1 := "test1"
+
+	gracePeriod := pod.GCGracePeriod
+	pod.GCGracePeriod = 0
+	defer func() {
+		pod.GCGracePeriod = gracePeriod
+	}()
+
+	_, err = pod1.CollectGarbage(ctx, podName1, true)
+	if err == nil {
+		t.Fatal("gc should fail, pod not opened")
+	}
+
+	podPassword, _ := utils.GetRandString(pod.PasswordLength)
+	info, err := pod1.CreatePod(ctx, podName1, "", podPassword)
+	if err != nil {
+		t.Fatalf("error creating pod %s", podName1)
+	}
+	err = info.GetDirectory().MkRootDir(ctx, "pod1", podPassword, info.GetPodAddress(), info.GetFeed())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// closes the pod, which writes the recorded pins
+	addFilesAndDirectories(t, info, pod1, podName1, podPassword)
+	info, err = pod1.OpenPod(ctx, podName1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// overwriting a value leaves the old value and the old manifests unreferenced
+	kvStore := info.GetKVStore()
+	err = kvStore.CreateKVTable(ctx, "kv", podPassword, collection.BytesIndex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = kvStore.OpenKVTable(ctx, "kv", podPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, value := range []string{"value1", "value2"} {
+		err = kvStore.KVPut(ctx, "kv", "key", []byte(value))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("dry-run", func(t *testing.T) {
+		report, err := pod1.CollectGarbage(ctx, podName1, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(report.Garbage) == 0 {
+			t.Fatal("expected garbage after overwriting a value")
+		}
+		if report.Recorded != report.Reachable+len(report.Garbage) {
+			t.Fatalf("unexpected report %+v", report)
+		}
+		if report.Unpinned != 0 {
+			t.Fatal("dry run should not unpin")
+		}
+		for _, ref := range report.Garbage {
+			addr, _ := hex.DecodeString(ref)
+			if !client.IsPinned(addr) {
+				t.Fatalf("dry run unpinned %s", ref)
+			}
+		}
+	})
+
+	t.Run("collect", func(t *testing.T) {
+		report, err := pod1.CollectGarbage(ctx, podName1, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.Unpinned != len(report.Garbage) || report.Failed != 0 {
+			t.Fatalf("unexpected report %+v", report)
+		}
+		for _, ref := range report.Garbage {
+			addr, _ := hex.DecodeString(ref)
+			if client.IsPinned(addr) {
+				t.Fatalf("%s is still pinned", ref)
+			}
+		}
+
+		// the live content is still readable
+		_, value, err := kvStore.KVGet(ctx, "kv", "key")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(value, []byte("value2")) {
+			t.Fatalf("unexpected value %s", value)
+		}
+		reader, _, err := info.GetFile().Download(ctx, "/parentDir/file1", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(data) != 100 {
+			t.Fatalf("unexpected file size %d", len(data))
+		}
+
+		// nothing is left to collect
+		report, err = pod1.CollectGarbage(ctx, podName1, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(report.Garbage) != 0 || report.Recorded != report.Reachable {
+			t.Fatalf("unexpected report after gc %+v", report)
+		}
+	})
+
+	t.Run("upload-session", func(t *testing.T) {
+		fileObject := info.GetFile()
+		content := make([]byte, 300)
+		_, err := rand.Read(content)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, err := fileObject.CreateUploadSession(ctx, "file3", int64(len(content)), 100, "/parentDir", "", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, err = fileObject.UploadPart(ctx, s.ID, 0, bytes.NewReader(content[:200]), podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the blocks of the unfinished upload are kept
+		_, err = pod1.CollectGarbage(ctx, podName1, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, i := range s.StoredBlocks() {
+			if !client.IsPinned(s.Blocks[i].Reference.Bytes()) {
+				t.Fatalf("block %d of the upload session was unpinned", i)
+			}
+		}
+		_, err = fileObject.UploadPart(ctx, s.ID, 200, bytes.NewReader(content[200:]), podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = fileObject.FinishUploadSession(ctx, s.ID, podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader, _, err := fileObject.Download(ctx, "/parentDir/file3", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, content) {
+			t.Fatal("file content mismatch after gc")
+		}
+	})
+
+	t.Run("versions", func(t *testing.T) {
+		fileObject := info.GetFile()
+		err := fileObject.SetVersionPolicy(ctx, file.VersionPolicy{Enabled: true}, podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		first := []byte("the content that is kept as a version")
+		err = fileObject.Upload(ctx, bytes.NewReader(first), "file4", int64(len(first)), 100, "/parentDir", "", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		second := []byte("the current content")
+		err = fileObject.Upload(ctx, bytes.NewReader(second), "file4", int64(len(second)), 100, "/parentDir", "", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = info.GetDirectory().AddEntryToDir(ctx, "/parentDir", podPassword, "file4", true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		versions, err := fileObject.ListVersions(ctx, "/parentDir/file4", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(versions) != 1 {
+			t.Fatalf("expected one version, got %d", len(versions))
+		}
+
+		// the inode of the version is kept
+		_, err = pod1.CollectGarbage(ctx, podName1, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !client.IsPinned(versions[0].InodeAddress) {
+			t.Fatal("inode of the version was unpinned")
+		}
+		reader, _, err := fileObject.DownloadVersion(ctx, "/parentDir/file4", versions[0].Version, podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, first) {
+			t.Fatal("version content mismatch after gc")
+		}
+	})
+
+	t.Run("rm-file", func(t *testing.T) {
+		fileObject := info.GetFile()
+		content := []byte("a file that is removed")
+		err := fileObject.Upload(ctx, bytes.NewReader(content), "file5", int64(len(content)), 100, "/parentDir", "", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// file1 is recorded in the ledger chain, file5 is still pending
+		for _, name := range []string{"/parentDir/file1", "/parentDir/file5"} {
+			meta := fileObject.GetFromFileMap(name)
+			if meta == nil {
+				t.Fatalf("%s not found", name)
+			}
+			err = fileObject.RmFile(ctx, name, podPassword)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if client.IsPinned(meta.InodeAddress) {
+				t.Fatalf("inode of %s uploaded by the pod is still pinned", name)
+			}
+		}
+	})
+}
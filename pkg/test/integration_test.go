@@ -1,6 +1,7 @@
 package test_test
 
 import (
+	"context"
 	"crypto/rand"
 	"io"
 	"math/big"
@@ -32,6 +33,7 @@ func randStringRunes(n int) string {
 }
 
 func TestLiteUser(t *testing.T) {
+	ctx := context.Background()
 	mockClient := mock.NewMockBeeClient()
 	ens := mock2.NewMockNamespaceManager()
 	logger := logging.New(io.Discard, logrus.ErrorLevel)
@@ -57,7 +59,7 @@ func TestLiteUser(t *testing.T) {
 			PodName: randStringRunes(16),
 		}
 
-		_, err = dfsApi.CreatePod(podRequest.PodName, sessionId)
+		_, err = dfsApi.CreatePod(ctx, podRequest.PodName, sessionId)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -125,13 +127,13 @@ func TestLiteUser(t *testing.T) {
 		for _, v := range entries {
 			if v.isDir {
 
-				err = dfsApi.Mkdir(podRequest.PodName, v.path, sessionId)
+				err = dfsApi.Mkdir(ctx, podRequest.PodName, v.path, sessionId)
 				if err != nil {
 					t.Fatal(err)
 				}
 			} else {
 				reader := &io.LimitedReader{R: rand.Reader, N: v.size}
-				err = dfsApi.UploadFile(podRequest.PodName, filepath.Base(v.path), sessionId, v.size, reader, filepath.Dir(v.path), "", 100000, false)
+				err = dfsApi.UploadFile(ctx, podRequest.PodName, filepath.Base(v.path), sessionId, v.size, reader, filepath.Dir(v.path), "", 100000, false)
 				if err != nil {
 					t.Fatal(err)
 				}
@@ -140,13 +142,13 @@ func TestLiteUser(t *testing.T) {
 
 		for _, v := range entries {
 			if v.isDir {
-				_, err := dfsApi.DirectoryStat(podRequest.PodName, v.path, sessionId)
+				_, err := dfsApi.DirectoryStat(ctx, podRequest.PodName, v.path, sessionId)
 				if err != nil {
 					t.Fatal(err)
 
 				}
 			} else {
-				_, err := dfsApi.FileStat(podRequest.PodName, v.path, sessionId)
+				_, err := dfsApi.FileStat(ctx, podRequest.PodName, v.path, sessionId)
 				if err != nil {
 					t.Fatal(err)
 
@@ -190,12 +192,12 @@ func TestLiteUser(t *testing.T) {
 		}
 		for _, v := range renames {
 			if v.isDir {
-				err = dfsApi.RenameDir(podRequest.PodName, v.oldPath, v.newPath, sessionId)
+				err = dfsApi.RenameDir(ctx, podRequest.PodName, v.oldPath, v.newPath, sessionId)
 				if err != nil {
 					t.Fatal(err)
 				}
 			} else {
-				err = dfsApi.RenameFile(podRequest.PodName, v.oldPath, v.newPath, sessionId)
+				err = dfsApi.RenameFile(ctx, podRequest.PodName, v.oldPath, v.newPath, sessionId)
 				if err != nil {
 					t.Fatal(err)
 				}
@@ -263,13 +265,13 @@ func TestLiteUser(t *testing.T) {
 		}
 		for _, v := range newEntries {
 			if v.isDir {
-				_, err := dfsApi.DirectoryStat(podRequest.PodName, v.path, sessionId)
+				_, err := dfsApi.DirectoryStat(ctx, podRequest.PodName, v.path, sessionId)
 				if err != nil {
 					t.Fatal(err)
 
 				}
 			} else {
-				_, err := dfsApi.FileStat(podRequest.PodName, v.path, sessionId)
+				_, err := dfsApi.FileStat(ctx, podRequest.PodName, v.path, sessionId)
 				if err != nil {
 					t.Fatal(err)
 
@@ -292,19 +294,19 @@ func TestLiteUser(t *testing.T) {
 
 			sessionId = ui.GetSessionId()
 
-			_, err = dfsApi.OpenPod(podRequest.PodName, sessionId)
+			_, err = dfsApi.OpenPod(ctx, podRequest.PodName, sessionId)
 			if err != nil {
 				t.Fatal(err)
 			}
 			for _, v := range newEntries {
 				if v.isDir {
-					_, err := dfsApi.DirectoryStat(podRequest.PodName, v.path, sessionId)
+					_, err := dfsApi.DirectoryStat(ctx, podRequest.PodName, v.path, sessionId)
 					if err != nil {
 						t.Fatal(err)
 
 					}
 				} else {
-					_, err := dfsApi.FileStat(podRequest.PodName, v.path, sessionId)
+					_, err := dfsApi.FileStat(ctx, podRequest.PodName, v.path, sessionId)
 					if err != nil {
 						t.Fatal(err)
 
@@ -34,6 +34,7 @@ import (
 )
 
 func TestLogin(t *testing.T) {
+	ctx := context.Background()
 	mockClient := mock.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	tm := taskmanager.New(1, 10, time.Second*15, logger)
@@ -45,7 +46,7 @@ func TestLogin(t *testing.T) {
 		ens := mock2.NewMockNamespaceManager()
 		// create user
 		userObject := user.NewUsers(mockClient, ens, logger)
-		_, _, _, _, ui, err := userObject.CreateNewUserV2("7e4567e7cb003804992eef11fd5c757275a4c", "password1twelve", "", "", tm)
+		_, _, _, _, ui, err := userObject.CreateNewUserV2(ctx, "7e4567e7cb003804992eef11fd5c757275a4c", "password1twelve", "", "", tm)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -56,18 +57,18 @@ func TestLogin(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		_, _, _, err = userObject.LoginUserV2("not_an_username", "password1", mockClient, tm, "")
+		_, _, _, err = userObject.LoginUserV2(ctx, "not_an_username", "password1", mockClient, tm, "")
 		if !errors.Is(err, user.ErrUserNameNotFound) {
 			t.Fatal(err)
 		}
 
-		_, _, _, err = userObject.LoginUserV2("7e4567e7cb003804992eef11fd5c757275a4c", "wrong_password", mockClient, tm, "")
+		_, _, _, err = userObject.LoginUserV2(ctx, "7e4567e7cb003804992eef11fd5c757275a4c", "wrong_password", mockClient, tm, "")
 		if !errors.Is(err, user.ErrInvalidPassword) {
 			t.Fatal(err)
 		}
 
 		// addUserAndSessionToMap user again
-		ui1, _, _, err := userObject.LoginUserV2("7e4567e7cb003804992eef11fd5c757275a4c", "password1twelve", mockClient, tm, "")
+		ui1, _, _, err := userObject.LoginUserV2(ctx, "7e4567e7cb003804992eef11fd5c757275a4c", "password1twelve", mockClient, tm, "")
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -98,12 +99,12 @@ func TestLogin(t *testing.T) {
 		pass := "password1password1"
 		//create user
 		userObject := user.NewUsers(mockClient, ens, logger)
-		_, mnemonic, _, _, ui, err := userObject.CreateNewUserV2(user1, pass, "", "", tm)
+		_, mnemonic, _, _, ui, err := userObject.CreateNewUserV2(ctx, user1, pass, "", "", tm)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		_, _, _, _, _, err = userObject.CreateNewUserV2(user1, pass, "", "", tm)
+		_, _, _, _, _, err = userObject.CreateNewUserV2(ctx, user1, pass, "", "", tm)
 		if !errors.Is(err, user.ErrUserAlreadyPresent) {
 			t.Fatal(err)
 		}
@@ -129,17 +130,17 @@ func TestLogin(t *testing.T) {
 			t.Fatalf("invalid mnemonic")
 		}
 
-		_, _, _, _, _, err = userObject.CreateNewUserV2(user1, pass+pass, mnemonic, "", tm)
+		_, _, _, _, _, err = userObject.CreateNewUserV2(ctx, user1, pass+pass, mnemonic, "", tm)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		login1, _, _, err := userObject.LoginUserV2(user1, pass, mockClient, tm, "")
+		login1, _, _, err := userObject.LoginUserV2(ctx, user1, pass, mockClient, tm, "")
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		login2, _, _, err := userObject.LoginUserV2(user1, pass+pass, mockClient, tm, "")
+		login2, _, _, err := userObject.LoginUserV2(ctx, user1, pass+pass, mockClient, tm, "")
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -156,12 +157,12 @@ func TestLogin(t *testing.T) {
 		//create user
 		userObject := user.NewUsers(mockClient, ens, logger)
 		pass := "password1password1"
-		_, mnemonic, _, _, ui, err := userObject.CreateNewUserV2(user1, pass, "", "", tm)
+		_, mnemonic, _, _, ui, err := userObject.CreateNewUserV2(ctx, user1, pass, "", "", tm)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		_, _, _, _, _, err = userObject.CreateNewUserV2(user1, pass, "", "", tm)
+		_, _, _, _, _, err = userObject.CreateNewUserV2(ctx, user1, pass, "", "", tm)
 		if !errors.Is(err, user.ErrUserAlreadyPresent) {
 			t.Fatal(err)
 		}
@@ -190,26 +191,26 @@ func TestLogin(t *testing.T) {
 		podName1 := "test1"
 		podName2 := "test2"
 		podPassword, _ := utils.GetRandString(pod.PasswordLength)
-		_, err = pod1.CreatePod(podName1, "", podPassword)
+		_, err = pod1.CreatePod(ctx, podName1, "", podPassword)
 		if err != nil {
 			t.Fatalf("error creating pod %s : %s", podName1, err.Error())
 		}
-		_, err = pod1.CreatePod(podName2, "", podPassword)
+		_, err = pod1.CreatePod(ctx, podName2, "", podPassword)
 		if err != nil {
 			t.Fatalf("error creating pod %s : %s", podName1, err.Error())
 		}
 
-		_, _, _, _, ui2, err := userObject.CreateNewUserV2(user1, pass+pass, mnemonic, "", tm)
+		_, _, _, _, ui2, err := userObject.CreateNewUserV2(ctx, user1, pass+pass, mnemonic, "", tm)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		login1, _, _, err := userObject.LoginUserV2(user1, pass, mockClient, tm, "")
+		login1, _, _, err := userObject.LoginUserV2(ctx, user1, pass, mockClient, tm, "")
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		login2, _, _, err := userObject.LoginUserV2(user1, pass+pass, mockClient, tm, "")
+		login2, _, _, err := userObject.LoginUserV2(ctx, user1, pass+pass, mockClient, tm, "")
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -219,15 +220,15 @@ func TestLogin(t *testing.T) {
 			t.Fatal("got different accounts with same login")
 		}
 
-		login1Pods, _, err := login1.GetPod().ListPods()
+		login1Pods, _, err := login1.GetPod().ListPods(ctx)
 		if err != nil {
 			t.Fatal(err)
 		}
-		login2Pods, _, err := login2.GetPod().ListPods()
+		login2Pods, _, err := login2.GetPod().ListPods(ctx)
 		if err != nil {
 			t.Fatal(err)
 		}
-		ui2Pods, _, err := ui2.GetPod().ListPods()
+		ui2Pods, _, err := ui2.GetPod().ListPods(ctx)
 		if err != nil {
 			t.Fatal(err)
 		}
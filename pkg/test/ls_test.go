@@ -35,6 +35,7 @@ import (
 )
 
 func TestPod_ListPods(t *testing.T) {
+	ctx := context.Background()
 	mockClient := mock.NewMockBeeClient()
 	logger := logging.New(os.Stdout, 0)
 	acc := account.New(logger)
@@ -55,7 +56,7 @@ func TestPod_ListPods(t *testing.T) {
 	podName2 := "test2"
 
 	t.Run("list-without-pods", func(t *testing.T) {
-		_, _, err = pod1.ListPods()
+		_, _, err = pod1.ListPods(ctx)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -63,16 +64,16 @@ func TestPod_ListPods(t *testing.T) {
 
 	t.Run("create-two-pods", func(t *testing.T) {
 		podPassword, _ := utils.GetRandString(pod.PasswordLength)
-		_, err := pod1.CreatePod(podName1, "", podPassword)
+		_, err := pod1.CreatePod(ctx, podName1, "", podPassword)
 		if err != nil {
 			t.Fatalf("error creating pod: %v", err)
 		}
-		_, err = pod1.CreatePod(podName2, "", podPassword)
+		_, err = pod1.CreatePod(ctx, podName2, "", podPassword)
 		if err != nil {
 			t.Fatalf("error creating pod %s", podName1)
 		}
 
-		pods, _, err := pod1.ListPods()
+		pods, _, err := pod1.ListPods(ctx)
 		if err != nil {
 			t.Fatal(err)
 		}
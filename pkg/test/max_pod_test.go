@@ -18,6 +18,7 @@ import (
 )
 
 func TestMaxPods(t *testing.T) {
+	ctx := context.Background()
 	mockClient := mock.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -43,7 +44,7 @@ func TestMaxPods(t *testing.T) {
 				t.Fatal(err)
 			}
 			podPassword, _ := utils.GetRandString(pod.PasswordLength)
-			_, err = pod1.CreatePod(name, "", podPassword)
+			_, err = pod1.CreatePod(ctx, name, "", podPassword)
 			if err != nil {
 				t.Fatalf("error creating pod %s with index %d: %s", name, i, err)
 			}
@@ -53,7 +54,7 @@ func TestMaxPods(t *testing.T) {
 			t.Fatal(err)
 		}
 		podPassword, _ := utils.GetRandString(pod.PasswordLength)
-		_, err = pod1.CreatePod(name, "", podPassword)
+		_, err = pod1.CreatePod(ctx, name, "", podPassword)
 		if !errors.Is(err, pod.ErrMaximumPodLimit) {
 			t.Fatalf("maximum pod limit should have been reached")
 		}
@@ -32,6 +32,7 @@ import (
 )
 
 func TestNew(t *testing.T) {
+	ctx := context.Background()
 	mockClient := mock.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	tm := taskmanager.New(1, 10, time.Second*15, logger)
@@ -44,7 +45,7 @@ func TestNew(t *testing.T) {
 
 		// create user
 		userObject := user.NewUsers(mockClient, ens, logger)
-		_, _, _, _, _, err := userObject.CreateNewUserV2("", "password1", "", "", tm)
+		_, _, _, _, _, err := userObject.CreateNewUserV2(ctx, "", "password1", "", "", tm)
 		if !errors.Is(err, user.ErrBlankUsername) {
 			t.Fatal(err)
 		}
@@ -55,17 +56,17 @@ func TestNew(t *testing.T) {
 
 		// create user
 		userObject := user.NewUsers(mockClient, ens, logger)
-		_, _, _, _, _, err := userObject.CreateNewUserV2("user1", "password1", "", "", tm)
+		_, _, _, _, _, err := userObject.CreateNewUserV2(ctx, "user1", "password1", "", "", tm)
 		if err != nil && !errors.Is(err, user.ErrPasswordTooSmall) {
 			t.Fatal(err)
 		}
 
-		_, mnemonic, _, _, ui, err := userObject.CreateNewUserV2("user1", "password1twelve", "", "", tm)
+		_, mnemonic, _, _, ui, err := userObject.CreateNewUserV2(ctx, "user1", "password1twelve", "", "", tm)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		_, _, _, _, _, err = userObject.CreateNewUserV2("user1", "password1twelve", "", "", tm)
+		_, _, _, _, _, err = userObject.CreateNewUserV2(ctx, "user1", "password1twelve", "", "", tm)
 		if !errors.Is(err, user.ErrUserAlreadyPresent) {
 			t.Fatal(err)
 		}
@@ -98,12 +99,12 @@ func TestNew(t *testing.T) {
 		//create user
 		userObject := user.NewUsers(mockClient, ens, logger)
 		pass := "password1password1"
-		_, mnemonic, _, _, ui, err := userObject.CreateNewUserV2(user1, pass, "", "", tm)
+		_, mnemonic, _, _, ui, err := userObject.CreateNewUserV2(ctx, user1, pass, "", "", tm)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		_, _, _, _, _, err = userObject.CreateNewUserV2(user1, pass, "", "", tm)
+		_, _, _, _, _, err = userObject.CreateNewUserV2(ctx, user1, pass, "", "", tm)
 		if !errors.Is(err, user.ErrUserAlreadyPresent) {
 			t.Fatal(err)
 		}
@@ -129,7 +130,7 @@ func TestNew(t *testing.T) {
 			t.Fatalf("invalid mnemonic")
 		}
 
-		_, _, _, _, _, err = userObject.CreateNewUserV2(user1, pass+pass, mnemonic, "", tm)
+		_, _, _, _, _, err = userObject.CreateNewUserV2(ctx, user1, pass+pass, mnemonic, "", tm)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -38,6 +38,7 @@ import (
 )
 
 func TestOpen(t *testing.T) {
+	ctx := context.Background()
 	mockClient := mock.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -57,20 +58,20 @@ func TestOpen(t *testing.T) {
 
 	t.Run("open-pod", func(t *testing.T) {
 		// open non-existent the pod
-		_, err := pod1.OpenPod(podName1)
+		_, err := pod1.OpenPod(ctx, podName1)
 		if !errors.Is(err, pod.ErrInvalidPodName) {
 			t.Fatal("pod should not be present")
 		}
 
 		// create a pod
 		podPassword, _ := utils.GetRandString(pod.PasswordLength)
-		info, err := pod1.CreatePod(podName1, "", podPassword)
+		info, err := pod1.CreatePod(ctx, podName1, "", podPassword)
 		if err != nil {
 			t.Fatalf("error creating pod %s", podName1)
 		}
 
 		// make root dir so that other directories can be added
-		err = info.GetDirectory().MkRootDir("pod1", podPassword, info.GetPodAddress(), info.GetFeed())
+		err = info.GetDirectory().MkRootDir(ctx, "pod1", podPassword, info.GetPodAddress(), info.GetFeed())
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -79,7 +80,7 @@ func TestOpen(t *testing.T) {
 		addFilesAndDirectories(t, info, pod1, podName1, podPassword)
 
 		// open the pod
-		podInfo, err := pod1.OpenPod(podName1)
+		podInfo, err := pod1.OpenPod(ctx, podName1)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -102,20 +103,20 @@ func TestOpen(t *testing.T) {
 
 	t.Run("open-pod-async", func(t *testing.T) {
 		// open non-existent the pod
-		_, err := pod1.OpenPod(podName2)
+		_, err := pod1.OpenPod(ctx, podName2)
 		if !errors.Is(err, pod.ErrInvalidPodName) {
 			t.Fatal("pod should not be present")
 		}
 
 		// create a pod
 		podPassword, _ := utils.GetRandString(pod.PasswordLength)
-		info, err := pod1.CreatePod(podName2, "", podPassword)
+		info, err := pod1.CreatePod(ctx, podName2, "", podPassword)
 		if err != nil {
 			t.Fatalf("error creating pod %s", podName1)
 		}
 
 		// make root dir so that other directories can be added
-		err = info.GetDirectory().MkRootDir("pod1", podPassword, info.GetPodAddress(), info.GetFeed())
+		err = info.GetDirectory().MkRootDir(ctx, "pod1", podPassword, info.GetPodAddress(), info.GetFeed())
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -144,9 +145,47 @@ func TestOpen(t *testing.T) {
 			t.Fatalf("invalid pod name")
 		}
 	})
+
+	t.Run("open-sequence-pod", func(t *testing.T) {
+		podName := "sequence"
+		podPassword, _ := utils.GetRandString(pod.PasswordLength)
+		info, err := pod1.CreatePodWithFeedType(ctx, podName, "", podPassword, feed.SequenceFeed)
+		if err != nil {
+			t.Fatalf("error creating pod %s", podName)
+		}
+		err = info.GetDirectory().MkRootDir(ctx, podName, podPassword, info.GetPodAddress(), info.GetFeed())
+		if err != nil {
+			t.Fatal(err)
+		}
+		addFilesAndDirectories(t, info, pod1, podName, podPassword)
+
+		// the pod is opened with the feed type it was created with
+		podInfo, err := pod1.OpenPod(ctx, podName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if podInfo.GetFeed().Type() != feed.SequenceFeed {
+			t.Fatalf("expected a sequence pod, got %s", podInfo.GetFeed().Type())
+		}
+		stat, err := pod1.PodStat(podName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stat.FeedType != "sequence" {
+			t.Fatalf("invalid feed type %s", stat.FeedType)
+		}
+		if podInfo.GetDirectory().GetDirFromDirectoryMap("/parentDir/subDir2") == nil {
+			t.Fatal("directory not synced")
+		}
+		meta := podInfo.GetFile().GetFromFileMap("/parentDir/file2")
+		if meta == nil || meta.Size != 200 {
+			t.Fatal("file not synced")
+		}
+	})
 }
 
 func uploadFile(t *testing.T, fileObject *file.File, filePath, fileName, compression, podPassword string, fileSize int64, blockSize uint32) ([]byte, error) {
+	ctx := context.Background()
 	// create a temp file
 	fd, err := os.CreateTemp("", fileName)
 	if err != nil {
@@ -178,13 +217,14 @@ func uploadFile(t *testing.T, fileObject *file.File, filePath, fileName, compres
 	}
 
 	// upload  the temp file
-	return content, fileObject.Upload(f1, fileName, fileSize, blockSize, filePath, compression, podPassword)
+	return content, fileObject.Upload(ctx, f1, fileName, fileSize, blockSize, filePath, compression, podPassword)
 }
 
 func addFilesAndDirectories(t *testing.T, info *pod.Info, pod1 *pod.Pod, podName1, podPassword string) {
+	ctx := context.Background()
 	t.Helper()
 	dirObject := info.GetDirectory()
-	err := dirObject.MkDir("/parentDir", podPassword)
+	err := dirObject.MkDir(ctx, "/parentDir", podPassword)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -198,11 +238,11 @@ func addFilesAndDirectories(t *testing.T, info *pod.Info, pod1 *pod.Pod, podName
 	}
 
 	// populate the directory with few directory and files
-	err = dirObject.MkDir("/parentDir/subDir1", podPassword)
+	err = dirObject.MkDir(ctx, "/parentDir/subDir1", podPassword)
 	if err != nil {
 		t.Fatal(err)
 	}
-	err = dirObject.MkDir("/parentDir/subDir2", podPassword)
+	err = dirObject.MkDir(ctx, "/parentDir/subDir2", podPassword)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -211,7 +251,7 @@ func addFilesAndDirectories(t *testing.T, info *pod.Info, pod1 *pod.Pod, podName
 	if err != nil {
 		t.Fatal(err)
 	}
-	err = dirObject.AddEntryToDir("/parentDir", podPassword, "file1", true)
+	err = dirObject.AddEntryToDir(ctx, "/parentDir", podPassword, "file1", true)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -219,19 +259,19 @@ func addFilesAndDirectories(t *testing.T, info *pod.Info, pod1 *pod.Pod, podName
 	if err != nil {
 		t.Fatal(err)
 	}
-	err = dirObject.AddEntryToDir("/parentDir", podPassword, "file2", true)
+	err = dirObject.AddEntryToDir(ctx, "/parentDir", podPassword, "file2", true)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	// close the pod
-	err = pod1.ClosePod(podName1)
+	err = pod1.ClosePod(ctx, podName1)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	// close the pod
-	err = pod1.ClosePod(podName1)
+	err = pod1.ClosePod(ctx, podName1)
 	if !errors.Is(err, pod.ErrPodNotOpened) {
 		t.Fatal("pod should not be open")
 	}
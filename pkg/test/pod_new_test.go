@@ -36,6 +36,7 @@ import (
 )
 
 func TestPodNew(t *testing.T) {
+	ctx := context.Background()
 	mockClient := mock.NewMockBeeClient()
 	logger := logging.New(os.Stdout, 0)
 	acc := account.New(logger)
@@ -53,7 +54,7 @@ func TestPodNew(t *testing.T) {
 	podName1 := "test1"
 	podName2 := "test2"
 	t.Run("create-first-pod", func(t *testing.T) {
-		podPresent := pod1.IsPodPresent("")
+		podPresent := pod1.IsPodPresent(ctx, "")
 		if podPresent {
 			t.Fatal("blank podname should not be present")
 		}
@@ -64,15 +65,15 @@ func TestPodNew(t *testing.T) {
 			t.Fatalf("error creating pod %s", podName1)
 		}
 		podPassword, _ := utils.GetRandString(pod.PasswordLength)
-		_, err = pod1.CreatePod(randomLongPOdName, "", podPassword)
+		_, err = pod1.CreatePod(ctx, randomLongPOdName, "", podPassword)
 		if !errors.Is(err, pod.ErrTooLongPodName) {
 			t.Fatalf("error creating pod %s", podName1)
 		}
-		pod1Present := pod1.IsPodPresent(randomLongPOdName)
+		pod1Present := pod1.IsPodPresent(ctx, randomLongPOdName)
 		if pod1Present {
 			t.Fatal("pod1 should not be present")
 		}
-		info, err := pod1.CreatePod(podName1, "", podPassword)
+		info, err := pod1.CreatePod(ctx, podName1, "", podPassword)
 		if err != nil {
 			t.Fatalf("error creating pod %s: %s", podName1, err.Error())
 		}
@@ -85,7 +86,7 @@ func TestPodNew(t *testing.T) {
 			t.Fatalf("invalid pod name: expected %s got %s", podName1, info.GetPodName())
 		}
 
-		pods, _, err := pod1.ListPods()
+		pods, _, err := pod1.ListPods(ctx)
 		if err != nil {
 			t.Fatalf("error getting pods")
 		}
@@ -110,7 +111,7 @@ func TestPodNew(t *testing.T) {
 
 	t.Run("create-second-pod", func(t *testing.T) {
 		podPassword, _ := utils.GetRandString(pod.PasswordLength)
-		info, err := pod1.CreatePod(podName2, "", podPassword)
+		info, err := pod1.CreatePod(ctx, podName2, "", podPassword)
 		if err != nil {
 			t.Fatalf("error creating pod %s", podName2)
 		}
@@ -119,7 +120,7 @@ func TestPodNew(t *testing.T) {
 			t.Fatalf("invalid pod name: expected %s got %s", podName2, info.GetPodName())
 		}
 
-		pods, _, err := pod1.ListPods()
+		pods, _, err := pod1.ListPods(ctx)
 		if err != nil {
 			t.Fatalf("error getting pods")
 		}
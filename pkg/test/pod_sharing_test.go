@@ -34,6 +34,7 @@ import (
 )
 
 func TestShare(t *testing.T) {
+	ctx := context.Background()
 	mockClient := mock.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -95,19 +96,19 @@ func TestShare(t *testing.T) {
 	podName6 := "test6"
 
 	t.Run("share-pod", func(t *testing.T) {
-		_, err := pod1.PodShare(podName1, "")
+		_, err := pod1.PodShare(ctx, podName1, "")
 		if err == nil {
 			t.Fatal("pod share should fail, not exists")
 		}
 		// create a pod
 		podPassword, _ := utils.GetRandString(pod.PasswordLength)
-		info, err := pod1.CreatePod(podName1, "", podPassword)
+		info, err := pod1.CreatePod(ctx, podName1, "", podPassword)
 		if err != nil {
 			t.Fatalf("error creating pod %s", podName1)
 		}
 
 		// make root dir so that other directories can be added
-		err = info.GetDirectory().MkRootDir("pod1", podPassword, info.GetPodAddress(), info.GetFeed())
+		err = info.GetDirectory().MkRootDir(ctx, "pod1", podPassword, info.GetPodAddress(), info.GetFeed())
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -116,7 +117,7 @@ func TestShare(t *testing.T) {
 		addFilesAndDirectories(t, info, pod1, podName1, podPassword)
 
 		// share pod
-		sharingRef, err := pod1.PodShare(podName1, "")
+		sharingRef, err := pod1.PodShare(ctx, podName1, "")
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -131,13 +132,13 @@ func TestShare(t *testing.T) {
 		// create a pod
 		podName01 := "test_1"
 		podPassword, _ := utils.GetRandString(pod.PasswordLength)
-		info, err := pod1.CreatePod(podName01, "", podPassword)
+		info, err := pod1.CreatePod(ctx, podName01, "", podPassword)
 		if err != nil {
 			t.Fatalf("error creating pod %s", podName01)
 		}
 
 		// make root dir so that other directories can be added
-		err = info.GetDirectory().MkRootDir("", podPassword, info.GetPodAddress(), info.GetFeed())
+		err = info.GetDirectory().MkRootDir(ctx, "", podPassword, info.GetPodAddress(), info.GetFeed())
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -147,7 +148,7 @@ func TestShare(t *testing.T) {
 
 		// share pod
 		sharedPodName := "test01"
-		sharingRef, err := pod1.PodShare(podName01, sharedPodName)
+		sharingRef, err := pod1.PodShare(ctx, podName01, sharedPodName)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -162,7 +163,7 @@ func TestShare(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		sharingInfo, err := pod1.ReceivePodInfo(ref)
+		sharingInfo, err := pod1.ReceivePodInfo(ctx, ref)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -179,13 +180,13 @@ func TestShare(t *testing.T) {
 	t.Run("receive-pod-info", func(t *testing.T) {
 		// create a pod
 		podPassword, _ := utils.GetRandString(pod.PasswordLength)
-		info, err := pod2.CreatePod(podName2, "", podPassword)
+		info, err := pod2.CreatePod(ctx, podName2, "", podPassword)
 		if err != nil {
 			t.Fatalf("error creating pod %s", podName2)
 		}
 
 		// make root dir so that other directories can be added
-		err = info.GetDirectory().MkRootDir("pod1", podPassword, info.GetPodAddress(), info.GetFeed())
+		err = info.GetDirectory().MkRootDir(ctx, "pod1", podPassword, info.GetPodAddress(), info.GetFeed())
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -194,7 +195,7 @@ func TestShare(t *testing.T) {
 		addFilesAndDirectories(t, info, pod2, podName2, podPassword)
 
 		// share pod
-		sharingRef, err := pod2.PodShare(podName2, "")
+		sharingRef, err := pod2.PodShare(ctx, podName2, "")
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -204,7 +205,7 @@ func TestShare(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		sharingInfo, err := pod2.ReceivePodInfo(ref)
+		sharingInfo, err := pod2.ReceivePodInfo(ctx, ref)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -221,7 +222,7 @@ func TestShare(t *testing.T) {
 	t.Run("receive-pod", func(t *testing.T) {
 		// create sending pod and receiving pod
 		podPassword, _ := utils.GetRandString(pod.PasswordLength)
-		info, err := pod3.CreatePod(podName3, "", podPassword)
+		info, err := pod3.CreatePod(ctx, podName3, "", podPassword)
 		if err != nil {
 			t.Fatalf("error creating pod %s", podName3)
 		}
@@ -229,16 +230,16 @@ func TestShare(t *testing.T) {
 		if err == nil {
 			t.Fatalf("GetAccountInfo for pod4 should fail")
 		}
-		pi4, err := pod4.CreatePod(podName4, "", podPassword)
+		pi4, err := pod4.CreatePod(ctx, podName4, "", podPassword)
 		if err != nil {
 			t.Fatalf("error creating pod %s", podName4)
 		}
 
-		pod4Present := pod4.IsPodPresent(podName4)
+		pod4Present := pod4.IsPodPresent(ctx, podName4)
 		if !pod4Present {
 			t.Fatal("pod4 should be present")
 		}
-		pod5Present := pod4.IsPodPresent(podName5)
+		pod5Present := pod4.IsPodPresent(ctx, podName5)
 		if pod5Present {
 			t.Fatal("pod5 should not be present")
 		}
@@ -250,7 +251,7 @@ func TestShare(t *testing.T) {
 			t.Fatalf("pod4 address does not match")
 		}
 		// make root dir so that other directories can be added
-		err = info.GetDirectory().MkRootDir("", podPassword, info.GetPodAddress(), info.GetFeed())
+		err = info.GetDirectory().MkRootDir(ctx, "", podPassword, info.GetPodAddress(), info.GetFeed())
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -259,7 +260,7 @@ func TestShare(t *testing.T) {
 		addFilesAndDirectories(t, info, pod3, podName3, podPassword)
 
 		// share pod
-		sharingRef, err := pod3.PodShare(podName3, "")
+		sharingRef, err := pod3.PodShare(ctx, podName3, "")
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -269,16 +270,16 @@ func TestShare(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		podInfo, err := pod4.ReceivePod("", ref)
+		podInfo, err := pod4.ReceivePod(ctx, "", ref)
 		if err != nil {
 			t.Fatal(err)
 		}
-		pod3Present := pod4.IsPodPresent(podName3)
+		pod3Present := pod4.IsPodPresent(ctx, podName3)
 		if !pod3Present {
 			t.Fatal("pod3 should be present")
 		}
 
-		podInfo2, err := pod4.OpenPod(podName3)
+		podInfo2, err := pod4.OpenPod(ctx, podName3)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -293,7 +294,7 @@ func TestShare(t *testing.T) {
 			t.Fatalf("invalid pod name received")
 		}
 
-		pods, sharedPods, err := pod4.ListPods()
+		pods, sharedPods, err := pod4.ListPods(ctx)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -310,32 +311,32 @@ func TestShare(t *testing.T) {
 			t.Fatalf("invalid pod name")
 		}
 		podPassword, _ = utils.GetRandString(pod.PasswordLength)
-		_, err = pod4.CreatePod(podName4, ref.String(), podPassword)
+		_, err = pod4.CreatePod(ctx, podName4, ref.String(), podPassword)
 		if !errors.Is(err, pod.ErrPodAlreadyExists) {
 			t.Fatal("pod should exist")
 		}
 		podPassword, _ = utils.GetRandString(pod.PasswordLength)
-		_, err = pod4.CreatePod(podName3, ref.String(), podPassword)
+		_, err = pod4.CreatePod(ctx, podName3, ref.String(), podPassword)
 		if !errors.Is(err, pod.ErrPodAlreadyExists) {
 			t.Fatal("shared pod should exist")
 		}
 		podPassword, _ = utils.GetRandString(pod.PasswordLength)
-		_, err = pod4.CreatePod(podName4, "", podPassword)
+		_, err = pod4.CreatePod(ctx, podName4, "", podPassword)
 		if !errors.Is(err, pod.ErrPodAlreadyExists) {
 			t.Fatal("pod should exist")
 		}
 		podPassword, _ = utils.GetRandString(pod.PasswordLength)
-		_, err = pod4.CreatePod(podName3, "", podPassword)
+		_, err = pod4.CreatePod(ctx, podName3, "", podPassword)
 		if !errors.Is(err, pod.ErrPodAlreadyExists) {
 			t.Fatal("shared pod should exist")
 		}
 
-		err = pod4.DeleteSharedPod(podName3)
+		err = pod4.DeleteSharedPod(ctx, podName3)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		err = pod4.DeleteSharedPod(podName3)
+		err = pod4.DeleteSharedPod(ctx, podName3)
 		if err == nil {
 			t.Fatal("pod should have been deleted")
 		}
@@ -344,18 +345,18 @@ func TestShare(t *testing.T) {
 	t.Run("receive-pod-with-new-name", func(t *testing.T) {
 		// create sending pod and receiving pod
 		podPassword, _ := utils.GetRandString(pod.PasswordLength)
-		info, err := pod5.CreatePod(podName5, "", podPassword)
+		info, err := pod5.CreatePod(ctx, podName5, "", podPassword)
 		if err != nil {
 			t.Fatalf("error creating pod %s", podName5)
 		}
 		podPassword, _ = utils.GetRandString(pod.PasswordLength)
-		_, err = pod6.CreatePod(podName6, "", podPassword)
+		_, err = pod6.CreatePod(ctx, podName6, "", podPassword)
 		if err != nil {
 			t.Fatalf("error creating pod %s", podName6)
 		}
 
 		// make root dir so that other directories can be added
-		err = info.GetDirectory().MkRootDir("", podPassword, info.GetPodAddress(), info.GetFeed())
+		err = info.GetDirectory().MkRootDir(ctx, "", podPassword, info.GetPodAddress(), info.GetFeed())
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -364,7 +365,7 @@ func TestShare(t *testing.T) {
 		addFilesAndDirectories(t, info, pod5, podName5, podPassword)
 
 		// share pod
-		sharingRef, err := pod5.PodShare(podName5, "")
+		sharingRef, err := pod5.PodShare(ctx, podName5, "")
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -375,7 +376,7 @@ func TestShare(t *testing.T) {
 			t.Fatal(err)
 		}
 		sharedPodName2 := "test02"
-		podInfo, err := pod6.ReceivePod(sharedPodName2, ref)
+		podInfo, err := pod6.ReceivePod(ctx, sharedPodName2, ref)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -388,7 +389,7 @@ func TestShare(t *testing.T) {
 			t.Fatalf("invalid pod name received")
 		}
 
-		pods, sharedPods, err := pod6.ListPods()
+		pods, sharedPods, err := pod6.ListPods(ctx)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -429,13 +430,13 @@ func TestShare(t *testing.T) {
 
 		// create sending pod and receiving pod
 		podPassword, _ := utils.GetRandString(pod.PasswordLength)
-		info, err := pod7.CreatePod(podName7, "", podPassword)
+		info, err := pod7.CreatePod(ctx, podName7, "", podPassword)
 		if err != nil {
 			t.Fatalf("error creating pod %s", podName7)
 		}
 
 		// make root dir so that other directories can be added
-		err = info.GetDirectory().MkRootDir("", podPassword, info.GetPodAddress(), info.GetFeed())
+		err = info.GetDirectory().MkRootDir(ctx, "", podPassword, info.GetPodAddress(), info.GetFeed())
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -444,7 +445,7 @@ func TestShare(t *testing.T) {
 		addFilesAndDirectories(t, info, pod7, podName7, podPassword)
 
 		// share pod
-		sharingRef, err := pod7.PodShare(podName7, "")
+		sharingRef, err := pod7.PodShare(ctx, podName7, "")
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -455,7 +456,7 @@ func TestShare(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		podInfo, err := pod8.ReceivePod("", ref)
+		podInfo, err := pod8.ReceivePod(ctx, "", ref)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -471,24 +472,24 @@ func TestShare(t *testing.T) {
 		// now change original pod content
 
 		// open the pod ths triggers sync too
-		gotInfo, err := pod7.OpenPod(podName7)
+		gotInfo, err := pod7.OpenPod(ctx, podName7)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// validate if the directory and files are synced
 		dirObject := gotInfo.GetDirectory()
-		err = dirObject.RenameDir("/parentDir/subDir1", "/parentDir/newSubDir1", podPassword)
+		err = dirObject.RenameDir(ctx, "/parentDir/subDir1", "/parentDir/newSubDir1", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 		fileObject := gotInfo.GetFile()
-		_, err = fileObject.RenameFromFileName("/parentDir/file1", "/parentDir/renamedFile1", podPassword)
+		_, err = fileObject.RenameFromFileName(ctx, "/parentDir/file1", "/parentDir/renamedFile1", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
 		// check shared pod entry
-		gotSharedPodInfo, err := pod8.OpenPod(podName7)
+		gotSharedPodInfo, err := pod8.OpenPod(ctx, podName7)
 		if err != nil {
 			t.Fatal(err)
 		}
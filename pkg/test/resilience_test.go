@@ -0,0 +1,270 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/account"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/bee/mock"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/fault"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/collection"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/pod"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
+	"github.com/plexsysio/taskmanager"
+)
+
+// newFaultyPod creates and opens a pod on top of a fault injecting blockstore
+func newFaultyPod(t *testing.T, seed int64) (*fault.Client, *pod.Pod, *pod.Info, string) {
+	t.Helper()
+	ctx := context.Background()
+	logger := logging.New(io.Discard, 0)
+	client := fault.NewClient(mock.NewMockBeeClient(), seed)
+	acc := account.New(logger)
+	_, _, err := acc.CreateUserAccount("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tm := taskmanager.New(1, 10, time.Second*15, logger)
+	t.Cleanup(func() {
+		_ = tm.Stop(context.Background())
+	})
+	fd := feed.New(acc.GetUserAccountInfo(), client, logger)
+	p := pod.NewPod(client, fd, acc, tm, logger)
+	podPassword, _ := utils.GetRandString(pod.PasswordLength)
+	info, err := p.CreatePod(ctx, "faulty", "", podPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = info.GetDirectory().MkRootDir(ctx, "faulty", podPassword, info.GetPodAddress(), info.GetFeed())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, p, info, podPassword
+}
+
+func TestResilience(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("file-upload-fails", func(t *testing.T) {
+		client, _, info, podPassword := newFaultyPod(t, 1)
+		fileObject := info.GetFile()
+
+		// the second batch of blocks fails half way
+		client.SetRules(fault.Rule{Ops: fault.BlobUploads, Kind: fault.Partial, After: 1, Times: 1})
+		_, err := uploadFile(t, fileObject, "/", "file1", "", podPassword, 1000, 10)
+		if !errors.Is(err, fault.ErrInjected) {
+			t.Fatalf("expected the upload to fail with the injected error, got %v", err)
+		}
+		if fileObject.GetFromFileMap("/file1") != nil {
+			t.Fatal("failed upload should not be visible")
+		}
+
+		// a retry stores the whole file
+		client.SetRules()
+		content, err := uploadFile(t, fileObject, "/", "file1", "", podPassword, 1000, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertFileContent(t, info, podPassword, "/file1", content)
+	})
+
+	t.Run("corrupted-download", func(t *testing.T) {
+		client, _, info, podPassword := newFaultyPod(t, 1)
+		content, err := uploadFile(t, info.GetFile(), "/", "file1", "", podPassword, 100, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the inode is corrupted, which must fail cleanly
+		client.SetRules(fault.Rule{Ops: []fault.Op{fault.OpDownloadBlob}, Kind: fault.Corrupt, Times: 1})
+		_, _, err = info.GetFile().Download(ctx, "/file1", podPassword)
+		if err == nil {
+			t.Fatal("expected an error for a corrupted inode")
+		}
+		assertFileContent(t, info, podPassword, "/file1", content)
+	})
+
+	t.Run("mkdir-feed-update-fails", func(t *testing.T) {
+		client, _, info, podPassword := newFaultyPod(t, 1)
+		dirObject := info.GetDirectory()
+
+		client.SetRules(fault.Rule{Ops: fault.SOCUploads, Kind: fault.Error})
+		err := dirObject.MkDir(ctx, "/dir1", podPassword)
+		if !errors.Is(err, fault.ErrInjected) {
+			t.Fatalf("expected mkdir to fail with the injected error, got %v", err)
+		}
+		if dirObject.GetDirFromDirectoryMap("/dir1") != nil {
+			t.Fatal("failed mkdir should not be visible")
+		}
+
+		client.SetRules()
+		err = dirObject.MkDir(ctx, "/dir1", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries, _, err := dirObject.ListDir(ctx, "/", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 || entries[0].Name != "dir1" {
+			t.Fatalf("expected dir1 in the root, got %v", entries)
+		}
+	})
+
+	t.Run("kv-put-fails", func(t *testing.T) {
+		client, _, info, podPassword := newFaultyPod(t, 1)
+		kvStore := info.GetKVStore()
+		err := kvStore.CreateKVTable(ctx, "kv", podPassword, collection.StringIndex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = kvStore.OpenKVTable(ctx, "kv", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = kvStore.KVPut(ctx, "kv", "key1", []byte("value1"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		client.SetRules(fault.Rule{Ops: fault.BlobUploads, Kind: fault.Error})
+		// the index reports its own error for the failed manifest upload
+		err = kvStore.KVPut(ctx, "kv", "key2", []byte("value2"))
+		if err == nil {
+			t.Fatal("expected put to fail")
+		}
+
+		client.SetRules()
+		_, value, err := kvStore.KVGet(ctx, "kv", "key1")
+		if err != nil || string(value) != "value1" {
+			t.Fatalf("existing key should be readable, got %s: %v", value, err)
+		}
+		_, _, err = kvStore.KVGet(ctx, "kv", "key2")
+		if err == nil {
+			t.Fatal("failed put should not be visible")
+		}
+	})
+
+	t.Run("dropped-feed-update", func(t *testing.T) {
+		client, p, info, podPassword := newFaultyPod(t, 1)
+		kvStore := info.GetKVStore()
+		err := kvStore.CreateKVTable(ctx, "kv", podPassword, collection.StringIndex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = kvStore.OpenKVTable(ctx, "kv", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = kvStore.KVPut(ctx, "kv", "key1", []byte("value1"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the manifest update is lost, so the put is gone once the table is read again
+		client.SetRules(fault.Rule{Ops: fault.SOCUploads, Kind: fault.Drop})
+		err = kvStore.KVPut(ctx, "kv", "key2", []byte("value2"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if client.Injected(fault.SOCUploads...) == 0 {
+			t.Fatal("expected the feed update to be dropped")
+		}
+		client.SetRules()
+		err = p.ClosePod(ctx, "faulty")
+		if err != nil {
+			t.Fatal(err)
+		}
+		info, err = p.OpenPod(ctx, "faulty")
+		if err != nil {
+			t.Fatal(err)
+		}
+		kvStore = info.GetKVStore()
+		err = kvStore.OpenKVTable(ctx, "kv", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, value, err := kvStore.KVGet(ctx, "kv", "key1")
+		if err != nil || string(value) != "value1" {
+			t.Fatalf("earlier put should survive, got %s: %v", value, err)
+		}
+		_, _, err = kvStore.KVGet(ctx, "kv", "key2")
+		if err == nil {
+			t.Fatal("put with a dropped feed update should be lost")
+		}
+	})
+
+	t.Run("seeded-random-errors", func(t *testing.T) {
+		client, _, info, podPassword := newFaultyPod(t, 7)
+		kvStore := info.GetKVStore()
+		err := kvStore.CreateKVTable(ctx, "kv", podPassword, collection.StringIndex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = kvStore.OpenKVTable(ctx, "kv", podPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		client.SetRules(fault.Rule{Ops: fault.Uploads, Kind: fault.Error, Probability: 0.3})
+		acknowledged := make(map[string]string)
+		for i := 0; i < 30; i++ {
+			key, value := fmt.Sprintf("key%d", i), fmt.Sprintf("value%d", i)
+			err = kvStore.KVPut(ctx, "kv", key, []byte(value))
+			if err == nil {
+				acknowledged[key] = value
+			}
+		}
+		if client.Injected() == 0 || len(acknowledged) == 0 {
+			t.Fatalf("expected some of the puts to fail, %d injected, %d acknowledged", client.Injected(), len(acknowledged))
+		}
+
+		// every acknowledged put is readable
+		client.SetRules()
+		for key, want := range acknowledged {
+			_, value, err := kvStore.KVGet(ctx, "kv", key)
+			if err != nil || string(value) != want {
+				t.Fatalf("acknowledged put of %s is lost, got %s: %v", key, value, err)
+			}
+		}
+	})
+}
+
+func assertFileContent(t *testing.T, info *pod.Info, podPassword, path string, content []byte) {
+	t.Helper()
+	reader, _, err := info.GetFile().Download(context.Background(), path, podPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, content) {
+		t.Fatal("file content mismatch")
+	}
+}
@@ -33,6 +33,7 @@ import (
 )
 
 func TestUserStat(t *testing.T) {
+	ctx := context.Background()
 	mockClient := mock.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	tm := taskmanager.New(1, 10, time.Second*15, logger)
@@ -56,7 +57,7 @@ func TestUserStat(t *testing.T) {
 		ens := mock2.NewMockNamespaceManager()
 		// create user
 		userObject := user.NewUsers(mockClient, ens, logger)
-		_, _, _, _, ui, err := userObject.CreateNewUserV2("user1", "password1twelve", "", "", tm)
+		_, _, _, _, ui, err := userObject.CreateNewUserV2(ctx, "user1", "password1twelve", "", "", tm)
 		if err != nil {
 			t.Fatal(err)
 		}
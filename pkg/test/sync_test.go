@@ -32,6 +32,7 @@ import (
 )
 
 func TestSync(t *testing.T) {
+	ctx := context.Background()
 	mockClient := mock.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 	acc := account.New(logger)
@@ -49,18 +50,18 @@ func TestSync(t *testing.T) {
 
 	t.Run("sync-pod", func(t *testing.T) {
 
-		err := pod1.SyncPod(podName1)
+		err := pod1.SyncPod(ctx, podName1)
 		if err == nil {
 			t.Fatal("sync should fail, pod not opened")
 		}
 		// create a pod
 		podPassword, _ := utils.GetRandString(pod.PasswordLength)
-		info, err := pod1.CreatePod(podName1, "", podPassword)
+		info, err := pod1.CreatePod(ctx, podName1, "", podPassword)
 		if err != nil {
 			t.Fatalf("error creating pod %s", podName1)
 		}
 		// make root dir so that other directories can be added
-		err = info.GetDirectory().MkRootDir("pod1", podPassword, info.GetPodAddress(), info.GetFeed())
+		err = info.GetDirectory().MkRootDir(ctx, "pod1", podPassword, info.GetPodAddress(), info.GetFeed())
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -69,7 +70,7 @@ func TestSync(t *testing.T) {
 		addFilesAndDirectories(t, info, pod1, podName1, podPassword)
 
 		// open the pod ths triggers sync too
-		gotInfo, err := pod1.OpenPod(podName1)
+		gotInfo, err := pod1.OpenPod(ctx, podName1)
 		if err != nil {
 			t.Fatal(err)
 		}
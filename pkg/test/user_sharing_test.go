@@ -37,6 +37,7 @@ import (
 )
 
 func TestSharing(t *testing.T) {
+	ctx := context.Background()
 	mockClient := mock.NewMockBeeClient()
 	logger := logging.New(io.Discard, 0)
 
@@ -75,27 +76,27 @@ func TestSharing(t *testing.T) {
 		ens := mock2.NewMockNamespaceManager()
 		// create source user
 		userObject1 := user.NewUsers(mockClient, ens, logger)
-		_, _, _, _, ui0, err := userObject1.CreateNewUserV2("user1", "password1twelve", "", "", tm)
+		_, _, _, _, ui0, err := userObject1.CreateNewUserV2(ctx, "user1", "password1twelve", "", "", tm)
 		if err != nil {
 			t.Fatal(err)
 		}
 		podPassword, _ := utils.GetRandString(pod.PasswordLength)
 		// create source pod
-		info1, err := pod1.CreatePod(podName1, "", podPassword)
+		info1, err := pod1.CreatePod(ctx, podName1, "", podPassword)
 		if err != nil {
 			t.Fatalf("error creating pod %s", podName1)
 		}
 		ui0.AddPodName(podName1, info1)
 
 		// make root dir so that other directories can be added
-		err = info1.GetDirectory().MkRootDir("pod1", podPassword, info1.GetPodAddress(), info1.GetFeed())
+		err = info1.GetDirectory().MkRootDir(ctx, "pod1", podPassword, info1.GetPodAddress(), info1.GetFeed())
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// create dir and file
 		dirObject1 := info1.GetDirectory()
-		err = dirObject1.MkDir("/parentDir1", podPassword)
+		err = dirObject1.MkDir(ctx, "/parentDir1", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -105,34 +106,34 @@ func TestSharing(t *testing.T) {
 			t.Fatal(err)
 		}
 		// share file with another user
-		sharingRefString, err := userObject1.ShareFileWithUser("pod1", podPassword, "/parentDir1/file1", "user2", ui0, pod1, info1.GetPodAddress())
+		sharingRefString, err := userObject1.ShareFileWithUser(ctx, "pod1", podPassword, "/parentDir1/file1", "user2", ui0, pod1, info1.GetPodAddress())
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// create destination user
 		userObject2 := user.NewUsers(mockClient, ens, logger)
-		_, _, _, _, ui, err := userObject2.CreateNewUserV2("user2", "password1twelve", "", "", tm)
+		_, _, _, _, ui, err := userObject2.CreateNewUserV2(ctx, "user2", "password1twelve", "", "", tm)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// create destination pod
 		podPassword, _ = utils.GetRandString(pod.PasswordLength)
-		info2, err := pod2.CreatePod(podName2, "", podPassword)
+		info2, err := pod2.CreatePod(ctx, podName2, "", podPassword)
 		if err != nil {
 			t.Fatalf("error creating pod %s", podName2)
 		}
 
 		// make root dir so that other directories can be added
-		err = info2.GetDirectory().MkRootDir("pod1", podPassword, info2.GetPodAddress(), info2.GetFeed())
+		err = info2.GetDirectory().MkRootDir(ctx, "pod1", podPassword, info2.GetPodAddress(), info2.GetFeed())
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// create dir and file
 		dirObject2 := info2.GetDirectory()
-		err = dirObject2.MkDir("/parentDir2", podPassword)
+		err = dirObject2.MkDir(ctx, "/parentDir2", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -142,7 +143,7 @@ func TestSharing(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		receiveFileInfo, err := userObject2.ReceiveFileInfo(sharingRef)
+		receiveFileInfo, err := userObject2.ReceiveFileInfo(ctx, sharingRef)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -161,18 +162,18 @@ func TestSharing(t *testing.T) {
 			t.Fatalf("invalid block size received")
 		}
 
-		_, err = userObject2.ReceiveFileFromUser("podName2", sharingRef, ui, pod2, "/parentDir2")
+		_, err = userObject2.ReceiveFileFromUser(ctx, "podName2", sharingRef, ui, pod2, "/parentDir2")
 		if !errors.Is(err, pod.ErrPodNotOpened) {
 			t.Fatal("pod does not supposed tp be open")
 		}
 
 		// receive file
-		destinationFilePath, err := userObject2.ReceiveFileFromUser(podName2, sharingRef, ui, pod2, "/parentDir2")
+		destinationFilePath, err := userObject2.ReceiveFileFromUser(ctx, podName2, sharingRef, ui, pod2, "/parentDir2")
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		_, err = userObject2.ReceiveFileFromUser(podName2, sharingRef, ui, pod2, "/parentDir2")
+		_, err = userObject2.ReceiveFileFromUser(ctx, podName2, sharingRef, ui, pod2, "/parentDir2")
 		if !errors.Is(err, file.ErrFileAlreadyPresent) {
 			t.Fatal("pod does not supposed tp be open")
 		}
@@ -181,7 +182,7 @@ func TestSharing(t *testing.T) {
 		if destinationFilePath != "/parentDir2/file1" {
 			t.Fatalf("invalid destination file name")
 		}
-		_, files, err := dirObject2.ListDir("/parentDir2", podPassword)
+		_, files, err := dirObject2.ListDir(ctx, "/parentDir2", podPassword)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -198,7 +199,7 @@ func TestSharing(t *testing.T) {
 			t.Fatalf("pod should be open")
 		}
 		// delete source pod
-		err = pod1.DeleteOwnPod(podName1)
+		err = pod1.DeleteOwnPod(ctx, podName1)
 		if err != nil {
 			t.Fatalf("error deleting pod %s", podName1)
 		}
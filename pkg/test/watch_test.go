@@ -0,0 +1,158 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/fairdatasociety/fairOS-dfs/pkg/blockstore/bee/mock"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/collection"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/dfs"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/dir"
+	mock2 "github.com/fairdatasociety/fairOS-dfs/pkg/ensm/eth/mock"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/user"
+	"github.com/sirupsen/logrus"
+)
+
+func TestWatch(t *testing.T) {
+	ctx := context.Background()
+	mockClient := mock.NewMockBeeClient()
+	logger := logging.New(io.Discard, logrus.ErrorLevel)
+	users := user.NewUsers(mockClient, mock2.NewMockNamespaceManager(), logger)
+	dfsApi := dfs.NewMockDfsAPI(mockClient, users, logger)
+	defer dfsApi.Close()
+
+	_, _, ui, err := dfsApi.LoadLiteUser(randStringRunes(16), randStringRunes(8), "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionId := ui.GetSessionId()
+	podName := randStringRunes(16)
+	_, err = dfsApi.CreatePod(ctx, podName, sessionId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// poll expects the given number of events from the watcher
+	poll := func(t *testing.T, watcher *feed.Watcher, want int) {
+		t.Helper()
+		events, err := watcher.Poll(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(events) != want {
+			t.Fatalf("expected %d events, got %d", want, len(events))
+		}
+	}
+
+	t.Run("dir", func(t *testing.T) {
+		_, err := dfsApi.WatchDir(podName, "/missing", sessionId, 0)
+		if !errors.Is(err, dir.ErrDirectoryNotPresent) {
+			t.Fatalf("expected directory not present, got %v", err)
+		}
+		err = dfsApi.Mkdir(ctx, podName, "/watched", sessionId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		watcher, err := dfsApi.WatchDir(podName, "/watched", sessionId, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		poll(t, watcher, 0)
+
+		err = dfsApi.Mkdir(ctx, podName, "/watched/child", sessionId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		poll(t, watcher, 1)
+		poll(t, watcher, 0)
+
+		// changes elsewhere are not reported
+		err = dfsApi.Mkdir(ctx, podName, "/other", sessionId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		poll(t, watcher, 0)
+	})
+
+	t.Run("kv", func(t *testing.T) {
+		_, err := dfsApi.WatchKVTable(ctx, sessionId, podName, "missing", 0)
+		if !errors.Is(err, collection.ErrKVTableNotPresent) {
+			t.Fatalf("expected table not present, got %v", err)
+		}
+		err = dfsApi.KVCreate(ctx, sessionId, podName, "table", collection.StringIndex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = dfsApi.KVOpen(ctx, sessionId, podName, "table")
+		if err != nil {
+			t.Fatal(err)
+		}
+		watcher, err := dfsApi.WatchKVTable(ctx, sessionId, podName, "table", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		poll(t, watcher, 0)
+
+		// the keys share a prefix, so the later ones only change a lower manifest
+		for i := 0; i < 5; i++ {
+			err = dfsApi.KVPut(ctx, sessionId, podName, "table", fmt.Sprintf("key%d", i), []byte("value"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			events, err := watcher.Poll(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(events) == 0 {
+				t.Fatalf("no event for put %d", i)
+			}
+		}
+		poll(t, watcher, 0)
+	})
+
+	t.Run("doc", func(t *testing.T) {
+		_, err := dfsApi.WatchDocDB(ctx, sessionId, podName, "missing", 0)
+		if !errors.Is(err, collection.ErrDocumentDBNotPresent) {
+			t.Fatalf("expected document db not present, got %v", err)
+		}
+		err = dfsApi.DocCreate(ctx, sessionId, podName, "db", nil, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = dfsApi.DocOpen(ctx, sessionId, podName, "db")
+		if err != nil {
+			t.Fatal(err)
+		}
+		watcher, err := dfsApi.WatchDocDB(ctx, sessionId, podName, "db", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		poll(t, watcher, 0)
+		err = dfsApi.DocPut(ctx, sessionId, podName, "db", []byte(`{"id":"1","name":"first"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		poll(t, watcher, 1)
+	})
+}
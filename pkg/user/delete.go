@@ -16,9 +16,11 @@ limitations under the License.
 
 package user
 
+import "context"
+
 // DeleteUserV2 deletes a user from the Swarm network. Logs him out if he is logged-in and remove from all the
 // data structures.
-func (u *Users) DeleteUserV2(userName, password, sessionId string, ui *Info) error {
+func (u *Users) DeleteUserV2(ctx context.Context, userName, password, sessionId string, ui *Info) error {
 	// check if session id and user address present in map
 	if !u.IsUserLoggedIn(sessionId) {
 		return ErrUserNotLoggedIn
@@ -33,7 +35,7 @@ func (u *Users) DeleteUserV2(userName, password, sessionId string, ui *Info) err
 	userInfo := u.getUserFromMap(sessionId)
 	acc := userInfo.account
 
-	err := u.deletePortableAccount(acc.GetUserAccountInfo().GetAddress(), userName, password, ui.GetFeed())
+	err := u.deletePortableAccount(ctx, acc.GetUserAccountInfo().GetAddress(), userName, password, ui.GetFeed())
 	if err != nil { // skipcq: TCV-001
 		return err
 	}
@@ -17,6 +17,7 @@ limitations under the License.
 package user
 
 import (
+	"context"
 	"sync"
 
 	"github.com/fairdatasociety/fairOS-dfs/pkg/taskmanager"
@@ -34,7 +35,7 @@ import (
 
 // LoginUserV2 checks if the user is present and logs in the user. It also creates the required information
 // to execute user function and stores it in memory.
-func (u *Users) LoginUserV2(userName, passPhrase string, client blockstore.Client, tm taskmanager.TaskManagerGO, sessionId string) (*Info, string, string, error) {
+func (u *Users) LoginUserV2(ctx context.Context, userName, passPhrase string, client blockstore.Client, tm taskmanager.TaskManagerGO, sessionId string) (*Info, string, string, error) {
 	// check if username is available (user created)
 	if !u.IsUsernameAvailableV2(userName) {
 		return nil, "", "", ErrUserNameNotFound
@@ -50,7 +51,7 @@ func (u *Users) LoginUserV2(userName, passPhrase string, client blockstore.Clien
 	accountInfo := acc.GetUserAccountInfo()
 	// load encrypted private key
 	fd := feed.New(accountInfo, client, u.logger)
-	key, err := u.downloadPortableAccount(utils.Address(address), userName, passPhrase, fd)
+	key, err := u.downloadPortableAccount(ctx, utils.Address(address), userName, passPhrase, fd)
 	if err != nil {
 		u.logger.Errorf(err.Error())
 		return nil, "", "", ErrInvalidPassword
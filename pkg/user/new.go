@@ -17,6 +17,7 @@ limitations under the License.
 package user
 
 import (
+	"context"
 	"regexp"
 	"sync"
 
@@ -41,7 +42,7 @@ const (
 
 // CreateNewUserV2 creates a new user with the given username and password. if a mnemonic is passed
 // then it is used instead of creating a new one.
-func (u *Users) CreateNewUserV2(userName, passPhrase, mnemonic, sessionId string, tm taskmanager.TaskManagerGO) (string, string, string, string, *Info, error) {
+func (u *Users) CreateNewUserV2(ctx context.Context, userName, passPhrase, mnemonic, sessionId string, tm taskmanager.TaskManagerGO) (string, string, string, string, *Info, error) {
 	if userName == "" {
 		return "", "", "", "", nil, ErrBlankUsername
 	}
@@ -96,7 +97,7 @@ func (u *Users) CreateNewUserV2(userName, passPhrase, mnemonic, sessionId string
 	if err != nil { // skipcq: TCV-001
 		return "", "", "", "", nil, err
 	}
-	if err := u.uploadPortableAccount(accountInfo, userName, passPhrase, key, fd); err != nil { // skipcq: TCV-001
+	if err := u.uploadPortableAccount(ctx, accountInfo, userName, passPhrase, key, fd); err != nil { // skipcq: TCV-001
 		return "", "", "", "", nil, err
 	}
 	// Instantiate pod, dir & file objects
@@ -1,6 +1,7 @@
 package user
 
 import (
+	"context"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/account"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/feed"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
@@ -11,25 +12,25 @@ const (
 	AuthVersion = "FDP-login-v1.0"
 )
 
-func (*Users) uploadPortableAccount(accountInfo *account.Info, username, password string, data []byte, fd *feed.API) error {
+func (*Users) uploadPortableAccount(ctx context.Context, accountInfo *account.Info, username, password string, data []byte, fd *feed.API) error {
 	topic := utils.HashString(AuthVersion + username + password)
-	_, err := fd.CreateFeedFromTopic(topic, accountInfo.GetAddress(), data)
+	_, err := fd.CreateFeedFromTopic(ctx, topic, accountInfo.GetAddress(), data)
 	if err != nil { // skipcq: TCV-001
 		return err
 	}
 	return nil
 }
 
-func (*Users) downloadPortableAccount(address utils.Address, username, password string, fd *feed.API) ([]byte, error) {
+func (*Users) downloadPortableAccount(ctx context.Context, address utils.Address, username, password string, fd *feed.API) ([]byte, error) {
 	topic := utils.HashString(AuthVersion + username + password)
-	_, data, err := fd.GetFeedDataFromTopic(topic, address)
+	_, data, err := fd.GetFeedDataFromTopic(ctx, topic, address)
 	if err != nil {
 		return nil, err
 	}
 	return data, nil
 }
 
-func (*Users) deletePortableAccount(address utils.Address, username, password string, fd *feed.API) error {
+func (*Users) deletePortableAccount(ctx context.Context, address utils.Address, username, password string, fd *feed.API) error {
 	topic := utils.HashString(AuthVersion + username + password)
-	return fd.DeleteFeedFromTopic(topic, address)
+	return fd.DeleteFeedFromTopic(ctx, topic, address)
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package user
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"strconv"
@@ -67,9 +68,9 @@ type ReceiveFileInfo struct {
 }
 
 // ShareFileWithUser exports a file to another user by creating and uploading a new encrypted sharing file entry.
-func (u *Users) ShareFileWithUser(podName, podPassword, podFileWithPath, destinationRef string, userInfo *Info, pod *pod.Pod, userAddress utils.Address) (string, error) {
+func (u *Users) ShareFileWithUser(ctx context.Context, podName, podPassword, podFileWithPath, destinationRef string, userInfo *Info, pod *pod.Pod, userAddress utils.Address) (string, error) {
 	totalFilePath := utils.CombinePathAndFile(podFileWithPath, "")
-	meta, err := userInfo.file.GetMetaFromFileName(totalFilePath, podPassword, userAddress)
+	meta, err := userInfo.file.GetMetaFromFileName(ctx, totalFilePath, podPassword, userAddress)
 	if err != nil { // skipcq: TCV-001
 		return "", err
 	}
@@ -96,7 +97,7 @@ func (u *Users) ShareFileWithUser(podName, podPassword, podFileWithPath, destina
 	}
 
 	// upload the encrypted data and get the reference
-	ref, err := u.client.UploadBlob(encryptedData, 0, true, true)
+	ref, err := u.client.UploadBlob(ctx, encryptedData, 0, true, true)
 	if err != nil { // skipcq: TCV-001
 		return "", err
 	}
@@ -107,12 +108,12 @@ func (u *Users) ShareFileWithUser(podName, podPassword, podFileWithPath, destina
 }
 
 // ReceiveFileFromUser imports an exported file in to the current user and pod by reading the sharing file entry.
-func (u *Users) ReceiveFileFromUser(podName string, sharingRef utils.SharingReference, userInfo *Info, pd *pod.Pod, podDir string) (string, error) {
+func (u *Users) ReceiveFileFromUser(ctx context.Context, podName string, sharingRef utils.SharingReference, userInfo *Info, pd *pod.Pod, podDir string) (string, error) {
 	metaRef := sharingRef.GetRef()
 	unixTime := sharingRef.GetNonce()
 
 	// get the encrypted meta
-	encryptedData, respCode, err := u.client.DownloadBlob(metaRef)
+	encryptedData, respCode, err := u.client.DownloadBlob(ctx, metaRef)
 	if err != nil || respCode != http.StatusOK {
 		return "", err
 	} // skipcq: TCV-001
@@ -153,25 +154,30 @@ func (u *Users) ReceiveFileFromUser(podName string, sharingRef utils.SharingRefe
 	// Add to file path map
 	now := time.Now().Unix()
 	newMeta := f.MetaData{
-		Version:          sharingEntry.Meta.Version,
-		Path:             podDir,
-		Name:             fileNameToAdd,
-		Size:             sharingEntry.Meta.Size,
-		BlockSize:        sharingEntry.Meta.BlockSize,
-		ContentType:      sharingEntry.Meta.ContentType,
-		Compression:      sharingEntry.Meta.Compression,
-		CreationTime:     now,
-		AccessTime:       now,
-		ModificationTime: now,
-		InodeAddress:     sharingEntry.Meta.InodeAddress,
+		Version:           sharingEntry.Meta.Version,
+		Path:              podDir,
+		Name:              fileNameToAdd,
+		Size:              sharingEntry.Meta.Size,
+		BlockSize:         sharingEntry.Meta.BlockSize,
+		ContentType:       sharingEntry.Meta.ContentType,
+		Compression:       sharingEntry.Meta.Compression,
+		CreationTime:      now,
+		AccessTime:        now,
+		ModificationTime:  now,
+		InodeAddress:      sharingEntry.Meta.InodeAddress,
+		BlockTreeChecksum: sharingEntry.Meta.BlockTreeChecksum,
+		Checksum:          sharingEntry.Meta.Checksum,
+		Xattrs:            sharingEntry.Meta.Xattrs,
+		// the blocks stay the ones of the sender
+		SharedBlocks: true,
 	}
 
 	file.AddToFileMap(totalPath, &newMeta)
-	err = file.PutMetaForFile(&newMeta, podInfo.GetPodPassword())
+	err = file.PutMetaForFile(ctx, &newMeta, podInfo.GetPodPassword())
 	if err != nil { // skipcq: TCV-001
 		return "", err
 	}
-	err = dir.AddEntryToDir(podDir, podInfo.GetPodPassword(), fileNameToAdd, true)
+	err = dir.AddEntryToDir(ctx, podDir, podInfo.GetPodPassword(), fileNameToAdd, true)
 	if err != nil { // skipcq: TCV-001
 		return "", err
 	}
@@ -199,12 +205,12 @@ func decryptData(data []byte, now int64) ([]byte, error) {
 
 // ReceiveFileInfo displays the information of the exported file. This is used to decide whether
 // to import the file or not.
-func (u *Users) ReceiveFileInfo(sharingRef utils.SharingReference) (*ReceiveFileInfo, error) {
+func (u *Users) ReceiveFileInfo(ctx context.Context, sharingRef utils.SharingReference) (*ReceiveFileInfo, error) {
 	metaRef := sharingRef.GetRef()
 	unixTime := sharingRef.GetNonce()
 
 	// get the encrypted meta
-	encryptedData, respCode, err := u.client.DownloadBlob(metaRef)
+	encryptedData, respCode, err := u.client.DownloadBlob(ctx, metaRef)
 	if err != nil || respCode != http.StatusOK { // skipcq: TCV-001
 		return nil, err
 	}
@@ -221,7 +227,7 @@ func (u *Users) ReceiveFileInfo(sharingRef utils.SharingReference) (*ReceiveFile
 	if err != nil { // skipcq: TCV-001
 		return nil, err
 	}
-	fileInodeBytes, respCode, err := u.client.DownloadBlob(sharingEntry.Meta.InodeAddress)
+	fileInodeBytes, respCode, err := u.client.DownloadBlob(ctx, sharingEntry.Meta.InodeAddress)
 	if err != nil || respCode != http.StatusOK { // skipcq: TCV-001
 		return nil, err
 	}
@@ -0,0 +1,99 @@
+/*
+Copyright © 2020 FairOS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+const (
+	// MaxXattrNameLength is the longest name an extended attribute can have
+	MaxXattrNameLength = 255
+	// MaxXattrsLength is the longest the json of the extended attributes of a file or a
+	// directory can be, so that its metadata still fits in a chunk
+	MaxXattrsLength = 2048
+)
+
+var (
+	//ErrInvalidXattrName
+	ErrInvalidXattrName = fmt.Errorf("invalid extended attribute name, should be valid utf-8 of 1 to %d bytes", MaxXattrNameLength)
+	//ErrXattrsTooLarge
+	ErrXattrsTooLarge = fmt.Errorf("extended attributes cannot take more than %d bytes", MaxXattrsLength)
+	//ErrXattrNotFound
+	ErrXattrNotFound = errors.New("extended attribute not found")
+)
+
+// CheckXattrs returns an error if a name of the extended attributes is invalid or if
+// they take too much space
+func CheckXattrs(xattrs map[string]string) error {
+	for name := range xattrs {
+		if name == "" || len(name) > MaxXattrNameLength || !utf8.ValidString(name) {
+			return ErrInvalidXattrName
+		}
+	}
+	data, err := json.Marshal(xattrs)
+	if err != nil { // skipcq: TCV-001
+		return err
+	}
+	if len(data) > MaxXattrsLength {
+		return ErrXattrsTooLarge
+	}
+	return nil
+}
+
+// CopyXattrs returns a copy of the extended attributes, nil if there are none
+func CopyXattrs(xattrs map[string]string) map[string]string {
+	if len(xattrs) == 0 {
+		return nil
+	}
+	c := make(map[string]string, len(xattrs))
+	for name, value := range xattrs {
+		c[name] = value
+	}
+	return c
+}
+
+// SetXattr returns a copy of the extended attributes with an attribute set. The
+// attributes that are shared with a cached metadata are not changed.
+func SetXattr(xattrs map[string]string, name, value string) (map[string]string, error) {
+	c := CopyXattrs(xattrs)
+	if c == nil {
+		c = map[string]string{}
+	}
+	c[name] = value
+	err := CheckXattrs(c)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// RemoveXattr returns a copy of the extended attributes without an attribute
+func RemoveXattr(xattrs map[string]string, name string) (map[string]string, error) {
+	if _, ok := xattrs[name]; !ok {
+		return nil, ErrXattrNotFound
+	}
+	c := CopyXattrs(xattrs)
+	delete(c, name)
+	if len(c) == 0 {
+		return nil, nil
+	}
+	return c, nil
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/fairdatasociety/fairOS-dfs/pkg/collection"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/contracts"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/dfs"
+	"github.com/fairdatasociety/fairOS-dfs/pkg/file"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/logging"
 	"github.com/fairdatasociety/fairOS-dfs/pkg/utils"
 	"github.com/sirupsen/logrus"
@@ -160,7 +161,7 @@ func login(_ js.Value, funcArgs []js.Value) interface{} {
 		password := funcArgs[1].String()
 
 		go func() {
-			ui, _, _, err := api.LoginUserV2(username, password, "")
+			ui, _, _, err := api.LoginUserV2(ctx, username, password, "")
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("Failed to create user : %s", err.Error()))
 				return
@@ -268,7 +269,7 @@ func userDelete(_ js.Value, funcArgs []js.Value) interface{} {
 		password := funcArgs[1].String()
 
 		go func() {
-			err := api.DeleteUserV2(password, sessionId)
+			err := api.DeleteUserV2(ctx, password, sessionId)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("userDelete failed : %s", err.Error()))
 				return
@@ -322,7 +323,7 @@ func podNew(_ js.Value, funcArgs []js.Value) interface{} {
 		podName := funcArgs[1].String()
 
 		go func() {
-			_, err := api.CreatePod(podName, sessionId)
+			_, err := api.CreatePod(ctx, podName, sessionId)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("podNew failed : %s", err.Error()))
 				return
@@ -349,7 +350,7 @@ func podOpen(_ js.Value, funcArgs []js.Value) interface{} {
 		podName := funcArgs[1].String()
 
 		go func() {
-			_, err := api.OpenPod(podName, sessionId)
+			_, err := api.OpenPod(ctx, podName, sessionId)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("podOpen failed : %s", err.Error()))
 				return
@@ -376,7 +377,7 @@ func podClose(_ js.Value, funcArgs []js.Value) interface{} {
 		podName := funcArgs[1].String()
 
 		go func() {
-			err := api.ClosePod(podName, sessionId)
+			err := api.ClosePod(ctx, podName, sessionId)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("podClose failed : %s", err.Error()))
 				return
@@ -403,7 +404,7 @@ func podSync(_ js.Value, funcArgs []js.Value) interface{} {
 		podName := funcArgs[1].String()
 
 		go func() {
-			err := api.SyncPod(podName, sessionId)
+			err := api.SyncPod(ctx, podName, sessionId)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("podSync failed : %s", err.Error()))
 				return
@@ -430,7 +431,7 @@ func podDelete(_ js.Value, funcArgs []js.Value) interface{} {
 		podName := funcArgs[1].String()
 
 		go func() {
-			err := api.DeletePod(podName, sessionId)
+			err := api.DeletePod(ctx, podName, sessionId)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("podDelete failed : %s", err.Error()))
 				return
@@ -456,7 +457,7 @@ func podList(_ js.Value, funcArgs []js.Value) interface{} {
 		sessionId := funcArgs[0].String()
 
 		go func() {
-			ownPods, sharedPods, err := api.ListPods(sessionId)
+			ownPods, sharedPods, err := api.ListPods(ctx, sessionId)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("podList failed : %s", err.Error()))
 				return
@@ -516,7 +517,7 @@ func podShare(_ js.Value, funcArgs []js.Value) interface{} {
 		shareAs := funcArgs[2].String()
 
 		go func() {
-			reference, err := api.PodShare(podName, shareAs, sessionId)
+			reference, err := api.PodShare(ctx, podName, shareAs, sessionId)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("podShare failed : %s", err.Error()))
 				return
@@ -552,7 +553,7 @@ func podReceive(_ js.Value, funcArgs []js.Value) interface{} {
 				reject.Invoke(fmt.Sprintf("podReceive failed : %s", err.Error()))
 				return
 			}
-			pi, err := api.PodReceive(sessionId, newPodName, ref)
+			pi, err := api.PodReceive(ctx, sessionId, newPodName, ref)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("podReceive failed : %s", err.Error()))
 				return
@@ -584,7 +585,7 @@ func podReceiveInfo(_ js.Value, funcArgs []js.Value) interface{} {
 				reject.Invoke(fmt.Sprintf("podReceiveInfo failed : %s", err.Error()))
 				return
 			}
-			shareInfo, err := api.PodReceiveInfo(sessionId, ref)
+			shareInfo, err := api.PodReceiveInfo(ctx, sessionId, ref)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("podReceiveInfo failed : %s", err.Error()))
 				return
@@ -613,7 +614,7 @@ func dirPresent(_ js.Value, funcArgs []js.Value) interface{} {
 		dirPath := funcArgs[2].String()
 
 		go func() {
-			present, err := api.IsDirPresent(podName, dirPath, sessionId)
+			present, err := api.IsDirPresent(ctx, podName, dirPath, sessionId)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("dirPresent failed : %s", err.Error()))
 				return
@@ -644,7 +645,7 @@ func dirMake(_ js.Value, funcArgs []js.Value) interface{} {
 		dirPath := funcArgs[2].String()
 
 		go func() {
-			err := api.Mkdir(podName, dirPath, sessionId)
+			err := api.Mkdir(ctx, podName, dirPath, sessionId)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("dirMake failed : %s", err.Error()))
 				return
@@ -672,7 +673,7 @@ func dirRemove(_ js.Value, funcArgs []js.Value) interface{} {
 		dirPath := funcArgs[2].String()
 
 		go func() {
-			err := api.RmDir(podName, dirPath, sessionId)
+			err := api.RmDir(ctx, podName, dirPath, sessionId)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("dirRemove failed : %s", err.Error()))
 				return
@@ -700,7 +701,7 @@ func dirList(_ js.Value, funcArgs []js.Value) interface{} {
 		dirPath := funcArgs[2].String()
 
 		go func() {
-			dirs, files, err := api.ListDir(podName, dirPath, sessionId)
+			dirs, files, err := api.ListDir(ctx, podName, dirPath, sessionId)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("dirList failed : %s", err.Error()))
 				return
@@ -732,7 +733,7 @@ func dirStat(_ js.Value, funcArgs []js.Value) interface{} {
 		dirPath := funcArgs[2].String()
 
 		go func() {
-			stat, err := api.DirectoryStat(podName, dirPath, sessionId)
+			stat, err := api.DirectoryStat(ctx, podName, dirPath, sessionId)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("dirStat failed : %s", err.Error()))
 				return
@@ -760,7 +761,7 @@ func fileDownload(_ js.Value, funcArgs []js.Value) interface{} {
 		filePath := funcArgs[2].String()
 
 		go func() {
-			r, _, err := api.DownloadFile(podName, filePath, sessionId)
+			r, _, err := api.DownloadFile(ctx, podName, filePath, sessionId)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("fileDownload failed : %s", err.Error()))
 				return
@@ -799,11 +800,9 @@ func fileUpload(_ js.Value, funcArgs []js.Value) interface{} {
 		size := funcArgs[5].Int()
 		blockSize := funcArgs[6].String()
 		compression := funcArgs[7].String()
-		if compression != "" {
-			if compression != "snappy" && compression != "gzip" {
-				reject.Invoke("invalid compression value")
-				return nil
-			}
+		if file.CheckCompression(compression) != nil {
+			reject.Invoke("invalid compression value")
+			return nil
 		}
 		bs, err := humanize.ParseBytes(blockSize)
 		if err != nil {
@@ -816,7 +815,7 @@ func fileUpload(_ js.Value, funcArgs []js.Value) interface{} {
 			js.CopyBytesToGo(inBuf, array)
 			reader := bytes.NewReader(inBuf)
 
-			err := api.UploadFile(podName, fileName, sessionId, int64(size), reader, dirPath, compression, uint32(bs), true)
+			err := api.UploadFile(ctx, podName, fileName, sessionId, int64(size), reader, dirPath, compression, uint32(bs), true)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("fileUpload failed : %s", err.Error()))
 				return
@@ -844,7 +843,7 @@ func fileShare(_ js.Value, funcArgs []js.Value) interface{} {
 		destinationUser := funcArgs[3].String()
 
 		go func() {
-			ref, err := api.ShareFile(podName, dirPath, destinationUser, sessionId)
+			ref, err := api.ShareFile(ctx, podName, dirPath, destinationUser, sessionId)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("fileShare failed : %s", err.Error()))
 				return
@@ -881,7 +880,7 @@ func fileReceive(_ js.Value, funcArgs []js.Value) interface{} {
 				reject.Invoke(fmt.Sprintf("fileReceive failed : %s", err.Error()))
 				return
 			}
-			filePath, err := api.ReceiveFile(podName, sessionId, ref, directory)
+			filePath, err := api.ReceiveFile(ctx, podName, sessionId, ref, directory)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("fileReceive failed : %s", err.Error()))
 				return
@@ -916,7 +915,7 @@ func fileReceiveInfo(_ js.Value, funcArgs []js.Value) interface{} {
 				reject.Invoke(fmt.Sprintf("fileReceiveInfo failed : %s", err.Error()))
 				return
 			}
-			receiveInfo, err := api.ReceiveInfo(sessionId, ref)
+			receiveInfo, err := api.ReceiveInfo(ctx, sessionId, ref)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("fileReceiveInfo failed : %s", err.Error()))
 				return
@@ -945,7 +944,7 @@ func fileDelete(_ js.Value, funcArgs []js.Value) interface{} {
 		filePath := funcArgs[2].String()
 
 		go func() {
-			err := api.DeleteFile(podName, filePath, sessionId)
+			err := api.DeleteFile(ctx, podName, filePath, sessionId)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("fileDelete failed : %s", err.Error()))
 				return
@@ -973,7 +972,7 @@ func fileStat(_ js.Value, funcArgs []js.Value) interface{} {
 		filePath := funcArgs[2].String()
 
 		go func() {
-			stat, err := api.FileStat(podName, filePath, sessionId)
+			stat, err := api.FileStat(ctx, podName, filePath, sessionId)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("fileStat failed : %s", err.Error()))
 				return
@@ -1018,7 +1017,7 @@ func kvNewStore(_ js.Value, funcArgs []js.Value) interface{} {
 		}
 
 		go func() {
-			err := api.KVCreate(sessionId, podName, tableName, indexType)
+			err := api.KVCreate(ctx, sessionId, podName, tableName, indexType)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("kvNewStore failed : %s", err.Error()))
 				return
@@ -1045,7 +1044,7 @@ func kvList(_ js.Value, funcArgs []js.Value) interface{} {
 		podName := funcArgs[1].String()
 
 		go func() {
-			collections, err := api.KVList(sessionId, podName)
+			collections, err := api.KVList(ctx, sessionId, podName)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("kvList failed : %s", err.Error()))
 				return
@@ -1074,7 +1073,7 @@ func kvOpen(_ js.Value, funcArgs []js.Value) interface{} {
 		tableName := funcArgs[2].String()
 
 		go func() {
-			err := api.KVOpen(sessionId, podName, tableName)
+			err := api.KVOpen(ctx, sessionId, podName, tableName)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("kvOpen failed : %s", err.Error()))
 				return
@@ -1102,7 +1101,7 @@ func kvDelete(_ js.Value, funcArgs []js.Value) interface{} {
 		tableName := funcArgs[2].String()
 
 		go func() {
-			err := api.KVDelete(sessionId, podName, tableName)
+			err := api.KVDelete(ctx, sessionId, podName, tableName)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("kvDelete failed : %s", err.Error()))
 				return
@@ -1130,7 +1129,7 @@ func kvCount(_ js.Value, funcArgs []js.Value) interface{} {
 		tableName := funcArgs[2].String()
 
 		go func() {
-			count, err := api.KVCount(sessionId, podName, tableName)
+			count, err := api.KVCount(ctx, sessionId, podName, tableName)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("kvCount failed : %s", err.Error()))
 				return
@@ -1161,7 +1160,7 @@ func kvEntryPut(_ js.Value, funcArgs []js.Value) interface{} {
 		value := funcArgs[4].String()
 
 		go func() {
-			err := api.KVPut(sessionId, podName, tableName, key, []byte(value))
+			err := api.KVPut(ctx, sessionId, podName, tableName, key, []byte(value))
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("kvEntryPut failed : %s", err.Error()))
 				return
@@ -1195,7 +1194,7 @@ func kvEntryGet(_ js.Value, funcArgs []js.Value) interface{} {
 		key := funcArgs[3].String()
 
 		go func() {
-			columns, data, err := api.KVGet(sessionId, podName, tableName, key)
+			columns, data, err := api.KVGet(ctx, sessionId, podName, tableName, key)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("kvEntryGet failed : %s", err.Error()))
 				return
@@ -1232,7 +1231,7 @@ func kvEntryDelete(_ js.Value, funcArgs []js.Value) interface{} {
 		key := funcArgs[3].String()
 
 		go func() {
-			_, err := api.KVDel(sessionId, podName, tableName, key)
+			_, err := api.KVDel(ctx, sessionId, podName, tableName, key)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("kvEntryDelete failed : %s", err.Error()))
 				return
@@ -1292,7 +1291,7 @@ func kvLoadCSV(_ js.Value, funcArgs []js.Value) interface{} {
 						return
 					}
 
-					err = batch.Put(collection.CSVHeaderKey, []byte(record), false, memory)
+					err = batch.Put(ctx, collection.CSVHeaderKey, []byte(record), false, memory)
 					if err != nil {
 						failureCount++
 						readHeader = true
@@ -1304,14 +1303,14 @@ func kvLoadCSV(_ js.Value, funcArgs []js.Value) interface{} {
 				}
 
 				key := strings.Split(record, ",")[0]
-				err = batch.Put(key, []byte(record), false, memory)
+				err = batch.Put(ctx, key, []byte(record), false, memory)
 				if err != nil {
 					failureCount++
 					continue
 				}
 				successCount++
 			}
-			_, err := batch.Write("")
+			_, err := batch.Write(ctx, "")
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("kv loadcsv: %s", err.Error()))
 				return
@@ -1344,7 +1343,7 @@ func kvSeek(_ js.Value, funcArgs []js.Value) interface{} {
 		}
 
 		go func() {
-			_, err := api.KVSeek(sessionId, podName, tableName, start, end, int64(limit))
+			_, err := api.KVSeek(ctx, sessionId, podName, tableName, start, end, int64(limit))
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("kvSeek failed : %s", err.Error()))
 				return
@@ -1372,7 +1371,7 @@ func kvSeekNext(_ js.Value, funcArgs []js.Value) interface{} {
 		tableName := funcArgs[2].String()
 
 		go func() {
-			columns, key, data, err := api.KVGetNext(sessionId, podName, tableName)
+			columns, key, data, err := api.KVGetNext(ctx, sessionId, podName, tableName)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("kvSeekNext failed : %s", err.Error()))
 				return
@@ -1435,7 +1434,7 @@ func docNewStore(_ js.Value, funcArgs []js.Value) interface{} {
 		}
 
 		go func() {
-			err := api.DocCreate(sessionId, podName, tableName, indexes, mutable)
+			err := api.DocCreate(ctx, sessionId, podName, tableName, indexes, mutable)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("docNewStore failed : %s", err.Error()))
 				return
@@ -1462,7 +1461,7 @@ func docList(_ js.Value, funcArgs []js.Value) interface{} {
 		podName := funcArgs[1].String()
 
 		go func() {
-			collections, err := api.DocList(sessionId, podName)
+			collections, err := api.DocList(ctx, sessionId, podName)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("docList failed : %s", err.Error()))
 				return
@@ -1491,7 +1490,7 @@ func docOpen(_ js.Value, funcArgs []js.Value) interface{} {
 		tableName := funcArgs[2].String()
 
 		go func() {
-			err := api.DocOpen(sessionId, podName, tableName)
+			err := api.DocOpen(ctx, sessionId, podName, tableName)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("docOpen failed : %s", err.Error()))
 				return
@@ -1520,7 +1519,7 @@ func docCount(_ js.Value, funcArgs []js.Value) interface{} {
 		expr := funcArgs[3].String()
 
 		go func() {
-			count, err := api.DocCount(sessionId, podName, tableName, expr)
+			count, err := api.DocCount(ctx, sessionId, podName, tableName, expr)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("docCount failed : %s", err.Error()))
 				return
@@ -1549,7 +1548,7 @@ func docDelete(_ js.Value, funcArgs []js.Value) interface{} {
 		tableName := funcArgs[2].String()
 
 		go func() {
-			err := api.DocDelete(sessionId, podName, tableName)
+			err := api.DocDelete(ctx, sessionId, podName, tableName)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("docDelete failed : %s", err.Error()))
 				return
@@ -1579,7 +1578,7 @@ func docFind(_ js.Value, funcArgs []js.Value) interface{} {
 		limit := funcArgs[4].Int()
 
 		go func() {
-			count, err := api.DocFind(sessionId, podName, tableName, expr, limit)
+			count, err := api.DocFind(ctx, sessionId, podName, tableName, expr, limit)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("docCount failed : %s", err.Error()))
 				return
@@ -1609,7 +1608,7 @@ func docEntryPut(_ js.Value, funcArgs []js.Value) interface{} {
 		value := funcArgs[3].String()
 
 		go func() {
-			err := api.DocPut(sessionId, podName, tableName, []byte(value))
+			err := api.DocPut(ctx, sessionId, podName, tableName, []byte(value))
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("docEntryPut failed : %s", err.Error()))
 				return
@@ -1642,7 +1641,7 @@ func docEntryGet(_ js.Value, funcArgs []js.Value) interface{} {
 		id := funcArgs[3].String()
 
 		go func() {
-			data, err := api.DocGet(sessionId, podName, tableName, id)
+			data, err := api.DocGet(ctx, sessionId, podName, tableName, id)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("docEntryGet failed : %s", err.Error()))
 				return
@@ -1675,7 +1674,7 @@ func docEntryDelete(_ js.Value, funcArgs []js.Value) interface{} {
 		id := funcArgs[3].String()
 
 		go func() {
-			err := api.DocDel(sessionId, podName, tableName, id)
+			err := api.DocDel(ctx, sessionId, podName, tableName, id)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("docEntryDelete failed : %s", err.Error()))
 				return
@@ -1711,7 +1710,7 @@ func docLoadJson(_ js.Value, funcArgs []js.Value) interface{} {
 			rowCount := 0
 			successCount := 0
 			failureCount := 0
-			docBatch, err := api.DocBatch(sessionId, podName, tableName)
+			docBatch, err := api.DocBatch(ctx, sessionId, podName, tableName)
 			for {
 				// read one row from csv (assuming
 				record, err := reader.ReadString('\n')
@@ -1727,14 +1726,14 @@ func docLoadJson(_ js.Value, funcArgs []js.Value) interface{} {
 				record = strings.TrimSuffix(record, "\n")
 				record = strings.TrimSuffix(record, "\r")
 
-				err = api.DocBatchPut(sessionId, podName, []byte(record), docBatch)
+				err = api.DocBatchPut(ctx, sessionId, podName, []byte(record), docBatch)
 				if err != nil {
 					failureCount++
 					continue
 				}
 				successCount++
 			}
-			err = api.DocBatchWrite(sessionId, podName, docBatch)
+			err = api.DocBatchWrite(ctx, sessionId, podName, docBatch)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("doc loadjson: %s", err.Error()))
 				return
@@ -1762,7 +1761,7 @@ func docIndexJson(_ js.Value, funcArgs []js.Value) interface{} {
 		filePath := funcArgs[3].String()
 
 		go func() {
-			err := api.DocIndexJson(sessionId, podName, tableName, filePath)
+			err := api.DocIndexJson(ctx, sessionId, podName, tableName, filePath)
 			if err != nil {
 				reject.Invoke(fmt.Sprintf("docIndexJson failed : %s", err.Error()))
 				return